@@ -0,0 +1,1294 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
+	"html"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dtbead/wc-maps-archive/internal/bagit"
+	"github.com/dtbead/wc-maps-archive/internal/download/ytdlp"
+	"github.com/dtbead/wc-maps-archive/internal/entities"
+	"github.com/dtbead/wc-maps-archive/internal/fingerprint/ffmpeg"
+	"github.com/dtbead/wc-maps-archive/internal/probe/ffprobe"
+	"github.com/dtbead/wc-maps-archive/internal/server"
+	"github.com/dtbead/wc-maps-archive/internal/service"
+	"github.com/dtbead/wc-maps-archive/internal/storage/postgres"
+	"github.com/dtbead/wc-maps-archive/internal/storage/postgres/backup"
+	"github.com/dtbead/wc-maps-archive/internal/tree"
+)
+
+func runDownload(ctx context.Context, a app, args []string) error {
+	fs := flag.NewFlagSet("download", flag.ExitOnError)
+	ffprobePath := fs.String("ffprobe", "", "path to the ffprobe binary, looked up in PATH if empty")
+	fs.Parse(args)
+
+	if fs.NArg() != 1 {
+		return errors.New("expected a single youtube url")
+	}
+
+	return a.service.DownloadYoutube(ctx, fs.Arg(0), ytdlp.NewYtdlp(nil), ffprobe.NewFfprobe(*ffprobePath))
+}
+
+func runImport(ctx context.Context, a app, args []string) error {
+	fs := flag.NewFlagSet("import", flag.ExitOnError)
+	project := fs.String("project", "", "uuid of an existing project to assign every imported file to")
+	matchYoutube := fs.Bool("match-youtube", false, "link files named like yt-dlp's \"title [id].ext\" to already archived youtube videos")
+	ffprobePath := fs.String("ffprobe", "", "path to the ffprobe binary, looked up in PATH if empty")
+	fs.Parse(args)
+
+	if fs.NArg() != 1 {
+		return errors.New("expected a single directory to import")
+	}
+
+	opts := service.ImportOptions{Project: entities.ProjectUUID(*project)}
+	if *matchYoutube {
+		opts.ParseYoutubeID = ytdlp.ParseFilenameID
+	}
+
+	results, err := a.service.ImportDirectory(ctx, fs.Arg(0), ffprobe.NewFfprobe(*ffprobePath), opts)
+
+	var failed int
+	for _, r := range results {
+		switch {
+		case r.Err != nil:
+			failed++
+			fmt.Printf("failed   %s: %v\n", r.Path, r.Err)
+		case r.YoutubeID != "":
+			fmt.Printf("imported %s as file %d, linked to youtube %s\n", r.Path, r.FileID, r.YoutubeID)
+		default:
+			fmt.Printf("imported %s as file %d\n", r.Path, r.FileID)
+		}
+	}
+	fmt.Printf("%d imported, %d failed\n", len(results)-failed, failed)
+
+	return err
+}
+
+func runProbe(ctx context.Context, a app, args []string) error {
+	fs := flag.NewFlagSet("probe", flag.ExitOnError)
+	backfill := fs.Bool("backfill", false, "probe every file that hasn't been probed yet")
+	ffprobePath := fs.String("ffprobe", "", "path to the ffprobe binary, looked up in PATH if empty")
+	fs.Parse(args)
+
+	prober := ffprobe.NewFfprobe(*ffprobePath)
+
+	var results []service.ProbeResult
+	var err error
+	switch {
+	case *backfill && fs.NArg() == 0:
+		results, err = a.service.BackfillProbes(ctx, prober)
+	case !*backfill && fs.NArg() > 0:
+		for _, arg := range fs.Args() {
+			file_id, err := parseFileID(arg)
+			if err != nil {
+				return err
+			}
+
+			mismatches, err := a.service.ProbeService.ProbeFile(ctx, file_id, prober, nil)
+			results = append(results, service.ProbeResult{FileID: file_id, Mismatches: mismatches, Err: err})
+		}
+	default:
+		return errors.New("expected either -backfill or one or more file ids")
+	}
+
+	var failed, mismatched int
+	for _, r := range results {
+		switch {
+		case r.Err != nil:
+			failed++
+			fmt.Printf("failed   file %d: %v\n", r.FileID, r.Err)
+		case len(r.Mismatches) > 0:
+			mismatched++
+			fmt.Printf("probed   file %d, %d mismatches\n", r.FileID, len(r.Mismatches))
+			printMismatches(r.Mismatches)
+		default:
+			fmt.Printf("probed   file %d\n", r.FileID)
+		}
+	}
+	fmt.Printf("%d probed, %d with mismatches, %d failed\n", len(results)-failed, mismatched, failed)
+
+	return err
+}
+
+func runMismatches(ctx context.Context, a app, args []string) error {
+	var mismatches []entities.ProbeMismatch
+	var err error
+	switch len(args) {
+	case 0:
+		mismatches, err = a.service.ProbeService.GetAllMismatches(ctx)
+	case 1:
+		var file_id entities.FileID
+		file_id, err = parseFileID(args[0])
+		if err != nil {
+			return err
+		}
+
+		mismatches, err = a.service.ProbeService.GetMismatches(ctx, file_id)
+	default:
+		return errors.New("expected at most a single file id")
+	}
+	if err != nil {
+		return err
+	}
+
+	printMismatches(mismatches)
+	return nil
+}
+
+func printMismatches(mismatches []entities.ProbeMismatch) {
+	for _, m := range mismatches {
+		fmt.Printf("  file %d: %s is %q, expected %q\n", m.FileID, m.Field, m.Actual, m.Expected)
+	}
+}
+
+func parseFileID(s string) (entities.FileID, error) {
+	id, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || !entities.FileID(id).IsValid() {
+		return entities.InvalidFileID, fmt.Errorf("invalid file id %q", s)
+	}
+
+	return entities.FileID(id), nil
+}
+
+func runFingerprint(ctx context.Context, a app, args []string) error {
+	fs := flag.NewFlagSet("fingerprint", flag.ExitOnError)
+	backfill := fs.Bool("backfill", false, "fingerprint every video file that hasn't been fingerprinted yet")
+	ffmpegPath := fs.String("ffmpeg", "", "path to the ffmpeg binary, looked up in PATH if empty")
+	interval := fs.Float64("interval", ffmpeg.DefaultInterval, "seconds between sampled frames")
+	fs.Parse(args)
+
+	fingerprinter := ffmpeg.NewFfmpeg(*ffmpegPath, *interval)
+
+	var results []service.FingerprintResult
+	var err error
+	switch {
+	case *backfill && fs.NArg() == 0:
+		results, err = a.service.BackfillFingerprints(ctx, fingerprinter)
+	case !*backfill && fs.NArg() > 0:
+		for _, arg := range fs.Args() {
+			file_id, err := parseFileID(arg)
+			if err != nil {
+				return err
+			}
+
+			err = a.service.FingerprintService.FingerprintFile(ctx, file_id, fingerprinter)
+			results = append(results, service.FingerprintResult{FileID: file_id, Err: err})
+		}
+	default:
+		return errors.New("expected either -backfill or one or more file ids")
+	}
+
+	var failed int
+	for _, r := range results {
+		if r.Err != nil {
+			failed++
+			fmt.Printf("failed        file %d: %v\n", r.FileID, r.Err)
+			continue
+		}
+		fmt.Printf("fingerprinted file %d\n", r.FileID)
+	}
+	fmt.Printf("%d fingerprinted, %d failed\n", len(results)-failed, failed)
+
+	return err
+}
+
+func runSimilar(ctx context.Context, a app, args []string) error {
+	fs := flag.NewFlagSet("similar", flag.ExitOnError)
+	minScore := fs.Float64("min-score", server.DefaultMinSimilarity, "lowest similarity, from 0 to 1, to list a file")
+	fs.Parse(args)
+
+	if fs.NArg() != 1 {
+		return errors.New("expected a single file id")
+	}
+
+	file_id, err := parseFileID(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	matches, err := a.service.FingerprintService.FindSimilar(ctx, file_id, *minScore)
+	if err != nil {
+		return err
+	}
+
+	for _, m := range matches {
+		fmt.Printf("file %d: %.2f\n", m.FileID, m.Score)
+	}
+
+	return nil
+}
+
+func runServe(ctx context.Context, a app, args []string) error {
+	fs := flag.NewFlagSet("serve", flag.ExitOnError)
+	address := fs.String("address", "localhost:8080", "address to listen on")
+	fs.Parse(args)
+
+	return server.NewServer(a.service).Start(*address)
+}
+
+func runProject(ctx context.Context, a app, args []string) error {
+	if len(args) < 2 {
+		return errors.New("expected a subcommand and a project uuid")
+	}
+	subcommand, uuid, rest := args[0], entities.ProjectUUID(args[1]), args[2:]
+
+	switch subcommand {
+	case "show":
+		p, err := a.service.ProjectService.GetProject(ctx, uuid)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("uuid:        %s\n", p.UUID)
+		fmt.Printf("title:       %s\n", p.Title)
+		fmt.Printf("type:        %s\n", p.ProjectType.ToString())
+		fmt.Printf("files:       %v\n", p.FileIDs)
+		fmt.Printf("announced:   %s\n", p.DateAnnounced.String())
+		fmt.Printf("completed:   %s\n", p.DateCompleted.String())
+		fmt.Printf("description: %s\n", p.Description)
+		return nil
+	case "title":
+		if len(rest) > 0 {
+			return a.service.ProjectService.SetTitle(ctx, uuid, strings.Join(rest, " "))
+		}
+
+		titles, err := a.service.ProjectService.GetTitles(ctx, uuid)
+		if err != nil {
+			return err
+		}
+
+		for _, t := range titles {
+			fmt.Printf("%s  %s\n", t.DateAdded.Format(time.DateTime), t.Title)
+		}
+		return nil
+	case "description":
+		if len(rest) > 0 {
+			return a.service.ProjectService.SetDescription(ctx, uuid, strings.Join(rest, " "))
+		}
+
+		descriptions, err := a.service.ProjectService.GetDescriptions(ctx, uuid)
+		if err != nil {
+			return err
+		}
+
+		for _, d := range descriptions {
+			fmt.Printf("%s\n%s\n\n", d.DateAdded.Format(time.DateTime), d.Description)
+		}
+		return nil
+	case "parts":
+		parts, err := a.service.ProjectService.GetParts(ctx, uuid)
+		if err != nil {
+			return err
+		}
+
+		for _, part := range parts {
+			printPart(part)
+		}
+		return nil
+	case "music":
+		music, err := a.service.MusicService.GetProjectMusic(ctx, uuid)
+		if err != nil {
+			return err
+		}
+
+		for _, m := range music {
+			fmt.Printf("%d: %s - %s\n", m.ID, m.Artist, m.Title)
+		}
+		return nil
+	case "status":
+		if len(rest) > 0 {
+			status, err := entities.NewProjectStatus(rest[0])
+			if err != nil {
+				return err
+			}
+
+			fs := flag.NewFlagSet("project status", flag.ExitOnError)
+			date := fs.String("date", time.Now().UTC().Format(time.DateOnly), "when the project changed status, as yyyy-mm-dd")
+			fs.Parse(rest[1:])
+
+			d, err := time.Parse(time.DateOnly, *date)
+			if err != nil {
+				return err
+			}
+
+			_, err = a.service.ProjectService.SetStatus(ctx, uuid, entities.ProjectStatusChange{
+				Status: status,
+				Date:   d,
+				Note:   strings.Join(fs.Args(), " "),
+			})
+			return err
+		}
+
+		timeline, err := a.service.ProjectService.GetTimeline(ctx, uuid)
+		if err != nil {
+			return err
+		}
+
+		for _, change := range timeline {
+			fmt.Printf("%s  %-14s %s\n", change.Date.Format(time.DateOnly), change.Status.ToString(), change.Note)
+		}
+		return nil
+	case "unstatus":
+		return a.service.ProjectService.UndoStatus(ctx, uuid)
+	case "announced", "completed":
+		if len(rest) != 1 {
+			return errors.New("expected a date such as 2015, 2015-06 or 2015-06-21, prefixed with a ~ if approximate, or \"\" if unknown")
+		}
+
+		date, err := entities.ParsePartialDate(rest[0])
+		if err != nil {
+			return err
+		}
+
+		if subcommand == "announced" {
+			return a.service.ProjectService.SetDateAnnounced(ctx, uuid, date)
+		}
+		return a.service.ProjectService.SetDateCompleted(ctx, uuid, date)
+	default:
+		return fmt.Errorf("unknown project subcommand %q", subcommand)
+	}
+}
+
+func runPart(ctx context.Context, a app, args []string) error {
+	if len(args) < 2 {
+		return errors.New("expected a subcommand and a project uuid or part id")
+	}
+	subcommand, target, rest := args[0], args[1], args[2:]
+
+	var part entities.ProjectPart
+	switch subcommand {
+	case "add":
+		part.ProjectUUID = entities.ProjectUUID(target)
+	case "set", "rm":
+		part_id, err := strconv.ParseInt(target, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid part id %q", target)
+		}
+
+		if subcommand == "rm" {
+			return a.service.ProjectService.DeletePart(ctx, part_id)
+		}
+
+		part, err = a.service.ProjectService.GetPart(ctx, part_id)
+		if err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unknown part subcommand %q", subcommand)
+	}
+
+	err := parsePartFlags(&part, rest)
+	if err != nil {
+		return err
+	}
+
+	if subcommand == "add" {
+		part.ID, err = a.service.ProjectService.NewPart(ctx, &part)
+	} else {
+		err = a.service.ProjectService.UpdatePart(ctx, &part)
+	}
+	if err != nil {
+		return err
+	}
+
+	printPart(part)
+	return nil
+}
+
+// parsePartFlags overwrites the fields of part given in args, leaving the others as they are.
+func parsePartFlags(part *entities.ProjectPart, args []string) error {
+	fs := flag.NewFlagSet("part", flag.ExitOnError)
+	fs.IntVar(&part.Number, "number", part.Number, "part number")
+	fs.DurationVar(&part.Start, "start", part.Start, "where the part starts in the finished video, such as 1m05s")
+	fs.DurationVar(&part.End, "end", part.End, "where the part ends in the finished video, such as 1m20.5s")
+	status := fs.String("status", part.Status.ToString(), "one of unknown, open, claimed, completed or dropped")
+	channel := fs.String("channel", string(part.Participant), "youtube channel id of the participant, empty for none")
+	fs.StringVar(&part.ParticipantArtist, "artist", part.ParticipantArtist, "name or alias of the participant's artist, empty for none")
+	fs.StringVar(&part.ParticipantName, "name", part.ParticipantName, "name of the participant, empty for none")
+	file_id := fs.Int64("file", int64(part.FileID), "file id of the standalone part, 0 for none")
+	youtube_id := fs.String("youtube", string(part.YoutubeID), "youtube id of the standalone part, empty for none")
+	fs.Parse(args)
+
+	if fs.NArg() != 0 {
+		return fmt.Errorf("unexpected arguments %v", fs.Args())
+	}
+
+	var err error
+	part.Status, err = entities.NewPartStatus(*status)
+	if err != nil {
+		return err
+	}
+
+	part.Participant = entities.YoutubeChannelID(*channel)
+	part.FileID = entities.FileID(*file_id)
+	part.YoutubeID = entities.YoutubeVideoID(*youtube_id)
+	return nil
+}
+
+func printPart(part entities.ProjectPart) {
+	timing := "unknown timing"
+	if part.End > 0 {
+		timing = part.Start.String() + " - " + part.End.String()
+	}
+
+	participant := cmp.Or(part.ParticipantArtist, part.ParticipantName, string(part.Participant), "nobody")
+	fmt.Printf("part %d (id %d): %s, %s, by %s", part.Number, part.ID, part.Status.ToString(), timing, participant)
+	if part.FileID.IsValid() {
+		fmt.Printf(", file %d", part.FileID)
+	}
+	if part.YoutubeID != "" {
+		fmt.Printf(", youtube %s", part.YoutubeID)
+	}
+	fmt.Println()
+}
+
+func runRelation(ctx context.Context, a app, args []string) error {
+	switch {
+	case len(args) == 4 && (args[0] == "add" || args[0] == "rm"):
+		relation_type, err := entities.NewProjectRelationType(args[2])
+		if err != nil {
+			return err
+		}
+
+		relation := entities.ProjectRelation{
+			Project: entities.ProjectUUID(args[1]),
+			Related: entities.ProjectUUID(args[3]),
+			Type:    relation_type,
+		}
+
+		if args[0] == "add" {
+			return a.service.ProjectService.Relate(ctx, relation)
+		}
+		return a.service.ProjectService.Unrelate(ctx, relation)
+	case len(args) == 2 && args[0] == "list":
+		relations, err := a.service.ProjectService.GetRelations(ctx, entities.ProjectUUID(args[1]))
+		if err != nil {
+			return err
+		}
+
+		for _, r := range relations {
+			fmt.Printf("%s %s %s\n", r.Project, r.Type.ToString(), r.Related)
+		}
+		return nil
+	case len(args) == 3 && args[0] == "tree":
+		uuid := entities.ProjectUUID(args[1])
+		relation_type, err := entities.NewProjectRelationType(args[2])
+		if err != nil {
+			return err
+		}
+
+		ancestors, err := a.service.ProjectService.GetAncestors(ctx, uuid, relation_type)
+		if err != nil {
+			return err
+		}
+
+		descendants, err := a.service.ProjectService.GetDescendants(ctx, uuid, relation_type)
+		if err != nil {
+			return err
+		}
+
+		// ancestors are printed furthest first, so that the tree reads top to bottom
+		for i := len(ancestors) - 1; i >= 0; i-- {
+			fmt.Printf("%s%s\n", strings.Repeat("  ", len(ancestors)-ancestors[i].Depth), ancestors[i].Related)
+		}
+		fmt.Printf("%s%s *\n", strings.Repeat("  ", len(ancestors)), uuid)
+		printDescendants(descendants, uuid, len(ancestors)+1)
+		return nil
+	default:
+		return errors.New("expected add|rm <uuid> <type> <related uuid>, list <uuid> or tree <uuid> <type>")
+	}
+}
+
+// printDescendants prints the descendants of parent depth-first, indented by their depth.
+func printDescendants(descendants []entities.ProjectRelation, parent entities.ProjectUUID, indent int) {
+	for _, d := range descendants {
+		if d.Related == parent {
+			fmt.Printf("%s%s\n", strings.Repeat("  ", indent), d.Project)
+			printDescendants(descendants, d.Project, indent+1)
+		}
+	}
+}
+
+func runArtist(ctx context.Context, a app, args []string) error {
+	if len(args) < 2 {
+		return errors.New("expected a subcommand and an artist name")
+	}
+	subcommand, name, rest := args[0], args[1], args[2:]
+
+	switch {
+	case subcommand == "add" && len(rest) == 0:
+		return a.service.ArtistService.NewArtist(ctx, name)
+	case subcommand == "rm" && len(rest) == 0:
+		return a.service.ArtistService.DeleteArtist(ctx, name)
+	case subcommand == "rename" && len(rest) == 1:
+		return a.service.ArtistService.Rename(ctx, name, rest[0])
+	case subcommand == "alias" && len(rest) == 1:
+		return a.service.ArtistService.AddAlias(ctx, name, rest[0])
+	case subcommand == "unalias" && len(rest) == 1:
+		return a.service.ArtistService.RemoveAlias(ctx, name, rest[0])
+	case subcommand == "channel" && len(rest) == 1:
+		return a.service.ArtistService.AssignChannel(ctx, name, entities.YoutubeChannelID(rest[0]))
+	case subcommand == "unchannel" && len(rest) == 1:
+		return a.service.ArtistService.UnassignChannel(ctx, name, entities.YoutubeChannelID(rest[0]))
+	case subcommand == "show" && len(rest) == 0:
+		artist, err := a.service.ArtistService.GetArtist(ctx, name)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("name:     %s\n", artist.Name)
+		fmt.Printf("aliases:  %s\n", strings.Join(artist.Aliases, ", "))
+		fmt.Printf("channels: %v\n", artist.Channels)
+		return nil
+	case subcommand == "videos" && len(rest) == 0:
+		videos, err := a.service.ArtistService.GetVideos(ctx, name)
+		if err != nil {
+			return err
+		}
+
+		for _, v := range videos {
+			fmt.Println(v)
+		}
+		return nil
+	case subcommand == "parts" && len(rest) == 0:
+		parts, err := a.service.ArtistService.GetParts(ctx, name)
+		if err != nil {
+			return err
+		}
+
+		for _, part := range parts {
+			fmt.Printf("%s ", part.ProjectUUID)
+			printPart(part)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown artist subcommand %q or wrong number of arguments", subcommand)
+	}
+}
+
+func runMusic(ctx context.Context, a app, args []string) error {
+	if len(args) < 1 {
+		return errors.New("expected a subcommand")
+	}
+	subcommand, rest := args[0], args[1:]
+
+	switch {
+	case subcommand == "add" && len(rest) == 2:
+		music_id, err := a.service.MusicService.NewMusic(ctx, &entities.Music{Artist: rest[0], Title: rest[1]})
+		if err != nil {
+			return err
+		}
+
+		fmt.Println(music_id)
+		return nil
+	case subcommand == "set" && len(rest) == 3:
+		music_id, err := parseMusicID(rest[0])
+		if err != nil {
+			return err
+		}
+
+		return a.service.MusicService.UpdateMusic(ctx, &entities.Music{ID: music_id, Artist: rest[1], Title: rest[2]})
+	case subcommand == "rm" && len(rest) == 1:
+		music_id, err := parseMusicID(rest[0])
+		if err != nil {
+			return err
+		}
+
+		return a.service.MusicService.DeleteMusic(ctx, music_id)
+	case subcommand == "find":
+		fs := flag.NewFlagSet("music find", flag.ExitOnError)
+		artist := fs.String("artist", "", "artist to look up, which may be misspelt or partial")
+		title := fs.String("title", "", "title to look up, which may be misspelt or partial")
+		fs.Parse(rest)
+
+		matches, err := a.service.MusicService.FindMusic(ctx, *artist, *title)
+		if err != nil {
+			return err
+		}
+
+		for _, m := range matches {
+			fmt.Printf("%d: %s - %s (%.2f)\n", m.Music.ID, m.Music.Artist, m.Music.Title, m.Score)
+		}
+		return nil
+	case subcommand == "projects" && len(rest) == 1:
+		music_id, err := parseMusicID(rest[0])
+		if err != nil {
+			return err
+		}
+
+		uuids, err := a.service.MusicService.GetProjects(ctx, music_id)
+		if err != nil {
+			return err
+		}
+
+		for _, uuid := range uuids {
+			fmt.Println(uuid)
+		}
+		return nil
+	case (subcommand == "assign" || subcommand == "unassign") && len(rest) == 2:
+		music_id, err := parseMusicID(rest[1])
+		if err != nil {
+			return err
+		}
+
+		if subcommand == "assign" {
+			return a.service.MusicService.AssignProject(ctx, entities.ProjectUUID(rest[0]), music_id)
+		}
+		return a.service.MusicService.UnassignProject(ctx, entities.ProjectUUID(rest[0]), music_id)
+	default:
+		return fmt.Errorf("unknown music subcommand %q or wrong number of arguments", subcommand)
+	}
+}
+
+func parseMusicID(s string) (int32, error) {
+	music_id, err := strconv.ParseInt(s, 10, 32)
+	if err != nil {
+		return 0, fmt.Errorf("invalid music id %q", s)
+	}
+
+	return int32(music_id), nil
+}
+
+func runCharacter(ctx context.Context, a app, args []string) error {
+	if len(args) < 2 {
+		return errors.New("expected a subcommand and a character")
+	}
+	subcommand, target, rest := args[0], args[1], args[2:]
+
+	switch subcommand {
+	case "add":
+		fs := flag.NewFlagSet("character add", flag.ExitOnError)
+		series := fs.String("series", "Warriors", "series the character comes from")
+		original := fs.Bool("original", false, "whether the character is fan-made")
+		fs.Parse(rest)
+
+		character := entities.Character{Name: target, Series: *series, IsOriginal: *original}
+		for _, arg := range fs.Args() {
+			alias, err := parseCharacterAlias(arg)
+			if err != nil {
+				return err
+			}
+			character.Aliases = append(character.Aliases, alias)
+		}
+
+		character_id, err := a.service.CharacterService.NewCharacter(ctx, &character)
+		if err != nil {
+			return err
+		}
+
+		fmt.Println(character_id)
+		return nil
+	case "find", "series":
+		var characters []entities.Character
+		var err error
+		if subcommand == "find" {
+			characters, err = a.service.CharacterService.FindCharacters(ctx, strings.Join(args[1:], " "))
+		} else {
+			characters, err = a.service.CharacterService.GetSeriesCharacters(ctx, strings.Join(args[1:], " "))
+		}
+		if err != nil {
+			return err
+		}
+
+		for _, c := range characters {
+			printCharacter(c)
+		}
+		return nil
+	}
+
+	character_id, err := strconv.Atoi(target)
+	if err != nil {
+		return fmt.Errorf("invalid character id %q", target)
+	}
+
+	switch {
+	case subcommand == "set":
+		character, err := a.service.CharacterService.GetCharacter(ctx, character_id)
+		if err != nil {
+			return err
+		}
+
+		fs := flag.NewFlagSet("character set", flag.ExitOnError)
+		fs.StringVar(&character.Name, "name", character.Name, "name the character is known by")
+		fs.StringVar(&character.Series, "series", character.Series, "series the character comes from")
+		fs.BoolVar(&character.IsOriginal, "original", character.IsOriginal, "whether the character is fan-made")
+		fs.Parse(rest)
+
+		return a.service.CharacterService.UpdateCharacter(ctx, &character)
+	case subcommand == "rm" && len(rest) == 0:
+		return a.service.CharacterService.DeleteCharacter(ctx, character_id)
+	case subcommand == "show" && len(rest) == 0:
+		character, err := a.service.CharacterService.GetCharacter(ctx, character_id)
+		if err != nil {
+			return err
+		}
+
+		printCharacter(character)
+		return nil
+	case subcommand == "alias" && len(rest) == 1:
+		alias, err := parseCharacterAlias(rest[0])
+		if err != nil {
+			return err
+		}
+
+		return a.service.CharacterService.AddAlias(ctx, character_id, alias)
+	case subcommand == "unalias" && len(rest) == 1:
+		return a.service.CharacterService.RemoveAlias(ctx, character_id, rest[0])
+	case (subcommand == "tag" || subcommand == "untag") && len(rest) == 2 && rest[0] == "project":
+		if subcommand == "tag" {
+			return a.service.CharacterService.TagProject(ctx, entities.ProjectUUID(rest[1]), character_id)
+		}
+		return a.service.CharacterService.UntagProject(ctx, entities.ProjectUUID(rest[1]), character_id)
+	case (subcommand == "tag" || subcommand == "untag") && len(rest) == 2 && rest[0] == "part":
+		part_id, err := strconv.ParseInt(rest[1], 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid part id %q", rest[1])
+		}
+
+		if subcommand == "tag" {
+			return a.service.CharacterService.TagPart(ctx, part_id, character_id)
+		}
+		return a.service.CharacterService.UntagPart(ctx, part_id, character_id)
+	case subcommand == "projects" && len(rest) == 0:
+		uuids, err := a.service.CharacterService.GetProjects(ctx, character_id)
+		if err != nil {
+			return err
+		}
+
+		for _, uuid := range uuids {
+			fmt.Println(uuid)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown character subcommand %q or wrong number of arguments", subcommand)
+	}
+}
+
+// parseCharacterAlias parses an alias given as stage:name, such as apprentice:Firepaw. A name without a stage is taken
+// as entities.NameStageOther.
+func parseCharacterAlias(s string) (entities.CharacterAlias, error) {
+	stage, name, ok := strings.Cut(s, ":")
+	if !ok {
+		return entities.CharacterAlias{Name: s, Stage: entities.NameStageOther}, nil
+	}
+
+	name_stage, err := entities.NewCharacterNameStage(stage)
+	if err != nil {
+		return entities.CharacterAlias{}, err
+	}
+
+	return entities.CharacterAlias{Name: name, Stage: name_stage}, nil
+}
+
+func printCharacter(character entities.Character) {
+	aliases := make([]string, len(character.Aliases))
+	for i, alias := range character.Aliases {
+		aliases[i] = alias.Stage.ToString() + ":" + alias.Name
+	}
+
+	original := ""
+	if character.IsOriginal {
+		original = ", original"
+	}
+
+	fmt.Printf("%d: %s (%s%s) %s\n", character.ID, character.Name, character.Series, original, strings.Join(aliases, " "))
+}
+
+func runSearch(ctx context.Context, a app, args []string) error {
+	fs := flag.NewFlagSet("search", flag.ExitOnError)
+	kind := fs.String("kind", "", "only search youtube, channel, project, artist, music or character")
+	limit := fs.Int("limit", 0, "most results to list")
+	fs.Parse(args)
+
+	search_kind, err := entities.NewSearchKind(*kind)
+	if err != nil {
+		return err
+	}
+
+	results, err := a.service.SearchService.Search(ctx, strings.Join(fs.Args(), " "), search_kind, *limit)
+	if err != nil {
+		return err
+	}
+
+	highlight := strings.NewReplacer("<b>", "*", "</b>", "*")
+	for _, r := range results {
+		fmt.Printf("%s %s: %s\n    %s\n", r.Kind.ToString(), r.ID, r.Title, html.UnescapeString(highlight.Replace(r.Headline)))
+	}
+
+	return nil
+}
+
+func runAudit(ctx context.Context, a app, args []string) error {
+	if len(args) != 2 {
+		return errors.New("expected project, file or youtube, and its id")
+	}
+
+	entity, err := entities.NewAuditEntity(args[0])
+	if err != nil {
+		return err
+	}
+
+	entries, err := a.service.AuditService.GetEntityLog(ctx, entity, args[1])
+	if err != nil {
+		return err
+	}
+
+	for _, e := range entries {
+		fmt.Printf("%d  %s  %s  %s\n    before: %s\n    after:  %s\n", e.ID, e.DateAdded.Format(time.DateTime), e.Actor, e.Action, e.Before, e.After)
+	}
+
+	return nil
+}
+
+func runList(ctx context.Context, a app, args []string) error {
+	if len(args) < 1 {
+		return errors.New("expected youtube, projects, channels or files")
+	}
+	kind, rest := args[0], args[1:]
+
+	fs := flag.NewFlagSet("list "+kind, flag.ExitOnError)
+	sort := fs.String("sort", "", "sort by uploaded, archived, duration or views, or a project's announced or completed date")
+	ascending := fs.Bool("asc", false, "list oldest, shortest or least viewed first")
+	cursor := fs.String("cursor", "", "cursor of the page to list, as printed after the previous one")
+	limit := fs.Int("limit", 0, "most results to list")
+	fs.Parse(rest)
+
+	list_sort, err := entities.NewListSort(*sort)
+	if err != nil {
+		return err
+	}
+
+	opts := entities.ListOptions{Sort: list_sort, Ascending: *ascending, Cursor: *cursor, Limit: *limit}
+	query := strings.Join(fs.Args(), " ")
+	if query != "" && kind != "youtube" && kind != "projects" {
+		return fmt.Errorf("%s can't be filtered", kind)
+	}
+
+	var results []string
+	var next_cursor string
+	switch kind {
+	case "youtube":
+		var youtube_ids []entities.YoutubeVideoID
+		youtube_ids, next_cursor, err = a.service.YoutubeService.ListYoutube(ctx, query, opts)
+		for _, youtube_id := range youtube_ids {
+			results = append(results, string(youtube_id))
+		}
+	case "projects":
+		var uuids []entities.ProjectUUID
+		uuids, next_cursor, err = a.service.ProjectService.ListProjects(ctx, query, opts)
+		for _, uuid := range uuids {
+			results = append(results, string(uuid))
+		}
+	case "channels":
+		var channel_ids []entities.YoutubeChannelID
+		channel_ids, next_cursor, err = a.service.YoutubeService.ListChannels(ctx, opts)
+		for _, channel_id := range channel_ids {
+			results = append(results, string(channel_id))
+		}
+	case "files":
+		var file_ids []entities.FileID
+		file_ids, next_cursor, err = a.service.FileService.ListFiles(ctx, opts)
+		for _, file_id := range file_ids {
+			results = append(results, strconv.FormatInt(int64(file_id), 10))
+		}
+	default:
+		return fmt.Errorf("can't list %q, expected youtube, projects, channels or files", kind)
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, r := range results {
+		fmt.Println(r)
+	}
+
+	if next_cursor != "" {
+		fmt.Fprintf(os.Stderr, "more results with -cursor %s\n", next_cursor)
+	}
+
+	return nil
+}
+
+func runBundle(ctx context.Context, a app, args []string) error {
+	if len(args) < 1 {
+		return errors.New("expected export or import")
+	}
+
+	switch args[0] {
+	case "export":
+		return runBundleExport(ctx, a, args[1:])
+	case "import":
+		if len(args) != 2 {
+			return errors.New("expected a bundle to import")
+		}
+
+		f, err := os.Open(args[1])
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		result, err := a.service.ImportBundle(ctx, f)
+		fmt.Printf("%d new files, %d new youtube videos, %d new projects\n", result.NewFiles, result.NewYoutube, result.NewProjects)
+		if result.SkippedParticipants > 0 {
+			fmt.Printf("%d part participants dropped, as their channels aren't archived\n", result.SkippedParticipants)
+		}
+		if result.SkippedRelations > 0 {
+			fmt.Printf("%d relations skipped, as their related projects aren't archived\n", result.SkippedRelations)
+		}
+
+		return err
+	default:
+		return fmt.Errorf("unknown bundle command %q", args[0])
+	}
+}
+
+func runBundleExport(ctx context.Context, a app, args []string) error {
+	fs := flag.NewFlagSet("bundle export", flag.ExitOnError)
+	output := fs.String("o", "bundle.tar", "file to write the bundle to")
+	limit := fs.Int("limit", 0, "most search results to export")
+	fs.Parse(args)
+
+	if fs.NArg() < 2 {
+		return errors.New("expected project, youtube, channel or search, and what to export")
+	}
+	kind, rest := fs.Arg(0), fs.Args()[1:]
+
+	var selection service.BundleSelection
+	switch kind {
+	case "project":
+		for _, uuid := range rest {
+			selection.Projects = append(selection.Projects, entities.ProjectUUID(uuid))
+		}
+	case "youtube":
+		for _, youtube_id := range rest {
+			selection.Youtube = append(selection.Youtube, entities.YoutubeVideoID(youtube_id))
+		}
+	case "channel":
+		for _, channel_id := range rest {
+			videos, err := a.service.YoutubeService.GetChannelVideos(ctx, entities.YoutubeChannelID(channel_id))
+			if err != nil {
+				return fmt.Errorf("channel %s: %w", channel_id, err)
+			}
+
+			selection.Youtube = append(selection.Youtube, videos...)
+		}
+	case "search":
+		results, err := a.service.SearchService.Search(ctx, strings.Join(rest, " "), entities.SearchKindAny, *limit)
+		if err != nil {
+			return err
+		}
+
+		for _, r := range results {
+			switch r.Kind {
+			case entities.SearchKindProject:
+				selection.Projects = append(selection.Projects, entities.ProjectUUID(r.ID))
+			case entities.SearchKindYoutube:
+				selection.Youtube = append(selection.Youtube, entities.YoutubeVideoID(r.ID))
+			}
+		}
+	default:
+		return fmt.Errorf("can't export %q, expected project, youtube, channel or search", kind)
+	}
+
+	f, err := os.Create(*output)
+	if err != nil {
+		return err
+	}
+
+	manifest, err := a.service.ExportBundle(ctx, f, selection)
+	if err != nil {
+		return errors.Join(err, f.Close(), os.Remove(*output))
+	}
+
+	err = f.Close()
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("wrote %d files, %d youtube videos and %d projects to %s\n", len(manifest.Files), len(manifest.Youtube), len(manifest.Projects), *output)
+	return nil
+}
+
+func runBag(ctx context.Context, a app, args []string) error {
+	if len(args) < 1 {
+		return errors.New("expected project, youtube or validate")
+	}
+
+	if args[0] == "validate" {
+		if len(args) != 2 {
+			return errors.New("expected a bag to validate")
+		}
+
+		err := bagit.Validate(args[1])
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("%s is a valid bag\n", args[1])
+		return nil
+	}
+
+	fs := flag.NewFlagSet("bag "+args[0], flag.ExitOnError)
+	org := fs.String("org", "", "organization handing the bag over")
+	fs.Parse(args[1:])
+
+	if fs.NArg() != 2 {
+		return errors.New("expected an id and a directory to write the bag to")
+	}
+	id, directory := fs.Arg(0), fs.Arg(1)
+	opts := service.BagOptions{SourceOrganization: *org}
+
+	switch args[0] {
+	case "project":
+		return a.service.ExportProjectBag(ctx, directory, entities.ProjectUUID(id), opts)
+	case "youtube":
+		return a.service.ExportYoutubeBag(ctx, directory, entities.YoutubeVideoID(id), opts)
+	default:
+		return fmt.Errorf("can't bag %q, expected project or youtube", args[0])
+	}
+}
+
+func runSidecar(ctx context.Context, a app, args []string) error {
+	fs := flag.NewFlagSet("sidecar", flag.ExitOnError)
+	backfill := fs.Bool("backfill", false, "write the sidecar of every stored file")
+	fs.Parse(args)
+
+	var results []service.SidecarResult
+	var err error
+	switch {
+	case *backfill && fs.NArg() == 0:
+		results, err = a.service.BackfillSidecars(ctx)
+	case !*backfill && fs.NArg() > 0:
+		for _, arg := range fs.Args() {
+			file_id, err := parseFileID(arg)
+			if err != nil {
+				return err
+			}
+
+			results = append(results, service.SidecarResult{FileID: file_id, Err: a.service.WriteSidecar(ctx, file_id)})
+		}
+	default:
+		return errors.New("expected either -backfill or one or more file ids")
+	}
+
+	var failed int
+	for _, r := range results {
+		if r.Err != nil {
+			failed++
+			fmt.Printf("failed   file %d: %v\n", r.FileID, r.Err)
+		}
+	}
+	fmt.Printf("%d written, %d failed\n", len(results)-failed, failed)
+
+	return err
+}
+
+func runFsck(ctx context.Context, a app, args []string) error {
+	fs := flag.NewFlagSet("fsck", flag.ExitOnError)
+	verify := fs.Bool("verify", false, "rehash the stored contents of every file, reading the whole storage root")
+	repair := fs.Bool("repair", false, "repair every finding which can be repaired automatically")
+	fs.Parse(args)
+
+	if fs.NArg() != 0 {
+		return errors.New("expected no arguments")
+	}
+
+	findings, err := a.service.Fsck(ctx, service.FsckOptions{Verify: *verify})
+
+	var counts [service.FsckError + 1]int
+	var repaired, failed int
+	for _, f := range findings {
+		fmt.Printf("%-8s %-22s %s: %s\n", f.Severity.ToString(), f.Check, f.Subject, f.Detail)
+
+		if !f.IsRepairable() {
+			counts[f.Severity]++
+			continue
+		}
+
+		if !*repair {
+			counts[f.Severity]++
+			fmt.Printf("%-31s repair: %s\n", "", f.Repair)
+			continue
+		}
+
+		if rerr := a.service.RepairFinding(ctx, f); rerr != nil {
+			counts[f.Severity]++
+			failed++
+			fmt.Printf("%-31s failed to repair: %v\n", "", rerr)
+			continue
+		}
+
+		repaired++
+		fmt.Printf("%-31s repaired: %s\n", "", f.Repair)
+	}
+	fmt.Printf("%d errors, %d warnings, %d info left, %d repaired, %d failed to repair\n",
+		counts[service.FsckError], counts[service.FsckWarning], counts[service.FsckInfo], repaired, failed)
+
+	if err != nil {
+		return err
+	}
+
+	if counts[service.FsckError] > 0 {
+		return fmt.Errorf("%d errors left", counts[service.FsckError])
+	}
+
+	return nil
+}
+
+func runTree(ctx context.Context, a app, args []string) error {
+	fs := flag.NewFlagSet("tree", flag.ExitOnError)
+	link := fs.String("link", "reflink,hardlink,copy", "link methods to try in order; a hardlinked entry is the stored file itself, so editing it corrupts the archive")
+	every := fs.Duration("every", 0, "keep syncing the tree this often, instead of syncing it once")
+	writeNFO := fs.Bool("nfo", false, "write a Kodi/Jellyfin .nfo file, and poster art where a thumbnail was archived, next to every entry")
+	fs.Parse(args)
+
+	if fs.NArg() != 1 {
+		return errors.New("expected a directory to sync the tree into")
+	}
+
+	var methods []tree.Method
+	for _, name := range strings.Split(*link, ",") {
+		method, err := tree.NewMethod(strings.TrimSpace(name))
+		if err != nil {
+			return err
+		}
+		methods = append(methods, method)
+	}
+
+	for {
+		result, err := a.service.ExportTree(ctx, fs.Arg(0), service.TreeOptions{Methods: methods, NFO: *writeNFO})
+		for _, p := range result.Conflicts {
+			fmt.Printf("conflict %s is in the way\n", p)
+		}
+		fmt.Printf("%d added, %d replaced, %d removed, %d unchanged, %d conflicting (%d reflinked, %d hardlinked, %d copied, %d written)\n",
+			result.Added, result.Replaced, result.Removed, result.Unchanged, len(result.Conflicts),
+			result.Linked[tree.MethodReflink], result.Linked[tree.MethodHardlink], result.Linked[tree.MethodCopy], result.Written)
+		if err != nil || *every <= 0 {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(*every):
+		}
+	}
+}
+
+func runRebuild(ctx context.Context, _ app, args []string) error {
+	fs := flag.NewFlagSet("rebuild", flag.ExitOnError)
+	database := fs.String("database", wc_main_pg, "empty database to rebuild into")
+	storageDirectory := fs.String("storage", wc_storage_directory, "storage root to rebuild out of")
+	fs.Parse(args)
+
+	if fs.NArg() != 0 {
+		return errors.New("expected no arguments")
+	}
+
+	db, err := sql.Open("pgx", *database)
+	if err != nil {
+		return err
+	}
+
+	// creating the schema in a single transaction fails as a whole on a database which already has one
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.Join(err, db.Close())
+	}
+
+	_, err = tx.ExecContext(ctx, postgres.Schema)
+	if err == nil {
+		err = tx.Commit()
+	}
+	if err != nil {
+		return errors.Join(err, tx.Rollback(), db.Close())
+	}
+
+	if err := db.Close(); err != nil {
+		return err
+	}
+
+	a, err := newApp(*database, *storageDirectory)
+	if err != nil {
+		return err
+	}
+	defer a.db.Close()
+
+	result, err := a.service.Rebuild(ctx)
+	for _, path := range result.Skipped {
+		fmt.Printf("skipped  %s, it isn't where its contents would be stored\n", path)
+	}
+	fmt.Printf("%d files, %d of them without a sidecar, %d youtube videos and %d projects rebuilt\n",
+		result.NewFiles, result.WithoutSidecar, result.NewYoutube, result.NewProjects)
+
+	return err
+}
+
+func runBackup(ctx context.Context, _ app, args []string) error {
+	if len(args) < 1 {
+		return errors.New("expected create, verify or restore")
+	}
+
+	fs := flag.NewFlagSet("backup "+args[0], flag.ExitOnError)
+	pgDumpPath := fs.String("pg-dump", "", "path to the pg_dump binary, looked up in PATH if empty")
+	pgRestorePath := fs.String("pg-restore", "", "path to the pg_restore binary, looked up in PATH if empty")
+	database := fs.String("database", wc_main_pg, "empty database to restore into")
+	storageDirectory := fs.String("storage", wc_storage_directory, "empty storage root to restore into")
+	fs.Parse(args[1:])
+
+	if fs.NArg() < 1 || fs.NArg() > 2 || (args[0] == "create" && fs.NArg() != 1) {
+		return errors.New("expected a backup directory, optionally followed by the name of a backup within it")
+	}
+	directory, name := fs.Arg(0), fs.Arg(1)
+	b := backup.NewBackup(*pgDumpPath, *pgRestorePath)
+
+	switch args[0] {
+	case "create":
+		a, err := newApp(wc_main_pg, wc_storage_directory)
+		if err != nil {
+			return err
+		}
+		defer a.db.Close()
+
+		m, err := b.Create(ctx, a.db, wc_main_pg, wc_storage_directory, directory)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("backed up %s with %d new files, up to file %d\n", m.Name, len(m.Files), m.LastFileID)
+		return nil
+	case "verify":
+		m, err := b.Verify(ctx, directory, name)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("%s is a valid backup, up to file %d\n", m.Name, m.LastFileID)
+		return nil
+	case "restore":
+		db, err := sql.Open("pgx", *database)
+		if err != nil {
+			return err
+		}
+		defer db.Close()
+
+		m, err := b.Restore(ctx, db, *database, *storageDirectory, directory, name)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("restored %s, up to file %d\n", m.Name, m.LastFileID)
+		return nil
+	default:
+		return fmt.Errorf("unknown backup subcommand %q", args[0])
+	}
+}
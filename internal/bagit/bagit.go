@@ -0,0 +1,257 @@
+// Package bagit writes and validates BagIt bags as described by RFC 8493, which is how institutions preserving part of
+// the archive ask for it. A bag is a directory holding its payload under data/, along with manifests of the checksums of
+// every payload file, and tag files describing it.
+package bagit
+
+import (
+	"bytes"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/dtbead/wc-maps-archive/internal/entities"
+)
+
+// Version is the version of BagIt written by Write.
+const Version = "1.0"
+
+const (
+	payloadDirectory = "data"
+	declarationName  = "bagit.txt"
+	infoName         = "bag-info.txt"
+)
+
+// algorithms are the checksum algorithms manifests may use, by the name they're given in a manifest's filename.
+var algorithms = map[string]func() hash.Hash{
+	"md5":    md5.New,
+	"sha1":   sha1.New,
+	"sha256": sha256.New,
+	"sha512": sha512.New,
+}
+
+// InfoField is a single line of bag-info.txt, such as "Source-Organization: ...". Labels may repeat.
+type InfoField struct {
+	Label, Value string
+}
+
+// Payload is a file of a bag, kept at data/<Path>. Hashes are the checksums it's known to have, which the manifests of
+// the bag are built from, and Open returns its contents.
+type Payload struct {
+	Path   string
+	Size   int64
+	Hashes entities.Hashes
+	Open   func() (io.ReadCloser, error)
+}
+
+// TagFile is a file describing a bag, kept at Path outside of data/.
+type TagFile struct {
+	Path     string
+	Contents []byte
+}
+
+type Bag struct {
+	Info    []InfoField
+	Payload []Payload
+	Tags    []TagFile
+}
+
+// Write creates directory and writes bag to it, with SHA256 and MD5 manifests of its payload and tag files. The
+// Bagging-Date and Payload-Oxum of bag-info.txt are filled in. Payload is verified against its Hashes while being
+// copied, failing with entities.ErrorHashMismatch if it doesn't match. Nothing is left of directory if Write fails.
+func Write(directory string, bag Bag) (err error) {
+	if err := checkPaths(bag); err != nil {
+		return err
+	}
+
+	err = os.Mkdir(directory, 0775)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			err = errors.Join(err, os.RemoveAll(directory))
+		}
+	}()
+
+	// the payload directory exists even when there's no payload
+	err = os.Mkdir(filepath.Join(directory, payloadDirectory), 0775)
+	if err != nil {
+		return err
+	}
+
+	write := func(name string, contents []byte) error {
+		name = filepath.Join(directory, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(name), 0775); err != nil {
+			return err
+		}
+
+		return os.WriteFile(name, contents, 0664)
+	}
+
+	var octets int64
+	sha256_manifest, md5_manifest := manifest{}, manifest{}
+	for _, p := range bag.Payload {
+		name := payloadDirectory + "/" + p.Path
+		err = writePayload(filepath.Join(directory, filepath.FromSlash(name)), p)
+		if err != nil {
+			return fmt.Errorf("%s: %w", p.Path, err)
+		}
+
+		octets += p.Size
+		sha256_manifest[name] = hex.EncodeToString(p.Hashes.SHA256)
+		md5_manifest[name] = hex.EncodeToString(p.Hashes.MD5)
+	}
+
+	info := append([]InfoField{
+		{"Bagging-Date", time.Now().UTC().Format(time.DateOnly)},
+		{"Payload-Oxum", fmt.Sprintf("%d.%d", octets, len(bag.Payload))},
+	}, bag.Info...)
+
+	tags := append([]TagFile{
+		{declarationName, []byte("BagIt-Version: " + Version + "\nTag-File-Character-Encoding: UTF-8\n")},
+		{infoName, formatInfo(info)},
+		{"manifest-sha256.txt", sha256_manifest.format()},
+		{"manifest-md5.txt", md5_manifest.format()},
+	}, bag.Tags...)
+
+	sha256_tags, md5_tags := manifest{}, manifest{}
+	for _, t := range tags {
+		err = write(t.Path, t.Contents)
+		if err != nil {
+			return err
+		}
+
+		sha256_tags[t.Path] = checksum(sha256.New(), t.Contents)
+		md5_tags[t.Path] = checksum(md5.New(), t.Contents)
+	}
+
+	err = write("tagmanifest-sha256.txt", sha256_tags.format())
+	if err != nil {
+		return err
+	}
+
+	return write("tagmanifest-md5.txt", md5_tags.format())
+}
+
+// checkPaths makes sure every file of bag stays within the bag, in its place, and is only written once.
+func checkPaths(bag Bag) error {
+	seen := make(map[string]bool, len(bag.Payload)+len(bag.Tags))
+	check := func(name string) error {
+		if name == "" || path.IsAbs(name) || path.Clean(name) != name || name == ".." || strings.HasPrefix(name, "../") || strings.Contains(name, `\`) {
+			return fmt.Errorf("invalid path %q", name)
+		}
+
+		if seen[name] {
+			return fmt.Errorf("%q is in the bag twice", name)
+		}
+
+		seen[name] = true
+		return nil
+	}
+
+	for _, p := range bag.Payload {
+		if err := check(payloadDirectory + "/" + p.Path); err != nil {
+			return err
+		}
+	}
+
+	for _, t := range bag.Tags {
+		if err := check(t.Path); err != nil {
+			return err
+		}
+
+		base := path.Base(t.Path)
+		if t.Path == payloadDirectory || strings.HasPrefix(t.Path, payloadDirectory+"/") || t.Path == declarationName || t.Path == infoName ||
+			strings.HasPrefix(base, "manifest-") || strings.HasPrefix(base, "tagmanifest-") {
+			return fmt.Errorf("tag file %q is in the place of one written by the bag itself", t.Path)
+		}
+	}
+
+	return nil
+}
+
+func writePayload(name string, p Payload) (err error) {
+	if err := os.MkdirAll(filepath.Dir(name), 0775); err != nil {
+		return err
+	}
+
+	r, err := p.Open()
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	f, err := os.Create(name)
+	if err != nil {
+		return err
+	}
+	defer func() { err = errors.Join(err, f.Close()) }()
+
+	sha256_hash, md5_hash := sha256.New(), md5.New()
+	written, err := io.Copy(io.MultiWriter(f, sha256_hash, md5_hash), r)
+	if err != nil {
+		return err
+	}
+
+	if written != p.Size || !bytes.Equal(sha256_hash.Sum(nil), p.Hashes.SHA256) || !bytes.Equal(md5_hash.Sum(nil), p.Hashes.MD5) {
+		return fmt.Errorf("%w, the stored file no longer matches", entities.ErrorHashMismatch)
+	}
+
+	return f.Sync()
+}
+
+// manifest maps the paths of files to their checksum.
+type manifest map[string]string
+
+// format formats m as a manifest file, sorted by path.
+func (m manifest) format() []byte {
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	var b bytes.Buffer
+	for _, name := range names {
+		fmt.Fprintf(&b, "%s  %s\n", m[name], encodePath(name))
+	}
+
+	return b.Bytes()
+}
+
+func checksum(h hash.Hash, contents []byte) string {
+	h.Write(contents)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// encodePath percent encodes the characters a path can't hold within a manifest.
+func encodePath(name string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A").Replace(name)
+}
+
+func decodePath(name string) string {
+	return strings.NewReplacer("%25", "%", "%0D", "\r", "%0d", "\r", "%0A", "\n", "%0a", "\n").Replace(name)
+}
+
+// formatInfo formats fields as bag-info.txt, continuing values spanning several lines on indented lines.
+func formatInfo(fields []InfoField) []byte {
+	var b bytes.Buffer
+	for _, f := range fields {
+		value := strings.ReplaceAll(strings.TrimSpace(strings.ReplaceAll(f.Value, "\r\n", "\n")), "\n", "\n  ")
+		fmt.Fprintf(&b, "%s: %s\n", f.Label, value)
+	}
+
+	return b.Bytes()
+}
@@ -0,0 +1,166 @@
+package bagit_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dtbead/wc-maps-archive/internal/bagit"
+	"github.com/dtbead/wc-maps-archive/internal/entities"
+	file_helper "github.com/dtbead/wc-maps-archive/internal/helper/file"
+)
+
+func newPayload(t *testing.T, name string, contents []byte) bagit.Payload {
+	t.Helper()
+
+	hashes, size, err := file_helper.GetHash(bytes.NewReader(contents))
+	if err != nil {
+		t.Fatalf("failed to hash contents, %v", err)
+	}
+
+	return bagit.Payload{
+		Path:   name,
+		Size:   size,
+		Hashes: hashes,
+		Open:   func() (io.ReadCloser, error) { return io.NopCloser(bytes.NewReader(contents)), nil },
+	}
+}
+
+func newBag(t *testing.T) bagit.Bag {
+	t.Helper()
+
+	return bagit.Bag{
+		Info: []bagit.InfoField{
+			{"External-Identifier", "https://www.youtube.com/watch?v=dQw4w9WgXcQ"},
+			{"External-Description", "firestar MAP\n\nparts:\n1. leafstar"},
+		},
+		Payload: []bagit.Payload{
+			newPayload(t, "first.mp4", []byte("the first video, at least sixteen bytes long")),
+			newPayload(t, "videos/second.mkv", []byte("the second video, at least sixteen bytes long")),
+		},
+		Tags: []bagit.TagFile{{Path: "ytdlp/first.info.json", Contents: []byte(`{"id": "dQw4w9WgXcQ"}`)}},
+	}
+}
+
+func writeBag(t *testing.T) string {
+	t.Helper()
+
+	directory := filepath.Join(t.TempDir(), "bag")
+	if err := bagit.Write(directory, newBag(t)); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	return directory
+}
+
+func TestWrite(t *testing.T) {
+	directory := writeBag(t)
+
+	if err := bagit.Validate(directory); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+
+	for name, want := range map[string]string{
+		"bagit.txt":             "BagIt-Version: 1.0\nTag-File-Character-Encoding: UTF-8\n",
+		"data/first.mp4":        "the first video, at least sixteen bytes long",
+		"ytdlp/first.info.json": `{"id": "dQw4w9WgXcQ"}`,
+	} {
+		got, err := os.ReadFile(filepath.Join(directory, name))
+		if err != nil {
+			t.Errorf("reading %s, error = %v", name, err)
+			continue
+		}
+
+		if string(got) != want {
+			t.Errorf("%s = %q, want %q", name, got, want)
+		}
+	}
+
+	info, err := os.ReadFile(filepath.Join(directory, "bag-info.txt"))
+	if err != nil {
+		t.Fatalf("reading bag-info.txt, error = %v", err)
+	}
+
+	for _, want := range []string{"Payload-Oxum: 89.2\n", "External-Description: firestar MAP\n  \n  parts:\n  1. leafstar\n"} {
+		if !strings.Contains(string(info), want) {
+			t.Errorf("bag-info.txt = %q, want it to contain %q", info, want)
+		}
+	}
+}
+
+func TestWrite_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(bag *bagit.Bag)
+		wantErr error
+	}{
+		{"stored file changed", func(bag *bagit.Bag) { bag.Payload[0].Hashes.MD5[0]++ }, entities.ErrorHashMismatch},
+		{"payload outside of data", func(bag *bagit.Bag) { bag.Payload[0].Path = "../first.mp4" }, nil},
+		{"tag file in data", func(bag *bagit.Bag) { bag.Tags[0].Path = "data/info.json" }, nil},
+		{"tag file replacing a manifest", func(bag *bagit.Bag) { bag.Tags[0].Path = "manifest-sha256.txt" }, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bag := newBag(t)
+			tt.modify(&bag)
+
+			directory := filepath.Join(t.TempDir(), "bag")
+			err := bagit.Write(directory, bag)
+			if err == nil {
+				t.Fatalf("Write() error = nil")
+			}
+
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("Write() error = %v, want %v", err, tt.wantErr)
+			}
+
+			if _, err := os.Stat(directory); !errors.Is(err, os.ErrNotExist) {
+				t.Errorf("Write() left %s behind", directory)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(directory string) error
+		wantErr error
+	}{
+		{"payload changed", func(directory string) error {
+			return os.WriteFile(filepath.Join(directory, "data/first.mp4"), []byte("The first video, at least sixteen bytes long"), 0664)
+		}, entities.ErrorHashMismatch},
+		{"payload missing", func(directory string) error {
+			return os.Remove(filepath.Join(directory, "data/videos/second.mkv"))
+		}, entities.ErrorInvalidBag},
+		{"payload added", func(directory string) error {
+			return os.WriteFile(filepath.Join(directory, "data/third.mp4"), []byte("a third video"), 0664)
+		}, entities.ErrorInvalidBag},
+		{"tag file changed", func(directory string) error {
+			return os.WriteFile(filepath.Join(directory, "ytdlp/first.info.json"), []byte(`{}`), 0664)
+		}, entities.ErrorHashMismatch},
+		{"not a bag", func(directory string) error {
+			return os.Remove(filepath.Join(directory, "bagit.txt"))
+		}, entities.ErrorInvalidBag},
+		{"unsupported algorithm", func(directory string) error {
+			return os.WriteFile(filepath.Join(directory, "manifest-crc32.txt"), nil, 0664)
+		}, entities.ErrorInvalidBag},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			directory := writeBag(t)
+			if err := tt.modify(directory); err != nil {
+				t.Fatal(err)
+			}
+
+			err := bagit.Validate(directory)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Validate() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,305 @@
+package bagit
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/dtbead/wc-maps-archive/internal/entities"
+)
+
+// Validate checks the bag at directory, whether written by Write or not. It's valid if it declares a version of BagIt,
+// every file under data/ is listed in every payload manifest and the other way around, every checksum of its payload and
+// tag manifests match, and its Payload-Oxum matches its payload, if it has one. Every problem found is returned together,
+// each wrapping entities.ErrorInvalidBag, and also entities.ErrorHashMismatch if it's a checksum that doesn't match.
+func Validate(directory string) error {
+	v := validator{directory: directory}
+
+	if err := v.declaration(); err != nil {
+		return err
+	}
+
+	manifests, err := filepath.Glob(filepath.Join(directory, "manifest-*.txt"))
+	if err != nil {
+		return err
+	}
+
+	if len(manifests) == 0 {
+		v.fail(errors.New("there's no payload manifest"))
+	}
+
+	payload, err := v.payload()
+	if err != nil {
+		return err
+	}
+
+	for _, m := range manifests {
+		v.manifest(filepath.Base(m), payload)
+	}
+
+	tag_manifests, err := filepath.Glob(filepath.Join(directory, "tagmanifest-*.txt"))
+	if err != nil {
+		return err
+	}
+
+	for _, m := range tag_manifests {
+		v.manifest(filepath.Base(m), payload)
+	}
+
+	v.oxum(payload)
+	return errors.Join(v.problems...)
+}
+
+type validator struct {
+	directory string
+	problems  []error
+}
+
+func (v *validator) fail(err error) {
+	v.problems = append(v.problems, fmt.Errorf("%w, %w", entities.ErrorInvalidBag, err))
+}
+
+// declaration checks bagit.txt, which has to exist for the directory to be a bag at all.
+func (v *validator) declaration() error {
+	contents, err := os.ReadFile(filepath.Join(v.directory, declarationName))
+	if err != nil {
+		return fmt.Errorf("%w, %w", entities.ErrorInvalidBag, err)
+	}
+
+	fields := parseInfo(contents)
+	version, _ := lookup(fields, "BagIt-Version")
+	encoding, _ := lookup(fields, "Tag-File-Character-Encoding")
+	if version == "" || encoding == "" {
+		return fmt.Errorf("%w, %s doesn't declare BagIt-Version and Tag-File-Character-Encoding", entities.ErrorInvalidBag, declarationName)
+	}
+
+	if !strings.EqualFold(encoding, "UTF-8") {
+		v.fail(fmt.Errorf("tag files are encoded as %s rather than UTF-8", encoding))
+	}
+
+	return nil
+}
+
+// payload returns the path and size of every file under data/, relative to the bag.
+func (v *validator) payload() (sizes map[string]int64, err error) {
+	sizes = make(map[string]int64)
+	root := filepath.Join(v.directory, payloadDirectory)
+
+	err = filepath.WalkDir(root, func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(v.directory, name)
+		if err != nil {
+			return err
+		}
+
+		sizes[filepath.ToSlash(rel)] = info.Size()
+		return nil
+	})
+	if errors.Is(err, fs.ErrNotExist) {
+		v.fail(fmt.Errorf("there's no %s directory", payloadDirectory))
+		return sizes, nil
+	}
+
+	return sizes, err
+}
+
+// manifest checks every checksum of the manifest called name. A payload manifest also has to list every file of
+// payload, and nothing else, while a tag manifest may only list files outside of it.
+func (v *validator) manifest(name string, payload map[string]int64) {
+	is_payload := !strings.HasPrefix(name, "tagmanifest-")
+	algorithm := strings.TrimSuffix(strings.TrimPrefix(strings.TrimPrefix(name, "tag"), "manifest-"), ".txt")
+	new_hash, ok := algorithms[algorithm]
+	if !ok {
+		v.fail(fmt.Errorf("%s uses an unsupported algorithm %q", name, algorithm))
+		return
+	}
+
+	f, err := os.Open(filepath.Join(v.directory, name))
+	if err != nil {
+		v.fail(err)
+		return
+	}
+	defer f.Close()
+
+	listed := make(map[string]bool)
+	scanner := bufio.NewScanner(f)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimRight(scanner.Text(), "\r")
+		if strings.TrimSpace(text) == "" {
+			continue
+		}
+
+		i := strings.IndexAny(text, " \t")
+		if i < 0 {
+			v.fail(fmt.Errorf("%s line %d isn't a checksum and a path", name, line))
+			continue
+		}
+
+		sum, file := text[:i], decodePath(strings.TrimLeft(text[i:], " \t"))
+		if file == "" {
+			v.fail(fmt.Errorf("%s line %d isn't a checksum and a path", name, line))
+			continue
+		}
+
+		if path.IsAbs(file) || path.Clean(file) != file || strings.HasPrefix(file, "../") {
+			v.fail(fmt.Errorf("%s lists %q, which is outside of the bag", name, file))
+			continue
+		}
+
+		if is_payload && !strings.HasPrefix(file, payloadDirectory+"/") {
+			v.fail(fmt.Errorf("%s lists %q, which is outside of %s", name, file, payloadDirectory))
+			continue
+		}
+
+		if !is_payload && strings.HasPrefix(file, payloadDirectory+"/") {
+			v.fail(fmt.Errorf("%s lists %q, which is a payload file", name, file))
+			continue
+		}
+
+		if _, ok := payload[file]; is_payload && !ok {
+			v.fail(fmt.Errorf("%s lists %q, which isn't in the bag", name, file))
+			continue
+		}
+
+		if listed[file] {
+			v.fail(fmt.Errorf("%s lists %q twice", name, file))
+			continue
+		}
+		listed[file] = true
+
+		if err := v.checksum(file, new_hash(), sum); err != nil {
+			v.fail(fmt.Errorf("%s: %w", name, err))
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		v.fail(fmt.Errorf("%s: %w", name, err))
+	}
+
+	if !is_payload {
+		return
+	}
+
+	for _, file := range sortedKeys(payload) {
+		if !listed[file] {
+			v.fail(fmt.Errorf("%q isn't listed in %s", file, name))
+		}
+	}
+}
+
+func (v *validator) checksum(file string, h hash.Hash, want string) error {
+	f, err := os.Open(filepath.Join(v.directory, filepath.FromSlash(file)))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = io.Copy(h, f)
+	if err != nil {
+		return err
+	}
+
+	got := h.Sum(nil)
+	want_sum, err := hex.DecodeString(want)
+	if err != nil || !bytes.Equal(got, want_sum) {
+		return fmt.Errorf("%w, %q has a checksum of %x rather than %s", entities.ErrorHashMismatch, file, got, want)
+	}
+
+	return nil
+}
+
+// oxum checks the Payload-Oxum of bag-info.txt against payload, which is the amount of bytes and files it holds.
+func (v *validator) oxum(payload map[string]int64) {
+	contents, err := os.ReadFile(filepath.Join(v.directory, infoName))
+	if errors.Is(err, fs.ErrNotExist) {
+		return
+	}
+	if err != nil {
+		v.fail(err)
+		return
+	}
+
+	oxum, ok := lookup(parseInfo(contents), "Payload-Oxum")
+	if !ok {
+		return
+	}
+
+	var octets int64
+	for _, size := range payload {
+		octets += size
+	}
+
+	want_octets, want_count, _ := strings.Cut(oxum, ".")
+	o, err_octets := strconv.ParseInt(want_octets, 10, 64)
+	c, err_count := strconv.Atoi(want_count)
+	if err_octets != nil || err_count != nil {
+		v.fail(fmt.Errorf("invalid Payload-Oxum %q", oxum))
+		return
+	}
+
+	if o != octets || c != len(payload) {
+		v.fail(fmt.Errorf("Payload-Oxum is %s, but the payload is %d bytes in %d files", oxum, octets, len(payload)))
+	}
+}
+
+// parseInfo parses the "Label: value" lines of a tag file such as bag-info.txt, joining indented lines onto the value
+// before them.
+func parseInfo(contents []byte) (fields []InfoField) {
+	for _, line := range strings.Split(strings.ReplaceAll(string(contents), "\r\n", "\n"), "\n") {
+		if line == "" {
+			continue
+		}
+
+		if (line[0] == ' ' || line[0] == '\t') && len(fields) > 0 {
+			fields[len(fields)-1].Value += "\n" + strings.TrimSpace(line)
+			continue
+		}
+
+		label, value, _ := strings.Cut(line, ":")
+		fields = append(fields, InfoField{Label: strings.TrimSpace(label), Value: strings.TrimSpace(value)})
+	}
+
+	return fields
+}
+
+// lookup returns the value of the first field labelled label.
+func lookup(fields []InfoField, label string) (value string, ok bool) {
+	i := slices.IndexFunc(fields, func(f InfoField) bool { return f.Label == label })
+	if i < 0 {
+		return "", false
+	}
+
+	return fields[i].Value, true
+}
+
+func sortedKeys(m map[string]int64) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+
+	return keys
+}
@@ -0,0 +1,276 @@
+// Package bundle reads and writes portable bundles of part of an archive, for handing to another archivist. A bundle is
+// a tar file starting with a manifest.json describing its projects, youtube videos and files, followed by the contents
+// of every file at blobs/<sha256>.<extension>.
+package bundle
+
+import (
+	"archive/tar"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/dtbead/wc-maps-archive/internal/entities"
+	file_helper "github.com/dtbead/wc-maps-archive/internal/helper/file"
+)
+
+const (
+	// Version is the version of the manifests written by Writer. Newer manifests are refused by Reader.
+	Version = 1
+
+	manifestName  = "manifest.json"
+	blobDirectory = "blobs/"
+)
+
+// BlobName is where the contents of file are kept within a bundle.
+func BlobName(file File) string {
+	return blobDirectory + file.SHA256 + "." + file.Extension
+}
+
+// Writer writes a bundle. The manifest gets written up front, so that a Reader can verify every file as it comes
+// across it without buffering the bundle.
+type Writer struct {
+	tw      *tar.Writer
+	files   map[string]File
+	written map[string]bool
+}
+
+// NewWriter starts a bundle of manifest on w. Every file of manifest then has to be written with WriteFile before
+// closing the Writer.
+func NewWriter(w io.Writer, manifest Manifest) (*Writer, error) {
+	manifest.Version = Version
+	if manifest.Created.IsZero() {
+		manifest.Created = time.Now().UTC().Truncate(time.Second)
+	}
+
+	files, err := validate(manifest)
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := json.MarshalIndent(manifest, "", "\t")
+	if err != nil {
+		return nil, err
+	}
+
+	tw := tar.NewWriter(w)
+	err = tw.WriteHeader(&tar.Header{
+		Name:    manifestName,
+		Mode:    0644,
+		Size:    int64(len(b)),
+		ModTime: manifest.Created,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = tw.Write(b)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Writer{tw: tw, files: files, written: make(map[string]bool, len(files))}, nil
+}
+
+// WriteFile writes the contents of the file hashing to sha256, which has to be in the manifest.
+func (w *Writer) WriteFile(sha256 string, contents io.Reader) error {
+	file, ok := w.files[sha256]
+	if !ok {
+		return fmt.Errorf("file %q isn't in the manifest", sha256)
+	}
+
+	if w.written[sha256] {
+		return fmt.Errorf("file %q has already been written", sha256)
+	}
+
+	err := w.tw.WriteHeader(&tar.Header{
+		Name: BlobName(file),
+		Mode: 0644,
+		Size: file.Size,
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(w.tw, contents)
+	if err != nil {
+		return err
+	}
+
+	w.written[sha256] = true
+	return nil
+}
+
+// Close finishes the bundle, failing if any file of the manifest hasn't been written. It doesn't close the underlying
+// io.Writer.
+func (w *Writer) Close() error {
+	for sha256 := range w.files {
+		if !w.written[sha256] {
+			return fmt.Errorf("file %q hasn't been written", sha256)
+		}
+	}
+
+	return w.tw.Close()
+}
+
+// Reader reads a bundle, verifying the contents of every file against the hashes of the manifest as they're read.
+type Reader struct {
+	tr       *tar.Reader
+	manifest Manifest
+	files    map[string]File
+}
+
+// NewReader reads the manifest of the bundle in r. Errors caused by a malformed bundle wrap entities.ErrorInvalidBundle.
+func NewReader(r io.Reader) (*Reader, error) {
+	tr := tar.NewReader(r)
+	header, err := tr.Next()
+	if err != nil {
+		return nil, fmt.Errorf("%w, %w", entities.ErrorInvalidBundle, err)
+	}
+
+	if header.Name != manifestName {
+		return nil, fmt.Errorf("%w, expected %s first, got %q", entities.ErrorInvalidBundle, manifestName, header.Name)
+	}
+
+	var manifest Manifest
+	err = json.NewDecoder(tr).Decode(&manifest)
+	if err != nil {
+		return nil, fmt.Errorf("%w, %w", entities.ErrorInvalidBundle, err)
+	}
+
+	if manifest.Version < 1 || manifest.Version > Version {
+		return nil, fmt.Errorf("%w, unsupported version %d", entities.ErrorInvalidBundle, manifest.Version)
+	}
+
+	files, err := validate(manifest)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Reader{tr: tr, manifest: manifest, files: files}, nil
+}
+
+func (r *Reader) Manifest() Manifest {
+	return r.manifest
+}
+
+// Next returns the next file of the bundle, along with its contents, or io.EOF once there are no more. Reading the
+// contents to the end fails with entities.ErrorHashMismatch if they don't match the hashes of file. Contents which
+// aren't read to the end are skipped over by the following call to Next.
+func (r *Reader) Next() (file File, contents io.Reader, err error) {
+	header, err := r.tr.Next()
+	if errors.Is(err, io.EOF) {
+		return File{}, nil, io.EOF
+	}
+	if err != nil {
+		return File{}, nil, fmt.Errorf("%w, %w", entities.ErrorInvalidBundle, err)
+	}
+
+	name, _, _ := strings.Cut(strings.TrimPrefix(header.Name, blobDirectory), ".")
+	file, ok := r.files[name]
+	if !ok || header.Name != BlobName(file) {
+		return File{}, nil, fmt.Errorf("%w, %q isn't a file of the manifest", entities.ErrorInvalidBundle, header.Name)
+	}
+
+	if header.Size != file.Size {
+		return File{}, nil, fmt.Errorf("%w, file %q is %d bytes rather than %d", entities.ErrorHashMismatch, file.SHA256, header.Size, file.Size)
+	}
+
+	hashes, err := file.Hashes()
+	if err != nil {
+		return File{}, nil, err
+	}
+
+	return file, &verifier{r: r.tr, hash: file_helper.NewHashWriter(), file: file, hashes: hashes}, nil
+}
+
+// verifier hashes everything read through it, and fails with entities.ErrorHashMismatch instead of returning io.EOF if
+// it didn't match the hashes of file.
+type verifier struct {
+	r      io.Reader
+	hash   *file_helper.HashWriter
+	file   File
+	hashes entities.Hashes
+}
+
+func (v *verifier) Read(p []byte) (n int, err error) {
+	n, err = v.r.Read(p)
+	v.hash.Write(p[:n])
+
+	if errors.Is(err, io.EOF) {
+		got := v.hash.Hashes()
+		if !bytes.Equal(got.SHA256, v.hashes.SHA256) || !bytes.Equal(got.SHA1, v.hashes.SHA1) || !bytes.Equal(got.MD5, v.hashes.MD5) {
+			return n, fmt.Errorf("%w, file %q", entities.ErrorHashMismatch, v.file.SHA256)
+		}
+	}
+
+	return n, err
+}
+
+// validate checks that every file of manifest is described once, and that everything referring to a file refers to
+// one of them. It returns the files of manifest by their SHA256.
+func validate(manifest Manifest) (files map[string]File, err error) {
+	files = make(map[string]File, len(manifest.Files))
+	for _, f := range manifest.Files {
+		if _, err := f.Hashes(); err != nil {
+			return nil, err
+		}
+
+		if len(f.Extension) < 3 || len(f.Extension) > 6 || strings.ContainsAny(f.Extension, "./\\") {
+			return nil, fmt.Errorf("%w, file %q has an invalid extension %q", entities.ErrorInvalidBundle, f.SHA256, f.Extension)
+		}
+
+		if f.Size < 16 {
+			return nil, fmt.Errorf("%w, file %q has an invalid size %d", entities.ErrorInvalidBundle, f.SHA256, f.Size)
+		}
+
+		if _, ok := files[f.SHA256]; ok {
+			return nil, fmt.Errorf("%w, file %q is described twice", entities.ErrorInvalidBundle, f.SHA256)
+		}
+
+		files[f.SHA256] = f
+	}
+
+	refer := func(sha256, by string) error {
+		if _, ok := files[sha256]; !ok {
+			return fmt.Errorf("%w, %s refers to file %q, which isn't in the bundle", entities.ErrorInvalidBundle, by, sha256)
+		}
+
+		return nil
+	}
+
+	for _, y := range manifest.Youtube {
+		if len(y.Files) == 0 {
+			return nil, fmt.Errorf("%w, youtube video %q has no files", entities.ErrorInvalidBundle, y.ID)
+		}
+
+		for _, f := range y.Files {
+			if err := refer(f, "youtube video "+y.ID); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	for _, p := range manifest.Projects {
+		for _, f := range p.Files {
+			if err := refer(f, "project "+p.UUID); err != nil {
+				return nil, err
+			}
+		}
+
+		for _, part := range p.Parts {
+			if part.File == "" {
+				continue
+			}
+
+			if err := refer(part.File, "a part of project "+p.UUID); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return files, nil
+}
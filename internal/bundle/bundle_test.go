@@ -0,0 +1,234 @@
+package bundle_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/dtbead/wc-maps-archive/internal/bundle"
+	"github.com/dtbead/wc-maps-archive/internal/entities"
+	file_helper "github.com/dtbead/wc-maps-archive/internal/helper/file"
+	"github.com/google/go-cmp/cmp"
+)
+
+// newFile describes contents as a bundle.File.
+func newFile(t *testing.T, contents []byte, extension string) bundle.File {
+	t.Helper()
+
+	hashes, size, err := file_helper.GetHash(bytes.NewReader(contents))
+	if err != nil {
+		t.Fatalf("failed to hash contents, %v", err)
+	}
+
+	return bundle.NewFile(entities.File{Extension: extension, Size: size, Hashes: hashes}, nil)
+}
+
+func newManifest(t *testing.T, contents ...[]byte) bundle.Manifest {
+	t.Helper()
+
+	announced, err := entities.ParsePartialDate("~2015-06")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m := bundle.Manifest{Created: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)}
+	for _, c := range contents {
+		m.Files = append(m.Files, newFile(t, c, "mp4"))
+	}
+
+	m.Youtube = []bundle.Youtube{{
+		ID:         "dQw4w9WgXcQ",
+		Title:      "firestar MAP",
+		UploadDate: time.Date(2015, 6, 21, 0, 0, 0, 0, time.UTC),
+		Duration:   300,
+		Channel:    &bundle.Channel{ID: "UCabcdefghijklmnopqrstuA", Uploader: "leafstar"},
+		Files:      []string{m.Files[0].SHA256},
+	}}
+
+	m.Projects = []bundle.Project{{
+		UUID:          "0b5e5c3a-6b4b-4c0f-9d0e-4f1b2a3c4d5e",
+		Type:          "map",
+		DateAnnounced: announced,
+		Titles:        []bundle.Revision{{Text: "firestar MAP", DateAdded: time.Date(2015, 6, 1, 0, 0, 0, 0, time.UTC)}},
+		Files:         []string{m.Files[len(m.Files)-1].SHA256},
+		Parts:         []bundle.Part{{Number: 1, Status: "completed", ParticipantName: "leafstar", File: m.Files[0].SHA256}},
+	}}
+
+	return m
+}
+
+func writeBundle(t *testing.T, m bundle.Manifest, contents ...[]byte) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	w, err := bundle.NewWriter(&buf, m)
+	if err != nil {
+		t.Fatalf("NewWriter() error = %v", err)
+	}
+
+	for i, c := range contents {
+		err = w.WriteFile(m.Files[i].SHA256, bytes.NewReader(c))
+		if err != nil {
+			t.Fatalf("WriteFile() error = %v", err)
+		}
+	}
+
+	err = w.Close()
+	if err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	return buf.Bytes()
+}
+
+func TestBundle(t *testing.T) {
+	contents := [][]byte{
+		[]byte("the first video, at least sixteen bytes long"),
+		[]byte("the second video, at least sixteen bytes long"),
+	}
+	m := newManifest(t, contents...)
+	b := writeBundle(t, m, contents...)
+
+	r, err := bundle.NewReader(bytes.NewReader(b))
+	if err != nil {
+		t.Fatalf("NewReader() error = %v", err)
+	}
+
+	m.Version = bundle.Version
+	if diff := cmp.Diff(m, r.Manifest()); diff != "" {
+		t.Errorf("Manifest() diff = %s", diff)
+	}
+
+	for i := range contents {
+		file, c, err := r.Next()
+		if err != nil {
+			t.Fatalf("Next() error = %v", err)
+		}
+
+		got, err := io.ReadAll(c)
+		if err != nil {
+			t.Fatalf("reading file %d, error = %v", i, err)
+		}
+
+		if file.SHA256 != m.Files[i].SHA256 || !bytes.Equal(got, contents[i]) {
+			t.Errorf("Next() = %s %q, want %s %q", file.SHA256, got, m.Files[i].SHA256, contents[i])
+		}
+	}
+
+	_, _, err = r.Next()
+	if !errors.Is(err, io.EOF) {
+		t.Errorf("Next() error = %v, want io.EOF", err)
+	}
+}
+
+func TestBundle_HashMismatch(t *testing.T) {
+	contents := []byte("the first video, at least sixteen bytes long")
+	m := newManifest(t, contents)
+
+	// the same amount of bytes, so that only the hashes give it away
+	corrupted := bytes.Clone(contents)
+	corrupted[0] = 'T'
+	b := writeBundle(t, m, corrupted)
+
+	r, err := bundle.NewReader(bytes.NewReader(b))
+	if err != nil {
+		t.Fatalf("NewReader() error = %v", err)
+	}
+
+	_, c, err := r.Next()
+	if err != nil {
+		t.Fatalf("Next() error = %v", err)
+	}
+
+	_, err = io.ReadAll(c)
+	if !errors.Is(err, entities.ErrorHashMismatch) {
+		t.Errorf("reading corrupted file, error = %v, want %v", err, entities.ErrorHashMismatch)
+	}
+}
+
+func TestWriter_Close(t *testing.T) {
+	contents := []byte("the first video, at least sixteen bytes long")
+
+	w, err := bundle.NewWriter(io.Discard, newManifest(t, contents))
+	if err != nil {
+		t.Fatalf("NewWriter() error = %v", err)
+	}
+
+	if err := w.Close(); err == nil {
+		t.Errorf("Close() with a file left unwritten, error = nil")
+	}
+}
+
+func TestNewReader(t *testing.T) {
+	contents := []byte("the first video, at least sixteen bytes long")
+	valid := newManifest(t, contents)
+
+	unknown_file := newManifest(t, contents)
+	unknown_file.Projects[0].Files = []string{"deadbeef"}
+
+	duplicate := newManifest(t, contents)
+	duplicate.Files = append(duplicate.Files, duplicate.Files[0])
+
+	bad_extension := newManifest(t, contents)
+	bad_extension.Files[0].Extension = "../mp4"
+
+	tar_of := func(name string, body []byte) []byte {
+		var buf bytes.Buffer
+		tw := tar.NewWriter(&buf)
+		tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(body))})
+		tw.Write(body)
+		tw.Close()
+		return buf.Bytes()
+	}
+
+	tests := []struct {
+		name     string
+		manifest *bundle.Manifest
+		raw      []byte
+		wantErr  bool
+	}{
+		{"valid", &valid, nil, false},
+		{"unknown file", &unknown_file, nil, true},
+		{"duplicate file", &duplicate, nil, true},
+		{"bad extension", &bad_extension, nil, true},
+		{"no manifest", nil, tar_of("blobs/abc.mp4", contents), true},
+		{"not json", nil, tar_of("manifest.json", contents), true},
+		{"newer version", nil, tar_of("manifest.json", []byte(`{"version": 999}`)), true},
+		{"not a tar", nil, contents, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			raw := tt.raw
+			if tt.manifest != nil {
+				var buf bytes.Buffer
+				w, err := bundle.NewWriter(&buf, *tt.manifest)
+				if err != nil {
+					if !tt.wantErr {
+						t.Fatalf("NewWriter() error = %v", err)
+					}
+
+					// the writer refuses to write a manifest the reader would refuse
+					if !errors.Is(err, entities.ErrorInvalidBundle) {
+						t.Errorf("NewWriter() error = %v, want %v", err, entities.ErrorInvalidBundle)
+					}
+					return
+				}
+				w.WriteFile(tt.manifest.Files[0].SHA256, bytes.NewReader(contents))
+				w.Close()
+				raw = buf.Bytes()
+			}
+
+			_, err := bundle.NewReader(bytes.NewReader(raw))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewReader() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if err != nil && !errors.Is(err, entities.ErrorInvalidBundle) {
+				t.Errorf("NewReader() error = %v, want %v", err, entities.ErrorInvalidBundle)
+			}
+		})
+	}
+}
@@ -0,0 +1,343 @@
+package bundle
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/dtbead/wc-maps-archive/internal/entities"
+	file_helper "github.com/dtbead/wc-maps-archive/internal/helper/file"
+)
+
+// Manifest describes everything in a bundle. Files are referred to by the hex SHA256 of their contents, which is what
+// ties youtube videos and projects to them, as file ids differ from one archive to the next.
+type Manifest struct {
+	Version  int       `json:"version"`
+	Created  time.Time `json:"created"`
+	Files    []File    `json:"files"`
+	Youtube  []Youtube `json:"youtube"`
+	Projects []Project `json:"projects"`
+}
+
+type File struct {
+	SHA256    string `json:"sha256"`
+	SHA1      string `json:"sha1"`
+	MD5       string `json:"md5"`
+	Extension string `json:"extension"`
+	Size      int64  `json:"size"`
+	Video     *Video `json:"video,omitempty"`
+}
+
+type Video struct {
+	VideoCodec string `json:"video_codec,omitempty"`
+	AudioCodec string `json:"audio_codec,omitempty"`
+	Duration   int    `json:"duration"`
+	Width      int16  `json:"width"`
+	Height     int16  `json:"height"`
+	Fps        int16  `json:"fps"`
+}
+
+// Youtube is a youtube video along with the files downloaded of it, the first of which it was originally archived with.
+// DlpInfo is the info json yt-dlp printed when downloading the first file, and Thumbnail the jpeg thumbnail it wrote
+// along with it, if they're known.
+type Youtube struct {
+	ID           string          `json:"id"`
+	Title        string          `json:"title"`
+	Description  string          `json:"description"`
+	UploadDate   time.Time       `json:"upload_date"`
+	Duration     int             `json:"duration"`
+	ViewCount    int             `json:"view_count"`
+	LikeCount    int             `json:"like_count"`
+	DislikeCount int             `json:"dislike_count"`
+	IsLive       bool            `json:"is_live"`
+	IsRestricted bool            `json:"is_restricted"`
+	Video        Video           `json:"video"`
+	Channel      *Channel        `json:"channel,omitempty"`
+	Format       *Format         `json:"format,omitempty"`
+	DlpVersion   *DlpVersion     `json:"ytdlp_version,omitempty"`
+	DlpInfo      json.RawMessage `json:"ytdlp_info,omitempty"`
+	Thumbnail    []byte          `json:"thumbnail,omitempty"`
+	Files        []string        `json:"files"`
+}
+
+type Channel struct {
+	ID         string `json:"id"`
+	UploaderID string `json:"uploader_id"`
+	Uploader   string `json:"uploader"`
+}
+
+type Format struct {
+	Format   string `json:"format"`
+	FormatID string `json:"format_id"`
+}
+
+type DlpVersion struct {
+	Version        string `json:"version"`
+	ReleaseGitHead string `json:"release_git_head"`
+	Repository     string `json:"repository"`
+}
+
+// Project is a project along with its history. Titles, Descriptions and Timeline are oldest first, and Relations only
+// hold those the project is the Project side of.
+type Project struct {
+	UUID          string               `json:"uuid"`
+	Type          string               `json:"type"`
+	DateAnnounced entities.PartialDate `json:"date_announced"`
+	DateCompleted entities.PartialDate `json:"date_completed"`
+	Titles        []Revision           `json:"titles"`
+	Descriptions  []Revision           `json:"descriptions"`
+	Files         []string             `json:"files"`
+	Parts         []Part               `json:"parts"`
+	Timeline      []StatusChange       `json:"timeline"`
+	Relations     []Relation           `json:"relations"`
+}
+
+// Revision is a single entry in the history of a title or description.
+type Revision struct {
+	Text      string    `json:"text"`
+	DateAdded time.Time `json:"date_added"`
+}
+
+// Part is a single part of a MAP. File is the SHA256 of its standalone upload, if it's in the bundle.
+type Part struct {
+	Number          int    `json:"number"`
+	StartMs         int64  `json:"start_ms"`
+	EndMs           int64  `json:"end_ms"`
+	Status          string `json:"status"`
+	Participant     string `json:"participant,omitempty"`
+	ParticipantName string `json:"participant_name,omitempty"`
+	File            string `json:"file,omitempty"`
+	Youtube         string `json:"youtube,omitempty"`
+}
+
+type StatusChange struct {
+	Status string    `json:"status"`
+	Date   time.Time `json:"date"`
+	Note   string    `json:"note,omitempty"`
+}
+
+type Relation struct {
+	Related string `json:"related"`
+	Type    string `json:"type"`
+}
+
+// NewFile describes file, along with its video metadata if it has any.
+func NewFile(file entities.File, video *entities.Video) File {
+	f := File{
+		SHA256:    file_helper.ByteToHexString(file.Hashes.SHA256),
+		SHA1:      file_helper.ByteToHexString(file.Hashes.SHA1),
+		MD5:       file_helper.ByteToHexString(file.Hashes.MD5),
+		Extension: file.Extension,
+		Size:      file.Size,
+	}
+
+	if video != nil {
+		v := NewVideo(*video)
+		f.Video = &v
+	}
+
+	return f
+}
+
+// Hashes decodes the hashes of f.
+func (f File) Hashes() (hashes entities.Hashes, err error) {
+	for _, h := range []struct {
+		name   string
+		hex    string
+		length int
+		dst    *[]byte
+	}{
+		{"sha256", f.SHA256, 32, &hashes.SHA256},
+		{"sha1", f.SHA1, 20, &hashes.SHA1},
+		{"md5", f.MD5, 16, &hashes.MD5},
+	} {
+		*h.dst = file_helper.HexStringToByte(h.hex)
+		if len(*h.dst) != h.length {
+			return entities.Hashes{}, fmt.Errorf("%w, file %q has an invalid %s", entities.ErrorInvalidBundle, f.SHA256, h.name)
+		}
+	}
+
+	return hashes, nil
+}
+
+func NewVideo(video entities.Video) Video {
+	return Video{
+		VideoCodec: video.VideoCodec,
+		AudioCodec: video.AudioCodec,
+		Duration:   video.Duration,
+		Width:      video.Width,
+		Height:     video.Height,
+		Fps:        video.Fps,
+	}
+}
+
+func (v Video) Entity() entities.Video {
+	return entities.Video{
+		VideoCodec: v.VideoCodec,
+		AudioCodec: v.AudioCodec,
+		Duration:   v.Duration,
+		Width:      v.Width,
+		Height:     v.Height,
+		Fps:        v.Fps,
+	}
+}
+
+// NewYoutube describes youtube, which was downloaded as files.
+func NewYoutube(youtube entities.Youtube, files []string) Youtube {
+	y := Youtube{
+		ID:           string(youtube.YouTube.YoutubeID),
+		Title:        youtube.Title,
+		Description:  youtube.Description,
+		UploadDate:   youtube.YouTube.UploadDate,
+		Duration:     youtube.YouTube.Duration,
+		ViewCount:    youtube.YouTube.ViewCount,
+		LikeCount:    youtube.YouTube.LikeCount,
+		DislikeCount: youtube.YouTube.DislikeCount,
+		IsLive:       youtube.YouTube.IsLive,
+		IsRestricted: youtube.YouTube.IsRestricted,
+		Video:        NewVideo(youtube.YouTube.Video),
+		DlpInfo:      youtube.DlpInfo,
+		Thumbnail:    youtube.Thumbnail,
+		Files:        files,
+	}
+
+	if youtube.Channel != nil {
+		y.Channel = &Channel{
+			ID:         string(youtube.Channel.ChannelID),
+			UploaderID: youtube.Channel.UploaderID,
+			Uploader:   youtube.Channel.Uploader,
+		}
+	}
+
+	if youtube.Format != nil {
+		y.Format = &Format{Format: youtube.Format.Format, FormatID: youtube.Format.FormatID}
+	}
+
+	if youtube.DlpVersion != nil {
+		y.DlpVersion = &DlpVersion{
+			Version:        youtube.DlpVersion.Version,
+			ReleaseGitHead: youtube.DlpVersion.ReleaseGitHead,
+			Repository:     youtube.DlpVersion.Repository,
+		}
+	}
+
+	return y
+}
+
+// Entity returns y as it's stored, its format and yt-dlp version belonging to file_id.
+func (y Youtube) Entity(file_id entities.FileID) *entities.Youtube {
+	youtube_id := entities.YoutubeVideoID(y.ID)
+	yt := &entities.Youtube{
+		YouTube: entities.YoutubeVideo{
+			YoutubeID:    youtube_id,
+			Video:        y.Video.Entity(),
+			UploadDate:   y.UploadDate,
+			Duration:     y.Duration,
+			ViewCount:    y.ViewCount,
+			LikeCount:    y.LikeCount,
+			DislikeCount: y.DislikeCount,
+			IsLive:       y.IsLive,
+			IsRestricted: y.IsRestricted,
+		},
+		Title:       y.Title,
+		Description: y.Description,
+		DlpInfo:     y.DlpInfo,
+		Thumbnail:   y.Thumbnail,
+	}
+
+	if y.Channel != nil {
+		yt.Channel = &entities.VideoYoutubeChannel{
+			ChannelID:  entities.YoutubeChannelID(y.Channel.ID),
+			UploaderID: y.Channel.UploaderID,
+			Uploader:   y.Channel.Uploader,
+		}
+	}
+
+	if y.Format != nil {
+		yt.Format = &entities.VideoYoutubeFormat{
+			YoutubeID: youtube_id,
+			FileID:    file_id,
+			Format:    y.Format.Format,
+			FormatID:  y.Format.FormatID,
+		}
+	}
+
+	if y.DlpVersion != nil {
+		yt.DlpVersion = &entities.VideoYoutubeDlpVersion{
+			YoutubeID:      youtube_id,
+			FileID:         file_id,
+			Version:        y.DlpVersion.Version,
+			ReleaseGitHead: y.DlpVersion.ReleaseGitHead,
+			Repository:     y.DlpVersion.Repository,
+		}
+	}
+
+	return yt
+}
+
+// NewPart describes part, whose standalone upload is the file hashing to file, if any. Artists aren't bundled, so a
+// participant known only by their artist is named after them instead.
+func NewPart(part entities.ProjectPart, file string) Part {
+	participant_name := part.ParticipantName
+	if participant_name == "" {
+		participant_name = part.ParticipantArtist
+	}
+
+	return Part{
+		Number:          part.Number,
+		StartMs:         part.Start.Milliseconds(),
+		EndMs:           part.End.Milliseconds(),
+		Status:          part.Status.ToString(),
+		Participant:     string(part.Participant),
+		ParticipantName: participant_name,
+		File:            file,
+		Youtube:         string(part.YoutubeID),
+	}
+}
+
+// Entity returns p as a part of project_uuid, its standalone upload being file_id.
+func (p Part) Entity(project_uuid entities.ProjectUUID, file_id entities.FileID) (part entities.ProjectPart, err error) {
+	status, err := entities.NewPartStatus(p.Status)
+	if err != nil {
+		return entities.ProjectPart{}, fmt.Errorf("%w, part %d: %w", entities.ErrorInvalidBundle, p.Number, err)
+	}
+
+	return entities.ProjectPart{
+		ProjectUUID:     project_uuid,
+		Number:          p.Number,
+		Start:           time.Duration(p.StartMs) * time.Millisecond,
+		End:             time.Duration(p.EndMs) * time.Millisecond,
+		Status:          status,
+		Participant:     entities.YoutubeChannelID(p.Participant),
+		ParticipantName: p.ParticipantName,
+		FileID:          file_id,
+		YoutubeID:       entities.YoutubeVideoID(p.Youtube),
+	}, nil
+}
+
+func NewStatusChange(change entities.ProjectStatusChange) StatusChange {
+	return StatusChange{Status: change.Status.ToString(), Date: change.Date, Note: change.Note}
+}
+
+func (s StatusChange) Entity() (change entities.ProjectStatusChange, err error) {
+	status, err := entities.NewProjectStatus(s.Status)
+	if err != nil {
+		return entities.ProjectStatusChange{}, fmt.Errorf("%w, %w", entities.ErrorInvalidBundle, err)
+	}
+
+	return entities.ProjectStatusChange{Status: status, Date: s.Date, Note: s.Note}, nil
+}
+
+func NewRelation(relation entities.ProjectRelation) Relation {
+	return Relation{Related: string(relation.Related), Type: relation.Type.ToString()}
+}
+
+// Entity returns r as a relation of project_uuid.
+func (r Relation) Entity(project_uuid entities.ProjectUUID) (relation entities.ProjectRelation, err error) {
+	relation_type, err := entities.NewProjectRelationType(r.Type)
+	if err != nil {
+		return entities.ProjectRelation{}, fmt.Errorf("%w, %w", entities.ErrorInvalidBundle, err)
+	}
+
+	return entities.ProjectRelation{Project: project_uuid, Related: entities.ProjectUUID(r.Related), Type: relation_type}, nil
+}
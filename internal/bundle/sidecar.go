@@ -0,0 +1,134 @@
+package bundle
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/dtbead/wc-maps-archive/internal/entities"
+)
+
+// SidecarVersion is the version of the sidecars written by this archive. Newer sidecars are refused by ParseSidecar.
+const SidecarVersion = 1
+
+// Sidecar describes a single stored file, along with the youtube video it's a download of and every project it belongs
+// to, and is kept next to it within the storage root. Together they describe the archive well enough for its database
+// to be rebuilt out of the storage root alone. The DlpInfo and Thumbnail of Youtube are only kept in the sidecar of
+// its first file.
+type Sidecar struct {
+	Version  int       `json:"version"`
+	Written  time.Time `json:"written"`
+	File     File      `json:"file"`
+	Youtube  *Youtube  `json:"youtube,omitempty"`
+	Projects []Project `json:"projects,omitempty"`
+}
+
+// ParseSidecar parses and checks the sidecar in contents. Errors wrap entities.ErrorInvalidSidecar.
+func ParseSidecar(contents []byte) (sidecar Sidecar, err error) {
+	err = json.Unmarshal(contents, &sidecar)
+	if err != nil {
+		return Sidecar{}, fmt.Errorf("%w, %w", entities.ErrorInvalidSidecar, err)
+	}
+
+	if sidecar.Version < 1 || sidecar.Version > SidecarVersion {
+		return Sidecar{}, fmt.Errorf("%w, unsupported version %d", entities.ErrorInvalidSidecar, sidecar.Version)
+	}
+
+	if _, err := sidecar.File.Hashes(); err != nil {
+		return Sidecar{}, fmt.Errorf("%w, %w", entities.ErrorInvalidSidecar, err)
+	}
+
+	if sidecar.Youtube != nil && !slices.Contains(sidecar.Youtube.Files, sidecar.File.SHA256) {
+		return Sidecar{}, fmt.Errorf("%w, youtube video %q isn't a download of file %q", entities.ErrorInvalidSidecar, sidecar.Youtube.ID, sidecar.File.SHA256)
+	}
+
+	return sidecar, nil
+}
+
+// MergeSidecars merges sidecars back into a manifest of every file, youtube video and project they describe. A youtube
+// video or project is described by the sidecar of each of its files, so it's taken from the most recently written one,
+// along with every file any of them lists. References to files for which stored returns false are dropped.
+func MergeSidecars(sidecars []Sidecar, stored func(sha256 string) bool) (manifest Manifest) {
+	sidecars = slices.Clone(sidecars)
+	slices.SortStableFunc(sidecars, func(a, b Sidecar) int { return b.Written.Compare(a.Written) })
+
+	manifest = Manifest{Version: Version, Files: []File{}, Youtube: []Youtube{}, Projects: []Project{}}
+	if len(sidecars) > 0 {
+		manifest.Created = sidecars[0].Written
+	}
+
+	youtube := make(map[string]int)
+	projects := make(map[string]int)
+	files := make(map[string]bool)
+
+	// sidecars are newest first, so the first one coming across a youtube video or project describes it
+	for _, sidecar := range sidecars {
+		if !files[sidecar.File.SHA256] {
+			files[sidecar.File.SHA256] = true
+			manifest.Files = append(manifest.Files, sidecar.File)
+		}
+
+		if y := sidecar.Youtube; y != nil {
+			i, ok := youtube[y.ID]
+			if !ok {
+				i = len(manifest.Youtube)
+				youtube[y.ID] = i
+				manifest.Youtube = append(manifest.Youtube, *y)
+				manifest.Youtube[i].Files = nil
+			}
+
+			manifest.Youtube[i].Files = appendStored(manifest.Youtube[i].Files, y.Files, stored)
+		}
+
+		for _, p := range sidecar.Projects {
+			i, ok := projects[p.UUID]
+			if !ok {
+				i = len(manifest.Projects)
+				projects[p.UUID] = i
+				manifest.Projects = append(manifest.Projects, p)
+				manifest.Projects[i].Files = nil
+				manifest.Projects[i].Parts = slices.Clone(p.Parts)
+				for j, part := range manifest.Projects[i].Parts {
+					if part.File != "" && !stored(part.File) {
+						manifest.Projects[i].Parts[j].File = ""
+					}
+				}
+			}
+
+			manifest.Projects[i].Files = appendStored(manifest.Projects[i].Files, p.Files, stored)
+		}
+	}
+
+	manifest.Youtube = slices.DeleteFunc(manifest.Youtube, func(y Youtube) bool { return len(y.Files) == 0 })
+
+	// the info json and thumbnail belong to the first file of a video, so they're only kept if that's still the first one
+	for i, y := range manifest.Youtube {
+		manifest.Youtube[i].DlpInfo, manifest.Youtube[i].Thumbnail = nil, nil
+		for _, sidecar := range sidecars {
+			if sidecar.Youtube == nil || sidecar.Youtube.ID != y.ID || sidecar.File.SHA256 != y.Files[0] {
+				continue
+			}
+
+			if len(manifest.Youtube[i].DlpInfo) == 0 {
+				manifest.Youtube[i].DlpInfo = sidecar.Youtube.DlpInfo
+			}
+			if len(manifest.Youtube[i].Thumbnail) == 0 {
+				manifest.Youtube[i].Thumbnail = sidecar.Youtube.Thumbnail
+			}
+		}
+	}
+
+	return manifest
+}
+
+// appendStored appends every file of add to files which isn't in files yet, and for which stored returns true.
+func appendStored(files, add []string, stored func(sha256 string) bool) []string {
+	for _, f := range add {
+		if stored(f) && !slices.Contains(files, f) {
+			files = append(files, f)
+		}
+	}
+
+	return files
+}
@@ -0,0 +1,105 @@
+package bundle_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/dtbead/wc-maps-archive/internal/bundle"
+	"github.com/dtbead/wc-maps-archive/internal/entities"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseSidecar(t *testing.T) {
+	m := newManifest(t, []byte("first file"), []byte("second file"))
+	valid := bundle.Sidecar{Version: bundle.SidecarVersion, File: m.Files[0], Youtube: &m.Youtube[0], Projects: m.Projects}
+
+	tests := []struct {
+		name    string
+		modify  func(s *bundle.Sidecar)
+		wantErr error
+	}{
+		{"valid", func(s *bundle.Sidecar) {}, nil},
+		{"without youtube video", func(s *bundle.Sidecar) { s.Youtube = nil }, nil},
+		{"newer version", func(s *bundle.Sidecar) { s.Version = bundle.SidecarVersion + 1 }, entities.ErrorInvalidSidecar},
+		{"invalid hash", func(s *bundle.Sidecar) { s.File.SHA1 = "abc" }, entities.ErrorInvalidSidecar},
+		{"youtube video of another file", func(s *bundle.Sidecar) { s.File = m.Files[1] }, entities.ErrorInvalidSidecar},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := valid
+			tt.modify(&s)
+
+			contents, err := json.Marshal(s)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := bundle.ParseSidecar(contents)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ParseSidecar() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if err == nil && got.File != s.File {
+				t.Errorf("ParseSidecar() = %+v, want %+v", got.File, s.File)
+			}
+		})
+	}
+
+	_, err := bundle.ParseSidecar([]byte("not json"))
+	if !errors.Is(err, entities.ErrorInvalidSidecar) {
+		t.Errorf("ParseSidecar() of invalid json, error = %v, want %v", err, entities.ErrorInvalidSidecar)
+	}
+}
+
+func TestMergeSidecars(t *testing.T) {
+	m := newManifest(t, []byte("first file"), []byte("second file"), []byte("third file"))
+	first, second, third := m.Files[0], m.Files[1], m.Files[2]
+
+	older, newer := m.Youtube[0], m.Youtube[0]
+	older.Files = []string{first.SHA256, second.SHA256}
+	older.DlpInfo = json.RawMessage(`{"id":"dQw4w9WgXcQ"}`)
+	older.Thumbnail = []byte("thumbnail of the first file")
+	newer.Title = "firestar MAP (reupload)"
+	newer.Thumbnail = []byte("thumbnail of another file")
+	newer.Files = []string{first.SHA256, second.SHA256, third.SHA256}
+
+	project := m.Projects[0]
+	project.Files = []string{third.SHA256}
+	project.Parts = []bundle.Part{{Number: 1, File: first.SHA256}, {Number: 2, File: second.SHA256}}
+
+	sidecars := []bundle.Sidecar{
+		{Written: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), File: first, Youtube: &older, Projects: []bundle.Project{project}},
+		{Written: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), File: third, Youtube: &newer, Projects: []bundle.Project{project}},
+	}
+
+	stored := map[string]bool{first.SHA256: true, third.SHA256: true}
+	got := bundle.MergeSidecars(sidecars, func(sha256 string) bool { return stored[sha256] })
+
+	wantYoutube := newer
+	wantYoutube.Files = []string{first.SHA256, third.SHA256}
+	wantYoutube.DlpInfo = older.DlpInfo
+	wantYoutube.Thumbnail = older.Thumbnail
+
+	wantProject := project
+	wantProject.Parts = []bundle.Part{{Number: 1, File: first.SHA256}, {Number: 2}}
+
+	want := bundle.Manifest{
+		Version:  bundle.Version,
+		Created:  sidecars[1].Written,
+		Files:    []bundle.File{third, first},
+		Youtube:  []bundle.Youtube{wantYoutube},
+		Projects: []bundle.Project{wantProject},
+	}
+
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("MergeSidecars() mismatch (-want +got):\n%s", diff)
+	}
+
+	// a youtube video left without any stored file is dropped
+	got = bundle.MergeSidecars(sidecars[:1], func(sha256 string) bool { return sha256 == third.SHA256 })
+	if len(got.Youtube) != 0 {
+		t.Errorf("MergeSidecars() youtube = %+v, want none", got.Youtube)
+	}
+}
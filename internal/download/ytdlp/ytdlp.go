@@ -5,8 +5,10 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"io/fs"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"regexp"
 	"strings"
 	"time"
@@ -15,9 +17,23 @@ import (
 )
 
 var regexpYoutubeURL *regexp.Regexp
+var regexpFilenameID *regexp.Regexp
 
 func init() {
 	regexpYoutubeURL = regexp.MustCompile(`youtube\.com\/watch\?v=([0-9A-Za-z_-]{10}[048AEIMQUYcgkosw])`)
+	regexpFilenameID = regexp.MustCompile(`\[([0-9A-Za-z_-]{10}[048AEIMQUYcgkosw])\]`)
+}
+
+// ParseFilenameID returns the youtube video id of a file named after yt-dlp's default "%(title)s [%(id)s].%(ext)s"
+// output template. ok is false if filename doesn't contain a "[id]".
+func ParseFilenameID(filename string) (youtube_id entities.YoutubeVideoID, ok bool) {
+	m := regexpFilenameID.FindAllStringSubmatch(filename, -1)
+	if len(m) < 1 {
+		return entities.UnknownYoutubeID, false
+	}
+
+	// titles may contain brackets of their own, but yt-dlp always puts the id last
+	return entities.YoutubeVideoID(m[len(m)-1][1]), true
 }
 
 func isValidURL(url string) bool {
@@ -55,7 +71,9 @@ func (y Ytdlp) Download(ctx context.Context, url string, output io.Writer) (yout
 		"--no-part",
 		"--no-write-comments",
 		"--no-cache-dir",
-		"--no-write-thumbnail",
+		"--write-thumbnail",
+		"--convert-thumbnails",
+		"jpg",
 		"--no-embed-metadata",
 		"--no-embed-info-json",
 		//"-S",
@@ -97,6 +115,17 @@ func (y Ytdlp) Download(ctx context.Context, url string, output io.Writer) (yout
 	}
 
 	yt := m.ToYoutubeEntity()
+	yt.DlpInfo = []byte(json)
+
+	// the thumbnail is written next to the video, named after it. not every video has one, so it's kept when found
+	thumbnail_output := strings.TrimSuffix(file_output, filepath.Ext(file_output)) + ".jpg"
+	defer os.Remove(thumbnail_output)
+
+	yt.Thumbnail, err = os.ReadFile(thumbnail_output)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, "", err
+	}
+
 	ext := m.Extension
 
 	stdout.Reset()
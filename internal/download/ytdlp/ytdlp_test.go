@@ -0,0 +1,35 @@
+package ytdlp_test
+
+import (
+	"testing"
+
+	"github.com/dtbead/wc-maps-archive/internal/download/ytdlp"
+	"github.com/dtbead/wc-maps-archive/internal/entities"
+)
+
+func TestParseFilenameID(t *testing.T) {
+	tests := []struct {
+		name     string
+		filename string
+		want     entities.YoutubeVideoID
+		wantOk   bool
+	}{
+		{"default template", "firestar MAP [dQw4w9WgXcQ].mkv", "dQw4w9WgXcQ", true},
+		{"brackets in title", "firestar MAP [OPEN] [dQw4w9WgXcQ].webm", "dQw4w9WgXcQ", true},
+		{"id-like title", "[y_wo8pyoxyk] reupload [dQw4w9WgXcQ].mp4", "dQw4w9WgXcQ", true},
+		{"id with dash and underscore", "part 03 [a-b_c-d_e-A].mp4", "a-b_c-d_e-A", true},
+		{"no id", "firestar MAP.mkv", entities.UnknownYoutubeID, false},
+		{"id without brackets", "firestar MAP dQw4w9WgXcQ.mkv", entities.UnknownYoutubeID, false},
+		{"id too short", "firestar MAP [dQw4w9WgXc].mkv", entities.UnknownYoutubeID, false},
+		{"invalid last character", "firestar MAP [dQw4w9WgXcR].mkv", entities.UnknownYoutubeID, false},
+		{"empty", "", entities.UnknownYoutubeID, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := ytdlp.ParseFilenameID(tt.filename)
+			if got != tt.want || ok != tt.wantOk {
+				t.Errorf("ParseFilenameID(%q) = %q, %v, want %q, %v", tt.filename, got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
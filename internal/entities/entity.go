@@ -2,8 +2,11 @@ package entities
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
+	"math"
 	"regexp"
 	"time"
 )
@@ -54,6 +57,12 @@ func (p ProjectType) ToString() string {
 	}
 }
 
+// MarshalText marshals p as its name rather than a number. The same goes for the other enums ending up in JSON, such
+// as the before and after of an AuditEntry.
+func (p ProjectType) MarshalText() ([]byte, error) {
+	return []byte(p.ToString()), nil
+}
+
 func NewProjectType(s string) (ProjectType, error) {
 	switch s {
 	case "other":
@@ -77,6 +86,517 @@ func NewProjectType(s string) (ProjectType, error) {
 	}
 }
 
+type PartStatus int
+
+const (
+	PartStatusUnknown PartStatus = iota
+	PartStatusOpen
+	PartStatusClaimed
+	PartStatusCompleted
+	PartStatusDropped
+)
+
+func (p PartStatus) ToString() string {
+	switch p {
+	case PartStatusOpen:
+		return "open"
+	case PartStatusClaimed:
+		return "claimed"
+	case PartStatusCompleted:
+		return "completed"
+	case PartStatusDropped:
+		return "dropped"
+	default:
+		return "unknown"
+	}
+}
+
+func (p PartStatus) MarshalText() ([]byte, error) {
+	return []byte(p.ToString()), nil
+}
+
+func NewPartStatus(s string) (PartStatus, error) {
+	switch s {
+	case "unknown":
+		return PartStatusUnknown, nil
+	case "open":
+		return PartStatusOpen, nil
+	case "claimed":
+		return PartStatusClaimed, nil
+	case "completed":
+		return PartStatusCompleted, nil
+	case "dropped":
+		return PartStatusDropped, nil
+	default:
+		return PartStatusUnknown, errors.New("unknown part status")
+	}
+}
+
+// CharacterNameStage is the stage of life a character went by a name in, following the naming ceremonies: Firepaw as an
+// apprentice, Fireheart as a warrior and Firestar as leader. Any other name, such as the kittypet name Rusty, is
+// NameStageOther.
+type CharacterNameStage int
+
+const (
+	NameStageOther CharacterNameStage = iota
+	NameStageKit
+	NameStageApprentice
+	NameStageWarrior
+	NameStageLeader
+)
+
+func (c CharacterNameStage) ToString() string {
+	switch c {
+	case NameStageKit:
+		return "kit"
+	case NameStageApprentice:
+		return "apprentice"
+	case NameStageWarrior:
+		return "warrior"
+	case NameStageLeader:
+		return "leader"
+	default:
+		return "other"
+	}
+}
+
+func NewCharacterNameStage(s string) (CharacterNameStage, error) {
+	switch s {
+	case "other":
+		return NameStageOther, nil
+	case "kit":
+		return NameStageKit, nil
+	case "apprentice":
+		return NameStageApprentice, nil
+	case "warrior":
+		return NameStageWarrior, nil
+	case "leader":
+		return NameStageLeader, nil
+	default:
+		return NameStageOther, errors.New("unknown character name stage")
+	}
+}
+
+// SearchKind is what a SearchResult points to, and what its ID is: a YoutubeVideoID, a YoutubeChannelID, a
+// ProjectUUID, or the id of an artist, song or character.
+type SearchKind int
+
+const (
+	SearchKindAny SearchKind = iota
+	SearchKindYoutube
+	SearchKindChannel
+	SearchKindProject
+	SearchKindArtist
+	SearchKindMusic
+	SearchKindCharacter
+)
+
+func (s SearchKind) ToString() string {
+	switch s {
+	case SearchKindYoutube:
+		return "youtube"
+	case SearchKindChannel:
+		return "channel"
+	case SearchKindProject:
+		return "project"
+	case SearchKindArtist:
+		return "artist"
+	case SearchKindMusic:
+		return "music"
+	case SearchKindCharacter:
+		return "character"
+	default:
+		return ""
+	}
+}
+
+func NewSearchKind(s string) (SearchKind, error) {
+	switch s {
+	case "":
+		return SearchKindAny, nil
+	case "youtube":
+		return SearchKindYoutube, nil
+	case "channel":
+		return SearchKindChannel, nil
+	case "project":
+		return SearchKindProject, nil
+	case "artist":
+		return SearchKindArtist, nil
+	case "music":
+		return SearchKindMusic, nil
+	case "character":
+		return SearchKindCharacter, nil
+	default:
+		return SearchKindAny, errors.New("unknown search kind")
+	}
+}
+
+// AuditEntity is what an AuditEntry is about, and what its EntityID is: a ProjectUUID, a FileID or a YoutubeVideoID.
+type AuditEntity int
+
+const (
+	AuditEntityUnknown AuditEntity = iota
+	AuditEntityProject
+	AuditEntityFile
+	AuditEntityYoutube
+)
+
+func (a AuditEntity) ToString() string {
+	switch a {
+	case AuditEntityProject:
+		return "project"
+	case AuditEntityFile:
+		return "file"
+	case AuditEntityYoutube:
+		return "youtube"
+	default:
+		return "unknown"
+	}
+}
+
+func NewAuditEntity(s string) (AuditEntity, error) {
+	switch s {
+	case "project":
+		return AuditEntityProject, nil
+	case "file":
+		return AuditEntityFile, nil
+	case "youtube":
+		return AuditEntityYoutube, nil
+	default:
+		return AuditEntityUnknown, errors.New("unknown audit entity")
+	}
+}
+
+// ListSort is what a listing is ordered by. ListSortDefault is whatever suits the listing best, such as newest upload
+// first for youtube videos. Not every listing supports every ListSort.
+type ListSort int
+
+const (
+	ListSortDefault ListSort = iota
+	ListSortUploadDate
+	ListSortArchivedDate
+	ListSortDuration
+	ListSortViews
+	ListSortAnnouncedDate
+	ListSortCompletedDate
+)
+
+func (l ListSort) ToString() string {
+	switch l {
+	case ListSortUploadDate:
+		return "uploaded"
+	case ListSortArchivedDate:
+		return "archived"
+	case ListSortDuration:
+		return "duration"
+	case ListSortViews:
+		return "views"
+	case ListSortAnnouncedDate:
+		return "announced"
+	case ListSortCompletedDate:
+		return "completed"
+	default:
+		return ""
+	}
+}
+
+func NewListSort(s string) (ListSort, error) {
+	switch s {
+	case "":
+		return ListSortDefault, nil
+	case "uploaded":
+		return ListSortUploadDate, nil
+	case "archived":
+		return ListSortArchivedDate, nil
+	case "duration":
+		return ListSortDuration, nil
+	case "views":
+		return ListSortViews, nil
+	case "announced":
+		return ListSortAnnouncedDate, nil
+	case "completed":
+		return ListSortCompletedDate, nil
+	default:
+		return ListSortDefault, errors.New("unknown sort")
+	}
+}
+
+// DefaultListLimit is how many entries a page of a listing holds when ListOptions doesn't say.
+const DefaultListLimit = 50
+
+// MaxListLimit is the most entries a single page of a listing may hold.
+const MaxListLimit = 500
+
+// ListOptions picks a page of a listing. Listings are in descending order unless Ascending, such as newest or longest
+// first. Cursor is where the previous page left off, empty for the first page, and only valid with the same Sort and
+// Ascending it was given out for.
+type ListOptions struct {
+	Sort      ListSort
+	Ascending bool
+	Cursor    string
+	Limit     int
+}
+
+// Normalize fills in the default Limit of l, and caps it at MaxListLimit.
+func (l *ListOptions) Normalize() error {
+	switch {
+	case l.Limit < 0:
+		return errors.New("negative list limit")
+	case l.Limit == 0:
+		l.Limit = DefaultListLimit
+	case l.Limit > MaxListLimit:
+		l.Limit = MaxListLimit
+	}
+
+	return nil
+}
+
+type ProjectRelationType int
+
+const (
+	ProjectRelationUnknown ProjectRelationType = iota
+	ProjectRelationPartOf
+	ProjectRelationBackupOf
+	ProjectRelationSequelOf
+	ProjectRelationReuploadOf
+)
+
+func (p ProjectRelationType) ToString() string {
+	switch p {
+	case ProjectRelationPartOf:
+		return "part-of"
+	case ProjectRelationBackupOf:
+		return "backup-of"
+	case ProjectRelationSequelOf:
+		return "sequel-of"
+	case ProjectRelationReuploadOf:
+		return "reupload-of"
+	default:
+		return "unknown"
+	}
+}
+
+func (p ProjectRelationType) MarshalText() ([]byte, error) {
+	return []byte(p.ToString()), nil
+}
+
+func NewProjectRelationType(s string) (ProjectRelationType, error) {
+	switch s {
+	case "part-of":
+		return ProjectRelationPartOf, nil
+	case "backup-of":
+		return ProjectRelationBackupOf, nil
+	case "sequel-of":
+		return ProjectRelationSequelOf, nil
+	case "reupload-of":
+		return ProjectRelationReuploadOf, nil
+	default:
+		return ProjectRelationUnknown, errors.New("unknown project relation type")
+	}
+}
+
+// ProjectStatus is where a project is in its life. A cancelled project may be revived, after which it goes through the
+// same statuses again. ProjectStatusUnknown is the status of a project without any recorded.
+type ProjectStatus int
+
+const (
+	ProjectStatusUnknown ProjectStatus = iota
+	ProjectStatusAnnounced
+	ProjectStatusSignupsOpen
+	ProjectStatusPartsAssigned
+	ProjectStatusInProgress
+	ProjectStatusCompleted
+	ProjectStatusCancelled
+	ProjectStatusRevived
+)
+
+func (p ProjectStatus) ToString() string {
+	switch p {
+	case ProjectStatusAnnounced:
+		return "announced"
+	case ProjectStatusSignupsOpen:
+		return "sign-ups open"
+	case ProjectStatusPartsAssigned:
+		return "parts assigned"
+	case ProjectStatusInProgress:
+		return "in progress"
+	case ProjectStatusCompleted:
+		return "completed"
+	case ProjectStatusCancelled:
+		return "cancelled"
+	case ProjectStatusRevived:
+		return "revived"
+	default:
+		return "unknown"
+	}
+}
+
+func (p ProjectStatus) MarshalText() ([]byte, error) {
+	return []byte(p.ToString()), nil
+}
+
+func NewProjectStatus(s string) (ProjectStatus, error) {
+	switch s {
+	case "announced":
+		return ProjectStatusAnnounced, nil
+	case "sign-ups open":
+		return ProjectStatusSignupsOpen, nil
+	case "parts assigned":
+		return ProjectStatusPartsAssigned, nil
+	case "in progress":
+		return ProjectStatusInProgress, nil
+	case "completed":
+		return ProjectStatusCompleted, nil
+	case "cancelled":
+		return ProjectStatusCancelled, nil
+	case "revived":
+		return ProjectStatusRevived, nil
+	default:
+		return ProjectStatusUnknown, errors.New("unknown project status")
+	}
+}
+
+// DatePrecision is how much of a PartialDate is known. DatePrecisionNone is that of an unknown date.
+type DatePrecision int
+
+const (
+	DatePrecisionNone DatePrecision = iota
+	DatePrecisionYear
+	DatePrecisionMonth
+	DatePrecisionDay
+)
+
+func (d DatePrecision) ToString() string {
+	switch d {
+	case DatePrecisionYear:
+		return "year"
+	case DatePrecisionMonth:
+		return "month"
+	case DatePrecisionDay:
+		return "day"
+	default:
+		return ""
+	}
+}
+
+func NewDatePrecision(s string) (DatePrecision, error) {
+	switch s {
+	case "":
+		return DatePrecisionNone, nil
+	case "year":
+		return DatePrecisionYear, nil
+	case "month":
+		return DatePrecisionMonth, nil
+	case "day":
+		return DatePrecisionDay, nil
+	default:
+		return DatePrecisionNone, errors.New("unknown date precision")
+	}
+}
+
+// PartialDate is a date known only to a year, month or day, such as a MAP announced sometime in 2015. It stands for the
+// whole period it names, which starts at Time, always in UTC and truncated to Precision. Approximate marks a best guess,
+// such as "summer 2013" becoming ~2013-07. The zero PartialDate is an unknown date.
+type PartialDate struct {
+	Time        time.Time
+	Precision   DatePrecision
+	Approximate bool
+}
+
+// NewPartialDate returns the period of t at precision, which is unknown for DatePrecisionNone.
+func NewPartialDate(t time.Time, precision DatePrecision, approximate bool) PartialDate {
+	t = t.UTC()
+	switch precision {
+	case DatePrecisionYear:
+		t = time.Date(t.Year(), 1, 1, 0, 0, 0, 0, time.UTC)
+	case DatePrecisionMonth:
+		t = time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	case DatePrecisionDay:
+		t = time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	default:
+		return PartialDate{}
+	}
+
+	return PartialDate{Time: t, Precision: precision, Approximate: approximate}
+}
+
+// ParsePartialDate parses a year, month or day such as 2015, 2015-06 or 2015-06-21, prefixed with a ~ if approximate.
+// An empty s parses as the unknown date.
+func ParsePartialDate(s string) (PartialDate, error) {
+	if s == "" {
+		return PartialDate{}, nil
+	}
+
+	approximate := false
+	if s[0] == '~' {
+		approximate, s = true, s[1:]
+	}
+
+	for _, p := range []struct {
+		layout    string
+		precision DatePrecision
+	}{
+		{"2006", DatePrecisionYear},
+		{"2006-01", DatePrecisionMonth},
+		{"2006-01-02", DatePrecisionDay},
+	} {
+		t, err := time.Parse(p.layout, s)
+		if err == nil {
+			return NewPartialDate(t, p.precision, approximate), nil
+		}
+	}
+
+	return PartialDate{}, fmt.Errorf("%w %q, expected a year, month or day such as 2015, 2015-06 or 2015-06-21", ErrorInvalidDate, s)
+}
+
+func (p PartialDate) IsZero() bool {
+	return p.Precision == DatePrecisionNone
+}
+
+// End is when the period of p is over, the start of the year, month or day following it.
+func (p PartialDate) End() time.Time {
+	switch p.Precision {
+	case DatePrecisionYear:
+		return p.Time.AddDate(1, 0, 0)
+	case DatePrecisionMonth:
+		return p.Time.AddDate(0, 1, 0)
+	case DatePrecisionDay:
+		return p.Time.AddDate(0, 0, 1)
+	default:
+		return time.Time{}
+	}
+}
+
+func (p PartialDate) MarshalText() ([]byte, error) {
+	return []byte(p.String()), nil
+}
+
+func (p *PartialDate) UnmarshalText(b []byte) (err error) {
+	*p, err = ParsePartialDate(string(b))
+	return err
+}
+
+// String formats p as ParsePartialDate parses it, giving no more precision than is known.
+func (p PartialDate) String() string {
+	var s string
+	switch p.Precision {
+	case DatePrecisionYear:
+		s = p.Time.Format("2006")
+	case DatePrecisionMonth:
+		s = p.Time.Format("2006-01")
+	case DatePrecisionDay:
+		s = p.Time.Format("2006-01-02")
+	default:
+		return ""
+	}
+
+	if p.Approximate {
+		return "~" + s
+	}
+
+	return s
+}
+
 func (f FileID) IsValid() bool {
 	return f > 0
 }
@@ -97,11 +617,107 @@ type Video struct {
 	Width, Height, Fps     int16
 }
 
+// MediaProbe is what a prober such as ffprobe found inside a media file, as opposed to what its source claimed.
+type MediaProbe struct {
+	Container string
+	// Duration is in seconds.
+	Duration float64
+	// Bitrate is the overall bitrate in bits per second, or 0 if unknown.
+	Bitrate int64
+	Streams []MediaStream
+}
+
+type MediaStream struct {
+	Index int
+	// CodecType is one of "video", "audio", "subtitle", "data" or "attachment".
+	CodecType string
+	Codec     string
+	// Bitrate is in bits per second, or 0 if unknown. Many containers such as webm and mkv don't store one per stream.
+	Bitrate       int64
+	Width, Height int16
+	Fps           float64
+	SampleRate    int
+	Channels      int16
+}
+
+// FirstStream returns the first stream of codec_type, or nil if there's none.
+func (m MediaProbe) FirstStream(codec_type string) *MediaStream {
+	for i := range m.Streams {
+		if m.Streams[i].CodecType == codec_type {
+			return &m.Streams[i]
+		}
+	}
+
+	return nil
+}
+
+// Video summarizes m by its first video and audio stream. It returns an error if m has no video stream.
+func (m MediaProbe) Video() (Video, error) {
+	v := m.FirstStream("video")
+	if v == nil {
+		return Video{}, errors.New("no video stream found")
+	}
+
+	if m.Duration < 0 {
+		return Video{}, ErrorInvalidDuration
+	}
+
+	video := Video{
+		VideoCodec: v.Codec,
+		Width:      v.Width,
+		Height:     v.Height,
+		Fps:        int16(math.Round(v.Fps)),
+		Duration:   int(math.Round(m.Duration)),
+	}
+
+	if a := m.FirstStream("audio"); a != nil {
+		video.AudioCodec = a.Codec
+	}
+
+	return video, nil
+}
+
+// YoutubeDownload is yt-dlp metadata, such as a format or an info json, recording FileID as a download of YoutubeID.
+// Source is the table it's kept in, and AssignedYoutubeID the youtube video FileID is assigned to, if any.
+type YoutubeDownload struct {
+	FileID            FileID
+	YoutubeID         YoutubeVideoID
+	Source            string
+	AssignedYoutubeID YoutubeVideoID
+}
+
+// ProbeMismatch is a property of a file where the probed Actual value disagrees with the Expected value its source
+// claimed, such as yt-dlp reporting an audio codec for a merge that silently dropped the audio track.
+type ProbeMismatch struct {
+	FileID                  FileID
+	Field, Expected, Actual string
+}
+
+// Fingerprint is a perceptual fingerprint of a video, surviving re-encodes and rescales that change every byte of it.
+type Fingerprint struct {
+	// Interval is the time between sampled frames, in seconds.
+	Interval float64
+	// Hashes holds a 64-bit perceptual hash per sampled frame, in order. Frames without any detail, such as a black
+	// screen, are left out as they'd match practically any video.
+	Hashes []uint64
+}
+
+type FingerprintMatch struct {
+	FileID FileID
+	// Score ranges from 0 for unrelated videos to 1 for videos that look identical.
+	Score float64
+}
+
+// Youtube is everything known about a youtube video when it's downloaded. DlpInfo is the info json yt-dlp printed for
+// the download, kept as is, and is left empty if it didn't come from yt-dlp. Thumbnail is the thumbnail yt-dlp wrote
+// alongside, converted to a jpeg, and is left empty if there wasn't one.
 type Youtube struct {
 	YouTube            YoutubeVideo
 	Channel            *VideoYoutubeChannel
 	Format             *VideoYoutubeFormat
 	DlpVersion         *VideoYoutubeDlpVersion
+	DlpInfo            json.RawMessage
+	Thumbnail          []byte
 	Title, Description string
 }
 
@@ -136,6 +752,7 @@ type VideoYoutubeDlpVersion struct {
 type ProjectTitle struct {
 	ProjectUUID ProjectUUID
 	Title       string
+	DateAdded   time.Time
 	project_id  int32
 	title_md5   []byte
 }
@@ -143,6 +760,7 @@ type ProjectTitle struct {
 type ProjectDescription struct {
 	ProjectUUID     ProjectUUID
 	Description     string
+	DateAdded       time.Time
 	description_md5 []byte
 	project_id      int32
 }
@@ -150,17 +768,58 @@ type ProjectDescription struct {
 type Project struct {
 	UUID          string
 	project_id    int32
+	Title         string
+	Description   string
 	FileIDs       []FileID
 	ProjectType   ProjectType
-	DateAnnounced time.Time
-	DateCompleted time.Time
+	DateAnnounced PartialDate
+	DateCompleted PartialDate
 	DateArchived  time.Time
 }
 
+// ProjectStatusChange is a single entry of a project's timeline, Status being what the project changed to on Date.
+// DateAdded is when the change was recorded.
+type ProjectStatusChange struct {
+	ID        int64
+	Status    ProjectStatus
+	Date      time.Time
+	Note      string
+	DateAdded time.Time
+}
+
+// ProjectPart is a single numbered part of a MAP. Start and End are where it sits in the finished video, with End being
+// 0 if the timing isn't known. Participant is the youtube channel of whoever animated it, ParticipantArtist the name of
+// their artist if they're a known one, and ParticipantName their name if they're neither. FileID and YoutubeID point to
+// the part's standalone upload, and are left zero if there's none.
+type ProjectPart struct {
+	ID                int64
+	ProjectUUID       ProjectUUID
+	Number            int
+	Start, End        time.Duration
+	Status            PartStatus
+	Participant       YoutubeChannelID
+	ParticipantArtist string
+	ParticipantName   string
+	FileID            FileID
+	YoutubeID         YoutubeVideoID
+}
+
+// ProjectRelation reads as "Project is a Type of Related", such as a part project being part-of its MAP. Depth is how
+// many relations away Related is from wherever a tree walk started, and 1 otherwise.
+type ProjectRelation struct {
+	Project, Related ProjectUUID
+	Type             ProjectRelationType
+	Depth            int
+}
+
+// ProjectImport is a project about to be created. UUID keeps the uuid a project already has elsewhere, such as in
+// another archive, and is generated when left empty.
 type ProjectImport struct {
-	ProjectType                                ProjectType
-	DateAnnounced, DateCompleted, DateArchived time.Time
-	FileIDs                                    []FileID
+	UUID                         ProjectUUID
+	ProjectType                  ProjectType
+	DateAnnounced, DateCompleted PartialDate
+	DateArchived                 time.Time
+	FileIDs                      []FileID
 }
 
 type ProjectYoutube struct {
@@ -179,10 +838,78 @@ type Music struct {
 	Artist, Title string
 }
 
+type MusicMatch struct {
+	Music Music
+	// Score ranges from 0 for no resemblance to 1 for a song matching everything that was looked up.
+	Score float64
+}
+
+// Artist is a person behind one or more youtube channels. Aliases holds every other name they've gone by.
+type Artist struct {
+	ID        int64
+	Name      string
+	Aliases   []string
+	Channels  []YoutubeChannelID
+	DateAdded time.Time
+}
+
+// Character is known by Name, and can be looked up by any of its Aliases as well. Series is the universe it comes from,
+// and IsOriginal marks fan-made characters.
 type Character struct {
 	ID           int
 	Name, Series string
 	IsOriginal   bool
+	Aliases      []CharacterAlias
+}
+
+type CharacterAlias struct {
+	Name  string
+	Stage CharacterNameStage
+}
+
+// SearchResult is a single match of a full-text search. Title is the current title or name of what matched, and
+// Headline the matching text escaped as HTML, with every matched word wrapped in <b> and </b>. Rank is only meaningful
+// compared to the other results of the same search, higher being a better match.
+type SearchResult struct {
+	Kind     SearchKind
+	ID       string
+	Title    string
+	Headline string
+	Rank     float64
+}
+
+// AuditEntry records a single change made through the service layer, Action being what Actor did to the entity, such
+// as "set title". Before and After are JSON of whatever the change touched, and null when there was nothing before or
+// is nothing after, such as when a project is made or deleted.
+type AuditEntry struct {
+	ID        int64
+	Actor     string
+	Entity    AuditEntity
+	EntityID  string
+	Action    string
+	Before    json.RawMessage
+	After     json.RawMessage
+	DateAdded time.Time
+}
+
+// UnknownActor is who makes changes through a context.Context not carrying an actor.
+const UnknownActor = "unknown"
+
+type actorKey struct{}
+
+// WithActor returns a copy of ctx carrying actor, whoever is making changes through it, such as a curator's name.
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFrom returns the actor ctx carries, or UnknownActor if it carries none.
+func ActorFrom(ctx context.Context) string {
+	actor, ok := ctx.Value(actorKey{}).(string)
+	if !ok || actor == "" {
+		return UnknownActor
+	}
+
+	return actor
 }
 
 type Hashes struct {
@@ -217,12 +944,37 @@ var (
 	ErrorInvalidVideoPtr         = errors.New("nil video pointer")
 	ErrorInvalidYoutubeVideoPtr  = errors.New("nil youtube video pointer")
 	ErrorNotFound                = errors.New("not found")
+	ErrorProjectRelationCycle    = errors.New("project relation would create a cycle")
+	ErrorInvalidStatusChange     = errors.New("invalid project status change")
+	ErrorInvalidMusicPtr         = errors.New("nil music pointer")
+	ErrorInvalidCharacterPtr     = errors.New("nil character pointer")
+	ErrorInvalidCursor           = errors.New("invalid cursor")
+	ErrorInvalidFilter           = errors.New("invalid filter")
+	ErrorUnsupportedSort         = errors.New("unsupported sort")
+	ErrorInvalidDate             = errors.New("invalid date")
+	ErrorInvalidBundle           = errors.New("invalid bundle")
+	ErrorInvalidBag              = errors.New("invalid bag")
+	ErrorInvalidBackup           = errors.New("invalid backup")
+	ErrorInvalidSidecar          = errors.New("invalid sidecar")
+	ErrorHashMismatch            = errors.New("contents don't match their hashes")
+	ErrorStorageInUse            = errors.New("storage root in use by another process")
+	ErrorFilePending             = errors.New("file is still being added or deleted")
+	ErrorPrimaryFileUnsupported  = errors.New("primary project files aren't supported")
+	ErrorNoChannelVideos         = errors.New("no videos found")
 )
 
 type YoutubeDownloader interface {
 	Download(ctx context.Context, url string, output io.Writer) (youtube *Youtube, extension string, err error)
 }
 
+type VideoProber interface {
+	Probe(ctx context.Context, path string) (probe *MediaProbe, err error)
+}
+
+type VideoFingerprinter interface {
+	Fingerprint(ctx context.Context, path string) (fingerprint *Fingerprint, err error)
+}
+
 type FileRelationship struct {
 	FileID  FileID
 	Youtube YoutubeVideoID
@@ -0,0 +1,190 @@
+// Package filter parses the query language used for listing archive contents, such as
+// `type:"multi-animation" channel:UC… uploaded:2014..2016 duration:>300 has:music`.
+//
+// A query is a list of terms separated by whitespace, every one of which has to match. A term is either field:value or
+// a bare word, which matches against the field named "". Prefixing a term with - negates it. Values may be quoted to
+// include whitespace, and take one of the forms
+//
+//	value       equal to value
+//	>value      greater than value, likewise >=, < and <=
+//	min..max    between min and max inclusive, either of which may be left out
+//
+// What a field means and which values it accepts is left to whoever compiles the filter.
+package filter
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/dtbead/wc-maps-archive/internal/entities"
+)
+
+type Op int
+
+const (
+	OpEqual Op = iota
+	OpLess
+	OpLessEqual
+	OpGreater
+	OpGreaterEqual
+	OpRange
+)
+
+func (o Op) ToString() string {
+	switch o {
+	case OpLess:
+		return "<"
+	case OpLessEqual:
+		return "<="
+	case OpGreater:
+		return ">"
+	case OpGreaterEqual:
+		return ">="
+	case OpRange:
+		return ".."
+	default:
+		return "="
+	}
+}
+
+// Term is a single condition of a Filter. Value is what Field is compared to, or the lower bound of an OpRange, with Max
+// being its upper bound. Either bound of a range may be empty for it being open.
+type Term struct {
+	Field  string
+	Op     Op
+	Value  string
+	Max    string
+	Negate bool
+}
+
+// Filter is a parsed query, matching whatever matches all of its terms.
+type Filter []Term
+
+var ErrorUnterminatedQuote = errors.New("unterminated quote")
+
+// Parse parses query into a Filter. An empty query gives an empty Filter, matching everything. Errors wrap
+// entities.ErrorInvalidFilter.
+func Parse(query string) (f Filter, err error) {
+	f = Filter{}
+
+	r := []rune(query)
+	for i := 0; i < len(r); {
+		if unicode.IsSpace(r[i]) {
+			i++
+			continue
+		}
+
+		var t Term
+		t, i, err = parseTerm(r, i)
+		if err != nil {
+			return nil, fmt.Errorf("%w, %w", entities.ErrorInvalidFilter, err)
+		}
+
+		f = append(f, t)
+	}
+
+	return f, nil
+}
+
+// parseTerm parses the term starting at r[i], returning it along with the index following it.
+func parseTerm(r []rune, i int) (t Term, next int, err error) {
+	if r[i] == '-' {
+		t.Negate = true
+		i++
+	}
+
+	// a field name is only ever made of letters and underscores, anything else is the start of a bare word
+	start := i
+	for i < len(r) && (unicode.IsLetter(r[i]) || r[i] == '_') {
+		i++
+	}
+
+	if i < len(r) && i > start && r[i] == ':' {
+		t.Field = strings.ToLower(string(r[start:i]))
+		i++
+	} else {
+		i = start
+	}
+
+	op, i := parseOp(r, i)
+
+	value, quoted, i, err := parseValue(r, i)
+	if err != nil {
+		return Term{}, 0, err
+	}
+
+	t.Op = op
+	t.Value = value
+
+	// ranges are only looked for in values that weren't quoted, so that a quoted title may contain ".."
+	if op == OpEqual && !quoted {
+		if min, max, ok := strings.Cut(value, ".."); ok {
+			if min == "" && max == "" {
+				return Term{}, 0, fmt.Errorf("range of %q has no bounds", t.Field)
+			}
+
+			t.Op = OpRange
+			t.Value, t.Max = min, max
+		}
+	}
+
+	if t.Op != OpRange && t.Value == "" {
+		if t.Field == "" {
+			return Term{}, 0, errors.New("empty term")
+		}
+
+		return Term{}, 0, fmt.Errorf("no value given for %q", t.Field)
+	}
+
+	return t, i, nil
+}
+
+func parseOp(r []rune, i int) (op Op, next int) {
+	if i >= len(r) || (r[i] != '<' && r[i] != '>') {
+		return OpEqual, i
+	}
+
+	orEqual := i+1 < len(r) && r[i+1] == '='
+	switch {
+	case r[i] == '<' && orEqual:
+		return OpLessEqual, i + 2
+	case r[i] == '<':
+		return OpLess, i + 1
+	case orEqual:
+		return OpGreaterEqual, i + 2
+	default:
+		return OpGreater, i + 1
+	}
+}
+
+// parseValue reads a value up to the next whitespace. A value may be quoted, in which case it runs up to the closing
+// quote and a backslash escapes the following character.
+func parseValue(r []rune, i int) (value string, quoted bool, next int, err error) {
+	if i >= len(r) || r[i] != '"' {
+		start := i
+		for i < len(r) && !unicode.IsSpace(r[i]) {
+			i++
+		}
+
+		return string(r[start:i]), false, i, nil
+	}
+
+	var b strings.Builder
+	for i++; i < len(r); i++ {
+		switch r[i] {
+		case '\\':
+			i++
+			if i < len(r) {
+				b.WriteRune(r[i])
+			}
+		case '"':
+			return b.String(), true, i + 1, nil
+		default:
+			b.WriteRune(r[i])
+		}
+	}
+
+	return "", false, 0, ErrorUnterminatedQuote
+}
@@ -0,0 +1,61 @@
+package filter_test
+
+import (
+	"testing"
+
+	"github.com/dtbead/wc-maps-archive/internal/filter"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   string
+		want    filter.Filter
+		wantErr bool
+	}{
+		{"empty", "   ", filter.Filter{}, false},
+		{"bare words", "firestar  MAP", filter.Filter{{Value: "firestar"}, {Value: "MAP"}}, false},
+		{"field", "Channel:UCabcdefghijklmnopqrstuA", filter.Filter{{Field: "channel", Value: "UCabcdefghijklmnopqrstuA"}}, false},
+		{"quoted", `type:"multi-animation" "a \"b\" c"`, filter.Filter{
+			{Field: "type", Value: "multi-animation"},
+			{Value: `a "b" c`},
+		}, false},
+		{"comparisons", "duration:>300 views:<=10 likes:>=2 height:<720", filter.Filter{
+			{Field: "duration", Op: filter.OpGreater, Value: "300"},
+			{Field: "views", Op: filter.OpLessEqual, Value: "10"},
+			{Field: "likes", Op: filter.OpGreaterEqual, Value: "2"},
+			{Field: "height", Op: filter.OpLess, Value: "720"},
+		}, false},
+		{"ranges", "uploaded:2014..2016 duration:..60 views:1000..", filter.Filter{
+			{Field: "uploaded", Op: filter.OpRange, Value: "2014", Max: "2016"},
+			{Field: "duration", Op: filter.OpRange, Max: "60"},
+			{Field: "views", Op: filter.OpRange, Value: "1000"},
+		}, false},
+		{"quoted range", `title:"part 1..2"`, filter.Filter{{Field: "title", Value: "part 1..2"}}, false},
+		{"negated", "-has:music -reupload", filter.Filter{
+			{Field: "has", Value: "music", Negate: true},
+			{Value: "reupload", Negate: true},
+		}, false},
+		{"not a field", "http://example.com 12:30", filter.Filter{
+			{Field: "http", Value: "//example.com"},
+			{Value: "12:30"},
+		}, false},
+		{"unterminated quote", `title:"firestar`, nil, true},
+		{"missing value", "has:", nil, true},
+		{"unbounded range", "uploaded:..", nil, true},
+		{"lone negation", "firestar -", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := filter.Parse(tt.query)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Parse() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !cmp.Equal(got, tt.want) {
+				t.Errorf("got diff %s", cmp.Diff(got, tt.want))
+			}
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package ffmpeg
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/dtbead/wc-maps-archive/internal/entities"
+	"github.com/dtbead/wc-maps-archive/internal/fingerprint"
+)
+
+// DefaultInterval is the time between sampled frames, in seconds, used when none is given to NewFfmpeg.
+const DefaultInterval = 1.0
+
+type Ffmpeg struct {
+	binary   string
+	interval float64
+}
+
+// NewFfmpeg returns a fingerprinter calling the ffmpeg binary at binary_path, sampling a frame every interval seconds.
+// An empty binary_path looks up "ffmpeg" in PATH instead, and an interval of 0 or less uses DefaultInterval.
+func NewFfmpeg(binary_path string, interval float64) Ffmpeg {
+	if binary_path == "" {
+		binary_path = "ffmpeg"
+	}
+
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+
+	return Ffmpeg{binary: binary_path, interval: interval}
+}
+
+// Fingerprint samples a frame every interval seconds from the video at path and perceptually hashes each of them.
+// ffmpeg does the decoding, scaling and grayscale conversion, streaming raw frames back so that no frame touches disk.
+func (f Ffmpeg) Fingerprint(ctx context.Context, path string) (fp *entities.Fingerprint, err error) {
+	size := strconv.Itoa(fingerprint.FrameSize)
+	args := []string{
+		"-v", "error",
+		"-nostdin",
+		// "file:" keeps ffmpeg from treating the path as a protocol or an option
+		"-i", "file:" + path,
+		"-an", "-sn", "-dn",
+		"-vf", "fps=1/" + strconv.FormatFloat(f.interval, 'f', -1, 64) + ",scale=" + size + ":" + size + ":flags=area,format=gray",
+		"-f", "rawvideo",
+		"-pix_fmt", "gray",
+		"pipe:1",
+	}
+
+	cmd := exec.CommandContext(ctx, f.binary, args...)
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+
+	err = cmd.Start()
+	if err != nil {
+		return nil, err
+	}
+
+	fp = &entities.Fingerprint{Interval: f.interval}
+	frame := make([]byte, fingerprint.FrameSize*fingerprint.FrameSize)
+	for {
+		_, err = io.ReadFull(stdout, frame)
+		if err != nil {
+			break
+		}
+
+		if hash, ok := fingerprint.PHash(frame); ok {
+			fp.Hashes = append(fp.Hashes, hash)
+		}
+	}
+
+	switch {
+	// a trailing partial frame means ffmpeg got cut off halfway, which Wait reports better than we could
+	case errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, io.EOF):
+		err = nil
+	default:
+		// nobody's reading stdout anymore, so ffmpeg would block forever writing to it
+		cmd.Process.Kill()
+	}
+
+	err = errors.Join(err, cmd.Wait())
+	if err != nil {
+		return nil, errors.Join(err, errors.New(stderr.String()))
+	}
+
+	if len(fp.Hashes) == 0 {
+		return nil, errors.New("no frames with enough detail to fingerprint")
+	}
+
+	return fp, nil
+}
@@ -0,0 +1,89 @@
+package ffmpeg_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"testing"
+
+	"github.com/dtbead/wc-maps-archive/internal/fingerprint"
+	"github.com/dtbead/wc-maps-archive/internal/fingerprint/ffmpeg"
+)
+
+// TestMain doubles as a fake ffmpeg binary. When FAKE_FFMPEG_FRAMES is set, the test binary writes that many raw
+// frames followed by a black one instead of running any tests, so that Ffmpeg can be tested without ffmpeg installed.
+// A negative count makes it fail like ffmpeg does on a broken file.
+func TestMain(m *testing.M) {
+	if frames, ok := os.LookupEnv("FAKE_FFMPEG_FRAMES"); ok {
+		os.Exit(fakeFfmpeg(frames))
+	}
+
+	os.Exit(m.Run())
+}
+
+func fakeFfmpeg(frames string) int {
+	args := os.Args[1:]
+	if i := slices.Index(args, "-i"); i < 0 || i+1 >= len(args) || args[i+1] != "file:video.mkv" {
+		fmt.Fprintf(os.Stderr, "missing -i file:video.mkv in %v\n", args)
+		return 1
+	}
+
+	if args[len(args)-1] != "pipe:1" {
+		fmt.Fprintf(os.Stderr, "not writing to stdout in %v\n", args)
+		return 1
+	}
+
+	n, err := strconv.Atoi(frames)
+	if err != nil || n < 0 {
+		fmt.Fprintln(os.Stderr, "file:video.mkv: Invalid data found when processing input")
+		return 1
+	}
+
+	frame := make([]byte, fingerprint.FrameSize*fingerprint.FrameSize)
+	for i := range n {
+		for p := range frame {
+			frame[p] = byte((p%fingerprint.FrameSize)*(i+1) + p/fingerprint.FrameSize*3)
+		}
+		os.Stdout.Write(frame)
+	}
+
+	os.Stdout.Write(make([]byte, len(frame)))
+	return 0
+}
+
+func TestFfmpeg_Fingerprint(t *testing.T) {
+	tests := []struct {
+		name       string
+		frames     string
+		wantHashes int
+		wantErr    bool
+	}{
+		{"frames", "5", 5, false},
+		{"only black frames", "0", 0, true},
+		{"broken file", "-1", 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("FAKE_FFMPEG_FRAMES", tt.frames)
+
+			got, err := ffmpeg.NewFfmpeg(os.Args[0], 2).Fingerprint(context.Background(), "video.mkv")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Ffmpeg.Fingerprint() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr {
+				return
+			}
+
+			if len(got.Hashes) != tt.wantHashes {
+				t.Errorf("Ffmpeg.Fingerprint() got %d hashes, want %d", len(got.Hashes), tt.wantHashes)
+			}
+
+			if got.Interval != 2 {
+				t.Errorf("Ffmpeg.Fingerprint() interval = %v, want 2", got.Interval)
+			}
+		})
+	}
+}
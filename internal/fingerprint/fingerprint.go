@@ -0,0 +1,144 @@
+package fingerprint
+
+import (
+	"cmp"
+	"context"
+	"math"
+	"math/bits"
+	"slices"
+
+	"github.com/dtbead/wc-maps-archive/internal/entities"
+)
+
+// FrameSize is the width and height, in pixels, of the grayscale frames PHash expects.
+const FrameSize = 32
+
+// hashSize is the width and height of the low frequency DCT coefficients a hash is built from.
+const hashSize = 8
+
+// MatchDistance is the largest hamming distance at which two frame hashes are considered to show the same frame.
+const MatchDistance = 10
+
+// minContrast is the smallest difference between a frame's darkest and brightest pixel for it to be worth hashing.
+const minContrast = 16
+
+type Video interface {
+	Fingerprint(ctx context.Context, path string) (fingerprint *entities.Fingerprint, err error)
+}
+
+// dctTable[u][x] holds the DCT-II basis function of frequency u at pixel x.
+var dctTable = func() (t [hashSize][FrameSize]float64) {
+	for u := range hashSize {
+		for x := range FrameSize {
+			t[u][x] = math.Cos(float64(2*x+1) * float64(u) * math.Pi / (2 * FrameSize))
+		}
+	}
+	return t
+}()
+
+// PHash returns the perceptual hash of frame, a FrameSize*FrameSize 8-bit grayscale image in row-major order. ok is
+// false if frame is too flat to be told apart from other flat frames, such as a fade to black.
+func PHash(frame []byte) (hash uint64, ok bool) {
+	if len(frame) != FrameSize*FrameSize {
+		return 0, false
+	}
+
+	if slices.Max(frame)-slices.Min(frame) < minContrast {
+		return 0, false
+	}
+
+	// separable 2D DCT, only computing the low frequencies the hash is built from
+	var rows [FrameSize][hashSize]float64
+	for y := range FrameSize {
+		for u := range hashSize {
+			var sum float64
+			for x := range FrameSize {
+				sum += float64(frame[y*FrameSize+x]) * dctTable[u][x]
+			}
+			rows[y][u] = sum
+		}
+	}
+
+	var coefficients [hashSize * hashSize]float64
+	for v := range hashSize {
+		for u := range hashSize {
+			var sum float64
+			for y := range FrameSize {
+				sum += rows[y][u] * dctTable[v][y]
+			}
+			coefficients[v*hashSize+u] = sum
+		}
+	}
+
+	// the DC coefficient only holds the average brightness, so it's left out of the median
+	sorted := slices.Clone(coefficients[1:])
+	slices.Sort(sorted)
+	median := sorted[len(sorted)/2]
+
+	for i, c := range coefficients {
+		if c > median {
+			hash |= 1 << i
+		}
+	}
+
+	return hash, true
+}
+
+// Distance returns the hamming distance between two frame hashes.
+func Distance(a, b uint64) int {
+	return bits.OnesCount64(a ^ b)
+}
+
+// Similarity scores how alike a and b look, from 0 to 1. Frames are matched one to one, and the score is the share of
+// frames in either fingerprint that got matched, so trimmed intros and outros lower the score without ruling out a
+// match, while a looped section only counts as often as the other fingerprint shows it.
+func Similarity(a, b entities.Fingerprint) float64 {
+	if len(a.Hashes) == 0 || len(b.Hashes) == 0 {
+		return 0
+	}
+
+	// frames are matched greedily, which depends on which fingerprint goes first, so the order is fixed for the score to
+	// be the same either way around
+	if c := cmp.Compare(len(a.Hashes), len(b.Hashes)); c > 0 || c == 0 && slices.Compare(a.Hashes, b.Hashes) > 0 {
+		a, b = b, a
+	}
+
+	matched := countMatched(a.Hashes, b.Hashes)
+	return float64(2*matched) / float64(len(a.Hashes)+len(b.Hashes))
+}
+
+// Duration returns how long the hashed frames of fp span, in seconds. Frames without any detail are left out of a
+// fingerprint, so this is at most as long as the video itself.
+func Duration(fp entities.Fingerprint) float64 {
+	return float64(len(fp.Hashes)) * fp.Interval
+}
+
+// DurationBounds returns the range of durations, as told by Duration, a fingerprint has to be within to possibly score
+// at least min_score against one lasting duration. Frames match one to one, so a fingerprint with r times as many
+// frames as another has at most all of its frames matched, scoring at most 2r/(1+r) against it. The bounds hold for
+// fingerprints sampled at the same interval, which is the case for every fingerprint taken with the same settings.
+func DurationBounds(duration, min_score float64) (lower, upper float64) {
+	if min_score <= 0 {
+		return 0, math.MaxFloat64
+	}
+
+	r := min_score / (2 - min_score)
+	return duration * r, duration / r
+}
+
+// countMatched returns how many hashes in a get matched to a hash in b within MatchDistance of it, every hash in b being
+// matched at most once. Each hash in a is matched to the first such hash in b that's still free.
+func countMatched(a, b []uint64) (matched int) {
+	used := make([]bool, len(b))
+	for _, x := range a {
+		for i, y := range b {
+			if !used[i] && Distance(x, y) <= MatchDistance {
+				used[i] = true
+				matched++
+				break
+			}
+		}
+	}
+
+	return matched
+}
@@ -0,0 +1,189 @@
+package fingerprint_test
+
+import (
+	"math"
+	"math/rand/v2"
+	"testing"
+
+	"github.com/dtbead/wc-maps-archive/internal/entities"
+	"github.com/dtbead/wc-maps-archive/internal/fingerprint"
+)
+
+// helperFrame returns a FrameSize*FrameSize frame of smooth random shapes, seeded by seed.
+func helperFrame(seed uint64) []byte {
+	r := rand.New(rand.NewPCG(seed, seed))
+	frame := make([]byte, fingerprint.FrameSize*fingerprint.FrameSize)
+
+	// a few random blocks on a gradient resemble a real downscaled frame better than pure noise does
+	for y := range fingerprint.FrameSize {
+		for x := range fingerprint.FrameSize {
+			frame[y*fingerprint.FrameSize+x] = byte(x * 4)
+		}
+	}
+
+	for range 6 {
+		x0, y0 := r.IntN(fingerprint.FrameSize-8), r.IntN(fingerprint.FrameSize-8)
+		w, h, c := 4+r.IntN(8), 4+r.IntN(8), byte(r.IntN(256))
+		for y := y0; y < min(y0+h, fingerprint.FrameSize); y++ {
+			for x := x0; x < min(x0+w, fingerprint.FrameSize); x++ {
+				frame[y*fingerprint.FrameSize+x] = c
+			}
+		}
+	}
+
+	return frame
+}
+
+// helperReencode mimics a lossy re-encode of frame by brightening it and adding a little noise.
+func helperReencode(frame []byte, seed uint64) []byte {
+	r := rand.New(rand.NewPCG(seed, 0))
+	out := make([]byte, len(frame))
+	for i, p := range frame {
+		v := int(p) + 10 + r.IntN(7) - 3
+		out[i] = byte(max(0, min(255, v)))
+	}
+
+	return out
+}
+
+func TestPHash(t *testing.T) {
+	original, ok := fingerprint.PHash(helperFrame(1))
+	if !ok {
+		t.Fatal("PHash() of a detailed frame returned !ok")
+	}
+
+	reencoded, ok := fingerprint.PHash(helperReencode(helperFrame(1), 1))
+	if !ok {
+		t.Fatal("PHash() of a re-encoded frame returned !ok")
+	}
+
+	if d := fingerprint.Distance(original, reencoded); d > fingerprint.MatchDistance {
+		t.Errorf("distance between original and re-encoded frame = %d, want at most %d", d, fingerprint.MatchDistance)
+	}
+
+	other, _ := fingerprint.PHash(helperFrame(2))
+	if d := fingerprint.Distance(original, other); d <= fingerprint.MatchDistance {
+		t.Errorf("distance between unrelated frames = %d, want more than %d", d, fingerprint.MatchDistance)
+	}
+
+	black := make([]byte, fingerprint.FrameSize*fingerprint.FrameSize)
+	if _, ok := fingerprint.PHash(black); ok {
+		t.Error("PHash() of a black frame returned ok")
+	}
+
+	if _, ok := fingerprint.PHash(black[:10]); ok {
+		t.Error("PHash() of a truncated frame returned ok")
+	}
+}
+
+func TestSimilarity(t *testing.T) {
+	var original, reencoded, trimmed, looped, unrelated entities.Fingerprint
+	for i := range uint64(20) {
+		h, _ := fingerprint.PHash(helperFrame(i))
+		original.Hashes = append(original.Hashes, h)
+
+		h, _ = fingerprint.PHash(helperReencode(helperFrame(i), i))
+		reencoded.Hashes = append(reencoded.Hashes, h)
+
+		// the first and last 5 frames are cut
+		if i >= 5 && i < 15 {
+			trimmed.Hashes = append(trimmed.Hashes, h)
+		}
+
+		h, _ = fingerprint.PHash(helperFrame(i + 100))
+		unrelated.Hashes = append(unrelated.Hashes, h)
+	}
+
+	// every frame is shown three times over
+	for range 3 {
+		looped.Hashes = append(looped.Hashes, reencoded.Hashes...)
+	}
+
+	tests := []struct {
+		name     string
+		a, b     entities.Fingerprint
+		min, max float64
+	}{
+		{"identical", original, original, 1, 1},
+		{"re-encoded", original, reencoded, 0.9, 1},
+		{"trimmed", original, trimmed, 0.6, 0.7},
+		{"looped", original, looped, 0.45, 0.5},
+		{"unrelated", original, unrelated, 0, 0.2},
+		{"empty", original, entities.Fingerprint{}, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := fingerprint.Similarity(tt.a, tt.b)
+			if got < tt.min || got > tt.max {
+				t.Errorf("Similarity() = %v, want between %v and %v", got, tt.min, tt.max)
+			}
+
+			if reverse := fingerprint.Similarity(tt.b, tt.a); reverse != got {
+				t.Errorf("Similarity() isn't symmetric, %v != %v", got, reverse)
+			}
+		})
+	}
+}
+
+func TestDurationBounds(t *testing.T) {
+	tests := []struct {
+		name         string
+		duration     float64
+		min_score    float64
+		lower, upper float64
+	}{
+		{"identical only", 60, 1, 60, 60},
+		{"half as long scores 2/3", 60, 2.0 / 3, 30, 120},
+		{"any score", 60, 0, 0, math.MaxFloat64},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lower, upper := fingerprint.DurationBounds(tt.duration, tt.min_score)
+			if math.Abs(lower-tt.lower) > 1e-9 || math.Abs(upper-tt.upper) > 1e-9 {
+				t.Errorf("DurationBounds() = %v, %v, want %v, %v", lower, upper, tt.lower, tt.upper)
+			}
+		})
+	}
+
+	// a trimmed copy still has to be within the bounds of the score it gets
+	var original, trimmed entities.Fingerprint
+	original.Interval, trimmed.Interval = 1, 1
+	for i := range uint64(20) {
+		h, _ := fingerprint.PHash(helperFrame(i))
+		original.Hashes = append(original.Hashes, h)
+		if i >= 5 && i < 15 {
+			trimmed.Hashes = append(trimmed.Hashes, h)
+		}
+	}
+
+	lower, upper := fingerprint.DurationBounds(fingerprint.Duration(original), fingerprint.Similarity(original, trimmed))
+	if d := fingerprint.Duration(trimmed); d < lower-1e-9 || d > upper+1e-9 {
+		t.Errorf("Duration() of trimmed copy = %v, want between %v and %v", d, lower, upper)
+	}
+}
+
+func TestDurationBounds_Scores(t *testing.T) {
+	// every pair scoring at least some score has to be within the bounds of it, however its frames repeat
+	r := rand.New(rand.NewPCG(1, 2))
+	for i := range 200 {
+		var frames []uint64
+		for j := range 1 + r.IntN(10) {
+			h, _ := fingerprint.PHash(helperFrame(uint64(i*100 + j)))
+			frames = append(frames, h)
+		}
+
+		a, b := entities.Fingerprint{Interval: 1}, entities.Fingerprint{Interval: 1}
+		for range 1 + r.IntN(40) {
+			a.Hashes = append(a.Hashes, frames[r.IntN(len(frames))])
+		}
+		for range 1 + r.IntN(40) {
+			b.Hashes = append(b.Hashes, frames[r.IntN(len(frames))])
+		}
+
+		score := fingerprint.Similarity(a, b)
+		lower, upper := fingerprint.DurationBounds(fingerprint.Duration(a), score)
+		if d := fingerprint.Duration(b); d < lower-1e-9 || d > upper+1e-9 {
+			t.Fatalf("%d frames scoring %v against %d frames, want between %v and %v frames", len(b.Hashes), score, len(a.Hashes), lower, upper)
+		}
+	}
+}
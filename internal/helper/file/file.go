@@ -94,6 +94,56 @@ func Copy(destination string, r io.Reader) error {
 	return nil
 }
 
+// HashWriter is an io.Writer which calculates the MD5, SHA1 and SHA256 hash of everything written to it.
+type HashWriter struct {
+	md5, sha1, sha256 hash.Hash
+	written           int64
+}
+
+func NewHashWriter() *HashWriter {
+	return &HashWriter{
+		md5:    md5.New(),
+		sha1:   sha1.New(),
+		sha256: sha256.New(),
+	}
+}
+
+func (h *HashWriter) Write(p []byte) (n int, err error) {
+	h.md5.Write(p)
+	h.sha1.Write(p)
+	h.sha256.Write(p)
+	h.written += int64(len(p))
+	return len(p), nil
+}
+
+// Hashes returns the hashes of everything written so far.
+func (h *HashWriter) Hashes() entities.Hashes {
+	return entities.Hashes{
+		MD5:    h.md5.Sum(nil),
+		SHA1:   h.sha1.Sum(nil),
+		SHA256: h.sha256.Sum(nil),
+	}
+}
+
+// Written returns the total amount of bytes written so far.
+func (h *HashWriter) Written() int64 {
+	return h.written
+}
+
+// Move renames source to destination, creating any missing parent directories of destination. Both paths are
+// expected to be on the same filesystem.
+func Move(source, destination string) error {
+	rootDirectory := filepath.Dir(destination)
+	if !DoesPathExist(rootDirectory) {
+		err := os.MkdirAll(rootDirectory, 0775)
+		if err != nil {
+			return err
+		}
+	}
+
+	return os.Rename(source, destination)
+}
+
 // GetHash returns the MD5, SHA1, SHA256 hash and total bytes read of a given io.Reader.
 func GetHash(r io.Reader) (hashes entities.Hashes, read int64, err error) {
 	hashPool := getHashpool()
@@ -11,10 +11,12 @@ package mock_storage
 
 import (
 	context "context"
+	json "encoding/json"
 	io "io"
 	reflect "reflect"
 
 	entities "github.com/dtbead/wc-maps-archive/internal/entities"
+	filter "github.com/dtbead/wc-maps-archive/internal/filter"
 	gomock "go.uber.org/mock/gomock"
 )
 
@@ -42,6 +44,21 @@ func (m *MockFileRepository) EXPECT() *MockFileRepositoryMockRecorder {
 	return m.recorder
 }
 
+// AdoptFile mocks base method.
+func (m *MockFileRepository) AdoptFile(ctx context.Context, path_relative string) (entities.FileID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AdoptFile", ctx, path_relative)
+	ret0, _ := ret[0].(entities.FileID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AdoptFile indicates an expected call of AdoptFile.
+func (mr *MockFileRepositoryMockRecorder) AdoptFile(ctx, path_relative any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AdoptFile", reflect.TypeOf((*MockFileRepository)(nil).AdoptFile), ctx, path_relative)
+}
+
 // DeleteFile mocks base method.
 func (m *MockFileRepository) DeleteFile(ctx context.Context, file_id entities.FileID) error {
 	m.ctrl.T.Helper()
@@ -71,6 +88,67 @@ func (mr *MockFileRepositoryMockRecorder) GetFile(ctx, file_id any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFile", reflect.TypeOf((*MockFileRepository)(nil).GetFile), ctx, file_id)
 }
 
+// GetFileIDBySHA256 mocks base method.
+func (m *MockFileRepository) GetFileIDBySHA256(ctx context.Context, sha256 []byte) (entities.FileID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFileIDBySHA256", ctx, sha256)
+	ret0, _ := ret[0].(entities.FileID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFileIDBySHA256 indicates an expected call of GetFileIDBySHA256.
+func (mr *MockFileRepositoryMockRecorder) GetFileIDBySHA256(ctx, sha256 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFileIDBySHA256", reflect.TypeOf((*MockFileRepository)(nil).GetFileIDBySHA256), ctx, sha256)
+}
+
+// GetFileVideo mocks base method.
+func (m *MockFileRepository) GetFileVideo(ctx context.Context, file_id entities.FileID) (*entities.Video, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFileVideo", ctx, file_id)
+	ret0, _ := ret[0].(*entities.Video)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFileVideo indicates an expected call of GetFileVideo.
+func (mr *MockFileRepositoryMockRecorder) GetFileVideo(ctx, file_id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFileVideo", reflect.TypeOf((*MockFileRepository)(nil).GetFileVideo), ctx, file_id)
+}
+
+// GetStoredPaths mocks base method.
+func (m *MockFileRepository) GetStoredPaths(ctx context.Context) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStoredPaths", ctx)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStoredPaths indicates an expected call of GetStoredPaths.
+func (mr *MockFileRepositoryMockRecorder) GetStoredPaths(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStoredPaths", reflect.TypeOf((*MockFileRepository)(nil).GetStoredPaths), ctx)
+}
+
+// ListFiles mocks base method.
+func (m *MockFileRepository) ListFiles(ctx context.Context, opts entities.ListOptions) ([]entities.FileID, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListFiles", ctx, opts)
+	ret0, _ := ret[0].([]entities.FileID)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListFiles indicates an expected call of ListFiles.
+func (mr *MockFileRepositoryMockRecorder) ListFiles(ctx, opts any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListFiles", reflect.TypeOf((*MockFileRepository)(nil).ListFiles), ctx, opts)
+}
+
 // NewFile mocks base method.
 func (m *MockFileRepository) NewFile(ctx context.Context, file io.Reader, extension string) (entities.FileID, error) {
 	m.ctrl.T.Helper()
@@ -86,6 +164,20 @@ func (mr *MockFileRepositoryMockRecorder) NewFile(ctx, file, extension any) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewFile", reflect.TypeOf((*MockFileRepository)(nil).NewFile), ctx, file, extension)
 }
 
+// NewFileVideo mocks base method.
+func (m *MockFileRepository) NewFileVideo(ctx context.Context, file_id entities.FileID, video *entities.Video) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NewFileVideo", ctx, file_id, video)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// NewFileVideo indicates an expected call of NewFileVideo.
+func (mr *MockFileRepositoryMockRecorder) NewFileVideo(ctx, file_id, video any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewFileVideo", reflect.TypeOf((*MockFileRepository)(nil).NewFileVideo), ctx, file_id, video)
+}
+
 // NewTempFile mocks base method.
 func (m *MockFileRepository) NewTempFile(ctx context.Context) (io.ReadWriteCloser, error) {
 	m.ctrl.T.Helper()
@@ -101,6 +193,49 @@ func (mr *MockFileRepositoryMockRecorder) NewTempFile(ctx any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewTempFile", reflect.TypeOf((*MockFileRepository)(nil).NewTempFile), ctx)
 }
 
+// ReadSidecar mocks base method.
+func (m *MockFileRepository) ReadSidecar(ctx context.Context, path_relative string) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadSidecar", ctx, path_relative)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReadSidecar indicates an expected call of ReadSidecar.
+func (mr *MockFileRepositoryMockRecorder) ReadSidecar(ctx, path_relative any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadSidecar", reflect.TypeOf((*MockFileRepository)(nil).ReadSidecar), ctx, path_relative)
+}
+
+// Recover mocks base method.
+func (m *MockFileRepository) Recover(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Recover", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Recover indicates an expected call of Recover.
+func (mr *MockFileRepositoryMockRecorder) Recover(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Recover", reflect.TypeOf((*MockFileRepository)(nil).Recover), ctx)
+}
+
+// WriteSidecar mocks base method.
+func (m *MockFileRepository) WriteSidecar(ctx context.Context, file_id entities.FileID, sidecar []byte) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WriteSidecar", ctx, file_id, sidecar)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WriteSidecar indicates an expected call of WriteSidecar.
+func (mr *MockFileRepositoryMockRecorder) WriteSidecar(ctx, file_id, sidecar any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WriteSidecar", reflect.TypeOf((*MockFileRepository)(nil).WriteSidecar), ctx, file_id, sidecar)
+}
+
 // MockProjectRepository is a mock of ProjectRepository interface.
 type MockProjectRepository struct {
 	ctrl     *gomock.Controller
@@ -139,6 +274,34 @@ func (mr *MockProjectRepositoryMockRecorder) AssignProjectFile(ctx, uuid, file_i
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AssignProjectFile", reflect.TypeOf((*MockProjectRepository)(nil).AssignProjectFile), ctx, uuid, file_id)
 }
 
+// AssignYoutube mocks base method.
+func (m *MockProjectRepository) AssignYoutube(ctx context.Context, project_uuid entities.ProjectUUID, youtube_id entities.YoutubeVideoID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AssignYoutube", ctx, project_uuid, youtube_id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AssignYoutube indicates an expected call of AssignYoutube.
+func (mr *MockProjectRepositoryMockRecorder) AssignYoutube(ctx, project_uuid, youtube_id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AssignYoutube", reflect.TypeOf((*MockProjectRepository)(nil).AssignYoutube), ctx, project_uuid, youtube_id)
+}
+
+// DeleteLatestProjectStatus mocks base method.
+func (m *MockProjectRepository) DeleteLatestProjectStatus(ctx context.Context, uuid entities.ProjectUUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteLatestProjectStatus", ctx, uuid)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteLatestProjectStatus indicates an expected call of DeleteLatestProjectStatus.
+func (mr *MockProjectRepositoryMockRecorder) DeleteLatestProjectStatus(ctx, uuid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteLatestProjectStatus", reflect.TypeOf((*MockProjectRepository)(nil).DeleteLatestProjectStatus), ctx, uuid)
+}
+
 // DeleteProject mocks base method.
 func (m *MockProjectRepository) DeleteProject(ctx context.Context, uuid entities.ProjectUUID) error {
 	m.ctrl.T.Helper()
@@ -153,6 +316,49 @@ func (mr *MockProjectRepositoryMockRecorder) DeleteProject(ctx, uuid any) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteProject", reflect.TypeOf((*MockProjectRepository)(nil).DeleteProject), ctx, uuid)
 }
 
+// DeleteProjectPart mocks base method.
+func (m *MockProjectRepository) DeleteProjectPart(ctx context.Context, part_id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteProjectPart", ctx, part_id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteProjectPart indicates an expected call of DeleteProjectPart.
+func (mr *MockProjectRepositoryMockRecorder) DeleteProjectPart(ctx, part_id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteProjectPart", reflect.TypeOf((*MockProjectRepository)(nil).DeleteProjectPart), ctx, part_id)
+}
+
+// DeleteProjectRelation mocks base method.
+func (m *MockProjectRepository) DeleteProjectRelation(ctx context.Context, relation entities.ProjectRelation) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteProjectRelation", ctx, relation)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteProjectRelation indicates an expected call of DeleteProjectRelation.
+func (mr *MockProjectRepositoryMockRecorder) DeleteProjectRelation(ctx, relation any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteProjectRelation", reflect.TypeOf((*MockProjectRepository)(nil).DeleteProjectRelation), ctx, relation)
+}
+
+// GetFileProjects mocks base method.
+func (m *MockProjectRepository) GetFileProjects(ctx context.Context, file_id entities.FileID) ([]entities.ProjectUUID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFileProjects", ctx, file_id)
+	ret0, _ := ret[0].([]entities.ProjectUUID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFileProjects indicates an expected call of GetFileProjects.
+func (mr *MockProjectRepositoryMockRecorder) GetFileProjects(ctx, file_id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFileProjects", reflect.TypeOf((*MockProjectRepository)(nil).GetFileProjects), ctx, file_id)
+}
+
 // GetProject mocks base method.
 func (m *MockProjectRepository) GetProject(ctx context.Context, uuid entities.ProjectUUID) (*entities.Project, error) {
 	m.ctrl.T.Helper()
@@ -168,190 +374,1555 @@ func (mr *MockProjectRepositoryMockRecorder) GetProject(ctx, uuid any) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProject", reflect.TypeOf((*MockProjectRepository)(nil).GetProject), ctx, uuid)
 }
 
-// GetProjectVideos mocks base method.
-func (m *MockProjectRepository) GetProjectVideos(ctx context.Context, uuid entities.ProjectUUID) ([]entities.FileID, error) {
+// GetProjectAncestors mocks base method.
+func (m *MockProjectRepository) GetProjectAncestors(ctx context.Context, uuid entities.ProjectUUID, relation_type entities.ProjectRelationType) ([]entities.ProjectRelation, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetProjectVideos", ctx, uuid)
-	ret0, _ := ret[0].([]entities.FileID)
+	ret := m.ctrl.Call(m, "GetProjectAncestors", ctx, uuid, relation_type)
+	ret0, _ := ret[0].([]entities.ProjectRelation)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetProjectVideos indicates an expected call of GetProjectVideos.
-func (mr *MockProjectRepositoryMockRecorder) GetProjectVideos(ctx, uuid any) *gomock.Call {
+// GetProjectAncestors indicates an expected call of GetProjectAncestors.
+func (mr *MockProjectRepositoryMockRecorder) GetProjectAncestors(ctx, uuid, relation_type any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProjectVideos", reflect.TypeOf((*MockProjectRepository)(nil).GetProjectVideos), ctx, uuid)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProjectAncestors", reflect.TypeOf((*MockProjectRepository)(nil).GetProjectAncestors), ctx, uuid, relation_type)
 }
 
-// NewProject mocks base method.
-func (m *MockProjectRepository) NewProject(ctx context.Context, project *entities.Project) (entities.ProjectUUID, error) {
+// GetProjectDescendants mocks base method.
+func (m *MockProjectRepository) GetProjectDescendants(ctx context.Context, uuid entities.ProjectUUID, relation_type entities.ProjectRelationType) ([]entities.ProjectRelation, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "NewProject", ctx, project)
-	ret0, _ := ret[0].(entities.ProjectUUID)
+	ret := m.ctrl.Call(m, "GetProjectDescendants", ctx, uuid, relation_type)
+	ret0, _ := ret[0].([]entities.ProjectRelation)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// NewProject indicates an expected call of NewProject.
-func (mr *MockProjectRepositoryMockRecorder) NewProject(ctx, project any) *gomock.Call {
+// GetProjectDescendants indicates an expected call of GetProjectDescendants.
+func (mr *MockProjectRepositoryMockRecorder) GetProjectDescendants(ctx, uuid, relation_type any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewProject", reflect.TypeOf((*MockProjectRepository)(nil).NewProject), ctx, project)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProjectDescendants", reflect.TypeOf((*MockProjectRepository)(nil).GetProjectDescendants), ctx, uuid, relation_type)
 }
 
-// UnassignProjectVideo mocks base method.
-func (m *MockProjectRepository) UnassignProjectVideo(ctx context.Context, uuid entities.ProjectUUID, file_id entities.FileID) error {
+// GetProjectDescriptions mocks base method.
+func (m *MockProjectRepository) GetProjectDescriptions(ctx context.Context, uuid entities.ProjectUUID) ([]entities.ProjectDescription, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UnassignProjectVideo", ctx, uuid, file_id)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "GetProjectDescriptions", ctx, uuid)
+	ret0, _ := ret[0].([]entities.ProjectDescription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// UnassignProjectVideo indicates an expected call of UnassignProjectVideo.
-func (mr *MockProjectRepositoryMockRecorder) UnassignProjectVideo(ctx, uuid, file_id any) *gomock.Call {
+// GetProjectDescriptions indicates an expected call of GetProjectDescriptions.
+func (mr *MockProjectRepositoryMockRecorder) GetProjectDescriptions(ctx, uuid any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnassignProjectVideo", reflect.TypeOf((*MockProjectRepository)(nil).UnassignProjectVideo), ctx, uuid, file_id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProjectDescriptions", reflect.TypeOf((*MockProjectRepository)(nil).GetProjectDescriptions), ctx, uuid)
 }
 
-// MockYoutubeRepository is a mock of YoutubeRepository interface.
-type MockYoutubeRepository struct {
-	ctrl     *gomock.Controller
-	recorder *MockYoutubeRepositoryMockRecorder
-	isgomock struct{}
+// GetProjectPart mocks base method.
+func (m *MockProjectRepository) GetProjectPart(ctx context.Context, part_id int64) (*entities.ProjectPart, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProjectPart", ctx, part_id)
+	ret0, _ := ret[0].(*entities.ProjectPart)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// MockYoutubeRepositoryMockRecorder is the mock recorder for MockYoutubeRepository.
-type MockYoutubeRepositoryMockRecorder struct {
-	mock *MockYoutubeRepository
+// GetProjectPart indicates an expected call of GetProjectPart.
+func (mr *MockProjectRepositoryMockRecorder) GetProjectPart(ctx, part_id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProjectPart", reflect.TypeOf((*MockProjectRepository)(nil).GetProjectPart), ctx, part_id)
 }
 
-// NewMockYoutubeRepository creates a new mock instance.
-func NewMockYoutubeRepository(ctrl *gomock.Controller) *MockYoutubeRepository {
-	mock := &MockYoutubeRepository{ctrl: ctrl}
-	mock.recorder = &MockYoutubeRepositoryMockRecorder{mock}
-	return mock
+// GetProjectParts mocks base method.
+func (m *MockProjectRepository) GetProjectParts(ctx context.Context, uuid entities.ProjectUUID) ([]entities.ProjectPart, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProjectParts", ctx, uuid)
+	ret0, _ := ret[0].([]entities.ProjectPart)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// EXPECT returns an object that allows the caller to indicate expected use.
-func (m *MockYoutubeRepository) EXPECT() *MockYoutubeRepositoryMockRecorder {
-	return m.recorder
+// GetProjectParts indicates an expected call of GetProjectParts.
+func (mr *MockProjectRepositoryMockRecorder) GetProjectParts(ctx, uuid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProjectParts", reflect.TypeOf((*MockProjectRepository)(nil).GetProjectParts), ctx, uuid)
 }
 
-// GetChannelVideos mocks base method.
-func (m *MockYoutubeRepository) GetChannelVideos(ctx context.Context, channel_id entities.YoutubeChannelID) ([]entities.YoutubeVideoID, error) {
+// GetProjectRelations mocks base method.
+func (m *MockProjectRepository) GetProjectRelations(ctx context.Context, uuid entities.ProjectUUID) ([]entities.ProjectRelation, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetChannelVideos", ctx, channel_id)
-	ret0, _ := ret[0].([]entities.YoutubeVideoID)
+	ret := m.ctrl.Call(m, "GetProjectRelations", ctx, uuid)
+	ret0, _ := ret[0].([]entities.ProjectRelation)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetChannelVideos indicates an expected call of GetChannelVideos.
-func (mr *MockYoutubeRepositoryMockRecorder) GetChannelVideos(ctx, channel_id any) *gomock.Call {
+// GetProjectRelations indicates an expected call of GetProjectRelations.
+func (mr *MockProjectRepositoryMockRecorder) GetProjectRelations(ctx, uuid any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetChannelVideos", reflect.TypeOf((*MockYoutubeRepository)(nil).GetChannelVideos), ctx, channel_id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProjectRelations", reflect.TypeOf((*MockProjectRepository)(nil).GetProjectRelations), ctx, uuid)
 }
 
-// GetDescription mocks base method.
-func (m *MockYoutubeRepository) GetDescription(ctx context.Context, youtube_id entities.YoutubeVideoID) (string, error) {
+// GetProjectStatuses mocks base method.
+func (m *MockProjectRepository) GetProjectStatuses(ctx context.Context, uuid entities.ProjectUUID) ([]entities.ProjectStatusChange, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetDescription", ctx, youtube_id)
-	ret0, _ := ret[0].(string)
+	ret := m.ctrl.Call(m, "GetProjectStatuses", ctx, uuid)
+	ret0, _ := ret[0].([]entities.ProjectStatusChange)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetDescription indicates an expected call of GetDescription.
-func (mr *MockYoutubeRepositoryMockRecorder) GetDescription(ctx, youtube_id any) *gomock.Call {
+// GetProjectStatuses indicates an expected call of GetProjectStatuses.
+func (mr *MockProjectRepositoryMockRecorder) GetProjectStatuses(ctx, uuid any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDescription", reflect.TypeOf((*MockYoutubeRepository)(nil).GetDescription), ctx, youtube_id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProjectStatuses", reflect.TypeOf((*MockProjectRepository)(nil).GetProjectStatuses), ctx, uuid)
 }
 
-// GetTitle mocks base method.
-func (m *MockYoutubeRepository) GetTitle(ctx context.Context, youtube_id entities.YoutubeVideoID) (string, error) {
+// GetProjectTitles mocks base method.
+func (m *MockProjectRepository) GetProjectTitles(ctx context.Context, uuid entities.ProjectUUID) ([]entities.ProjectTitle, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetTitle", ctx, youtube_id)
-	ret0, _ := ret[0].(string)
+	ret := m.ctrl.Call(m, "GetProjectTitles", ctx, uuid)
+	ret0, _ := ret[0].([]entities.ProjectTitle)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetTitle indicates an expected call of GetTitle.
-func (mr *MockYoutubeRepositoryMockRecorder) GetTitle(ctx, youtube_id any) *gomock.Call {
+// GetProjectTitles indicates an expected call of GetProjectTitles.
+func (mr *MockProjectRepositoryMockRecorder) GetProjectTitles(ctx, uuid any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTitle", reflect.TypeOf((*MockYoutubeRepository)(nil).GetTitle), ctx, youtube_id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProjectTitles", reflect.TypeOf((*MockProjectRepository)(nil).GetProjectTitles), ctx, uuid)
 }
 
-// GetYoutube mocks base method.
-func (m *MockYoutubeRepository) GetYoutube(ctx context.Context, youtube_id entities.YoutubeVideoID) (*entities.Youtube, error) {
+// GetProjectVideos mocks base method.
+func (m *MockProjectRepository) GetProjectVideos(ctx context.Context, uuid entities.ProjectUUID) ([]entities.FileID, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetYoutube", ctx, youtube_id)
-	ret0, _ := ret[0].(*entities.Youtube)
+	ret := m.ctrl.Call(m, "GetProjectVideos", ctx, uuid)
+	ret0, _ := ret[0].([]entities.FileID)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetYoutube indicates an expected call of GetYoutube.
-func (mr *MockYoutubeRepositoryMockRecorder) GetYoutube(ctx, youtube_id any) *gomock.Call {
+// GetProjectVideos indicates an expected call of GetProjectVideos.
+func (mr *MockProjectRepositoryMockRecorder) GetProjectVideos(ctx, uuid any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetYoutube", reflect.TypeOf((*MockYoutubeRepository)(nil).GetYoutube), ctx, youtube_id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProjectVideos", reflect.TypeOf((*MockProjectRepository)(nil).GetProjectVideos), ctx, uuid)
 }
 
-// GetYoutubeFileIDs mocks base method.
-func (m *MockYoutubeRepository) GetYoutubeFileIDs(ctx context.Context, youtube_id entities.YoutubeVideoID) ([]entities.FileID, error) {
+// GetProjectYoutube mocks base method.
+func (m *MockProjectRepository) GetProjectYoutube(ctx context.Context, uuid entities.ProjectUUID) ([]entities.YoutubeVideoID, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetYoutubeFileIDs", ctx, youtube_id)
-	ret0, _ := ret[0].([]entities.FileID)
+	ret := m.ctrl.Call(m, "GetProjectYoutube", ctx, uuid)
+	ret0, _ := ret[0].([]entities.YoutubeVideoID)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetYoutubeFileIDs indicates an expected call of GetYoutubeFileIDs.
-func (mr *MockYoutubeRepositoryMockRecorder) GetYoutubeFileIDs(ctx, youtube_id any) *gomock.Call {
+// GetProjectYoutube indicates an expected call of GetProjectYoutube.
+func (mr *MockProjectRepositoryMockRecorder) GetProjectYoutube(ctx, uuid any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetYoutubeFileIDs", reflect.TypeOf((*MockYoutubeRepository)(nil).GetYoutubeFileIDs), ctx, youtube_id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProjectYoutube", reflect.TypeOf((*MockProjectRepository)(nil).GetProjectYoutube), ctx, uuid)
 }
 
-// GetYoutubeVideo mocks base method.
-func (m *MockYoutubeRepository) GetYoutubeVideo(ctx context.Context, youtube_id entities.YoutubeVideoID) (*entities.YoutubeVideo, error) {
+// ListProjects mocks base method.
+func (m *MockProjectRepository) ListProjects(ctx context.Context, f filter.Filter, opts entities.ListOptions) ([]entities.ProjectUUID, string, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetYoutubeVideo", ctx, youtube_id)
-	ret0, _ := ret[0].(*entities.YoutubeVideo)
+	ret := m.ctrl.Call(m, "ListProjects", ctx, f, opts)
+	ret0, _ := ret[0].([]entities.ProjectUUID)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListProjects indicates an expected call of ListProjects.
+func (mr *MockProjectRepositoryMockRecorder) ListProjects(ctx, f, opts any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListProjects", reflect.TypeOf((*MockProjectRepository)(nil).ListProjects), ctx, f, opts)
+}
+
+// NewProject mocks base method.
+func (m *MockProjectRepository) NewProject(ctx context.Context, project *entities.Project) (entities.ProjectUUID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NewProject", ctx, project)
+	ret0, _ := ret[0].(entities.ProjectUUID)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetYoutubeVideo indicates an expected call of GetYoutubeVideo.
-func (mr *MockYoutubeRepositoryMockRecorder) GetYoutubeVideo(ctx, youtube_id any) *gomock.Call {
+// NewProject indicates an expected call of NewProject.
+func (mr *MockProjectRepositoryMockRecorder) NewProject(ctx, project any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetYoutubeVideo", reflect.TypeOf((*MockYoutubeRepository)(nil).GetYoutubeVideo), ctx, youtube_id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewProject", reflect.TypeOf((*MockProjectRepository)(nil).NewProject), ctx, project)
 }
 
-// NewYoutube mocks base method.
-func (m *MockYoutubeRepository) NewYoutube(ctx context.Context, file_id entities.FileID, youtube *entities.Youtube) error {
+// NewProjectDescription mocks base method.
+func (m *MockProjectRepository) NewProjectDescription(ctx context.Context, uuid entities.ProjectUUID, description string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "NewYoutube", ctx, file_id, youtube)
+	ret := m.ctrl.Call(m, "NewProjectDescription", ctx, uuid, description)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// NewYoutube indicates an expected call of NewYoutube.
-func (mr *MockYoutubeRepositoryMockRecorder) NewYoutube(ctx, file_id, youtube any) *gomock.Call {
+// NewProjectDescription indicates an expected call of NewProjectDescription.
+func (mr *MockProjectRepositoryMockRecorder) NewProjectDescription(ctx, uuid, description any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewYoutube", reflect.TypeOf((*MockYoutubeRepository)(nil).NewYoutube), ctx, file_id, youtube)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewProjectDescription", reflect.TypeOf((*MockProjectRepository)(nil).NewProjectDescription), ctx, uuid, description)
 }
 
-// NewYoutubeVideo mocks base method.
-func (m *MockYoutubeRepository) NewYoutubeVideo(ctx context.Context, file_id entities.FileID, youtube_video *entities.YoutubeVideo) error {
+// NewProjectPart mocks base method.
+func (m *MockProjectRepository) NewProjectPart(ctx context.Context, part *entities.ProjectPart) (int64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "NewYoutubeVideo", ctx, file_id, youtube_video)
+	ret := m.ctrl.Call(m, "NewProjectPart", ctx, part)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// NewProjectPart indicates an expected call of NewProjectPart.
+func (mr *MockProjectRepositoryMockRecorder) NewProjectPart(ctx, part any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewProjectPart", reflect.TypeOf((*MockProjectRepository)(nil).NewProjectPart), ctx, part)
+}
+
+// NewProjectRelation mocks base method.
+func (m *MockProjectRepository) NewProjectRelation(ctx context.Context, relation entities.ProjectRelation) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NewProjectRelation", ctx, relation)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// NewYoutubeVideo indicates an expected call of NewYoutubeVideo.
-func (mr *MockYoutubeRepositoryMockRecorder) NewYoutubeVideo(ctx, file_id, youtube_video any) *gomock.Call {
+// NewProjectRelation indicates an expected call of NewProjectRelation.
+func (mr *MockProjectRepositoryMockRecorder) NewProjectRelation(ctx, relation any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewYoutubeVideo", reflect.TypeOf((*MockYoutubeRepository)(nil).NewYoutubeVideo), ctx, file_id, youtube_video)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewProjectRelation", reflect.TypeOf((*MockProjectRepository)(nil).NewProjectRelation), ctx, relation)
+}
+
+// NewProjectStatus mocks base method.
+func (m *MockProjectRepository) NewProjectStatus(ctx context.Context, uuid entities.ProjectUUID, change entities.ProjectStatusChange, check func([]entities.ProjectStatusChange) error) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NewProjectStatus", ctx, uuid, change, check)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// NewProjectStatus indicates an expected call of NewProjectStatus.
+func (mr *MockProjectRepositoryMockRecorder) NewProjectStatus(ctx, uuid, change, check any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewProjectStatus", reflect.TypeOf((*MockProjectRepository)(nil).NewProjectStatus), ctx, uuid, change, check)
+}
+
+// NewProjectTitle mocks base method.
+func (m *MockProjectRepository) NewProjectTitle(ctx context.Context, uuid entities.ProjectUUID, title string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NewProjectTitle", ctx, uuid, title)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// NewProjectTitle indicates an expected call of NewProjectTitle.
+func (mr *MockProjectRepositoryMockRecorder) NewProjectTitle(ctx, uuid, title any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewProjectTitle", reflect.TypeOf((*MockProjectRepository)(nil).NewProjectTitle), ctx, uuid, title)
+}
+
+// SetProjectDateAnnounced mocks base method.
+func (m *MockProjectRepository) SetProjectDateAnnounced(ctx context.Context, uuid entities.ProjectUUID, date entities.PartialDate) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetProjectDateAnnounced", ctx, uuid, date)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetProjectDateAnnounced indicates an expected call of SetProjectDateAnnounced.
+func (mr *MockProjectRepositoryMockRecorder) SetProjectDateAnnounced(ctx, uuid, date any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetProjectDateAnnounced", reflect.TypeOf((*MockProjectRepository)(nil).SetProjectDateAnnounced), ctx, uuid, date)
+}
+
+// SetProjectDateCompleted mocks base method.
+func (m *MockProjectRepository) SetProjectDateCompleted(ctx context.Context, uuid entities.ProjectUUID, date entities.PartialDate) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetProjectDateCompleted", ctx, uuid, date)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetProjectDateCompleted indicates an expected call of SetProjectDateCompleted.
+func (mr *MockProjectRepositoryMockRecorder) SetProjectDateCompleted(ctx, uuid, date any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetProjectDateCompleted", reflect.TypeOf((*MockProjectRepository)(nil).SetProjectDateCompleted), ctx, uuid, date)
+}
+
+// SetProjectType mocks base method.
+func (m *MockProjectRepository) SetProjectType(ctx context.Context, uuid entities.ProjectUUID, project_type entities.ProjectType) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetProjectType", ctx, uuid, project_type)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetProjectType indicates an expected call of SetProjectType.
+func (mr *MockProjectRepositoryMockRecorder) SetProjectType(ctx, uuid, project_type any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetProjectType", reflect.TypeOf((*MockProjectRepository)(nil).SetProjectType), ctx, uuid, project_type)
+}
+
+// UnassignProjectVideo mocks base method.
+func (m *MockProjectRepository) UnassignProjectVideo(ctx context.Context, uuid entities.ProjectUUID, file_id entities.FileID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnassignProjectVideo", ctx, uuid, file_id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UnassignProjectVideo indicates an expected call of UnassignProjectVideo.
+func (mr *MockProjectRepositoryMockRecorder) UnassignProjectVideo(ctx, uuid, file_id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnassignProjectVideo", reflect.TypeOf((*MockProjectRepository)(nil).UnassignProjectVideo), ctx, uuid, file_id)
+}
+
+// UnassignYoutube mocks base method.
+func (m *MockProjectRepository) UnassignYoutube(ctx context.Context, project_uuid entities.ProjectUUID, youtube_id entities.YoutubeVideoID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnassignYoutube", ctx, project_uuid, youtube_id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UnassignYoutube indicates an expected call of UnassignYoutube.
+func (mr *MockProjectRepositoryMockRecorder) UnassignYoutube(ctx, project_uuid, youtube_id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnassignYoutube", reflect.TypeOf((*MockProjectRepository)(nil).UnassignYoutube), ctx, project_uuid, youtube_id)
+}
+
+// UpdateProjectPart mocks base method.
+func (m *MockProjectRepository) UpdateProjectPart(ctx context.Context, part *entities.ProjectPart) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateProjectPart", ctx, part)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateProjectPart indicates an expected call of UpdateProjectPart.
+func (mr *MockProjectRepositoryMockRecorder) UpdateProjectPart(ctx, part any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateProjectPart", reflect.TypeOf((*MockProjectRepository)(nil).UpdateProjectPart), ctx, part)
+}
+
+// MockYoutubeRepository is a mock of YoutubeRepository interface.
+type MockYoutubeRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockYoutubeRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockYoutubeRepositoryMockRecorder is the mock recorder for MockYoutubeRepository.
+type MockYoutubeRepositoryMockRecorder struct {
+	mock *MockYoutubeRepository
+}
+
+// NewMockYoutubeRepository creates a new mock instance.
+func NewMockYoutubeRepository(ctrl *gomock.Controller) *MockYoutubeRepository {
+	mock := &MockYoutubeRepository{ctrl: ctrl}
+	mock.recorder = &MockYoutubeRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockYoutubeRepository) EXPECT() *MockYoutubeRepositoryMockRecorder {
+	return m.recorder
+}
+
+// AssignYoutubeFile mocks base method.
+func (m *MockYoutubeRepository) AssignYoutubeFile(ctx context.Context, youtube_id entities.YoutubeVideoID, file_id entities.FileID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AssignYoutubeFile", ctx, youtube_id, file_id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AssignYoutubeFile indicates an expected call of AssignYoutubeFile.
+func (mr *MockYoutubeRepositoryMockRecorder) AssignYoutubeFile(ctx, youtube_id, file_id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AssignYoutubeFile", reflect.TypeOf((*MockYoutubeRepository)(nil).AssignYoutubeFile), ctx, youtube_id, file_id)
+}
+
+// GetChannelVideos mocks base method.
+func (m *MockYoutubeRepository) GetChannelVideos(ctx context.Context, channel_id entities.YoutubeChannelID) ([]entities.YoutubeVideoID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetChannelVideos", ctx, channel_id)
+	ret0, _ := ret[0].([]entities.YoutubeVideoID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetChannelVideos indicates an expected call of GetChannelVideos.
+func (mr *MockYoutubeRepositoryMockRecorder) GetChannelVideos(ctx, channel_id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetChannelVideos", reflect.TypeOf((*MockYoutubeRepository)(nil).GetChannelVideos), ctx, channel_id)
+}
+
+// GetDescription mocks base method.
+func (m *MockYoutubeRepository) GetDescription(ctx context.Context, youtube_id entities.YoutubeVideoID) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDescription", ctx, youtube_id)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDescription indicates an expected call of GetDescription.
+func (mr *MockYoutubeRepositoryMockRecorder) GetDescription(ctx, youtube_id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDescription", reflect.TypeOf((*MockYoutubeRepository)(nil).GetDescription), ctx, youtube_id)
+}
+
+// GetFileYoutubeID mocks base method.
+func (m *MockYoutubeRepository) GetFileYoutubeID(ctx context.Context, file_id entities.FileID) (entities.YoutubeVideoID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFileYoutubeID", ctx, file_id)
+	ret0, _ := ret[0].(entities.YoutubeVideoID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFileYoutubeID indicates an expected call of GetFileYoutubeID.
+func (mr *MockYoutubeRepositoryMockRecorder) GetFileYoutubeID(ctx, file_id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFileYoutubeID", reflect.TypeOf((*MockYoutubeRepository)(nil).GetFileYoutubeID), ctx, file_id)
+}
+
+// GetThumbnail mocks base method.
+func (m *MockYoutubeRepository) GetThumbnail(ctx context.Context, file_id entities.FileID) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetThumbnail", ctx, file_id)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetThumbnail indicates an expected call of GetThumbnail.
+func (mr *MockYoutubeRepositoryMockRecorder) GetThumbnail(ctx, file_id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetThumbnail", reflect.TypeOf((*MockYoutubeRepository)(nil).GetThumbnail), ctx, file_id)
+}
+
+// GetTitle mocks base method.
+func (m *MockYoutubeRepository) GetTitle(ctx context.Context, youtube_id entities.YoutubeVideoID) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTitle", ctx, youtube_id)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTitle indicates an expected call of GetTitle.
+func (mr *MockYoutubeRepositoryMockRecorder) GetTitle(ctx, youtube_id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTitle", reflect.TypeOf((*MockYoutubeRepository)(nil).GetTitle), ctx, youtube_id)
+}
+
+// GetYoutube mocks base method.
+func (m *MockYoutubeRepository) GetYoutube(ctx context.Context, youtube_id entities.YoutubeVideoID) (*entities.Youtube, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetYoutube", ctx, youtube_id)
+	ret0, _ := ret[0].(*entities.Youtube)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetYoutube indicates an expected call of GetYoutube.
+func (mr *MockYoutubeRepositoryMockRecorder) GetYoutube(ctx, youtube_id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetYoutube", reflect.TypeOf((*MockYoutubeRepository)(nil).GetYoutube), ctx, youtube_id)
+}
+
+// GetYoutubeFileIDs mocks base method.
+func (m *MockYoutubeRepository) GetYoutubeFileIDs(ctx context.Context, youtube_id entities.YoutubeVideoID) ([]entities.FileID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetYoutubeFileIDs", ctx, youtube_id)
+	ret0, _ := ret[0].([]entities.FileID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetYoutubeFileIDs indicates an expected call of GetYoutubeFileIDs.
+func (mr *MockYoutubeRepositoryMockRecorder) GetYoutubeFileIDs(ctx, youtube_id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetYoutubeFileIDs", reflect.TypeOf((*MockYoutubeRepository)(nil).GetYoutubeFileIDs), ctx, youtube_id)
+}
+
+// GetYoutubeVideo mocks base method.
+func (m *MockYoutubeRepository) GetYoutubeVideo(ctx context.Context, youtube_id entities.YoutubeVideoID) (*entities.YoutubeVideo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetYoutubeVideo", ctx, youtube_id)
+	ret0, _ := ret[0].(*entities.YoutubeVideo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetYoutubeVideo indicates an expected call of GetYoutubeVideo.
+func (mr *MockYoutubeRepositoryMockRecorder) GetYoutubeVideo(ctx, youtube_id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetYoutubeVideo", reflect.TypeOf((*MockYoutubeRepository)(nil).GetYoutubeVideo), ctx, youtube_id)
+}
+
+// GetYtdlpInfo mocks base method.
+func (m *MockYoutubeRepository) GetYtdlpInfo(ctx context.Context, file_id entities.FileID) (json.RawMessage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetYtdlpInfo", ctx, file_id)
+	ret0, _ := ret[0].(json.RawMessage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetYtdlpInfo indicates an expected call of GetYtdlpInfo.
+func (mr *MockYoutubeRepositoryMockRecorder) GetYtdlpInfo(ctx, file_id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetYtdlpInfo", reflect.TypeOf((*MockYoutubeRepository)(nil).GetYtdlpInfo), ctx, file_id)
+}
+
+// ListChannels mocks base method.
+func (m *MockYoutubeRepository) ListChannels(ctx context.Context, opts entities.ListOptions) ([]entities.YoutubeChannelID, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListChannels", ctx, opts)
+	ret0, _ := ret[0].([]entities.YoutubeChannelID)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListChannels indicates an expected call of ListChannels.
+func (mr *MockYoutubeRepositoryMockRecorder) ListChannels(ctx, opts any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListChannels", reflect.TypeOf((*MockYoutubeRepository)(nil).ListChannels), ctx, opts)
+}
+
+// ListYoutube mocks base method.
+func (m *MockYoutubeRepository) ListYoutube(ctx context.Context, f filter.Filter, opts entities.ListOptions) ([]entities.YoutubeVideoID, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListYoutube", ctx, f, opts)
+	ret0, _ := ret[0].([]entities.YoutubeVideoID)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListYoutube indicates an expected call of ListYoutube.
+func (mr *MockYoutubeRepositoryMockRecorder) ListYoutube(ctx, f, opts any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListYoutube", reflect.TypeOf((*MockYoutubeRepository)(nil).ListYoutube), ctx, f, opts)
+}
+
+// NewYoutube mocks base method.
+func (m *MockYoutubeRepository) NewYoutube(ctx context.Context, file_id entities.FileID, youtube *entities.Youtube) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NewYoutube", ctx, file_id, youtube)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// NewYoutube indicates an expected call of NewYoutube.
+func (mr *MockYoutubeRepositoryMockRecorder) NewYoutube(ctx, file_id, youtube any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewYoutube", reflect.TypeOf((*MockYoutubeRepository)(nil).NewYoutube), ctx, file_id, youtube)
+}
+
+// NewYoutubeVideo mocks base method.
+func (m *MockYoutubeRepository) NewYoutubeVideo(ctx context.Context, file_id entities.FileID, youtube_video *entities.YoutubeVideo) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NewYoutubeVideo", ctx, file_id, youtube_video)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// NewYoutubeVideo indicates an expected call of NewYoutubeVideo.
+func (mr *MockYoutubeRepositoryMockRecorder) NewYoutubeVideo(ctx, file_id, youtube_video any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewYoutubeVideo", reflect.TypeOf((*MockYoutubeRepository)(nil).NewYoutubeVideo), ctx, file_id, youtube_video)
+}
+
+// MockProbeRepository is a mock of ProbeRepository interface.
+type MockProbeRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockProbeRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockProbeRepositoryMockRecorder is the mock recorder for MockProbeRepository.
+type MockProbeRepositoryMockRecorder struct {
+	mock *MockProbeRepository
+}
+
+// NewMockProbeRepository creates a new mock instance.
+func NewMockProbeRepository(ctrl *gomock.Controller) *MockProbeRepository {
+	mock := &MockProbeRepository{ctrl: ctrl}
+	mock.recorder = &MockProbeRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockProbeRepository) EXPECT() *MockProbeRepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetAllProbeMismatches mocks base method.
+func (m *MockProbeRepository) GetAllProbeMismatches(ctx context.Context) ([]entities.ProbeMismatch, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAllProbeMismatches", ctx)
+	ret0, _ := ret[0].([]entities.ProbeMismatch)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAllProbeMismatches indicates an expected call of GetAllProbeMismatches.
+func (mr *MockProbeRepositoryMockRecorder) GetAllProbeMismatches(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllProbeMismatches", reflect.TypeOf((*MockProbeRepository)(nil).GetAllProbeMismatches), ctx)
+}
+
+// GetClaimedVideo mocks base method.
+func (m *MockProbeRepository) GetClaimedVideo(ctx context.Context, file_id entities.FileID) (*entities.Video, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetClaimedVideo", ctx, file_id)
+	ret0, _ := ret[0].(*entities.Video)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetClaimedVideo indicates an expected call of GetClaimedVideo.
+func (mr *MockProbeRepositoryMockRecorder) GetClaimedVideo(ctx, file_id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetClaimedVideo", reflect.TypeOf((*MockProbeRepository)(nil).GetClaimedVideo), ctx, file_id)
+}
+
+// GetProbe mocks base method.
+func (m *MockProbeRepository) GetProbe(ctx context.Context, file_id entities.FileID) (*entities.MediaProbe, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProbe", ctx, file_id)
+	ret0, _ := ret[0].(*entities.MediaProbe)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetProbe indicates an expected call of GetProbe.
+func (mr *MockProbeRepositoryMockRecorder) GetProbe(ctx, file_id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProbe", reflect.TypeOf((*MockProbeRepository)(nil).GetProbe), ctx, file_id)
+}
+
+// GetProbeMismatches mocks base method.
+func (m *MockProbeRepository) GetProbeMismatches(ctx context.Context, file_id entities.FileID) ([]entities.ProbeMismatch, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProbeMismatches", ctx, file_id)
+	ret0, _ := ret[0].([]entities.ProbeMismatch)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetProbeMismatches indicates an expected call of GetProbeMismatches.
+func (mr *MockProbeRepositoryMockRecorder) GetProbeMismatches(ctx, file_id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProbeMismatches", reflect.TypeOf((*MockProbeRepository)(nil).GetProbeMismatches), ctx, file_id)
+}
+
+// GetUnprobedFiles mocks base method.
+func (m *MockProbeRepository) GetUnprobedFiles(ctx context.Context) ([]entities.FileID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUnprobedFiles", ctx)
+	ret0, _ := ret[0].([]entities.FileID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUnprobedFiles indicates an expected call of GetUnprobedFiles.
+func (mr *MockProbeRepositoryMockRecorder) GetUnprobedFiles(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUnprobedFiles", reflect.TypeOf((*MockProbeRepository)(nil).GetUnprobedFiles), ctx)
+}
+
+// NewProbe mocks base method.
+func (m *MockProbeRepository) NewProbe(ctx context.Context, file_id entities.FileID, probe *entities.MediaProbe, mismatches []entities.ProbeMismatch) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NewProbe", ctx, file_id, probe, mismatches)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// NewProbe indicates an expected call of NewProbe.
+func (mr *MockProbeRepositoryMockRecorder) NewProbe(ctx, file_id, probe, mismatches any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewProbe", reflect.TypeOf((*MockProbeRepository)(nil).NewProbe), ctx, file_id, probe, mismatches)
+}
+
+// MockFingerprintRepository is a mock of FingerprintRepository interface.
+type MockFingerprintRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockFingerprintRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockFingerprintRepositoryMockRecorder is the mock recorder for MockFingerprintRepository.
+type MockFingerprintRepositoryMockRecorder struct {
+	mock *MockFingerprintRepository
+}
+
+// NewMockFingerprintRepository creates a new mock instance.
+func NewMockFingerprintRepository(ctrl *gomock.Controller) *MockFingerprintRepository {
+	mock := &MockFingerprintRepository{ctrl: ctrl}
+	mock.recorder = &MockFingerprintRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockFingerprintRepository) EXPECT() *MockFingerprintRepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetFingerprint mocks base method.
+func (m *MockFingerprintRepository) GetFingerprint(ctx context.Context, file_id entities.FileID) (*entities.Fingerprint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFingerprint", ctx, file_id)
+	ret0, _ := ret[0].(*entities.Fingerprint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFingerprint indicates an expected call of GetFingerprint.
+func (mr *MockFingerprintRepositoryMockRecorder) GetFingerprint(ctx, file_id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFingerprint", reflect.TypeOf((*MockFingerprintRepository)(nil).GetFingerprint), ctx, file_id)
+}
+
+// GetFingerprintsByDuration mocks base method.
+func (m *MockFingerprintRepository) GetFingerprintsByDuration(ctx context.Context, min_duration, max_duration float64) (map[entities.FileID]entities.Fingerprint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFingerprintsByDuration", ctx, min_duration, max_duration)
+	ret0, _ := ret[0].(map[entities.FileID]entities.Fingerprint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFingerprintsByDuration indicates an expected call of GetFingerprintsByDuration.
+func (mr *MockFingerprintRepositoryMockRecorder) GetFingerprintsByDuration(ctx, min_duration, max_duration any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFingerprintsByDuration", reflect.TypeOf((*MockFingerprintRepository)(nil).GetFingerprintsByDuration), ctx, min_duration, max_duration)
+}
+
+// GetUnfingerprintedFiles mocks base method.
+func (m *MockFingerprintRepository) GetUnfingerprintedFiles(ctx context.Context) ([]entities.FileID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUnfingerprintedFiles", ctx)
+	ret0, _ := ret[0].([]entities.FileID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUnfingerprintedFiles indicates an expected call of GetUnfingerprintedFiles.
+func (mr *MockFingerprintRepositoryMockRecorder) GetUnfingerprintedFiles(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUnfingerprintedFiles", reflect.TypeOf((*MockFingerprintRepository)(nil).GetUnfingerprintedFiles), ctx)
+}
+
+// NewFingerprint mocks base method.
+func (m *MockFingerprintRepository) NewFingerprint(ctx context.Context, file_id entities.FileID, fingerprint *entities.Fingerprint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NewFingerprint", ctx, file_id, fingerprint)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// NewFingerprint indicates an expected call of NewFingerprint.
+func (mr *MockFingerprintRepositoryMockRecorder) NewFingerprint(ctx, file_id, fingerprint any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewFingerprint", reflect.TypeOf((*MockFingerprintRepository)(nil).NewFingerprint), ctx, file_id, fingerprint)
+}
+
+// MockArtistRepository is a mock of ArtistRepository interface.
+type MockArtistRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockArtistRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockArtistRepositoryMockRecorder is the mock recorder for MockArtistRepository.
+type MockArtistRepositoryMockRecorder struct {
+	mock *MockArtistRepository
+}
+
+// NewMockArtistRepository creates a new mock instance.
+func NewMockArtistRepository(ctrl *gomock.Controller) *MockArtistRepository {
+	mock := &MockArtistRepository{ctrl: ctrl}
+	mock.recorder = &MockArtistRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockArtistRepository) EXPECT() *MockArtistRepositoryMockRecorder {
+	return m.recorder
+}
+
+// AssignArtistChannel mocks base method.
+func (m *MockArtistRepository) AssignArtistChannel(ctx context.Context, artist_name string, channel_id entities.YoutubeChannelID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AssignArtistChannel", ctx, artist_name, channel_id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AssignArtistChannel indicates an expected call of AssignArtistChannel.
+func (mr *MockArtistRepositoryMockRecorder) AssignArtistChannel(ctx, artist_name, channel_id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AssignArtistChannel", reflect.TypeOf((*MockArtistRepository)(nil).AssignArtistChannel), ctx, artist_name, channel_id)
+}
+
+// DeleteArtist mocks base method.
+func (m *MockArtistRepository) DeleteArtist(ctx context.Context, artist_name string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteArtist", ctx, artist_name)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteArtist indicates an expected call of DeleteArtist.
+func (mr *MockArtistRepositoryMockRecorder) DeleteArtist(ctx, artist_name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteArtist", reflect.TypeOf((*MockArtistRepository)(nil).DeleteArtist), ctx, artist_name)
+}
+
+// DeleteArtistAlias mocks base method.
+func (m *MockArtistRepository) DeleteArtistAlias(ctx context.Context, artist_name, alias string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteArtistAlias", ctx, artist_name, alias)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteArtistAlias indicates an expected call of DeleteArtistAlias.
+func (mr *MockArtistRepositoryMockRecorder) DeleteArtistAlias(ctx, artist_name, alias any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteArtistAlias", reflect.TypeOf((*MockArtistRepository)(nil).DeleteArtistAlias), ctx, artist_name, alias)
+}
+
+// GetArtist mocks base method.
+func (m *MockArtistRepository) GetArtist(ctx context.Context, artist_name string) (*entities.Artist, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetArtist", ctx, artist_name)
+	ret0, _ := ret[0].(*entities.Artist)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetArtist indicates an expected call of GetArtist.
+func (mr *MockArtistRepositoryMockRecorder) GetArtist(ctx, artist_name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetArtist", reflect.TypeOf((*MockArtistRepository)(nil).GetArtist), ctx, artist_name)
+}
+
+// GetArtistParts mocks base method.
+func (m *MockArtistRepository) GetArtistParts(ctx context.Context, artist_name string) ([]entities.ProjectPart, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetArtistParts", ctx, artist_name)
+	ret0, _ := ret[0].([]entities.ProjectPart)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetArtistParts indicates an expected call of GetArtistParts.
+func (mr *MockArtistRepositoryMockRecorder) GetArtistParts(ctx, artist_name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetArtistParts", reflect.TypeOf((*MockArtistRepository)(nil).GetArtistParts), ctx, artist_name)
+}
+
+// GetArtistVideos mocks base method.
+func (m *MockArtistRepository) GetArtistVideos(ctx context.Context, artist_name string) ([]entities.YoutubeVideoID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetArtistVideos", ctx, artist_name)
+	ret0, _ := ret[0].([]entities.YoutubeVideoID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetArtistVideos indicates an expected call of GetArtistVideos.
+func (mr *MockArtistRepositoryMockRecorder) GetArtistVideos(ctx, artist_name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetArtistVideos", reflect.TypeOf((*MockArtistRepository)(nil).GetArtistVideos), ctx, artist_name)
+}
+
+// GetChannelArtists mocks base method.
+func (m *MockArtistRepository) GetChannelArtists(ctx context.Context, channel_id entities.YoutubeChannelID) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetChannelArtists", ctx, channel_id)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetChannelArtists indicates an expected call of GetChannelArtists.
+func (mr *MockArtistRepositoryMockRecorder) GetChannelArtists(ctx, channel_id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetChannelArtists", reflect.TypeOf((*MockArtistRepository)(nil).GetChannelArtists), ctx, channel_id)
+}
+
+// NewArtist mocks base method.
+func (m *MockArtistRepository) NewArtist(ctx context.Context, artist_name string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NewArtist", ctx, artist_name)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// NewArtist indicates an expected call of NewArtist.
+func (mr *MockArtistRepositoryMockRecorder) NewArtist(ctx, artist_name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewArtist", reflect.TypeOf((*MockArtistRepository)(nil).NewArtist), ctx, artist_name)
+}
+
+// NewArtistAlias mocks base method.
+func (m *MockArtistRepository) NewArtistAlias(ctx context.Context, artist_name, alias string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NewArtistAlias", ctx, artist_name, alias)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// NewArtistAlias indicates an expected call of NewArtistAlias.
+func (mr *MockArtistRepositoryMockRecorder) NewArtistAlias(ctx, artist_name, alias any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewArtistAlias", reflect.TypeOf((*MockArtistRepository)(nil).NewArtistAlias), ctx, artist_name, alias)
+}
+
+// RenameArtist mocks base method.
+func (m *MockArtistRepository) RenameArtist(ctx context.Context, artist_name, new_name string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RenameArtist", ctx, artist_name, new_name)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RenameArtist indicates an expected call of RenameArtist.
+func (mr *MockArtistRepositoryMockRecorder) RenameArtist(ctx, artist_name, new_name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RenameArtist", reflect.TypeOf((*MockArtistRepository)(nil).RenameArtist), ctx, artist_name, new_name)
+}
+
+// UnassignArtistChannel mocks base method.
+func (m *MockArtistRepository) UnassignArtistChannel(ctx context.Context, artist_name string, channel_id entities.YoutubeChannelID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnassignArtistChannel", ctx, artist_name, channel_id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UnassignArtistChannel indicates an expected call of UnassignArtistChannel.
+func (mr *MockArtistRepositoryMockRecorder) UnassignArtistChannel(ctx, artist_name, channel_id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnassignArtistChannel", reflect.TypeOf((*MockArtistRepository)(nil).UnassignArtistChannel), ctx, artist_name, channel_id)
+}
+
+// MockMusicRepository is a mock of MusicRepository interface.
+type MockMusicRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockMusicRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockMusicRepositoryMockRecorder is the mock recorder for MockMusicRepository.
+type MockMusicRepositoryMockRecorder struct {
+	mock *MockMusicRepository
+}
+
+// NewMockMusicRepository creates a new mock instance.
+func NewMockMusicRepository(ctrl *gomock.Controller) *MockMusicRepository {
+	mock := &MockMusicRepository{ctrl: ctrl}
+	mock.recorder = &MockMusicRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMusicRepository) EXPECT() *MockMusicRepositoryMockRecorder {
+	return m.recorder
+}
+
+// AssignProjectMusic mocks base method.
+func (m *MockMusicRepository) AssignProjectMusic(ctx context.Context, uuid entities.ProjectUUID, music_id int32) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AssignProjectMusic", ctx, uuid, music_id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AssignProjectMusic indicates an expected call of AssignProjectMusic.
+func (mr *MockMusicRepositoryMockRecorder) AssignProjectMusic(ctx, uuid, music_id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AssignProjectMusic", reflect.TypeOf((*MockMusicRepository)(nil).AssignProjectMusic), ctx, uuid, music_id)
+}
+
+// DeleteMusic mocks base method.
+func (m *MockMusicRepository) DeleteMusic(ctx context.Context, music_id int32) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteMusic", ctx, music_id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteMusic indicates an expected call of DeleteMusic.
+func (mr *MockMusicRepositoryMockRecorder) DeleteMusic(ctx, music_id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteMusic", reflect.TypeOf((*MockMusicRepository)(nil).DeleteMusic), ctx, music_id)
+}
+
+// FindMusic mocks base method.
+func (m *MockMusicRepository) FindMusic(ctx context.Context, artist, title string, min_score float64, max_results int) ([]entities.MusicMatch, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindMusic", ctx, artist, title, min_score, max_results)
+	ret0, _ := ret[0].([]entities.MusicMatch)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindMusic indicates an expected call of FindMusic.
+func (mr *MockMusicRepositoryMockRecorder) FindMusic(ctx, artist, title, min_score, max_results any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindMusic", reflect.TypeOf((*MockMusicRepository)(nil).FindMusic), ctx, artist, title, min_score, max_results)
+}
+
+// GetMusic mocks base method.
+func (m *MockMusicRepository) GetMusic(ctx context.Context, music_id int32) (*entities.Music, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMusic", ctx, music_id)
+	ret0, _ := ret[0].(*entities.Music)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMusic indicates an expected call of GetMusic.
+func (mr *MockMusicRepositoryMockRecorder) GetMusic(ctx, music_id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMusic", reflect.TypeOf((*MockMusicRepository)(nil).GetMusic), ctx, music_id)
+}
+
+// GetMusicProjects mocks base method.
+func (m *MockMusicRepository) GetMusicProjects(ctx context.Context, music_id int32) ([]entities.ProjectUUID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMusicProjects", ctx, music_id)
+	ret0, _ := ret[0].([]entities.ProjectUUID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMusicProjects indicates an expected call of GetMusicProjects.
+func (mr *MockMusicRepositoryMockRecorder) GetMusicProjects(ctx, music_id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMusicProjects", reflect.TypeOf((*MockMusicRepository)(nil).GetMusicProjects), ctx, music_id)
+}
+
+// GetProjectMusic mocks base method.
+func (m *MockMusicRepository) GetProjectMusic(ctx context.Context, uuid entities.ProjectUUID) ([]entities.Music, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProjectMusic", ctx, uuid)
+	ret0, _ := ret[0].([]entities.Music)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetProjectMusic indicates an expected call of GetProjectMusic.
+func (mr *MockMusicRepositoryMockRecorder) GetProjectMusic(ctx, uuid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProjectMusic", reflect.TypeOf((*MockMusicRepository)(nil).GetProjectMusic), ctx, uuid)
+}
+
+// NewMusic mocks base method.
+func (m *MockMusicRepository) NewMusic(ctx context.Context, music *entities.Music) (int32, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NewMusic", ctx, music)
+	ret0, _ := ret[0].(int32)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// NewMusic indicates an expected call of NewMusic.
+func (mr *MockMusicRepositoryMockRecorder) NewMusic(ctx, music any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewMusic", reflect.TypeOf((*MockMusicRepository)(nil).NewMusic), ctx, music)
+}
+
+// UnassignProjectMusic mocks base method.
+func (m *MockMusicRepository) UnassignProjectMusic(ctx context.Context, uuid entities.ProjectUUID, music_id int32) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnassignProjectMusic", ctx, uuid, music_id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UnassignProjectMusic indicates an expected call of UnassignProjectMusic.
+func (mr *MockMusicRepositoryMockRecorder) UnassignProjectMusic(ctx, uuid, music_id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnassignProjectMusic", reflect.TypeOf((*MockMusicRepository)(nil).UnassignProjectMusic), ctx, uuid, music_id)
+}
+
+// UpdateMusic mocks base method.
+func (m *MockMusicRepository) UpdateMusic(ctx context.Context, music *entities.Music) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateMusic", ctx, music)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateMusic indicates an expected call of UpdateMusic.
+func (mr *MockMusicRepositoryMockRecorder) UpdateMusic(ctx, music any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateMusic", reflect.TypeOf((*MockMusicRepository)(nil).UpdateMusic), ctx, music)
+}
+
+// MockCharacterRepository is a mock of CharacterRepository interface.
+type MockCharacterRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockCharacterRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockCharacterRepositoryMockRecorder is the mock recorder for MockCharacterRepository.
+type MockCharacterRepositoryMockRecorder struct {
+	mock *MockCharacterRepository
+}
+
+// NewMockCharacterRepository creates a new mock instance.
+func NewMockCharacterRepository(ctrl *gomock.Controller) *MockCharacterRepository {
+	mock := &MockCharacterRepository{ctrl: ctrl}
+	mock.recorder = &MockCharacterRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCharacterRepository) EXPECT() *MockCharacterRepositoryMockRecorder {
+	return m.recorder
+}
+
+// DeleteCharacter mocks base method.
+func (m *MockCharacterRepository) DeleteCharacter(ctx context.Context, character_id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteCharacter", ctx, character_id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteCharacter indicates an expected call of DeleteCharacter.
+func (mr *MockCharacterRepositoryMockRecorder) DeleteCharacter(ctx, character_id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCharacter", reflect.TypeOf((*MockCharacterRepository)(nil).DeleteCharacter), ctx, character_id)
+}
+
+// DeleteCharacterAlias mocks base method.
+func (m *MockCharacterRepository) DeleteCharacterAlias(ctx context.Context, character_id int, name string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteCharacterAlias", ctx, character_id, name)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteCharacterAlias indicates an expected call of DeleteCharacterAlias.
+func (mr *MockCharacterRepositoryMockRecorder) DeleteCharacterAlias(ctx, character_id, name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCharacterAlias", reflect.TypeOf((*MockCharacterRepository)(nil).DeleteCharacterAlias), ctx, character_id, name)
+}
+
+// FindCharacters mocks base method.
+func (m *MockCharacterRepository) FindCharacters(ctx context.Context, name string) ([]entities.Character, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindCharacters", ctx, name)
+	ret0, _ := ret[0].([]entities.Character)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindCharacters indicates an expected call of FindCharacters.
+func (mr *MockCharacterRepositoryMockRecorder) FindCharacters(ctx, name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindCharacters", reflect.TypeOf((*MockCharacterRepository)(nil).FindCharacters), ctx, name)
+}
+
+// GetCharacter mocks base method.
+func (m *MockCharacterRepository) GetCharacter(ctx context.Context, character_id int) (*entities.Character, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCharacter", ctx, character_id)
+	ret0, _ := ret[0].(*entities.Character)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCharacter indicates an expected call of GetCharacter.
+func (mr *MockCharacterRepositoryMockRecorder) GetCharacter(ctx, character_id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCharacter", reflect.TypeOf((*MockCharacterRepository)(nil).GetCharacter), ctx, character_id)
+}
+
+// GetCharacterProjects mocks base method.
+func (m *MockCharacterRepository) GetCharacterProjects(ctx context.Context, character_id int) ([]entities.ProjectUUID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCharacterProjects", ctx, character_id)
+	ret0, _ := ret[0].([]entities.ProjectUUID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCharacterProjects indicates an expected call of GetCharacterProjects.
+func (mr *MockCharacterRepositoryMockRecorder) GetCharacterProjects(ctx, character_id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCharacterProjects", reflect.TypeOf((*MockCharacterRepository)(nil).GetCharacterProjects), ctx, character_id)
+}
+
+// GetPartCharacters mocks base method.
+func (m *MockCharacterRepository) GetPartCharacters(ctx context.Context, part_id int64) ([]entities.Character, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPartCharacters", ctx, part_id)
+	ret0, _ := ret[0].([]entities.Character)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPartCharacters indicates an expected call of GetPartCharacters.
+func (mr *MockCharacterRepositoryMockRecorder) GetPartCharacters(ctx, part_id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPartCharacters", reflect.TypeOf((*MockCharacterRepository)(nil).GetPartCharacters), ctx, part_id)
+}
+
+// GetProjectCharacters mocks base method.
+func (m *MockCharacterRepository) GetProjectCharacters(ctx context.Context, uuid entities.ProjectUUID) ([]entities.Character, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProjectCharacters", ctx, uuid)
+	ret0, _ := ret[0].([]entities.Character)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetProjectCharacters indicates an expected call of GetProjectCharacters.
+func (mr *MockCharacterRepositoryMockRecorder) GetProjectCharacters(ctx, uuid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProjectCharacters", reflect.TypeOf((*MockCharacterRepository)(nil).GetProjectCharacters), ctx, uuid)
+}
+
+// GetSeriesCharacters mocks base method.
+func (m *MockCharacterRepository) GetSeriesCharacters(ctx context.Context, series string) ([]entities.Character, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSeriesCharacters", ctx, series)
+	ret0, _ := ret[0].([]entities.Character)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSeriesCharacters indicates an expected call of GetSeriesCharacters.
+func (mr *MockCharacterRepositoryMockRecorder) GetSeriesCharacters(ctx, series any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSeriesCharacters", reflect.TypeOf((*MockCharacterRepository)(nil).GetSeriesCharacters), ctx, series)
+}
+
+// NewCharacter mocks base method.
+func (m *MockCharacterRepository) NewCharacter(ctx context.Context, character *entities.Character) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NewCharacter", ctx, character)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// NewCharacter indicates an expected call of NewCharacter.
+func (mr *MockCharacterRepositoryMockRecorder) NewCharacter(ctx, character any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewCharacter", reflect.TypeOf((*MockCharacterRepository)(nil).NewCharacter), ctx, character)
+}
+
+// NewCharacterAlias mocks base method.
+func (m *MockCharacterRepository) NewCharacterAlias(ctx context.Context, character_id int, alias entities.CharacterAlias) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NewCharacterAlias", ctx, character_id, alias)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// NewCharacterAlias indicates an expected call of NewCharacterAlias.
+func (mr *MockCharacterRepositoryMockRecorder) NewCharacterAlias(ctx, character_id, alias any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewCharacterAlias", reflect.TypeOf((*MockCharacterRepository)(nil).NewCharacterAlias), ctx, character_id, alias)
+}
+
+// TagPartCharacter mocks base method.
+func (m *MockCharacterRepository) TagPartCharacter(ctx context.Context, part_id int64, character_id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TagPartCharacter", ctx, part_id, character_id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// TagPartCharacter indicates an expected call of TagPartCharacter.
+func (mr *MockCharacterRepositoryMockRecorder) TagPartCharacter(ctx, part_id, character_id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TagPartCharacter", reflect.TypeOf((*MockCharacterRepository)(nil).TagPartCharacter), ctx, part_id, character_id)
+}
+
+// TagProjectCharacter mocks base method.
+func (m *MockCharacterRepository) TagProjectCharacter(ctx context.Context, uuid entities.ProjectUUID, character_id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TagProjectCharacter", ctx, uuid, character_id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// TagProjectCharacter indicates an expected call of TagProjectCharacter.
+func (mr *MockCharacterRepositoryMockRecorder) TagProjectCharacter(ctx, uuid, character_id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TagProjectCharacter", reflect.TypeOf((*MockCharacterRepository)(nil).TagProjectCharacter), ctx, uuid, character_id)
+}
+
+// UntagPartCharacter mocks base method.
+func (m *MockCharacterRepository) UntagPartCharacter(ctx context.Context, part_id int64, character_id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UntagPartCharacter", ctx, part_id, character_id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UntagPartCharacter indicates an expected call of UntagPartCharacter.
+func (mr *MockCharacterRepositoryMockRecorder) UntagPartCharacter(ctx, part_id, character_id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UntagPartCharacter", reflect.TypeOf((*MockCharacterRepository)(nil).UntagPartCharacter), ctx, part_id, character_id)
+}
+
+// UntagProjectCharacter mocks base method.
+func (m *MockCharacterRepository) UntagProjectCharacter(ctx context.Context, uuid entities.ProjectUUID, character_id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UntagProjectCharacter", ctx, uuid, character_id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UntagProjectCharacter indicates an expected call of UntagProjectCharacter.
+func (mr *MockCharacterRepositoryMockRecorder) UntagProjectCharacter(ctx, uuid, character_id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UntagProjectCharacter", reflect.TypeOf((*MockCharacterRepository)(nil).UntagProjectCharacter), ctx, uuid, character_id)
+}
+
+// UpdateCharacter mocks base method.
+func (m *MockCharacterRepository) UpdateCharacter(ctx context.Context, character *entities.Character) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateCharacter", ctx, character)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateCharacter indicates an expected call of UpdateCharacter.
+func (mr *MockCharacterRepositoryMockRecorder) UpdateCharacter(ctx, character any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCharacter", reflect.TypeOf((*MockCharacterRepository)(nil).UpdateCharacter), ctx, character)
+}
+
+// MockSearchRepository is a mock of SearchRepository interface.
+type MockSearchRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockSearchRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockSearchRepositoryMockRecorder is the mock recorder for MockSearchRepository.
+type MockSearchRepositoryMockRecorder struct {
+	mock *MockSearchRepository
+}
+
+// NewMockSearchRepository creates a new mock instance.
+func NewMockSearchRepository(ctrl *gomock.Controller) *MockSearchRepository {
+	mock := &MockSearchRepository{ctrl: ctrl}
+	mock.recorder = &MockSearchRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSearchRepository) EXPECT() *MockSearchRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Search mocks base method.
+func (m *MockSearchRepository) Search(ctx context.Context, query string, kind entities.SearchKind, max_results int) ([]entities.SearchResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Search", ctx, query, kind, max_results)
+	ret0, _ := ret[0].([]entities.SearchResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Search indicates an expected call of Search.
+func (mr *MockSearchRepositoryMockRecorder) Search(ctx, query, kind, max_results any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Search", reflect.TypeOf((*MockSearchRepository)(nil).Search), ctx, query, kind, max_results)
+}
+
+// MockAuditRepository is a mock of AuditRepository interface.
+type MockAuditRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockAuditRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockAuditRepositoryMockRecorder is the mock recorder for MockAuditRepository.
+type MockAuditRepositoryMockRecorder struct {
+	mock *MockAuditRepository
+}
+
+// NewMockAuditRepository creates a new mock instance.
+func NewMockAuditRepository(ctrl *gomock.Controller) *MockAuditRepository {
+	mock := &MockAuditRepository{ctrl: ctrl}
+	mock.recorder = &MockAuditRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAuditRepository) EXPECT() *MockAuditRepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetAuditEntries mocks base method.
+func (m *MockAuditRepository) GetAuditEntries(ctx context.Context, entity entities.AuditEntity, entity_id string) ([]entities.AuditEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAuditEntries", ctx, entity, entity_id)
+	ret0, _ := ret[0].([]entities.AuditEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAuditEntries indicates an expected call of GetAuditEntries.
+func (mr *MockAuditRepositoryMockRecorder) GetAuditEntries(ctx, entity, entity_id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAuditEntries", reflect.TypeOf((*MockAuditRepository)(nil).GetAuditEntries), ctx, entity, entity_id)
+}
+
+// GetAuditEntry mocks base method.
+func (m *MockAuditRepository) GetAuditEntry(ctx context.Context, entry_id int64) (*entities.AuditEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAuditEntry", ctx, entry_id)
+	ret0, _ := ret[0].(*entities.AuditEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAuditEntry indicates an expected call of GetAuditEntry.
+func (mr *MockAuditRepositoryMockRecorder) GetAuditEntry(ctx, entry_id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAuditEntry", reflect.TypeOf((*MockAuditRepository)(nil).GetAuditEntry), ctx, entry_id)
+}
+
+// NewAuditEntry mocks base method.
+func (m *MockAuditRepository) NewAuditEntry(ctx context.Context, entry *entities.AuditEntry) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NewAuditEntry", ctx, entry)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// NewAuditEntry indicates an expected call of NewAuditEntry.
+func (mr *MockAuditRepositoryMockRecorder) NewAuditEntry(ctx, entry any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewAuditEntry", reflect.TypeOf((*MockAuditRepository)(nil).NewAuditEntry), ctx, entry)
+}
+
+// MockFsckRepository is a mock of FsckRepository interface.
+type MockFsckRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockFsckRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockFsckRepositoryMockRecorder is the mock recorder for MockFsckRepository.
+type MockFsckRepositoryMockRecorder struct {
+	mock *MockFsckRepository
+}
+
+// NewMockFsckRepository creates a new mock instance.
+func NewMockFsckRepository(ctrl *gomock.Controller) *MockFsckRepository {
+	mock := &MockFsckRepository{ctrl: ctrl}
+	mock.recorder = &MockFsckRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockFsckRepository) EXPECT() *MockFsckRepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetFilesWithoutVideo mocks base method.
+func (m *MockFsckRepository) GetFilesWithoutVideo(ctx context.Context) ([]entities.FileID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFilesWithoutVideo", ctx)
+	ret0, _ := ret[0].([]entities.FileID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFilesWithoutVideo indicates an expected call of GetFilesWithoutVideo.
+func (mr *MockFsckRepositoryMockRecorder) GetFilesWithoutVideo(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFilesWithoutVideo", reflect.TypeOf((*MockFsckRepository)(nil).GetFilesWithoutVideo), ctx)
+}
+
+// GetMisattributedDownloads mocks base method.
+func (m *MockFsckRepository) GetMisattributedDownloads(ctx context.Context) ([]entities.YoutubeDownload, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMisattributedDownloads", ctx)
+	ret0, _ := ret[0].([]entities.YoutubeDownload)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMisattributedDownloads indicates an expected call of GetMisattributedDownloads.
+func (mr *MockFsckRepositoryMockRecorder) GetMisattributedDownloads(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMisattributedDownloads", reflect.TypeOf((*MockFsckRepository)(nil).GetMisattributedDownloads), ctx)
+}
+
+// GetProjectsWithoutFiles mocks base method.
+func (m *MockFsckRepository) GetProjectsWithoutFiles(ctx context.Context) ([]entities.ProjectUUID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProjectsWithoutFiles", ctx)
+	ret0, _ := ret[0].([]entities.ProjectUUID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetProjectsWithoutFiles indicates an expected call of GetProjectsWithoutFiles.
+func (mr *MockFsckRepositoryMockRecorder) GetProjectsWithoutFiles(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProjectsWithoutFiles", reflect.TypeOf((*MockFsckRepository)(nil).GetProjectsWithoutFiles), ctx)
+}
+
+// GetUnassignedDownloads mocks base method.
+func (m *MockFsckRepository) GetUnassignedDownloads(ctx context.Context) ([]entities.YoutubeDownload, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUnassignedDownloads", ctx)
+	ret0, _ := ret[0].([]entities.YoutubeDownload)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUnassignedDownloads indicates an expected call of GetUnassignedDownloads.
+func (mr *MockFsckRepositoryMockRecorder) GetUnassignedDownloads(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUnassignedDownloads", reflect.TypeOf((*MockFsckRepository)(nil).GetUnassignedDownloads), ctx)
+}
+
+// GetYoutubeWithoutFiles mocks base method.
+func (m *MockFsckRepository) GetYoutubeWithoutFiles(ctx context.Context) ([]entities.YoutubeVideoID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetYoutubeWithoutFiles", ctx)
+	ret0, _ := ret[0].([]entities.YoutubeVideoID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetYoutubeWithoutFiles indicates an expected call of GetYoutubeWithoutFiles.
+func (mr *MockFsckRepositoryMockRecorder) GetYoutubeWithoutFiles(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetYoutubeWithoutFiles", reflect.TypeOf((*MockFsckRepository)(nil).GetYoutubeWithoutFiles), ctx)
 }
 
 // MockVideoRepository is a mock of VideoRepository interface.
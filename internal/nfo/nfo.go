@@ -0,0 +1,76 @@
+// Package nfo writes the .nfo files Kodi and Jellyfin read the metadata of a video out of, so that a library of the
+// archive is tagged without anyone doing it by hand. An .nfo file is kept next to the video it describes, sharing its
+// name.
+package nfo
+
+import (
+	"encoding/xml"
+	"time"
+)
+
+// Extension is the extension of an .nfo file, replacing the extension of the video it describes.
+const Extension = ".nfo"
+
+// Movie describes a single video. Runtime is in minutes, and Premiered is left out when it's zero.
+type Movie struct {
+	Title     string
+	Plot      string
+	Premiered time.Time
+	Runtime   int
+	Studio    string
+	Genres    []string
+	UniqueIDs []UniqueID
+}
+
+// UniqueID is an id of the video elsewhere, such as its youtube video id. Type names where, and the Default id is the
+// one a media center looks the video up by.
+type UniqueID struct {
+	Type    string
+	ID      string
+	Default bool
+}
+
+type movieXML struct {
+	XMLName   xml.Name      `xml:"movie"`
+	Title     string        `xml:"title"`
+	Plot      string        `xml:"plot,omitempty"`
+	Premiered string        `xml:"premiered,omitempty"`
+	Year      int           `xml:"year,omitempty"`
+	Runtime   int           `xml:"runtime,omitempty"`
+	Studio    string        `xml:"studio,omitempty"`
+	Genres    []string      `xml:"genre"`
+	UniqueIDs []uniqueIDXML `xml:"uniqueid"`
+}
+
+type uniqueIDXML struct {
+	Type    string `xml:"type,attr"`
+	Default bool   `xml:"default,attr,omitempty"`
+	ID      string `xml:",chardata"`
+}
+
+// Marshal returns m as the contents of an .nfo file.
+func (m Movie) Marshal() ([]byte, error) {
+	x := movieXML{
+		Title:   m.Title,
+		Plot:    m.Plot,
+		Runtime: m.Runtime,
+		Studio:  m.Studio,
+		Genres:  m.Genres,
+	}
+
+	if !m.Premiered.IsZero() {
+		x.Premiered = m.Premiered.Format(time.DateOnly)
+		x.Year = m.Premiered.Year()
+	}
+
+	for _, id := range m.UniqueIDs {
+		x.UniqueIDs = append(x.UniqueIDs, uniqueIDXML{Type: id.Type, Default: id.Default, ID: id.ID})
+	}
+
+	contents, err := xml.MarshalIndent(x, "", "\t")
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]byte(xml.Header), append(contents, '\n')...), nil
+}
@@ -0,0 +1,56 @@
+package nfo_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dtbead/wc-maps-archive/internal/nfo"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestMovie_Marshal(t *testing.T) {
+	tests := []struct {
+		name  string
+		movie nfo.Movie
+		want  string
+	}{
+		{"youtube video", nfo.Movie{
+			Title:     "firestar MAP <complete>",
+			Plot:      "parts by\n& for everyone",
+			Premiered: time.Date(2015, 6, 21, 0, 0, 0, 0, time.UTC),
+			Runtime:   5,
+			Studio:    "leafstar",
+			Genres:    []string{"multi-animation", "animated music video"},
+			UniqueIDs: []nfo.UniqueID{{Type: "youtube", ID: "dQw4w9WgXcQ", Default: true}},
+		}, `<?xml version="1.0" encoding="UTF-8"?>
+<movie>
+	<title>firestar MAP &lt;complete&gt;</title>
+	<plot>parts by&#xA;&amp; for everyone</plot>
+	<premiered>2015-06-21</premiered>
+	<year>2015</year>
+	<runtime>5</runtime>
+	<studio>leafstar</studio>
+	<genre>multi-animation</genre>
+	<genre>animated music video</genre>
+	<uniqueid type="youtube" default="true">dQw4w9WgXcQ</uniqueid>
+</movie>
+`},
+		{"title only", nfo.Movie{Title: "untitled"}, `<?xml version="1.0" encoding="UTF-8"?>
+<movie>
+	<title>untitled</title>
+</movie>
+`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.movie.Marshal()
+			if err != nil {
+				t.Fatalf("Movie.Marshal() error = %v", err)
+			}
+
+			if diff := cmp.Diff(tt.want, string(got)); diff != "" {
+				t.Errorf("Movie.Marshal() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package ffprobe
+
+import (
+	"context"
+	"errors"
+	"os/exec"
+	"strings"
+
+	"github.com/dtbead/wc-maps-archive/internal/entities"
+)
+
+type Ffprobe struct {
+	binary string
+}
+
+// NewFfprobe returns a prober calling the ffprobe binary at binary_path. An empty binary_path looks up "ffprobe" in PATH instead.
+func NewFfprobe(binary_path string) Ffprobe {
+	if binary_path == "" {
+		binary_path = "ffprobe"
+	}
+
+	return Ffprobe{binary: binary_path}
+}
+
+// Probe returns the container and stream metadata of the media file at path, as reported by ffprobe.
+func (f Ffprobe) Probe(ctx context.Context, path string) (probe *entities.MediaProbe, err error) {
+	args := []string{
+		"-v", "error",
+		"-print_format", "json",
+		"-show_format",
+		"-show_streams",
+		"--",
+		path,
+	}
+
+	cmd := exec.CommandContext(ctx, f.binary, args...)
+	var stdout, stderr strings.Builder
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	err = cmd.Run()
+	if err != nil {
+		return nil, errors.Join(err, errors.New(stderr.String()))
+	}
+
+	m, err := newMetadata([]byte(stdout.String()))
+	if err != nil {
+		return nil, err
+	}
+
+	p, err := m.ToMediaProbe()
+	if err != nil {
+		return nil, err
+	}
+
+	return &p, nil
+}
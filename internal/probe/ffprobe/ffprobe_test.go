@@ -0,0 +1,161 @@
+package ffprobe_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"slices"
+	"testing"
+
+	"github.com/dtbead/wc-maps-archive/internal/entities"
+	"github.com/dtbead/wc-maps-archive/internal/probe/ffprobe"
+	"github.com/google/go-cmp/cmp"
+)
+
+// TestMain doubles as a fake ffprobe binary. When FAKE_FFPROBE_OUTPUT is set, the test binary prints that file
+// instead of running any tests, so that Ffprobe can be tested without ffprobe installed.
+func TestMain(m *testing.M) {
+	if output, ok := os.LookupEnv("FAKE_FFPROBE_OUTPUT"); ok {
+		os.Exit(fakeFfprobe(output))
+	}
+
+	os.Exit(m.Run())
+}
+
+func fakeFfprobe(output string) int {
+	// the probed path must always be separated from the flags, so that a path starting with "-" isn't parsed as one
+	args := os.Args[1:]
+	if len(args) < 2 || args[len(args)-2] != "--" {
+		fmt.Fprintf(os.Stderr, "missing -- before path in %v\n", args)
+		return 1
+	}
+
+	if !slices.Contains(args, "-show_streams") || !slices.Contains(args, "-show_format") {
+		fmt.Fprintf(os.Stderr, "missing -show_streams or -show_format in %v\n", args)
+		return 1
+	}
+
+	if output == "" {
+		fmt.Fprintf(os.Stderr, "%s: Invalid data found when processing input\n", args[len(args)-1])
+		return 1
+	}
+
+	b, err := os.ReadFile(output)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	os.Stdout.Write(b)
+	return 0
+}
+
+func TestFfprobe_Probe(t *testing.T) {
+	tests := []struct {
+		name    string
+		output  string
+		want    *entities.MediaProbe
+		wantErr bool
+	}{
+		{
+			name:   "video and audio",
+			output: "testdata/video_audio.json",
+			want: &entities.MediaProbe{
+				Container: "matroska,webm",
+				Duration:  212.161,
+				Bitrate:   823753,
+				Streams: []entities.MediaStream{
+					{Index: 0, CodecType: "video", Codec: "vp9", Width: 1280, Height: 720, Fps: 30000.0 / 1001.0},
+					{Index: 1, CodecType: "audio", Codec: "opus", SampleRate: 48000, Channels: 2},
+				},
+			},
+		},
+		{
+			name:   "video only without container duration",
+			output: "testdata/video_only.json",
+			want: &entities.MediaProbe{
+				Container: "mov,mp4,m4a,3gp,3g2,mj2",
+				Duration:  95.04,
+				Streams: []entities.MediaStream{
+					{Index: 0, CodecType: "video", Codec: "h264", Bitrate: 512034, Width: 640, Height: 360, Fps: 25},
+				},
+			},
+		},
+		{
+			name:   "audio only",
+			output: "testdata/audio_only.json",
+			want: &entities.MediaProbe{
+				Container: "mov,mp4,m4a,3gp,3g2,mj2",
+				Duration:  187.012,
+				Bitrate:   130712,
+				Streams: []entities.MediaStream{
+					{Index: 0, CodecType: "audio", Codec: "aac", Bitrate: 128000, SampleRate: 44100, Channels: 2},
+				},
+			},
+		},
+		{
+			name:    "unreadable file",
+			output:  "",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("FAKE_FFPROBE_OUTPUT", tt.output)
+
+			f := ffprobe.NewFfprobe(os.Args[0])
+			got, err := f.Probe(context.Background(), "-video.webm")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Ffprobe.Probe() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Ffprobe.Probe() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestMediaProbe_Video(t *testing.T) {
+	tests := []struct {
+		name    string
+		output  string
+		want    entities.Video
+		wantErr bool
+	}{
+		{
+			name:   "video and audio",
+			output: "testdata/video_audio.json",
+			want:   entities.Video{VideoCodec: "vp9", AudioCodec: "opus", Duration: 212, Width: 1280, Height: 720, Fps: 30},
+		},
+		{
+			name:   "video only",
+			output: "testdata/video_only.json",
+			want:   entities.Video{VideoCodec: "h264", Duration: 95, Width: 640, Height: 360, Fps: 25},
+		},
+		{
+			name:    "audio only",
+			output:  "testdata/audio_only.json",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("FAKE_FFPROBE_OUTPUT", tt.output)
+
+			probe, err := ffprobe.NewFfprobe(os.Args[0]).Probe(context.Background(), "video")
+			if err != nil {
+				t.Fatalf("Ffprobe.Probe() error = %v", err)
+			}
+
+			got, err := probe.Video()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("MediaProbe.Video() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("MediaProbe.Video() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
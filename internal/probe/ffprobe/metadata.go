@@ -0,0 +1,129 @@
+package ffprobe
+
+import (
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/dtbead/wc-maps-archive/internal/entities"
+)
+
+func newMetadata(ffprobeJSON []byte) (*metadata, error) {
+	m := new(metadata)
+	err := json.Unmarshal(ffprobeJSON, &m)
+	if err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+type metadata struct {
+	Streams []stream `json:"streams"`
+	Format  format   `json:"format"`
+}
+
+type stream struct {
+	Index         int    `json:"index"`
+	CodecType     string `json:"codec_type"`
+	CodecName     string `json:"codec_name"`
+	Width         int    `json:"width"`
+	Height        int    `json:"height"`
+	AvgFrameRate  string `json:"avg_frame_rate"`
+	RealFrameRate string `json:"r_frame_rate"`
+	Duration      string `json:"duration"`
+	BitRate       string `json:"bit_rate"`
+	SampleRate    string `json:"sample_rate"`
+	Channels      int    `json:"channels"`
+}
+
+type format struct {
+	FormatName string `json:"format_name"`
+	Duration   string `json:"duration"`
+	BitRate    string `json:"bit_rate"`
+}
+
+func (m metadata) ToMediaProbe() (entities.MediaProbe, error) {
+	if m.Format.FormatName == "" {
+		return entities.MediaProbe{}, errors.New("no container format found")
+	}
+
+	probe := entities.MediaProbe{
+		Container: m.Format.FormatName,
+		Bitrate:   parseInt(m.Format.BitRate),
+		Streams:   make([]entities.MediaStream, 0, len(m.Streams)),
+	}
+
+	var longest float64 = -1
+	for _, s := range m.Streams {
+		ms := entities.MediaStream{
+			Index:      s.Index,
+			CodecType:  s.CodecType,
+			Codec:      s.CodecName,
+			Bitrate:    parseInt(s.BitRate),
+			Width:      int16(s.Width),
+			Height:     int16(s.Height),
+			SampleRate: int(parseInt(s.SampleRate)),
+			Channels:   int16(s.Channels),
+		}
+
+		if s.CodecType == "video" {
+			// avg_frame_rate is 0/0 for some containers, in which case r_frame_rate is the best we've got
+			ms.Fps = parseRational(s.AvgFrameRate)
+			if ms.Fps <= 0 {
+				ms.Fps = parseRational(s.RealFrameRate)
+			}
+		}
+
+		if d, err := strconv.ParseFloat(s.Duration, 64); err == nil && d > longest {
+			longest = d
+		}
+
+		probe.Streams = append(probe.Streams, ms)
+	}
+
+	// prefer the container duration, as not every muxer writes a per-stream one
+	duration, err := strconv.ParseFloat(m.Format.Duration, 64)
+	if err != nil {
+		duration = longest
+	}
+
+	if duration < 0 {
+		return entities.MediaProbe{}, entities.ErrorInvalidDuration
+	}
+	probe.Duration = duration
+
+	return probe, nil
+}
+
+// parseInt parses a ffprobe integer such as "128000", returning 0 if it's missing or invalid.
+func parseInt(s string) int64 {
+	i, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || i < 0 {
+		return 0
+	}
+
+	return i
+}
+
+// parseRational parses a ffprobe rational such as "30000/1001", returning 0 if it's invalid.
+func parseRational(s string) float64 {
+	numerator, denominator, found := strings.Cut(s, "/")
+	if !found {
+		f, _ := strconv.ParseFloat(s, 64)
+		return f
+	}
+
+	n, err := strconv.ParseFloat(numerator, 64)
+	if err != nil {
+		return 0
+	}
+
+	d, err := strconv.ParseFloat(denominator, 64)
+	if err != nil || d == 0 {
+		return 0
+	}
+
+	return n / d
+}
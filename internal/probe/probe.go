@@ -0,0 +1,11 @@
+package probe
+
+import (
+	"context"
+
+	"github.com/dtbead/wc-maps-archive/internal/entities"
+)
+
+type Media interface {
+	Probe(ctx context.Context, path string) (probe *entities.MediaProbe, err error)
+}
@@ -0,0 +1,65 @@
+package server
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/dtbead/wc-maps-archive/internal/entities"
+	"github.com/labstack/echo/v4"
+)
+
+// ActorHeader names whoever is making a request, which is who its changes are recorded as made by in the audit log.
+// Requests without one are recorded as made by their remote address. It's whatever the client says it is and isn't
+// checked against anything, so the audit log only tells who made a change as far as clients can be trusted to say so.
+const ActorHeader = "X-Actor"
+
+// actor is middleware carrying the actor of a request along in its context.
+func actor(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		a := c.Request().Header.Get(ActorHeader)
+		if a == "" {
+			a = "http:" + c.RealIP()
+		}
+
+		r := c.Request()
+		c.SetRequest(r.WithContext(entities.WithActor(r.Context(), a)))
+		return next(c)
+	}
+}
+
+// audit serves GET /audit/:entity/:id, every change made to a project, file or youtube video oldest first, and
+// GET /audit/entry/:entry_id for a single change.
+func (s ServerController) audit() {
+	s.e.GET("/audit/entry/:entry_id", func(c echo.Context) error {
+		entry_id, err := strconv.ParseInt(c.Param("entry_id"), 10, 64)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, Message{Error: "invalid audit entry id"})
+		}
+
+		entry, err := s.service.AuditService.GetEntry(c.Request().Context(), entry_id)
+		if err != nil {
+			return errorJSON(c, err)
+		}
+
+		return c.JSON(http.StatusOK, NewAuditEntry(entry))
+	})
+
+	s.e.GET("/audit/:entity/:id", func(c echo.Context) error {
+		entity, err := entities.NewAuditEntity(c.Param("entity"))
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, Message{Error: err.Error()})
+		}
+
+		entries, err := s.service.AuditService.GetEntityLog(c.Request().Context(), entity, c.Param("id"))
+		if err != nil {
+			return errorJSON(c, err)
+		}
+
+		res := make([]AuditEntry, len(entries))
+		for i, e := range entries {
+			res[i] = NewAuditEntry(e)
+		}
+
+		return c.JSON(http.StatusOK, res)
+	})
+}
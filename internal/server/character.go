@@ -0,0 +1,164 @@
+package server
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/dtbead/wc-maps-archive/internal/entities"
+	"github.com/labstack/echo/v4"
+)
+
+// characters serves GET /character?name= for looking characters up by any name they went by, or ?series= for
+// everyone in a series, GET /character/:id and GET /character/:id/projects. Projects and parts are tagged through
+// GET, PUT and DELETE /project/:uuid/characters[/:character_id] and /part/:id/characters[/:character_id].
+func (s ServerController) characters() {
+	s.characterGroup.GET("", func(c echo.Context) error {
+		var characters []entities.Character
+		var err error
+
+		switch name, series := c.QueryParam("name"), c.QueryParam("series"); {
+		case name != "":
+			characters, err = s.service.CharacterService.FindCharacters(c.Request().Context(), name)
+		case series != "":
+			characters, err = s.service.CharacterService.GetSeriesCharacters(c.Request().Context(), series)
+		default:
+			return c.JSON(http.StatusBadRequest, Message{Error: "expected a name or series"})
+		}
+		if err != nil {
+			return errorJSON(c, err)
+		}
+
+		return c.JSON(http.StatusOK, newCharacters(characters))
+	})
+
+	s.characterGroup.GET("/:id", func(c echo.Context) error {
+		character_id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, Message{Error: "invalid character id"})
+		}
+
+		character, err := s.service.CharacterService.GetCharacter(c.Request().Context(), character_id)
+		if err != nil {
+			return errorJSON(c, err)
+		}
+
+		return c.JSON(http.StatusOK, NewCharacter(character))
+	})
+
+	s.characterGroup.GET("/:id/projects", func(c echo.Context) error {
+		character_id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, Message{Error: "invalid character id"})
+		}
+
+		uuids, err := s.service.CharacterService.GetProjects(c.Request().Context(), character_id)
+		if err != nil {
+			return errorJSON(c, err)
+		}
+
+		res := make([]string, len(uuids))
+		for i, uuid := range uuids {
+			res[i] = string(uuid)
+		}
+
+		return c.JSON(http.StatusOK, res)
+	})
+
+	s.projectGroup.GET("/:uuid/characters", func(c echo.Context) error {
+		characters, err := s.service.CharacterService.GetProjectCharacters(c.Request().Context(), entities.ProjectUUID(c.Param("uuid")))
+		if err != nil {
+			return errorJSON(c, err)
+		}
+
+		return c.JSON(http.StatusOK, newCharacters(characters))
+	})
+
+	s.projectGroup.PUT("/:uuid/characters/:character_id", func(c echo.Context) error {
+		character_id, err := strconv.Atoi(c.Param("character_id"))
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, Message{Error: "invalid character id"})
+		}
+
+		err = s.service.CharacterService.TagProject(c.Request().Context(), entities.ProjectUUID(c.Param("uuid")), character_id)
+		if err != nil {
+			return errorJSON(c, err)
+		}
+
+		return c.NoContent(http.StatusNoContent)
+	})
+
+	s.projectGroup.DELETE("/:uuid/characters/:character_id", func(c echo.Context) error {
+		character_id, err := strconv.Atoi(c.Param("character_id"))
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, Message{Error: "invalid character id"})
+		}
+
+		err = s.service.CharacterService.UntagProject(c.Request().Context(), entities.ProjectUUID(c.Param("uuid")), character_id)
+		if err != nil {
+			return errorJSON(c, err)
+		}
+
+		return c.NoContent(http.StatusNoContent)
+	})
+
+	s.partGroup.GET("/:id/characters", func(c echo.Context) error {
+		part_id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, Message{Error: "invalid part id"})
+		}
+
+		characters, err := s.service.CharacterService.GetPartCharacters(c.Request().Context(), part_id)
+		if err != nil {
+			return errorJSON(c, err)
+		}
+
+		return c.JSON(http.StatusOK, newCharacters(characters))
+	})
+
+	s.partGroup.PUT("/:id/characters/:character_id", func(c echo.Context) error {
+		part_id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, Message{Error: "invalid part id"})
+		}
+
+		character_id, err := strconv.Atoi(c.Param("character_id"))
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, Message{Error: "invalid character id"})
+		}
+
+		err = s.service.CharacterService.TagPart(c.Request().Context(), part_id, character_id)
+		if err != nil {
+			return errorJSON(c, err)
+		}
+
+		return c.NoContent(http.StatusNoContent)
+	})
+
+	s.partGroup.DELETE("/:id/characters/:character_id", func(c echo.Context) error {
+		part_id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, Message{Error: "invalid part id"})
+		}
+
+		character_id, err := strconv.Atoi(c.Param("character_id"))
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, Message{Error: "invalid character id"})
+		}
+
+		err = s.service.CharacterService.UntagPart(c.Request().Context(), part_id, character_id)
+		if err != nil {
+			return errorJSON(c, err)
+		}
+
+		return c.NoContent(http.StatusNoContent)
+	})
+}
+
+func newCharacters(characters []entities.Character) []Character {
+	res := make([]Character, len(characters))
+	for i, c := range characters {
+		res[i] = NewCharacter(c)
+	}
+
+	return res
+}
@@ -1,9 +1,251 @@
 package server
 
-import "github.com/dtbead/wc-maps-archive/internal/entities"
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/dtbead/wc-maps-archive/internal/entities"
+)
 
 type Video struct {
 	Id       int64
 	Duration int32
 	Hashes   entities.Hashes
 }
+
+// DefaultMinSimilarity is the lowest score a video needs to be listed as similar, unless asked otherwise.
+const DefaultMinSimilarity = 0.5
+
+type SimilarVideo struct {
+	FileID int64   `json:"file_id"`
+	Score  float64 `json:"score"`
+}
+
+// Project is a single project. date_announced and date_completed are only as precise as they are known, being a year,
+// month or day such as 2015, 2015-06 or 2015-06-21, prefixed with a ~ if approximate. They are left out when unknown.
+type Project struct {
+	UUID          string    `json:"uuid"`
+	Type          string    `json:"type"`
+	Title         string    `json:"title"`
+	Description   string    `json:"description"`
+	FileIDs       []int64   `json:"file_ids"`
+	DateAnnounced string    `json:"date_announced,omitempty"`
+	DateCompleted string    `json:"date_completed,omitempty"`
+	DateArchived  time.Time `json:"date_archived"`
+}
+
+func NewProject(p entities.Project) Project {
+	file_ids := make([]int64, len(p.FileIDs))
+	for i, id := range p.FileIDs {
+		file_ids[i] = int64(id)
+	}
+
+	return Project{
+		UUID:          p.UUID,
+		Type:          p.ProjectType.ToString(),
+		Title:         p.Title,
+		Description:   p.Description,
+		FileIDs:       file_ids,
+		DateAnnounced: p.DateAnnounced.String(),
+		DateCompleted: p.DateCompleted.String(),
+		DateArchived:  p.DateArchived,
+	}
+}
+
+// PartialDate is a date as precise as it is known, in the form of Project's date_announced. An empty date is unknown.
+type PartialDate struct {
+	Date string `json:"date"`
+}
+
+// Revision is a single entry in the history of a title or description.
+type Revision struct {
+	Text      string    `json:"text"`
+	DateAdded time.Time `json:"date_added"`
+}
+
+// Part is a single part of a MAP. Timestamps are in milliseconds, with end_ms being 0 if the timing isn't known.
+type Part struct {
+	ID                int64  `json:"id"`
+	ProjectUUID       string `json:"project_uuid"`
+	Number            int    `json:"number"`
+	StartMs           int64  `json:"start_ms"`
+	EndMs             int64  `json:"end_ms"`
+	Status            string `json:"status"`
+	Participant       string `json:"participant,omitempty"`
+	ParticipantArtist string `json:"participant_artist,omitempty"`
+	ParticipantName   string `json:"participant_name,omitempty"`
+	FileID            int64  `json:"file_id,omitempty"`
+	YoutubeID         string `json:"youtube_id,omitempty"`
+}
+
+func NewPart(p entities.ProjectPart) Part {
+	return Part{
+		ID:                p.ID,
+		ProjectUUID:       string(p.ProjectUUID),
+		Number:            p.Number,
+		StartMs:           p.Start.Milliseconds(),
+		EndMs:             p.End.Milliseconds(),
+		Status:            p.Status.ToString(),
+		Participant:       string(p.Participant),
+		ParticipantArtist: p.ParticipantArtist,
+		ParticipantName:   p.ParticipantName,
+		FileID:            int64(p.FileID),
+		YoutubeID:         string(p.YoutubeID),
+	}
+}
+
+func (p Part) ToEntity() (entities.ProjectPart, error) {
+	status, err := entities.NewPartStatus(p.Status)
+	if err != nil {
+		return entities.ProjectPart{}, err
+	}
+
+	return entities.ProjectPart{
+		ID:                p.ID,
+		ProjectUUID:       entities.ProjectUUID(p.ProjectUUID),
+		Number:            p.Number,
+		Start:             time.Duration(p.StartMs) * time.Millisecond,
+		End:               time.Duration(p.EndMs) * time.Millisecond,
+		Status:            status,
+		Participant:       entities.YoutubeChannelID(p.Participant),
+		ParticipantArtist: p.ParticipantArtist,
+		ParticipantName:   p.ParticipantName,
+		FileID:            entities.FileID(p.FileID),
+		YoutubeID:         entities.YoutubeVideoID(p.YoutubeID),
+	}, nil
+}
+
+// Relation reads as "project is a type of related".
+type Relation struct {
+	Project string `json:"project"`
+	Related string `json:"related"`
+	Type    string `json:"type"`
+	Depth   int    `json:"depth"`
+}
+
+func NewRelation(r entities.ProjectRelation) Relation {
+	return Relation{
+		Project: string(r.Project),
+		Related: string(r.Related),
+		Type:    r.Type.ToString(),
+		Depth:   r.Depth,
+	}
+}
+
+type Music struct {
+	ID     int32  `json:"id"`
+	Artist string `json:"artist"`
+	Title  string `json:"title"`
+}
+
+func NewMusic(m entities.Music) Music {
+	return Music{
+		ID:     m.ID,
+		Artist: m.Artist,
+		Title:  m.Title,
+	}
+}
+
+type MusicMatch struct {
+	Music Music   `json:"music"`
+	Score float64 `json:"score"`
+}
+
+type Character struct {
+	ID         int              `json:"id"`
+	Name       string           `json:"name"`
+	Series     string           `json:"series"`
+	IsOriginal bool             `json:"is_original"`
+	Aliases    []CharacterAlias `json:"aliases"`
+}
+
+type CharacterAlias struct {
+	Name  string `json:"name"`
+	Stage string `json:"stage"`
+}
+
+func NewCharacter(c entities.Character) Character {
+	res := Character{
+		ID:         c.ID,
+		Name:       c.Name,
+		Series:     c.Series,
+		IsOriginal: c.IsOriginal,
+		Aliases:    make([]CharacterAlias, len(c.Aliases)),
+	}
+
+	for i, a := range c.Aliases {
+		res.Aliases[i] = CharacterAlias{Name: a.Name, Stage: a.Stage.ToString()}
+	}
+
+	return res
+}
+
+type SearchResult struct {
+	Kind     string  `json:"kind"`
+	ID       string  `json:"id"`
+	Title    string  `json:"title"`
+	Headline string  `json:"headline"`
+	Rank     float64 `json:"rank"`
+}
+
+func NewSearchResult(r entities.SearchResult) SearchResult {
+	return SearchResult{
+		Kind:     r.Kind.ToString(),
+		ID:       r.ID,
+		Title:    r.Title,
+		Headline: r.Headline,
+		Rank:     r.Rank,
+	}
+}
+
+// AuditEntry is a single change recorded in the audit log. before and after are JSON of whatever the change touched,
+// null if there was nothing before or is nothing after.
+type AuditEntry struct {
+	ID        int64           `json:"id"`
+	Actor     string          `json:"actor"`
+	Entity    string          `json:"entity"`
+	EntityID  string          `json:"entity_id"`
+	Action    string          `json:"action"`
+	Before    json.RawMessage `json:"before"`
+	After     json.RawMessage `json:"after"`
+	DateAdded time.Time       `json:"date_added"`
+}
+
+func NewAuditEntry(e entities.AuditEntry) AuditEntry {
+	return AuditEntry{
+		ID:        e.ID,
+		Actor:     e.Actor,
+		Entity:    e.Entity.ToString(),
+		EntityID:  e.EntityID,
+		Action:    e.Action,
+		Before:    e.Before,
+		After:     e.After,
+		DateAdded: e.DateAdded,
+	}
+}
+
+// Page is a single page of a listing. NextCursor is passed as the cursor query parameter for the page after it, and is
+// left out on the last page.
+type Page struct {
+	Items      any    `json:"items"`
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// StatusChange is a single entry of a project's timeline. ID and DateAdded are ignored when changing status.
+type StatusChange struct {
+	ID        int64     `json:"id"`
+	Status    string    `json:"status"`
+	Date      time.Time `json:"date"`
+	Note      string    `json:"note,omitempty"`
+	DateAdded time.Time `json:"date_added,omitzero"`
+}
+
+func NewStatusChange(s entities.ProjectStatusChange) StatusChange {
+	return StatusChange{
+		ID:        s.ID,
+		Status:    s.Status.ToString(),
+		Date:      s.Date,
+		Note:      s.Note,
+		DateAdded: s.DateAdded,
+	}
+}
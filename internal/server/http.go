@@ -2,7 +2,12 @@ package server
 
 import (
 	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"strconv"
 
+	"github.com/dtbead/wc-maps-archive/internal/entities"
 	"github.com/dtbead/wc-maps-archive/internal/service"
 	"github.com/labstack/echo/v4"
 )
@@ -12,17 +17,26 @@ type Message struct {
 }
 
 type ServerController struct {
-	e          *echo.Echo
-	service    *service.Service
-	videoGroup *echo.Group
+	e              *echo.Echo
+	service        *service.Service
+	videoGroup     *echo.Group
+	projectGroup   *echo.Group
+	partGroup      *echo.Group
+	musicGroup     *echo.Group
+	characterGroup *echo.Group
 }
 
 func NewServer(s *service.Service) ServerController {
 	ctrl := ServerController{
 		echo.New(),
-		s, nil}
+		s, nil, nil, nil, nil, nil}
 
+	ctrl.e.Use(actor)
 	ctrl.videoGroup = ctrl.e.Group("/video")
+	ctrl.projectGroup = ctrl.e.Group("/project")
+	ctrl.partGroup = ctrl.e.Group("/part")
+	ctrl.musicGroup = ctrl.e.Group("/music")
+	ctrl.characterGroup = ctrl.e.Group("/character")
 
 	ctrl.initEcho()
 	return ctrl
@@ -36,9 +50,80 @@ func (s ServerController) Stop() error {
 	return s.e.Shutdown(context.Background())
 }
 
+// ServeHTTP serves a single request, as Start does for every request to its address.
+func (s ServerController) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.e.ServeHTTP(w, r)
+}
+
 func (s ServerController) initEcho() {
 	s.getVideoInfo()
+	s.getSimilarVideos()
+	s.getProject()
+	s.projectTitles()
+	s.projectDescriptions()
+	s.projectDates()
+	s.projectParts()
+	s.projectRelations()
+	s.projectStatus()
+	s.music()
+	s.characters()
+	s.search()
+	s.lists()
+	s.audit()
+	s.dav()
+}
+
+// errorJSON responds with err, as a 404 if it's caused by something that doesn't exist, or a 400 if it's caused by a bad
+// filter or page.
+func errorJSON(c echo.Context, err error) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		return c.JSON(http.StatusNotFound, Message{Error: "not found"})
+	}
+
+	if errors.Is(err, entities.ErrorProjectRelationCycle) || errors.Is(err, entities.ErrorInvalidStatusChange) {
+		return c.JSON(http.StatusConflict, Message{Error: err.Error()})
+	}
+
+	if errors.Is(err, entities.ErrorInvalidFilter) || errors.Is(err, entities.ErrorInvalidCursor) || errors.Is(err, entities.ErrorUnsupportedSort) {
+		return c.JSON(http.StatusBadRequest, Message{Error: err.Error()})
+	}
+
+	return c.JSON(http.StatusInternalServerError, Message{Error: err.Error()})
 }
 
 func (s ServerController) getVideoInfo() {
 }
+
+// getSimilarVideos serves GET /video/:file_id/similar, listing likely reuploads and re-encodes of a video file. The
+// optional min_score query parameter defaults to DefaultMinSimilarity.
+func (s ServerController) getSimilarVideos() {
+	s.videoGroup.GET("/:file_id/similar", func(c echo.Context) error {
+		file_id, err := strconv.ParseInt(c.Param("file_id"), 10, 64)
+		if err != nil || !entities.FileID(file_id).IsValid() {
+			return c.JSON(http.StatusBadRequest, Message{Error: "invalid file id"})
+		}
+
+		min_score := DefaultMinSimilarity
+		if v := c.QueryParam("min_score"); v != "" {
+			min_score, err = strconv.ParseFloat(v, 64)
+			if err != nil || min_score < 0 || min_score > 1 {
+				return c.JSON(http.StatusBadRequest, Message{Error: "min_score must be a number between 0 and 1"})
+			}
+		}
+
+		matches, err := s.service.FingerprintService.FindSimilar(c.Request().Context(), entities.FileID(file_id), min_score)
+		if errors.Is(err, sql.ErrNoRows) {
+			return c.JSON(http.StatusNotFound, Message{Error: "video has no fingerprint"})
+		}
+		if err != nil {
+			return errorJSON(c, err)
+		}
+
+		res := make([]SimilarVideo, len(matches))
+		for i, m := range matches {
+			res[i] = SimilarVideo{FileID: int64(m.FileID), Score: m.Score}
+		}
+
+		return c.JSON(http.StatusOK, res)
+	})
+}
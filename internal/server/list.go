@@ -0,0 +1,98 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/dtbead/wc-maps-archive/internal/entities"
+	"github.com/labstack/echo/v4"
+)
+
+// listOptions reads the sort, order, cursor and limit query parameters of a listing. order is either asc or desc, the
+// default.
+func listOptions(c echo.Context) (opts entities.ListOptions, err error) {
+	opts.Sort, err = entities.NewListSort(c.QueryParam("sort"))
+	if err != nil {
+		return entities.ListOptions{}, err
+	}
+
+	switch c.QueryParam("order") {
+	case "", "desc":
+	case "asc":
+		opts.Ascending = true
+	default:
+		return entities.ListOptions{}, errors.New("order must be asc or desc")
+	}
+
+	if v := c.QueryParam("limit"); v != "" {
+		opts.Limit, err = strconv.Atoi(v)
+		if err != nil {
+			return entities.ListOptions{}, errors.New("invalid limit")
+		}
+	}
+
+	opts.Cursor = c.QueryParam("cursor")
+	return opts, nil
+}
+
+// lists serves GET /project and GET /youtube, listing projects and youtube videos matching the filter query q, as well
+// as GET /channel and GET /file listing every youtube channel and stored file. Every listing takes the sort, order,
+// limit and cursor query parameters, the cursor of the next page being given alongside the results.
+func (s ServerController) lists() {
+	s.projectGroup.GET("", func(c echo.Context) error {
+		opts, err := listOptions(c)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, Message{Error: err.Error()})
+		}
+
+		uuids, next_cursor, err := s.service.ProjectService.ListProjects(c.Request().Context(), c.QueryParam("q"), opts)
+		if err != nil {
+			return errorJSON(c, err)
+		}
+
+		return c.JSON(http.StatusOK, Page{Items: uuids, NextCursor: next_cursor})
+	})
+
+	s.e.GET("/youtube", func(c echo.Context) error {
+		opts, err := listOptions(c)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, Message{Error: err.Error()})
+		}
+
+		youtube_ids, next_cursor, err := s.service.YoutubeService.ListYoutube(c.Request().Context(), c.QueryParam("q"), opts)
+		if err != nil {
+			return errorJSON(c, err)
+		}
+
+		return c.JSON(http.StatusOK, Page{Items: youtube_ids, NextCursor: next_cursor})
+	})
+
+	s.e.GET("/channel", func(c echo.Context) error {
+		opts, err := listOptions(c)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, Message{Error: err.Error()})
+		}
+
+		channel_ids, next_cursor, err := s.service.YoutubeService.ListChannels(c.Request().Context(), opts)
+		if err != nil {
+			return errorJSON(c, err)
+		}
+
+		return c.JSON(http.StatusOK, Page{Items: channel_ids, NextCursor: next_cursor})
+	})
+
+	s.e.GET("/file", func(c echo.Context) error {
+		opts, err := listOptions(c)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, Message{Error: err.Error()})
+		}
+
+		file_ids, next_cursor, err := s.service.FileService.ListFiles(c.Request().Context(), opts)
+		if err != nil {
+			return errorJSON(c, err)
+		}
+
+		return c.JSON(http.StatusOK, Page{Items: file_ids, NextCursor: next_cursor})
+	})
+}
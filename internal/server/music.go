@@ -0,0 +1,108 @@
+package server
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/dtbead/wc-maps-archive/internal/entities"
+	"github.com/labstack/echo/v4"
+)
+
+// music serves GET /music?artist=&title= for looking songs up, GET /music/:id and GET /music/:id/projects for every
+// project that used a song, as well as GET /project/:uuid/music and PUT and DELETE /project/:uuid/music/:id for the
+// songs of a project.
+func (s ServerController) music() {
+	s.musicGroup.GET("", func(c echo.Context) error {
+		artist, title := c.QueryParam("artist"), c.QueryParam("title")
+		if artist == "" && title == "" {
+			return c.JSON(http.StatusBadRequest, Message{Error: "expected an artist or title"})
+		}
+
+		matches, err := s.service.MusicService.FindMusic(c.Request().Context(), artist, title)
+		if err != nil {
+			return errorJSON(c, err)
+		}
+
+		res := make([]MusicMatch, len(matches))
+		for i, m := range matches {
+			res[i] = MusicMatch{Music: NewMusic(m.Music), Score: m.Score}
+		}
+
+		return c.JSON(http.StatusOK, res)
+	})
+
+	s.musicGroup.GET("/:id", func(c echo.Context) error {
+		music_id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, Message{Error: "invalid music id"})
+		}
+
+		music, err := s.service.MusicService.GetMusic(c.Request().Context(), int32(music_id))
+		if err != nil {
+			return errorJSON(c, err)
+		}
+
+		return c.JSON(http.StatusOK, NewMusic(music))
+	})
+
+	s.musicGroup.GET("/:id/projects", func(c echo.Context) error {
+		music_id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, Message{Error: "invalid music id"})
+		}
+
+		uuids, err := s.service.MusicService.GetProjects(c.Request().Context(), int32(music_id))
+		if err != nil {
+			return errorJSON(c, err)
+		}
+
+		res := make([]string, len(uuids))
+		for i, uuid := range uuids {
+			res[i] = string(uuid)
+		}
+
+		return c.JSON(http.StatusOK, res)
+	})
+
+	s.projectGroup.GET("/:uuid/music", func(c echo.Context) error {
+		music, err := s.service.MusicService.GetProjectMusic(c.Request().Context(), entities.ProjectUUID(c.Param("uuid")))
+		if err != nil {
+			return errorJSON(c, err)
+		}
+
+		res := make([]Music, len(music))
+		for i, m := range music {
+			res[i] = NewMusic(m)
+		}
+
+		return c.JSON(http.StatusOK, res)
+	})
+
+	s.projectGroup.PUT("/:uuid/music/:id", func(c echo.Context) error {
+		music_id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, Message{Error: "invalid music id"})
+		}
+
+		err = s.service.MusicService.AssignProject(c.Request().Context(), entities.ProjectUUID(c.Param("uuid")), int32(music_id))
+		if err != nil {
+			return errorJSON(c, err)
+		}
+
+		return c.NoContent(http.StatusNoContent)
+	})
+
+	s.projectGroup.DELETE("/:uuid/music/:id", func(c echo.Context) error {
+		music_id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, Message{Error: "invalid music id"})
+		}
+
+		err = s.service.MusicService.UnassignProject(c.Request().Context(), entities.ProjectUUID(c.Param("uuid")), int32(music_id))
+		if err != nil {
+			return errorJSON(c, err)
+		}
+
+		return c.NoContent(http.StatusNoContent)
+	})
+}
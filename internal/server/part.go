@@ -0,0 +1,99 @@
+package server
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/dtbead/wc-maps-archive/internal/entities"
+	"github.com/labstack/echo/v4"
+)
+
+// projectParts serves GET and POST /project/:uuid/parts, listing a project's parts in order and adding a new one, as
+// well as GET, PUT and DELETE /part/:id for working with a single part.
+func (s ServerController) projectParts() {
+	s.projectGroup.GET("/:uuid/parts", func(c echo.Context) error {
+		parts, err := s.service.ProjectService.GetParts(c.Request().Context(), entities.ProjectUUID(c.Param("uuid")))
+		if err != nil {
+			return errorJSON(c, err)
+		}
+
+		res := make([]Part, len(parts))
+		for i, p := range parts {
+			res[i] = NewPart(p)
+		}
+
+		return c.JSON(http.StatusOK, res)
+	})
+
+	s.projectGroup.POST("/:uuid/parts", func(c echo.Context) error {
+		part, err := bindPart(c)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, Message{Error: err.Error()})
+		}
+		part.ProjectUUID = entities.ProjectUUID(c.Param("uuid"))
+
+		part.ID, err = s.service.ProjectService.NewPart(c.Request().Context(), &part)
+		if err != nil {
+			return errorJSON(c, err)
+		}
+
+		return c.JSON(http.StatusCreated, NewPart(part))
+	})
+
+	s.partGroup.GET("/:id", func(c echo.Context) error {
+		part_id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, Message{Error: "invalid part id"})
+		}
+
+		part, err := s.service.ProjectService.GetPart(c.Request().Context(), part_id)
+		if err != nil {
+			return errorJSON(c, err)
+		}
+
+		return c.JSON(http.StatusOK, NewPart(part))
+	})
+
+	s.partGroup.PUT("/:id", func(c echo.Context) error {
+		part_id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, Message{Error: "invalid part id"})
+		}
+
+		part, err := bindPart(c)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, Message{Error: err.Error()})
+		}
+		part.ID = part_id
+
+		err = s.service.ProjectService.UpdatePart(c.Request().Context(), &part)
+		if err != nil {
+			return errorJSON(c, err)
+		}
+
+		return c.NoContent(http.StatusNoContent)
+	})
+
+	s.partGroup.DELETE("/:id", func(c echo.Context) error {
+		part_id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, Message{Error: "invalid part id"})
+		}
+
+		err = s.service.ProjectService.DeletePart(c.Request().Context(), part_id)
+		if err != nil {
+			return errorJSON(c, err)
+		}
+
+		return c.NoContent(http.StatusNoContent)
+	})
+}
+
+func bindPart(c echo.Context) (entities.ProjectPart, error) {
+	var p Part
+	if err := c.Bind(&p); err != nil {
+		return entities.ProjectPart{}, err
+	}
+
+	return p.ToEntity()
+}
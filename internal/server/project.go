@@ -0,0 +1,116 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/dtbead/wc-maps-archive/internal/entities"
+	"github.com/labstack/echo/v4"
+)
+
+// getProject serves GET /project/:uuid.
+func (s ServerController) getProject() {
+	s.projectGroup.GET("/:uuid", func(c echo.Context) error {
+		p, err := s.service.ProjectService.GetProject(c.Request().Context(), entities.ProjectUUID(c.Param("uuid")))
+		if err != nil {
+			return errorJSON(c, err)
+		}
+
+		return c.JSON(http.StatusOK, NewProject(p))
+	})
+}
+
+// projectTitles serves GET /project/:uuid/titles, listing every title newest first, and PUT /project/:uuid/title
+// taking a Revision whose text becomes the current title.
+func (s ServerController) projectTitles() {
+	s.projectGroup.GET("/:uuid/titles", func(c echo.Context) error {
+		titles, err := s.service.ProjectService.GetTitles(c.Request().Context(), entities.ProjectUUID(c.Param("uuid")))
+		if err != nil {
+			return errorJSON(c, err)
+		}
+
+		res := make([]Revision, len(titles))
+		for i, t := range titles {
+			res[i] = Revision{Text: t.Title, DateAdded: t.DateAdded}
+		}
+
+		return c.JSON(http.StatusOK, res)
+	})
+
+	s.projectGroup.PUT("/:uuid/title", func(c echo.Context) error {
+		var r Revision
+		if err := c.Bind(&r); err != nil || strings.TrimSpace(r.Text) == "" {
+			return c.JSON(http.StatusBadRequest, Message{Error: "invalid title"})
+		}
+
+		err := s.service.ProjectService.SetTitle(c.Request().Context(), entities.ProjectUUID(c.Param("uuid")), r.Text)
+		if err != nil {
+			return errorJSON(c, err)
+		}
+
+		return c.NoContent(http.StatusNoContent)
+	})
+}
+
+// projectDescriptions serves GET /project/:uuid/descriptions, listing every description newest first, and
+// PUT /project/:uuid/description taking a Revision whose text becomes the current description.
+func (s ServerController) projectDescriptions() {
+	s.projectGroup.GET("/:uuid/descriptions", func(c echo.Context) error {
+		descriptions, err := s.service.ProjectService.GetDescriptions(c.Request().Context(), entities.ProjectUUID(c.Param("uuid")))
+		if err != nil {
+			return errorJSON(c, err)
+		}
+
+		res := make([]Revision, len(descriptions))
+		for i, d := range descriptions {
+			res[i] = Revision{Text: d.Description, DateAdded: d.DateAdded}
+		}
+
+		return c.JSON(http.StatusOK, res)
+	})
+
+	s.projectGroup.PUT("/:uuid/description", func(c echo.Context) error {
+		var r Revision
+		if err := c.Bind(&r); err != nil {
+			return c.JSON(http.StatusBadRequest, Message{Error: "invalid description"})
+		}
+
+		err := s.service.ProjectService.SetDescription(c.Request().Context(), entities.ProjectUUID(c.Param("uuid")), r.Text)
+		if err != nil {
+			return errorJSON(c, err)
+		}
+
+		return c.NoContent(http.StatusNoContent)
+	})
+}
+
+// projectDates serves PUT /project/:uuid/announced and PUT /project/:uuid/completed, each taking a PartialDate that
+// becomes when the project was announced or completed.
+func (s ServerController) projectDates() {
+	set := func(c echo.Context, set_date func(ctx context.Context, project_uuid entities.ProjectUUID, date entities.PartialDate) error) error {
+		var d PartialDate
+		if err := c.Bind(&d); err != nil {
+			return c.JSON(http.StatusBadRequest, Message{Error: "invalid date"})
+		}
+
+		date, err := entities.ParsePartialDate(d.Date)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, Message{Error: err.Error()})
+		}
+
+		if err := set_date(c.Request().Context(), entities.ProjectUUID(c.Param("uuid")), date); err != nil {
+			return errorJSON(c, err)
+		}
+
+		return c.NoContent(http.StatusNoContent)
+	}
+
+	s.projectGroup.PUT("/:uuid/announced", func(c echo.Context) error {
+		return set(c, s.service.ProjectService.SetDateAnnounced)
+	})
+
+	s.projectGroup.PUT("/:uuid/completed", func(c echo.Context) error {
+		return set(c, s.service.ProjectService.SetDateCompleted)
+	})
+}
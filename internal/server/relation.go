@@ -0,0 +1,99 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/dtbead/wc-maps-archive/internal/entities"
+	"github.com/labstack/echo/v4"
+)
+
+// projectRelations serves GET and POST /project/:uuid/relations, DELETE /project/:uuid/relations/:type/:related, and
+// GET /project/:uuid/ancestors/:type and /project/:uuid/descendants/:type for walking the relation tree.
+func (s ServerController) projectRelations() {
+	s.projectGroup.GET("/:uuid/relations", func(c echo.Context) error {
+		relations, err := s.service.ProjectService.GetRelations(c.Request().Context(), entities.ProjectUUID(c.Param("uuid")))
+		if err != nil {
+			return errorJSON(c, err)
+		}
+
+		return c.JSON(http.StatusOK, newRelations(relations))
+	})
+
+	s.projectGroup.POST("/:uuid/relations", func(c echo.Context) error {
+		var r Relation
+		if err := c.Bind(&r); err != nil {
+			return c.JSON(http.StatusBadRequest, Message{Error: "invalid relation"})
+		}
+
+		relation_type, err := entities.NewProjectRelationType(r.Type)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, Message{Error: err.Error()})
+		}
+
+		err = s.service.ProjectService.Relate(c.Request().Context(), entities.ProjectRelation{
+			Project: entities.ProjectUUID(c.Param("uuid")),
+			Related: entities.ProjectUUID(r.Related),
+			Type:    relation_type,
+		})
+		if err != nil {
+			return errorJSON(c, err)
+		}
+
+		return c.NoContent(http.StatusCreated)
+	})
+
+	s.projectGroup.DELETE("/:uuid/relations/:type/:related", func(c echo.Context) error {
+		relation_type, err := entities.NewProjectRelationType(c.Param("type"))
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, Message{Error: err.Error()})
+		}
+
+		err = s.service.ProjectService.Unrelate(c.Request().Context(), entities.ProjectRelation{
+			Project: entities.ProjectUUID(c.Param("uuid")),
+			Related: entities.ProjectUUID(c.Param("related")),
+			Type:    relation_type,
+		})
+		if err != nil {
+			return errorJSON(c, err)
+		}
+
+		return c.NoContent(http.StatusNoContent)
+	})
+
+	s.projectGroup.GET("/:uuid/ancestors/:type", func(c echo.Context) error {
+		relation_type, err := entities.NewProjectRelationType(c.Param("type"))
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, Message{Error: err.Error()})
+		}
+
+		relations, err := s.service.ProjectService.GetAncestors(c.Request().Context(), entities.ProjectUUID(c.Param("uuid")), relation_type)
+		if err != nil {
+			return errorJSON(c, err)
+		}
+
+		return c.JSON(http.StatusOK, newRelations(relations))
+	})
+
+	s.projectGroup.GET("/:uuid/descendants/:type", func(c echo.Context) error {
+		relation_type, err := entities.NewProjectRelationType(c.Param("type"))
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, Message{Error: err.Error()})
+		}
+
+		relations, err := s.service.ProjectService.GetDescendants(c.Request().Context(), entities.ProjectUUID(c.Param("uuid")), relation_type)
+		if err != nil {
+			return errorJSON(c, err)
+		}
+
+		return c.JSON(http.StatusOK, newRelations(relations))
+	})
+}
+
+func newRelations(relations []entities.ProjectRelation) []Relation {
+	res := make([]Relation, len(relations))
+	for i, r := range relations {
+		res[i] = NewRelation(r)
+	}
+
+	return res
+}
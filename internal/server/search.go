@@ -0,0 +1,45 @@
+package server
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/dtbead/wc-maps-archive/internal/entities"
+	"github.com/labstack/echo/v4"
+)
+
+// search serves GET /search?q=, searching the whole archive. The optional kind query parameter limits results to a
+// single kind, and limit sets how many are returned.
+func (s ServerController) search() {
+	s.e.GET("/search", func(c echo.Context) error {
+		query := c.QueryParam("q")
+		if query == "" {
+			return c.JSON(http.StatusBadRequest, Message{Error: "expected a search query"})
+		}
+
+		kind, err := entities.NewSearchKind(c.QueryParam("kind"))
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, Message{Error: err.Error()})
+		}
+
+		var limit int
+		if v := c.QueryParam("limit"); v != "" {
+			limit, err = strconv.Atoi(v)
+			if err != nil {
+				return c.JSON(http.StatusBadRequest, Message{Error: "invalid limit"})
+			}
+		}
+
+		results, err := s.service.SearchService.Search(c.Request().Context(), query, kind, limit)
+		if err != nil {
+			return errorJSON(c, err)
+		}
+
+		res := make([]SearchResult, len(results))
+		for i, r := range results {
+			res[i] = NewSearchResult(r)
+		}
+
+		return c.JSON(http.StatusOK, res)
+	})
+}
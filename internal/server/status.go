@@ -0,0 +1,54 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/dtbead/wc-maps-archive/internal/entities"
+	"github.com/labstack/echo/v4"
+)
+
+// projectStatus serves GET /project/:uuid/status, the timeline of a project oldest first, POST /project/:uuid/status
+// for changing its status, and DELETE /project/:uuid/status for undoing the latest change.
+func (s ServerController) projectStatus() {
+	s.projectGroup.GET("/:uuid/status", func(c echo.Context) error {
+		timeline, err := s.service.ProjectService.GetTimeline(c.Request().Context(), entities.ProjectUUID(c.Param("uuid")))
+		if err != nil {
+			return errorJSON(c, err)
+		}
+
+		res := make([]StatusChange, len(timeline))
+		for i, change := range timeline {
+			res[i] = NewStatusChange(change)
+		}
+
+		return c.JSON(http.StatusOK, res)
+	})
+
+	s.projectGroup.POST("/:uuid/status", func(c echo.Context) error {
+		var change StatusChange
+		if err := c.Bind(&change); err != nil {
+			return c.JSON(http.StatusBadRequest, Message{Error: "invalid status change"})
+		}
+
+		status, err := entities.NewProjectStatus(change.Status)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, Message{Error: err.Error()})
+		}
+
+		res := entities.ProjectStatusChange{Status: status, Date: change.Date, Note: change.Note}
+		res.ID, err = s.service.ProjectService.SetStatus(c.Request().Context(), entities.ProjectUUID(c.Param("uuid")), res)
+		if err != nil {
+			return errorJSON(c, err)
+		}
+
+		return c.JSON(http.StatusCreated, NewStatusChange(res))
+	})
+
+	s.projectGroup.DELETE("/:uuid/status", func(c echo.Context) error {
+		if err := s.service.ProjectService.UndoStatus(c.Request().Context(), entities.ProjectUUID(c.Param("uuid"))); err != nil {
+			return errorJSON(c, err)
+		}
+
+		return c.NoContent(http.StatusNoContent)
+	})
+}
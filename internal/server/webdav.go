@@ -0,0 +1,329 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dtbead/wc-maps-archive/internal/service"
+	"github.com/labstack/echo/v4"
+	"golang.org/x/net/webdav"
+)
+
+// davPrefix is where the archive is served over WebDAV.
+const davPrefix = "/dav"
+
+// davSnapshotTTL is how long the tree served over WebDAV is reused before being laid out again, as a file manager
+// browsing a folder asks for it many times over.
+const davSnapshotTTL = time.Minute
+
+// davMethods are the WebDAV methods which don't change anything. LOCK and UNLOCK are allowed because some clients lock
+// a file before reading it.
+var davMethods = []string{http.MethodOptions, http.MethodGet, http.MethodHead, "PROPFIND", "LOCK", "UNLOCK"}
+
+// dav serves the archive read-only over WebDAV under /dav/, laid out as service.TreeEntries lays it out, so that it can
+// be browsed and streamed by file managers and media players without being exported anywhere. Methods which would
+// change it are refused.
+func (s ServerController) dav() {
+	archive := &archiveFS{service: s.service}
+	dav := echo.WrapHandler(&webdav.Handler{
+		Prefix:     davPrefix,
+		FileSystem: archive,
+		LockSystem: webdav.NewMemLS(),
+	})
+
+	// locking a path that doesn't exist would create it, which webdav.Handler fails as an internal error
+	h := func(c echo.Context) error {
+		if c.Request().Method == "LOCK" {
+			_, err := archive.Stat(c.Request().Context(), strings.TrimPrefix(c.Request().URL.Path, davPrefix))
+			if errors.Is(err, os.ErrNotExist) {
+				return c.NoContent(http.StatusForbidden)
+			}
+		}
+
+		return dav(c)
+	}
+
+	s.e.Match(davMethods, davPrefix, h)
+	s.e.Match(davMethods, davPrefix+"/*", h)
+}
+
+// archiveFS is a read-only webdav.FileSystem of the archive. Directories only exist as far as entries are within them.
+type archiveFS struct {
+	service *service.Service
+
+	mu       sync.Mutex
+	snapshot *davSnapshot
+	// rebuilt is closed once the snapshot being laid out is done, and nil while none is
+	rebuilt chan struct{}
+	err     error
+}
+
+// davSnapshot is the tree of the archive at a point in time, by cleaned absolute path.
+type davSnapshot struct {
+	built time.Time
+	files map[string]service.TreeEntry
+	dirs  map[string][]string
+}
+
+// current returns the latest snapshot of the tree. One older than davSnapshotTTL is laid out again in the background,
+// while it keeps being served until that's done, so only the very first request waits on the tree being laid out.
+func (a *archiveFS) current(ctx context.Context) (*davSnapshot, error) {
+	a.mu.Lock()
+	snapshot := a.snapshot
+	if snapshot != nil && time.Since(snapshot.built) < davSnapshotTTL {
+		a.mu.Unlock()
+		return snapshot, nil
+	}
+
+	rebuilt := a.rebuilt
+	if rebuilt == nil {
+		rebuilt = make(chan struct{})
+		a.rebuilt = rebuilt
+
+		// the snapshot is shared by every request, so it's laid out for as long as it takes even if this one goes away
+		go a.rebuild(context.WithoutCancel(ctx), rebuilt)
+	}
+	a.mu.Unlock()
+
+	if snapshot != nil {
+		return snapshot, nil
+	}
+
+	select {
+	case <-rebuilt:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.snapshot == nil {
+		return nil, a.err
+	}
+
+	return a.snapshot, nil
+}
+
+// rebuild lays the tree out again, closing rebuilt once it's done. A tree that fails to be laid out keeps the snapshot
+// as it was, and is tried again on the next request.
+func (a *archiveFS) rebuild(ctx context.Context, rebuilt chan struct{}) {
+	snapshot, err := newDavSnapshot(ctx, a.service)
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if err == nil {
+		a.snapshot = snapshot
+	}
+	a.err = err
+	a.rebuilt = nil
+	close(rebuilt)
+}
+
+func newDavSnapshot(ctx context.Context, s *service.Service) (*davSnapshot, error) {
+	entries, err := s.TreeEntries(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := &davSnapshot{
+		built: time.Now(),
+		files: make(map[string]service.TreeEntry, len(entries)),
+		dirs:  map[string][]string{"/": nil},
+	}
+
+	for _, e := range entries {
+		p := "/" + e.Path
+		snapshot.files[p] = e
+
+		// adds p to its directory, and every directory up to the root to theirs, until one that's already known
+		for dir := path.Dir(p); ; p, dir = dir, path.Dir(dir) {
+			_, known := snapshot.dirs[dir]
+			snapshot.dirs[dir] = append(snapshot.dirs[dir], path.Base(p))
+			if known {
+				break
+			}
+		}
+	}
+
+	for _, children := range snapshot.dirs {
+		slices.Sort(children)
+	}
+
+	return snapshot, nil
+}
+
+func (a *archiveFS) Mkdir(ctx context.Context, name string, perm os.FileMode) error {
+	return os.ErrPermission
+}
+
+func (a *archiveFS) RemoveAll(ctx context.Context, name string) error {
+	return os.ErrPermission
+}
+
+func (a *archiveFS) Rename(ctx context.Context, oldName, newName string) error {
+	return os.ErrPermission
+}
+
+func (a *archiveFS) Stat(ctx context.Context, name string) (os.FileInfo, error) {
+	snapshot, err := a.current(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return snapshot.stat(path.Clean("/" + name))
+}
+
+func (a *archiveFS) OpenFile(ctx context.Context, name string, flag int, perm os.FileMode) (webdav.File, error) {
+	if flag&(os.O_WRONLY|os.O_RDWR|os.O_CREATE|os.O_TRUNC|os.O_APPEND) != 0 {
+		return nil, os.ErrPermission
+	}
+
+	snapshot, err := a.current(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	name = path.Clean("/" + name)
+	info, err := snapshot.stat(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if info.IsDir() {
+		children := make([]fs.FileInfo, 0, len(snapshot.dirs[name]))
+		for _, child := range snapshot.dirs[name] {
+			child_info, err := snapshot.stat(path.Join(name, child))
+			if err != nil {
+				return nil, err
+			}
+			children = append(children, child_info)
+		}
+
+		return &davDirectory{info: info, children: children}, nil
+	}
+
+	r, err := a.service.FileService.GetReader(ctx, snapshot.files[name].FileID)
+	if err != nil {
+		return nil, err
+	}
+
+	// streaming needs to seek, which stored files opened from disk always can
+	rs, ok := r.(io.ReadSeekCloser)
+	if !ok {
+		r.Close()
+		return nil, errors.New("stored file can't be seeked")
+	}
+
+	return &davFile{ReadSeekCloser: rs, info: info}, nil
+}
+
+func (d *davSnapshot) stat(name string) (fs.FileInfo, error) {
+	if e, ok := d.files[name]; ok {
+		return davFileInfo{name: path.Base(name), size: e.File.Size, mod_time: e.ModTime}, nil
+	}
+
+	if _, ok := d.dirs[name]; ok {
+		return davFileInfo{name: path.Base(name), mod_time: d.built, dir: true}, nil
+	}
+
+	return nil, os.ErrNotExist
+}
+
+type davFileInfo struct {
+	name     string
+	size     int64
+	mod_time time.Time
+	dir      bool
+}
+
+func (i davFileInfo) Name() string       { return i.name }
+func (i davFileInfo) Size() int64        { return i.size }
+func (i davFileInfo) ModTime() time.Time { return i.mod_time }
+func (i davFileInfo) IsDir() bool        { return i.dir }
+func (i davFileInfo) Sys() any           { return nil }
+
+func (i davFileInfo) Mode() fs.FileMode {
+	if i.dir {
+		return fs.ModeDir | 0555
+	}
+
+	return 0444
+}
+
+// davFile is a stored file opened for reading.
+type davFile struct {
+	io.ReadSeekCloser
+	info fs.FileInfo
+}
+
+func (f *davFile) Readdir(count int) ([]fs.FileInfo, error) {
+	return nil, errors.New("not a directory")
+}
+
+func (f *davFile) Stat() (fs.FileInfo, error) {
+	return f.info, nil
+}
+
+func (f *davFile) Write(p []byte) (int, error) {
+	return 0, os.ErrPermission
+}
+
+// davDirectory is a directory of the tree, listing its children as of when it was opened.
+type davDirectory struct {
+	info     fs.FileInfo
+	children []fs.FileInfo
+	read     int
+}
+
+func (d *davDirectory) Close() error {
+	return nil
+}
+
+func (d *davDirectory) Read(p []byte) (int, error) {
+	return 0, errors.New("is a directory")
+}
+
+func (d *davDirectory) Seek(offset int64, whence int) (int64, error) {
+	if offset == 0 && whence == io.SeekStart {
+		d.read = 0
+		return 0, nil
+	}
+
+	return 0, errors.New("is a directory")
+}
+
+// Readdir returns the next count children, or every child left if count is 0 or less, as described by os.File.
+func (d *davDirectory) Readdir(count int) ([]fs.FileInfo, error) {
+	left := d.children[d.read:]
+	if count <= 0 {
+		d.read = len(d.children)
+		return left, nil
+	}
+
+	if len(left) == 0 {
+		return nil, io.EOF
+	}
+
+	n := min(count, len(left))
+	d.read += n
+	return left[:n], nil
+}
+
+func (d *davDirectory) Stat() (fs.FileInfo, error) {
+	return d.info, nil
+}
+
+func (d *davDirectory) Write(p []byte) (int, error) {
+	return 0, os.ErrPermission
+}
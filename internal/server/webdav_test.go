@@ -0,0 +1,66 @@
+package server_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	helper_test "github.com/dtbead/wc-maps-archive/internal/helper/testing"
+	"github.com/dtbead/wc-maps-archive/internal/server"
+	"github.com/dtbead/wc-maps-archive/internal/service"
+	"github.com/dtbead/wc-maps-archive/internal/storage/postgres"
+	_ "github.com/jackc/pgx/v5/stdlib"
+)
+
+const lockInfo = `<?xml version="1.0" encoding="utf-8"?>
+<D:lockinfo xmlns:D="DAV:"><D:lockscope><D:exclusive/></D:lockscope><D:locktype><D:write/></D:locktype></D:lockinfo>`
+
+func TestServerController_DavReadOnly(t *testing.T) {
+	db := helper_test.NewDatabase(&helper_test.DefaultConnection)
+	defer db.Close()
+
+	repositories, err := postgres.NewRepository(db, t.TempDir())
+	if err != nil {
+		t.Fatalf("failed to create repositories, %v", err)
+	}
+	srv := server.NewServer(service.NewService(repositories))
+
+	request := func(method, target, body string) int {
+		t.Helper()
+
+		r := httptest.NewRequest(method, target, strings.NewReader(body))
+		if method == "PROPFIND" {
+			r.Header.Set("Depth", "0")
+		}
+
+		w := httptest.NewRecorder()
+		srv.ServeHTTP(w, r)
+		return w.Code
+	}
+
+	if code := request("PROPFIND", "/dav/", ""); code != http.StatusMultiStatus {
+		t.Fatalf("PROPFIND /dav/ = %d, want %d", code, http.StatusMultiStatus)
+	}
+
+	tests := []struct {
+		method, target, body string
+		want                 int
+	}{
+		{http.MethodPut, "/dav/new.mp4", "contents of a new file", http.StatusMethodNotAllowed},
+		{http.MethodDelete, "/dav/Projects", "", http.StatusMethodNotAllowed},
+		{"MKCOL", "/dav/new", "", http.StatusMethodNotAllowed},
+		{"LOCK", "/dav/new.mp4", lockInfo, http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.method, func(t *testing.T) {
+			if code := request(tt.method, tt.target, tt.body); code != tt.want {
+				t.Errorf("%s %s = %d, want %d", tt.method, tt.target, code, tt.want)
+			}
+
+			if code := request("PROPFIND", tt.target, ""); code != http.StatusNotFound {
+				t.Errorf("PROPFIND %s after %s = %d, want %d", tt.target, tt.method, code, http.StatusNotFound)
+			}
+		})
+	}
+}
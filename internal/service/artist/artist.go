@@ -0,0 +1,104 @@
+package artist
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/dtbead/wc-maps-archive/internal/entities"
+	"github.com/dtbead/wc-maps-archive/internal/storage"
+)
+
+// ArtistService takes artist_name as either the current name of an artist or any of their aliases.
+type ArtistService struct {
+	ArtistRepo storage.ArtistRepository
+}
+
+func NewService(ArtistRepo storage.ArtistRepository) *ArtistService {
+	return &ArtistService{ArtistRepo: ArtistRepo}
+}
+
+var errorEmptyArtistName = errors.New("empty artist name")
+
+func (a ArtistService) NewArtist(ctx context.Context, artist_name string) (err error) {
+	artist_name = strings.TrimSpace(artist_name)
+	if artist_name == "" {
+		return errorEmptyArtistName
+	}
+
+	return a.ArtistRepo.NewArtist(ctx, artist_name)
+}
+
+func (a ArtistService) DeleteArtist(ctx context.Context, artist_name string) (err error) {
+	return a.ArtistRepo.DeleteArtist(ctx, artist_name)
+}
+
+// Rename changes the current name of artist_name to new_name. Their previous name is kept as an alias, so that they
+// can still be found by it.
+func (a ArtistService) Rename(ctx context.Context, artist_name, new_name string) (err error) {
+	new_name = strings.TrimSpace(new_name)
+	if new_name == "" {
+		return errorEmptyArtistName
+	}
+
+	return a.ArtistRepo.RenameArtist(ctx, artist_name, new_name)
+}
+
+func (a ArtistService) AddAlias(ctx context.Context, artist_name, alias string) (err error) {
+	alias = strings.TrimSpace(alias)
+	if alias == "" {
+		return errors.New("empty artist alias")
+	}
+
+	artist, err := a.ArtistRepo.GetArtist(ctx, artist_name)
+	if err != nil {
+		return err
+	}
+
+	if strings.EqualFold(artist.Name, alias) {
+		return errors.New("alias is the artist's current name")
+	}
+
+	return a.ArtistRepo.NewArtistAlias(ctx, artist.Name, alias)
+}
+
+func (a ArtistService) RemoveAlias(ctx context.Context, artist_name, alias string) (err error) {
+	return a.ArtistRepo.DeleteArtistAlias(ctx, artist_name, alias)
+}
+
+func (a ArtistService) AssignChannel(ctx context.Context, artist_name string, channel_id entities.YoutubeChannelID) (err error) {
+	if !channel_id.IsValid() || channel_id == entities.UnknownYoutubeChannelID {
+		return entities.ErrorInvalidYoutubeChannelID
+	}
+
+	return a.ArtistRepo.AssignArtistChannel(ctx, artist_name, channel_id)
+}
+
+func (a ArtistService) UnassignChannel(ctx context.Context, artist_name string, channel_id entities.YoutubeChannelID) (err error) {
+	return a.ArtistRepo.UnassignArtistChannel(ctx, artist_name, channel_id)
+}
+
+func (a ArtistService) GetArtist(ctx context.Context, artist_name string) (artist entities.Artist, err error) {
+	res, err := a.ArtistRepo.GetArtist(ctx, artist_name)
+	if err != nil {
+		return entities.Artist{}, err
+	}
+
+	return *res, nil
+}
+
+// GetChannelArtists returns the current name of every artist behind channel_id.
+func (a ArtistService) GetChannelArtists(ctx context.Context, channel_id entities.YoutubeChannelID) (artists []string, err error) {
+	return a.ArtistRepo.GetChannelArtists(ctx, channel_id)
+}
+
+// GetVideos returns every archived youtube video artist_name uploaded, across all of their channels, oldest first.
+func (a ArtistService) GetVideos(ctx context.Context, artist_name string) (videos []entities.YoutubeVideoID, err error) {
+	return a.ArtistRepo.GetArtistVideos(ctx, artist_name)
+}
+
+// GetParts returns every MAP part artist_name animated, whether it was credited to one of their channels or to any
+// name they've gone by.
+func (a ArtistService) GetParts(ctx context.Context, artist_name string) (parts []entities.ProjectPart, err error) {
+	return a.ArtistRepo.GetArtistParts(ctx, artist_name)
+}
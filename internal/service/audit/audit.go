@@ -0,0 +1,55 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/dtbead/wc-maps-archive/internal/entities"
+	"github.com/dtbead/wc-maps-archive/internal/storage"
+)
+
+type AuditService struct {
+	AuditRepo storage.AuditRepository
+}
+
+func NewService(AuditRepo storage.AuditRepository) *AuditService {
+	return &AuditService{AuditRepo: AuditRepo}
+}
+
+// Record logs action as having been done to the entity_id of entity by the actor ctx carries. before and after are
+// marshalled to JSON, a nil one being recorded as null.
+func (a AuditService) Record(ctx context.Context, entity entities.AuditEntity, entity_id string, action string, before, after any) (err error) {
+	b, err := json.Marshal(before)
+	if err != nil {
+		return err
+	}
+
+	c, err := json.Marshal(after)
+	if err != nil {
+		return err
+	}
+
+	_, err = a.AuditRepo.NewAuditEntry(ctx, &entities.AuditEntry{
+		Actor:    entities.ActorFrom(ctx),
+		Entity:   entity,
+		EntityID: entity_id,
+		Action:   action,
+		Before:   b,
+		After:    c,
+	})
+	return err
+}
+
+func (a AuditService) GetEntry(ctx context.Context, entry_id int64) (entry entities.AuditEntry, err error) {
+	e, err := a.AuditRepo.GetAuditEntry(ctx, entry_id)
+	if err != nil {
+		return entities.AuditEntry{}, err
+	}
+
+	return *e, nil
+}
+
+// GetEntityLog returns every change made to the entity_id of entity, oldest first.
+func (a AuditService) GetEntityLog(ctx context.Context, entity entities.AuditEntity, entity_id string) (entries []entities.AuditEntry, err error) {
+	return a.AuditRepo.GetAuditEntries(ctx, entity, entity_id)
+}
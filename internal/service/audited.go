@@ -0,0 +1,431 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"io"
+	"log"
+	"strconv"
+
+	"github.com/dtbead/wc-maps-archive/internal/entities"
+)
+
+// snapshot returns v as the state of something to record, or nil if err says it doesn't exist, so that it gets
+// recorded as null.
+func snapshot[T any](v T, err error) (any, error) {
+	if errors.Is(err, sql.ErrNoRows) || errors.Is(err, entities.ErrorNotFound) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return v, nil
+}
+
+// auditChange makes change, recording it as action on the entity_id of entity if it succeeds. state returns whatever
+// change touches, and is called before and after it so that the entry holds what changed. A change that fails isn't
+// recorded. The entry is written after change is made rather than along with it, so a change that's made but fails to
+// be recorded is logged instead of being reported as failed.
+func auditChange(ctx context.Context, audit AuditService, entity entities.AuditEntity, entity_id string, action string, state func() (any, error), change func() error) (err error) {
+	before, err := state()
+	if err != nil {
+		return err
+	}
+
+	if err := change(); err != nil {
+		return err
+	}
+
+	after, err := state()
+	if err == nil {
+		err = audit.Record(ctx, entity, entity_id, action, before, after)
+	}
+	if err != nil {
+		notRecorded(ctx, entity, entity_id, action, err)
+	}
+
+	return nil
+}
+
+// auditNew records the entity_id of entity as just having been made by action, state returning what it was made as.
+// Like auditChange, failing to record it is only logged.
+func auditNew(ctx context.Context, audit AuditService, entity entities.AuditEntity, entity_id string, action string, state func() (any, error)) {
+	after, err := state()
+	if err == nil {
+		err = audit.Record(ctx, entity, entity_id, action, nil, after)
+	}
+	if err != nil {
+		notRecorded(ctx, entity, entity_id, action, err)
+	}
+}
+
+func notRecorded(ctx context.Context, entity entities.AuditEntity, entity_id string, action string, err error) {
+	log.Printf("%s of %s %s by %s was made but not recorded in the audit log, %v", action, entity.ToString(), entity_id, entities.ActorFrom(ctx), err)
+}
+
+// auditedProjectService records every change made through a ProjectService. Parts, relations and statuses are recorded
+// against the project they belong to. The sidecars of the files of a changed project are rewritten through sidecars.
+type auditedProjectService struct {
+	ProjectService
+	audit    AuditService
+	sidecars func(ctx context.Context, file_ids []entities.FileID)
+}
+
+func (p auditedProjectService) change(ctx context.Context, project_uuid entities.ProjectUUID, action string, state func() (any, error), change func() error) error {
+	// files removed from the project no longer describe it either, so the files from before the change are kept as well
+	before, err := p.files(ctx, project_uuid)
+	if err != nil {
+		return err
+	}
+
+	err = auditChange(ctx, p.audit, entities.AuditEntityProject, string(project_uuid), action, state, change)
+	if err != nil {
+		return err
+	}
+
+	p.rewriteSidecars(ctx, project_uuid, before...)
+	return nil
+}
+
+// files returns every file whose sidecar describes project_uuid, being its own files and the uploads of its parts.
+func (p auditedProjectService) files(ctx context.Context, project_uuid entities.ProjectUUID) (file_ids []entities.FileID, err error) {
+	project, err := p.ProjectService.GetProject(ctx, project_uuid)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	parts, err := p.ProjectService.GetParts(ctx, project_uuid)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+
+	file_ids = project.FileIDs
+	for _, part := range parts {
+		if part.FileID.IsValid() {
+			file_ids = append(file_ids, part.FileID)
+		}
+	}
+
+	return file_ids, nil
+}
+
+// rewriteSidecars rewrites the sidecars of the files of project_uuid along with those of file_ids. Like an audit entry,
+// failing to find its files is only logged.
+func (p auditedProjectService) rewriteSidecars(ctx context.Context, project_uuid entities.ProjectUUID, file_ids ...entities.FileID) {
+	after, err := p.files(ctx, project_uuid)
+	if err != nil {
+		log.Printf("sidecars of project %s are out of date until sidecars are backfilled, %v", project_uuid, err)
+	}
+
+	p.sidecars(ctx, append(file_ids, after...))
+}
+
+// project returns the state of project_uuid as a whole.
+func (p auditedProjectService) project(ctx context.Context, project_uuid entities.ProjectUUID) func() (any, error) {
+	return func() (any, error) {
+		return snapshot(p.ProjectService.GetProject(ctx, project_uuid))
+	}
+}
+
+// projectField returns the state of a single field of project_uuid.
+func projectField[T any](ctx context.Context, p auditedProjectService, project_uuid entities.ProjectUUID, field func(entities.Project) T) func() (any, error) {
+	return func() (any, error) {
+		project, err := p.ProjectService.GetProject(ctx, project_uuid)
+		if err != nil {
+			return snapshot(project, err)
+		}
+
+		return field(project), nil
+	}
+}
+
+func projectFiles(p entities.Project) []entities.FileID { return p.FileIDs }
+
+func (p auditedProjectService) NewProject(ctx context.Context, project *entities.ProjectImport) (uuid entities.ProjectUUID, err error) {
+	uuid, err = p.ProjectService.NewProject(ctx, project)
+	if err != nil {
+		return uuid, err
+	}
+
+	auditNew(ctx, p.audit, entities.AuditEntityProject, string(uuid), "new project", p.project(ctx, uuid))
+	p.rewriteSidecars(ctx, uuid)
+	return uuid, nil
+}
+
+func (p auditedProjectService) DeleteProject(ctx context.Context, project_uuid entities.ProjectUUID) (err error) {
+	return p.change(ctx, project_uuid, "delete project", p.project(ctx, project_uuid), func() error {
+		return p.ProjectService.DeleteProject(ctx, project_uuid)
+	})
+}
+
+func (p auditedProjectService) AssignFile(ctx context.Context, project_uuid entities.ProjectUUID, file_id entities.FileID) (err error) {
+	return p.change(ctx, project_uuid, "assign file", projectField(ctx, p, project_uuid, projectFiles), func() error {
+		return p.ProjectService.AssignFile(ctx, project_uuid, file_id)
+	})
+}
+
+func (p auditedProjectService) UnassignFile(ctx context.Context, project_uuid entities.ProjectUUID, file_id entities.FileID) (err error) {
+	return p.change(ctx, project_uuid, "unassign file", projectField(ctx, p, project_uuid, projectFiles), func() error {
+		return p.ProjectService.UnassignFile(ctx, project_uuid, file_id)
+	})
+}
+
+func (p auditedProjectService) AssignPrimaryFile(ctx context.Context, project_uuid entities.ProjectUUID, file_id entities.FileID) (err error) {
+	return p.change(ctx, project_uuid, "assign primary file", projectField(ctx, p, project_uuid, projectFiles), func() error {
+		return p.ProjectService.AssignPrimaryFile(ctx, project_uuid, file_id)
+	})
+}
+
+func (p auditedProjectService) UnassignPrimaryFile(ctx context.Context, project_uuid entities.ProjectUUID, file_id entities.FileID) (err error) {
+	return p.change(ctx, project_uuid, "unassign primary file", projectField(ctx, p, project_uuid, projectFiles), func() error {
+		return p.ProjectService.UnassignPrimaryFile(ctx, project_uuid, file_id)
+	})
+}
+
+// youtube returns the youtube videos of project_uuid.
+func (p auditedProjectService) youtube(ctx context.Context, project_uuid entities.ProjectUUID) func() (any, error) {
+	return func() (any, error) {
+		return snapshot(p.ProjectService.GetProjectYoutube(ctx, project_uuid))
+	}
+}
+
+func (p auditedProjectService) AssignYoutube(ctx context.Context, project_uuid entities.ProjectUUID, youtube_id entities.YoutubeVideoID) (err error) {
+	return p.change(ctx, project_uuid, "assign youtube", p.youtube(ctx, project_uuid), func() error {
+		return p.ProjectService.AssignYoutube(ctx, project_uuid, youtube_id)
+	})
+}
+
+func (p auditedProjectService) UnassignYoutube(ctx context.Context, project_uuid entities.ProjectUUID, youtube_id entities.YoutubeVideoID) (err error) {
+	return p.change(ctx, project_uuid, "unassign youtube", p.youtube(ctx, project_uuid), func() error {
+		return p.ProjectService.UnassignYoutube(ctx, project_uuid, youtube_id)
+	})
+}
+
+func (p auditedProjectService) SetProjectType(ctx context.Context, project_uuid entities.ProjectUUID, project_type entities.ProjectType) (err error) {
+	state := projectField(ctx, p, project_uuid, func(p entities.Project) entities.ProjectType { return p.ProjectType })
+	return p.change(ctx, project_uuid, "set type", state, func() error {
+		return p.ProjectService.SetProjectType(ctx, project_uuid, project_type)
+	})
+}
+
+func (p auditedProjectService) SetDateAnnounced(ctx context.Context, project_uuid entities.ProjectUUID, date entities.PartialDate) (err error) {
+	state := projectField(ctx, p, project_uuid, func(p entities.Project) entities.PartialDate { return p.DateAnnounced })
+	return p.change(ctx, project_uuid, "set date announced", state, func() error {
+		return p.ProjectService.SetDateAnnounced(ctx, project_uuid, date)
+	})
+}
+
+func (p auditedProjectService) SetDateCompleted(ctx context.Context, project_uuid entities.ProjectUUID, date entities.PartialDate) (err error) {
+	state := projectField(ctx, p, project_uuid, func(p entities.Project) entities.PartialDate { return p.DateCompleted })
+	return p.change(ctx, project_uuid, "set date completed", state, func() error {
+		return p.ProjectService.SetDateCompleted(ctx, project_uuid, date)
+	})
+}
+
+func (p auditedProjectService) SetTitle(ctx context.Context, project_uuid entities.ProjectUUID, title string) (err error) {
+	state := projectField(ctx, p, project_uuid, func(p entities.Project) string { return p.Title })
+	return p.change(ctx, project_uuid, "set title", state, func() error {
+		return p.ProjectService.SetTitle(ctx, project_uuid, title)
+	})
+}
+
+func (p auditedProjectService) SetDescription(ctx context.Context, project_uuid entities.ProjectUUID, description string) (err error) {
+	state := projectField(ctx, p, project_uuid, func(p entities.Project) string { return p.Description })
+	return p.change(ctx, project_uuid, "set description", state, func() error {
+		return p.ProjectService.SetDescription(ctx, project_uuid, description)
+	})
+}
+
+// part returns the state of part_id.
+func (p auditedProjectService) part(ctx context.Context, part_id int64) func() (any, error) {
+	return func() (any, error) {
+		return snapshot(p.ProjectService.GetPart(ctx, part_id))
+	}
+}
+
+func (p auditedProjectService) NewPart(ctx context.Context, part *entities.ProjectPart) (part_id int64, err error) {
+	part_id, err = p.ProjectService.NewPart(ctx, part)
+	if err != nil {
+		return part_id, err
+	}
+
+	auditNew(ctx, p.audit, entities.AuditEntityProject, string(part.ProjectUUID), "new part", p.part(ctx, part_id))
+	p.rewriteSidecars(ctx, part.ProjectUUID)
+	return part_id, nil
+}
+
+func (p auditedProjectService) UpdatePart(ctx context.Context, part *entities.ProjectPart) (err error) {
+	if part == nil {
+		return p.ProjectService.UpdatePart(ctx, part)
+	}
+
+	// a part can't be moved to another project, so the one it's in now is the one it's recorded against
+	current, err := p.ProjectService.GetPart(ctx, part.ID)
+	if err != nil {
+		return err
+	}
+
+	return p.change(ctx, current.ProjectUUID, "update part", p.part(ctx, part.ID), func() error {
+		return p.ProjectService.UpdatePart(ctx, part)
+	})
+}
+
+func (p auditedProjectService) DeletePart(ctx context.Context, part_id int64) (err error) {
+	current, err := p.ProjectService.GetPart(ctx, part_id)
+	if err != nil {
+		return err
+	}
+
+	return p.change(ctx, current.ProjectUUID, "delete part", p.part(ctx, part_id), func() error {
+		return p.ProjectService.DeletePart(ctx, part_id)
+	})
+}
+
+// relations returns every relation project_uuid has.
+func (p auditedProjectService) relations(ctx context.Context, project_uuid entities.ProjectUUID) func() (any, error) {
+	return func() (any, error) {
+		return snapshot(p.ProjectService.GetRelations(ctx, project_uuid))
+	}
+}
+
+func (p auditedProjectService) Relate(ctx context.Context, relation entities.ProjectRelation) (err error) {
+	return p.change(ctx, relation.Project, "relate", p.relations(ctx, relation.Project), func() error {
+		return p.ProjectService.Relate(ctx, relation)
+	})
+}
+
+func (p auditedProjectService) Unrelate(ctx context.Context, relation entities.ProjectRelation) (err error) {
+	return p.change(ctx, relation.Project, "unrelate", p.relations(ctx, relation.Project), func() error {
+		return p.ProjectService.Unrelate(ctx, relation)
+	})
+}
+
+// status returns the current status of project_uuid.
+func (p auditedProjectService) status(ctx context.Context, project_uuid entities.ProjectUUID) func() (any, error) {
+	return func() (any, error) {
+		return snapshot(p.ProjectService.GetStatus(ctx, project_uuid))
+	}
+}
+
+func (p auditedProjectService) SetStatus(ctx context.Context, project_uuid entities.ProjectUUID, change entities.ProjectStatusChange) (status_id int64, err error) {
+	err = p.change(ctx, project_uuid, "set status", p.status(ctx, project_uuid), func() (err error) {
+		status_id, err = p.ProjectService.SetStatus(ctx, project_uuid, change)
+		return err
+	})
+	return status_id, err
+}
+
+func (p auditedProjectService) UndoStatus(ctx context.Context, project_uuid entities.ProjectUUID) (err error) {
+	return p.change(ctx, project_uuid, "undo status", p.status(ctx, project_uuid), func() error {
+		return p.ProjectService.UndoStatus(ctx, project_uuid)
+	})
+}
+
+// auditedFileService records every change made through a FileService. Temporary files aren't part of the archive, so
+// making one isn't recorded.
+type auditedFileService struct {
+	FileService
+	audit AuditService
+}
+
+func (f auditedFileService) change(ctx context.Context, file_id entities.FileID, action string, state func() (any, error), change func() error) error {
+	return auditChange(ctx, f.audit, entities.AuditEntityFile, strconv.FormatInt(int64(file_id), 10), action, state, change)
+}
+
+// file returns the state of file_id as stored.
+func (f auditedFileService) file(ctx context.Context, file_id entities.FileID) func() (any, error) {
+	return func() (any, error) {
+		return snapshot(f.FileService.GetFile(ctx, file_id))
+	}
+}
+
+func (f auditedFileService) NewFile(ctx context.Context, file io.Reader, extension string) (file_id entities.FileID, err error) {
+	file_id, err = f.FileService.NewFile(ctx, file, extension)
+	if err != nil {
+		return file_id, err
+	}
+
+	auditNew(ctx, f.audit, entities.AuditEntityFile, strconv.FormatInt(int64(file_id), 10), "new file", f.file(ctx, file_id))
+	return file_id, nil
+}
+
+func (f auditedFileService) AdoptFile(ctx context.Context, path_relative string) (file_id entities.FileID, err error) {
+	file_id, err = f.FileService.AdoptFile(ctx, path_relative)
+	if err != nil {
+		return file_id, err
+	}
+
+	auditNew(ctx, f.audit, entities.AuditEntityFile, strconv.FormatInt(int64(file_id), 10), "adopt file", f.file(ctx, file_id))
+	return file_id, nil
+}
+
+func (f auditedFileService) DeleteFile(ctx context.Context, file_id entities.FileID) (err error) {
+	return f.change(ctx, file_id, "delete file", f.file(ctx, file_id), func() error {
+		return f.FileService.DeleteFile(ctx, file_id)
+	})
+}
+
+func (f auditedFileService) NewFileVideo(ctx context.Context, file_id entities.FileID, video *entities.Video) (err error) {
+	state := func() (any, error) {
+		return snapshot(f.FileService.GetFileVideo(ctx, file_id))
+	}
+
+	return f.change(ctx, file_id, "set video metadata", state, func() error {
+		return f.FileService.NewFileVideo(ctx, file_id, video)
+	})
+}
+
+// auditedYoutubeService records every change made through a YoutubeService. The sidecars of the files of a changed
+// youtube video are rewritten through sidecars.
+type auditedYoutubeService struct {
+	YoutubeService
+	audit    AuditService
+	sidecars func(ctx context.Context, file_ids []entities.FileID)
+}
+
+func (y auditedYoutubeService) change(ctx context.Context, youtube_id entities.YoutubeVideoID, action string, state func() (any, error), change func() error) error {
+	err := auditChange(ctx, y.audit, entities.AuditEntityYoutube, string(youtube_id), action, state, change)
+	if err != nil {
+		return err
+	}
+
+	// files are never taken away from a youtube video, so its files after the change are every one it touched
+	file_ids, err := y.YoutubeService.GetYoutubeFileIDs(ctx, youtube_id)
+	if err != nil {
+		log.Printf("sidecars of youtube video %s are out of date until sidecars are backfilled, %v", youtube_id, err)
+	}
+
+	y.sidecars(ctx, file_ids)
+	return nil
+}
+
+func (y auditedYoutubeService) NewYoutube(ctx context.Context, file_id entities.FileID, youtube *entities.Youtube) (err error) {
+	if youtube == nil {
+		return y.YoutubeService.NewYoutube(ctx, file_id, youtube)
+	}
+
+	youtube_id := youtube.YouTube.YoutubeID
+	state := func() (any, error) {
+		return snapshot(y.YoutubeService.GetYoutubeVideo(ctx, youtube_id))
+	}
+
+	return y.change(ctx, youtube_id, "new youtube", state, func() error {
+		return y.YoutubeService.NewYoutube(ctx, file_id, youtube)
+	})
+}
+
+func (y auditedYoutubeService) AssignFile(ctx context.Context, youtube_id entities.YoutubeVideoID, file_id entities.FileID) (err error) {
+	state := func() (any, error) {
+		return snapshot(y.YoutubeService.GetYoutubeFileIDs(ctx, youtube_id))
+	}
+
+	return y.change(ctx, youtube_id, "assign file", state, func() error {
+		return y.YoutubeService.AssignFile(ctx, youtube_id, file_id)
+	})
+}
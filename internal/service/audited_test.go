@@ -0,0 +1,332 @@
+package service_test
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dtbead/wc-maps-archive/internal/entities"
+	"github.com/dtbead/wc-maps-archive/internal/helper"
+	helper_test "github.com/dtbead/wc-maps-archive/internal/helper/testing"
+	mock_storage "github.com/dtbead/wc-maps-archive/internal/helper/testing/mock/storage"
+	"github.com/dtbead/wc-maps-archive/internal/service"
+	"github.com/dtbead/wc-maps-archive/internal/storage"
+	"github.com/dtbead/wc-maps-archive/internal/storage/postgres"
+	"github.com/google/go-cmp/cmp"
+	_ "github.com/jackc/pgx/v5/stdlib"
+	"go.uber.org/mock/gomock"
+)
+
+func TestAuditedProjectService(t *testing.T) {
+	db := helper_test.NewDatabase(&helper_test.DefaultConnection)
+	defer db.Close()
+
+	repositories, err := postgres.NewRepository(db, t.TempDir())
+	if err != nil {
+		t.Fatalf("failed to create repositories, %v", err)
+	}
+	s := service.NewService(repositories)
+	ctx := entities.WithActor(context.Background(), "tester")
+
+	uuid, err := s.ProjectService.NewProject(ctx, &entities.ProjectImport{ProjectType: entities.ProjectMultiAnimation})
+	if err != nil {
+		t.Fatalf("ProjectService.NewProject() error = %v", err)
+	}
+
+	if err := s.ProjectService.SetTitle(ctx, uuid, "Firestar MAP"); err != nil {
+		t.Fatalf("ProjectService.SetTitle() error = %v", err)
+	}
+
+	// a change that fails isn't recorded
+	_, err = s.ProjectService.SetStatus(ctx, uuid, entities.ProjectStatusChange{Status: entities.ProjectStatusRevived, Date: time.Now()})
+	if !errors.Is(err, entities.ErrorInvalidStatusChange) {
+		t.Fatalf("ProjectService.SetStatus() error = %v, want %v", err, entities.ErrorInvalidStatusChange)
+	}
+
+	entries, err := s.AuditService.GetEntityLog(ctx, entities.AuditEntityProject, string(uuid))
+	if err != nil {
+		t.Fatalf("AuditService.GetEntityLog() error = %v", err)
+	}
+
+	type entry struct {
+		Actor, Action, Before, After string
+	}
+	got := make([]entry, len(entries))
+	for i, e := range entries {
+		got[i] = entry{Actor: e.Actor, Action: e.Action, Before: string(e.Before), After: string(e.After)}
+	}
+
+	// the new project's state is whatever the project came out as, so only whether there was one is checked
+	if len(got) > 0 {
+		var project entities.Project
+		if err := json.Unmarshal(entries[0].After, &project); err != nil || project.UUID != string(uuid) {
+			t.Errorf("new project recorded as %s, want project %s", entries[0].After, uuid)
+		}
+		got[0].After = ""
+	}
+
+	want := []entry{
+		{Actor: "tester", Action: "new project", Before: "null"},
+		{Actor: "tester", Action: "set title", Before: `""`, After: `"Firestar MAP"`},
+	}
+	if !cmp.Equal(got, want) {
+		t.Errorf("got diff %s", cmp.Diff(got, want))
+	}
+}
+
+func TestAuditedProjectService_NotRecorded(t *testing.T) {
+	db := helper_test.NewDatabase(&helper_test.DefaultConnection)
+	defer db.Close()
+
+	repositories, err := postgres.NewRepository(db, t.TempDir())
+	if err != nil {
+		t.Fatalf("failed to create repositories, %v", err)
+	}
+
+	ctrl := gomock.NewController(t)
+	audit := mock_storage.NewMockAuditRepository(ctrl)
+	audit.EXPECT().NewAuditEntry(gomock.Any(), gomock.Any()).Return(int64(0), errors.New("audit log unavailable")).AnyTimes()
+	repositories.Audit = audit
+
+	s := service.NewService(repositories)
+	ctx := context.Background()
+
+	// changes that are made are reported as made, even though they couldn't be recorded
+	uuid, err := s.ProjectService.NewProject(ctx, &entities.ProjectImport{ProjectType: entities.ProjectMultiAnimation})
+	if err != nil {
+		t.Fatalf("ProjectService.NewProject() error = %v", err)
+	}
+
+	if err := s.ProjectService.SetTitle(ctx, uuid, "Firestar MAP"); err != nil {
+		t.Errorf("ProjectService.SetTitle() error = %v, want nil", err)
+	}
+
+	p, err := s.ProjectService.GetProject(ctx, uuid)
+	if err != nil {
+		t.Fatalf("ProjectService.GetProject() error = %v", err)
+	}
+
+	if p.Title != "Firestar MAP" {
+		t.Errorf("ProjectService.GetProject() title = %q, want %q", p.Title, "Firestar MAP")
+	}
+}
+
+func TestAuditedFileService(t *testing.T) {
+	db := helper_test.NewDatabase(&helper_test.DefaultConnection)
+	defer db.Close()
+
+	repositories, err := postgres.NewRepository(db, t.TempDir())
+	if err != nil {
+		t.Fatalf("failed to create repositories, %v", err)
+	}
+	s := service.NewService(repositories)
+	ctx := context.Background()
+
+	file_id, err := s.FileService.NewFile(ctx, strings.NewReader(helper.RandomString(64)), "mkv")
+	if err != nil {
+		t.Fatalf("FileService.NewFile() error = %v", err)
+	}
+
+	if err := s.FileService.DeleteFile(ctx, file_id); err != nil {
+		t.Fatalf("FileService.DeleteFile() error = %v", err)
+	}
+
+	entries, err := s.AuditService.GetEntityLog(ctx, entities.AuditEntityFile, strconv.FormatInt(int64(file_id), 10))
+	if err != nil {
+		t.Fatalf("AuditService.GetEntityLog() error = %v", err)
+	}
+
+	var actions []string
+	for _, e := range entries {
+		actions = append(actions, e.Action)
+		if e.Actor != entities.UnknownActor {
+			t.Errorf("%s recorded as made by %q, want %q", e.Action, e.Actor, entities.UnknownActor)
+		}
+	}
+
+	if want := []string{"new file", "delete file"}; !cmp.Equal(actions, want) {
+		t.Fatalf("got diff %s", cmp.Diff(actions, want))
+	}
+
+	// a deleted file is recorded as no longer existing
+	if string(entries[1].After) != "null" {
+		t.Errorf("delete file recorded after as %s, want null", entries[1].After)
+	}
+}
+
+func TestAuditedProjectService_Changes(t *testing.T) {
+	const uuid entities.ProjectUUID = "0e4f8a3c-9d1b-4c55-8f3e-2a6b7c8d9e01"
+	const file_id entities.FileID = 7
+	const part_file_id entities.FileID = 9
+	const youtube_id entities.YoutubeVideoID = "dQw4w9WgXcQ"
+	ctx := entities.WithActor(context.Background(), "tester")
+
+	project := entities.Project{UUID: string(uuid), ProjectType: entities.ProjectMultiAnimation}
+	with_file := project
+	with_file.FileIDs = []entities.FileID{file_id}
+	multi_edit := project
+	multi_edit.ProjectType = entities.ProjectMultiEdit
+
+	tests := []struct {
+		name string
+		// before and after are what the project and its youtube videos are before and after the change
+		before, after                 entities.Project
+		youtube_before, youtube_after []entities.YoutubeVideoID
+		// expect sets up the change itself, calling changed once it's made
+		expect       func(projects *mock_storage.MockProjectRepository, changed func())
+		change       func(s *service.Service) error
+		wantErr      error
+		wantAction   string
+		wantBefore   string
+		wantAfter    string
+		wantSidecars []entities.FileID
+	}{
+		{
+			name: "assign youtube", before: project, after: with_file, youtube_after: []entities.YoutubeVideoID{youtube_id},
+			expect: func(projects *mock_storage.MockProjectRepository, changed func()) {
+				projects.EXPECT().AssignYoutube(gomock.Any(), uuid, youtube_id).DoAndReturn(func(context.Context, entities.ProjectUUID, entities.YoutubeVideoID) error {
+					changed()
+					return nil
+				})
+			},
+			change:     func(s *service.Service) error { return s.ProjectService.AssignYoutube(ctx, uuid, youtube_id) },
+			wantAction: "assign youtube", wantBefore: "null", wantAfter: `["dQw4w9WgXcQ"]`,
+			wantSidecars: []entities.FileID{file_id, part_file_id},
+		},
+		{
+			name: "unassign youtube", before: with_file, after: project, youtube_before: []entities.YoutubeVideoID{youtube_id},
+			expect: func(projects *mock_storage.MockProjectRepository, changed func()) {
+				projects.EXPECT().UnassignYoutube(gomock.Any(), uuid, youtube_id).DoAndReturn(func(context.Context, entities.ProjectUUID, entities.YoutubeVideoID) error {
+					changed()
+					return nil
+				})
+			},
+			change:     func(s *service.Service) error { return s.ProjectService.UnassignYoutube(ctx, uuid, youtube_id) },
+			wantAction: "unassign youtube", wantBefore: `["dQw4w9WgXcQ"]`, wantAfter: "null",
+			wantSidecars: []entities.FileID{file_id, part_file_id},
+		},
+		{
+			// the file no longer belongs to the project, so its sidecar has to stop describing it
+			name: "unassign file", before: with_file, after: project,
+			expect: func(projects *mock_storage.MockProjectRepository, changed func()) {
+				projects.EXPECT().UnassignProjectVideo(gomock.Any(), uuid, file_id).DoAndReturn(func(context.Context, entities.ProjectUUID, entities.FileID) error {
+					changed()
+					return nil
+				})
+			},
+			change:     func(s *service.Service) error { return s.ProjectService.UnassignFile(ctx, uuid, file_id) },
+			wantAction: "unassign file", wantBefore: "[7]", wantAfter: "null",
+			wantSidecars: []entities.FileID{file_id, part_file_id},
+		},
+		{
+			name: "set type", before: project, after: multi_edit,
+			expect: func(projects *mock_storage.MockProjectRepository, changed func()) {
+				projects.EXPECT().SetProjectType(gomock.Any(), uuid, entities.ProjectMultiEdit).DoAndReturn(func(context.Context, entities.ProjectUUID, entities.ProjectType) error {
+					changed()
+					return nil
+				})
+			},
+			change: func(s *service.Service) error {
+				return s.ProjectService.SetProjectType(ctx, uuid, entities.ProjectMultiEdit)
+			},
+			wantAction: "set type", wantBefore: `"multi-animation"`, wantAfter: `"multi-edit"`,
+			wantSidecars: []entities.FileID{part_file_id},
+		},
+		{
+			name: "assign primary file", before: project, after: project,
+			expect:  func(projects *mock_storage.MockProjectRepository, changed func()) {},
+			change:  func(s *service.Service) error { return s.ProjectService.AssignPrimaryFile(ctx, uuid, file_id) },
+			wantErr: entities.ErrorPrimaryFileUnsupported,
+		},
+		{
+			name: "unassign primary file", before: project, after: project,
+			expect:  func(projects *mock_storage.MockProjectRepository, changed func()) {},
+			change:  func(s *service.Service) error { return s.ProjectService.UnassignPrimaryFile(ctx, uuid, file_id) },
+			wantErr: entities.ErrorPrimaryFileUnsupported,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			files := mock_storage.NewMockFileRepository(ctrl)
+			youtube := mock_storage.NewMockYoutubeRepository(ctrl)
+			projects := mock_storage.NewMockProjectRepository(ctrl)
+			audit := mock_storage.NewMockAuditRepository(ctrl)
+
+			// the project is whatever it was before the change until changed is called
+			changed := false
+			tt.expect(projects, func() { changed = true })
+			projects.EXPECT().GetProject(gomock.Any(), uuid).DoAndReturn(func(context.Context, entities.ProjectUUID) (*entities.Project, error) {
+				p := tt.before
+				if changed {
+					p = tt.after
+				}
+				return &p, nil
+			}).AnyTimes()
+			projects.EXPECT().GetProjectYoutube(gomock.Any(), uuid).DoAndReturn(func(context.Context, entities.ProjectUUID) ([]entities.YoutubeVideoID, error) {
+				if changed {
+					return tt.youtube_after, nil
+				}
+				return tt.youtube_before, nil
+			}).AnyTimes()
+			projects.EXPECT().GetProjectParts(gomock.Any(), uuid).Return([]entities.ProjectPart{
+				{ProjectUUID: uuid, Number: 1, FileID: part_file_id},
+				{ProjectUUID: uuid, Number: 2, FileID: entities.InvalidFileID},
+			}, nil).AnyTimes()
+
+			var got []entities.AuditEntry
+			audit.EXPECT().NewAuditEntry(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, entry *entities.AuditEntry) (int64, error) {
+				got = append(got, *entry)
+				return int64(len(got)), nil
+			}).AnyTimes()
+
+			// what the sidecars hold is left to the sidecar tests, only which ones get rewritten is checked here
+			var gotSidecars []entities.FileID
+			files.EXPECT().GetFile(gomock.Any(), gomock.Any()).Return(&entities.File{Extension: "mp4"}, nil).AnyTimes()
+			files.EXPECT().GetFileVideo(gomock.Any(), gomock.Any()).Return(nil, sql.ErrNoRows).AnyTimes()
+			youtube.EXPECT().GetFileYoutubeID(gomock.Any(), gomock.Any()).Return(entities.YoutubeVideoID(""), sql.ErrNoRows).AnyTimes()
+			projects.EXPECT().GetFileProjects(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+			files.EXPECT().WriteSidecar(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, file_id entities.FileID, sidecar []byte) error {
+				gotSidecars = append(gotSidecars, file_id)
+				return nil
+			}).AnyTimes()
+
+			s := service.NewService(&storage.Repository{
+				File:    files,
+				Youtube: youtube,
+				Probe:   mock_storage.NewMockProbeRepository(ctrl),
+				Project: projects,
+				Audit:   audit,
+			})
+
+			if err := tt.change(s); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("change error = %v, want %v", err, tt.wantErr)
+			}
+
+			// a change that fails isn't recorded
+			var want []entities.AuditEntry
+			if tt.wantErr == nil {
+				want = []entities.AuditEntry{{
+					Actor:    "tester",
+					Entity:   entities.AuditEntityProject,
+					EntityID: string(uuid),
+					Action:   tt.wantAction,
+					Before:   json.RawMessage(tt.wantBefore),
+					After:    json.RawMessage(tt.wantAfter),
+				}}
+			}
+			if !cmp.Equal(got, want) {
+				t.Errorf("got diff %s", cmp.Diff(got, want))
+			}
+
+			if !cmp.Equal(gotSidecars, tt.wantSidecars) {
+				t.Errorf("rewrote sidecars got diff %s", cmp.Diff(gotSidecars, tt.wantSidecars))
+			}
+		})
+	}
+}
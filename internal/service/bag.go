@@ -0,0 +1,158 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"io"
+	"slices"
+
+	"github.com/dtbead/wc-maps-archive/internal/bagit"
+	"github.com/dtbead/wc-maps-archive/internal/entities"
+	file_helper "github.com/dtbead/wc-maps-archive/internal/helper/file"
+)
+
+type BagOptions struct {
+	// SourceOrganization is whoever is handing the bag over, recorded as its Source-Organization if set.
+	SourceOrganization string
+}
+
+// ExportProjectBag writes a BagIt bag of project_uuid to directory, which mustn't exist yet. Its payload is every file
+// of the project, and its bag-info.txt describes the project along with the youtube videos of its files.
+func (s Service) ExportProjectBag(ctx context.Context, directory string, project_uuid entities.ProjectUUID, opts BagOptions) error {
+	project, err := s.ProjectService.GetProject(ctx, project_uuid)
+	if err != nil {
+		return err
+	}
+
+	status, err := s.ProjectService.GetStatus(ctx, project_uuid)
+	if err != nil {
+		return err
+	}
+
+	bag := bagit.Bag{Info: opts.info()}
+	bag.Info = append(bag.Info,
+		bagit.InfoField{Label: "External-Identifier", Value: project.UUID},
+		bagit.InfoField{Label: "Title", Value: project.Title},
+		bagit.InfoField{Label: "Project-Type", Value: project.ProjectType.ToString()},
+		bagit.InfoField{Label: "Project-Status", Value: status.Status.ToString()},
+	)
+
+	if project.Description != "" {
+		bag.Info = append(bag.Info, bagit.InfoField{Label: "External-Description", Value: project.Description})
+	}
+
+	if !project.DateAnnounced.IsZero() {
+		bag.Info = append(bag.Info, bagit.InfoField{Label: "Date-Announced", Value: project.DateAnnounced.String()})
+	}
+
+	if !project.DateCompleted.IsZero() {
+		bag.Info = append(bag.Info, bagit.InfoField{Label: "Date-Completed", Value: project.DateCompleted.String()})
+	}
+
+	var youtube_ids []entities.YoutubeVideoID
+	for _, file_id := range project.FileIDs {
+		err = s.addBagFile(ctx, &bag, file_id)
+		if err != nil {
+			return err
+		}
+
+		youtube_id, err := s.YoutubeService.GetFileYoutube(ctx, file_id)
+		if errors.Is(err, sql.ErrNoRows) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		if !slices.Contains(youtube_ids, youtube_id) {
+			youtube_ids = append(youtube_ids, youtube_id)
+			bag.Info = append(bag.Info, bagit.InfoField{Label: "Youtube-Video", Value: youtubeURL(youtube_id)})
+		}
+	}
+
+	return bagit.Write(directory, bag)
+}
+
+// ExportYoutubeBag writes a BagIt bag of youtube_id to directory, which mustn't exist yet. Its payload is every file
+// downloaded of the video, and its bag-info.txt describes the video and its channel.
+func (s Service) ExportYoutubeBag(ctx context.Context, directory string, youtube_id entities.YoutubeVideoID, opts BagOptions) error {
+	yt, err := s.YoutubeService.GetYoutube(ctx, youtube_id)
+	if err != nil {
+		return err
+	}
+
+	file_ids, err := s.YoutubeService.GetYoutubeFileIDs(ctx, youtube_id)
+	if err != nil {
+		return err
+	}
+
+	bag := bagit.Bag{Info: opts.info()}
+	bag.Info = append(bag.Info,
+		bagit.InfoField{Label: "External-Identifier", Value: youtubeURL(youtube_id)},
+		bagit.InfoField{Label: "Title", Value: yt.Title},
+		bagit.InfoField{Label: "Upload-Date", Value: yt.YouTube.UploadDate.UTC().Format("2006-01-02")},
+	)
+
+	if yt.Description != "" {
+		bag.Info = append(bag.Info, bagit.InfoField{Label: "External-Description", Value: yt.Description})
+	}
+
+	if yt.Channel != nil {
+		bag.Info = append(bag.Info,
+			bagit.InfoField{Label: "Youtube-Channel-ID", Value: string(yt.Channel.ChannelID)},
+			bagit.InfoField{Label: "Youtube-Uploader", Value: yt.Channel.Uploader},
+		)
+	}
+
+	for _, file_id := range file_ids {
+		err = s.addBagFile(ctx, &bag, file_id)
+		if err != nil {
+			return err
+		}
+	}
+
+	return bagit.Write(directory, bag)
+}
+
+// addBagFile adds file_id to the payload of bag as <sha256>.<extension>, along with the info json yt-dlp printed when
+// downloading it as the tag file ytdlp/<sha256>.info.json, if it was kept.
+func (s Service) addBagFile(ctx context.Context, bag *bagit.Bag, file_id entities.FileID) error {
+	file, err := s.FileService.GetFile(ctx, file_id)
+	if err != nil {
+		return err
+	}
+
+	sha256 := file_helper.ByteToHexString(file.Hashes.SHA256)
+	bag.Payload = append(bag.Payload, bagit.Payload{
+		Path:   sha256 + "." + file.Extension,
+		Size:   file.Size,
+		Hashes: file.Hashes,
+		Open: func() (io.ReadCloser, error) {
+			return s.FileService.GetReader(ctx, file_id)
+		},
+	})
+
+	info, err := s.YoutubeService.GetYtdlpInfo(ctx, file_id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	bag.Tags = append(bag.Tags, bagit.TagFile{Path: "ytdlp/" + sha256 + ".info.json", Contents: info})
+	return nil
+}
+
+func (o BagOptions) info() []bagit.InfoField {
+	if o.SourceOrganization == "" {
+		return nil
+	}
+
+	return []bagit.InfoField{{Label: "Source-Organization", Value: o.SourceOrganization}}
+}
+
+func youtubeURL(youtube_id entities.YoutubeVideoID) string {
+	return "https://www.youtube.com/watch?v=" + string(youtube_id)
+}
@@ -0,0 +1,667 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/dtbead/wc-maps-archive/internal/bundle"
+	"github.com/dtbead/wc-maps-archive/internal/entities"
+)
+
+// BundleSelection is the part of the archive to export. Projects bring along their files and parts, and the youtube
+// videos of those, while youtube videos bring along the files downloaded of them.
+type BundleSelection struct {
+	Projects []entities.ProjectUUID
+	Youtube  []entities.YoutubeVideoID
+}
+
+// ExportBundle writes a bundle of selection to w, returning its manifest. Only relations between projects of the
+// bundle are kept, as the others couldn't be recreated by whoever imports it.
+func (s Service) ExportBundle(ctx context.Context, w io.Writer, selection BundleSelection) (manifest bundle.Manifest, err error) {
+	e := bundleExport{s: s, ctx: ctx, files: make(map[entities.FileID]string)}
+
+	for _, project_uuid := range selection.Projects {
+		if slices.Contains(e.project_uuids, project_uuid) {
+			continue
+		}
+
+		e.project_uuids = append(e.project_uuids, project_uuid)
+	}
+
+	// projects come first, as they add the youtube videos of their files and parts
+	for _, project_uuid := range e.project_uuids {
+		project, err := e.project(project_uuid)
+		if err != nil {
+			return bundle.Manifest{}, fmt.Errorf("project %s: %w", project_uuid, err)
+		}
+
+		e.manifest.Projects = append(e.manifest.Projects, project)
+	}
+
+	e.youtube_ids = append(e.youtube_ids, selection.Youtube...)
+	for i := 0; i < len(e.youtube_ids); i++ {
+		youtube_id := e.youtube_ids[i]
+		if slices.Contains(e.youtube_ids[:i], youtube_id) {
+			continue
+		}
+
+		youtube, err := e.youtube(youtube_id)
+		if err != nil {
+			return bundle.Manifest{}, fmt.Errorf("youtube video %s: %w", youtube_id, err)
+		}
+
+		e.manifest.Youtube = append(e.manifest.Youtube, youtube)
+	}
+
+	bw, err := bundle.NewWriter(w, e.manifest)
+	if err != nil {
+		return bundle.Manifest{}, err
+	}
+
+	for _, file_id := range e.file_ids {
+		err = e.writeFile(bw, file_id)
+		if err != nil {
+			return bundle.Manifest{}, fmt.Errorf("file %d: %w", file_id, err)
+		}
+	}
+
+	return e.manifest, bw.Close()
+}
+
+// bundleExport gathers everything going into a bundle. file_ids and youtube_ids are in the order they were come across
+// in, and files maps every file going into the bundle to its SHA256. every_relation keeps the relations to projects
+// which aren't in the bundle as well.
+type bundleExport struct {
+	s              Service
+	ctx            context.Context
+	manifest       bundle.Manifest
+	project_uuids  []entities.ProjectUUID
+	youtube_ids    []entities.YoutubeVideoID
+	file_ids       []entities.FileID
+	files          map[entities.FileID]string
+	every_relation bool
+}
+
+// file adds file_id to the bundle along with the youtube video it's a download of, returning its SHA256.
+func (e *bundleExport) file(file_id entities.FileID) (sha256 string, err error) {
+	if sha256, ok := e.files[file_id]; ok {
+		return sha256, nil
+	}
+
+	file, err := e.s.FileService.GetFile(e.ctx, file_id)
+	if err != nil {
+		return "", err
+	}
+
+	var video *entities.Video
+	v, err := e.s.FileService.GetFileVideo(e.ctx, file_id)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return "", err
+	}
+	if err == nil {
+		video = &v
+	}
+
+	f := bundle.NewFile(file, video)
+	e.manifest.Files = append(e.manifest.Files, f)
+	e.file_ids = append(e.file_ids, file_id)
+	e.files[file_id] = f.SHA256
+
+	youtube_id, err := e.s.YoutubeService.GetFileYoutube(e.ctx, file_id)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return "", err
+	}
+	if err == nil {
+		e.youtube_ids = append(e.youtube_ids, youtube_id)
+	}
+
+	return f.SHA256, nil
+}
+
+func (e *bundleExport) project(project_uuid entities.ProjectUUID) (project bundle.Project, err error) {
+	p, err := e.s.ProjectService.GetProject(e.ctx, project_uuid)
+	if err != nil {
+		return bundle.Project{}, err
+	}
+
+	project = bundle.Project{
+		UUID:          p.UUID,
+		Type:          p.ProjectType.ToString(),
+		DateAnnounced: p.DateAnnounced,
+		DateCompleted: p.DateCompleted,
+	}
+
+	for _, file_id := range p.FileIDs {
+		sha256, err := e.file(file_id)
+		if err != nil {
+			return bundle.Project{}, err
+		}
+
+		project.Files = append(project.Files, sha256)
+	}
+
+	titles, err := e.s.ProjectService.GetTitles(e.ctx, project_uuid)
+	if err != nil {
+		return bundle.Project{}, err
+	}
+
+	for _, t := range titles {
+		project.Titles = append(project.Titles, bundle.Revision{Text: t.Title, DateAdded: t.DateAdded})
+	}
+
+	descriptions, err := e.s.ProjectService.GetDescriptions(e.ctx, project_uuid)
+	if err != nil {
+		return bundle.Project{}, err
+	}
+
+	for _, d := range descriptions {
+		project.Descriptions = append(project.Descriptions, bundle.Revision{Text: d.Description, DateAdded: d.DateAdded})
+	}
+
+	parts, err := e.s.ProjectService.GetParts(e.ctx, project_uuid)
+	if err != nil {
+		return bundle.Project{}, err
+	}
+
+	for _, part := range parts {
+		var sha256 string
+		if part.FileID.IsValid() {
+			sha256, err = e.file(part.FileID)
+			if err != nil {
+				return bundle.Project{}, err
+			}
+		}
+
+		if part.YoutubeID != "" {
+			e.youtube_ids = append(e.youtube_ids, part.YoutubeID)
+		}
+
+		project.Parts = append(project.Parts, bundle.NewPart(part, sha256))
+	}
+
+	timeline, err := e.s.ProjectService.GetTimeline(e.ctx, project_uuid)
+	if err != nil {
+		return bundle.Project{}, err
+	}
+
+	for _, change := range timeline {
+		project.Timeline = append(project.Timeline, bundle.NewStatusChange(change))
+	}
+
+	relations, err := e.s.ProjectService.GetRelations(e.ctx, project_uuid)
+	if err != nil {
+		return bundle.Project{}, err
+	}
+
+	for _, relation := range relations {
+		if relation.Project != project_uuid || (!e.every_relation && !slices.Contains(e.project_uuids, relation.Related)) {
+			continue
+		}
+
+		project.Relations = append(project.Relations, bundle.NewRelation(relation))
+	}
+
+	return project, nil
+}
+
+func (e *bundleExport) youtube(youtube_id entities.YoutubeVideoID) (youtube bundle.Youtube, err error) {
+	yt, err := e.s.YoutubeService.GetYoutube(e.ctx, youtube_id)
+	if err != nil {
+		return bundle.Youtube{}, err
+	}
+
+	file_ids, err := e.s.YoutubeService.GetYoutubeFileIDs(e.ctx, youtube_id)
+	if err != nil {
+		return bundle.Youtube{}, err
+	}
+
+	files := make([]string, 0, len(file_ids))
+	for _, file_id := range file_ids {
+		sha256, err := e.file(file_id)
+		if err != nil {
+			return bundle.Youtube{}, err
+		}
+
+		files = append(files, sha256)
+	}
+
+	// the thumbnail is kept along with the first file, which is the one the video is stored with again on import
+	if len(file_ids) > 0 {
+		yt.Thumbnail, err = e.s.YoutubeService.GetThumbnail(e.ctx, file_ids[0])
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return bundle.Youtube{}, err
+		}
+	}
+
+	return bundle.NewYoutube(*yt, files), nil
+}
+
+func (e *bundleExport) writeFile(bw *bundle.Writer, file_id entities.FileID) error {
+	r, err := e.s.FileService.GetReader(e.ctx, file_id)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	return bw.WriteFile(e.files[file_id], r)
+}
+
+// BundleImportResult is what importing a bundle added to the archive. FileIDs maps the SHA256 of every file of the
+// bundle to its id in this archive, whether it was added or already archived.
+type BundleImportResult struct {
+	FileIDs                               map[string]entities.FileID
+	NewFiles, NewYoutube, NewProjects     int
+	SkippedParticipants, SkippedRelations int
+}
+
+// ImportBundle adds whatever of the bundle in r isn't archived yet, so importing a bundle again carries on where a
+// failed import stopped. Errors wrap entities.ErrorInvalidBundle for a malformed bundle and entities.ErrorHashMismatch
+// for corrupted files.
+func (s Service) ImportBundle(ctx context.Context, r io.Reader) (result BundleImportResult, err error) {
+	br, err := bundle.NewReader(r)
+	if err != nil {
+		return result, err
+	}
+	manifest := br.Manifest()
+	result.FileIDs = make(map[string]entities.FileID, len(manifest.Files))
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+
+		file, contents, err := br.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return result, err
+		}
+
+		file_id, added, err := s.importBundleFile(ctx, file, contents)
+		if err != nil {
+			return result, fmt.Errorf("file %s: %w", file.SHA256, err)
+		}
+
+		result.FileIDs[file.SHA256] = file_id
+		if added {
+			result.NewFiles++
+		}
+	}
+
+	for _, file := range manifest.Files {
+		if _, ok := result.FileIDs[file.SHA256]; !ok {
+			return result, fmt.Errorf("%w, the contents of file %q are missing", entities.ErrorInvalidBundle, file.SHA256)
+		}
+	}
+
+	batch_ctx, batch := s.batchSidecars(ctx)
+	err = s.importManifest(batch_ctx, manifest, &result)
+	if err != nil {
+		return result, err
+	}
+
+	// the sidecars of every file of the bundle are rewritten, as they may have gained youtube videos and projects
+	file_ids := make([]entities.FileID, 0, len(result.FileIDs))
+	for _, file_id := range result.FileIDs {
+		file_ids = append(file_ids, file_id)
+	}
+
+	return result, batch.write(ctx, file_ids...)
+}
+
+// importManifest adds the youtube videos and projects of manifest to the archive, along with the video metadata of its
+// files, all of which have to be stored already with their ids in result.FileIDs.
+func (s Service) importManifest(ctx context.Context, manifest bundle.Manifest, result *BundleImportResult) error {
+	for _, youtube := range manifest.Youtube {
+		added, err := s.importBundleYoutube(ctx, youtube, result.FileIDs)
+		if err != nil {
+			return fmt.Errorf("youtube video %s: %w", youtube.ID, err)
+		}
+
+		if added {
+			result.NewYoutube++
+		}
+	}
+
+	// the video metadata of files downloaded from youtube was stored along with their youtube video
+	for _, file := range manifest.Files {
+		if file.Video == nil {
+			continue
+		}
+
+		file_id := result.FileIDs[file.SHA256]
+		_, err := s.FileService.GetFileVideo(ctx, file_id)
+		if !errors.Is(err, sql.ErrNoRows) {
+			if err != nil {
+				return fmt.Errorf("file %s: %w", file.SHA256, err)
+			}
+
+			continue
+		}
+
+		video := file.Video.Entity()
+		err = s.FileService.NewFileVideo(ctx, file_id, &video)
+		if err != nil {
+			return fmt.Errorf("file %s: %w", file.SHA256, err)
+		}
+	}
+
+	for _, project := range manifest.Projects {
+		added, skipped, err := s.importBundleProject(ctx, project, result.FileIDs)
+		if err != nil {
+			return fmt.Errorf("project %s: %w", project.UUID, err)
+		}
+
+		result.SkippedParticipants += skipped
+		if added {
+			result.NewProjects++
+		}
+	}
+
+	// relations go last, as they may refer to projects further along the bundle
+	for _, project := range manifest.Projects {
+		skipped, err := s.importBundleRelations(ctx, project)
+		if err != nil {
+			return fmt.Errorf("project %s: %w", project.UUID, err)
+		}
+
+		result.SkippedRelations += skipped
+	}
+
+	return nil
+}
+
+// importBundleFile stores the contents of file, unless a file with the same contents is already archived. Contents are
+// only stored once they've been read in full, and so verified.
+func (s Service) importBundleFile(ctx context.Context, file bundle.File, contents io.Reader) (file_id entities.FileID, added bool, err error) {
+	hashes, err := file.Hashes()
+	if err != nil {
+		return entities.InvalidFileID, false, err
+	}
+
+	file_id, err = s.FileService.FindFile(ctx, hashes.SHA256)
+	if err == nil {
+		return file_id, false, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return entities.InvalidFileID, false, err
+	}
+
+	tmp, err := s.FileService.NewTempFile(ctx)
+	if err != nil {
+		return entities.InvalidFileID, false, err
+	}
+	defer tmp.Close()
+
+	_, err = io.Copy(tmp, contents)
+	if err != nil {
+		return entities.InvalidFileID, false, err
+	}
+
+	file_id, err = s.FileService.NewFile(ctx, tmp, file.Extension)
+	if err != nil {
+		return entities.InvalidFileID, false, err
+	}
+
+	return file_id, true, nil
+}
+
+// importBundleYoutube stores youtube along with its first file, unless it's already archived, and links it to the rest
+// of its files.
+func (s Service) importBundleYoutube(ctx context.Context, youtube bundle.Youtube, file_ids map[string]entities.FileID) (added bool, err error) {
+	youtube_id := entities.YoutubeVideoID(youtube.ID)
+
+	_, err = s.YoutubeService.GetYoutubeVideo(ctx, youtube_id)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return false, err
+	}
+
+	if errors.Is(err, sql.ErrNoRows) {
+		first := file_ids[youtube.Files[0]]
+		err = s.YoutubeService.NewYoutube(ctx, first, youtube.Entity(first))
+		if err != nil {
+			return false, err
+		}
+
+		added = true
+	}
+
+	for _, sha256 := range youtube.Files {
+		file_id := file_ids[sha256]
+		linked, err := s.YoutubeService.GetFileYoutube(ctx, file_id)
+		if err == nil {
+			if linked != youtube_id {
+				return added, fmt.Errorf("file %s is already a download of youtube video %s", sha256, linked)
+			}
+
+			continue
+		}
+		if !errors.Is(err, sql.ErrNoRows) {
+			return added, err
+		}
+
+		err = s.YoutubeService.AssignFile(ctx, youtube_id, file_id)
+		if err != nil {
+			return added, err
+		}
+	}
+
+	return added, nil
+}
+
+// importBundleProject creates project, unless it's already archived, and brings its history, parts and files up to
+// date with the bundle, so that importing a bundle again finishes an import that was interrupted. The participants of
+// parts are dropped, keeping their name, if their youtube channel isn't archived, and skipped says how many were.
+func (s Service) importBundleProject(ctx context.Context, project bundle.Project, file_ids map[string]entities.FileID) (added bool, skipped int, err error) {
+	project_uuid := entities.ProjectUUID(project.UUID)
+
+	existing, err := s.ProjectService.GetProject(ctx, project_uuid)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return false, 0, err
+	}
+
+	if errors.Is(err, sql.ErrNoRows) {
+		project_type, err := entities.NewProjectType(project.Type)
+		if err != nil {
+			return false, 0, fmt.Errorf("%w, %w", entities.ErrorInvalidBundle, err)
+		}
+
+		project_uuid, err = s.ProjectService.NewProject(ctx, &entities.ProjectImport{
+			UUID:          project_uuid,
+			ProjectType:   project_type,
+			DateAnnounced: project.DateAnnounced,
+			DateCompleted: project.DateCompleted,
+		})
+		if err != nil {
+			return false, 0, err
+		}
+
+		added = true
+	}
+
+	skipped, err = s.importBundleProjectHistory(ctx, project_uuid, project, file_ids)
+	if err != nil {
+		return added, skipped, err
+	}
+
+	for _, sha256 := range project.Files {
+		file_id := file_ids[sha256]
+		if slices.Contains(existing.FileIDs, file_id) {
+			continue
+		}
+
+		err = s.ProjectService.AssignFile(ctx, project_uuid, file_id)
+		if err != nil {
+			return added, skipped, err
+		}
+	}
+
+	return added, skipped, nil
+}
+
+// missingHistory returns what's left of bundled once the history already archived is taken off its start, both
+// oldest first. History that doesn't start out the same as the bundle's was changed since, and is kept as it is by
+// returning nothing.
+func missingHistory[T, U any](archived []T, bundled []U, same func(T, U) bool) []U {
+	if len(archived) > len(bundled) {
+		return nil
+	}
+
+	for i := range archived {
+		if !same(archived[i], bundled[i]) {
+			return nil
+		}
+	}
+
+	return bundled[len(archived):]
+}
+
+// importBundleProjectHistory adds whatever of the titles, descriptions, timeline and parts of project isn't archived
+// yet. Parts are told apart by their number.
+func (s Service) importBundleProjectHistory(ctx context.Context, project_uuid entities.ProjectUUID, project bundle.Project, file_ids map[string]entities.FileID) (skipped int, err error) {
+	titles, err := s.ProjectService.GetTitles(ctx, project_uuid)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return skipped, err
+	}
+	slices.Reverse(titles)
+
+	for _, title := range missingHistory(titles, project.Titles, func(t entities.ProjectTitle, r bundle.Revision) bool {
+		return t.Title == strings.TrimSpace(r.Text)
+	}) {
+		err = s.ProjectService.SetTitle(ctx, project_uuid, title.Text)
+		if err != nil {
+			return skipped, err
+		}
+	}
+
+	descriptions, err := s.ProjectService.GetDescriptions(ctx, project_uuid)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return skipped, err
+	}
+	slices.Reverse(descriptions)
+
+	for _, description := range missingHistory(descriptions, project.Descriptions, func(d entities.ProjectDescription, r bundle.Revision) bool {
+		return d.Description == strings.TrimSpace(r.Text)
+	}) {
+		err = s.ProjectService.SetDescription(ctx, project_uuid, description.Text)
+		if err != nil {
+			return skipped, err
+		}
+	}
+
+	timeline, err := s.ProjectService.GetTimeline(ctx, project_uuid)
+	if err != nil {
+		return skipped, err
+	}
+
+	for _, change := range missingHistory(timeline, project.Timeline, func(c entities.ProjectStatusChange, b bundle.StatusChange) bool {
+		return c.Status.ToString() == b.Status && c.Date.Equal(b.Date.UTC().Truncate(time.Second)) && c.Note == b.Note
+	}) {
+		c, err := change.Entity()
+		if err != nil {
+			return skipped, err
+		}
+
+		_, err = s.ProjectService.SetStatus(ctx, project_uuid, c)
+		if err != nil {
+			return skipped, err
+		}
+	}
+
+	parts, err := s.ProjectService.GetParts(ctx, project_uuid)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return skipped, err
+	}
+
+	for _, p := range project.Parts {
+		if slices.ContainsFunc(parts, func(part entities.ProjectPart) bool { return part.Number == p.Number }) {
+			continue
+		}
+
+		file_id := entities.InvalidFileID
+		if p.File != "" {
+			file_id = file_ids[p.File]
+		}
+
+		part, err := p.Entity(project_uuid, file_id)
+		if err != nil {
+			return skipped, err
+		}
+
+		if part.YoutubeID != "" {
+			_, err := s.YoutubeService.GetYoutubeVideo(ctx, part.YoutubeID)
+			if err != nil && !errors.Is(err, sql.ErrNoRows) {
+				return skipped, err
+			}
+			if err != nil {
+				part.YoutubeID = ""
+			}
+		}
+
+		if part.Participant != "" {
+			// channels only get archived along with their videos
+			_, err := s.YoutubeService.GetChannelVideos(ctx, part.Participant)
+			if err != nil && !errors.Is(err, entities.ErrorNoChannelVideos) {
+				return skipped, err
+			}
+			if err != nil {
+				part.Participant = ""
+				skipped++
+			}
+		}
+
+		_, err = s.ProjectService.NewPart(ctx, &part)
+		if err != nil {
+			return skipped, fmt.Errorf("part %d: %w", p.Number, err)
+		}
+	}
+
+	return skipped, nil
+}
+
+// importBundleRelations relates project to the projects it was related to, skipping the relations which are already
+// archived and those to projects which aren't. skipped says how many weren't archived.
+func (s Service) importBundleRelations(ctx context.Context, project bundle.Project) (skipped int, err error) {
+	project_uuid := entities.ProjectUUID(project.UUID)
+
+	existing, err := s.ProjectService.GetRelations(ctx, project_uuid)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, r := range project.Relations {
+		relation, err := r.Entity(project_uuid)
+		if err != nil {
+			return skipped, err
+		}
+
+		if slices.ContainsFunc(existing, func(e entities.ProjectRelation) bool {
+			return e.Project == relation.Project && e.Related == relation.Related && e.Type == relation.Type
+		}) {
+			continue
+		}
+
+		_, err = s.ProjectService.GetProject(ctx, relation.Related)
+		if errors.Is(err, sql.ErrNoRows) {
+			skipped++
+			continue
+		}
+		if err != nil {
+			return skipped, err
+		}
+
+		err = s.ProjectService.Relate(ctx, relation)
+		if err != nil {
+			return skipped, err
+		}
+	}
+
+	return skipped, nil
+}
@@ -0,0 +1,168 @@
+package service_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/dtbead/wc-maps-archive/internal/bundle"
+	"github.com/dtbead/wc-maps-archive/internal/entities"
+	"github.com/dtbead/wc-maps-archive/internal/helper"
+	file_helper "github.com/dtbead/wc-maps-archive/internal/helper/file"
+	helper_test "github.com/dtbead/wc-maps-archive/internal/helper/testing"
+	"github.com/dtbead/wc-maps-archive/internal/service"
+	"github.com/dtbead/wc-maps-archive/internal/storage/postgres"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestService_ImportBundle_Interrupted(t *testing.T) {
+	db := helper_test.NewDatabase(&helper_test.DefaultConnection)
+	defer db.Close()
+
+	repositories, err := postgres.NewRepository(db, t.TempDir())
+	if err != nil {
+		t.Fatalf("failed to create repositories, %v", err)
+	}
+	s := service.NewService(repositories)
+	ctx := context.Background()
+
+	contents := []byte(helper.RandomString(64))
+	hashes, size, err := file_helper.GetHash(bytes.NewReader(contents))
+	if err != nil {
+		t.Fatalf("failed to hash contents, %v", err)
+	}
+	file := bundle.NewFile(entities.File{Extension: "mp4", Size: size, Hashes: hashes}, nil)
+
+	project := bundle.Project{
+		UUID:         helper.RandomUUID(),
+		Type:         entities.ProjectMultiAnimation.ToString(),
+		Titles:       []bundle.Revision{{Text: "Firestar MAP"}, {Text: "Firestar MAP - COMPLETED"}},
+		Descriptions: []bundle.Revision{{Text: "parts are 10 seconds long"}},
+		Files:        []string{file.SHA256},
+		Parts: []bundle.Part{
+			{Number: 1, Status: "completed", ParticipantName: "leafstar"},
+			{Number: 2, Status: "completed", ParticipantName: "ashfur"},
+		},
+		Timeline: []bundle.StatusChange{
+			{Status: entities.ProjectStatusAnnounced.ToString(), Date: time.Date(2015, 3, 1, 0, 0, 0, 0, time.UTC)},
+			{Status: entities.ProjectStatusCompleted.ToString(), Date: time.Date(2015, 9, 1, 0, 0, 0, 0, time.UTC), Note: "on time"},
+		},
+	}
+	manifest := bundle.Manifest{Files: []bundle.File{file}, Projects: []bundle.Project{project}}
+
+	// an import interrupted partway leaves the project with only the start of its history
+	uuid := entities.ProjectUUID(project.UUID)
+	_, err = s.ProjectService.NewProject(ctx, &entities.ProjectImport{UUID: uuid, ProjectType: entities.ProjectMultiAnimation})
+	if err != nil {
+		t.Fatalf("ProjectService.NewProject() error = %v", err)
+	}
+
+	if err := s.ProjectService.SetTitle(ctx, uuid, project.Titles[0].Text); err != nil {
+		t.Fatalf("ProjectService.SetTitle() error = %v", err)
+	}
+
+	change, err := project.Timeline[0].Entity()
+	if err != nil {
+		t.Fatalf("bundle.StatusChange.Entity() error = %v", err)
+	}
+
+	if _, err := s.ProjectService.SetStatus(ctx, uuid, change); err != nil {
+		t.Fatalf("ProjectService.SetStatus() error = %v", err)
+	}
+
+	part, err := project.Parts[0].Entity(uuid, entities.InvalidFileID)
+	if err != nil {
+		t.Fatalf("bundle.Part.Entity() error = %v", err)
+	}
+
+	if _, err := s.ProjectService.NewPart(ctx, &part); err != nil {
+		t.Fatalf("ProjectService.NewPart() error = %v", err)
+	}
+
+	// importing the bundle again finishes the import, and importing it after that changes nothing
+	for i := range 2 {
+		var buf bytes.Buffer
+		w, err := bundle.NewWriter(&buf, manifest)
+		if err != nil {
+			t.Fatalf("bundle.NewWriter() error = %v", err)
+		}
+
+		if err := w.WriteFile(file.SHA256, bytes.NewReader(contents)); err != nil {
+			t.Fatalf("bundle.Writer.WriteFile() error = %v", err)
+		}
+
+		if err := w.Close(); err != nil {
+			t.Fatalf("bundle.Writer.Close() error = %v", err)
+		}
+
+		result, err := s.ImportBundle(ctx, &buf)
+		if err != nil {
+			t.Fatalf("import %d, Service.ImportBundle() error = %v", i, err)
+		}
+
+		if result.NewProjects != 0 {
+			t.Errorf("import %d, Service.ImportBundle() NewProjects = %d, want 0", i, result.NewProjects)
+		}
+
+		titles, err := s.ProjectService.GetTitles(ctx, uuid)
+		if err != nil {
+			t.Fatalf("ProjectService.GetTitles() error = %v", err)
+		}
+
+		var got_titles []string
+		for _, title := range titles {
+			got_titles = append(got_titles, title.Title)
+		}
+
+		if want := []string{"Firestar MAP - COMPLETED", "Firestar MAP"}; !cmp.Equal(got_titles, want) {
+			t.Errorf("import %d, titles got diff %s", i, cmp.Diff(got_titles, want))
+		}
+
+		descriptions, err := s.ProjectService.GetDescriptions(ctx, uuid)
+		if err != nil {
+			t.Fatalf("ProjectService.GetDescriptions() error = %v", err)
+		}
+
+		if len(descriptions) != 1 {
+			t.Errorf("import %d, ProjectService.GetDescriptions() = %v, want a single description", i, descriptions)
+		}
+
+		timeline, err := s.ProjectService.GetTimeline(ctx, uuid)
+		if err != nil {
+			t.Fatalf("ProjectService.GetTimeline() error = %v", err)
+		}
+
+		var got_statuses []string
+		for _, c := range timeline {
+			got_statuses = append(got_statuses, c.Status.ToString())
+		}
+
+		if want := []string{project.Timeline[0].Status, project.Timeline[1].Status}; !cmp.Equal(got_statuses, want) {
+			t.Errorf("import %d, timeline got diff %s", i, cmp.Diff(got_statuses, want))
+		}
+
+		parts, err := s.ProjectService.GetParts(ctx, uuid)
+		if err != nil {
+			t.Fatalf("ProjectService.GetParts() error = %v", err)
+		}
+
+		var got_parts []int
+		for _, p := range parts {
+			got_parts = append(got_parts, p.Number)
+		}
+
+		if want := []int{1, 2}; !cmp.Equal(got_parts, want) {
+			t.Errorf("import %d, parts got diff %s", i, cmp.Diff(got_parts, want))
+		}
+
+		p, err := s.ProjectService.GetProject(ctx, uuid)
+		if err != nil {
+			t.Fatalf("ProjectService.GetProject() error = %v", err)
+		}
+
+		if want := []entities.FileID{result.FileIDs[file.SHA256]}; !cmp.Equal(p.FileIDs, want) {
+			t.Errorf("import %d, files got diff %s", i, cmp.Diff(p.FileIDs, want))
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package character
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/dtbead/wc-maps-archive/internal/entities"
+	"github.com/dtbead/wc-maps-archive/internal/storage"
+)
+
+type CharacterService struct {
+	CharacterRepo storage.CharacterRepository
+}
+
+func NewService(CharacterRepo storage.CharacterRepository) *CharacterService {
+	return &CharacterService{CharacterRepo: CharacterRepo}
+}
+
+// IsValidCharacter trims the name, series and aliases of character, and checks that none of them are left empty.
+func IsValidCharacter(character *entities.Character) error {
+	if character == nil {
+		return entities.ErrorInvalidCharacterPtr
+	}
+
+	character.Name = strings.TrimSpace(character.Name)
+	character.Series = strings.TrimSpace(character.Series)
+
+	switch {
+	case character.Name == "":
+		return errors.New("empty character name")
+	case character.Series == "":
+		return errors.New("empty character series")
+	}
+
+	for i := range character.Aliases {
+		if err := IsValidAlias(&character.Aliases[i]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func IsValidAlias(alias *entities.CharacterAlias) error {
+	alias.Name = strings.TrimSpace(alias.Name)
+	if alias.Name == "" {
+		return errors.New("empty character alias")
+	}
+
+	return nil
+}
+
+func (c CharacterService) NewCharacter(ctx context.Context, character *entities.Character) (character_id int, err error) {
+	if err := IsValidCharacter(character); err != nil {
+		return 0, err
+	}
+
+	return c.CharacterRepo.NewCharacter(ctx, character)
+}
+
+// UpdateCharacter updates the name, series and original flag of character. Aliases are changed through AddAlias and
+// RemoveAlias instead.
+func (c CharacterService) UpdateCharacter(ctx context.Context, character *entities.Character) (err error) {
+	if err := IsValidCharacter(character); err != nil {
+		return err
+	}
+
+	return c.CharacterRepo.UpdateCharacter(ctx, character)
+}
+
+func (c CharacterService) DeleteCharacter(ctx context.Context, character_id int) (err error) {
+	return c.CharacterRepo.DeleteCharacter(ctx, character_id)
+}
+
+func (c CharacterService) GetCharacter(ctx context.Context, character_id int) (character entities.Character, err error) {
+	res, err := c.CharacterRepo.GetCharacter(ctx, character_id)
+	if err != nil {
+		return entities.Character{}, err
+	}
+
+	return *res, nil
+}
+
+// AddAlias adds a name character_id went by, such as Firepaw with NameStageApprentice for Firestar. Adding an alias
+// character_id already has changes its stage.
+func (c CharacterService) AddAlias(ctx context.Context, character_id int, alias entities.CharacterAlias) (err error) {
+	if err := IsValidAlias(&alias); err != nil {
+		return err
+	}
+
+	return c.CharacterRepo.NewCharacterAlias(ctx, character_id, alias)
+}
+
+func (c CharacterService) RemoveAlias(ctx context.Context, character_id int, name string) (err error) {
+	return c.CharacterRepo.DeleteCharacterAlias(ctx, character_id, strings.TrimSpace(name))
+}
+
+// FindCharacters returns every character that has gone by name, at any stage of its life.
+func (c CharacterService) FindCharacters(ctx context.Context, name string) (characters []entities.Character, err error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, errors.New("empty character name")
+	}
+
+	return c.CharacterRepo.FindCharacters(ctx, name)
+}
+
+func (c CharacterService) GetSeriesCharacters(ctx context.Context, series string) (characters []entities.Character, err error) {
+	return c.CharacterRepo.GetSeriesCharacters(ctx, strings.TrimSpace(series))
+}
+
+func (c CharacterService) TagProject(ctx context.Context, project_uuid entities.ProjectUUID, character_id int) (err error) {
+	return c.CharacterRepo.TagProjectCharacter(ctx, project_uuid, character_id)
+}
+
+func (c CharacterService) UntagProject(ctx context.Context, project_uuid entities.ProjectUUID, character_id int) (err error) {
+	return c.CharacterRepo.UntagProjectCharacter(ctx, project_uuid, character_id)
+}
+
+func (c CharacterService) GetProjectCharacters(ctx context.Context, project_uuid entities.ProjectUUID) (characters []entities.Character, err error) {
+	return c.CharacterRepo.GetProjectCharacters(ctx, project_uuid)
+}
+
+func (c CharacterService) TagPart(ctx context.Context, part_id int64, character_id int) (err error) {
+	return c.CharacterRepo.TagPartCharacter(ctx, part_id, character_id)
+}
+
+func (c CharacterService) UntagPart(ctx context.Context, part_id int64, character_id int) (err error) {
+	return c.CharacterRepo.UntagPartCharacter(ctx, part_id, character_id)
+}
+
+func (c CharacterService) GetPartCharacters(ctx context.Context, part_id int64) (characters []entities.Character, err error) {
+	return c.CharacterRepo.GetPartCharacters(ctx, part_id)
+}
+
+// GetProjects returns every project character_id appears in, whether it was tagged in the project itself or in one of
+// its parts.
+func (c CharacterService) GetProjects(ctx context.Context, character_id int) (uuids []entities.ProjectUUID, err error) {
+	return c.CharacterRepo.GetCharacterProjects(ctx, character_id)
+}
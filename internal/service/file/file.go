@@ -2,7 +2,9 @@ package file
 
 import (
 	"context"
+	"errors"
 	"io"
+	"os"
 
 	"github.com/dtbead/wc-maps-archive/internal/entities"
 	"github.com/dtbead/wc-maps-archive/internal/storage"
@@ -23,16 +25,104 @@ func (f FileService) DeleteFile(ctx context.Context, file_id entities.FileID) (e
 	return f.FileRepo.DeleteFile(ctx, file_id)
 }
 
+func (f FileService) GetFile(ctx context.Context, file_id entities.FileID) (file entities.File, err error) {
+	meta, err := f.FileRepo.GetFile(ctx, file_id)
+	if err != nil {
+		return entities.File{}, err
+	}
+
+	return *meta, nil
+}
+
 func (f FileService) NewTempFile(ctx context.Context) (file io.ReadWriteCloser, err error) {
 	return f.FileRepo.NewTempFile(ctx)
 }
 
+func (f FileService) NewFileVideo(ctx context.Context, file_id entities.FileID, video *entities.Video) (err error) {
+	if !file_id.IsValid() {
+		return errors.New("invalid file_id")
+	}
+
+	if video == nil {
+		return entities.ErrorInvalidVideoPtr
+	}
+
+	if video.Duration < 0 || video.Width < 0 || video.Height < 0 || video.Fps < 0 {
+		return errors.New("invalid video metadata")
+	}
+
+	return f.FileRepo.NewFileVideo(ctx, file_id, video)
+}
+
+func (f FileService) GetFileVideo(ctx context.Context, file_id entities.FileID) (video entities.Video, err error) {
+	if !file_id.IsValid() {
+		return entities.Video{}, errors.New("invalid file_id")
+	}
+
+	v, err := f.FileRepo.GetFileVideo(ctx, file_id)
+	if err != nil {
+		return entities.Video{}, err
+	}
+
+	return *v, nil
+}
+
 func (f FileService) GetHash(ctx context.Context, file_id entities.FileID) (err error, hashes entities.Hashes) {
 	panic("unimplemented")
 }
+
+// GetReader opens the stored contents of file_id for reading.
 func (f FileService) GetReader(ctx context.Context, file_id entities.FileID) (file io.ReadCloser, err error) {
-	panic("unimplemented")
+	meta, err := f.FileRepo.GetFile(ctx, file_id)
+	if err != nil {
+		return nil, err
+	}
+
+	return os.Open(meta.PathAbsolute)
 }
+
+// FindFile returns the stored file whose contents hash to sha256, or sql.ErrNoRows if there's none.
+func (f FileService) FindFile(ctx context.Context, sha256 []byte) (file_id entities.FileID, err error) {
+	if len(sha256) != 32 {
+		return entities.InvalidFileID, errors.New("invalid sha256")
+	}
+
+	return f.FileRepo.GetFileIDBySHA256(ctx, sha256)
+}
+
+// AdoptFile stores the file already at path_relative within the storage root, which is only expected to happen when
+// rebuilding the database.
+func (f FileService) AdoptFile(ctx context.Context, path_relative string) (file_id entities.FileID, err error) {
+	return f.FileRepo.AdoptFile(ctx, path_relative)
+}
+
+// GetStoredPaths returns the path of every file within the storage root, relative to it, whether it's stored or not.
+// Files still being added or deleted are left out.
+func (f FileService) GetStoredPaths(ctx context.Context) (paths []string, err error) {
+	return f.FileRepo.GetStoredPaths(ctx)
+}
+
+func (f FileService) WriteSidecar(ctx context.Context, file_id entities.FileID, sidecar []byte) (err error) {
+	if !file_id.IsValid() {
+		return errors.New("invalid file_id")
+	}
+
+	return f.FileRepo.WriteSidecar(ctx, file_id, sidecar)
+}
+
+func (f FileService) ReadSidecar(ctx context.Context, path_relative string) (sidecar []byte, err error) {
+	return f.FileRepo.ReadSidecar(ctx, path_relative)
+}
+
 func (f FileService) GetFileRelationship(ctx context.Context, file_id entities.FileID) (relationships entities.FileRelationship, err error) {
 	panic("unimplemented")
 }
+
+// ListFiles returns a page of every stored file, along with the cursor of the next page, which is empty on the last one.
+func (f FileService) ListFiles(ctx context.Context, opts entities.ListOptions) (file_ids []entities.FileID, next_cursor string, err error) {
+	if err := opts.Normalize(); err != nil {
+		return nil, "", err
+	}
+
+	return f.FileRepo.ListFiles(ctx, opts)
+}
@@ -0,0 +1,102 @@
+package fingerprint
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"slices"
+
+	"github.com/dtbead/wc-maps-archive/internal/entities"
+	"github.com/dtbead/wc-maps-archive/internal/fingerprint"
+	"github.com/dtbead/wc-maps-archive/internal/storage"
+)
+
+type FingerprintService struct {
+	FingerprintRepo storage.FingerprintRepository
+	FileRepo        storage.FileRepository
+}
+
+func NewService(FingerprintRepo storage.FingerprintRepository, FileRepo storage.FileRepository) *FingerprintService {
+	return &FingerprintService{FingerprintRepo: FingerprintRepo, FileRepo: FileRepo}
+}
+
+// FingerprintFile fingerprints the stored file of file_id, replacing any earlier fingerprint of it.
+func (f FingerprintService) FingerprintFile(ctx context.Context, file_id entities.FileID, fingerprinter entities.VideoFingerprinter) (err error) {
+	if fingerprinter == nil {
+		return errors.New("nil fingerprinter given")
+	}
+
+	file, err := f.FileRepo.GetFile(ctx, file_id)
+	if err != nil {
+		return err
+	}
+
+	fp, err := fingerprinter.Fingerprint(ctx, file.PathAbsolute)
+	if err != nil {
+		return err
+	}
+
+	return f.FingerprintRepo.NewFingerprint(ctx, file_id, fp)
+}
+
+func (f FingerprintService) GetFingerprint(ctx context.Context, file_id entities.FileID) (fp entities.Fingerprint, err error) {
+	res, err := f.FingerprintRepo.GetFingerprint(ctx, file_id)
+	if err != nil {
+		return entities.Fingerprint{}, err
+	}
+
+	return *res, nil
+}
+
+func (f FingerprintService) GetUnfingerprintedFiles(ctx context.Context) (file_ids []entities.FileID, err error) {
+	return f.FingerprintRepo.GetUnfingerprintedFiles(ctx)
+}
+
+// FindSimilar returns every other fingerprinted file scoring at least min_score against file_id, best match first.
+func (f FingerprintService) FindSimilar(ctx context.Context, file_id entities.FileID, min_score float64) (matches []entities.FingerprintMatch, err error) {
+	fp, err := f.FingerprintRepo.GetFingerprint(ctx, file_id)
+	if err != nil {
+		return nil, err
+	}
+
+	matches, err = f.FindSimilarFingerprint(ctx, *fp, min_score)
+	if err != nil {
+		return nil, err
+	}
+
+	return slices.DeleteFunc(matches, func(m entities.FingerprintMatch) bool {
+		return m.FileID == file_id
+	}), nil
+}
+
+// FindSimilarFingerprint returns every fingerprinted file scoring at least min_score against fp, best match first.
+// This allows checking a video for duplicates before it's ever stored. Only files whose duration could score min_score
+// are compared against fp, as told by fingerprint.DurationBounds.
+func (f FingerprintService) FindSimilarFingerprint(ctx context.Context, fp entities.Fingerprint, min_score float64) (matches []entities.FingerprintMatch, err error) {
+	if min_score < 0 || min_score > 1 {
+		return nil, errors.New("min_score must be between 0 and 1")
+	}
+
+	lower, upper := fingerprint.DurationBounds(fingerprint.Duration(fp), min_score)
+	fingerprints, err := f.FingerprintRepo.GetFingerprintsByDuration(ctx, lower, upper)
+	if err != nil {
+		return nil, err
+	}
+
+	for file_id, other := range fingerprints {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		score := fingerprint.Similarity(fp, other)
+		if score > 0 && score >= min_score {
+			matches = append(matches, entities.FingerprintMatch{FileID: file_id, Score: score})
+		}
+	}
+
+	slices.SortFunc(matches, func(a, b entities.FingerprintMatch) int {
+		return cmp.Or(cmp.Compare(b.Score, a.Score), cmp.Compare(a.FileID, b.FileID))
+	})
+
+	return matches, nil
+}
@@ -0,0 +1,403 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+
+	"github.com/dtbead/wc-maps-archive/internal/bundle"
+	"github.com/dtbead/wc-maps-archive/internal/entities"
+	file_helper "github.com/dtbead/wc-maps-archive/internal/helper/file"
+)
+
+// FsckSeverity is how badly an FsckFinding is wrong. An error loses or contradicts what was archived, a warning leaves
+// something incomplete, and info is merely worth knowing about.
+type FsckSeverity int
+
+const (
+	FsckInfo FsckSeverity = iota
+	FsckWarning
+	FsckError
+)
+
+func (f FsckSeverity) ToString() string {
+	switch f {
+	case FsckInfo:
+		return "info"
+	case FsckWarning:
+		return "warning"
+	case FsckError:
+		return "error"
+	default:
+		return "unknown"
+	}
+}
+
+// FsckCheck is a single consistency check Fsck runs, named after what it finds.
+type FsckCheck string
+
+const (
+	// FsckYoutubeWithoutFiles finds youtube videos without a single file downloaded of them. Repairing their unassigned
+	// downloads, if there are any, repairs them as well.
+	FsckYoutubeWithoutFiles FsckCheck = "youtube-without-files"
+	// FsckUnassignedDownload finds files recorded by yt-dlp metadata as a download of a single youtube video, which
+	// aren't assigned to it. They're repaired by assigning them to it.
+	FsckUnassignedDownload FsckCheck = "unassigned-download"
+	// FsckMisattributedDownload finds yt-dlp metadata, such as formats, recording a file as a download of another youtube
+	// video than the one it's assigned to. Which of them is wrong can't be told, so they aren't repaired.
+	FsckMisattributedDownload FsckCheck = "misattributed-download"
+	// FsckFileWithoutVideo finds files without their video metadata. Those which have been probed are repaired out of
+	// their probe.
+	FsckFileWithoutVideo FsckCheck = "file-without-video"
+	// FsckProjectWithoutFiles finds projects without a file of their own or of any of their parts.
+	FsckProjectWithoutFiles FsckCheck = "project-without-files"
+	// FsckMissingFile finds files whose stored contents are gone from the storage root.
+	FsckMissingFile FsckCheck = "missing-file"
+	// FsckSizeMismatch finds files whose stored contents aren't as big as when they were stored.
+	FsckSizeMismatch FsckCheck = "size-mismatch"
+	// FsckHashMismatch finds files whose stored contents don't match their hashes. It's only run by FsckOptions.Verify.
+	FsckHashMismatch FsckCheck = "hash-mismatch"
+	// FsckUntrackedFile finds files within the storage root which aren't stored, other than those still being added or
+	// deleted. They're repaired by storing them, which is refused for those that aren't where their contents would have
+	// been stored, or that have started being added or deleted since.
+	FsckUntrackedFile FsckCheck = "untracked-file"
+	// FsckMissingSidecar finds files without a sidecar. They're repaired by writing it.
+	FsckMissingSidecar FsckCheck = "missing-sidecar"
+	// FsckInvalidSidecar finds files whose sidecar is unreadable or describes another file. They're repaired by
+	// writing it again.
+	FsckInvalidSidecar FsckCheck = "invalid-sidecar"
+)
+
+// FsckFinding is something a FsckCheck found to be wrong. Subject is what it's about, such as "file 12", and Repair
+// describes what RepairFinding would do about it, being empty if it can't be repaired automatically. FileID, YoutubeID
+// and Path are whatever the finding is about, as far as they're known.
+type FsckFinding struct {
+	Check     FsckCheck
+	Severity  FsckSeverity
+	Subject   string
+	Detail    string
+	Repair    string
+	FileID    entities.FileID
+	YoutubeID entities.YoutubeVideoID
+	Path      string
+}
+
+func (f FsckFinding) IsRepairable() bool {
+	return f.Repair != ""
+}
+
+type FsckOptions struct {
+	// Verify rehashes the stored contents of every file, which reads the whole storage root.
+	Verify bool
+}
+
+// Fsck checks the archive for states its schema allows but which make no sense, across the database and the storage
+// root, returning everything found to be wrong by check. Nothing gets repaired; that's left to RepairFinding.
+func (s Service) Fsck(ctx context.Context, opts FsckOptions) (findings []FsckFinding, err error) {
+	for _, check := range []func(ctx context.Context) ([]FsckFinding, error){
+		s.fsckYoutube,
+		s.fsckDownloads,
+		s.fsckFileVideos,
+		s.fsckProjects,
+		func(ctx context.Context) ([]FsckFinding, error) { return s.fsckStorage(ctx, opts) },
+	} {
+		found, err := check(ctx)
+		findings = append(findings, found...)
+		if err != nil {
+			return findings, err
+		}
+	}
+
+	return findings, nil
+}
+
+func (s Service) fsckYoutube(ctx context.Context) (findings []FsckFinding, err error) {
+	youtube_ids, err := s.FsckService.GetYoutubeWithoutFiles(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, youtube_id := range youtube_ids {
+		findings = append(findings, FsckFinding{
+			Check:     FsckYoutubeWithoutFiles,
+			Severity:  FsckError,
+			Subject:   fmt.Sprintf("youtube %s", youtube_id),
+			Detail:    "no file is a download of it",
+			YoutubeID: youtube_id,
+		})
+	}
+
+	return findings, nil
+}
+
+func (s Service) fsckDownloads(ctx context.Context) (findings []FsckFinding, err error) {
+	unassigned, err := s.FsckService.GetUnassignedDownloads(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// downloads are ordered by file, so a file recorded as a download of several videos comes one after the other
+	for i := 0; i < len(unassigned); {
+		j := i + 1
+		for j < len(unassigned) && unassigned[j].FileID == unassigned[i].FileID {
+			j++
+		}
+
+		finding := FsckFinding{
+			Check:    FsckUnassignedDownload,
+			Severity: FsckError,
+			Subject:  fmt.Sprintf("file %d", unassigned[i].FileID),
+			FileID:   unassigned[i].FileID,
+		}
+
+		if j-i == 1 {
+			finding.Detail = fmt.Sprintf("yt-dlp downloaded it of youtube %s, but it isn't assigned to it", unassigned[i].YoutubeID)
+			finding.Repair = fmt.Sprintf("assign it to youtube %s", unassigned[i].YoutubeID)
+			finding.YoutubeID = unassigned[i].YoutubeID
+		} else {
+			finding.Detail = fmt.Sprintf("yt-dlp metadata records it as a download of %d youtube videos, but it isn't assigned to any", j-i)
+		}
+
+		findings = append(findings, finding)
+		i = j
+	}
+
+	misattributed, err := s.FsckService.GetMisattributedDownloads(ctx)
+	if err != nil {
+		return findings, err
+	}
+
+	for _, d := range misattributed {
+		findings = append(findings, FsckFinding{
+			Check:     FsckMisattributedDownload,
+			Severity:  FsckError,
+			Subject:   fmt.Sprintf("file %d", d.FileID),
+			Detail:    fmt.Sprintf("%s records it as a download of youtube %s, but it's assigned to youtube %s", d.Source, d.YoutubeID, d.AssignedYoutubeID),
+			FileID:    d.FileID,
+			YoutubeID: d.YoutubeID,
+		})
+	}
+
+	return findings, nil
+}
+
+func (s Service) fsckFileVideos(ctx context.Context) (findings []FsckFinding, err error) {
+	file_ids, err := s.FsckService.GetFilesWithoutVideo(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, file_id := range file_ids {
+		finding := FsckFinding{
+			Check:    FsckFileWithoutVideo,
+			Severity: FsckWarning,
+			Subject:  fmt.Sprintf("file %d", file_id),
+			Detail:   "it has no video metadata",
+			FileID:   file_id,
+		}
+
+		probe, err := s.ProbeService.GetProbe(ctx, file_id)
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			finding.Detail += ", and hasn't been probed"
+		case err != nil:
+			return findings, err
+		default:
+			if _, err := probe.Video(); err != nil {
+				finding.Detail += fmt.Sprintf(", and its probe can't make up for it, %v", err)
+			} else {
+				finding.Repair = "store the video metadata of its probe"
+			}
+		}
+
+		findings = append(findings, finding)
+	}
+
+	return findings, nil
+}
+
+func (s Service) fsckProjects(ctx context.Context) (findings []FsckFinding, err error) {
+	uuids, err := s.FsckService.GetProjectsWithoutFiles(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, uuid := range uuids {
+		findings = append(findings, FsckFinding{
+			Check:    FsckProjectWithoutFiles,
+			Severity: FsckWarning,
+			Subject:  fmt.Sprintf("project %s", uuid),
+			Detail:   "neither it nor any of its parts has a file",
+		})
+	}
+
+	return findings, nil
+}
+
+// fsckStorage checks the stored contents and sidecar of every file, then looks for files within the storage root which
+// aren't stored.
+func (s Service) fsckStorage(ctx context.Context, opts FsckOptions) (findings []FsckFinding, err error) {
+	stored := make(map[string]bool)
+
+	list_opts := entities.ListOptions{Limit: entities.MaxListLimit}
+	for {
+		file_ids, next_cursor, err := s.FileService.ListFiles(ctx, list_opts)
+		if err != nil {
+			return findings, err
+		}
+
+		for _, file_id := range file_ids {
+			if err := ctx.Err(); err != nil {
+				return findings, err
+			}
+
+			file, err := s.FileService.GetFile(ctx, file_id)
+			if err != nil {
+				return findings, err
+			}
+			stored[file.PathRelative] = true
+
+			found, err := s.fsckFile(ctx, file_id, file, opts)
+			findings = append(findings, found...)
+			if err != nil {
+				return findings, fmt.Errorf("file %d: %w", file_id, err)
+			}
+		}
+
+		if next_cursor == "" {
+			break
+		}
+		list_opts.Cursor = next_cursor
+	}
+
+	paths, err := s.FileService.GetStoredPaths(ctx)
+	if err != nil {
+		return findings, err
+	}
+
+	for _, path := range paths {
+		if stored[path] {
+			continue
+		}
+
+		findings = append(findings, FsckFinding{
+			Check:    FsckUntrackedFile,
+			Severity: FsckWarning,
+			Subject:  path,
+			Detail:   "it's within the storage root, but isn't stored",
+			Repair:   "store it",
+			Path:     path,
+		})
+	}
+
+	return findings, nil
+}
+
+func (s Service) fsckFile(ctx context.Context, file_id entities.FileID, file entities.File, opts FsckOptions) (findings []FsckFinding, err error) {
+	finding := func(check FsckCheck, severity FsckSeverity, detail, repair string) FsckFinding {
+		return FsckFinding{
+			Check:    check,
+			Severity: severity,
+			Subject:  fmt.Sprintf("file %d", file_id),
+			Detail:   detail,
+			Repair:   repair,
+			FileID:   file_id,
+			Path:     file.PathRelative,
+		}
+	}
+
+	info, err := os.Stat(file.PathAbsolute)
+	if errors.Is(err, fs.ErrNotExist) {
+		return []FsckFinding{finding(FsckMissingFile, FsckError, fmt.Sprintf("%s is gone from the storage root", file.PathRelative), "")}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if info.Size() != file.Size {
+		findings = append(findings, finding(FsckSizeMismatch, FsckError, fmt.Sprintf("it's %d bytes, instead of %d", info.Size(), file.Size), ""))
+	} else if opts.Verify {
+		f, err := os.Open(file.PathAbsolute)
+		if err != nil {
+			return findings, err
+		}
+
+		hashes, _, err := file_helper.GetHash(f)
+		f.Close()
+		if err != nil {
+			return findings, err
+		}
+
+		if !bytes.Equal(hashes.SHA256, file.Hashes.SHA256) {
+			findings = append(findings, finding(FsckHashMismatch, FsckError, fmt.Sprintf("its sha256 is %s, instead of %s",
+				file_helper.ByteToHexString(hashes.SHA256), file_helper.ByteToHexString(file.Hashes.SHA256)), ""))
+		}
+	}
+
+	contents, err := s.FileService.ReadSidecar(ctx, file.PathRelative)
+	if errors.Is(err, fs.ErrNotExist) {
+		return append(findings, finding(FsckMissingSidecar, FsckInfo, "it has no sidecar", "write its sidecar")), nil
+	}
+	if err != nil {
+		return findings, err
+	}
+
+	sidecar, err := bundle.ParseSidecar(contents)
+	if err != nil {
+		return append(findings, finding(FsckInvalidSidecar, FsckWarning, err.Error(), "write its sidecar again")), nil
+	}
+
+	if !sidecarDescribes(sidecar, file) {
+		return append(findings, finding(FsckInvalidSidecar, FsckWarning, "its sidecar describes another file", "write its sidecar again")), nil
+	}
+
+	return findings, nil
+}
+
+// RepairFinding repairs what finding found to be wrong, if it can be repaired automatically. Repairs only ever add what
+// is missing, and never delete anything.
+func (s Service) RepairFinding(ctx context.Context, finding FsckFinding) error {
+	if !finding.IsRepairable() {
+		return fmt.Errorf("%s can't be repaired automatically", finding.Check)
+	}
+
+	switch finding.Check {
+	case FsckUnassignedDownload:
+		// the sidecars of the files of the youtube video describe the file it didn't have until now
+		batch_ctx, batch := s.batchSidecars(ctx)
+		err := s.YoutubeService.AssignFile(batch_ctx, finding.YoutubeID, finding.FileID)
+		if err != nil {
+			return err
+		}
+
+		return batch.write(ctx, finding.FileID)
+	case FsckFileWithoutVideo:
+		probe, err := s.ProbeService.GetProbe(ctx, finding.FileID)
+		if err != nil {
+			return err
+		}
+
+		video, err := probe.Video()
+		if err != nil {
+			return err
+		}
+
+		err = s.FileService.NewFileVideo(ctx, finding.FileID, &video)
+		if err != nil {
+			return err
+		}
+
+		return s.WriteSidecar(ctx, finding.FileID)
+	case FsckUntrackedFile:
+		_, err := s.FileService.AdoptFile(ctx, finding.Path)
+		return err
+	case FsckMissingSidecar, FsckInvalidSidecar:
+		return s.WriteSidecar(ctx, finding.FileID)
+	default:
+		return fmt.Errorf("%s can't be repaired automatically", finding.Check)
+	}
+}
@@ -0,0 +1,43 @@
+package fsck
+
+import (
+	"context"
+
+	"github.com/dtbead/wc-maps-archive/internal/entities"
+	"github.com/dtbead/wc-maps-archive/internal/storage"
+)
+
+type FsckService struct {
+	FsckRepo storage.FsckRepository
+}
+
+func NewService(FsckRepo storage.FsckRepository) *FsckService {
+	return &FsckService{FsckRepo: FsckRepo}
+}
+
+// GetYoutubeWithoutFiles returns every youtube video without a single file downloaded of it.
+func (f FsckService) GetYoutubeWithoutFiles(ctx context.Context) (youtube_ids []entities.YoutubeVideoID, err error) {
+	return f.FsckRepo.GetYoutubeWithoutFiles(ctx)
+}
+
+// GetFilesWithoutVideo returns every file without its video metadata.
+func (f FsckService) GetFilesWithoutVideo(ctx context.Context) (file_ids []entities.FileID, err error) {
+	return f.FsckRepo.GetFilesWithoutVideo(ctx)
+}
+
+// GetUnassignedDownloads returns every file recorded by yt-dlp metadata as a download of a youtube video, which isn't
+// assigned to any youtube video.
+func (f FsckService) GetUnassignedDownloads(ctx context.Context) (downloads []entities.YoutubeDownload, err error) {
+	return f.FsckRepo.GetUnassignedDownloads(ctx)
+}
+
+// GetMisattributedDownloads returns every piece of yt-dlp metadata recording a file as a download of another youtube
+// video than the one it's assigned to.
+func (f FsckService) GetMisattributedDownloads(ctx context.Context) (downloads []entities.YoutubeDownload, err error) {
+	return f.FsckRepo.GetMisattributedDownloads(ctx)
+}
+
+// GetProjectsWithoutFiles returns every project without a file of its own or of any of its parts.
+func (f FsckService) GetProjectsWithoutFiles(ctx context.Context) (uuids []entities.ProjectUUID, err error) {
+	return f.FsckRepo.GetProjectsWithoutFiles(ctx)
+}
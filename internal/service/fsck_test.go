@@ -0,0 +1,296 @@
+package service_test
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/dtbead/wc-maps-archive/internal/bundle"
+	"github.com/dtbead/wc-maps-archive/internal/entities"
+	mock_storage "github.com/dtbead/wc-maps-archive/internal/helper/testing/mock/storage"
+	mock_youtube "github.com/dtbead/wc-maps-archive/internal/helper/testing/mock/youtube"
+	"github.com/dtbead/wc-maps-archive/internal/service"
+	"github.com/dtbead/wc-maps-archive/internal/storage"
+	"github.com/google/go-cmp/cmp"
+	"go.uber.org/mock/gomock"
+)
+
+type fsckMocks struct {
+	files    *mock_storage.MockFileRepository
+	youtube  *mock_storage.MockYoutubeRepository
+	probes   *mock_storage.MockProbeRepository
+	projects *mock_storage.MockProjectRepository
+	fsck     *mock_storage.MockFsckRepository
+}
+
+// newFsckService returns a Service backed by strict mocks, so that a repair calling anything it isn't expected to, such
+// as deleting or unassigning something, fails the test.
+func newFsckService(t *testing.T) (*service.Service, fsckMocks) {
+	ctrl := gomock.NewController(t)
+	m := fsckMocks{
+		files:    mock_storage.NewMockFileRepository(ctrl),
+		youtube:  mock_storage.NewMockYoutubeRepository(ctrl),
+		probes:   mock_storage.NewMockProbeRepository(ctrl),
+		projects: mock_storage.NewMockProjectRepository(ctrl),
+		fsck:     mock_storage.NewMockFsckRepository(ctrl),
+	}
+
+	audit := mock_storage.NewMockAuditRepository(ctrl)
+	audit.EXPECT().NewAuditEntry(gomock.Any(), gomock.Any()).Return(int64(1), nil).AnyTimes()
+
+	return service.NewService(&storage.Repository{File: m.files, Youtube: m.youtube, Probe: m.probes, Project: m.projects, Audit: audit, Fsck: m.fsck}), m
+}
+
+// newFsckProbe returns the probe of a file with a video and an audio stream.
+func newFsckProbe() *entities.MediaProbe {
+	return &entities.MediaProbe{
+		Container: "mov,mp4,m4a,3gp,3g2,mj2",
+		Duration:  212.4,
+		Streams: []entities.MediaStream{
+			{Index: 0, CodecType: "video", Codec: "h264", Width: 1920, Height: 1080, Fps: 29.97},
+			{Index: 1, CodecType: "audio", Codec: "aac"},
+		},
+	}
+}
+
+func newFsckSidecar(t *testing.T, file entities.File) []byte {
+	t.Helper()
+
+	contents, err := json.Marshal(bundle.Sidecar{Version: bundle.SidecarVersion, Written: time.Now(), File: bundle.NewFile(file, nil)})
+	if err != nil {
+		t.Fatalf("failed to marshal sidecar, %v", err)
+	}
+	return contents
+}
+
+func TestService_Fsck(t *testing.T) {
+	s, m := newFsckService(t)
+	ctx := context.Background()
+	project_uuid := entities.ProjectUUID("0190f5d2-4b6c-7a3e-9d1f-2c8b5e7a4f60")
+
+	m.fsck.EXPECT().GetYoutubeWithoutFiles(gomock.Any()).Return([]entities.YoutubeVideoID{"dQw4w9WgXcQ"}, nil)
+	m.fsck.EXPECT().GetUnassignedDownloads(gomock.Any()).Return([]entities.YoutubeDownload{
+		{FileID: 1, YoutubeID: "9bZkp7q19f0", Source: "youtube_format"},
+		// recorded as a download of two videos, so which to assign it to can't be told
+		{FileID: 2, YoutubeID: "9bZkp7q19f0", Source: "youtube_format"},
+		{FileID: 2, YoutubeID: "kJQP7kiw5Fk", Source: "youtube_ytdlp_info"},
+	}, nil)
+	m.fsck.EXPECT().GetMisattributedDownloads(gomock.Any()).Return([]entities.YoutubeDownload{
+		{FileID: 3, YoutubeID: "9bZkp7q19f0", Source: "youtube_format", AssignedYoutubeID: "kJQP7kiw5Fk"},
+	}, nil)
+	m.fsck.EXPECT().GetFilesWithoutVideo(gomock.Any()).Return([]entities.FileID{4, 5, 6}, nil)
+	m.probes.EXPECT().GetProbe(gomock.Any(), entities.FileID(4)).Return(newFsckProbe(), nil)
+	m.probes.EXPECT().GetProbe(gomock.Any(), entities.FileID(5)).Return(nil, sql.ErrNoRows)
+	m.probes.EXPECT().GetProbe(gomock.Any(), entities.FileID(6)).Return(&entities.MediaProbe{Duration: 212, Streams: []entities.MediaStream{{CodecType: "audio", Codec: "opus"}}}, nil)
+	m.fsck.EXPECT().GetProjectsWithoutFiles(gomock.Any()).Return([]entities.ProjectUUID{project_uuid}, nil)
+
+	root := t.TempDir()
+	stored := []struct {
+		path    string
+		file_id entities.FileID
+		// contents are what's in the storage root, if anything
+		contents []byte
+		size     int64
+		sidecar  func(file entities.File) []byte
+	}{
+		{path: "07/valid.mp4", file_id: 7, contents: []byte("valid file"), size: 10, sidecar: func(file entities.File) []byte { return newFsckSidecar(t, file) }},
+		{path: "08/missing.mp4", file_id: 8, size: 10},
+		{path: "09/truncated.mp4", file_id: 9, contents: []byte("truncated"), size: 10, sidecar: func(file entities.File) []byte { return newFsckSidecar(t, file) }},
+		{path: "10/without-sidecar.mp4", file_id: 10, contents: []byte("no sidecar"), size: 10},
+		{path: "11/invalid-sidecar.mp4", file_id: 11, contents: []byte("bad sidecar"), size: 11, sidecar: func(entities.File) []byte { return []byte("{") }},
+		{path: "12/replaced.mp4", file_id: 12, contents: []byte("replaced"), size: 8, sidecar: func(file entities.File) []byte {
+			file.Hashes = newTestHashes(0xff)
+			return newFsckSidecar(t, file)
+		}},
+	}
+
+	var file_ids []entities.FileID
+	paths := []string{"ff/untracked.mp4"}
+	for _, f := range stored {
+		file := entities.File{PathRelative: f.path, PathAbsolute: filepath.Join(root, filepath.FromSlash(f.path)), Extension: "mp4", Size: f.size, Hashes: newTestHashes(byte(f.file_id))}
+		file_ids = append(file_ids, f.file_id)
+		m.files.EXPECT().GetFile(gomock.Any(), f.file_id).Return(&file, nil)
+
+		if f.contents == nil {
+			continue
+		}
+
+		if err := os.MkdirAll(filepath.Dir(file.PathAbsolute), 0775); err != nil {
+			t.Fatalf("failed to create test directory, %v", err)
+		}
+		if err := os.WriteFile(file.PathAbsolute, f.contents, 0664); err != nil {
+			t.Fatalf("failed to write test file, %v", err)
+		}
+		paths = append(paths, f.path)
+
+		if f.sidecar == nil {
+			m.files.EXPECT().ReadSidecar(gomock.Any(), f.path).Return(nil, fs.ErrNotExist)
+		} else {
+			m.files.EXPECT().ReadSidecar(gomock.Any(), f.path).Return(f.sidecar(file), nil)
+		}
+	}
+	m.files.EXPECT().ListFiles(gomock.Any(), gomock.Any()).Return(file_ids, "", nil)
+	m.files.EXPECT().GetStoredPaths(gomock.Any()).Return(paths, nil)
+
+	findings, err := s.Fsck(ctx, service.FsckOptions{})
+	if err != nil {
+		t.Fatalf("Service.Fsck() error = %v", err)
+	}
+
+	type finding struct {
+		Check      service.FsckCheck
+		FileID     entities.FileID
+		YoutubeID  entities.YoutubeVideoID
+		Path       string
+		Repairable bool
+	}
+	got := make([]finding, len(findings))
+	for i, f := range findings {
+		got[i] = finding{f.Check, f.FileID, f.YoutubeID, f.Path, f.IsRepairable()}
+	}
+
+	want := []finding{
+		{Check: service.FsckYoutubeWithoutFiles, YoutubeID: "dQw4w9WgXcQ"},
+		{Check: service.FsckUnassignedDownload, FileID: 1, YoutubeID: "9bZkp7q19f0", Repairable: true},
+		{Check: service.FsckUnassignedDownload, FileID: 2},
+		{Check: service.FsckMisattributedDownload, FileID: 3, YoutubeID: "9bZkp7q19f0"},
+		{Check: service.FsckFileWithoutVideo, FileID: 4, Repairable: true},
+		{Check: service.FsckFileWithoutVideo, FileID: 5},
+		{Check: service.FsckFileWithoutVideo, FileID: 6},
+		{Check: service.FsckProjectWithoutFiles},
+		{Check: service.FsckMissingFile, FileID: 8, Path: "08/missing.mp4"},
+		{Check: service.FsckSizeMismatch, FileID: 9, Path: "09/truncated.mp4"},
+		{Check: service.FsckMissingSidecar, FileID: 10, Path: "10/without-sidecar.mp4", Repairable: true},
+		{Check: service.FsckInvalidSidecar, FileID: 11, Path: "11/invalid-sidecar.mp4", Repairable: true},
+		{Check: service.FsckInvalidSidecar, FileID: 12, Path: "12/replaced.mp4", Repairable: true},
+		{Check: service.FsckUntrackedFile, Path: "ff/untracked.mp4", Repairable: true},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Service.Fsck() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestService_RepairFinding(t *testing.T) {
+	yt := mock_youtube.NewYoutube()
+	youtube_id := yt.YouTube.YoutubeID
+
+	tests := []struct {
+		name    string
+		finding service.FsckFinding
+		// expect sets up what the repair is expected to add, anything else failing the test
+		expect func(m fsckMocks)
+		// wantSidecars are the files whose sidecars the repair is expected to write
+		wantSidecars []entities.FileID
+		wantErr      bool
+		// wantErrIs is what the error is expected to wrap, if anything in particular
+		wantErrIs error
+	}{
+		{
+			name:    "unassigned download",
+			finding: service.FsckFinding{Check: service.FsckUnassignedDownload, FileID: 1, YoutubeID: youtube_id, Repair: "assign"},
+			expect: func(m fsckMocks) {
+				// the video already has file 2, whose sidecar describes the file it's gaining as well
+				assigned := false
+				m.youtube.EXPECT().GetYoutubeFileIDs(gomock.Any(), youtube_id).DoAndReturn(func(context.Context, entities.YoutubeVideoID) ([]entities.FileID, error) {
+					if assigned {
+						return []entities.FileID{1, 2}, nil
+					}
+					return []entities.FileID{2}, nil
+				}).AnyTimes()
+				m.youtube.EXPECT().AssignYoutubeFile(gomock.Any(), youtube_id, entities.FileID(1)).DoAndReturn(func(context.Context, entities.YoutubeVideoID, entities.FileID) error {
+					assigned = true
+					return nil
+				})
+				m.youtube.EXPECT().GetFileYoutubeID(gomock.Any(), gomock.Any()).Return(youtube_id, nil).AnyTimes()
+				m.youtube.EXPECT().GetYoutube(gomock.Any(), youtube_id).Return(&yt, nil).AnyTimes()
+				m.youtube.EXPECT().GetYtdlpInfo(gomock.Any(), gomock.Any()).Return(nil, sql.ErrNoRows).AnyTimes()
+				m.youtube.EXPECT().GetThumbnail(gomock.Any(), gomock.Any()).Return(nil, sql.ErrNoRows).AnyTimes()
+			},
+			wantSidecars: []entities.FileID{1, 2},
+		},
+		{
+			name:    "file without video",
+			finding: service.FsckFinding{Check: service.FsckFileWithoutVideo, FileID: 4, Repair: "store"},
+			expect: func(m fsckMocks) {
+				m.probes.EXPECT().GetProbe(gomock.Any(), entities.FileID(4)).Return(newFsckProbe(), nil)
+				want := entities.Video{VideoCodec: "h264", AudioCodec: "aac", Duration: 212, Width: 1920, Height: 1080, Fps: 30}
+				m.files.EXPECT().NewFileVideo(gomock.Any(), entities.FileID(4), &want).Return(nil)
+				m.youtube.EXPECT().GetFileYoutubeID(gomock.Any(), entities.FileID(4)).Return(entities.YoutubeVideoID(""), sql.ErrNoRows).AnyTimes()
+			},
+			wantSidecars: []entities.FileID{4},
+		},
+		{
+			name:    "untracked file",
+			finding: service.FsckFinding{Check: service.FsckUntrackedFile, Path: "ff/untracked.mp4", Repair: "store"},
+			expect: func(m fsckMocks) {
+				m.files.EXPECT().AdoptFile(gomock.Any(), "ff/untracked.mp4").Return(entities.FileID(13), nil)
+			},
+		},
+		{
+			// an ingest started moving it into place since fsck ran
+			name:    "untracked file being added",
+			finding: service.FsckFinding{Check: service.FsckUntrackedFile, Path: "ff/pending.mp4", Repair: "store"},
+			expect: func(m fsckMocks) {
+				m.files.EXPECT().AdoptFile(gomock.Any(), "ff/pending.mp4").Return(entities.InvalidFileID, entities.ErrorFilePending)
+			},
+			wantErr:   true,
+			wantErrIs: entities.ErrorFilePending,
+		},
+		{
+			name:    "missing sidecar",
+			finding: service.FsckFinding{Check: service.FsckMissingSidecar, FileID: 10, Repair: "write"},
+			expect: func(m fsckMocks) {
+				m.youtube.EXPECT().GetFileYoutubeID(gomock.Any(), entities.FileID(10)).Return(entities.YoutubeVideoID(""), sql.ErrNoRows).AnyTimes()
+			},
+			wantSidecars: []entities.FileID{10},
+		},
+		{
+			name:    "invalid sidecar",
+			finding: service.FsckFinding{Check: service.FsckInvalidSidecar, FileID: 11, Repair: "write"},
+			expect: func(m fsckMocks) {
+				m.youtube.EXPECT().GetFileYoutubeID(gomock.Any(), entities.FileID(11)).Return(entities.YoutubeVideoID(""), sql.ErrNoRows).AnyTimes()
+			},
+			wantSidecars: []entities.FileID{11},
+		},
+		{
+			name:    "unrepairable",
+			finding: service.FsckFinding{Check: service.FsckMissingFile, FileID: 8},
+			expect:  func(fsckMocks) {},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, m := newFsckService(t)
+			tt.expect(m)
+
+			// writing sidecars only reads what they describe
+			m.files.EXPECT().GetFile(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, file_id entities.FileID) (*entities.File, error) {
+				return &entities.File{PathRelative: "stored.mp4", Extension: "mp4", Size: 10, Hashes: newTestHashes(byte(file_id))}, nil
+			}).AnyTimes()
+			m.files.EXPECT().GetFileVideo(gomock.Any(), gomock.Any()).Return(nil, sql.ErrNoRows).AnyTimes()
+			m.projects.EXPECT().GetFileProjects(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+
+			var sidecars []entities.FileID
+			m.files.EXPECT().WriteSidecar(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, file_id entities.FileID, sidecar []byte) error {
+				sidecars = append(sidecars, file_id)
+				return nil
+			}).AnyTimes()
+
+			err := s.RepairFinding(context.Background(), tt.finding)
+			if (err != nil) != tt.wantErr || (tt.wantErrIs != nil && !errors.Is(err, tt.wantErrIs)) {
+				t.Fatalf("Service.RepairFinding() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if diff := cmp.Diff(tt.wantSidecars, sidecars); diff != "" {
+				t.Errorf("Service.RepairFinding() sidecars written mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,144 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/dtbead/wc-maps-archive/internal/entities"
+)
+
+// importExtensions are the file extensions ImportDirectory considers to be videos.
+var importExtensions = []string{"3gp", "avi", "flv", "m4v", "mkv", "mov", "mp4", "mpeg", "mpg", "ogv", "webm", "wmv"}
+
+type ImportOptions struct {
+	// Project assigns every imported file to an existing project, unless it's entities.InvalidProjectUUID.
+	Project entities.ProjectUUID
+	// ParseYoutubeID extracts a youtube video id from a filename, such as yt-dlp's "%(title)s [%(id)s].%(ext)s".
+	// Imported files get linked to the parsed youtube video if it's already archived. nil disables linking.
+	ParseYoutubeID func(filename string) (youtube_id entities.YoutubeVideoID, ok bool)
+}
+
+type ImportResult struct {
+	Path      string
+	FileID    entities.FileID
+	YoutubeID entities.YoutubeVideoID
+	Err       error
+}
+
+// ImportDirectory walks directory and imports every video file found through ImportFile. A file failing to import
+// doesn't stop the walk; its error is reported in its ImportResult instead.
+func (s Service) ImportDirectory(ctx context.Context, directory string, prober entities.VideoProber, opts ImportOptions) (results []ImportResult, err error) {
+	err = filepath.WalkDir(directory, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		if d.IsDir() || !slices.Contains(importExtensions, extension(path)) {
+			return nil
+		}
+
+		results = append(results, s.ImportFile(ctx, path, prober, opts))
+		return nil
+	})
+
+	return results, err
+}
+
+// ImportFile stores the local video file at path, along with its probed media metadata. Nothing is kept if any step
+// fails, other than writing its sidecar.
+func (s Service) ImportFile(ctx context.Context, path string, prober entities.VideoProber, opts ImportOptions) (result ImportResult) {
+	result = ImportResult{Path: path, FileID: entities.InvalidFileID}
+
+	// probe before storing anything, so that files ffprobe can't make sense of never make it into the archive
+	probe, err := prober.Probe(ctx, path)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+
+	_, err = probe.Video()
+	if err != nil {
+		result.Err = err
+		return result
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+	defer f.Close()
+
+	file_id, err := s.FileService.NewFile(ctx, f, extension(path))
+	if err != nil {
+		result.Err = err
+		return result
+	}
+
+	batch_ctx, batch := s.batchSidecars(ctx)
+	err = s.assignImportedFile(batch_ctx, file_id, probe, path, opts, &result)
+	if err != nil {
+		result.Err = errors.Join(err, s.FileService.DeleteFile(ctx, file_id))
+		return result
+	}
+
+	result.FileID = file_id
+	result.Err = batch.write(ctx, file_id)
+	return result
+}
+
+func (s Service) assignImportedFile(ctx context.Context, file_id entities.FileID, probe *entities.MediaProbe, path string, opts ImportOptions, result *ImportResult) error {
+	// there's no source claiming anything about a local file, so there's nothing to verify the probe against
+	_, err := s.ProbeService.StoreProbe(ctx, file_id, probe, nil)
+	if err != nil {
+		return err
+	}
+
+	if opts.Project != entities.InvalidProjectUUID {
+		err = s.ProjectService.AssignFile(ctx, opts.Project, file_id)
+		if err != nil {
+			return err
+		}
+	}
+
+	if opts.ParseYoutubeID == nil {
+		return nil
+	}
+
+	youtube_id, ok := opts.ParseYoutubeID(filepath.Base(path))
+	if !ok || !youtube_id.IsValid() {
+		return nil
+	}
+
+	// only link to youtube videos we've already archived; an unknown id just means the upload is gone
+	_, err = s.YoutubeService.GetYoutubeVideo(ctx, youtube_id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	err = s.YoutubeService.AssignFile(ctx, youtube_id, file_id)
+	if err != nil {
+		return err
+	}
+
+	result.YoutubeID = youtube_id
+	return nil
+}
+
+// extension returns the lowercase extension of path without its leading period.
+func extension(path string) string {
+	return strings.ToLower(strings.TrimPrefix(filepath.Ext(path), "."))
+}
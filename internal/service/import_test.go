@@ -0,0 +1,176 @@
+package service_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/dtbead/wc-maps-archive/internal/entities"
+	mock_storage "github.com/dtbead/wc-maps-archive/internal/helper/testing/mock/storage"
+	"github.com/dtbead/wc-maps-archive/internal/service"
+	"github.com/dtbead/wc-maps-archive/internal/storage"
+	"go.uber.org/mock/gomock"
+)
+
+// proberFunc probes every video as whatever it returns.
+type proberFunc func(ctx context.Context, path string) (*entities.MediaProbe, error)
+
+func (p proberFunc) Probe(ctx context.Context, path string) (*entities.MediaProbe, error) {
+	return p(ctx, path)
+}
+
+var regexpImportYoutubeID = regexp.MustCompile(`\[([\w-]{11})\]\.\w+$`)
+
+func parseYoutubeID(filename string) (youtube_id entities.YoutubeVideoID, ok bool) {
+	m := regexpImportYoutubeID.FindStringSubmatch(filename)
+	if m == nil {
+		return "", false
+	}
+
+	return entities.YoutubeVideoID(m[1]), true
+}
+
+func TestService_ImportFile(t *testing.T) {
+	const file_id entities.FileID = 7
+	const youtube_id entities.YoutubeVideoID = "dQw4w9WgXcQ"
+	errLookup := errors.New("connection reset by peer")
+
+	probe := &entities.MediaProbe{
+		Container: "mov,mp4,m4a,3gp,3g2,mj2",
+		Duration:  212,
+		Streams: []entities.MediaStream{
+			{Index: 0, CodecType: "video", Codec: "h264", Width: 1920, Height: 1080, Fps: 25},
+			{Index: 1, CodecType: "audio", Codec: "aac"},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		filename string
+		// lookup is what looking up the parsed youtube video returns, if it's looked up at all
+		lookup        error
+		wantLookup    bool
+		wantYoutubeID entities.YoutubeVideoID
+		wantErr       error
+	}{
+		{name: "archived", filename: "Firestar MAP [dQw4w9WgXcQ].mp4", wantLookup: true, wantYoutubeID: youtube_id},
+		{name: "not archived", filename: "Firestar MAP [dQw4w9WgXcQ].mp4", lookup: sql.ErrNoRows, wantLookup: true},
+		{name: "lookup error", filename: "Firestar MAP [dQw4w9WgXcQ].mp4", lookup: errLookup, wantLookup: true, wantErr: errLookup},
+		{name: "no youtube id", filename: "Firestar MAP.mp4"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), tt.filename)
+			if err := os.WriteFile(path, []byte("not actually a video file"), 0664); err != nil {
+				t.Fatalf("failed to write test file, %v", err)
+			}
+
+			ctrl := gomock.NewController(t)
+			files := mock_storage.NewMockFileRepository(ctrl)
+			youtube := mock_storage.NewMockYoutubeRepository(ctrl)
+			probes := mock_storage.NewMockProbeRepository(ctrl)
+			projects := mock_storage.NewMockProjectRepository(ctrl)
+			audit := mock_storage.NewMockAuditRepository(ctrl)
+
+			stored := &entities.File{
+				PathRelative: "ab/cd.mp4",
+				Extension:    "mp4",
+				Size:         25,
+				Hashes:       entities.Hashes{MD5: make([]byte, 16), SHA1: make([]byte, 20), SHA256: make([]byte, 32)},
+			}
+			files.EXPECT().NewFile(gomock.Any(), gomock.Any(), "mp4").Return(file_id, nil)
+			files.EXPECT().GetFile(gomock.Any(), file_id).Return(stored, nil).AnyTimes()
+			files.EXPECT().GetFileVideo(gomock.Any(), file_id).Return(nil, sql.ErrNoRows).AnyTimes()
+			audit.EXPECT().NewAuditEntry(gomock.Any(), gomock.Any()).Return(int64(1), nil).AnyTimes()
+			probes.EXPECT().GetProbeMismatches(gomock.Any(), file_id).Return(nil, nil)
+			probes.EXPECT().NewProbe(gomock.Any(), file_id, probe, gomock.Any()).Return(nil)
+			projects.EXPECT().GetFileProjects(gomock.Any(), file_id).Return(nil, nil).AnyTimes()
+
+			if tt.wantLookup {
+				var video *entities.YoutubeVideo
+				if tt.lookup == nil {
+					video = &entities.YoutubeVideo{YoutubeID: youtube_id}
+				}
+				youtube.EXPECT().GetYoutubeVideo(gomock.Any(), youtube_id).Return(video, tt.lookup)
+			}
+
+			if tt.wantYoutubeID != "" {
+				youtube.EXPECT().AssignYoutubeFile(gomock.Any(), youtube_id, file_id).Return(nil)
+				youtube.EXPECT().GetYoutubeFileIDs(gomock.Any(), youtube_id).Return([]entities.FileID{file_id}, nil).AnyTimes()
+				youtube.EXPECT().GetFileYoutubeID(gomock.Any(), file_id).Return(youtube_id, nil).AnyTimes()
+				youtube.EXPECT().GetYoutube(gomock.Any(), youtube_id).Return(&entities.Youtube{YouTube: entities.YoutubeVideo{YoutubeID: youtube_id}}, nil).AnyTimes()
+				youtube.EXPECT().GetYtdlpInfo(gomock.Any(), file_id).Return(nil, sql.ErrNoRows).AnyTimes()
+				youtube.EXPECT().GetThumbnail(gomock.Any(), file_id).Return(nil, sql.ErrNoRows).AnyTimes()
+			} else {
+				youtube.EXPECT().GetFileYoutubeID(gomock.Any(), file_id).Return(entities.YoutubeVideoID(""), sql.ErrNoRows).AnyTimes()
+			}
+
+			// a file which fails to import is removed again, and only one which imports gets a sidecar
+			if tt.wantErr != nil {
+				files.EXPECT().DeleteFile(gomock.Any(), file_id).Return(nil)
+			} else {
+				files.EXPECT().WriteSidecar(gomock.Any(), file_id, gomock.Any()).Return(nil)
+			}
+
+			s := service.NewService(&storage.Repository{File: files, Youtube: youtube, Probe: probes, Project: projects, Audit: audit})
+			prober := proberFunc(func(ctx context.Context, path string) (*entities.MediaProbe, error) { return probe, nil })
+
+			result := s.ImportFile(context.Background(), path, prober, service.ImportOptions{ParseYoutubeID: parseYoutubeID})
+			if !errors.Is(result.Err, tt.wantErr) {
+				t.Fatalf("Service.ImportFile() error = %v, want %v", result.Err, tt.wantErr)
+			}
+
+			wantFileID := file_id
+			if tt.wantErr != nil {
+				wantFileID = entities.InvalidFileID
+			}
+			if result.FileID != wantFileID {
+				t.Errorf("Service.ImportFile() FileID = %d, want %d", result.FileID, wantFileID)
+			}
+
+			if result.YoutubeID != tt.wantYoutubeID {
+				t.Errorf("Service.ImportFile() YoutubeID = %q, want %q", result.YoutubeID, tt.wantYoutubeID)
+			}
+		})
+	}
+}
+
+func TestService_ImportFile_Unprobeable(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	// nothing gets stored of a file which ffprobe can't make sense of, so no repository is expected to be called
+	s := service.NewService(&storage.Repository{
+		File:    mock_storage.NewMockFileRepository(ctrl),
+		Youtube: mock_storage.NewMockYoutubeRepository(ctrl),
+		Probe:   mock_storage.NewMockProbeRepository(ctrl),
+		Project: mock_storage.NewMockProjectRepository(ctrl),
+		Audit:   mock_storage.NewMockAuditRepository(ctrl),
+	})
+
+	tests := []struct {
+		name  string
+		probe *entities.MediaProbe
+		err   error
+	}{
+		{"probe fails", nil, errors.New("Invalid data found when processing input")},
+		{"no video stream", &entities.MediaProbe{Duration: 212, Streams: []entities.MediaStream{{CodecType: "audio", Codec: "aac"}}}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prober := proberFunc(func(ctx context.Context, path string) (*entities.MediaProbe, error) { return tt.probe, tt.err })
+
+			result := s.ImportFile(context.Background(), "Firestar MAP [dQw4w9WgXcQ].mp4", prober, service.ImportOptions{ParseYoutubeID: parseYoutubeID})
+			if result.Err == nil {
+				t.Errorf("Service.ImportFile() error = nil, want an error")
+			}
+
+			if result.FileID != entities.InvalidFileID {
+				t.Errorf("Service.ImportFile() FileID = %d, want %d", result.FileID, entities.InvalidFileID)
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package music
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/dtbead/wc-maps-archive/internal/entities"
+	"github.com/dtbead/wc-maps-archive/internal/storage"
+)
+
+// MinMatchScore is the score below which FindMusic considers a song unrelated to what was looked up.
+const MinMatchScore = 0.3
+
+// MaxMatches is the most songs FindMusic returns.
+const MaxMatches = 25
+
+type MusicService struct {
+	MusicRepo storage.MusicRepository
+}
+
+func NewService(MusicRepo storage.MusicRepository) *MusicService {
+	return &MusicService{MusicRepo: MusicRepo}
+}
+
+// IsValidMusic trims the artist and title of music, and checks that neither is left empty.
+func IsValidMusic(music *entities.Music) error {
+	if music == nil {
+		return entities.ErrorInvalidMusicPtr
+	}
+
+	music.Artist = strings.TrimSpace(music.Artist)
+	music.Title = strings.TrimSpace(music.Title)
+
+	switch {
+	case music.Artist == "":
+		return errors.New("empty music artist")
+	case music.Title == "":
+		return errors.New("empty music title")
+	}
+
+	return nil
+}
+
+func (m MusicService) NewMusic(ctx context.Context, music *entities.Music) (music_id int32, err error) {
+	if err := IsValidMusic(music); err != nil {
+		return 0, err
+	}
+
+	return m.MusicRepo.NewMusic(ctx, music)
+}
+
+func (m MusicService) UpdateMusic(ctx context.Context, music *entities.Music) (err error) {
+	if err := IsValidMusic(music); err != nil {
+		return err
+	}
+
+	return m.MusicRepo.UpdateMusic(ctx, music)
+}
+
+func (m MusicService) DeleteMusic(ctx context.Context, music_id int32) (err error) {
+	return m.MusicRepo.DeleteMusic(ctx, music_id)
+}
+
+func (m MusicService) GetMusic(ctx context.Context, music_id int32) (music entities.Music, err error) {
+	res, err := m.MusicRepo.GetMusic(ctx, music_id)
+	if err != nil {
+		return entities.Music{}, err
+	}
+
+	return *res, nil
+}
+
+// FindMusic looks songs up by a rough artist and title, such as a misspelt or partial one, best match first. Either
+// may be left empty, but not both.
+func (m MusicService) FindMusic(ctx context.Context, artist, title string) (matches []entities.MusicMatch, err error) {
+	artist, title = strings.TrimSpace(artist), strings.TrimSpace(title)
+	if artist == "" && title == "" {
+		return nil, errors.New("expected an artist or title to look up")
+	}
+
+	return m.MusicRepo.FindMusic(ctx, artist, title, MinMatchScore, MaxMatches)
+}
+
+func (m MusicService) AssignProject(ctx context.Context, project_uuid entities.ProjectUUID, music_id int32) (err error) {
+	return m.MusicRepo.AssignProjectMusic(ctx, project_uuid, music_id)
+}
+
+func (m MusicService) UnassignProject(ctx context.Context, project_uuid entities.ProjectUUID, music_id int32) (err error) {
+	return m.MusicRepo.UnassignProjectMusic(ctx, project_uuid, music_id)
+}
+
+// GetProjectMusic returns every song used in project_uuid.
+func (m MusicService) GetProjectMusic(ctx context.Context, project_uuid entities.ProjectUUID) (music []entities.Music, err error) {
+	return m.MusicRepo.GetProjectMusic(ctx, project_uuid)
+}
+
+// GetProjects returns every project that used music_id.
+func (m MusicService) GetProjects(ctx context.Context, music_id int32) (uuids []entities.ProjectUUID, err error) {
+	return m.MusicRepo.GetMusicProjects(ctx, music_id)
+}
@@ -0,0 +1,197 @@
+package probe
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/dtbead/wc-maps-archive/internal/entities"
+	"github.com/dtbead/wc-maps-archive/internal/storage"
+)
+
+type ProbeService struct {
+	ProbeRepo storage.ProbeRepository
+	FileRepo  storage.FileRepository
+}
+
+func NewService(ProbeRepo storage.ProbeRepository, FileRepo storage.FileRepository) *ProbeService {
+	return &ProbeService{ProbeRepo: ProbeRepo, FileRepo: FileRepo}
+}
+
+// ProbeFile probes the stored file of file_id and stores the result through StoreProbe. A nil claimed falls back to
+// what yt-dlp claimed when downloading the file, or to the file's file_video if it has never been probed, as that's
+// still what its source claimed.
+func (p ProbeService) ProbeFile(ctx context.Context, file_id entities.FileID, prober entities.VideoProber, claimed *entities.Video) (mismatches []entities.ProbeMismatch, err error) {
+	if prober == nil {
+		return nil, errors.New("nil prober given")
+	}
+
+	file, err := p.FileRepo.GetFile(ctx, file_id)
+	if err != nil {
+		return nil, err
+	}
+
+	probe, err := prober.Probe(ctx, file.PathAbsolute)
+	if err != nil {
+		return nil, err
+	}
+
+	if claimed == nil {
+		claimed, err = p.claim(ctx, file_id)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return p.StoreProbe(ctx, file_id, probe, claimed)
+}
+
+// claim returns what the source of file_id claimed it is, which is nil if nothing is known to have claimed anything.
+func (p ProbeService) claim(ctx context.Context, file_id entities.FileID) (claimed *entities.Video, err error) {
+	claimed, err = p.ProbeRepo.GetClaimedVideo(ctx, file_id)
+	if !errors.Is(err, sql.ErrNoRows) {
+		return claimed, err
+	}
+
+	// probing overwrites file_video with what was probed, which would be the probe verifying itself
+	_, err = p.ProbeRepo.GetProbe(ctx, file_id)
+	if err == nil {
+		return nil, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+
+	// a missing file_video just means there's nothing to verify against
+	claimed, err = p.FileRepo.GetFileVideo(ctx, file_id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+
+	return claimed, err
+}
+
+// StoreProbe stores probe for file_id along with any mismatches against claimed. A nil claimed keeps the mismatches
+// found before, as there's nothing to verify probe against.
+func (p ProbeService) StoreProbe(ctx context.Context, file_id entities.FileID, probe *entities.MediaProbe, claimed *entities.Video) (mismatches []entities.ProbeMismatch, err error) {
+	if !file_id.IsValid() {
+		return nil, errors.New("invalid file_id")
+	}
+
+	if probe == nil {
+		return nil, errors.New("nil probe given")
+	}
+
+	if claimed != nil {
+		mismatches = Compare(file_id, *claimed, *probe)
+	} else {
+		mismatches, err = p.ProbeRepo.GetProbeMismatches(ctx, file_id)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = p.ProbeRepo.NewProbe(ctx, file_id, probe, mismatches)
+	if err != nil {
+		return nil, err
+	}
+
+	return mismatches, nil
+}
+
+func (p ProbeService) GetProbe(ctx context.Context, file_id entities.FileID) (probe entities.MediaProbe, err error) {
+	res, err := p.ProbeRepo.GetProbe(ctx, file_id)
+	if err != nil {
+		return entities.MediaProbe{}, err
+	}
+
+	return *res, nil
+}
+
+func (p ProbeService) GetMismatches(ctx context.Context, file_id entities.FileID) (mismatches []entities.ProbeMismatch, err error) {
+	return p.ProbeRepo.GetProbeMismatches(ctx, file_id)
+}
+
+func (p ProbeService) GetAllMismatches(ctx context.Context) (mismatches []entities.ProbeMismatch, err error) {
+	return p.ProbeRepo.GetAllProbeMismatches(ctx)
+}
+
+func (p ProbeService) GetUnprobedFiles(ctx context.Context) (file_ids []entities.FileID, err error) {
+	return p.ProbeRepo.GetUnprobedFiles(ctx)
+}
+
+// Compare returns every property where probe disagrees with what claimed says the file should be. Properties claimed
+// leaves empty aren't checked.
+func Compare(file_id entities.FileID, claimed entities.Video, probe entities.MediaProbe) (mismatches []entities.ProbeMismatch) {
+	add := func(field, expected, actual string) {
+		mismatches = append(mismatches, entities.ProbeMismatch{FileID: file_id, Field: field, Expected: expected, Actual: actual})
+	}
+
+	// yt-dlp rounds durations to the nearest second, and containers rarely agree with it to the millisecond
+	if claimed.Duration > 0 && math.Abs(probe.Duration-float64(claimed.Duration)) > 1 {
+		add("duration", strconv.Itoa(claimed.Duration), strconv.FormatFloat(probe.Duration, 'f', 3, 64))
+	}
+
+	v := probe.FirstStream("video")
+	switch {
+	case hasCodec(claimed.VideoCodec) && v == nil:
+		add("video_stream", claimed.VideoCodec, "none")
+	case hasCodec(claimed.VideoCodec) && codecFamily(claimed.VideoCodec) != codecFamily(v.Codec):
+		add("video_codec", claimed.VideoCodec, v.Codec)
+	}
+
+	if v != nil {
+		if claimed.Width > 0 && claimed.Width != v.Width {
+			add("width", strconv.Itoa(int(claimed.Width)), strconv.Itoa(int(v.Width)))
+		}
+
+		if claimed.Height > 0 && claimed.Height != v.Height {
+			add("height", strconv.Itoa(int(claimed.Height)), strconv.Itoa(int(v.Height)))
+		}
+
+		if claimed.Fps > 0 && math.Abs(v.Fps-float64(claimed.Fps)) > 1 {
+			add("fps", strconv.Itoa(int(claimed.Fps)), strconv.FormatFloat(v.Fps, 'f', 3, 64))
+		}
+	}
+
+	a := probe.FirstStream("audio")
+	switch {
+	case hasCodec(claimed.AudioCodec) && a == nil:
+		add("audio_stream", claimed.AudioCodec, "none")
+	case hasCodec(claimed.AudioCodec) && codecFamily(claimed.AudioCodec) != codecFamily(a.Codec):
+		add("audio_codec", claimed.AudioCodec, a.Codec)
+	}
+
+	return mismatches
+}
+
+// hasCodec reports whether codec names an actual codec. yt-dlp reports "none" for a missing stream.
+func hasCodec(codec string) bool {
+	return codec != "" && codec != "none"
+}
+
+// codecFamily maps yt-dlp's RFC 6381 codec strings such as "avc1.64001F" and ffprobe's codec names such as "h264"
+// onto a common name, so that the two can be compared.
+func codecFamily(codec string) string {
+	name, _, _ := strings.Cut(strings.ToLower(codec), ".")
+
+	switch name {
+	case "avc1", "avc3", "h264":
+		return "h264"
+	case "hev1", "hvc1", "hevc", "h265":
+		return "hevc"
+	case "vp09", "vp9":
+		return "vp9"
+	case "vp08", "vp8":
+		return "vp8"
+	case "av01", "av1":
+		return "av1"
+	case "mp4a", "aac":
+		return "aac"
+	default:
+		return name
+	}
+}
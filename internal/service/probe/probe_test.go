@@ -0,0 +1,89 @@
+package probe_test
+
+import (
+	"testing"
+
+	"github.com/dtbead/wc-maps-archive/internal/entities"
+	"github.com/dtbead/wc-maps-archive/internal/service/probe"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestCompare(t *testing.T) {
+	const file_id entities.FileID = 1
+
+	// newProbe returns a probe of a file holding a video stream of video_codec and an audio stream of audio_codec, leaving
+	// out the streams whose codec is empty.
+	newProbe := func(duration float64, video_codec, audio_codec string) entities.MediaProbe {
+		p := entities.MediaProbe{Container: "mov,mp4,m4a,3gp,3g2,mj2", Duration: duration}
+		if video_codec != "" {
+			p.Streams = append(p.Streams, entities.MediaStream{Index: 0, CodecType: "video", Codec: video_codec, Width: 1920, Height: 1080, Fps: 29.97})
+		}
+		if audio_codec != "" {
+			p.Streams = append(p.Streams, entities.MediaStream{Index: len(p.Streams), CodecType: "audio", Codec: audio_codec})
+		}
+		return p
+	}
+
+	claimed := entities.Video{Duration: 212, VideoCodec: "avc1.64001F", AudioCodec: "mp4a.40.2", Width: 1920, Height: 1080, Fps: 30}
+
+	tests := []struct {
+		name    string
+		claimed entities.Video
+		probe   entities.MediaProbe
+		want    []entities.ProbeMismatch
+	}{
+		{"matching", claimed, newProbe(212, "h264", "aac"), nil},
+
+		// codecs are named differently by yt-dlp and ffprobe
+		{"avc3 is h264", entities.Video{VideoCodec: "avc3.4D401E"}, newProbe(212, "h264", ""), nil},
+		{"hvc1 is hevc", entities.Video{VideoCodec: "hvc1.1.6.L93.B0"}, newProbe(212, "hevc", ""), nil},
+		{"hev1 is hevc", entities.Video{VideoCodec: "hev1.1.6.L93.B0"}, newProbe(212, "hevc", ""), nil},
+		{"vp09 is vp9", entities.Video{VideoCodec: "vp09.00.40.08"}, newProbe(212, "vp9", ""), nil},
+		{"vp9 is vp9", entities.Video{VideoCodec: "vp9"}, newProbe(212, "vp9", ""), nil},
+		{"av01 is av1", entities.Video{VideoCodec: "av01.0.08M.08"}, newProbe(212, "av1", ""), nil},
+		{"opus is opus", entities.Video{AudioCodec: "opus"}, newProbe(212, "", "opus"), nil},
+		{"codecs are case insensitive", entities.Video{VideoCodec: "AVC1.64001F", AudioCodec: "MP4A.40.2"}, newProbe(212, "h264", "aac"), nil},
+		{"different video codec", entities.Video{VideoCodec: "vp09.00.40.08"}, newProbe(212, "h264", ""), []entities.ProbeMismatch{
+			{FileID: file_id, Field: "video_codec", Expected: "vp09.00.40.08", Actual: "h264"},
+		}},
+		{"different audio codec", entities.Video{AudioCodec: "opus"}, newProbe(212, "", "aac"), []entities.ProbeMismatch{
+			{FileID: file_id, Field: "audio_codec", Expected: "opus", Actual: "aac"},
+		}},
+
+		// yt-dlp rounds durations to the nearest second
+		{"duration within a second", entities.Video{Duration: 212}, newProbe(212.999, "h264", "aac"), nil},
+		{"duration a second short", entities.Video{Duration: 212}, newProbe(211, "h264", "aac"), nil},
+		{"duration over a second off", entities.Video{Duration: 212}, newProbe(210.5, "h264", "aac"), []entities.ProbeMismatch{
+			{FileID: file_id, Field: "duration", Expected: "212", Actual: "210.500"},
+		}},
+		{"truncated", claimed, newProbe(30, "h264", "aac"), []entities.ProbeMismatch{
+			{FileID: file_id, Field: "duration", Expected: "212", Actual: "30.000"},
+		}},
+
+		// whatever yt-dlp didn't claim isn't checked
+		{"nothing claimed", entities.Video{}, newProbe(30, "vp9", "opus"), nil},
+		{"no video claimed", entities.Video{VideoCodec: "none", AudioCodec: "mp4a.40.2"}, newProbe(212, "", "aac"), nil},
+		{"no audio claimed", entities.Video{VideoCodec: "avc1.64001F", AudioCodec: "none"}, newProbe(212, "h264", ""), nil},
+		{"missing video stream", entities.Video{VideoCodec: "avc1.64001F", Width: 1920}, newProbe(212, "", "aac"), []entities.ProbeMismatch{
+			{FileID: file_id, Field: "video_stream", Expected: "avc1.64001F", Actual: "none"},
+		}},
+		{"missing audio stream", entities.Video{AudioCodec: "mp4a.40.2"}, newProbe(212, "h264", ""), []entities.ProbeMismatch{
+			{FileID: file_id, Field: "audio_stream", Expected: "mp4a.40.2", Actual: "none"},
+		}},
+		{"different resolution", entities.Video{Width: 1280, Height: 720, Fps: 30}, newProbe(212, "h264", "aac"), []entities.ProbeMismatch{
+			{FileID: file_id, Field: "width", Expected: "1280", Actual: "1920"},
+			{FileID: file_id, Field: "height", Expected: "720", Actual: "1080"},
+		}},
+		{"different fps", entities.Video{Fps: 60}, newProbe(212, "h264", "aac"), []entities.ProbeMismatch{
+			{FileID: file_id, Field: "fps", Expected: "60", Actual: "29.970"},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := probe.Compare(file_id, tt.claimed, tt.probe)
+			if !cmp.Equal(got, tt.want) {
+				t.Errorf("Compare() got diff %s", cmp.Diff(got, tt.want))
+			}
+		})
+	}
+}
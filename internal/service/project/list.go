@@ -0,0 +1,27 @@
+package project
+
+import (
+	"context"
+
+	"github.com/dtbead/wc-maps-archive/internal/entities"
+	"github.com/dtbead/wc-maps-archive/internal/filter"
+)
+
+// ListProjects returns a page of projects matching query, written in the filter query language, along with the cursor
+// of the next page, which is empty on the last one.
+//
+// Projects can be filtered by title (or bare words), description, type, announced, completed, archived, uploaded,
+// duration, views, channel, status, was, participant, parts, music and character, as well as has:files, youtube,
+// title, description, parts, music, characters, relations, status, footage and part-footage.
+func (p ProjectService) ListProjects(ctx context.Context, query string, opts entities.ListOptions) (uuids []entities.ProjectUUID, next_cursor string, err error) {
+	if err := opts.Normalize(); err != nil {
+		return nil, "", err
+	}
+
+	f, err := filter.Parse(query)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return p.ProjectRepo.ListProjects(ctx, f, opts)
+}
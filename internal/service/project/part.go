@@ -0,0 +1,69 @@
+package project
+
+import (
+	"context"
+	"errors"
+	"math"
+
+	"github.com/dtbead/wc-maps-archive/internal/entities"
+)
+
+func IsValidPart(part *entities.ProjectPart) error {
+	if part == nil {
+		return errors.New("given nil part")
+	}
+
+	switch {
+	case part.Number < 0 || part.Number > math.MaxInt16:
+		return errors.New("invalid part number")
+	case part.Start < 0 || part.End < 0:
+		return errors.New("negative part timestamp")
+	case part.End == 0 && part.Start != 0:
+		return errors.New("part has a start but no end")
+	case part.End != 0 && part.End <= part.Start:
+		return errors.New("part ends before it starts")
+	case part.End.Milliseconds() > math.MaxInt32:
+		return errors.New("part ends too late")
+	case part.Participant != "" && !part.Participant.IsValid():
+		return entities.ErrorInvalidYoutubeChannelID
+	case part.YoutubeID != "" && !part.YoutubeID.IsValid():
+		return entities.ErrorInvalidYoutubeID
+	case part.FileID != 0 && !part.FileID.IsValid():
+		return errors.New("invalid file id")
+	}
+
+	return nil
+}
+
+func (p ProjectService) NewPart(ctx context.Context, part *entities.ProjectPart) (part_id int64, err error) {
+	if err := IsValidPart(part); err != nil {
+		return 0, err
+	}
+
+	return p.ProjectRepo.NewProjectPart(ctx, part)
+}
+
+func (p ProjectService) UpdatePart(ctx context.Context, part *entities.ProjectPart) (err error) {
+	if err := IsValidPart(part); err != nil {
+		return err
+	}
+
+	return p.ProjectRepo.UpdateProjectPart(ctx, part)
+}
+
+func (p ProjectService) DeletePart(ctx context.Context, part_id int64) (err error) {
+	return p.ProjectRepo.DeleteProjectPart(ctx, part_id)
+}
+
+func (p ProjectService) GetPart(ctx context.Context, part_id int64) (part entities.ProjectPart, err error) {
+	res, err := p.ProjectRepo.GetProjectPart(ctx, part_id)
+	if err != nil {
+		return entities.ProjectPart{}, err
+	}
+
+	return *res, nil
+}
+
+func (p ProjectService) GetParts(ctx context.Context, project_uuid entities.ProjectUUID) (parts []entities.ProjectPart, err error) {
+	return p.ProjectRepo.GetProjectParts(ctx, project_uuid)
+}
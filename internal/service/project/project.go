@@ -3,7 +3,7 @@ package project
 import (
 	"context"
 	"errors"
-	"time"
+	"strings"
 
 	"github.com/dtbead/wc-maps-archive/internal/entities"
 	"github.com/dtbead/wc-maps-archive/internal/helper"
@@ -30,11 +30,14 @@ func (p ProjectService) NewProject(ctx context.Context, project *entities.Projec
 		// DateArchived: time.Now().UTC().Truncate(time.Second),
 		// database layer handles this for us
 	}
-	if !project.DateCompleted.IsZero() {
-		proj.DateAnnounced = project.DateAnnounced.UTC().Truncate(time.Second)
+	if project.UUID != entities.InvalidProjectUUID {
+		proj.UUID = string(project.UUID)
 	}
 	if !project.DateAnnounced.IsZero() {
-		proj.DateCompleted = project.DateCompleted.UTC().Truncate(time.Second)
+		proj.DateAnnounced = entities.NewPartialDate(project.DateAnnounced.Time, project.DateAnnounced.Precision, project.DateAnnounced.Approximate)
+	}
+	if !project.DateCompleted.IsZero() {
+		proj.DateCompleted = entities.NewPartialDate(project.DateCompleted.Time, project.DateCompleted.Precision, project.DateCompleted.Approximate)
 	}
 
 	uuid, err = p.ProjectRepo.NewProject(ctx, proj)
@@ -45,12 +48,22 @@ func (p ProjectService) NewProject(ctx context.Context, project *entities.Projec
 	return uuid, err
 }
 
+// SetDateAnnounced sets when project_uuid was announced, as precisely as it is known. An unknown date clears it.
+func (p ProjectService) SetDateAnnounced(ctx context.Context, project_uuid entities.ProjectUUID, date entities.PartialDate) (err error) {
+	return p.ProjectRepo.SetProjectDateAnnounced(ctx, project_uuid, date)
+}
+
+// SetDateCompleted sets when project_uuid was completed, as precisely as it is known. An unknown date clears it.
+func (p ProjectService) SetDateCompleted(ctx context.Context, project_uuid entities.ProjectUUID, date entities.PartialDate) (err error) {
+	return p.ProjectRepo.SetProjectDateCompleted(ctx, project_uuid, date)
+}
+
 func (p ProjectService) DeleteProject(ctx context.Context, project_uuid entities.ProjectUUID) (err error) {
 	return p.ProjectRepo.DeleteProject(ctx, project_uuid)
 }
 
 func (p ProjectService) AssignFile(ctx context.Context, project_uuid entities.ProjectUUID, file_id entities.FileID) (err error) {
-	return p.AssignFile(ctx, project_uuid, file_id)
+	return p.ProjectRepo.AssignProjectFile(ctx, project_uuid, file_id)
 }
 
 func (p ProjectService) AssignYoutube(ctx context.Context, project_uuid entities.ProjectUUID, youtube_id entities.YoutubeVideoID) (err error) {
@@ -61,26 +74,74 @@ func (p ProjectService) UnassignYoutube(ctx context.Context, project_uuid entiti
 	return p.ProjectRepo.UnassignYoutube(ctx, project_uuid, youtube_id)
 }
 
+// AssignPrimaryFile would mark file_id as the primary file of project_uuid, but nothing records which file is primary
+// yet, so it always returns entities.ErrorPrimaryFileUnsupported.
 func (p ProjectService) AssignPrimaryFile(ctx context.Context, project_uuid entities.ProjectUUID, file_id entities.FileID) (err error) {
-	panic("unimplemented")
+	return entities.ErrorPrimaryFileUnsupported
 }
 
+// UnassignPrimaryFile always returns entities.ErrorPrimaryFileUnsupported, see AssignPrimaryFile.
 func (p ProjectService) UnassignPrimaryFile(ctx context.Context, project_uuid entities.ProjectUUID, file_id entities.FileID) (err error) {
-	panic("unimplemented")
+	return entities.ErrorPrimaryFileUnsupported
 }
 
+// UnassignFile removes file_id from the files of project_uuid, returning sql.ErrNoRows if it wasn't one of them.
 func (p ProjectService) UnassignFile(ctx context.Context, project_uuid entities.ProjectUUID, file_id entities.FileID) (err error) {
-	panic("unimplemented")
+	if !file_id.IsValid() {
+		return errors.New("invalid file_id")
+	}
+
+	return p.ProjectRepo.UnassignProjectVideo(ctx, project_uuid, file_id)
 }
 
 func (p ProjectService) SetProjectType(ctx context.Context, project_uuid entities.ProjectUUID, project_type entities.ProjectType) (err error) {
-	panic("unimplemented")
+	return p.ProjectRepo.SetProjectType(ctx, project_uuid, project_type)
 }
 
 func (p ProjectService) GetProject(ctx context.Context, project_uuid entities.ProjectUUID) (project entities.Project, err error) {
-	panic("unimplemented")
+	res, err := p.ProjectRepo.GetProject(ctx, project_uuid)
+	if err != nil {
+		return entities.Project{}, err
+	}
+
+	return *res, nil
+}
+
+// GetFileProjects returns every project file_id belongs to, either as one of its files or as the upload of one of its
+// parts.
+func (p ProjectService) GetFileProjects(ctx context.Context, file_id entities.FileID) (uuids []entities.ProjectUUID, err error) {
+	if !file_id.IsValid() {
+		return nil, errors.New("invalid file_id")
+	}
+
+	return p.ProjectRepo.GetFileProjects(ctx, file_id)
 }
 
+// GetProjectYoutube returns every youtube video which one of the files of project_uuid is an upload of.
 func (p ProjectService) GetProjectYoutube(ctx context.Context, project_uuid entities.ProjectUUID) (youtube_ids []entities.YoutubeVideoID, err error) {
-	panic("unimplemented")
+	return p.ProjectRepo.GetProjectYoutube(ctx, project_uuid)
+}
+
+// SetTitle makes title the current title of project_uuid, keeping the previous ones as its history.
+func (p ProjectService) SetTitle(ctx context.Context, project_uuid entities.ProjectUUID, title string) (err error) {
+	title = strings.TrimSpace(title)
+	if title == "" {
+		return errors.New("empty title")
+	}
+
+	return p.ProjectRepo.NewProjectTitle(ctx, project_uuid, title)
+}
+
+func (p ProjectService) GetTitles(ctx context.Context, project_uuid entities.ProjectUUID) (titles []entities.ProjectTitle, err error) {
+	return p.ProjectRepo.GetProjectTitles(ctx, project_uuid)
+}
+
+// SetDescription makes description the current description of project_uuid, keeping the previous ones as its
+// history. An empty description clears it.
+func (p ProjectService) SetDescription(ctx context.Context, project_uuid entities.ProjectUUID, description string) (err error) {
+	return p.ProjectRepo.NewProjectDescription(ctx, project_uuid, strings.TrimSpace(description))
+}
+
+func (p ProjectService) GetDescriptions(ctx context.Context, project_uuid entities.ProjectUUID) (descriptions []entities.ProjectDescription, err error) {
+	return p.ProjectRepo.GetProjectDescriptions(ctx, project_uuid)
 }
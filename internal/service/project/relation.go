@@ -0,0 +1,50 @@
+package project
+
+import (
+	"context"
+	"errors"
+
+	"github.com/dtbead/wc-maps-archive/internal/entities"
+)
+
+func IsValidRelation(relation entities.ProjectRelation) error {
+	switch {
+	case relation.Project == entities.InvalidProjectUUID || relation.Related == entities.InvalidProjectUUID:
+		return errors.New("invalid project uuid")
+	case relation.Type == entities.ProjectRelationUnknown:
+		return errors.New("unknown project relation type")
+	case relation.Project == relation.Related:
+		return entities.ErrorProjectRelationCycle
+	}
+
+	return nil
+}
+
+// Relate stores relation, which reads as "relation.Project is a relation.Type of relation.Related".
+func (p ProjectService) Relate(ctx context.Context, relation entities.ProjectRelation) (err error) {
+	if err := IsValidRelation(relation); err != nil {
+		return err
+	}
+
+	return p.ProjectRepo.NewProjectRelation(ctx, relation)
+}
+
+func (p ProjectService) Unrelate(ctx context.Context, relation entities.ProjectRelation) (err error) {
+	return p.ProjectRepo.DeleteProjectRelation(ctx, relation)
+}
+
+// GetRelations returns every direct relation project_uuid is either side of.
+func (p ProjectService) GetRelations(ctx context.Context, project_uuid entities.ProjectUUID) (relations []entities.ProjectRelation, err error) {
+	return p.ProjectRepo.GetProjectRelations(ctx, project_uuid)
+}
+
+// GetAncestors walks up the relation_type tree from project_uuid, such as from a part to its MAP and on to whatever
+// that MAP is part of.
+func (p ProjectService) GetAncestors(ctx context.Context, project_uuid entities.ProjectUUID, relation_type entities.ProjectRelationType) (relations []entities.ProjectRelation, err error) {
+	return p.ProjectRepo.GetProjectAncestors(ctx, project_uuid, relation_type)
+}
+
+// GetDescendants walks down the relation_type tree from project_uuid, such as from a MAP to all of its parts.
+func (p ProjectService) GetDescendants(ctx context.Context, project_uuid entities.ProjectUUID, relation_type entities.ProjectRelationType) (relations []entities.ProjectRelation, err error) {
+	return p.ProjectRepo.GetProjectDescendants(ctx, project_uuid, relation_type)
+}
@@ -0,0 +1,107 @@
+package project
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/dtbead/wc-maps-archive/internal/entities"
+)
+
+// statusChanges maps every status to those a project may change to from it. A project without a status may start
+// anywhere but revived, as it's often only archived long after being announced. Completed is final, while a cancelled
+// project may only be revived.
+var statusChanges = map[entities.ProjectStatus][]entities.ProjectStatus{
+	entities.ProjectStatusUnknown: {
+		entities.ProjectStatusAnnounced, entities.ProjectStatusSignupsOpen, entities.ProjectStatusPartsAssigned,
+		entities.ProjectStatusInProgress, entities.ProjectStatusCompleted, entities.ProjectStatusCancelled,
+	},
+	entities.ProjectStatusAnnounced: {
+		entities.ProjectStatusSignupsOpen, entities.ProjectStatusPartsAssigned, entities.ProjectStatusInProgress,
+		entities.ProjectStatusCompleted, entities.ProjectStatusCancelled,
+	},
+	entities.ProjectStatusSignupsOpen: {
+		entities.ProjectStatusPartsAssigned, entities.ProjectStatusInProgress, entities.ProjectStatusCompleted,
+		entities.ProjectStatusCancelled,
+	},
+	entities.ProjectStatusPartsAssigned: {
+		entities.ProjectStatusSignupsOpen, entities.ProjectStatusInProgress, entities.ProjectStatusCompleted,
+		entities.ProjectStatusCancelled,
+	},
+	entities.ProjectStatusInProgress: {
+		entities.ProjectStatusSignupsOpen, entities.ProjectStatusCompleted, entities.ProjectStatusCancelled,
+	},
+	entities.ProjectStatusCompleted: {},
+	entities.ProjectStatusCancelled: {entities.ProjectStatusRevived},
+	entities.ProjectStatusRevived: {
+		entities.ProjectStatusSignupsOpen, entities.ProjectStatusPartsAssigned, entities.ProjectStatusInProgress,
+		entities.ProjectStatusCompleted, entities.ProjectStatusCancelled,
+	},
+}
+
+// CanChangeStatus reports whether a project may go from one status to another.
+func CanChangeStatus(from, to entities.ProjectStatus) bool {
+	return slices.Contains(statusChanges[from], to)
+}
+
+// IsValidStatusChange checks that change may follow the last entry of timeline, both by status and by date. Errors
+// about the order of statuses wrap entities.ErrorInvalidStatusChange.
+func IsValidStatusChange(timeline []entities.ProjectStatusChange, change entities.ProjectStatusChange) error {
+	if change.Status == entities.ProjectStatusUnknown {
+		return fmt.Errorf("%w, unknown status", entities.ErrorInvalidStatusChange)
+	}
+
+	if change.Date.IsZero() {
+		return fmt.Errorf("%w, no date given", entities.ErrorInvalidStatusChange)
+	}
+
+	current := entities.ProjectStatusChange{Status: entities.ProjectStatusUnknown}
+	if len(timeline) > 0 {
+		current = timeline[len(timeline)-1]
+	}
+
+	if !CanChangeStatus(current.Status, change.Status) {
+		return fmt.Errorf("%w, a project can't go from %s to %s", entities.ErrorInvalidStatusChange, current.Status.ToString(), change.Status.ToString())
+	}
+
+	if change.Date.Before(current.Date) {
+		return fmt.Errorf("%w, %s on %s is before the project was %s on %s", entities.ErrorInvalidStatusChange,
+			change.Status.ToString(), change.Date.Format(time.DateOnly), current.Status.ToString(), current.Date.Format(time.DateOnly))
+	}
+
+	return nil
+}
+
+// SetStatus changes the status of project_uuid, adding change to its timeline. The change has to follow from the
+// current status, and can't be dated before it, which is checked along with adding it so that two changes made at
+// once can't both follow from the same status.
+func (p ProjectService) SetStatus(ctx context.Context, project_uuid entities.ProjectUUID, change entities.ProjectStatusChange) (status_id int64, err error) {
+	return p.ProjectRepo.NewProjectStatus(ctx, project_uuid, change, func(timeline []entities.ProjectStatusChange) error {
+		return IsValidStatusChange(timeline, change)
+	})
+}
+
+// UndoStatus removes the current status of project_uuid, for when it was set by mistake.
+func (p ProjectService) UndoStatus(ctx context.Context, project_uuid entities.ProjectUUID) (err error) {
+	return p.ProjectRepo.DeleteLatestProjectStatus(ctx, project_uuid)
+}
+
+// GetTimeline returns every status project_uuid went through, oldest first.
+func (p ProjectService) GetTimeline(ctx context.Context, project_uuid entities.ProjectUUID) (timeline []entities.ProjectStatusChange, err error) {
+	return p.ProjectRepo.GetProjectStatuses(ctx, project_uuid)
+}
+
+// GetStatus returns the current status of project_uuid, which is entities.ProjectStatusUnknown if none was recorded.
+func (p ProjectService) GetStatus(ctx context.Context, project_uuid entities.ProjectUUID) (status entities.ProjectStatusChange, err error) {
+	timeline, err := p.ProjectRepo.GetProjectStatuses(ctx, project_uuid)
+	if err != nil {
+		return entities.ProjectStatusChange{}, err
+	}
+
+	if len(timeline) == 0 {
+		return entities.ProjectStatusChange{Status: entities.ProjectStatusUnknown}, nil
+	}
+
+	return timeline[len(timeline)-1], nil
+}
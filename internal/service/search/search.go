@@ -0,0 +1,40 @@
+package search
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/dtbead/wc-maps-archive/internal/entities"
+	"github.com/dtbead/wc-maps-archive/internal/storage"
+)
+
+// DefaultMaxResults is how many results Search returns when not told otherwise.
+const DefaultMaxResults = 50
+
+// MaxResults is the most results a single Search returns.
+const MaxResults = 500
+
+type SearchService struct {
+	SearchRepo storage.SearchRepository
+}
+
+func NewService(SearchRepo storage.SearchRepository) *SearchService {
+	return &SearchService{SearchRepo: SearchRepo}
+}
+
+// Search looks query up across the whole archive, best match first. max_results falls back to DefaultMaxResults if
+// it's 0 or less, and is capped at MaxResults.
+func (s SearchService) Search(ctx context.Context, query string, kind entities.SearchKind, max_results int) (results []entities.SearchResult, err error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, errors.New("empty search query")
+	}
+
+	if max_results <= 0 {
+		max_results = DefaultMaxResults
+	}
+	max_results = min(max_results, MaxResults)
+
+	return s.SearchRepo.Search(ctx, query, kind, max_results)
+}
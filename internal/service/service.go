@@ -2,28 +2,66 @@ package service
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
 
 	"github.com/dtbead/wc-maps-archive/internal/entities"
+	"github.com/dtbead/wc-maps-archive/internal/service/artist"
+	"github.com/dtbead/wc-maps-archive/internal/service/audit"
+	"github.com/dtbead/wc-maps-archive/internal/service/character"
 	"github.com/dtbead/wc-maps-archive/internal/service/file"
+	"github.com/dtbead/wc-maps-archive/internal/service/fingerprint"
+	"github.com/dtbead/wc-maps-archive/internal/service/fsck"
+	"github.com/dtbead/wc-maps-archive/internal/service/music"
+	"github.com/dtbead/wc-maps-archive/internal/service/probe"
 	"github.com/dtbead/wc-maps-archive/internal/service/project"
+	"github.com/dtbead/wc-maps-archive/internal/service/search"
 	"github.com/dtbead/wc-maps-archive/internal/service/youtube"
 	"github.com/dtbead/wc-maps-archive/internal/storage"
 )
 
 type Service struct {
-	ProjectService ProjectService
-	FileService    FileService
-	YoutubeService YoutubeService
+	ProjectService     ProjectService
+	FileService        FileService
+	YoutubeService     YoutubeService
+	ProbeService       ProbeService
+	FingerprintService FingerprintService
+	ArtistService      ArtistService
+	MusicService       MusicService
+	CharacterService   CharacterService
+	SearchService      SearchService
+	AuditService       AuditService
+	FsckService        FsckService
 }
 
+// NewService builds every service out of repositories. Changes made through the ProjectService, FileService and
+// YoutubeService get recorded by the AuditService, and those to projects and youtube videos rewrite the sidecars of
+// their files.
 func NewService(repositories *storage.Repository) *Service {
-	return &Service{
-		ProjectService: project.NewService(repositories.Project),
-		FileService:    file.NewService(repositories.File),
-		YoutubeService: youtube.NewService(repositories.Youtube),
+	audit_service := audit.NewService(repositories.Audit)
+
+	s := &Service{}
+	sidecars := func(ctx context.Context, file_ids []entities.FileID) {
+		s.rewriteSidecars(ctx, file_ids)
+	}
+
+	*s = Service{
+		ProjectService:     auditedProjectService{project.NewService(repositories.Project), audit_service, sidecars},
+		FileService:        auditedFileService{file.NewService(repositories.File), audit_service},
+		YoutubeService:     auditedYoutubeService{youtube.NewService(repositories.Youtube), audit_service, sidecars},
+		ProbeService:       probe.NewService(repositories.Probe, repositories.File),
+		FingerprintService: fingerprint.NewService(repositories.Fingerprint, repositories.File),
+		ArtistService:      artist.NewService(repositories.Artist),
+		MusicService:       music.NewService(repositories.Music),
+		CharacterService:   character.NewService(repositories.Character),
+		SearchService:      search.NewService(repositories.Search),
+		AuditService:       audit_service,
+		FsckService:        fsck.NewService(repositories.Fsck),
 	}
+
+	return s
 }
 
 type ProjectService interface {
@@ -36,29 +74,149 @@ type ProjectService interface {
 	UnassignFile(ctx context.Context, project_uuid entities.ProjectUUID, file_id entities.FileID) (err error)
 	UnassignYoutube(ctx context.Context, project_uuid entities.ProjectUUID, youtube_id entities.YoutubeVideoID) (err error)
 	SetProjectType(ctx context.Context, project_uuid entities.ProjectUUID, project_type entities.ProjectType) (err error)
+	SetDateAnnounced(ctx context.Context, project_uuid entities.ProjectUUID, date entities.PartialDate) (err error)
+	SetDateCompleted(ctx context.Context, project_uuid entities.ProjectUUID, date entities.PartialDate) (err error)
 	GetProject(ctx context.Context, project_uuid entities.ProjectUUID) (project entities.Project, err error)
 	GetProjectYoutube(ctx context.Context, project_uuid entities.ProjectUUID) (youtube_ids []entities.YoutubeVideoID, err error)
+	GetFileProjects(ctx context.Context, file_id entities.FileID) (uuids []entities.ProjectUUID, err error)
+	SetTitle(ctx context.Context, project_uuid entities.ProjectUUID, title string) (err error)
+	GetTitles(ctx context.Context, project_uuid entities.ProjectUUID) (titles []entities.ProjectTitle, err error)
+	SetDescription(ctx context.Context, project_uuid entities.ProjectUUID, description string) (err error)
+	GetDescriptions(ctx context.Context, project_uuid entities.ProjectUUID) (descriptions []entities.ProjectDescription, err error)
+	NewPart(ctx context.Context, part *entities.ProjectPart) (part_id int64, err error)
+	UpdatePart(ctx context.Context, part *entities.ProjectPart) (err error)
+	DeletePart(ctx context.Context, part_id int64) (err error)
+	GetPart(ctx context.Context, part_id int64) (part entities.ProjectPart, err error)
+	GetParts(ctx context.Context, project_uuid entities.ProjectUUID) (parts []entities.ProjectPart, err error)
+	Relate(ctx context.Context, relation entities.ProjectRelation) (err error)
+	Unrelate(ctx context.Context, relation entities.ProjectRelation) (err error)
+	GetRelations(ctx context.Context, project_uuid entities.ProjectUUID) (relations []entities.ProjectRelation, err error)
+	GetAncestors(ctx context.Context, project_uuid entities.ProjectUUID, relation_type entities.ProjectRelationType) (relations []entities.ProjectRelation, err error)
+	GetDescendants(ctx context.Context, project_uuid entities.ProjectUUID, relation_type entities.ProjectRelationType) (relations []entities.ProjectRelation, err error)
+	SetStatus(ctx context.Context, project_uuid entities.ProjectUUID, change entities.ProjectStatusChange) (status_id int64, err error)
+	UndoStatus(ctx context.Context, project_uuid entities.ProjectUUID) (err error)
+	GetTimeline(ctx context.Context, project_uuid entities.ProjectUUID) (timeline []entities.ProjectStatusChange, err error)
+	GetStatus(ctx context.Context, project_uuid entities.ProjectUUID) (status entities.ProjectStatusChange, err error)
+	ListProjects(ctx context.Context, query string, opts entities.ListOptions) (uuids []entities.ProjectUUID, next_cursor string, err error)
 }
 
 type FileService interface {
 	NewFile(ctx context.Context, file io.Reader, extension string) (file_id entities.FileID, err error)
 	DeleteFile(ctx context.Context, file_id entities.FileID) (err error)
+	GetFile(ctx context.Context, file_id entities.FileID) (file entities.File, err error)
+	FindFile(ctx context.Context, sha256 []byte) (file_id entities.FileID, err error)
+	AdoptFile(ctx context.Context, path_relative string) (file_id entities.FileID, err error)
+	GetStoredPaths(ctx context.Context) (paths []string, err error)
+	WriteSidecar(ctx context.Context, file_id entities.FileID, sidecar []byte) (err error)
+	ReadSidecar(ctx context.Context, path_relative string) (sidecar []byte, err error)
 	NewTempFile(ctx context.Context) (file io.ReadWriteCloser, err error)
 	GetHash(ctx context.Context, file_id entities.FileID) (err error, hashes entities.Hashes)
 	GetReader(ctx context.Context, file_id entities.FileID) (file io.ReadCloser, err error)
 	GetFileRelationship(ctx context.Context, file_id entities.FileID) (relationships entities.FileRelationship, err error)
+	NewFileVideo(ctx context.Context, file_id entities.FileID, video *entities.Video) (err error)
+	GetFileVideo(ctx context.Context, file_id entities.FileID) (video entities.Video, err error)
+	ListFiles(ctx context.Context, opts entities.ListOptions) (file_ids []entities.FileID, next_cursor string, err error)
 }
 
 type YoutubeService interface {
 	NewYoutube(ctx context.Context, file_id entities.FileID, youtube *entities.Youtube) (err error)
+	GetYoutube(ctx context.Context, youtube_id entities.YoutubeVideoID) (youtube *entities.Youtube, err error)
+	GetFileYoutube(ctx context.Context, file_id entities.FileID) (youtube_id entities.YoutubeVideoID, err error)
+	GetYtdlpInfo(ctx context.Context, file_id entities.FileID) (info json.RawMessage, err error)
+	GetThumbnail(ctx context.Context, file_id entities.FileID) (thumbnail []byte, err error)
 	GetYoutubeFileIDs(ctx context.Context, youtube_id entities.YoutubeVideoID) (file_ids []entities.FileID, err error)
 	GetTitle(ctx context.Context, youtube_id entities.YoutubeVideoID) (title string, err error)
 	GetDescription(ctx context.Context, youtube_id entities.YoutubeVideoID) (description string, err error)
 	GetChannelVideos(ctx context.Context, channel_id entities.YoutubeChannelID) (videos []entities.YoutubeVideoID, err error)
 	GetYoutubeVideo(ctx context.Context, youtube_id entities.YoutubeVideoID) (video entities.YoutubeVideo, err error)
+	AssignFile(ctx context.Context, youtube_id entities.YoutubeVideoID, file_id entities.FileID) (err error)
+	ListYoutube(ctx context.Context, query string, opts entities.ListOptions) (youtube_ids []entities.YoutubeVideoID, next_cursor string, err error)
+	ListChannels(ctx context.Context, opts entities.ListOptions) (channel_ids []entities.YoutubeChannelID, next_cursor string, err error)
+}
+
+type ProbeService interface {
+	ProbeFile(ctx context.Context, file_id entities.FileID, prober entities.VideoProber, claimed *entities.Video) (mismatches []entities.ProbeMismatch, err error)
+	StoreProbe(ctx context.Context, file_id entities.FileID, probe *entities.MediaProbe, claimed *entities.Video) (mismatches []entities.ProbeMismatch, err error)
+	GetProbe(ctx context.Context, file_id entities.FileID) (probe entities.MediaProbe, err error)
+	GetMismatches(ctx context.Context, file_id entities.FileID) (mismatches []entities.ProbeMismatch, err error)
+	GetAllMismatches(ctx context.Context) (mismatches []entities.ProbeMismatch, err error)
+	GetUnprobedFiles(ctx context.Context) (file_ids []entities.FileID, err error)
 }
 
-func (s Service) DownloadYoutube(ctx context.Context, url string, downloader entities.YoutubeDownloader) (err error) {
+type FingerprintService interface {
+	FingerprintFile(ctx context.Context, file_id entities.FileID, fingerprinter entities.VideoFingerprinter) (err error)
+	GetFingerprint(ctx context.Context, file_id entities.FileID) (fingerprint entities.Fingerprint, err error)
+	GetUnfingerprintedFiles(ctx context.Context) (file_ids []entities.FileID, err error)
+	FindSimilar(ctx context.Context, file_id entities.FileID, min_score float64) (matches []entities.FingerprintMatch, err error)
+	FindSimilarFingerprint(ctx context.Context, fingerprint entities.Fingerprint, min_score float64) (matches []entities.FingerprintMatch, err error)
+}
+
+type ArtistService interface {
+	NewArtist(ctx context.Context, artist_name string) (err error)
+	DeleteArtist(ctx context.Context, artist_name string) (err error)
+	Rename(ctx context.Context, artist_name, new_name string) (err error)
+	AddAlias(ctx context.Context, artist_name, alias string) (err error)
+	RemoveAlias(ctx context.Context, artist_name, alias string) (err error)
+	AssignChannel(ctx context.Context, artist_name string, channel_id entities.YoutubeChannelID) (err error)
+	UnassignChannel(ctx context.Context, artist_name string, channel_id entities.YoutubeChannelID) (err error)
+	GetArtist(ctx context.Context, artist_name string) (artist entities.Artist, err error)
+	GetChannelArtists(ctx context.Context, channel_id entities.YoutubeChannelID) (artists []string, err error)
+	GetVideos(ctx context.Context, artist_name string) (videos []entities.YoutubeVideoID, err error)
+	GetParts(ctx context.Context, artist_name string) (parts []entities.ProjectPart, err error)
+}
+
+type MusicService interface {
+	NewMusic(ctx context.Context, music *entities.Music) (music_id int32, err error)
+	UpdateMusic(ctx context.Context, music *entities.Music) (err error)
+	DeleteMusic(ctx context.Context, music_id int32) (err error)
+	GetMusic(ctx context.Context, music_id int32) (music entities.Music, err error)
+	FindMusic(ctx context.Context, artist, title string) (matches []entities.MusicMatch, err error)
+	AssignProject(ctx context.Context, project_uuid entities.ProjectUUID, music_id int32) (err error)
+	UnassignProject(ctx context.Context, project_uuid entities.ProjectUUID, music_id int32) (err error)
+	GetProjectMusic(ctx context.Context, project_uuid entities.ProjectUUID) (music []entities.Music, err error)
+	GetProjects(ctx context.Context, music_id int32) (uuids []entities.ProjectUUID, err error)
+}
+
+type CharacterService interface {
+	NewCharacter(ctx context.Context, character *entities.Character) (character_id int, err error)
+	UpdateCharacter(ctx context.Context, character *entities.Character) (err error)
+	DeleteCharacter(ctx context.Context, character_id int) (err error)
+	GetCharacter(ctx context.Context, character_id int) (character entities.Character, err error)
+	AddAlias(ctx context.Context, character_id int, alias entities.CharacterAlias) (err error)
+	RemoveAlias(ctx context.Context, character_id int, name string) (err error)
+	FindCharacters(ctx context.Context, name string) (characters []entities.Character, err error)
+	GetSeriesCharacters(ctx context.Context, series string) (characters []entities.Character, err error)
+	TagProject(ctx context.Context, project_uuid entities.ProjectUUID, character_id int) (err error)
+	UntagProject(ctx context.Context, project_uuid entities.ProjectUUID, character_id int) (err error)
+	GetProjectCharacters(ctx context.Context, project_uuid entities.ProjectUUID) (characters []entities.Character, err error)
+	TagPart(ctx context.Context, part_id int64, character_id int) (err error)
+	UntagPart(ctx context.Context, part_id int64, character_id int) (err error)
+	GetPartCharacters(ctx context.Context, part_id int64) (characters []entities.Character, err error)
+	GetProjects(ctx context.Context, character_id int) (uuids []entities.ProjectUUID, err error)
+}
+
+type SearchService interface {
+	Search(ctx context.Context, query string, kind entities.SearchKind, max_results int) (results []entities.SearchResult, err error)
+}
+
+type AuditService interface {
+	Record(ctx context.Context, entity entities.AuditEntity, entity_id string, action string, before, after any) (err error)
+	GetEntry(ctx context.Context, entry_id int64) (entry entities.AuditEntry, err error)
+	GetEntityLog(ctx context.Context, entity entities.AuditEntity, entity_id string) (entries []entities.AuditEntry, err error)
+}
+
+type FsckService interface {
+	GetYoutubeWithoutFiles(ctx context.Context) (youtube_ids []entities.YoutubeVideoID, err error)
+	GetFilesWithoutVideo(ctx context.Context) (file_ids []entities.FileID, err error)
+	GetUnassignedDownloads(ctx context.Context) (downloads []entities.YoutubeDownload, err error)
+	GetMisattributedDownloads(ctx context.Context) (downloads []entities.YoutubeDownload, err error)
+	GetProjectsWithoutFiles(ctx context.Context) (uuids []entities.ProjectUUID, err error)
+}
+
+// DownloadYoutube downloads and stores the youtube video at url, then probes the stored file so that anything yt-dlp
+// got wrong about it gets flagged as a mismatch. The stored file is kept even if it fails to be probed, to be probed
+// again by BackfillProbes, or if its sidecar fails to be written.
+func (s Service) DownloadYoutube(ctx context.Context, url string, downloader entities.YoutubeDownloader, prober entities.VideoProber) (err error) {
 	tmp, err := s.FileService.NewTempFile(ctx)
 	if err != nil {
 		return err
@@ -75,10 +233,67 @@ func (s Service) DownloadYoutube(ctx context.Context, url string, downloader ent
 		return err
 	}
 
-	err = s.YoutubeService.NewYoutube(ctx, file_id, yt)
+	batch_ctx, batch := s.batchSidecars(ctx)
+	err = s.YoutubeService.NewYoutube(batch_ctx, file_id, yt)
 	if err != nil {
 		return errors.Join(err, s.FileService.DeleteFile(ctx, file_id))
 	}
 
-	return nil
+	_, probe_err := s.ProbeService.ProbeFile(ctx, file_id, prober, &yt.YouTube.Video)
+	if probe_err != nil {
+		probe_err = fmt.Errorf("probing file %d: %w", file_id, probe_err)
+	}
+
+	return errors.Join(probe_err, batch.write(ctx, file_id))
+}
+
+type ProbeResult struct {
+	FileID     entities.FileID
+	Mismatches []entities.ProbeMismatch
+	Err        error
+}
+
+// BackfillProbes probes every file that hasn't been probed yet, verifying it against its current file_video. A file
+// failing to probe doesn't stop the backfill; its error is reported in its ProbeResult instead.
+func (s Service) BackfillProbes(ctx context.Context, prober entities.VideoProber) (results []ProbeResult, err error) {
+	file_ids, err := s.ProbeService.GetUnprobedFiles(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, file_id := range file_ids {
+		if err := ctx.Err(); err != nil {
+			return results, err
+		}
+
+		mismatches, err := s.ProbeService.ProbeFile(ctx, file_id, prober, nil)
+		results = append(results, ProbeResult{FileID: file_id, Mismatches: mismatches, Err: err})
+	}
+
+	return results, nil
+}
+
+type FingerprintResult struct {
+	FileID entities.FileID
+	Err    error
+}
+
+// BackfillFingerprints fingerprints every video file that hasn't been fingerprinted yet. A file failing to fingerprint
+// doesn't stop the backfill; its error is reported in its FingerprintResult instead.
+func (s Service) BackfillFingerprints(ctx context.Context, fingerprinter entities.VideoFingerprinter) (results []FingerprintResult, err error) {
+	file_ids, err := s.FingerprintService.GetUnfingerprintedFiles(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, file_id := range file_ids {
+		if err := ctx.Err(); err != nil {
+			return results, err
+		}
+
+		err := s.FingerprintService.FingerprintFile(ctx, file_id, fingerprinter)
+		results = append(results, FingerprintResult{FileID: file_id, Err: err})
+	}
+
+	return results, nil
 }
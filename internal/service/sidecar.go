@@ -0,0 +1,268 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"slices"
+	"time"
+
+	"github.com/dtbead/wc-maps-archive/internal/bundle"
+	"github.com/dtbead/wc-maps-archive/internal/entities"
+	file_helper "github.com/dtbead/wc-maps-archive/internal/helper/file"
+)
+
+// WriteSidecar writes the sidecar of file_id next to it, describing it along with its youtube video and every project
+// it belongs to. Sidecars are written whenever a file is stored, and rewritten whenever its youtube video or projects
+// change through the Service; BackfillSidecars catches up on any that failed to be. Probes and fingerprints aren't kept
+// in sidecars, as they can be backfilled again.
+func (s Service) WriteSidecar(ctx context.Context, file_id entities.FileID) error {
+	e := bundleExport{s: s, ctx: ctx, files: make(map[entities.FileID]string), every_relation: true}
+
+	sha256, err := e.file(file_id)
+	if err != nil {
+		return err
+	}
+
+	sidecar := bundle.Sidecar{
+		Version: bundle.SidecarVersion,
+		Written: time.Now().UTC(),
+		File:    e.manifest.Files[0],
+	}
+
+	youtube_id, err := s.YoutubeService.GetFileYoutube(ctx, file_id)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+
+	if err == nil {
+		youtube, err := e.youtube(youtube_id)
+		if err != nil {
+			return err
+		}
+
+		// the info json and thumbnail only belong to the first file of a video
+		if youtube.Files[0] == sha256 {
+			youtube.DlpInfo, err = s.YoutubeService.GetYtdlpInfo(ctx, file_id)
+			if err != nil && !errors.Is(err, sql.ErrNoRows) {
+				return err
+			}
+		} else {
+			youtube.Thumbnail = nil
+		}
+
+		sidecar.Youtube = &youtube
+	}
+
+	e.project_uuids, err = s.ProjectService.GetFileProjects(ctx, file_id)
+	if err != nil {
+		return err
+	}
+
+	for _, project_uuid := range e.project_uuids {
+		project, err := e.project(project_uuid)
+		if err != nil {
+			return fmt.Errorf("project %s: %w", project_uuid, err)
+		}
+
+		sidecar.Projects = append(sidecar.Projects, project)
+	}
+
+	contents, err := json.MarshalIndent(sidecar, "", "\t")
+	if err != nil {
+		return err
+	}
+
+	return s.FileService.WriteSidecar(ctx, file_id, contents)
+}
+
+// rewriteSidecars rewrites the sidecars of file_ids after something they describe changed, or adds them to the batch of
+// ctx if it has one. The change was already made, so a sidecar failing to be rewritten is only logged.
+func (s Service) rewriteSidecars(ctx context.Context, file_ids []entities.FileID) {
+	if batch, ok := ctx.Value(sidecarBatchKey{}).(*sidecarBatch); ok {
+		batch.add(file_ids...)
+		return
+	}
+
+	for _, file_id := range uniqueFileIDs(file_ids) {
+		if err := s.WriteSidecar(ctx, file_id); err != nil {
+			log.Printf("sidecar of file %d is out of date until sidecars are backfilled, %v", file_id, err)
+		}
+	}
+}
+
+type sidecarBatchKey struct{}
+
+// sidecarBatch holds the files whose sidecars a run of changes touched, so that they're rewritten once after every
+// change is made rather than after each of them.
+type sidecarBatch struct {
+	s        Service
+	file_ids []entities.FileID
+}
+
+// batchSidecars returns a context under which the changes rewriteSidecars is told about are added to the returned
+// batch instead.
+func (s Service) batchSidecars(ctx context.Context) (context.Context, *sidecarBatch) {
+	batch := &sidecarBatch{s: s}
+	return context.WithValue(ctx, sidecarBatchKey{}, batch), batch
+}
+
+func (b *sidecarBatch) add(file_ids ...entities.FileID) {
+	b.file_ids = append(b.file_ids, file_ids...)
+}
+
+// write rewrites the sidecars of every file in the batch along with those of file_ids, stopping at the first that
+// fails to be.
+func (b *sidecarBatch) write(ctx context.Context, file_ids ...entities.FileID) error {
+	b.add(file_ids...)
+	for _, file_id := range uniqueFileIDs(b.file_ids) {
+		if err := b.s.WriteSidecar(ctx, file_id); err != nil {
+			return fmt.Errorf("file %d: %w", file_id, err)
+		}
+	}
+
+	return nil
+}
+
+// uniqueFileIDs returns file_ids sorted, without duplicates.
+func uniqueFileIDs(file_ids []entities.FileID) []entities.FileID {
+	file_ids = slices.Clone(file_ids)
+	slices.Sort(file_ids)
+	return slices.Compact(file_ids)
+}
+
+type SidecarResult struct {
+	FileID entities.FileID
+	Err    error
+}
+
+// BackfillSidecars writes the sidecar of every stored file, bringing those already written up to date. A sidecar failing
+// to be written doesn't stop the backfill; its error is reported in its SidecarResult instead.
+func (s Service) BackfillSidecars(ctx context.Context) (results []SidecarResult, err error) {
+	opts := entities.ListOptions{Limit: entities.MaxListLimit}
+	for {
+		file_ids, next_cursor, err := s.FileService.ListFiles(ctx, opts)
+		if err != nil {
+			return results, err
+		}
+
+		for _, file_id := range file_ids {
+			if err := ctx.Err(); err != nil {
+				return results, err
+			}
+
+			err := s.WriteSidecar(ctx, file_id)
+			results = append(results, SidecarResult{FileID: file_id, Err: err})
+		}
+
+		if next_cursor == "" {
+			return results, nil
+		}
+		opts.Cursor = next_cursor
+	}
+}
+
+// RebuildResult is what rebuilding the database recreated. WithoutSidecar is how many files were stored with nothing
+// but their hashes, as their sidecar was missing or didn't match them. Skipped are the paths within the storage root
+// which weren't stored, as they aren't where their contents would have been stored.
+type RebuildResult struct {
+	BundleImportResult
+	WithoutSidecar int
+	Skipped        []string
+}
+
+// Rebuild recreates the database out of the storage root, storing every file found in it and recreating the youtube
+// videos and projects described by their sidecars. It's meant to be run against a freshly created schema, after the
+// database has been lost. Files are given new ids.
+func (s Service) Rebuild(ctx context.Context) (result RebuildResult, err error) {
+	paths, err := s.FileService.GetStoredPaths(ctx)
+	if err != nil {
+		return result, err
+	}
+
+	result.FileIDs = make(map[string]entities.FileID, len(paths))
+	var sidecars []bundle.Sidecar
+	for _, path := range paths {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+
+		sidecar, ok, err := s.rebuildFile(ctx, path, &result)
+		if errors.Is(err, entities.ErrorHashMismatch) {
+			result.Skipped = append(result.Skipped, path)
+			continue
+		}
+		if err != nil {
+			return result, fmt.Errorf("%s: %w", path, err)
+		}
+
+		if !ok {
+			result.WithoutSidecar++
+			continue
+		}
+
+		sidecars = append(sidecars, sidecar)
+	}
+
+	manifest := bundle.MergeSidecars(sidecars, func(sha256 string) bool {
+		_, ok := result.FileIDs[sha256]
+		return ok
+	})
+
+	// sidecars are only rewritten once everything they described is back, so that a rebuild failing partway can be run
+	// again out of the same sidecars
+	batch_ctx, batch := s.batchSidecars(ctx)
+	err = s.importManifest(batch_ctx, manifest, &result.BundleImportResult)
+	if err != nil {
+		return result, err
+	}
+
+	return result, batch.write(ctx)
+}
+
+// rebuildFile stores the file at path, returning its sidecar if it has one matching it.
+func (s Service) rebuildFile(ctx context.Context, path string, result *RebuildResult) (sidecar bundle.Sidecar, ok bool, err error) {
+	file_id, err := s.FileService.AdoptFile(ctx, path)
+	if err != nil {
+		return bundle.Sidecar{}, false, err
+	}
+
+	file, err := s.FileService.GetFile(ctx, file_id)
+	if err != nil {
+		return bundle.Sidecar{}, false, err
+	}
+
+	result.FileIDs[file_helper.ByteToHexString(file.Hashes.SHA256)] = file_id
+	result.NewFiles++
+
+	contents, err := s.FileService.ReadSidecar(ctx, path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return bundle.Sidecar{}, false, nil
+	}
+	if err != nil {
+		return bundle.Sidecar{}, false, err
+	}
+
+	sidecar, err = bundle.ParseSidecar(contents)
+	if err != nil {
+		return bundle.Sidecar{}, false, nil
+	}
+
+	if !sidecarDescribes(sidecar, file) {
+		return bundle.Sidecar{}, false, nil
+	}
+
+	return sidecar, true, nil
+}
+
+// sidecarDescribes reports whether sidecar describes file, rather than a file stored at the same path before it.
+func sidecarDescribes(sidecar bundle.Sidecar, file entities.File) bool {
+	hashes, err := sidecar.File.Hashes()
+	return err == nil && bytes.Equal(hashes.SHA256, file.Hashes.SHA256) && bytes.Equal(hashes.SHA1, file.Hashes.SHA1) &&
+		bytes.Equal(hashes.MD5, file.Hashes.MD5) && sidecar.File.Extension == file.Extension
+}
@@ -0,0 +1,142 @@
+package service_test
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"io/fs"
+	"testing"
+	"time"
+
+	"github.com/dtbead/wc-maps-archive/internal/bundle"
+	"github.com/dtbead/wc-maps-archive/internal/entities"
+	mock_storage "github.com/dtbead/wc-maps-archive/internal/helper/testing/mock/storage"
+	mock_youtube "github.com/dtbead/wc-maps-archive/internal/helper/testing/mock/youtube"
+	"github.com/dtbead/wc-maps-archive/internal/service"
+	"github.com/dtbead/wc-maps-archive/internal/storage"
+	"github.com/google/go-cmp/cmp"
+	"go.uber.org/mock/gomock"
+)
+
+// newTestHashes returns hashes made up of b, so that files given different bytes have different contents.
+func newTestHashes(b byte) entities.Hashes {
+	return entities.Hashes{
+		MD5:    bytes.Repeat([]byte{b}, 16),
+		SHA1:   bytes.Repeat([]byte{b}, 20),
+		SHA256: bytes.Repeat([]byte{b}, 32),
+	}
+}
+
+func TestService_Rebuild(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	files := mock_storage.NewMockFileRepository(ctrl)
+	youtube := mock_storage.NewMockYoutubeRepository(ctrl)
+	projects := mock_storage.NewMockProjectRepository(ctrl)
+	audit := mock_storage.NewMockAuditRepository(ctrl)
+	ctx := context.Background()
+
+	yt := mock_youtube.NewYoutube()
+	yt.Thumbnail = []byte("not actually a jpeg")
+	youtube_id := yt.YouTube.YoutubeID
+
+	newSidecar := func(file entities.File, youtube *bundle.Youtube) []byte {
+		contents, err := json.Marshal(bundle.Sidecar{Version: bundle.SidecarVersion, Written: time.Now(), File: bundle.NewFile(file, nil), Youtube: youtube})
+		if err != nil {
+			t.Fatalf("failed to marshal sidecar, %v", err)
+		}
+		return contents
+	}
+
+	downloaded := entities.File{Extension: "mp4", Size: 25, Hashes: newTestHashes(1)}
+	described := bundle.NewYoutube(yt, []string{bundle.NewFile(downloaded, nil).SHA256})
+
+	// every path is adopted as a file of its own, but only the first has a sidecar describing it
+	stored := []struct {
+		path    string
+		file    entities.File
+		sidecar []byte
+		err     error
+	}{
+		{path: "01/downloaded.mp4", file: downloaded, sidecar: newSidecar(downloaded, &described)},
+		{path: "02/without-sidecar.mp4", file: entities.File{Extension: "mp4", Hashes: newTestHashes(2)}, err: fs.ErrNotExist},
+		// a file stored at the same path before this one left its sidecar behind
+		{path: "03/replaced.mp4", file: entities.File{Extension: "mp4", Hashes: newTestHashes(3)}, sidecar: newSidecar(entities.File{Extension: "mp4", Hashes: newTestHashes(4)}, &described)},
+		{path: "04/other-extension.mkv", file: entities.File{Extension: "mkv", Hashes: newTestHashes(5)}, sidecar: newSidecar(entities.File{Extension: "mp4", Hashes: newTestHashes(5)}, nil)},
+		{path: "05/invalid-sidecar.mp4", file: entities.File{Extension: "mp4", Hashes: newTestHashes(6)}, sidecar: []byte("{")},
+	}
+
+	var paths []string
+	for i, f := range stored {
+		file_id := entities.FileID(i + 1)
+		file := f.file
+		paths = append(paths, f.path)
+		files.EXPECT().AdoptFile(gomock.Any(), f.path).Return(file_id, nil)
+		files.EXPECT().GetFile(gomock.Any(), file_id).Return(&file, nil).AnyTimes()
+		files.EXPECT().ReadSidecar(gomock.Any(), f.path).Return(f.sidecar, f.err)
+	}
+
+	// whatever isn't where its contents would have been stored is skipped
+	paths = append(paths, "06/moved.mp4")
+	files.EXPECT().AdoptFile(gomock.Any(), "06/moved.mp4").Return(entities.InvalidFileID, entities.ErrorHashMismatch)
+	files.EXPECT().GetStoredPaths(gomock.Any()).Return(paths, nil)
+
+	// the youtube video of the sidecar is stored along with the file it describes and its thumbnail, and nothing else
+	archived := false
+	youtube.EXPECT().GetYoutubeVideo(gomock.Any(), youtube_id).DoAndReturn(func(context.Context, entities.YoutubeVideoID) (*entities.YoutubeVideo, error) {
+		if !archived {
+			return nil, sql.ErrNoRows
+		}
+		return &yt.YouTube, nil
+	}).AnyTimes()
+	youtube.EXPECT().NewYoutube(gomock.Any(), entities.FileID(1), gomock.Any()).DoAndReturn(func(ctx context.Context, file_id entities.FileID, youtube *entities.Youtube) error {
+		if !bytes.Equal(youtube.Thumbnail, yt.Thumbnail) {
+			t.Errorf("youtube video stored with thumbnail %q, want %q", youtube.Thumbnail, yt.Thumbnail)
+		}
+		archived = true
+		return nil
+	})
+	youtube.EXPECT().GetFileYoutubeID(gomock.Any(), entities.FileID(1)).Return(youtube_id, nil).AnyTimes()
+	youtube.EXPECT().GetYoutubeFileIDs(gomock.Any(), youtube_id).Return([]entities.FileID{1}, nil).AnyTimes()
+	youtube.EXPECT().GetYoutube(gomock.Any(), youtube_id).Return(&yt, nil).AnyTimes()
+	youtube.EXPECT().GetYtdlpInfo(gomock.Any(), entities.FileID(1)).Return(nil, sql.ErrNoRows).AnyTimes()
+	youtube.EXPECT().GetThumbnail(gomock.Any(), entities.FileID(1)).Return(yt.Thumbnail, nil).AnyTimes()
+	files.EXPECT().GetFileVideo(gomock.Any(), entities.FileID(1)).Return(nil, sql.ErrNoRows).AnyTimes()
+	projects.EXPECT().GetFileProjects(gomock.Any(), entities.FileID(1)).Return(nil, nil).AnyTimes()
+	audit.EXPECT().NewAuditEntry(gomock.Any(), gomock.Any()).Return(int64(1), nil).AnyTimes()
+
+	// its sidecar is rewritten once the rebuild is done, by which point the youtube video is back
+	var rewritten bundle.Sidecar
+	files.EXPECT().WriteSidecar(gomock.Any(), entities.FileID(1), gomock.Any()).DoAndReturn(func(ctx context.Context, file_id entities.FileID, sidecar []byte) error {
+		if !archived {
+			t.Errorf("sidecar of file %d rewritten before its youtube video was stored", file_id)
+		}
+		return json.Unmarshal(sidecar, &rewritten)
+	})
+
+	s := service.NewService(&storage.Repository{File: files, Youtube: youtube, Probe: mock_storage.NewMockProbeRepository(ctrl), Project: projects, Audit: audit})
+	result, err := s.Rebuild(ctx)
+	if err != nil {
+		t.Fatalf("Service.Rebuild() error = %v", err)
+	}
+
+	got := struct {
+		NewFiles, NewYoutube, WithoutSidecar int
+		Skipped                              []string
+	}{result.NewFiles, result.NewYoutube, result.WithoutSidecar, result.Skipped}
+	want := struct {
+		NewFiles, NewYoutube, WithoutSidecar int
+		Skipped                              []string
+	}{NewFiles: 5, NewYoutube: 1, WithoutSidecar: 4, Skipped: []string{"06/moved.mp4"}}
+	if !cmp.Equal(got, want) {
+		t.Errorf("Service.Rebuild() got diff %s", cmp.Diff(got, want))
+	}
+
+	if len(result.FileIDs) != len(stored) {
+		t.Errorf("Service.Rebuild() FileIDs = %v, want %d of them", result.FileIDs, len(stored))
+	}
+
+	if rewritten.Youtube == nil || rewritten.Youtube.ID != string(youtube_id) || !bytes.Equal(rewritten.Youtube.Thumbnail, yt.Thumbnail) {
+		t.Errorf("rewritten sidecar youtube = %+v, want %s with its thumbnail", rewritten.Youtube, youtube_id)
+	}
+}
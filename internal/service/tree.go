@@ -0,0 +1,399 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/dtbead/wc-maps-archive/internal/entities"
+	file_helper "github.com/dtbead/wc-maps-archive/internal/helper/file"
+	"github.com/dtbead/wc-maps-archive/internal/nfo"
+	"github.com/dtbead/wc-maps-archive/internal/tree"
+)
+
+// TreeEntry is a single file of the tree laid out by TreeEntries. Path is where it goes, separated by slashes, and
+// ModTime is when the youtube video it's a download of was uploaded, or when its project was archived.
+type TreeEntry struct {
+	Path    string
+	FileID  entities.FileID
+	File    entities.File
+	ModTime time.Time
+}
+
+// TreeEntries lays the archive out as a tree of human-readable names. Every project goes in
+// "Projects/<type>/<title> [uuid]/", holding its own files as "<title> [youtube id].<ext>" and the files of its parts
+// as "<part> - <participant> [youtube id].<ext>", and every youtube video goes in
+// "Youtube/<uploader> [channel id]/<upload date> <title> [youtube id].<ext>". A file which isn't a download of a youtube
+// video is named after its file id instead.
+func (s Service) TreeEntries(ctx context.Context) (entries []TreeEntry, err error) {
+	t := treeExport{s: s, ctx: ctx, files: make(map[entities.FileID]entities.File), paths: make(map[string]bool)}
+
+	err = t.projects()
+	if err != nil {
+		return nil, err
+	}
+
+	err = t.youtube()
+	if err != nil {
+		return nil, err
+	}
+
+	return t.entries, nil
+}
+
+// posterSuffix is what the poster of an entry is named by in place of its extension, as Kodi and Jellyfin look for it.
+const posterSuffix = "-poster.jpg"
+
+// TreeOptions is how ExportTree exports the tree. Methods are tried in order to link every entry, and NFO writes an
+// .nfo file next to every entry, which Kodi and Jellyfin read its title, description and such out of, along with a
+// "-poster.jpg" of the thumbnail of every download of a youtube video which came with one.
+type TreeOptions struct {
+	Methods []tree.Method
+	NFO     bool
+}
+
+// ExportTree syncs the tree at directory with the archive as laid out by TreeEntries. Running it again only changes
+// what changed in the archive since.
+func (s Service) ExportTree(ctx context.Context, directory string, opts TreeOptions) (result tree.SyncResult, err error) {
+	entries, err := s.TreeEntries(ctx)
+	if err != nil {
+		return result, err
+	}
+
+	synced := make([]tree.Entry, 0, len(entries))
+	paths := make(map[string]bool, len(entries))
+	for _, e := range entries {
+		synced = append(synced, tree.Entry{
+			Path:   e.Path,
+			Source: e.File.PathAbsolute,
+			SHA256: file_helper.ByteToHexString(e.File.Hashes.SHA256),
+		})
+		paths[strings.ToLower(e.Path)] = true
+	}
+
+	if opts.NFO {
+		movies := make(map[entities.FileID][]byte)
+		posters := make(map[entities.FileID][]byte)
+		for _, e := range entries {
+			if err := ctx.Err(); err != nil {
+				return result, err
+			}
+
+			// an entry which happens to be named like the .nfo file or poster of another keeps its name
+			name := strings.TrimSuffix(e.Path, path.Ext(e.Path))
+			if p := name + nfo.Extension; !paths[strings.ToLower(p)] {
+				paths[strings.ToLower(p)] = true
+
+				contents, ok := movies[e.FileID]
+				if !ok {
+					movie, err := s.treeMovie(ctx, e)
+					if err != nil {
+						return result, fmt.Errorf("file %d: %w", e.FileID, err)
+					}
+
+					contents, err = movie.Marshal()
+					if err != nil {
+						return result, fmt.Errorf("file %d: %w", e.FileID, err)
+					}
+					movies[e.FileID] = contents
+				}
+
+				sum := sha256.Sum256(contents)
+				synced = append(synced, tree.Entry{Path: p, Contents: contents, SHA256: hex.EncodeToString(sum[:])})
+			}
+
+			if p := name + posterSuffix; !paths[strings.ToLower(p)] {
+				contents, ok := posters[e.FileID]
+				if !ok {
+					contents, err = s.YoutubeService.GetThumbnail(ctx, e.FileID)
+					if err != nil && !errors.Is(err, sql.ErrNoRows) {
+						return result, fmt.Errorf("file %d: %w", e.FileID, err)
+					}
+					posters[e.FileID] = contents
+				}
+
+				// only downloads of youtube videos which came with a thumbnail have a poster
+				if len(contents) == 0 {
+					continue
+				}
+				paths[strings.ToLower(p)] = true
+
+				sum := sha256.Sum256(contents)
+				synced = append(synced, tree.Entry{Path: p, Contents: contents, SHA256: hex.EncodeToString(sum[:])})
+			}
+		}
+	}
+
+	return tree.Sync(ctx, directory, synced, opts.Methods)
+}
+
+// treeMovie describes the file of e for a media center. A download of a youtube video is described by the video, and
+// any other file by the first project it belongs to, its genres being the types of every project it belongs to.
+func (s Service) treeMovie(ctx context.Context, e TreeEntry) (movie nfo.Movie, err error) {
+	uuids, err := s.ProjectService.GetFileProjects(ctx, e.FileID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return movie, err
+	}
+
+	var projects []entities.Project
+	for _, uuid := range uuids {
+		p, err := s.ProjectService.GetProject(ctx, uuid)
+		if err != nil {
+			return movie, fmt.Errorf("project %s: %w", uuid, err)
+		}
+		projects = append(projects, p)
+
+		genre := p.ProjectType.ToString()
+		if !slices.Contains(movie.Genres, genre) {
+			movie.Genres = append(movie.Genres, genre)
+		}
+	}
+
+	youtube_id, err := s.YoutubeService.GetFileYoutube(ctx, e.FileID)
+	switch {
+	case err == nil:
+		yt, err := s.YoutubeService.GetYoutube(ctx, youtube_id)
+		if err != nil {
+			return movie, fmt.Errorf("youtube %s: %w", youtube_id, err)
+		}
+
+		movie.Title, movie.Plot = yt.Title, yt.Description
+		movie.Premiered = yt.YouTube.UploadDate
+		movie.Runtime = (yt.YouTube.Duration + 59) / 60
+		if yt.Channel != nil {
+			movie.Studio = yt.Channel.Uploader
+		}
+		movie.UniqueIDs = []nfo.UniqueID{{Type: "youtube", ID: string(youtube_id), Default: true}}
+	case !errors.Is(err, sql.ErrNoRows):
+		return movie, err
+	case len(projects) > 0:
+		movie.Title, movie.Plot = projects[0].Title, projects[0].Description
+		if projects[0].DateCompleted.Precision != entities.DatePrecisionNone {
+			movie.Premiered = projects[0].DateCompleted.Time
+		}
+	}
+
+	if movie.Title == "" {
+		movie.Title = strings.TrimSuffix(path.Base(e.Path), path.Ext(e.Path))
+	}
+
+	return movie, nil
+}
+
+// treeExport gathers the entries of a tree.
+type treeExport struct {
+	s       Service
+	ctx     context.Context
+	entries []TreeEntry
+	files   map[entities.FileID]entities.File
+	// paths holds the lowercased path of every entry, as some filesystems don't tell cases apart
+	paths map[string]bool
+}
+
+// add adds file_id to the tree as name, along with its extension, within directory. A name already taken gets the file
+// id added to it.
+func (t *treeExport) add(directory, name string, file_id entities.FileID, mod_time time.Time) error {
+	file, ok := t.files[file_id]
+	if !ok {
+		var err error
+		file, err = t.s.FileService.GetFile(t.ctx, file_id)
+		if err != nil {
+			return fmt.Errorf("file %d: %w", file_id, err)
+		}
+		t.files[file_id] = file
+	}
+
+	p := path.Join(directory, fmt.Sprintf("%s.%s", name, file.Extension))
+	if t.paths[strings.ToLower(p)] {
+		p = path.Join(directory, fmt.Sprintf("%s (file %d).%s", name, file_id, file.Extension))
+	}
+	t.paths[strings.ToLower(p)] = true
+
+	t.entries = append(t.entries, TreeEntry{Path: p, FileID: file_id, File: file, ModTime: mod_time})
+
+	return nil
+}
+
+// label is what a file is told apart by within a name, being the youtube video it's a download of if there's one.
+func (t *treeExport) label(file_id entities.FileID) (string, error) {
+	youtube_id, err := t.s.YoutubeService.GetFileYoutube(t.ctx, file_id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Sprintf("file %d", file_id), nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return string(youtube_id), nil
+}
+
+func (t *treeExport) projects() error {
+	opts := entities.ListOptions{Limit: entities.MaxListLimit}
+	for {
+		uuids, next_cursor, err := t.s.ProjectService.ListProjects(t.ctx, "", opts)
+		if err != nil {
+			return err
+		}
+
+		for _, uuid := range uuids {
+			if err := t.ctx.Err(); err != nil {
+				return err
+			}
+
+			err := t.project(uuid)
+			if err != nil {
+				return fmt.Errorf("project %s: %w", uuid, err)
+			}
+		}
+
+		if next_cursor == "" {
+			return nil
+		}
+		opts.Cursor = next_cursor
+	}
+}
+
+func (t *treeExport) project(uuid entities.ProjectUUID) error {
+	p, err := t.s.ProjectService.GetProject(t.ctx, uuid)
+	if err != nil {
+		return err
+	}
+
+	title := tree.Name(p.Title)
+	directory := path.Join("Projects", tree.Name(p.ProjectType.ToString()), fmt.Sprintf("%s [%s]", title, tree.Name(string(uuid))))
+
+	for _, file_id := range p.FileIDs {
+		label, err := t.label(file_id)
+		if err != nil {
+			return err
+		}
+
+		err = t.add(directory, fmt.Sprintf("%s [%s]", title, label), file_id, p.DateArchived)
+		if err != nil {
+			return err
+		}
+	}
+
+	parts, err := t.s.ProjectService.GetParts(t.ctx, uuid)
+	if err != nil {
+		return err
+	}
+
+	for _, part := range parts {
+		err := t.part(directory, part, p.DateArchived)
+		if err != nil {
+			return fmt.Errorf("part %d: %w", part.Number, err)
+		}
+	}
+
+	return nil
+}
+
+// part adds the file of part, or the first file of its youtube video if it has none of its own.
+func (t *treeExport) part(directory string, part entities.ProjectPart, mod_time time.Time) error {
+	file_id := part.FileID
+	if !file_id.IsValid() && part.YoutubeID != "" {
+		file_ids, err := t.s.YoutubeService.GetYoutubeFileIDs(t.ctx, part.YoutubeID)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return err
+		}
+
+		if len(file_ids) > 0 {
+			file_id = file_ids[0]
+		}
+	}
+
+	if !file_id.IsValid() {
+		return nil
+	}
+
+	// a part's participant is named after its artist, or after its uploader when it has no name of its own
+	participant := part.ParticipantArtist
+	if participant == "" {
+		participant = part.ParticipantName
+	}
+	if participant == "" && part.YoutubeID != "" {
+		yt, err := t.s.YoutubeService.GetYoutube(t.ctx, part.YoutubeID)
+		if err != nil {
+			return err
+		}
+
+		if yt.Channel != nil {
+			participant = yt.Channel.Uploader
+		}
+	}
+	if participant == "" && part.Participant != "" {
+		participant = string(part.Participant)
+	}
+	if participant == "" {
+		participant = "unknown"
+	}
+
+	label, err := t.label(file_id)
+	if err != nil {
+		return err
+	}
+
+	return t.add(directory, fmt.Sprintf("%02d - %s [%s]", part.Number, tree.Name(participant), label), file_id, mod_time)
+}
+
+func (t *treeExport) youtube() error {
+	opts := entities.ListOptions{Limit: entities.MaxListLimit}
+	for {
+		youtube_ids, next_cursor, err := t.s.YoutubeService.ListYoutube(t.ctx, "", opts)
+		if err != nil {
+			return err
+		}
+
+		for _, youtube_id := range youtube_ids {
+			if err := t.ctx.Err(); err != nil {
+				return err
+			}
+
+			err := t.video(youtube_id)
+			if err != nil {
+				return fmt.Errorf("youtube %s: %w", youtube_id, err)
+			}
+		}
+
+		if next_cursor == "" {
+			return nil
+		}
+		opts.Cursor = next_cursor
+	}
+}
+
+func (t *treeExport) video(youtube_id entities.YoutubeVideoID) error {
+	yt, err := t.s.YoutubeService.GetYoutube(t.ctx, youtube_id)
+	if err != nil {
+		return err
+	}
+
+	channel := "unknown channel"
+	if yt.Channel != nil {
+		channel = fmt.Sprintf("%s [%s]", tree.Name(yt.Channel.Uploader), tree.Name(string(yt.Channel.ChannelID)))
+	}
+	directory := path.Join("Youtube", channel)
+
+	file_ids, err := t.s.YoutubeService.GetYoutubeFileIDs(t.ctx, youtube_id)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+
+	name := fmt.Sprintf("%s %s [%s]", yt.YouTube.UploadDate.Format("2006-01-02"), tree.Name(yt.Title), youtube_id)
+	for _, file_id := range file_ids {
+		err := t.add(directory, name, file_id, yt.YouTube.UploadDate)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
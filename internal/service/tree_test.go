@@ -0,0 +1,370 @@
+package service_test
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/dtbead/wc-maps-archive/internal/entities"
+	"github.com/dtbead/wc-maps-archive/internal/filter"
+	mock_storage "github.com/dtbead/wc-maps-archive/internal/helper/testing/mock/storage"
+	"github.com/dtbead/wc-maps-archive/internal/service"
+	"github.com/dtbead/wc-maps-archive/internal/storage"
+	"github.com/dtbead/wc-maps-archive/internal/tree"
+	"github.com/google/go-cmp/cmp"
+	"go.uber.org/mock/gomock"
+)
+
+// treeArchive is an archive held in memory, which the repositories of newTreeService answer out of. It can be changed
+// between calls, like an archive changing between exports.
+type treeArchive struct {
+	files    map[entities.FileID]entities.File
+	projects []entities.Project
+	parts    map[entities.ProjectUUID][]entities.ProjectPart
+	youtube  []entities.Youtube
+	// downloads are the files of every youtube video, and thumbnails the thumbnail kept of a download, if there's one
+	downloads  map[entities.YoutubeVideoID][]entities.FileID
+	thumbnails map[entities.FileID][]byte
+	// thumbnailErr is what getting any thumbnail fails with, if it does
+	thumbnailErr error
+}
+
+func newTreeArchive() *treeArchive {
+	return &treeArchive{
+		files:      make(map[entities.FileID]entities.File),
+		parts:      make(map[entities.ProjectUUID][]entities.ProjectPart),
+		downloads:  make(map[entities.YoutubeVideoID][]entities.FileID),
+		thumbnails: make(map[entities.FileID][]byte),
+	}
+}
+
+// addFile stores contents as file_id within the storage root at root.
+func (a *treeArchive) addFile(t *testing.T, root string, file_id entities.FileID, extension string, contents string) {
+	t.Helper()
+
+	p := filepath.Join(root, fmt.Sprintf("%d.%s", file_id, extension))
+	if err := os.WriteFile(p, []byte(contents), 0664); err != nil {
+		t.Fatalf("failed to write test file, %v", err)
+	}
+
+	a.files[file_id] = entities.File{PathAbsolute: p, Extension: extension, Hashes: newTestHashes(byte(file_id))}
+}
+
+func (a *treeArchive) youtubeID(file_id entities.FileID) (entities.YoutubeVideoID, bool) {
+	for youtube_id, file_ids := range a.downloads {
+		if slices.Contains(file_ids, file_id) {
+			return youtube_id, true
+		}
+	}
+
+	return "", false
+}
+
+// newTreeService returns a Service whose repositories answer out of a, as far as laying out and exporting a tree goes.
+func newTreeService(t *testing.T, a *treeArchive) *service.Service {
+	ctrl := gomock.NewController(t)
+	files := mock_storage.NewMockFileRepository(ctrl)
+	youtube := mock_storage.NewMockYoutubeRepository(ctrl)
+	projects := mock_storage.NewMockProjectRepository(ctrl)
+
+	files.EXPECT().GetFile(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, file_id entities.FileID) (*entities.File, error) {
+		file, ok := a.files[file_id]
+		if !ok {
+			return nil, sql.ErrNoRows
+		}
+		return &file, nil
+	}).AnyTimes()
+
+	projects.EXPECT().ListProjects(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, f filter.Filter, opts entities.ListOptions) ([]entities.ProjectUUID, string, error) {
+		var uuids []entities.ProjectUUID
+		for _, p := range a.projects {
+			uuids = append(uuids, entities.ProjectUUID(p.UUID))
+		}
+		return uuids, "", nil
+	}).AnyTimes()
+	projects.EXPECT().GetProject(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, uuid entities.ProjectUUID) (*entities.Project, error) {
+		i := slices.IndexFunc(a.projects, func(p entities.Project) bool { return p.UUID == string(uuid) })
+		if i < 0 {
+			return nil, sql.ErrNoRows
+		}
+		p := a.projects[i]
+		return &p, nil
+	}).AnyTimes()
+	projects.EXPECT().GetProjectParts(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, uuid entities.ProjectUUID) ([]entities.ProjectPart, error) {
+		return a.parts[uuid], nil
+	}).AnyTimes()
+	projects.EXPECT().GetFileProjects(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, file_id entities.FileID) ([]entities.ProjectUUID, error) {
+		var uuids []entities.ProjectUUID
+		for _, p := range a.projects {
+			uuid := entities.ProjectUUID(p.UUID)
+			if slices.Contains(p.FileIDs, file_id) || slices.ContainsFunc(a.parts[uuid], func(part entities.ProjectPart) bool { return part.FileID == file_id }) {
+				uuids = append(uuids, uuid)
+			}
+		}
+		return uuids, nil
+	}).AnyTimes()
+
+	youtube.EXPECT().ListYoutube(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, f filter.Filter, opts entities.ListOptions) ([]entities.YoutubeVideoID, string, error) {
+		var youtube_ids []entities.YoutubeVideoID
+		for _, yt := range a.youtube {
+			youtube_ids = append(youtube_ids, yt.YouTube.YoutubeID)
+		}
+		return youtube_ids, "", nil
+	}).AnyTimes()
+	youtube.EXPECT().GetYoutube(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, youtube_id entities.YoutubeVideoID) (*entities.Youtube, error) {
+		i := slices.IndexFunc(a.youtube, func(yt entities.Youtube) bool { return yt.YouTube.YoutubeID == youtube_id })
+		if i < 0 {
+			return nil, sql.ErrNoRows
+		}
+		yt := a.youtube[i]
+		return &yt, nil
+	}).AnyTimes()
+	youtube.EXPECT().GetYoutubeFileIDs(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, youtube_id entities.YoutubeVideoID) ([]entities.FileID, error) {
+		return a.downloads[youtube_id], nil
+	}).AnyTimes()
+	youtube.EXPECT().GetFileYoutubeID(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, file_id entities.FileID) (entities.YoutubeVideoID, error) {
+		youtube_id, ok := a.youtubeID(file_id)
+		if !ok {
+			return "", sql.ErrNoRows
+		}
+		return youtube_id, nil
+	}).AnyTimes()
+	youtube.EXPECT().GetThumbnail(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, file_id entities.FileID) ([]byte, error) {
+		if a.thumbnailErr != nil {
+			return nil, a.thumbnailErr
+		}
+		thumbnail, ok := a.thumbnails[file_id]
+		if !ok {
+			return nil, sql.ErrNoRows
+		}
+		return thumbnail, nil
+	}).AnyTimes()
+
+	return service.NewService(&storage.Repository{
+		File:    files,
+		Youtube: youtube,
+		Probe:   mock_storage.NewMockProbeRepository(ctrl),
+		Project: projects,
+		Audit:   mock_storage.NewMockAuditRepository(ctrl),
+	})
+}
+
+// newTreeYoutube returns a youtube video uploaded by Rusty on the first of january 2024.
+func newTreeYoutube(youtube_id entities.YoutubeVideoID, title string) entities.Youtube {
+	return entities.Youtube{
+		YouTube: entities.YoutubeVideo{YoutubeID: youtube_id, UploadDate: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), Duration: 212},
+		Channel: &entities.VideoYoutubeChannel{ChannelID: "UCKhKck7AoDI-H8PktMnZi0Q", Uploader: "Rusty"},
+		Title:   title,
+	}
+}
+
+func TestService_ExportTree_Posters(t *testing.T) {
+	const directory = "Youtube/Rusty [UCKhKck7AoDI-H8PktMnZi0Q]"
+
+	a := newTreeArchive()
+	root := t.TempDir()
+	a.addFile(t, root, 1, "mp4", "first download")
+	a.addFile(t, root, 2, "webm", "second download")
+	a.addFile(t, root, 3, "mp4", "download without a thumbnail")
+
+	a.youtube = []entities.Youtube{newTreeYoutube("dQw4w9WgXcQ", "Firestar MAP"), newTreeYoutube("9bZkp7q19f0", "Leafstar MAP")}
+	a.downloads["dQw4w9WgXcQ"] = []entities.FileID{1, 2}
+	a.downloads["9bZkp7q19f0"] = []entities.FileID{3}
+
+	// both downloads of the first video are named alike, so they share a poster, being the thumbnail of the only one of
+	// them which has one
+	a.thumbnails[2] = []byte("thumbnail of the second download")
+
+	s := newTreeService(t, a)
+	ctx := context.Background()
+	output := t.TempDir()
+
+	_, err := s.ExportTree(ctx, output, service.TreeOptions{Methods: []tree.Method{tree.MethodCopy}, NFO: true})
+	if err != nil {
+		t.Fatalf("Service.ExportTree() error = %v", err)
+	}
+
+	tests := []struct {
+		path string
+		// want is what the file holds, or nil if there shouldn't be one
+		want []byte
+	}{
+		{"2024-01-01 Firestar MAP [dQw4w9WgXcQ].mp4", []byte("first download")},
+		{"2024-01-01 Firestar MAP [dQw4w9WgXcQ].webm", []byte("second download")},
+		{"2024-01-01 Firestar MAP [dQw4w9WgXcQ]-poster.jpg", a.thumbnails[2]},
+		{"2024-01-01 Leafstar MAP [9bZkp7q19f0].mp4", []byte("download without a thumbnail")},
+		{"2024-01-01 Leafstar MAP [9bZkp7q19f0]-poster.jpg", nil},
+	}
+	for _, tt := range tests {
+		got, err := os.ReadFile(filepath.Join(output, filepath.FromSlash(directory), tt.path))
+		if tt.want == nil {
+			if !errors.Is(err, os.ErrNotExist) {
+				t.Errorf("%s exists, error = %v, want it not to", tt.path, err)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("failed to read %s, %v", tt.path, err)
+			continue
+		}
+
+		if !bytes.Equal(got, tt.want) {
+			t.Errorf("%s = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+
+	// a thumbnail failing to be got fails the export, rather than leaving the poster out
+	a.thumbnailErr = errors.New("connection reset by peer")
+	_, err = s.ExportTree(ctx, t.TempDir(), service.TreeOptions{Methods: []tree.Method{tree.MethodCopy}, NFO: true})
+	if !errors.Is(err, a.thumbnailErr) {
+		t.Errorf("Service.ExportTree() error = %v, want %v", err, a.thumbnailErr)
+	}
+}
+
+// newTreeProjectArchive returns an archive with a multi-animation, its parts and the youtube videos they're uploads of,
+// the files of which are stored within root.
+func newTreeProjectArchive(t *testing.T, root string) *treeArchive {
+	t.Helper()
+
+	a := newTreeArchive()
+	for file_id := entities.FileID(1); file_id <= 6; file_id++ {
+		a.addFile(t, root, file_id, "mp4", fmt.Sprintf("contents of file %d", file_id))
+	}
+
+	a.youtube = []entities.Youtube{newTreeYoutube("dQw4w9WgXcQ", "Firestar MAP"), newTreeYoutube("9bZkp7q19f0", "Leafstar MAP")}
+	// both files of the first video are named alike, so the second is told apart by its file id
+	a.downloads["dQw4w9WgXcQ"] = []entities.FileID{1, 5}
+	a.downloads["9bZkp7q19f0"] = []entities.FileID{3}
+
+	uuid := entities.ProjectUUID("0190f5d2-4b6c-7a3e-9d1f-2c8b5e7a4f60")
+	a.projects = []entities.Project{{
+		UUID:         string(uuid),
+		Title:        "Firestar MAP",
+		FileIDs:      []entities.FileID{1, 5},
+		ProjectType:  entities.ProjectMultiAnimation,
+		DateArchived: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC),
+	}}
+	a.parts[uuid] = []entities.ProjectPart{
+		{ProjectUUID: uuid, Number: 1, ParticipantArtist: "Rusty", ParticipantName: "rusty_cat", FileID: 2},
+		// a part without a file of its own is the first download of its youtube video, named after its uploader
+		{ProjectUUID: uuid, Number: 2, FileID: entities.InvalidFileID, YoutubeID: "9bZkp7q19f0"},
+		{ProjectUUID: uuid, Number: 3, Participant: "UCKhKck7AoDI-H8PktMnZi0Q", FileID: 4},
+		// a part with nothing uploaded yet isn't in the tree
+		{ProjectUUID: uuid, Number: 4, ParticipantName: "Leafpool", FileID: entities.InvalidFileID},
+	}
+
+	return a
+}
+
+func TestService_TreeEntries(t *testing.T) {
+	const (
+		project = "Projects/multi-animation/Firestar MAP [0190f5d2-4b6c-7a3e-9d1f-2c8b5e7a4f60]"
+		channel = "Youtube/Rusty [UCKhKck7AoDI-H8PktMnZi0Q]"
+	)
+
+	a := newTreeProjectArchive(t, t.TempDir())
+	s := newTreeService(t, a)
+
+	entries, err := s.TreeEntries(context.Background())
+	if err != nil {
+		t.Fatalf("Service.TreeEntries() error = %v", err)
+	}
+
+	type entry struct {
+		Path    string
+		FileID  entities.FileID
+		ModTime time.Time
+	}
+	got := make([]entry, len(entries))
+	for i, e := range entries {
+		got[i] = entry{e.Path, e.FileID, e.ModTime}
+		if e.File.PathAbsolute != a.files[e.FileID].PathAbsolute {
+			t.Errorf("entry %s is of %s, want %s", e.Path, e.File.PathAbsolute, a.files[e.FileID].PathAbsolute)
+		}
+	}
+
+	archived := a.projects[0].DateArchived
+	uploaded := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	want := []entry{
+		{project + "/Firestar MAP [dQw4w9WgXcQ].mp4", 1, archived},
+		{project + "/Firestar MAP [dQw4w9WgXcQ] (file 5).mp4", 5, archived},
+		{project + "/01 - Rusty [file 2].mp4", 2, archived},
+		{project + "/02 - Rusty [9bZkp7q19f0].mp4", 3, archived},
+		{project + "/03 - UCKhKck7AoDI-H8PktMnZi0Q [file 4].mp4", 4, archived},
+		{channel + "/2024-01-01 Firestar MAP [dQw4w9WgXcQ].mp4", 1, uploaded},
+		{channel + "/2024-01-01 Firestar MAP [dQw4w9WgXcQ] (file 5).mp4", 5, uploaded},
+		{channel + "/2024-01-01 Leafstar MAP [9bZkp7q19f0].mp4", 3, uploaded},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Service.TreeEntries() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestService_ExportTree_Sync(t *testing.T) {
+	const project = "Projects/multi-animation/Firestar MAP [0190f5d2-4b6c-7a3e-9d1f-2c8b5e7a4f60]"
+
+	root := t.TempDir()
+	a := newTreeProjectArchive(t, root)
+	s := newTreeService(t, a)
+	ctx := context.Background()
+	output := t.TempDir()
+	opts := service.TreeOptions{Methods: []tree.Method{tree.MethodCopy}}
+
+	type counts struct {
+		Added, Replaced, Removed, Unchanged int
+	}
+	export := func() counts {
+		t.Helper()
+
+		result, err := s.ExportTree(ctx, output, opts)
+		if err != nil {
+			t.Fatalf("Service.ExportTree() error = %v", err)
+		}
+		if len(result.Conflicts) != 0 {
+			t.Errorf("Service.ExportTree() conflicts = %v, want none", result.Conflicts)
+		}
+
+		return counts{result.Added, result.Replaced, result.Removed, result.Unchanged}
+	}
+
+	if diff := cmp.Diff(counts{Added: 8}, export()); diff != "" {
+		t.Errorf("Service.ExportTree() of a new tree mismatch (-want +got):\n%s", diff)
+	}
+
+	got, err := os.ReadFile(filepath.Join(output, filepath.FromSlash(project+"/01 - Rusty [file 2].mp4")))
+	if err != nil || string(got) != "contents of file 2" {
+		t.Errorf("exported part = %q, error = %v, want %q", got, err, "contents of file 2")
+	}
+
+	if diff := cmp.Diff(counts{Unchanged: 8}, export()); diff != "" {
+		t.Errorf("Service.ExportTree() of an unchanged archive mismatch (-want +got):\n%s", diff)
+	}
+
+	// the third part is removed, and a download of another youtube video stored
+	uuid := entities.ProjectUUID(a.projects[0].UUID)
+	a.parts[uuid] = slices.DeleteFunc(a.parts[uuid], func(part entities.ProjectPart) bool { return part.Number == 3 })
+	a.youtube = append(a.youtube, newTreeYoutube("kJQP7kiw5Fk", "Ravenpaw MAP"))
+	a.downloads["kJQP7kiw5Fk"] = []entities.FileID{6}
+
+	if diff := cmp.Diff(counts{Added: 1, Removed: 1, Unchanged: 7}, export()); diff != "" {
+		t.Errorf("Service.ExportTree() of a changed archive mismatch (-want +got):\n%s", diff)
+	}
+
+	removed := filepath.Join(output, filepath.FromSlash(project+"/03 - UCKhKck7AoDI-H8PktMnZi0Q [file 4].mp4"))
+	if _, err := os.Stat(removed); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("removed part is still in the tree, error = %v", err)
+	}
+
+	added := filepath.Join(output, filepath.FromSlash("Youtube/Rusty [UCKhKck7AoDI-H8PktMnZi0Q]/2024-01-01 Ravenpaw MAP [kJQP7kiw5Fk].mp4"))
+	if got, err := os.ReadFile(added); err != nil || string(got) != "contents of file 6" {
+		t.Errorf("added download = %q, error = %v, want %q", got, err, "contents of file 6")
+	}
+}
@@ -0,0 +1,36 @@
+package youtube
+
+import (
+	"context"
+
+	"github.com/dtbead/wc-maps-archive/internal/entities"
+	"github.com/dtbead/wc-maps-archive/internal/filter"
+)
+
+// ListYoutube returns a page of youtube videos matching query, written in the filter query language, along with the
+// cursor of the next page, which is empty on the last one.
+//
+// Videos can be filtered by title (or bare words), description, channel, uploader, uploaded, duration, views, likes,
+// width, height and project, as well as is:live and restricted, and has:file, description, project, music, characters
+// and artist.
+func (y YoutubeService) ListYoutube(ctx context.Context, query string, opts entities.ListOptions) (youtube_ids []entities.YoutubeVideoID, next_cursor string, err error) {
+	if err := opts.Normalize(); err != nil {
+		return nil, "", err
+	}
+
+	f, err := filter.Parse(query)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return y.YoutubeRepository.ListYoutube(ctx, f, opts)
+}
+
+// ListChannels returns a page of every youtube channel known to the archive, along with the cursor of the next page.
+func (y YoutubeService) ListChannels(ctx context.Context, opts entities.ListOptions) (channel_ids []entities.YoutubeChannelID, next_cursor string, err error) {
+	if err := opts.Normalize(); err != nil {
+		return nil, "", err
+	}
+
+	return y.YoutubeRepository.ListChannels(ctx, opts)
+}
@@ -2,6 +2,7 @@ package youtube
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 
 	"github.com/dtbead/wc-maps-archive/internal/entities"
@@ -72,6 +73,33 @@ func (y YoutubeService) GetYoutube(ctx context.Context, youtube_id entities.Yout
 	return y.YoutubeRepository.GetYoutube(ctx, youtube_id)
 }
 
+// GetFileYoutube returns the youtube video file_id is a download of, or sql.ErrNoRows if it isn't one.
+func (y YoutubeService) GetFileYoutube(ctx context.Context, file_id entities.FileID) (youtube_id entities.YoutubeVideoID, err error) {
+	if !file_id.IsValid() {
+		return "", errors.New("invalid file_id")
+	}
+
+	return y.YoutubeRepository.GetFileYoutubeID(ctx, file_id)
+}
+
+// GetYtdlpInfo returns the info json yt-dlp printed when downloading file_id, or sql.ErrNoRows if none was kept.
+func (y YoutubeService) GetYtdlpInfo(ctx context.Context, file_id entities.FileID) (info json.RawMessage, err error) {
+	if !file_id.IsValid() {
+		return nil, errors.New("invalid file_id")
+	}
+
+	return y.YoutubeRepository.GetYtdlpInfo(ctx, file_id)
+}
+
+// GetThumbnail returns the jpeg thumbnail yt-dlp wrote when downloading file_id, or sql.ErrNoRows if none was kept.
+func (y YoutubeService) GetThumbnail(ctx context.Context, file_id entities.FileID) (thumbnail []byte, err error) {
+	if !file_id.IsValid() {
+		return nil, errors.New("invalid file_id")
+	}
+
+	return y.YoutubeRepository.GetThumbnail(ctx, file_id)
+}
+
 func (y YoutubeService) GetYoutubeFileIDs(ctx context.Context, youtube_id entities.YoutubeVideoID) (file_ids []entities.FileID, err error) {
 	if !youtube_id.IsValid() {
 		return nil, entities.ErrorInvalidYoutubeID
@@ -98,6 +126,20 @@ func (y YoutubeService) GetChannelVideos(ctx context.Context, channel_id entitie
 	}
 	return y.YoutubeRepository.GetChannelVideos(ctx, channel_id)
 }
+
+// AssignFile links an already stored file to a youtube video which is known to the archive.
+func (y YoutubeService) AssignFile(ctx context.Context, youtube_id entities.YoutubeVideoID, file_id entities.FileID) (err error) {
+	if !youtube_id.IsValid() {
+		return entities.ErrorInvalidYoutubeID
+	}
+
+	if !file_id.IsValid() {
+		return errors.New("invalid file_id")
+	}
+
+	return y.YoutubeRepository.AssignYoutubeFile(ctx, youtube_id, file_id)
+}
+
 func (y YoutubeService) GetYoutubeVideo(ctx context.Context, youtube_id entities.YoutubeVideoID) (video entities.YoutubeVideo, err error) {
 	if !youtube_id.IsValid() {
 		return entities.YoutubeVideo{}, entities.ErrorInvalidYoutubeID
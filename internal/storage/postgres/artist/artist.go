@@ -3,11 +3,14 @@ package artist
 import (
 	"context"
 	"database/sql"
+	"time"
 
 	"github.com/dtbead/wc-maps-archive/internal/entities"
 	"github.com/dtbead/wc-maps-archive/internal/storage/postgres/queries"
 )
 
+// ArtistRepository looks artists up by artist_name, which may be either their current name or one of their aliases.
+// The current name wins if both happen to match different artists.
 type ArtistRepository struct {
 	db *sql.DB
 	q  *queries.Queries
@@ -21,17 +24,227 @@ func NewArtistRepository(db *sql.DB) *ArtistRepository {
 }
 
 func (a ArtistRepository) NewArtist(ctx context.Context, artist_name string) (err error) {
-	panic("unimplemented")
+	_, err = a.q.NewArtist(ctx, artist_name)
+	return err
 }
 
 func (a ArtistRepository) DeleteArtist(ctx context.Context, artist_name string) (err error) {
-	panic("unimplemented")
+	artist_id, err := a.q.GetArtistID(ctx, artist_name)
+	if err != nil {
+		return err
+	}
+
+	rows, err := a.q.DeleteArtist(ctx, artist_id)
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
 }
 
+// RenameArtist changes the current name of artist_name to new_name, keeping their previous name as an alias.
+func (a ArtistRepository) RenameArtist(ctx context.Context, artist_name, new_name string) (err error) {
+	tx, err := a.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	q := a.q.WithTx(tx)
+
+	artist_id, err := q.GetArtistID(ctx, artist_name)
+	if err != nil {
+		return err
+	}
+
+	artist, err := q.GetArtist(ctx, artist_id)
+	if err != nil {
+		return err
+	}
+
+	if artist.Name == new_name {
+		return nil
+	}
+
+	// new_name may well be a name the artist has gone by before, which is now their current name rather than an alias
+	_, err = q.DeleteArtistAlias(ctx, queries.DeleteArtistAliasParams{ArtistID: artist_id, Alias: new_name})
+	if err != nil {
+		return err
+	}
+
+	err = q.RenameArtist(ctx, queries.RenameArtistParams{ID: artist_id, Name: new_name})
+	if err != nil {
+		return err
+	}
+
+	err = q.NewArtistAlias(ctx, queries.NewArtistAliasParams{ArtistID: artist_id, Alias: artist.Name})
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (a ArtistRepository) NewArtistAlias(ctx context.Context, artist_name, alias string) (err error) {
+	artist_id, err := a.q.GetArtistID(ctx, artist_name)
+	if err != nil {
+		return err
+	}
+
+	return a.q.NewArtistAlias(ctx, queries.NewArtistAliasParams{ArtistID: artist_id, Alias: alias})
+}
+
+func (a ArtistRepository) DeleteArtistAlias(ctx context.Context, artist_name, alias string) (err error) {
+	artist_id, err := a.q.GetArtistID(ctx, artist_name)
+	if err != nil {
+		return err
+	}
+
+	rows, err := a.q.DeleteArtistAlias(ctx, queries.DeleteArtistAliasParams{ArtistID: artist_id, Alias: alias})
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// AssignArtistChannel links channel_id to artist_name. channel_id doesn't need to have been archived yet.
 func (a ArtistRepository) AssignArtistChannel(ctx context.Context, artist_name string, channel_id entities.YoutubeChannelID) (err error) {
-	panic("unimplemented")
+	tx, err := a.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	q := a.q.WithTx(tx)
+
+	artist_id, err := q.GetArtistID(ctx, artist_name)
+	if err != nil {
+		return err
+	}
+
+	err = q.NewYoutubeChannel(ctx, channel_id)
+	if err != nil {
+		return err
+	}
+
+	err = q.AssignArtistChannel(ctx, queries.AssignArtistChannelParams{ArtistID: artist_id, ChannelID: channel_id})
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
 }
 
 func (a ArtistRepository) UnassignArtistChannel(ctx context.Context, artist_name string, channel_id entities.YoutubeChannelID) (err error) {
-	panic("unimplemented")
+	artist_id, err := a.q.GetArtistID(ctx, artist_name)
+	if err != nil {
+		return err
+	}
+
+	rows, err := a.q.UnassignArtistChannel(ctx, queries.UnassignArtistChannelParams{ArtistID: artist_id, ChannelID: channel_id})
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+func (a ArtistRepository) GetArtist(ctx context.Context, artist_name string) (artist *entities.Artist, err error) {
+	artist_id, err := a.q.GetArtistID(ctx, artist_name)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := a.q.GetArtist(ctx, artist_id)
+	if err != nil {
+		return nil, err
+	}
+
+	aliases, err := a.q.GetArtistAliases(ctx, artist_id)
+	if err != nil {
+		return nil, err
+	}
+
+	channels, err := a.q.GetArtistChannels(ctx, artist_id)
+	if err != nil {
+		return nil, err
+	}
+
+	artist = &entities.Artist{
+		ID:        res.ID,
+		Name:      res.Name,
+		Aliases:   aliases,
+		Channels:  make([]entities.YoutubeChannelID, 0, len(channels)),
+		DateAdded: res.DateAdded,
+	}
+
+	for _, v := range channels {
+		artist.Channels = append(artist.Channels, entities.YoutubeChannelID(v.(string)))
+	}
+
+	return artist, nil
+}
+
+// GetChannelArtists returns the current name of every artist linked to channel_id.
+func (a ArtistRepository) GetChannelArtists(ctx context.Context, channel_id entities.YoutubeChannelID) (artists []string, err error) {
+	return a.q.GetChannelArtists(ctx, channel_id)
+}
+
+// GetArtistVideos returns every youtube video uploaded by any of artist_name's channels, oldest first.
+func (a ArtistRepository) GetArtistVideos(ctx context.Context, artist_name string) (videos []entities.YoutubeVideoID, err error) {
+	artist_id, err := a.q.GetArtistID(ctx, artist_name)
+	if err != nil {
+		return nil, err
+	}
+
+	return a.q.GetArtistYoutubeVideos(ctx, artist_id)
+}
+
+// GetArtistParts returns every MAP part artist_name took part in, either through one of their channels or under their
+// name or any of their aliases.
+func (a ArtistRepository) GetArtistParts(ctx context.Context, artist_name string) (parts []entities.ProjectPart, err error) {
+	artist_id, err := a.q.GetArtistID(ctx, artist_name)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := a.q.GetArtistProjectParts(ctx, artist_id)
+	if err != nil {
+		return nil, err
+	}
+
+	parts = make([]entities.ProjectPart, 0, len(res))
+	for _, v := range res {
+		status, err := entities.NewPartStatus(string(v.Status))
+		if err != nil {
+			return nil, err
+		}
+
+		parts = append(parts, entities.ProjectPart{
+			ID:                v.ID,
+			ProjectUUID:       entities.ProjectUUID(v.Uuid),
+			Number:            int(v.PartNumber),
+			Start:             time.Duration(v.StartMs.Int32) * time.Millisecond,
+			End:               time.Duration(v.EndMs.Int32) * time.Millisecond,
+			Status:            status,
+			Participant:       entities.YoutubeChannelID(v.ParticipantChannelID.String),
+			ParticipantArtist: v.ParticipantArtist.String,
+			ParticipantName:   v.ParticipantName.String,
+			FileID:            entities.FileID(v.FileID.Int64),
+			YoutubeID:         entities.YoutubeVideoID(v.YoutubeID.String),
+		})
+	}
+
+	return parts, nil
 }
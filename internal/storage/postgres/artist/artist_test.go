@@ -0,0 +1,130 @@
+package artist_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dtbead/wc-maps-archive/internal/entities"
+	helper_test "github.com/dtbead/wc-maps-archive/internal/helper/testing"
+	"github.com/dtbead/wc-maps-archive/internal/storage/postgres/artist"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestArtistRepository(t *testing.T) {
+	db := helper_test.NewDatabase(&helper_test.DefaultConnection)
+	defer db.Close()
+
+	artistRepo := artist.NewArtistRepository(db)
+	ctx := context.Background()
+
+	const channel entities.YoutubeChannelID = "UCabcdefghijklmnopqrstuA"
+	const other_channel entities.YoutubeChannelID = "UCzyxwvutsrqponmlkjihgfw"
+
+	if err := artistRepo.NewArtist(ctx, "Tallstar"); err != nil {
+		t.Fatalf("ArtistRepository.NewArtist() error = %v", err)
+	}
+
+	if err := artistRepo.NewArtistAlias(ctx, "tallstar", "TallstarAnimates"); err != nil {
+		t.Fatalf("ArtistRepository.NewArtistAlias() error = %v", err)
+	}
+
+	for _, c := range []entities.YoutubeChannelID{channel, other_channel} {
+		if err := artistRepo.AssignArtistChannel(ctx, "TallstarAnimates", c); err != nil {
+			t.Fatalf("ArtistRepository.AssignArtistChannel() error = %v", err)
+		}
+	}
+
+	// a video on each of the artist's channels, and one on a channel of somebody else
+	const unrelated_channel entities.YoutubeChannelID = "UCaaaaaaaaaaaaaaaaaaaaaQ"
+	videos := []struct {
+		youtube_id  entities.YoutubeVideoID
+		channel_id  entities.YoutubeChannelID
+		upload_date string
+	}{
+		{"y_wo8pyoxyk", channel, "2015-06-21"},
+		{"dQw4w9WgXcQ", other_channel, "2016-01-02"},
+		{"abcdefghijA", unrelated_channel, "2015-01-01"},
+	}
+	if _, err := db.Exec(`INSERT INTO youtube_channel (id) VALUES ($1)`, string(unrelated_channel)); err != nil {
+		t.Fatalf("failed to insert youtube channel, %v", err)
+	}
+	for _, v := range videos {
+		if _, err := db.Exec(`INSERT INTO youtube_video (id, upload_date, duration) VALUES ($1, $2, 7)`, string(v.youtube_id), v.upload_date); err != nil {
+			t.Fatalf("failed to insert youtube video, %v", err)
+		}
+
+		if _, err := db.Exec(`INSERT INTO youtube_channel_youtube_video (channel_id, youtube_id) VALUES ($1, $2)`, string(v.channel_id), string(v.youtube_id)); err != nil {
+			t.Fatalf("failed to insert youtube channel video, %v", err)
+		}
+	}
+
+	got_videos, err := artistRepo.GetArtistVideos(ctx, "TallstarAnimates")
+	if err != nil {
+		t.Fatalf("ArtistRepository.GetArtistVideos() error = %v", err)
+	}
+
+	// videos of every channel come back together, oldest first
+	if diff := cmp.Diff([]entities.YoutubeVideoID{"y_wo8pyoxyk", "dQw4w9WgXcQ"}, got_videos); diff != "" {
+		t.Errorf("ArtistRepository.GetArtistVideos() mismatch (-want +got):\n%s", diff)
+	}
+
+	if err := artistRepo.RenameArtist(ctx, "Tallstar", "Tallstar's Revenge"); err != nil {
+		t.Fatalf("ArtistRepository.RenameArtist() error = %v", err)
+	}
+
+	// the previous name keeps pointing at the same artist
+	got, err := artistRepo.GetArtist(ctx, "tallstar")
+	if err != nil {
+		t.Fatalf("ArtistRepository.GetArtist() error = %v", err)
+	}
+
+	want := &entities.Artist{
+		ID:        got.ID,
+		Name:      "Tallstar's Revenge",
+		Aliases:   []string{"Tallstar", "TallstarAnimates"},
+		Channels:  []entities.YoutubeChannelID{channel, other_channel},
+		DateAdded: got.DateAdded,
+	}
+	if !cmp.Equal(got, want) {
+		t.Errorf("got diff %s", cmp.Diff(got, want))
+	}
+
+	artists, err := artistRepo.GetChannelArtists(ctx, other_channel)
+	if err != nil {
+		t.Fatalf("ArtistRepository.GetChannelArtists() error = %v", err)
+	}
+
+	if !cmp.Equal(artists, []string{"Tallstar's Revenge"}) {
+		t.Errorf("ArtistRepository.GetChannelArtists() = %v", artists)
+	}
+
+	if err := artistRepo.UnassignArtistChannel(ctx, "Tallstar", other_channel); err != nil {
+		t.Errorf("ArtistRepository.UnassignArtistChannel() error = %v", err)
+	}
+
+	if err := artistRepo.DeleteArtistAlias(ctx, "Tallstar's Revenge", "TallstarAnimates"); err != nil {
+		t.Errorf("ArtistRepository.DeleteArtistAlias() error = %v", err)
+	}
+
+	if err := artistRepo.DeleteArtistAlias(ctx, "Tallstar's Revenge", "TallstarAnimates"); err == nil {
+		t.Errorf("ArtistRepository.DeleteArtistAlias() of a removed alias error = %v, wantErr %v", err, true)
+	}
+
+	// videos of a channel no longer assigned to the artist aren't theirs anymore
+	got_videos, err = artistRepo.GetArtistVideos(ctx, "Tallstar")
+	if err != nil {
+		t.Fatalf("ArtistRepository.GetArtistVideos() error = %v", err)
+	}
+
+	if diff := cmp.Diff([]entities.YoutubeVideoID{"y_wo8pyoxyk"}, got_videos); diff != "" {
+		t.Errorf("ArtistRepository.GetArtistVideos() mismatch (-want +got):\n%s", diff)
+	}
+
+	if err := artistRepo.DeleteArtist(ctx, "Tallstar"); err != nil {
+		t.Errorf("ArtistRepository.DeleteArtist() error = %v", err)
+	}
+
+	if _, err := artistRepo.GetArtist(ctx, "Tallstar's Revenge"); err == nil {
+		t.Errorf("ArtistRepository.GetArtist() of a deleted artist error = %v, wantErr %v", err, true)
+	}
+}
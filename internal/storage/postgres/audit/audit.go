@@ -0,0 +1,104 @@
+package audit
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+
+	"github.com/dtbead/wc-maps-archive/internal/entities"
+	"github.com/dtbead/wc-maps-archive/internal/storage/postgres/queries"
+)
+
+type AuditRepository struct {
+	db *sql.DB
+	q  *queries.Queries
+}
+
+func NewAuditRepository(db *sql.DB) *AuditRepository {
+	return &AuditRepository{
+		db: db,
+		q:  queries.New(db),
+	}
+}
+
+// orNull returns b, or JSON null if b is empty.
+func orNull(b json.RawMessage) json.RawMessage {
+	if len(b) == 0 {
+		return json.RawMessage("null")
+	}
+
+	return b
+}
+
+// NewAuditEntry records entry, returning its id. entry.ID and entry.DateAdded are ignored, and an empty Before or After
+// is stored as null.
+func (a AuditRepository) NewAuditEntry(ctx context.Context, entry *entities.AuditEntry) (entry_id int64, err error) {
+	if entry == nil {
+		return 0, errors.New("nil audit entry")
+	}
+
+	return a.q.NewAuditEntry(ctx, queries.NewAuditEntryParams{
+		Actor:    entry.Actor,
+		Entity:   queries.Auditentity(entry.Entity.ToString()),
+		EntityID: entry.EntityID,
+		Action:   entry.Action,
+		Before:   orNull(entry.Before),
+		After:    orNull(entry.After),
+	})
+}
+
+func (a AuditRepository) GetAuditEntry(ctx context.Context, entry_id int64) (entry *entities.AuditEntry, err error) {
+	res, err := a.q.GetAuditEntry(ctx, entry_id)
+	if err != nil {
+		return nil, err
+	}
+
+	e, err := newAuditEntry(res)
+	if err != nil {
+		return nil, err
+	}
+
+	return &e, nil
+}
+
+// GetAuditEntries returns every change made to the entity_id of entity, oldest first.
+func (a AuditRepository) GetAuditEntries(ctx context.Context, entity entities.AuditEntity, entity_id string) (entries []entities.AuditEntry, err error) {
+	res, err := a.q.GetAuditEntries(ctx, queries.GetAuditEntriesParams{
+		Entity:   queries.Auditentity(entity.ToString()),
+		EntityID: entity_id,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	entries = make([]entities.AuditEntry, 0, len(res))
+	for _, v := range res {
+		e, err := newAuditEntry(v)
+		if err != nil {
+			return nil, err
+		}
+
+		entries = append(entries, e)
+	}
+
+	return entries, nil
+}
+
+func newAuditEntry(res queries.AuditLog) (entry entities.AuditEntry, err error) {
+	entity, err := entities.NewAuditEntity(string(res.Entity))
+	if err != nil {
+		return entities.AuditEntry{}, err
+	}
+
+	return entities.AuditEntry{
+		ID:        res.ID,
+		Actor:     res.Actor,
+		Entity:    entity,
+		EntityID:  res.EntityID,
+		Action:    res.Action,
+		Before:    res.Before,
+		After:     res.After,
+		DateAdded: res.DateAdded,
+	}, nil
+}
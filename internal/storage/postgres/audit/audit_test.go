@@ -0,0 +1,78 @@
+package audit_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/dtbead/wc-maps-archive/internal/entities"
+	"github.com/dtbead/wc-maps-archive/internal/helper"
+	helper_test "github.com/dtbead/wc-maps-archive/internal/helper/testing"
+	"github.com/dtbead/wc-maps-archive/internal/storage/postgres/audit"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestAuditRepository_AuditEntries(t *testing.T) {
+	db := helper_test.NewDatabase(&helper_test.DefaultConnection)
+	defer db.Close()
+
+	auditRepo := audit.NewAuditRepository(db)
+	ctx := context.Background()
+
+	uuid := helper.RandomUUID()
+	want := []entities.AuditEntry{
+		{Actor: "cli:firestar", Entity: entities.AuditEntityProject, EntityID: uuid, Action: "new project",
+			Before: json.RawMessage("null"), After: json.RawMessage(`"Firestar MAP"`)},
+		{Actor: "http:127.0.0.1", Entity: entities.AuditEntityProject, EntityID: uuid, Action: "set title",
+			Before: json.RawMessage(`"Firestar MAP"`), After: json.RawMessage(`"Firestar's Quest MAP"`)},
+	}
+
+	// an empty Before is stored as null
+	first := want[0]
+	first.Before = nil
+
+	for i, e := range []entities.AuditEntry{first, want[1]} {
+		id, err := auditRepo.NewAuditEntry(ctx, &e)
+		if err != nil {
+			t.Fatalf("AuditRepository.NewAuditEntry() error = %v", err)
+		}
+		want[i].ID = id
+	}
+
+	// changes to another entity aren't part of the log
+	if _, err := auditRepo.NewAuditEntry(ctx, &entities.AuditEntry{Actor: "cli:firestar", Entity: entities.AuditEntityFile,
+		EntityID: "1", Action: "delete file", Before: json.RawMessage("null"), After: json.RawMessage("null")}); err != nil {
+		t.Fatalf("AuditRepository.NewAuditEntry() error = %v", err)
+	}
+
+	got, err := auditRepo.GetAuditEntries(ctx, entities.AuditEntityProject, uuid)
+	if err != nil {
+		t.Fatalf("AuditRepository.GetAuditEntries() error = %v", err)
+	}
+
+	for i := range got {
+		if got[i].DateAdded.IsZero() {
+			t.Errorf("AuditRepository.GetAuditEntries() entry %d has no DateAdded", got[i].ID)
+		}
+		got[i].DateAdded = time.Time{}
+	}
+
+	if !cmp.Equal(got, want) {
+		t.Errorf("got diff %s", cmp.Diff(got, want))
+	}
+
+	entry, err := auditRepo.GetAuditEntry(ctx, want[1].ID)
+	if err != nil {
+		t.Fatalf("AuditRepository.GetAuditEntry() error = %v", err)
+	}
+
+	entry.DateAdded = time.Time{}
+	if !cmp.Equal(*entry, want[1]) {
+		t.Errorf("got diff %s", cmp.Diff(*entry, want[1]))
+	}
+
+	if _, err := auditRepo.NewAuditEntry(ctx, &entities.AuditEntry{Entity: entities.AuditEntityProject, EntityID: uuid, Action: "set title"}); err == nil {
+		t.Errorf("AuditRepository.NewAuditEntry() without an actor succeeded")
+	}
+}
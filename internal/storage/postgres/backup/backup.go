@@ -0,0 +1,484 @@
+// Package backup takes backups of the database together with the files of the storage root, and restores them into an
+// empty instance. Every backup is a directory of its own within a backup directory, holding a full dump of the database
+// and only the files added since the backup before it, so restoring a backup needs every backup it builds on as well.
+package backup
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/dtbead/wc-maps-archive/internal/entities"
+	"github.com/dtbead/wc-maps-archive/internal/storage/postgres/queries"
+)
+
+// Version is the version of the manifest written by Create.
+const Version = 1
+
+const (
+	manifestName   = "manifest.json"
+	databaseName   = "database.dump"
+	filesDirectory = "files"
+
+	// nameLayout names every backup after the time it was taken, so that they sort in the order they were taken in
+	nameLayout = "20060102T150405Z"
+)
+
+// Manifest describes a single backup. It holds every file with an id after AfterFileID up to and including LastFileID
+// which existed when it was taken, while the files up to AfterFileID are held by Previous and the backups before it.
+type Manifest struct {
+	Version     int       `json:"version"`
+	Name        string    `json:"name"`
+	Created     time.Time `json:"created"`
+	Previous    string    `json:"previous,omitempty"`
+	Database    Database  `json:"database"`
+	AfterFileID int64     `json:"after_file_id"`
+	LastFileID  int64     `json:"last_file_id"`
+	Files       []File    `json:"files"`
+}
+
+// Database is the pg_dump archive of a backup.
+type Database struct {
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// File is a file of the storage root, kept at files/<Path> within a backup, Path being relative to the storage root.
+type File struct {
+	ID     int64  `json:"id"`
+	Path   string `json:"path"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+type Backup struct {
+	pgDump, pgRestore string
+}
+
+// NewBackup returns a Backup calling the pg_dump and pg_restore binaries at the given paths. An empty path looks up
+// "pg_dump" or "pg_restore" in PATH instead. Both need to be at least the version of the database server.
+func NewBackup(pg_dump_path, pg_restore_path string) Backup {
+	if pg_dump_path == "" {
+		pg_dump_path = "pg_dump"
+	}
+
+	if pg_restore_path == "" {
+		pg_restore_path = "pg_restore"
+	}
+
+	return Backup{pgDump: pg_dump_path, pgRestore: pg_restore_path}
+}
+
+// Create takes a new backup of the database at connection, which db is connected to, and of the storage root at
+// base_directory, within directory. It builds on the latest backup already within directory, only copying the files
+// added since then.
+//
+// The database is dumped from the same snapshot the files to copy are listed from, and the "file" table stays locked
+// against changes until every file has been copied, so that no file the dump refers to can be deleted before it has
+// been copied. Locking the table also waits for files still being added to finish, so none of them can show up later
+// with an id below LastFileID. Adding or deleting a file waits on the lock, so nothing can be ingested or deleted for
+// as long as the database is being dumped and the new files copied, which for a large backup may be a long while.
+func (b Backup) Create(ctx context.Context, db *sql.DB, connection, base_directory, directory string) (manifest *Manifest, err error) {
+	previous, err := Latest(directory)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	manifest = &Manifest{
+		Version: Version,
+		Name:    now.Format(nameLayout),
+		Created: now,
+		Files:   []File{},
+	}
+
+	if previous != nil {
+		manifest.Previous = previous.Name
+		manifest.AfterFileID = previous.LastFileID
+	}
+	manifest.LastFileID = manifest.AfterFileID
+
+	err = os.MkdirAll(directory, 0775)
+	if err != nil {
+		return nil, err
+	}
+
+	backup_directory := filepath.Join(directory, manifest.Name)
+	err = os.Mkdir(backup_directory, 0775)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			err = errors.Join(err, os.RemoveAll(backup_directory))
+		}
+	}()
+
+	tx, err := db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	q := queries.New(tx)
+
+	// the lock has to be taken before anything else, as the snapshot of the transaction is taken by its first query
+	err = q.LockFiles(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot_id, err := q.ExportSnapshot(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	database := filepath.Join(backup_directory, databaseName)
+	err = run(ctx, b.pgDump, "--format=custom", "--snapshot="+snapshot_id, "--file="+database, "--dbname="+connection)
+	if err != nil {
+		return nil, err
+	}
+
+	manifest.Database.SHA256, manifest.Database.Size, err = hashFile(database)
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := q.GetFilesAfter(ctx, manifest.AfterFileID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, f := range files {
+		err = copyFile(filepath.Join(base_directory, filepath.FromSlash(f.Path)), filepath.Join(backup_directory, filesDirectory, filepath.FromSlash(f.Path)), f.Filesize, f.Sha256)
+		if err != nil {
+			return nil, fmt.Errorf("file %d: %w", f.ID, err)
+		}
+
+		manifest.Files = append(manifest.Files, File{ID: f.ID, Path: f.Path, Size: f.Filesize, SHA256: hex.EncodeToString(f.Sha256)})
+		manifest.LastFileID = f.ID
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, err
+	}
+
+	// the manifest is written last, as a backup without one is one that never finished
+	err = writeManifest(backup_directory, manifest)
+	if err != nil {
+		return nil, err
+	}
+
+	return manifest, nil
+}
+
+// Verify checks the backup called name within directory, or the latest one if name is empty, without restoring it. Every
+// backup it builds on has to be there and hold the files it's meant to, and the database dump and every file have to
+// match their checksums. Every problem found is returned together, each wrapping entities.ErrorInvalidBackup, and also
+// entities.ErrorHashMismatch if it's a checksum that doesn't match.
+func (b Backup) Verify(ctx context.Context, directory, name string) (manifest *Manifest, err error) {
+	chain, err := verify(ctx, directory, name)
+	if err != nil {
+		return nil, err
+	}
+
+	return chain[len(chain)-1], nil
+}
+
+// verify verifies the backup called name as described by Verify, returning it preceded by every backup it builds on.
+func verify(ctx context.Context, directory, name string) (chain []*Manifest, err error) {
+	chain, err = readChain(directory, name)
+	if err != nil {
+		return nil, err
+	}
+	manifest := chain[len(chain)-1]
+
+	var problems []error
+	fail := func(err error) {
+		problems = append(problems, fmt.Errorf("%w, %w", entities.ErrorInvalidBackup, err))
+	}
+
+	sum, size, err := hashFile(filepath.Join(directory, manifest.Name, databaseName))
+	if err != nil {
+		fail(fmt.Errorf("%s: %w", manifest.Name, err))
+	} else if sum != manifest.Database.SHA256 || size != manifest.Database.Size {
+		fail(fmt.Errorf("%w, %s: the database dump doesn't match its checksum", entities.ErrorHashMismatch, manifest.Name))
+	}
+
+	for _, m := range chain {
+		for _, f := range m.Files {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+
+			sum, size, err := hashFile(filepath.Join(directory, m.Name, filesDirectory, filepath.FromSlash(f.Path)))
+			if err != nil {
+				fail(fmt.Errorf("%s: file %d: %w", m.Name, f.ID, err))
+				continue
+			}
+
+			if sum != f.SHA256 || size != f.Size {
+				fail(fmt.Errorf("%w, %s: file %d doesn't match its checksum", entities.ErrorHashMismatch, m.Name, f.ID))
+			}
+		}
+	}
+
+	err = errors.Join(problems...)
+	if err != nil {
+		return nil, err
+	}
+
+	return chain, nil
+}
+
+// Restore verifies the backup called name within directory, or the latest one if name is empty, and restores it into
+// the empty database at connection, which db is connected to, and the storage root at base_directory, which has to
+// either not exist yet or be empty. The database is restored in a single transaction, so a database which isn't empty
+// is left as it was.
+func (b Backup) Restore(ctx context.Context, db *sql.DB, connection, base_directory, directory, name string) (manifest *Manifest, err error) {
+	chain, err := verify(ctx, directory, name)
+	if err != nil {
+		return nil, err
+	}
+	manifest = chain[len(chain)-1]
+
+	entries, err := os.ReadDir(base_directory)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+
+	if len(entries) > 0 {
+		return nil, fmt.Errorf("can't restore into %s, it isn't empty", base_directory)
+	}
+
+	// the files of the backup by their id, along with the backup holding them
+	backed_up := make(map[int64]File)
+	held_by := make(map[int64]string)
+	for _, m := range chain {
+		for _, f := range m.Files {
+			backed_up[f.ID], held_by[f.ID] = f, m.Name
+		}
+	}
+
+	err = run(ctx, b.pgRestore, "--exit-on-error", "--single-transaction", "--no-owner", "--dbname="+connection, filepath.Join(directory, manifest.Name, databaseName))
+	if err != nil {
+		return nil, err
+	}
+
+	// files deleted since they were backed up aren't in the restored database anymore, and so aren't restored either
+	files, err := queries.New(db).GetFilesAfter(ctx, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, f := range files {
+		backup, ok := backed_up[f.ID]
+		if !ok || backup.Path != f.Path {
+			return nil, fmt.Errorf("%w, file %d isn't in any backup %s builds on", entities.ErrorInvalidBackup, f.ID, manifest.Name)
+		}
+
+		source := filepath.Join(directory, held_by[f.ID], filesDirectory, filepath.FromSlash(f.Path))
+		err = copyFile(source, filepath.Join(base_directory, filepath.FromSlash(f.Path)), f.Filesize, f.Sha256)
+		if err != nil {
+			return nil, fmt.Errorf("file %d: %w", f.ID, err)
+		}
+	}
+
+	return manifest, nil
+}
+
+// Latest returns the manifest of the latest finished backup within directory, or an error wrapping fs.ErrNotExist if
+// there's none.
+func Latest(directory string) (manifest *Manifest, err error) {
+	entries, err := os.ReadDir(directory)
+	if err != nil {
+		return nil, err
+	}
+
+	// entries are sorted by name, which is the time they were taken
+	for i := len(entries) - 1; i >= 0; i-- {
+		if !entries[i].IsDir() {
+			continue
+		}
+
+		manifest, err = readManifest(directory, entries[i].Name())
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+
+		return manifest, err
+	}
+
+	return nil, fmt.Errorf("no backup within %s: %w", directory, fs.ErrNotExist)
+}
+
+// readChain returns the manifest of the backup called name within directory, or of the latest one if name is empty,
+// preceded by every backup it builds on, oldest first. The backups have to hold every file id between them exactly once.
+func readChain(directory, name string) (chain []*Manifest, err error) {
+	var manifest *Manifest
+	if name == "" {
+		manifest, err = Latest(directory)
+	} else {
+		manifest, err = readManifest(directory, name)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		chain = append([]*Manifest{manifest}, chain...)
+		if manifest.Previous == "" {
+			break
+		}
+
+		// backups are named after the time they were taken, which also keeps a broken manifest from looping forever
+		if manifest.Previous >= manifest.Name {
+			return nil, fmt.Errorf("%w, %s builds on %s, which isn't older than it", entities.ErrorInvalidBackup, manifest.Name, manifest.Previous)
+		}
+
+		previous, err := readManifest(directory, manifest.Previous)
+		if err != nil {
+			return nil, fmt.Errorf("%w, %s builds on %s: %w", entities.ErrorInvalidBackup, manifest.Name, manifest.Previous, err)
+		}
+
+		if previous.LastFileID != manifest.AfterFileID {
+			return nil, fmt.Errorf("%w, %s holds files after %d, but %s only holds files up to %d", entities.ErrorInvalidBackup,
+				manifest.Name, manifest.AfterFileID, previous.Name, previous.LastFileID)
+		}
+
+		manifest = previous
+	}
+
+	if chain[0].AfterFileID != 0 {
+		return nil, fmt.Errorf("%w, %s is the first backup, but only holds files after %d", entities.ErrorInvalidBackup, chain[0].Name, chain[0].AfterFileID)
+	}
+
+	return chain, nil
+}
+
+func readManifest(directory, name string) (*Manifest, error) {
+	contents, err := os.ReadFile(filepath.Join(directory, name, manifestName))
+	if err != nil {
+		return nil, err
+	}
+
+	var m Manifest
+	err = json.Unmarshal(contents, &m)
+	if err != nil {
+		return nil, fmt.Errorf("%w, %s: %w", entities.ErrorInvalidBackup, name, err)
+	}
+
+	if m.Version != Version {
+		return nil, fmt.Errorf("%w, %s is of version %d, expected %d", entities.ErrorInvalidBackup, name, m.Version, Version)
+	}
+
+	if m.Name != name {
+		return nil, fmt.Errorf("%w, %s is named %q in its manifest", entities.ErrorInvalidBackup, name, m.Name)
+	}
+
+	id := m.AfterFileID
+	for _, f := range m.Files {
+		if f.ID <= id || f.ID > m.LastFileID {
+			return nil, fmt.Errorf("%w, %s holds file %d out of order or outside of %d to %d", entities.ErrorInvalidBackup, name, f.ID, m.AfterFileID, m.LastFileID)
+		}
+		id = f.ID
+
+		if f.Path == "" || path.IsAbs(f.Path) || path.Clean(f.Path) != f.Path || strings.HasPrefix(f.Path, "../") || strings.Contains(f.Path, `\`) {
+			return nil, fmt.Errorf("%w, %s holds file %d at invalid path %q", entities.ErrorInvalidBackup, name, f.ID, f.Path)
+		}
+	}
+
+	return &m, nil
+}
+
+// writeManifest writes manifest to backup_directory, moving it into place once it's been fully written.
+func writeManifest(backup_directory string, manifest *Manifest) error {
+	contents, err := json.MarshalIndent(manifest, "", "\t")
+	if err != nil {
+		return err
+	}
+
+	tmp := filepath.Join(backup_directory, manifestName+".tmp")
+	err = os.WriteFile(tmp, contents, 0664)
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, filepath.Join(backup_directory, manifestName))
+}
+
+// copyFile copies source to destination, failing with entities.ErrorHashMismatch if what was copied isn't size bytes
+// long or doesn't hash to sha256.
+func copyFile(source, destination string, size int64, sha256_sum []byte) (err error) {
+	r, err := os.Open(source)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	err = os.MkdirAll(filepath.Dir(destination), 0775)
+	if err != nil {
+		return err
+	}
+
+	w, err := os.Create(destination)
+	if err != nil {
+		return err
+	}
+	defer func() { err = errors.Join(err, w.Close()) }()
+
+	h := sha256.New()
+	written, err := io.Copy(io.MultiWriter(w, h), r)
+	if err != nil {
+		return err
+	}
+
+	if written != size || !bytes.Equal(h.Sum(nil), sha256_sum) {
+		return fmt.Errorf("%w, %s", entities.ErrorHashMismatch, source)
+	}
+
+	return w.Sync()
+}
+
+// hashFile returns the hex SHA256 and size of the file called name.
+func hashFile(name string) (sha256_sum string, size int64, err error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return "", 0, err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	size, err = io.Copy(h, f)
+	if err != nil {
+		return "", 0, err
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), size, nil
+}
+
+func run(ctx context.Context, binary string, args ...string) error {
+	cmd := exec.CommandContext(ctx, binary, args...)
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+
+	err := cmd.Run()
+	if err != nil {
+		return errors.Join(err, errors.New(stderr.String()))
+	}
+
+	return nil
+}
@@ -0,0 +1,264 @@
+package backup_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dtbead/wc-maps-archive/internal/entities"
+	"github.com/dtbead/wc-maps-archive/internal/helper"
+	helper_test "github.com/dtbead/wc-maps-archive/internal/helper/testing"
+	"github.com/dtbead/wc-maps-archive/internal/storage/postgres"
+	"github.com/dtbead/wc-maps-archive/internal/storage/postgres/backup"
+	"github.com/dtbead/wc-maps-archive/internal/storage/postgres/file"
+	_ "github.com/jackc/pgx/v5/stdlib"
+)
+
+func checksum(contents string) string {
+	sum := sha256.Sum256([]byte(contents))
+	return hex.EncodeToString(sum[:])
+}
+
+// writeBackup writes a backup called name holding files by their id, as Create would have.
+func writeBackup(t *testing.T, directory, name, previous string, after_file_id int64, files map[int64]string) {
+	t.Helper()
+
+	m := backup.Manifest{
+		Version:     backup.Version,
+		Name:        name,
+		Previous:    previous,
+		AfterFileID: after_file_id,
+		LastFileID:  after_file_id,
+		Database:    backup.Database{Size: int64(len("dump of " + name)), SHA256: checksum("dump of " + name)},
+	}
+
+	write := func(name, contents string) {
+		err := os.MkdirAll(filepath.Dir(name), 0775)
+		if err == nil {
+			err = os.WriteFile(name, []byte(contents), 0664)
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write(filepath.Join(directory, name, "database.dump"), "dump of "+name)
+	for id := after_file_id + 1; files[id] != ""; id++ {
+		path := checksum(files[id])[:2] + "/" + checksum(files[id]) + ".mp4"
+		write(filepath.Join(directory, name, "files", path), files[id])
+
+		m.Files = append(m.Files, backup.File{ID: id, Path: path, Size: int64(len(files[id])), SHA256: checksum(files[id])})
+		m.LastFileID = id
+	}
+
+	contents, err := json.Marshal(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	write(filepath.Join(directory, name, "manifest.json"), string(contents))
+}
+
+func writeBackups(t *testing.T) string {
+	t.Helper()
+
+	directory := t.TempDir()
+	writeBackup(t, directory, "20240101T000000Z", "", 0, map[int64]string{1: "first file", 2: "second file"})
+	writeBackup(t, directory, "20240201T000000Z", "20240101T000000Z", 2, map[int64]string{3: "third file"})
+	writeBackup(t, directory, "20240301T000000Z", "20240201T000000Z", 3, nil)
+
+	return directory
+}
+
+func TestLatest(t *testing.T) {
+	directory := writeBackups(t)
+
+	// a backup which never finished doesn't have a manifest
+	err := os.Mkdir(filepath.Join(directory, "20240401T000000Z"), 0775)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := backup.Latest(directory)
+	if err != nil {
+		t.Fatalf("Latest() error = %v", err)
+	}
+
+	if got.Name != "20240301T000000Z" || got.LastFileID != 3 {
+		t.Errorf("Latest() = %s up to file %d, want 20240301T000000Z up to file 3", got.Name, got.LastFileID)
+	}
+
+	_, err = backup.Latest(t.TempDir())
+	if !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("Latest() of an empty directory, error = %v, want %v", err, fs.ErrNotExist)
+	}
+}
+
+func TestBackup_Verify(t *testing.T) {
+	b := backup.NewBackup("", "")
+
+	tests := []struct {
+		name    string
+		backup  string
+		modify  func(directory string) error
+		wantErr error
+	}{
+		{"latest", "", func(directory string) error { return nil }, nil},
+		{"named", "20240201T000000Z", func(directory string) error { return nil }, nil},
+		{"file changed", "", func(directory string) error {
+			return os.WriteFile(filepath.Join(directory, "20240201T000000Z", "files", checksum("third file")[:2], checksum("third file")+".mp4"), []byte("Third file"), 0664)
+		}, entities.ErrorHashMismatch},
+		{"file missing", "", func(directory string) error {
+			return os.RemoveAll(filepath.Join(directory, "20240101T000000Z", "files"))
+		}, entities.ErrorInvalidBackup},
+		{"database changed", "", func(directory string) error {
+			return os.WriteFile(filepath.Join(directory, "20240301T000000Z", "database.dump"), []byte("dump of 20240301T000000X"), 0664)
+		}, entities.ErrorHashMismatch},
+		{"previous backup missing", "", func(directory string) error {
+			return os.RemoveAll(filepath.Join(directory, "20240101T000000Z"))
+		}, entities.ErrorInvalidBackup},
+		{"files skipped", "", func(directory string) error {
+			writeBackup(t, directory, "20240401T000000Z", "20240301T000000Z", 4, map[int64]string{5: "fifth file"})
+			return nil
+		}, entities.ErrorInvalidBackup},
+		{"builds on a newer backup", "20231201T000000Z", func(directory string) error {
+			writeBackup(t, directory, "20231201T000000Z", "20240301T000000Z", 3, nil)
+			return nil
+		}, entities.ErrorInvalidBackup},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			directory := writeBackups(t)
+			if err := tt.modify(directory); err != nil {
+				t.Fatal(err)
+			}
+
+			_, err := b.Verify(context.Background(), directory, tt.backup)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Verify() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// newDatabase creates an empty database of its own for the test, dropped once it's done, and returns its connection
+// string and a connection to it.
+func newDatabase(t *testing.T) (connection string, db *sql.DB) {
+	t.Helper()
+
+	admin, err := sql.Open("pgx", helper_test.NewDsn(helper_test.DefaultConnection))
+	if err != nil {
+		t.Fatalf("failed to connect to the database, %v", err)
+	}
+	t.Cleanup(func() { admin.Close() })
+
+	options := helper_test.DefaultConnection
+	options.DbName = "wc_backup_" + strings.ToLower(helper.RandomString(12))
+	if _, err := admin.Exec("CREATE DATABASE " + options.DbName); err != nil {
+		t.Fatalf("failed to create database %s, %v", options.DbName, err)
+	}
+
+	connection = helper_test.NewDsn(options)
+	db, err = sql.Open("pgx", connection)
+	if err != nil {
+		t.Fatalf("failed to connect to database %s, %v", options.DbName, err)
+	}
+
+	t.Cleanup(func() {
+		db.Close()
+		if _, err := admin.Exec("DROP DATABASE " + options.DbName + " WITH (FORCE)"); err != nil {
+			t.Errorf("failed to drop database %s, %v", options.DbName, err)
+		}
+	})
+
+	return connection, db
+}
+
+func TestBackup_CreateRestore(t *testing.T) {
+	for _, binary := range []string{"pg_dump", "pg_restore"} {
+		if _, err := exec.LookPath(binary); err != nil {
+			t.Skipf("%s isn't in PATH", binary)
+		}
+	}
+
+	b := backup.NewBackup("", "")
+	ctx := context.Background()
+	directory := t.TempDir()
+
+	connection, db := newDatabase(t)
+	if _, err := db.Exec(postgres.Schema); err != nil {
+		t.Fatalf("failed to create the schema, %v", err)
+	}
+
+	base_directory := t.TempDir()
+	fileRepo, err := file.NewFileRepository(db, base_directory)
+	if err != nil {
+		t.Fatalf("failed to create file repo, %v", err)
+	}
+
+	contents := make(map[entities.FileID]string)
+	newFile := func() {
+		c := helper.RandomString(64)
+		file_id, err := fileRepo.NewFile(ctx, strings.NewReader(c), "mp4")
+		if err != nil {
+			t.Fatalf("FileRepository.NewFile() error = %v", err)
+		}
+		contents[file_id] = c
+	}
+
+	newFile()
+	first, err := b.Create(ctx, db, connection, base_directory, directory)
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	// backups are named after the second they're taken in
+	time.Sleep(time.Until(first.Created.Truncate(time.Second).Add(time.Second)))
+
+	newFile()
+	second, err := b.Create(ctx, db, connection, base_directory, directory)
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	if second.Previous != first.Name || len(first.Files) != 1 || len(second.Files) != 1 {
+		t.Fatalf("Create() took %s holding %d files and %s holding %d files building on %q, want a file each, the second building on the first",
+			first.Name, len(first.Files), second.Name, len(second.Files), second.Previous)
+	}
+
+	restored_connection, restored_db := newDatabase(t)
+	restored_directory := filepath.Join(t.TempDir(), "storage")
+	if _, err := b.Restore(ctx, restored_db, restored_connection, restored_directory, directory, ""); err != nil {
+		t.Fatalf("Restore() error = %v", err)
+	}
+
+	restoredRepo, err := file.NewFileRepository(restored_db, restored_directory)
+	if err != nil {
+		t.Fatalf("failed to create file repo, %v", err)
+	}
+
+	for file_id, want := range contents {
+		f, err := restoredRepo.GetFile(ctx, file_id)
+		if err != nil {
+			t.Fatalf("FileRepository.GetFile() of restored file %d error = %v", file_id, err)
+		}
+
+		got, err := os.ReadFile(f.PathAbsolute)
+		if err != nil {
+			t.Fatalf("failed to read restored file %d, %v", file_id, err)
+		}
+
+		if string(got) != want {
+			t.Errorf("restored file %d = %q, want %q", file_id, got, want)
+		}
+	}
+}
@@ -0,0 +1,272 @@
+package character
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/dtbead/wc-maps-archive/internal/entities"
+	"github.com/dtbead/wc-maps-archive/internal/storage/postgres/queries"
+)
+
+type CharacterRepository struct {
+	db *sql.DB
+	q  *queries.Queries
+}
+
+func NewCharacterRepository(db *sql.DB) *CharacterRepository {
+	return &CharacterRepository{
+		db: db,
+		q:  queries.New(db),
+	}
+}
+
+// NewCharacter stores character together with its aliases.
+func (c CharacterRepository) NewCharacter(ctx context.Context, character *entities.Character) (character_id int, err error) {
+	if character == nil {
+		return 0, entities.ErrorInvalidCharacterPtr
+	}
+
+	tx, err := c.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+	q := c.q.WithTx(tx)
+
+	id, err := q.NewCharacter(ctx, queries.NewCharacterParams{
+		Name:       character.Name,
+		Series:     character.Series,
+		IsOriginal: character.IsOriginal,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	for _, alias := range character.Aliases {
+		err = q.NewCharacterAlias(ctx, queries.NewCharacterAliasParams{
+			CharacterID: id,
+			Name:        alias.Name,
+			Stage:       queries.Characternamestage(alias.Stage.ToString()),
+		})
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return 0, err
+	}
+
+	return int(id), nil
+}
+
+// UpdateCharacter updates the name, series and original flag of character. Its aliases are left alone.
+func (c CharacterRepository) UpdateCharacter(ctx context.Context, character *entities.Character) (err error) {
+	if character == nil {
+		return entities.ErrorInvalidCharacterPtr
+	}
+
+	rows, err := c.q.UpdateCharacter(ctx, queries.UpdateCharacterParams{
+		ID:         int32(character.ID),
+		Name:       character.Name,
+		Series:     character.Series,
+		IsOriginal: character.IsOriginal,
+	})
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+func (c CharacterRepository) DeleteCharacter(ctx context.Context, character_id int) (err error) {
+	rows, err := c.q.DeleteCharacter(ctx, int32(character_id))
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+func (c CharacterRepository) GetCharacter(ctx context.Context, character_id int) (character *entities.Character, err error) {
+	res, err := c.q.GetCharacter(ctx, int32(character_id))
+	if err != nil {
+		return nil, err
+	}
+
+	return c.toCharacter(ctx, res)
+}
+
+// NewCharacterAlias adds alias to character_id, or changes its stage if character_id already goes by alias.Name.
+func (c CharacterRepository) NewCharacterAlias(ctx context.Context, character_id int, alias entities.CharacterAlias) (err error) {
+	return c.q.NewCharacterAlias(ctx, queries.NewCharacterAliasParams{
+		CharacterID: int32(character_id),
+		Name:        alias.Name,
+		Stage:       queries.Characternamestage(alias.Stage.ToString()),
+	})
+}
+
+func (c CharacterRepository) DeleteCharacterAlias(ctx context.Context, character_id int, name string) (err error) {
+	rows, err := c.q.DeleteCharacterAlias(ctx, queries.DeleteCharacterAliasParams{
+		CharacterID: int32(character_id),
+		Name:        name,
+	})
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// FindCharacters returns every character going by name, either as its name or any of its aliases. Names are matched
+// case insensitively, and more than one character may share a name.
+func (c CharacterRepository) FindCharacters(ctx context.Context, name string) (characters []entities.Character, err error) {
+	res, err := c.q.FindCharacters(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.toCharacters(ctx, res)
+}
+
+func (c CharacterRepository) GetSeriesCharacters(ctx context.Context, series string) (characters []entities.Character, err error) {
+	res, err := c.q.GetSeriesCharacters(ctx, series)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.toCharacters(ctx, res)
+}
+
+func (c CharacterRepository) TagProjectCharacter(ctx context.Context, uuid entities.ProjectUUID, character_id int) (err error) {
+	return c.q.TagProjectCharacter(ctx, queries.TagProjectCharacterParams{
+		Uuid:        string(uuid),
+		CharacterID: int32(character_id),
+	})
+}
+
+func (c CharacterRepository) UntagProjectCharacter(ctx context.Context, uuid entities.ProjectUUID, character_id int) (err error) {
+	rows, err := c.q.UntagProjectCharacter(ctx, queries.UntagProjectCharacterParams{
+		Uuid:        string(uuid),
+		CharacterID: int32(character_id),
+	})
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+func (c CharacterRepository) GetProjectCharacters(ctx context.Context, uuid entities.ProjectUUID) (characters []entities.Character, err error) {
+	res, err := c.q.GetProjectCharacters(ctx, string(uuid))
+	if err != nil {
+		return nil, err
+	}
+
+	return c.toCharacters(ctx, res)
+}
+
+func (c CharacterRepository) TagPartCharacter(ctx context.Context, part_id int64, character_id int) (err error) {
+	return c.q.TagPartCharacter(ctx, queries.TagPartCharacterParams{
+		PartID:      part_id,
+		CharacterID: int32(character_id),
+	})
+}
+
+func (c CharacterRepository) UntagPartCharacter(ctx context.Context, part_id int64, character_id int) (err error) {
+	rows, err := c.q.UntagPartCharacter(ctx, queries.UntagPartCharacterParams{
+		PartID:      part_id,
+		CharacterID: int32(character_id),
+	})
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+func (c CharacterRepository) GetPartCharacters(ctx context.Context, part_id int64) (characters []entities.Character, err error) {
+	res, err := c.q.GetPartCharacters(ctx, part_id)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.toCharacters(ctx, res)
+}
+
+// GetCharacterProjects returns every project character_id has been tagged in, either directly or through one of its
+// parts, in the order they were archived.
+func (c CharacterRepository) GetCharacterProjects(ctx context.Context, character_id int) (uuids []entities.ProjectUUID, err error) {
+	res, err := c.q.GetCharacterProjects(ctx, int32(character_id))
+	if err != nil {
+		return nil, err
+	}
+
+	uuids = make([]entities.ProjectUUID, 0, len(res))
+	for _, v := range res {
+		uuids = append(uuids, entities.ProjectUUID(v))
+	}
+
+	return uuids, nil
+}
+
+// toCharacter converts res to an entities.Character, looking up its aliases along the way.
+func (c CharacterRepository) toCharacter(ctx context.Context, res queries.Character) (*entities.Character, error) {
+	aliases, err := c.q.GetCharacterAliases(ctx, res.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	character := &entities.Character{
+		ID:         int(res.ID),
+		Name:       res.Name,
+		Series:     res.Series,
+		IsOriginal: res.IsOriginal,
+		Aliases:    make([]entities.CharacterAlias, 0, len(aliases)),
+	}
+
+	for _, v := range aliases {
+		stage, err := entities.NewCharacterNameStage(string(v.Stage))
+		if err != nil {
+			return nil, err
+		}
+
+		character.Aliases = append(character.Aliases, entities.CharacterAlias{Name: v.Name, Stage: stage})
+	}
+
+	return character, nil
+}
+
+func (c CharacterRepository) toCharacters(ctx context.Context, res []queries.Character) ([]entities.Character, error) {
+	characters := make([]entities.Character, 0, len(res))
+	for _, v := range res {
+		character, err := c.toCharacter(ctx, v)
+		if err != nil {
+			return nil, err
+		}
+		characters = append(characters, *character)
+	}
+
+	return characters, nil
+}
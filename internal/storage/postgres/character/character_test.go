@@ -0,0 +1,148 @@
+package character_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dtbead/wc-maps-archive/internal/entities"
+	"github.com/dtbead/wc-maps-archive/internal/helper"
+	helper_test "github.com/dtbead/wc-maps-archive/internal/helper/testing"
+	"github.com/dtbead/wc-maps-archive/internal/storage/postgres/character"
+	"github.com/dtbead/wc-maps-archive/internal/storage/postgres/project"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestCharacterRepository(t *testing.T) {
+	db := helper_test.NewDatabase(&helper_test.DefaultConnection)
+	defer db.Close()
+
+	characterRepo := character.NewCharacterRepository(db)
+	projectRepo := project.NewProjectRepository(db)
+	ctx := context.Background()
+
+	firestar := entities.Character{
+		Name:   "Firestar",
+		Series: "Warriors",
+		Aliases: []entities.CharacterAlias{
+			{Name: "Rusty", Stage: entities.NameStageOther},
+			{Name: "Firestar", Stage: entities.NameStageLeader},
+			{Name: "Firepaw", Stage: entities.NameStageApprentice},
+			{Name: "Fireheart", Stage: entities.NameStageWarrior},
+		},
+	}
+
+	var err error
+	firestar.ID, err = characterRepo.NewCharacter(ctx, &firestar)
+	if err != nil {
+		t.Fatalf("CharacterRepository.NewCharacter() error = %v", err)
+	}
+
+	oc := entities.Character{Name: "Fireheart", Series: "Dawn of the Clans", IsOriginal: true}
+	oc.ID, err = characterRepo.NewCharacter(ctx, &oc)
+	if err != nil {
+		t.Fatalf("CharacterRepository.NewCharacter() error = %v", err)
+	}
+	oc.Aliases = []entities.CharacterAlias{}
+
+	// aliases come in the order of the character's life
+	firestar.Aliases = []entities.CharacterAlias{
+		{Name: "Firepaw", Stage: entities.NameStageApprentice},
+		{Name: "Fireheart", Stage: entities.NameStageWarrior},
+		{Name: "Firestar", Stage: entities.NameStageLeader},
+		{Name: "Rusty", Stage: entities.NameStageOther},
+	}
+
+	got, err := characterRepo.FindCharacters(ctx, "rusty")
+	if err != nil {
+		t.Fatalf("CharacterRepository.FindCharacters() error = %v", err)
+	}
+
+	if !cmp.Equal(got, []entities.Character{firestar}) {
+		t.Errorf("got diff %s", cmp.Diff(got, []entities.Character{firestar}))
+	}
+
+	// one character's alias may well be another's name
+	got, err = characterRepo.FindCharacters(ctx, "Fireheart")
+	if err != nil {
+		t.Fatalf("CharacterRepository.FindCharacters() error = %v", err)
+	}
+
+	if !cmp.Equal(got, []entities.Character{oc, firestar}) {
+		t.Errorf("got diff %s", cmp.Diff(got, []entities.Character{oc, firestar}))
+	}
+
+	if err := characterRepo.NewCharacterAlias(ctx, oc.ID, entities.CharacterAlias{Name: "Firekit", Stage: entities.NameStageKit}); err != nil {
+		t.Fatalf("CharacterRepository.NewCharacterAlias() error = %v", err)
+	}
+
+	if err := characterRepo.DeleteCharacterAlias(ctx, firestar.ID, "Rusty"); err != nil {
+		t.Fatalf("CharacterRepository.DeleteCharacterAlias() error = %v", err)
+	}
+
+	uuid, err := projectRepo.NewProject(ctx, &entities.Project{
+		UUID:        helper.RandomUUID(),
+		ProjectType: entities.ProjectMultiAnimation,
+	})
+	if err != nil {
+		t.Fatalf("failed to create mock project, %v", err)
+	}
+
+	part_id, err := projectRepo.NewProjectPart(ctx, &entities.ProjectPart{ProjectUUID: uuid, Number: 1})
+	if err != nil {
+		t.Fatalf("failed to create mock part, %v", err)
+	}
+
+	if err := characterRepo.TagProjectCharacter(ctx, uuid, firestar.ID); err != nil {
+		t.Fatalf("CharacterRepository.TagProjectCharacter() error = %v", err)
+	}
+
+	for _, character_id := range []int{firestar.ID, oc.ID} {
+		if err := characterRepo.TagPartCharacter(ctx, part_id, character_id); err != nil {
+			t.Fatalf("CharacterRepository.TagPartCharacter() error = %v", err)
+		}
+	}
+
+	characters, err := characterRepo.GetPartCharacters(ctx, part_id)
+	if err != nil {
+		t.Fatalf("CharacterRepository.GetPartCharacters() error = %v", err)
+	}
+
+	if len(characters) != 2 {
+		t.Errorf("CharacterRepository.GetPartCharacters() = %v, want 2 characters", characters)
+	}
+
+	// tagged in both the project and one of its parts, the project is still listed once
+	uuids, err := characterRepo.GetCharacterProjects(ctx, firestar.ID)
+	if err != nil {
+		t.Fatalf("CharacterRepository.GetCharacterProjects() error = %v", err)
+	}
+
+	if !cmp.Equal(uuids, []entities.ProjectUUID{uuid}) {
+		t.Errorf("CharacterRepository.GetCharacterProjects() = %v, want %v", uuids, []entities.ProjectUUID{uuid})
+	}
+
+	uuids, err = characterRepo.GetCharacterProjects(ctx, oc.ID)
+	if err != nil {
+		t.Fatalf("CharacterRepository.GetCharacterProjects() error = %v", err)
+	}
+
+	if !cmp.Equal(uuids, []entities.ProjectUUID{uuid}) {
+		t.Errorf("CharacterRepository.GetCharacterProjects() = %v, want %v", uuids, []entities.ProjectUUID{uuid})
+	}
+
+	if err := characterRepo.UntagPartCharacter(ctx, part_id, oc.ID); err != nil {
+		t.Errorf("CharacterRepository.UntagPartCharacter() error = %v", err)
+	}
+
+	if err := characterRepo.UntagProjectCharacter(ctx, uuid, oc.ID); err == nil {
+		t.Errorf("CharacterRepository.UntagProjectCharacter() of an untagged character error = %v, wantErr %v", err, true)
+	}
+
+	if err := characterRepo.DeleteCharacter(ctx, oc.ID); err != nil {
+		t.Errorf("CharacterRepository.DeleteCharacter() error = %v", err)
+	}
+
+	if _, err := characterRepo.GetCharacter(ctx, oc.ID); err == nil {
+		t.Errorf("CharacterRepository.GetCharacter() of a deleted character error = %v, wantErr %v", err, true)
+	}
+}
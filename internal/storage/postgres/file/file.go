@@ -4,8 +4,14 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
 	"io"
+	"io/fs"
 	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
 
 	"github.com/dtbead/wc-maps-archive/internal/entities"
 	file_helper "github.com/dtbead/wc-maps-archive/internal/helper/file"
@@ -30,46 +36,78 @@ func NewFileRepository(db *sql.DB, base_directory string) (*FileRepository, erro
 	}, nil
 }
 
-// entities.File.Hashes gets ignored implicitly, with NewFile using file io.Reader to generate hashes. TODO: add check whether
-// file already exists in database, and in folder
+// stagingDirectory is where files get written to before being moved to their final BuildPath location. It lives inside
+// the storage root so that moving a file into place is a single rename on the same filesystem.
+const stagingDirectory = ".staging"
+
+// sidecarExtension is appended to the path of a file to get the path of its sidecar, which describes it well enough for
+// the database to be rebuilt from the storage root.
+const sidecarExtension = ".json"
+
+// NewFile stores the contents of file in a single pass, hashing it while it gets written to a staging file, and renames
+// it into place once it has been inserted into the database. file does not need to be seekable. Files returned by
+// NewTempFile have already been hashed while being written to, and are moved into place without being read again.
 func (f FileRepository) NewFile(ctx context.Context, file io.Reader, extension string) (file_id entities.FileID, err error) {
 	if file == nil {
 		return entities.InvalidFileID, entities.ErrorInvalidFilePtr
 	}
 
-	// file_helper.ResetFileSeek is unable to catch our embedded *os.File struct, so we'll handle it here instead.
-	if t, ok := file.(tempFile); ok {
-		t.ResetFileSeek()
-	} else {
-		file_helper.ResetFileSeek(file)
-	}
+	staged, ok := file.(*stagingFile)
+	if !ok {
+		staged, err = f.newStagingFile(ctx)
+		if err != nil {
+			return entities.InvalidFileID, err
+		}
+		defer staged.Close()
 
-	// calculate file hash
-	hashes, read, err := file_helper.GetHash(file)
-	if err != nil {
-		return entities.InvalidFileID, err
+		_, err = io.Copy(staged, file)
+		if err != nil {
+			return entities.InvalidFileID, err
+		}
 	}
 
-	// file_helper.GetHash will only return errors on file reading errors, but not when reading an empty file.
+	return f.commitStagingFile(ctx, staged, extension)
+}
+
+// commitStagingFile inserts staged into the database and moves it to its BuildPath location.
+func (f FileRepository) commitStagingFile(ctx context.Context, staged *stagingFile, extension string) (file_id entities.FileID, err error) {
+	read := staged.hash.Written()
 	if read < 16 {
 		return entities.InvalidFileID, errors.New("read less than 16 bytes from file io.Reader")
 	}
 
+	// make sure the file is fully on disk before anything in the database references it
+	err = staged.file.Sync()
+	if err != nil {
+		return entities.InvalidFileID, err
+	}
+
+	hashes := staged.hash.Hashes()
 	path_relative := file_helper.BuildPath(hashes.SHA256, extension)
 	path_absolute := f.baseDirectory + "/" + path_relative
-	if file_helper.DoesPathExist(path_absolute) {
-		return entities.InvalidFileID, errors.New("path already exists")
+
+	// record our intent to add path before touching the storage root, so that a crash before the "file" row gets
+	// committed can be rolled back by Recover.
+	intent_id, err := f.q.NewFileIntent(ctx, queries.NewFileIntentParams{
+		Action: queries.FileintentactionAdd,
+		Path:   path_relative,
+	})
+	if err != nil {
+		return entities.InvalidFileID, err
 	}
 
 	// only begin tx at this point to not lock database when hashing our file
-	tx, err := f.db.Begin()
+	tx, err := f.db.BeginTx(ctx, nil)
 	if err != nil {
-		return entities.InvalidFileID, err
+		return entities.InvalidFileID, errors.Join(err, f.q.DeleteFileIntent(ctx, intent_id))
 	}
 	defer tx.Rollback()
-	f.q = f.q.WithTx(tx)
+	q := f.q.WithTx(tx)
 
-	r, err := f.q.NewFile(ctx, queries.NewFileParams{
+	// the "file" row is inserted before the file is moved into place, so that of two ingests of the same contents the
+	// unique constraint on its path lets only one through, while the other waits for it to finish and then fails
+	// without having touched the storage root.
+	r, err := q.NewFile(ctx, queries.NewFileParams{
 		Path:      path_relative,
 		Extension: extension,
 		Md5:       hashes.MD5,
@@ -78,44 +116,166 @@ func (f FileRepository) NewFile(ctx context.Context, file io.Reader, extension s
 		Filesize:  read,
 	})
 	if err != nil {
-		return entities.InvalidFileID, err
+		tx.Rollback()
+		return entities.InvalidFileID, errors.Join(err, f.q.DeleteFileIntent(ctx, intent_id))
 	}
 
-	// file_helper.ResetFileSeek is unable to catch our embedded *os.File struct, so we'll handle it here instead x2.
-	if t, ok := file.(tempFile); ok {
-		t.ResetFileSeek()
-	} else {
-		file_helper.ResetFileSeek(file)
+	// a file at path without a "file" row isn't ours to replace
+	if file_helper.DoesPathExist(path_absolute) {
+		tx.Rollback()
+		return entities.InvalidFileID, errors.Join(errors.New("path already exists"), f.q.DeleteFileIntent(ctx, intent_id))
 	}
 
-	err = file_helper.Copy(path_absolute, file)
+	err = file_helper.Move(staged.Name(), path_absolute)
+	if err == nil {
+		err = q.DeleteFileIntent(ctx, intent_id)
+	}
 	if err != nil {
-		return entities.InvalidFileID, errors.Join(err, os.Remove(path_absolute))
+		// path is still held by the uncommitted row, so whatever got moved there can only be ours
+		err = errors.Join(err, removeIfExists(path_absolute))
+		tx.Rollback()
+		return entities.InvalidFileID, errors.Join(err, f.q.DeleteFileIntent(ctx, intent_id))
 	}
 
 	err = tx.Commit()
 	if err != nil {
-		return entities.InvalidFileID, err
+		return entities.InvalidFileID, errors.Join(err, f.resolveFileIntent(ctx, intent_id, path_relative))
 	}
 
 	return entities.FileID(r), nil
 }
 
+// DeleteFile deletes the "file" row of file_id together with recording an intent to delete its path, and only
+// then removes the file from the storage root. If removing the file fails, the intent is left for Recover to finish.
 func (f FileRepository) DeleteFile(ctx context.Context, file_id entities.FileID) (err error) {
+	unlock, err := f.lockShared(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { err = errors.Join(err, unlock()) }()
+
 	meta, err := f.GetFile(ctx, file_id)
 	if err != nil {
 		return err
 	}
 
-	err = os.Remove(f.baseDirectory + "/" + meta.PathRelative)
+	tx, err := f.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	q := f.q.WithTx(tx)
+
+	err = q.DeleteFileByID(ctx, int64(file_id))
+	if err != nil {
+		return err
+	}
+
+	intent_id, err := q.NewFileIntent(ctx, queries.NewFileIntentParams{
+		Action: queries.FileintentactionDelete,
+		Path:   meta.PathRelative,
+	})
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit()
 	if err != nil {
 		return err
 	}
 
-	err = f.q.DeleteFileByID(ctx, int64(file_id))
+	return f.resolveFileIntent(ctx, intent_id, meta.PathRelative)
+}
+
+// Recover resolves every pending file intent left behind by a crash, so that the "file" table and the storage root
+// agree with each other again. Pending adds are rolled back and pending deletes are rolled forward. Any leftover
+// staging files are removed as well. Files are staged and intents left pending while holding the storage root lock
+// shared, so Recover returns entities.ErrorStorageInUse without touching anything if it can't take the lock for itself,
+// as another process could still be working on them.
+func (f FileRepository) Recover(ctx context.Context) (err error) {
+	conn, err := f.db.Conn(ctx)
 	if err != nil {
 		return err
 	}
+	defer conn.Close()
+	q := queries.New(conn)
+
+	locked, err := q.TryLockStorage(ctx)
+	if err != nil {
+		return err
+	}
+	if !locked {
+		return entities.ErrorStorageInUse
+	}
+	defer func() { err = errors.Join(err, q.UnlockStorage(context.WithoutCancel(ctx))) }()
+
+	intents, err := f.q.GetFileIntents(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, intent := range intents {
+		err = errors.Join(err, f.resolveFileIntent(ctx, intent.ID, intent.Path))
+	}
+	if err != nil {
+		return err
+	}
+
+	entries, err := os.ReadDir(f.baseDirectory + "/" + stagingDirectory)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+
+	for _, entry := range entries {
+		err = errors.Join(err, removeIfExists(f.baseDirectory+"/"+stagingDirectory+"/"+entry.Name()))
+	}
+
+	return err
+}
+
+// lockShared takes the storage root lock shared, keeping Recover from resolving anything until unlock is called. The
+// lock belongs to a database session, so a connection is held on to until then.
+func (f FileRepository) lockShared(ctx context.Context) (unlock func() error, err error) {
+	conn, err := f.db.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	q := queries.New(conn)
+
+	err = q.LockStorageShared(ctx)
+	if err != nil {
+		return nil, errors.Join(err, conn.Close())
+	}
+
+	return func() error {
+		return errors.Join(q.UnlockStorageShared(context.Background()), conn.Close())
+	}, nil
+}
+
+// resolveFileIntent finishes a pending file intent. An add intent is rolled back and a delete intent is rolled
+// forward, which in both cases means removing path from the storage root, unless a "file" row still references it.
+// The intent is only cleared once the storage root has been brought in line.
+func (f FileRepository) resolveFileIntent(ctx context.Context, intent_id int64, path_relative string) error {
+	exists, err := f.q.GetFileExistsByPath(ctx, path_relative)
+	if err != nil {
+		return err
+	}
+
+	if !exists {
+		err = errors.Join(removeIfExists(f.baseDirectory+"/"+path_relative), removeIfExists(f.baseDirectory+"/"+path_relative+sidecarExtension))
+		if err != nil {
+			return err
+		}
+	}
+
+	return f.q.DeleteFileIntent(ctx, intent_id)
+}
+
+func removeIfExists(path string) error {
+	err := os.Remove(path)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
 
 	return nil
 }
@@ -139,26 +299,244 @@ func (f FileRepository) GetFile(ctx context.Context, file_id entities.FileID) (f
 	}, nil
 }
 
-// NewTempFile returns a temporary file to write/read to. Closing file will close further access
-// to the file, and delete it.
+// GetFileIDBySHA256 returns the id of the file whose contents hash to sha256, or sql.ErrNoRows if none does.
+func (f FileRepository) GetFileIDBySHA256(ctx context.Context, sha256 []byte) (file_id entities.FileID, err error) {
+	id, err := f.q.GetFileIDBySHA256(ctx, sha256)
+	if err != nil {
+		return entities.InvalidFileID, err
+	}
+
+	return entities.FileID(id), nil
+}
+
+// AdoptFile inserts the file already at path_relative within the storage root, as is the case when rebuilding the
+// database out of the storage root. path_relative has to be the BuildPath of its contents, or
+// entities.ErrorHashMismatch is returned, which is also the case for anything else which ended up in the storage root.
+// A path with a pending file intent belongs to a file still being added or deleted, so entities.ErrorFilePending is
+// returned for it instead of adopting it.
+func (f FileRepository) AdoptFile(ctx context.Context, path_relative string) (file_id entities.FileID, err error) {
+	if path.IsAbs(path_relative) || path.Clean(path_relative) != path_relative || strings.HasPrefix(path_relative, "../") {
+		return entities.InvalidFileID, fmt.Errorf("invalid path %q", path_relative)
+	}
+	extension := strings.TrimPrefix(path.Ext(path_relative), ".")
+
+	file, err := os.Open(f.baseDirectory + "/" + path_relative)
+	if err != nil {
+		return entities.InvalidFileID, err
+	}
+	defer file.Close()
+
+	hashes, read, err := file_helper.GetHash(file)
+	if err != nil {
+		return entities.InvalidFileID, err
+	}
+
+	if file_helper.BuildPath(hashes.SHA256, extension) != path_relative {
+		return entities.InvalidFileID, fmt.Errorf("%w, %s isn't where its contents would be stored", entities.ErrorHashMismatch, path_relative)
+	}
+
+	// an add intent is recorded before its file is moved into place, so whatever is at a path without one by now isn't
+	// an ingest that's yet to commit
+	pending, err := f.q.GetFileIntentExistsByPath(ctx, path_relative)
+	if err != nil {
+		return entities.InvalidFileID, err
+	}
+	if pending {
+		return entities.InvalidFileID, fmt.Errorf("%w, %s", entities.ErrorFilePending, path_relative)
+	}
+
+	r, err := f.q.NewFile(ctx, queries.NewFileParams{
+		Path:      path_relative,
+		Extension: extension,
+		Md5:       hashes.MD5,
+		Sha1:      hashes.SHA1,
+		Sha256:    hashes.SHA256,
+		Filesize:  read,
+	})
+	if err != nil {
+		return entities.InvalidFileID, err
+	}
+
+	return entities.FileID(r), nil
+}
+
+// GetStoredPaths returns the path of every file within the storage root, relative to it, whether the database knows of
+// it or not. Sidecars and staging files aren't included, and neither are paths with a pending file intent, as their
+// file is still being added or deleted.
+func (f FileRepository) GetStoredPaths(ctx context.Context) (paths []string, err error) {
+	err = filepath.WalkDir(f.baseDirectory, func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		if d.IsDir() && d.Name() == stagingDirectory {
+			return filepath.SkipDir
+		}
+
+		if d.IsDir() || strings.HasSuffix(name, sidecarExtension) {
+			return nil
+		}
+
+		rel, err := filepath.Rel(f.baseDirectory, name)
+		if err != nil {
+			return err
+		}
+
+		paths = append(paths, filepath.ToSlash(rel))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// intents are only looked up once the walk is done, as a file being added is moved into place after its intent
+	// is recorded, and only loses it once its "file" row is committed
+	intents, err := f.q.GetFileIntents(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	pending := make(map[string]bool, len(intents))
+	for _, intent := range intents {
+		pending[intent.Path] = true
+	}
+
+	return slices.DeleteFunc(paths, func(p string) bool { return pending[p] }), nil
+}
+
+// WriteSidecar replaces the sidecar of file_id with sidecar. It's written to the staging directory first, so that a
+// crash never leaves a partially written sidecar behind.
+func (f FileRepository) WriteSidecar(ctx context.Context, file_id entities.FileID, sidecar []byte) (err error) {
+	meta, err := f.GetFile(ctx, file_id)
+	if err != nil {
+		return err
+	}
+
+	staged, err := f.newStagingFile(ctx)
+	if err != nil {
+		return err
+	}
+	defer staged.Close()
+
+	_, err = staged.Write(sidecar)
+	if err == nil {
+		err = staged.file.Sync()
+	}
+	if err != nil {
+		return err
+	}
+
+	return file_helper.Move(staged.Name(), meta.PathAbsolute+sidecarExtension)
+}
+
+// ReadSidecar returns the sidecar of the file at path_relative within the storage root, or an error wrapping
+// fs.ErrNotExist if it hasn't got one.
+func (f FileRepository) ReadSidecar(ctx context.Context, path_relative string) (sidecar []byte, err error) {
+	return os.ReadFile(f.baseDirectory + "/" + path_relative + sidecarExtension)
+}
+
+func (f FileRepository) NewFileVideo(ctx context.Context, file_id entities.FileID, video *entities.Video) (err error) {
+	if video == nil {
+		return entities.ErrorInvalidVideoPtr
+	}
+
+	return f.q.NewFileVideo(ctx, queries.NewFileVideoParams{
+		FileID:     int64(file_id),
+		Duration:   int32(video.Duration),
+		Width:      video.Width,
+		Height:     video.Height,
+		Fps:        sql.NullInt16{Int16: video.Fps, Valid: video.Fps > 0},
+		VideoCodec: sql.NullString{String: video.VideoCodec, Valid: video.VideoCodec != ""},
+		AudioCodec: sql.NullString{String: video.AudioCodec, Valid: video.AudioCodec != ""},
+	})
+}
+
+func (f FileRepository) GetFileVideo(ctx context.Context, file_id entities.FileID) (video *entities.Video, err error) {
+	res, err := f.q.GetFileVideo(ctx, int64(file_id))
+	if err != nil {
+		return nil, err
+	}
+
+	return &entities.Video{
+		VideoCodec: res.VideoCodec.String,
+		AudioCodec: res.AudioCodec.String,
+		Duration:   int(res.Duration),
+		Width:      res.Width,
+		Height:     res.Height,
+		Fps:        res.Fps.Int16,
+	}, nil
+}
+
+// NewTempFile returns a temporary file to write/read to inside the storage root. Everything written to file gets hashed
+// along the way, so that passing it to NewFile afterwards moves it into place without reading it again. Closing file
+// will close further access to the file, and delete it if it hasn't been stored with NewFile.
 func (f FileRepository) NewTempFile(ctx context.Context) (file io.ReadWriteCloser, err error) {
-	tmp, err := os.CreateTemp("", "")
+	return f.newStagingFile(ctx)
+}
+
+// newStagingFile creates a file inside the staging directory, holding the storage root lock shared until it's closed.
+func (f FileRepository) newStagingFile(ctx context.Context) (*stagingFile, error) {
+	unlock, err := f.lockShared(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	return tempFile{tmp}, nil
+	directory := f.baseDirectory + "/" + stagingDirectory
+	err = os.MkdirAll(directory, 0775)
+	if err != nil {
+		return nil, errors.Join(err, unlock())
+	}
+
+	tmp, err := os.CreateTemp(directory, "*.tmp")
+	if err != nil {
+		return nil, errors.Join(err, unlock())
+	}
+
+	h := file_helper.NewHashWriter()
+	return &stagingFile{
+		file:   tmp,
+		hash:   h,
+		w:      io.MultiWriter(tmp, h),
+		unlock: unlock,
+	}, nil
 }
 
-type tempFile struct {
-	*os.File
+// stagingFile is a file inside the staging directory which hashes everything written to it. It deliberately doesn't
+// embed *os.File, as io.Copy would otherwise pick up (*os.File).ReadFrom and write around the hash.
+type stagingFile struct {
+	file   *os.File
+	hash   *file_helper.HashWriter
+	w      io.Writer
+	unlock func() error
 }
 
-func (t tempFile) ResetFileSeek() {
-	file_helper.ResetFileSeek(t.File)
+func (s *stagingFile) Read(p []byte) (n int, err error) {
+	return s.file.Read(p)
 }
 
-func (t tempFile) Close() error {
-	err := t.File.Close()
-	return errors.Join(err, os.Remove(t.Name()))
+func (s *stagingFile) Write(p []byte) (n int, err error) {
+	return s.w.Write(p)
+}
+
+func (s *stagingFile) Name() string {
+	return s.file.Name()
+}
+
+func (s *stagingFile) Close() error {
+	err := s.file.Close()
+
+	// a staging file which has been stored with NewFile has already been moved out of the staging directory.
+	err = errors.Join(err, removeIfExists(s.file.Name()))
+
+	// closing it again mustn't release a lock taken by someone else
+	if s.unlock != nil {
+		err = errors.Join(err, s.unlock())
+		s.unlock = nil
+	}
+
+	return err
 }
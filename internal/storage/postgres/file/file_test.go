@@ -1,19 +1,26 @@
 package file_test
 
 import (
+	"bytes"
 	"context"
+	"database/sql"
+	"errors"
 	"io"
+	"io/fs"
 	"os"
 	"reflect"
+	"slices"
 	"testing"
 
 	"github.com/dtbead/wc-maps-archive/internal/entities"
+	"github.com/dtbead/wc-maps-archive/internal/helper"
 	helper_file "github.com/dtbead/wc-maps-archive/internal/helper/file"
 	helper_test "github.com/dtbead/wc-maps-archive/internal/helper/testing"
 
 	"github.com/google/go-cmp/cmp"
 
 	"github.com/dtbead/wc-maps-archive/internal/storage/postgres/file"
+	"github.com/dtbead/wc-maps-archive/internal/storage/postgres/queries"
 	_ "github.com/jackc/pgx/v5/stdlib"
 )
 
@@ -157,3 +164,535 @@ func TestFileRepository_GetFile(t *testing.T) {
 		})
 	}
 }
+
+func TestFileRepository_GetFileIDBySHA256(t *testing.T) {
+	db := helper_test.NewDatabase(&helper_test.DefaultConnection)
+	defer db.Close()
+
+	fileRepo, err := file.NewFileRepository(db, t.TempDir())
+	if err != nil {
+		t.Fatalf("failed to create file repo, %v", err)
+	}
+
+	f, err := os.Open("testdata/y_wo8pyoxyk.mkv")
+	if err != nil {
+		t.Fatalf("failed to open test file, %v", err)
+	}
+	defer f.Close()
+
+	file_id, err := fileRepo.NewFile(context.Background(), f, "mkv")
+	if err != nil {
+		t.Fatalf("failed to insert test file, %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		sha256      []byte
+		wantFile_id entities.FileID
+		wantErr     bool
+	}{
+		{"stored file", helper_file.HexStringToByte("6bebd6bfc85e9840e6bb47e1f329b5453afd184fa7fe2d52da3cd46200062ddc"), file_id, false},
+		{"unknown hash", make([]byte, 32), entities.InvalidFileID, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotFile_id, err := fileRepo.GetFileIDBySHA256(context.Background(), tt.sha256)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("FileRepository.GetFileIDBySHA256() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if gotFile_id != tt.wantFile_id {
+				t.Errorf("FileRepository.GetFileIDBySHA256() = %v, want %v", gotFile_id, tt.wantFile_id)
+			}
+		})
+	}
+}
+
+func TestFileRepository_NewFileNonSeekable(t *testing.T) {
+	db := helper_test.NewDatabase(&helper_test.DefaultConnection)
+	defer db.Close()
+
+	fileRepo, err := file.NewFileRepository(db, t.TempDir())
+	if err != nil {
+		t.Fatalf("failed to create file repo, %v", err)
+	}
+
+	f, err := os.Open("testdata/y_wo8pyoxyk.mkv")
+	if err != nil {
+		t.Fatalf("failed to open test file, %v", err)
+	}
+	defer f.Close()
+
+	// hide *os.File behind a plain io.Reader so NewFile can't seek or stat it
+	file_id, err := fileRepo.NewFile(context.Background(), struct{ io.Reader }{f}, "mkv")
+	if err != nil {
+		t.Fatalf("FileRepository.NewFile() error = %v", err)
+	}
+
+	gotFile, err := fileRepo.GetFile(context.Background(), file_id)
+	if err != nil {
+		t.Fatalf("failed to get file, %v", err)
+	}
+
+	wantSHA256 := helper_file.HexStringToByte("6bebd6bfc85e9840e6bb47e1f329b5453afd184fa7fe2d52da3cd46200062ddc")
+	if !cmp.Equal(gotFile.Hashes.SHA256, wantSHA256) || gotFile.Size != 330070 {
+		t.Errorf("got sha256 %x with size %d, want %x with size %d", gotFile.Hashes.SHA256, gotFile.Size, wantSHA256, 330070)
+	}
+
+	if !helper_file.DoesPathExist(gotFile.PathAbsolute) {
+		t.Errorf("file %s was not moved into place", gotFile.PathAbsolute)
+	}
+}
+
+func TestFileRepository_NewTempFile(t *testing.T) {
+	db := helper_test.NewDatabase(&helper_test.DefaultConnection)
+	defer db.Close()
+
+	fileRepo, err := file.NewFileRepository(db, t.TempDir())
+	if err != nil {
+		t.Fatalf("failed to create file repo, %v", err)
+	}
+
+	f, err := os.Open("testdata/y_wo8pyoxyk.mkv")
+	if err != nil {
+		t.Fatalf("failed to open test file, %v", err)
+	}
+	defer f.Close()
+
+	tmp, err := fileRepo.NewTempFile(context.Background())
+	if err != nil {
+		t.Fatalf("FileRepository.NewTempFile() error = %v", err)
+	}
+
+	_, err = io.Copy(tmp, f)
+	if err != nil {
+		t.Fatalf("failed to write to temp file, %v", err)
+	}
+
+	file_id, err := fileRepo.NewFile(context.Background(), tmp, "mkv")
+	if err != nil {
+		t.Fatalf("FileRepository.NewFile() error = %v", err)
+	}
+
+	if err := tmp.Close(); err != nil {
+		t.Errorf("failed to close stored temp file, %v", err)
+	}
+
+	gotFile, err := fileRepo.GetFile(context.Background(), file_id)
+	if err != nil {
+		t.Fatalf("failed to get file, %v", err)
+	}
+
+	wantMD5 := helper_file.HexStringToByte("3e9cb26fede9bd75e406cbb7e6ff81e6")
+	if !cmp.Equal(gotFile.Hashes.MD5, wantMD5) {
+		t.Errorf("got md5 %x, want %x", gotFile.Hashes.MD5, wantMD5)
+	}
+
+	if !helper_file.DoesPathExist(gotFile.PathAbsolute) {
+		t.Errorf("temp file was not moved to %s", gotFile.PathAbsolute)
+	}
+}
+
+func TestFileRepository_Recover(t *testing.T) {
+	db := helper_test.NewDatabase(&helper_test.DefaultConnection)
+	defer db.Close()
+
+	tempDir := t.TempDir()
+	fileRepo, err := file.NewFileRepository(db, tempDir)
+	if err != nil {
+		t.Fatalf("failed to create file repo, %v", err)
+	}
+
+	f, err := os.Open("testdata/y_wo8pyoxyk.mkv")
+	if err != nil {
+		t.Fatalf("failed to open test file, %v", err)
+	}
+	defer f.Close()
+
+	file_id, err := fileRepo.NewFile(context.Background(), f, "mkv")
+	if err != nil {
+		t.Fatalf("failed to insert test file, %v", err)
+	}
+
+	stored, err := fileRepo.GetFile(context.Background(), file_id)
+	if err != nil {
+		t.Fatalf("failed to get test file, %v", err)
+	}
+
+	// simulate a crash after an added file got moved into place, but before its row was committed
+	orphanPath := "ff/orphan.mkv"
+	if err := helper_file.Move(writeTestFile(t), tempDir+"/"+orphanPath); err != nil {
+		t.Fatalf("failed to create orphan file, %v", err)
+	}
+
+	q := queries.New(db)
+	for _, intent := range []queries.NewFileIntentParams{
+		{Action: queries.FileintentactionAdd, Path: orphanPath},
+		{Action: queries.FileintentactionDelete, Path: stored.PathRelative},
+	} {
+		if _, err := q.NewFileIntent(context.Background(), intent); err != nil {
+			t.Fatalf("failed to insert file intent, %v", err)
+		}
+	}
+
+	if err := fileRepo.Recover(context.Background()); err != nil {
+		t.Fatalf("FileRepository.Recover() error = %v", err)
+	}
+
+	if helper_file.DoesPathExist(tempDir + "/" + orphanPath) {
+		t.Errorf("pending add of %s was not rolled back", orphanPath)
+	}
+
+	// stored still has a "file" row, so its pending delete must not remove it from disk
+	if !helper_file.DoesPathExist(stored.PathAbsolute) {
+		t.Errorf("file %s still referenced by the database was removed", stored.PathAbsolute)
+	}
+
+	intents, err := q.GetFileIntents(context.Background())
+	if err != nil {
+		t.Fatalf("failed to get file intents, %v", err)
+	}
+	if len(intents) != 0 {
+		t.Errorf("got %d pending file intents after recovery, want 0", len(intents))
+	}
+}
+
+func TestFileRepository_RecoverInUse(t *testing.T) {
+	db := helper_test.NewDatabase(&helper_test.DefaultConnection)
+	defer db.Close()
+
+	tempDir := t.TempDir()
+	fileRepo, err := file.NewFileRepository(db, tempDir)
+	if err != nil {
+		t.Fatalf("failed to create file repo, %v", err)
+	}
+
+	// another process ingesting holds the storage root lock from a session of its own
+	other, err := sql.Open("pgx", helper_test.NewDsn(helper_test.DefaultConnection))
+	if err != nil {
+		t.Fatalf("failed to open database, %v", err)
+	}
+	defer other.Close()
+
+	conn, err := other.Conn(context.Background())
+	if err != nil {
+		t.Fatalf("failed to connect to database, %v", err)
+	}
+	defer conn.Close()
+
+	if err := queries.New(conn).LockStorageShared(context.Background()); err != nil {
+		t.Fatalf("failed to lock storage root, %v", err)
+	}
+	defer queries.New(conn).UnlockStorageShared(context.Background())
+
+	staged, err := fileRepo.NewTempFile(context.Background())
+	if err != nil {
+		t.Fatalf("FileRepository.NewTempFile() error = %v", err)
+	}
+	defer staged.Close()
+
+	pendingPath := "ff/pending.mkv"
+	if err := helper_file.Move(writeTestFile(t), tempDir+"/"+pendingPath); err != nil {
+		t.Fatalf("failed to create pending file, %v", err)
+	}
+
+	q := queries.New(db)
+	if _, err := q.NewFileIntent(context.Background(), queries.NewFileIntentParams{Action: queries.FileintentactionAdd, Path: pendingPath}); err != nil {
+		t.Fatalf("failed to insert file intent, %v", err)
+	}
+
+	if err := fileRepo.Recover(context.Background()); !errors.Is(err, entities.ErrorStorageInUse) {
+		t.Fatalf("FileRepository.Recover() error = %v, want %v", err, entities.ErrorStorageInUse)
+	}
+
+	// what's pending may still be the other process's, so nothing of it is touched
+	if !helper_file.DoesPathExist(tempDir + "/" + pendingPath) {
+		t.Errorf("pending add of %s was rolled back while the storage root was in use", pendingPath)
+	}
+
+	if entries, err := os.ReadDir(tempDir + "/.staging"); err != nil || len(entries) != 1 {
+		t.Errorf("staging directory holds %v, %v, want the staging file still being written", entries, err)
+	}
+
+	intents, err := q.GetFileIntents(context.Background())
+	if err != nil {
+		t.Fatalf("failed to get file intents, %v", err)
+	}
+	if len(intents) != 1 {
+		t.Errorf("got %d pending file intents, want 1", len(intents))
+	}
+}
+
+// writeTestFile writes a small file outside of any storage root and returns its path.
+func writeTestFile(t *testing.T) string {
+	t.Helper()
+
+	path := t.TempDir() + "/test.mkv"
+	if err := os.WriteFile(path, []byte("not actually a video file"), 0664); err != nil {
+		t.Fatalf("failed to write test file, %v", err)
+	}
+
+	return path
+}
+
+func TestFileRepository_NewFileVideo(t *testing.T) {
+	db := helper_test.NewDatabase(&helper_test.DefaultConnection)
+	defer db.Close()
+
+	fileRepo, err := file.NewFileRepository(db, t.TempDir())
+	if err != nil {
+		t.Fatalf("failed to create file repo, %v", err)
+	}
+
+	f, err := os.Open("testdata/y_wo8pyoxyk.mkv")
+	if err != nil {
+		t.Fatalf("failed to open test file, %v", err)
+	}
+	defer f.Close()
+
+	file_id, err := fileRepo.NewFile(context.Background(), f, "mkv")
+	if err != nil {
+		t.Fatalf("failed to insert test file, %v", err)
+	}
+
+	want := &entities.Video{
+		VideoCodec: "h264",
+		AudioCodec: "opus",
+		Duration:   7,
+		Width:      976,
+		Height:     720,
+		Fps:        30,
+	}
+
+	if err := fileRepo.NewFileVideo(context.Background(), file_id, want); err != nil {
+		t.Fatalf("FileRepository.NewFileVideo() error = %v", err)
+	}
+
+	got, err := fileRepo.GetFileVideo(context.Background(), file_id)
+	if err != nil {
+		t.Fatalf("FileRepository.GetFileVideo() error = %v", err)
+	}
+
+	if !cmp.Equal(got, want) {
+		t.Errorf("got diff %s", cmp.Diff(got, want))
+	}
+
+	if err := fileRepo.NewFileVideo(context.Background(), file_id, nil); err == nil {
+		t.Errorf("FileRepository.NewFileVideo() with nil video error = %v, wantErr %v", err, true)
+	}
+}
+
+func TestFileRepository_AdoptFile(t *testing.T) {
+	db := helper_test.NewDatabase(&helper_test.DefaultConnection)
+	defer db.Close()
+
+	directory := t.TempDir()
+	fileRepo, err := file.NewFileRepository(db, directory)
+	if err != nil {
+		t.Fatalf("failed to create file repo, %v", err)
+	}
+
+	contents, err := os.ReadFile("testdata/y_wo8pyoxyk.mkv")
+	if err != nil {
+		t.Fatalf("failed to read test file, %v", err)
+	}
+
+	sha256 := helper_file.HexStringToByte("6bebd6bfc85e9840e6bb47e1f329b5453afd184fa7fe2d52da3cd46200062ddc")
+	stored := helper_file.BuildPath(sha256, "mkv")
+	// a file moved into place by an ingest which has yet to commit it
+	pending := helper_file.BuildPath(sha256, "webm")
+	for _, path := range []string{stored, pending, "6b/misplaced.mkv"} {
+		if err := helper_file.Copy(directory+"/"+path, bytes.NewReader(contents)); err != nil {
+			t.Fatalf("failed to copy test file, %v", err)
+		}
+	}
+
+	_, err = queries.New(db).NewFileIntent(context.Background(), queries.NewFileIntentParams{Action: queries.FileintentactionAdd, Path: pending})
+	if err != nil {
+		t.Fatalf("failed to insert file intent, %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		path        string
+		wantFile_id entities.FileID
+		wantErr     error
+	}{
+		{"stored file", stored, 1, nil},
+		{"misplaced file", "6b/misplaced.mkv", entities.InvalidFileID, entities.ErrorHashMismatch},
+		{"missing file", "6b/missing.mkv", entities.InvalidFileID, fs.ErrNotExist},
+		{"pending file", pending, entities.InvalidFileID, entities.ErrorFilePending},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotFile_id, err := fileRepo.AdoptFile(context.Background(), tt.path)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("FileRepository.AdoptFile() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if gotFile_id != tt.wantFile_id {
+				t.Errorf("FileRepository.AdoptFile() = %v, want %v", gotFile_id, tt.wantFile_id)
+			}
+		})
+	}
+
+	meta, err := fileRepo.GetFile(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("failed to get adopted file, %v", err)
+	}
+
+	if meta.PathRelative != stored || meta.Extension != "mkv" || meta.Size != int64(len(contents)) {
+		t.Errorf("FileRepository.GetFile() = %+v, want it at %s", meta, stored)
+	}
+
+	paths, err := fileRepo.GetStoredPaths(context.Background())
+	if err != nil {
+		t.Fatalf("FileRepository.GetStoredPaths() error = %v", err)
+	}
+
+	if slices.Contains(paths, pending) {
+		t.Errorf("FileRepository.GetStoredPaths() = %v, want it without %s", paths, pending)
+	}
+}
+
+func TestFileRepository_Sidecar(t *testing.T) {
+	db := helper_test.NewDatabase(&helper_test.DefaultConnection)
+	defer db.Close()
+
+	fileRepo, err := file.NewFileRepository(db, t.TempDir())
+	if err != nil {
+		t.Fatalf("failed to create file repo, %v", err)
+	}
+
+	f, err := os.Open("testdata/y_wo8pyoxyk.mkv")
+	if err != nil {
+		t.Fatalf("failed to open test file, %v", err)
+	}
+	defer f.Close()
+
+	file_id, err := fileRepo.NewFile(context.Background(), f, "mkv")
+	if err != nil {
+		t.Fatalf("failed to insert test file, %v", err)
+	}
+
+	meta, err := fileRepo.GetFile(context.Background(), file_id)
+	if err != nil {
+		t.Fatalf("failed to get test file, %v", err)
+	}
+
+	for _, want := range []string{`{"version": 1}`, `{"version": 2}`} {
+		err = fileRepo.WriteSidecar(context.Background(), file_id, []byte(want))
+		if err != nil {
+			t.Fatalf("FileRepository.WriteSidecar() error = %v", err)
+		}
+
+		got, err := fileRepo.ReadSidecar(context.Background(), meta.PathRelative)
+		if err != nil {
+			t.Fatalf("FileRepository.ReadSidecar() error = %v", err)
+		}
+
+		if string(got) != want {
+			t.Errorf("FileRepository.ReadSidecar() = %s, want %s", got, want)
+		}
+	}
+
+	paths, err := fileRepo.GetStoredPaths(context.Background())
+	if err != nil {
+		t.Fatalf("FileRepository.GetStoredPaths() error = %v", err)
+	}
+
+	if diff := cmp.Diff([]string{meta.PathRelative}, paths); diff != "" {
+		t.Errorf("FileRepository.GetStoredPaths() mismatch (-want +got):\n%s", diff)
+	}
+
+	err = fileRepo.DeleteFile(context.Background(), file_id)
+	if err != nil {
+		t.Fatalf("failed to delete test file, %v", err)
+	}
+
+	_, err = fileRepo.ReadSidecar(context.Background(), meta.PathRelative)
+	if !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("FileRepository.ReadSidecar() of a deleted file, error = %v, want %v", err, fs.ErrNotExist)
+	}
+}
+
+func TestFileRepository_ListFiles(t *testing.T) {
+	db := helper_test.NewDatabase(&helper_test.DefaultConnection)
+	defer db.Close()
+
+	fileRepo, err := file.NewFileRepository(db, t.TempDir())
+	if err != nil {
+		t.Fatalf("failed to create file repo, %v", err)
+	}
+	ctx := context.Background()
+
+	newFile := func(duration int) entities.FileID {
+		file_id, err := fileRepo.NewFile(ctx, bytes.NewReader([]byte(helper.RandomString(64))), "mkv")
+		if err != nil {
+			t.Fatalf("failed to insert test file, %v", err)
+		}
+
+		if duration > 0 {
+			err := fileRepo.NewFileVideo(ctx, file_id, &entities.Video{Duration: duration, Width: 976, Height: 720})
+			if err != nil {
+				t.Fatalf("failed to insert test file video, %v", err)
+			}
+		}
+		return file_id
+	}
+
+	first := newFile(30)
+	second := newFile(0)
+	third := newFile(10)
+	mine := []entities.FileID{first, second, third}
+
+	// files archived before aren't the test's, so every page is walked and only these files are checked
+	list := func(opts entities.ListOptions) (got []entities.FileID) {
+		t.Helper()
+
+		opts.Limit = 1
+		for {
+			file_ids, next_cursor, err := fileRepo.ListFiles(ctx, opts)
+			if err != nil {
+				t.Fatalf("FileRepository.ListFiles() error = %v", err)
+			}
+
+			if len(file_ids) > opts.Limit {
+				t.Fatalf("FileRepository.ListFiles() page = %v, want at most %d files", file_ids, opts.Limit)
+			}
+
+			for _, file_id := range file_ids {
+				if slices.Contains(mine, file_id) {
+					got = append(got, file_id)
+				}
+			}
+
+			if next_cursor == "" {
+				return got
+			}
+			opts.Cursor = next_cursor
+		}
+	}
+
+	tests := []struct {
+		name string
+		opts entities.ListOptions
+		want []entities.FileID
+	}{
+		{"newest first", entities.ListOptions{}, []entities.FileID{third, second, first}},
+		{"oldest first", entities.ListOptions{Ascending: true}, []entities.FileID{first, second, third}},
+		{"shortest first", entities.ListOptions{Sort: entities.ListSortDuration, Ascending: true}, []entities.FileID{second, third, first}},
+		{"longest first", entities.ListOptions{Sort: entities.ListSortDuration}, []entities.FileID{first, third, second}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := list(tt.opts); !cmp.Equal(got, tt.want) {
+				t.Errorf("got diff %s", cmp.Diff(got, tt.want))
+			}
+		})
+	}
+
+	if _, _, err := fileRepo.ListFiles(ctx, entities.ListOptions{Sort: entities.ListSortAnnouncedDate}); !errors.Is(err, entities.ErrorUnsupportedSort) {
+		t.Errorf("FileRepository.ListFiles() by announced date error = %v, want %v", err, entities.ErrorUnsupportedSort)
+	}
+}
@@ -0,0 +1,48 @@
+package file
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/dtbead/wc-maps-archive/internal/entities"
+	"github.com/dtbead/wc-maps-archive/internal/storage/postgres/where"
+)
+
+// fileYoutube joins f, the file, to the youtube video it's a download of as v.
+const fileYoutube = "youtube_file yf JOIN youtube_video v ON v.id = yf.youtube_id WHERE yf.file_id = f.id"
+
+// fileListing pages through files, most recently archived first by default. Files get their ids in the order they
+// were archived in, which is what the archived date sorts by.
+var fileListing = where.Listing{
+	From:   `"file" f`,
+	Select: "f.id",
+	ID:     "f.id",
+	IDType: "bigint",
+	Orders: map[entities.ListSort]where.Order{
+		entities.ListSortDefault:      {Expr: "f.id", Type: "bigint"},
+		entities.ListSortArchivedDate: {Expr: "f.id", Type: "bigint"},
+		entities.ListSortUploadDate:   {Expr: "COALESCE((SELECT min(v.upload_date) FROM " + fileYoutube + "), '-infinity')", Type: "timestamp"},
+		entities.ListSortDuration:     {Expr: "COALESCE((SELECT fv.duration FROM file_video fv WHERE fv.file_id = f.id), -1)::bigint", Type: "bigint"},
+		entities.ListSortViews:        {Expr: "COALESCE((SELECT max(v.view_count) FROM " + fileYoutube + "), -1)::bigint", Type: "bigint"},
+	},
+}
+
+// ListFiles returns a page of every stored file, along with the cursor of the next page.
+func (f FileRepository) ListFiles(ctx context.Context, opts entities.ListOptions) (file_ids []entities.FileID, next_cursor string, err error) {
+	res, next_cursor, err := where.List(ctx, f.db, fileListing, "TRUE", nil, opts)
+	if err != nil {
+		return nil, "", err
+	}
+
+	file_ids = make([]entities.FileID, 0, len(res))
+	for _, v := range res {
+		file_id, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return nil, "", err
+		}
+
+		file_ids = append(file_ids, entities.FileID(file_id))
+	}
+
+	return file_ids, next_cursor, nil
+}
@@ -0,0 +1,103 @@
+package fingerprint
+
+import (
+	"context"
+	"database/sql"
+	"encoding/binary"
+	"errors"
+
+	"github.com/dtbead/wc-maps-archive/internal/entities"
+	"github.com/dtbead/wc-maps-archive/internal/storage/postgres/queries"
+)
+
+type FingerprintRepository struct {
+	db *sql.DB
+	q  *queries.Queries
+}
+
+func NewFingerprintRepository(db *sql.DB) *FingerprintRepository {
+	return &FingerprintRepository{
+		db: db,
+		q:  queries.New(db),
+	}
+}
+
+// NewFingerprint stores fingerprint for file_id, replacing any earlier one.
+func (f FingerprintRepository) NewFingerprint(ctx context.Context, file_id entities.FileID, fingerprint *entities.Fingerprint) (err error) {
+	if fingerprint == nil {
+		return errors.New("nil fingerprint given")
+	}
+
+	return f.q.UpsertFileFingerprint(ctx, queries.UpsertFileFingerprintParams{
+		FileID:        int64(file_id),
+		FrameInterval: fingerprint.Interval,
+		Hashes:        encodeHashes(fingerprint.Hashes),
+	})
+}
+
+func (f FingerprintRepository) GetFingerprint(ctx context.Context, file_id entities.FileID) (fingerprint *entities.Fingerprint, err error) {
+	res, err := f.q.GetFileFingerprint(ctx, int64(file_id))
+	if err != nil {
+		return nil, err
+	}
+
+	return &entities.Fingerprint{
+		Interval: res.FrameInterval,
+		Hashes:   decodeHashes(res.Hashes),
+	}, nil
+}
+
+// GetFingerprintsByDuration returns the fingerprint of every fingerprinted file, keyed by file_id, whose duration is
+// within min_duration and max_duration seconds. The duration of a fingerprint is how long its hashed frames span.
+func (f FingerprintRepository) GetFingerprintsByDuration(ctx context.Context, min_duration, max_duration float64) (fingerprints map[entities.FileID]entities.Fingerprint, err error) {
+	res, err := f.q.GetFileFingerprintsByDuration(ctx, queries.GetFileFingerprintsByDurationParams{
+		MinDuration: min_duration,
+		MaxDuration: max_duration,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	fingerprints = make(map[entities.FileID]entities.Fingerprint, len(res))
+	for _, r := range res {
+		fingerprints[entities.FileID(r.FileID)] = entities.Fingerprint{
+			Interval: r.FrameInterval,
+			Hashes:   decodeHashes(r.Hashes),
+		}
+	}
+
+	return fingerprints, nil
+}
+
+// GetUnfingerprintedFiles returns every video file that hasn't been fingerprinted yet.
+func (f FingerprintRepository) GetUnfingerprintedFiles(ctx context.Context) (file_ids []entities.FileID, err error) {
+	res, err := f.q.GetUnfingerprintedFileIDs(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	file_ids = make([]entities.FileID, len(res))
+	for i, id := range res {
+		file_ids[i] = entities.FileID(id)
+	}
+
+	return file_ids, nil
+}
+
+func encodeHashes(hashes []uint64) []byte {
+	b := make([]byte, 0, len(hashes)*8)
+	for _, h := range hashes {
+		b = binary.BigEndian.AppendUint64(b, h)
+	}
+
+	return b
+}
+
+func decodeHashes(b []byte) []uint64 {
+	hashes := make([]uint64, len(b)/8)
+	for i := range hashes {
+		hashes[i] = binary.BigEndian.Uint64(b[i*8:])
+	}
+
+	return hashes
+}
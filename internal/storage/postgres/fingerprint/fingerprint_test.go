@@ -0,0 +1,92 @@
+package fingerprint_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"slices"
+	"testing"
+
+	"github.com/dtbead/wc-maps-archive/internal/entities"
+	helper_test "github.com/dtbead/wc-maps-archive/internal/helper/testing"
+	"github.com/dtbead/wc-maps-archive/internal/storage/postgres/file"
+	"github.com/dtbead/wc-maps-archive/internal/storage/postgres/fingerprint"
+	"github.com/google/go-cmp/cmp"
+)
+
+// helperInsertFile stores random bytes in fileRepo and returns their file_id. helperInsertFile will implicitly call
+// t.Fatal if storing fails.
+func helperInsertFile(fileRepo *file.FileRepository, t *testing.T) entities.FileID {
+	t.Helper()
+
+	file_id, err := fileRepo.NewFile(context.Background(), bytes.NewReader([]byte(rand.Text())), "mkv")
+	if err != nil {
+		t.Fatalf("failed to insert file to db, %v", err)
+	}
+
+	if err := fileRepo.NewFileVideo(context.Background(), file_id, &entities.Video{Duration: 7, Width: 976, Height: 720}); err != nil {
+		t.Fatalf("failed to insert file video, %v", err)
+	}
+
+	return file_id
+}
+
+func TestFingerprintRepository_NewFingerprint(t *testing.T) {
+	db := helper_test.NewDatabase(&helper_test.DefaultConnection)
+	defer db.Close()
+
+	fingerprintRepo := fingerprint.NewFingerprintRepository(db)
+	fileRepo, err := file.NewFileRepository(db, t.TempDir())
+	if err != nil {
+		t.Fatalf("failed to create file repo, %v", err)
+	}
+
+	file_id := helperInsertFile(fileRepo, t)
+	unfingerprinted := helperInsertFile(fileRepo, t)
+
+	want := &entities.Fingerprint{
+		Interval: 1,
+		Hashes:   []uint64{0, 1, 0x8000000000000000, 0xdeadbeefcafebabe, ^uint64(0)},
+	}
+
+	if err := fingerprintRepo.NewFingerprint(context.Background(), file_id, want); err != nil {
+		t.Fatalf("FingerprintRepository.NewFingerprint() error = %v", err)
+	}
+
+	got, err := fingerprintRepo.GetFingerprint(context.Background(), file_id)
+	if err != nil {
+		t.Fatalf("FingerprintRepository.GetFingerprint() error = %v", err)
+	}
+
+	if !cmp.Equal(got, want) {
+		t.Errorf("got diff %s", cmp.Diff(got, want))
+	}
+
+	duration := float64(len(want.Hashes)) * want.Interval
+	all, err := fingerprintRepo.GetFingerprintsByDuration(context.Background(), duration, duration)
+	if err != nil {
+		t.Fatalf("FingerprintRepository.GetFingerprintsByDuration() error = %v", err)
+	}
+
+	if !cmp.Equal(all[file_id], *want) {
+		t.Errorf("got diff %s", cmp.Diff(all[file_id], *want))
+	}
+
+	all, err = fingerprintRepo.GetFingerprintsByDuration(context.Background(), duration+1, duration*2+1)
+	if err != nil {
+		t.Fatalf("FingerprintRepository.GetFingerprintsByDuration() error = %v", err)
+	}
+
+	if _, ok := all[file_id]; ok {
+		t.Errorf("GetFingerprintsByDuration() returned file %d of another duration", file_id)
+	}
+
+	unfingerprintedFiles, err := fingerprintRepo.GetUnfingerprintedFiles(context.Background())
+	if err != nil {
+		t.Fatalf("FingerprintRepository.GetUnfingerprintedFiles() error = %v", err)
+	}
+
+	if slices.Contains(unfingerprintedFiles, file_id) || !slices.Contains(unfingerprintedFiles, unfingerprinted) {
+		t.Errorf("GetUnfingerprintedFiles() = %v, want %d but not %d", unfingerprintedFiles, unfingerprinted, file_id)
+	}
+}
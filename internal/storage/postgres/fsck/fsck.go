@@ -0,0 +1,108 @@
+package fsck
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/dtbead/wc-maps-archive/internal/entities"
+	"github.com/dtbead/wc-maps-archive/internal/storage/postgres/queries"
+)
+
+// FsckRepository finds rows which the schema allows but make no sense in the archive, such as a youtube video without
+// any downloaded file.
+type FsckRepository struct {
+	db *sql.DB
+	q  *queries.Queries
+}
+
+func NewFsckRepository(db *sql.DB) *FsckRepository {
+	return &FsckRepository{
+		db: db,
+		q:  queries.New(db),
+	}
+}
+
+// GetYoutubeWithoutFiles returns every youtube video without a single file downloaded of it.
+func (f FsckRepository) GetYoutubeWithoutFiles(ctx context.Context) (youtube_ids []entities.YoutubeVideoID, err error) {
+	res, err := f.q.GetYoutubeWithoutFiles(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	youtube_ids = make([]entities.YoutubeVideoID, 0, len(res))
+	for _, v := range res {
+		youtube_ids = append(youtube_ids, entities.YoutubeVideoID(v))
+	}
+
+	return youtube_ids, nil
+}
+
+// GetFilesWithoutVideo returns every file without its video metadata.
+func (f FsckRepository) GetFilesWithoutVideo(ctx context.Context) (file_ids []entities.FileID, err error) {
+	res, err := f.q.GetFilesWithoutVideo(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	file_ids = make([]entities.FileID, 0, len(res))
+	for _, v := range res {
+		file_ids = append(file_ids, entities.FileID(v))
+	}
+
+	return file_ids, nil
+}
+
+// GetUnassignedDownloads returns every file recorded by yt-dlp metadata as a download of a youtube video, which isn't
+// assigned to any youtube video. A file recorded as a download of several videos is returned once for each of them.
+func (f FsckRepository) GetUnassignedDownloads(ctx context.Context) (downloads []entities.YoutubeDownload, err error) {
+	res, err := f.q.GetUnassignedDownloads(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	downloads = make([]entities.YoutubeDownload, 0, len(res))
+	for _, v := range res {
+		downloads = append(downloads, entities.YoutubeDownload{
+			FileID:    entities.FileID(v.FileID),
+			YoutubeID: entities.YoutubeVideoID(v.YoutubeID),
+		})
+	}
+
+	return downloads, nil
+}
+
+// GetMisattributedDownloads returns every piece of yt-dlp metadata recording a file as a download of another youtube
+// video than the one it's assigned to.
+func (f FsckRepository) GetMisattributedDownloads(ctx context.Context) (downloads []entities.YoutubeDownload, err error) {
+	res, err := f.q.GetMisattributedDownloads(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	downloads = make([]entities.YoutubeDownload, 0, len(res))
+	for _, v := range res {
+		downloads = append(downloads, entities.YoutubeDownload{
+			FileID:            entities.FileID(v.FileID),
+			YoutubeID:         entities.YoutubeVideoID(v.YoutubeID),
+			Source:            v.Source,
+			AssignedYoutubeID: entities.YoutubeVideoID(v.AssignedYoutubeID),
+		})
+	}
+
+	return downloads, nil
+}
+
+// GetProjectsWithoutFiles returns every project without a file of its own or of any of its parts.
+func (f FsckRepository) GetProjectsWithoutFiles(ctx context.Context) (uuids []entities.ProjectUUID, err error) {
+	res, err := f.q.GetProjectsWithoutFiles(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	uuids = make([]entities.ProjectUUID, 0, len(res))
+	for _, v := range res {
+		uuids = append(uuids, entities.ProjectUUID(v))
+	}
+
+	return uuids, nil
+}
@@ -0,0 +1,153 @@
+package fsck_test
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"slices"
+	"testing"
+
+	"github.com/dtbead/wc-maps-archive/internal/entities"
+	helper_test "github.com/dtbead/wc-maps-archive/internal/helper/testing"
+	"github.com/dtbead/wc-maps-archive/internal/storage/postgres/file"
+	"github.com/dtbead/wc-maps-archive/internal/storage/postgres/fsck"
+	"github.com/dtbead/wc-maps-archive/internal/storage/postgres/project"
+)
+
+// helperInsertFile imports a test video file into a new FileRepository and returns its file_id.
+func helperInsertFile(db *sql.DB, t *testing.T) (file_id entities.FileID) {
+	t.Helper()
+
+	fileRepo, err := file.NewFileRepository(db, t.TempDir())
+	if err != nil {
+		t.Fatalf("failed to create file repo, %v", err)
+	}
+
+	f, err := os.Open("testdata/y_wo8pyoxyk.mkv")
+	if err != nil {
+		t.Fatalf("failed to open test file, %v", err)
+	}
+	defer f.Close()
+
+	file_id, err = fileRepo.NewFile(context.Background(), f, "mkv")
+	if err != nil {
+		t.Fatalf("failed to insert file to db, %v", err)
+	}
+
+	return file_id
+}
+
+// helperExec runs statement with args, to set up a state only a broken archive would be in.
+func helperExec(db *sql.DB, t *testing.T, statement string, args ...any) {
+	t.Helper()
+
+	if _, err := db.Exec(statement, args...); err != nil {
+		t.Fatalf("failed to run %q, %v", statement, err)
+	}
+}
+
+func TestFsckRepository_Youtube(t *testing.T) {
+	db := helper_test.NewDatabase(&helper_test.DefaultConnection)
+	defer db.Close()
+
+	fsckRepo := fsck.NewFsckRepository(db)
+	file_id := helperInsertFile(db, t)
+
+	helperExec(db, t, `INSERT INTO youtube_video (id, upload_date, duration) VALUES ('y_wo8pyoxyk', NOW(), 7), ('dQw4w9WgXcQ', NOW(), 212)`)
+	helperExec(db, t, `INSERT INTO youtube_video_format (youtube_id, file_id, format_id, format) VALUES ('y_wo8pyoxyk', $1, '303', '1920x1080')`, int64(file_id))
+
+	// whatever else is archived may turn up as well, so only the videos and files of the test are checked for
+	youtube_ids, err := fsckRepo.GetYoutubeWithoutFiles(context.Background())
+	if err != nil {
+		t.Fatalf("FsckRepository.GetYoutubeWithoutFiles() error = %v", err)
+	}
+	for _, youtube_id := range []entities.YoutubeVideoID{"dQw4w9WgXcQ", "y_wo8pyoxyk"} {
+		if !slices.Contains(youtube_ids, youtube_id) {
+			t.Errorf("FsckRepository.GetYoutubeWithoutFiles() = %v, want it to contain %v", youtube_ids, youtube_id)
+		}
+	}
+
+	unassigned := entities.YoutubeDownload{FileID: file_id, YoutubeID: "y_wo8pyoxyk"}
+	downloads, err := fsckRepo.GetUnassignedDownloads(context.Background())
+	if err != nil {
+		t.Fatalf("FsckRepository.GetUnassignedDownloads() error = %v", err)
+	}
+	if !slices.Contains(downloads, unassigned) {
+		t.Errorf("FsckRepository.GetUnassignedDownloads() = %v, want it to contain %v", downloads, unassigned)
+	}
+
+	// assigning the file to another video than yt-dlp downloaded it of misattributes its format
+	helperExec(db, t, `INSERT INTO youtube_file (youtube_id, file_id) VALUES ('dQw4w9WgXcQ', $1)`, int64(file_id))
+
+	downloads, err = fsckRepo.GetUnassignedDownloads(context.Background())
+	if err != nil {
+		t.Fatalf("FsckRepository.GetUnassignedDownloads() error = %v", err)
+	}
+	if slices.ContainsFunc(downloads, func(d entities.YoutubeDownload) bool { return d.FileID == file_id }) {
+		t.Errorf("FsckRepository.GetUnassignedDownloads() = %v, want it to no longer contain file %d", downloads, file_id)
+	}
+
+	downloads, err = fsckRepo.GetMisattributedDownloads(context.Background())
+	if err != nil {
+		t.Fatalf("FsckRepository.GetMisattributedDownloads() error = %v", err)
+	}
+	misattributed := entities.YoutubeDownload{FileID: file_id, YoutubeID: "y_wo8pyoxyk", Source: "youtube_video_format", AssignedYoutubeID: "dQw4w9WgXcQ"}
+	if !slices.Contains(downloads, misattributed) {
+		t.Errorf("FsckRepository.GetMisattributedDownloads() = %v, want it to contain %v", downloads, misattributed)
+	}
+}
+
+func TestFsckRepository_GetFilesWithoutVideo(t *testing.T) {
+	db := helper_test.NewDatabase(&helper_test.DefaultConnection)
+	defer db.Close()
+
+	fsckRepo := fsck.NewFsckRepository(db)
+	file_id := helperInsertFile(db, t)
+
+	file_ids, err := fsckRepo.GetFilesWithoutVideo(context.Background())
+	if err != nil {
+		t.Fatalf("FsckRepository.GetFilesWithoutVideo() error = %v", err)
+	}
+	if !slices.Contains(file_ids, file_id) {
+		t.Errorf("FsckRepository.GetFilesWithoutVideo() = %v, want it to contain %v", file_ids, file_id)
+	}
+
+	helperExec(db, t, `INSERT INTO file_video (file_id, duration, width, height) VALUES ($1, 7, 1920, 1080)`, int64(file_id))
+
+	file_ids, err = fsckRepo.GetFilesWithoutVideo(context.Background())
+	if err != nil {
+		t.Fatalf("FsckRepository.GetFilesWithoutVideo() error = %v", err)
+	}
+	if slices.Contains(file_ids, file_id) {
+		t.Errorf("FsckRepository.GetFilesWithoutVideo() = %v, want it to no longer contain %v", file_ids, file_id)
+	}
+}
+
+func TestFsckRepository_GetProjectsWithoutFiles(t *testing.T) {
+	db := helper_test.NewDatabase(&helper_test.DefaultConnection)
+	defer db.Close()
+
+	fsckRepo := fsck.NewFsckRepository(db)
+	projectRepo := project.NewProjectRepository(db)
+	file_id := helperInsertFile(db, t)
+
+	for _, uuid := range []entities.ProjectUUID{"r4ruYKZQT3XBtpxPjr6s9k", "Vb3kLyFzmd5LQhZkyZ8pGr"} {
+		_, err := projectRepo.NewProject(context.Background(), &entities.Project{UUID: string(uuid), ProjectType: entities.ProjectAnimatedMusicVideo})
+		if err != nil {
+			t.Fatalf("failed to insert project, %v", err)
+		}
+	}
+
+	err := projectRepo.AssignProjectFile(context.Background(), "Vb3kLyFzmd5LQhZkyZ8pGr", file_id)
+	if err != nil {
+		t.Fatalf("failed to assign file to project, %v", err)
+	}
+
+	uuids, err := fsckRepo.GetProjectsWithoutFiles(context.Background())
+	if err != nil {
+		t.Fatalf("FsckRepository.GetProjectsWithoutFiles() error = %v", err)
+	}
+	if !slices.Contains(uuids, "r4ruYKZQT3XBtpxPjr6s9k") || slices.Contains(uuids, "Vb3kLyFzmd5LQhZkyZ8pGr") {
+		t.Errorf("FsckRepository.GetProjectsWithoutFiles() = %v, want it to contain r4ruYKZQT3XBtpxPjr6s9k but not Vb3kLyFzmd5LQhZkyZ8pGr", uuids)
+	}
+}
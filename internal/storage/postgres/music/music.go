@@ -0,0 +1,151 @@
+package music
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/dtbead/wc-maps-archive/internal/entities"
+	"github.com/dtbead/wc-maps-archive/internal/storage/postgres/queries"
+)
+
+type MusicRepository struct {
+	db *sql.DB
+	q  *queries.Queries
+}
+
+func NewMusicRepository(db *sql.DB) *MusicRepository {
+	return &MusicRepository{
+		db: db,
+		q:  queries.New(db),
+	}
+}
+
+func (m MusicRepository) NewMusic(ctx context.Context, music *entities.Music) (music_id int32, err error) {
+	if music == nil {
+		return 0, entities.ErrorInvalidMusicPtr
+	}
+
+	return m.q.NewMusic(ctx, queries.NewMusicParams{
+		Artist: music.Artist,
+		Title:  music.Title,
+	})
+}
+
+func (m MusicRepository) UpdateMusic(ctx context.Context, music *entities.Music) (err error) {
+	if music == nil {
+		return entities.ErrorInvalidMusicPtr
+	}
+
+	rows, err := m.q.UpdateMusic(ctx, queries.UpdateMusicParams{
+		ID:     music.ID,
+		Artist: music.Artist,
+		Title:  music.Title,
+	})
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+func (m MusicRepository) DeleteMusic(ctx context.Context, music_id int32) (err error) {
+	rows, err := m.q.DeleteMusic(ctx, music_id)
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+func (m MusicRepository) GetMusic(ctx context.Context, music_id int32) (music *entities.Music, err error) {
+	res, err := m.q.GetMusic(ctx, music_id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &entities.Music{ID: res.ID, Artist: res.Artist, Title: res.Title}, nil
+}
+
+// FindMusic fuzzily matches artist and title against every song, either of which may be left empty to match anything.
+// Matches scoring below min_score are left out, and at most max_results are returned, best first.
+func (m MusicRepository) FindMusic(ctx context.Context, artist, title string, min_score float64, max_results int) (matches []entities.MusicMatch, err error) {
+	res, err := m.q.FindMusic(ctx, queries.FindMusicParams{
+		Artist:     artist,
+		Title:      title,
+		MinScore:   min_score,
+		MaxResults: int32(max_results),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	matches = make([]entities.MusicMatch, 0, len(res))
+	for _, v := range res {
+		matches = append(matches, entities.MusicMatch{
+			Music: entities.Music{ID: v.ID, Artist: v.Artist, Title: v.Title},
+			Score: v.Score,
+		})
+	}
+
+	return matches, nil
+}
+
+func (m MusicRepository) AssignProjectMusic(ctx context.Context, uuid entities.ProjectUUID, music_id int32) (err error) {
+	return m.q.AssignProjectMusic(ctx, queries.AssignProjectMusicParams{
+		Uuid:    string(uuid),
+		MusicID: music_id,
+	})
+}
+
+func (m MusicRepository) UnassignProjectMusic(ctx context.Context, uuid entities.ProjectUUID, music_id int32) (err error) {
+	rows, err := m.q.UnassignProjectMusic(ctx, queries.UnassignProjectMusicParams{
+		Uuid:    string(uuid),
+		MusicID: music_id,
+	})
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+func (m MusicRepository) GetProjectMusic(ctx context.Context, uuid entities.ProjectUUID) (music []entities.Music, err error) {
+	res, err := m.q.GetProjectMusic(ctx, string(uuid))
+	if err != nil {
+		return nil, err
+	}
+
+	music = make([]entities.Music, 0, len(res))
+	for _, v := range res {
+		music = append(music, entities.Music{ID: v.ID, Artist: v.Artist, Title: v.Title})
+	}
+
+	return music, nil
+}
+
+// GetMusicProjects returns every project using music_id, in the order they were archived.
+func (m MusicRepository) GetMusicProjects(ctx context.Context, music_id int32) (uuids []entities.ProjectUUID, err error) {
+	res, err := m.q.GetMusicProjects(ctx, music_id)
+	if err != nil {
+		return nil, err
+	}
+
+	uuids = make([]entities.ProjectUUID, 0, len(res))
+	for _, v := range res {
+		uuids = append(uuids, entities.ProjectUUID(v))
+	}
+
+	return uuids, nil
+}
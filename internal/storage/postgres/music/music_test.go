@@ -0,0 +1,116 @@
+package music_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dtbead/wc-maps-archive/internal/entities"
+	"github.com/dtbead/wc-maps-archive/internal/helper"
+	helper_test "github.com/dtbead/wc-maps-archive/internal/helper/testing"
+	"github.com/dtbead/wc-maps-archive/internal/storage/postgres/music"
+	"github.com/dtbead/wc-maps-archive/internal/storage/postgres/project"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestMusicRepository(t *testing.T) {
+	db := helper_test.NewDatabase(&helper_test.DefaultConnection)
+	defer db.Close()
+
+	musicRepo := music.NewMusicRepository(db)
+	projectRepo := project.NewProjectRepository(db)
+	ctx := context.Background()
+
+	songs := []entities.Music{
+		{Artist: "Hollow Coves", Title: "Coastline"},
+		{Artist: "Hollywood Undead", Title: "Bullet"},
+		{Artist: "Ruelle", Title: "Monsters"},
+	}
+	for i := range songs {
+		music_id, err := musicRepo.NewMusic(ctx, &songs[i])
+		if err != nil {
+			t.Fatalf("MusicRepository.NewMusic() error = %v", err)
+		}
+		songs[i].ID = music_id
+	}
+
+	matches, err := musicRepo.FindMusic(ctx, "holow coves", "", 0.3, 10)
+	if err != nil {
+		t.Fatalf("MusicRepository.FindMusic() error = %v", err)
+	}
+
+	if len(matches) == 0 || matches[0].Music != songs[0] {
+		t.Errorf("MusicRepository.FindMusic() = %v, want %v first", matches, songs[0])
+	}
+
+	matches, err = musicRepo.FindMusic(ctx, "", "MONSTER", 0.3, 10)
+	if err != nil {
+		t.Fatalf("MusicRepository.FindMusic() error = %v", err)
+	}
+
+	if len(matches) != 1 || matches[0].Music != songs[2] {
+		t.Errorf("MusicRepository.FindMusic() = %v, want only %v", matches, songs[2])
+	}
+
+	songs[1].Title = "Bullet (Acoustic)"
+	if err := musicRepo.UpdateMusic(ctx, &songs[1]); err != nil {
+		t.Fatalf("MusicRepository.UpdateMusic() error = %v", err)
+	}
+
+	got, err := musicRepo.GetMusic(ctx, songs[1].ID)
+	if err != nil {
+		t.Fatalf("MusicRepository.GetMusic() error = %v", err)
+	}
+
+	if *got != songs[1] {
+		t.Errorf("MusicRepository.GetMusic() = %v, want %v", *got, songs[1])
+	}
+
+	uuid, err := projectRepo.NewProject(ctx, &entities.Project{
+		UUID:        helper.RandomUUID(),
+		ProjectType: entities.ProjectMultiAnimation,
+	})
+	if err != nil {
+		t.Fatalf("failed to create mock project, %v", err)
+	}
+
+	for _, song := range songs[:2] {
+		if err := musicRepo.AssignProjectMusic(ctx, uuid, song.ID); err != nil {
+			t.Fatalf("MusicRepository.AssignProjectMusic() error = %v", err)
+		}
+	}
+
+	projectMusic, err := musicRepo.GetProjectMusic(ctx, uuid)
+	if err != nil {
+		t.Fatalf("MusicRepository.GetProjectMusic() error = %v", err)
+	}
+
+	if !cmp.Equal(projectMusic, songs[:2]) {
+		t.Errorf("got diff %s", cmp.Diff(projectMusic, songs[:2]))
+	}
+
+	uuids, err := musicRepo.GetMusicProjects(ctx, songs[0].ID)
+	if err != nil {
+		t.Fatalf("MusicRepository.GetMusicProjects() error = %v", err)
+	}
+
+	if !cmp.Equal(uuids, []entities.ProjectUUID{uuid}) {
+		t.Errorf("MusicRepository.GetMusicProjects() = %v, want %v", uuids, []entities.ProjectUUID{uuid})
+	}
+
+	if err := musicRepo.UnassignProjectMusic(ctx, uuid, songs[0].ID); err != nil {
+		t.Errorf("MusicRepository.UnassignProjectMusic() error = %v", err)
+	}
+
+	if err := musicRepo.DeleteMusic(ctx, songs[2].ID); err != nil {
+		t.Errorf("MusicRepository.DeleteMusic() error = %v", err)
+	}
+
+	if err := musicRepo.DeleteMusic(ctx, songs[2].ID); err == nil {
+		t.Errorf("MusicRepository.DeleteMusic() of a deleted song error = %v, wantErr %v", err, true)
+	}
+
+	// artist and title are case insensitive; a failing statement aborts the test transaction, so this has to come last
+	if _, err := musicRepo.NewMusic(ctx, &entities.Music{Artist: "hollow coves", Title: "COASTLINE"}); err == nil {
+		t.Errorf("MusicRepository.NewMusic() of a duplicate song error = %v, wantErr %v", err, true)
+	}
+}
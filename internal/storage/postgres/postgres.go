@@ -5,8 +5,16 @@ import (
 	_ "embed"
 
 	"github.com/dtbead/wc-maps-archive/internal/storage"
+	"github.com/dtbead/wc-maps-archive/internal/storage/postgres/artist"
+	"github.com/dtbead/wc-maps-archive/internal/storage/postgres/audit"
+	"github.com/dtbead/wc-maps-archive/internal/storage/postgres/character"
 	"github.com/dtbead/wc-maps-archive/internal/storage/postgres/file"
+	"github.com/dtbead/wc-maps-archive/internal/storage/postgres/fingerprint"
+	"github.com/dtbead/wc-maps-archive/internal/storage/postgres/fsck"
+	"github.com/dtbead/wc-maps-archive/internal/storage/postgres/music"
+	"github.com/dtbead/wc-maps-archive/internal/storage/postgres/probe"
 	"github.com/dtbead/wc-maps-archive/internal/storage/postgres/project"
+	"github.com/dtbead/wc-maps-archive/internal/storage/postgres/search"
 	"github.com/dtbead/wc-maps-archive/internal/storage/postgres/youtube"
 )
 
@@ -20,8 +28,16 @@ func NewRepository(db *sql.DB, base_directory string) (*storage.Repository, erro
 	}
 
 	return &storage.Repository{
-		Project: project.NewProjectRepository(db),
-		Youtube: youtube.NewYoutubeRepository(db),
-		File:    f,
+		Project:     project.NewProjectRepository(db),
+		Youtube:     youtube.NewYoutubeRepository(db),
+		File:        f,
+		Probe:       probe.NewProbeRepository(db),
+		Fingerprint: fingerprint.NewFingerprintRepository(db),
+		Artist:      artist.NewArtistRepository(db),
+		Music:       music.NewMusicRepository(db),
+		Character:   character.NewCharacterRepository(db),
+		Search:      search.NewSearchRepository(db),
+		Audit:       audit.NewAuditRepository(db),
+		Fsck:        fsck.NewFsckRepository(db),
 	}, nil
 }
@@ -0,0 +1,206 @@
+package probe
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/dtbead/wc-maps-archive/internal/entities"
+	"github.com/dtbead/wc-maps-archive/internal/storage/postgres/queries"
+)
+
+type ProbeRepository struct {
+	db *sql.DB
+	q  *queries.Queries
+}
+
+func NewProbeRepository(db *sql.DB) *ProbeRepository {
+	return &ProbeRepository{
+		db: db,
+		q:  queries.New(db),
+	}
+}
+
+// NewProbe stores probe as the current state of file_id, replacing any earlier probe and mismatches. The file's
+// file_video is overwritten with what was actually probed, so it no longer relies on whatever its source claimed.
+func (p ProbeRepository) NewProbe(ctx context.Context, file_id entities.FileID, probe *entities.MediaProbe, mismatches []entities.ProbeMismatch) (err error) {
+	if probe == nil {
+		return errors.New("nil probe given")
+	}
+
+	tx, err := p.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	q := p.q.WithTx(tx)
+
+	err = q.UpsertFileProbe(ctx, queries.UpsertFileProbeParams{
+		FileID:    int64(file_id),
+		Container: probe.Container,
+		Duration:  probe.Duration,
+		Bitrate:   sql.NullInt64{Int64: probe.Bitrate, Valid: probe.Bitrate > 0},
+	})
+	if err != nil {
+		return err
+	}
+
+	err = q.DeleteFileProbeStreams(ctx, int64(file_id))
+	if err != nil {
+		return err
+	}
+
+	for _, s := range probe.Streams {
+		err = q.NewFileProbeStream(ctx, queries.NewFileProbeStreamParams{
+			FileID:      int64(file_id),
+			StreamIndex: int16(s.Index),
+			CodecType:   s.CodecType,
+			Codec:       sql.NullString{String: s.Codec, Valid: s.Codec != ""},
+			Bitrate:     sql.NullInt64{Int64: s.Bitrate, Valid: s.Bitrate > 0},
+			Width:       sql.NullInt16{Int16: s.Width, Valid: s.Width > 0},
+			Height:      sql.NullInt16{Int16: s.Height, Valid: s.Height > 0},
+			Fps:         sql.NullFloat64{Float64: s.Fps, Valid: s.Fps > 0},
+			SampleRate:  sql.NullInt32{Int32: int32(s.SampleRate), Valid: s.SampleRate > 0},
+			Channels:    sql.NullInt16{Int16: s.Channels, Valid: s.Channels > 0},
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	// files without a video stream simply don't get a file_video
+	if video, err := probe.Video(); err == nil {
+		err = q.UpsertFileVideo(ctx, queries.UpsertFileVideoParams{
+			FileID:     int64(file_id),
+			Duration:   int32(video.Duration),
+			Width:      video.Width,
+			Height:     video.Height,
+			Fps:        sql.NullInt16{Int16: video.Fps, Valid: video.Fps > 0},
+			VideoCodec: sql.NullString{String: video.VideoCodec, Valid: video.VideoCodec != ""},
+			AudioCodec: sql.NullString{String: video.AudioCodec, Valid: video.AudioCodec != ""},
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	err = q.DeleteFileProbeMismatches(ctx, int64(file_id))
+	if err != nil {
+		return err
+	}
+
+	for _, m := range mismatches {
+		err = q.NewFileProbeMismatch(ctx, queries.NewFileProbeMismatchParams{
+			FileID:   int64(file_id),
+			Field:    m.Field,
+			Expected: m.Expected,
+			Actual:   m.Actual,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (p ProbeRepository) GetProbe(ctx context.Context, file_id entities.FileID) (probe *entities.MediaProbe, err error) {
+	res, err := p.q.GetFileProbe(ctx, int64(file_id))
+	if err != nil {
+		return nil, err
+	}
+
+	streams, err := p.q.GetFileProbeStreams(ctx, int64(file_id))
+	if err != nil {
+		return nil, err
+	}
+
+	probe = &entities.MediaProbe{
+		Container: res.Container,
+		Duration:  res.Duration,
+		Bitrate:   res.Bitrate.Int64,
+		Streams:   make([]entities.MediaStream, len(streams)),
+	}
+
+	for i, s := range streams {
+		probe.Streams[i] = entities.MediaStream{
+			Index:      int(s.StreamIndex),
+			CodecType:  s.CodecType,
+			Codec:      s.Codec.String,
+			Bitrate:    s.Bitrate.Int64,
+			Width:      s.Width.Int16,
+			Height:     s.Height.Int16,
+			Fps:        s.Fps.Float64,
+			SampleRate: int(s.SampleRate.Int32),
+			Channels:   s.Channels.Int16,
+		}
+	}
+
+	return probe, nil
+}
+
+func (p ProbeRepository) GetProbeMismatches(ctx context.Context, file_id entities.FileID) (mismatches []entities.ProbeMismatch, err error) {
+	res, err := p.q.GetFileProbeMismatches(ctx, int64(file_id))
+	if err != nil {
+		return nil, err
+	}
+
+	return toProbeMismatches(res), nil
+}
+
+func (p ProbeRepository) GetAllProbeMismatches(ctx context.Context) (mismatches []entities.ProbeMismatch, err error) {
+	res, err := p.q.GetAllFileProbeMismatches(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return toProbeMismatches(res), nil
+}
+
+// GetUnprobedFiles returns every file that has never been probed, such as files stored before probing on ingest.
+func (p ProbeRepository) GetUnprobedFiles(ctx context.Context) (file_ids []entities.FileID, err error) {
+	res, err := p.q.GetUnprobedFileIDs(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	file_ids = make([]entities.FileID, len(res))
+	for i, id := range res {
+		file_ids[i] = entities.FileID(id)
+	}
+
+	return file_ids, nil
+}
+
+// GetClaimedVideo returns what yt-dlp claimed about the video it downloaded as file_id, read out of the info json it
+// printed at the time, as file_video stops saying so once the file is probed. A file without an info json is
+// sql.ErrNoRows.
+func (p ProbeRepository) GetClaimedVideo(ctx context.Context, file_id entities.FileID) (video *entities.Video, err error) {
+	res, err := p.q.GetYtdlpClaimedVideo(ctx, int64(file_id))
+	if err != nil {
+		return nil, err
+	}
+
+	return &entities.Video{
+		Duration:   int(res.Duration),
+		Width:      res.Width,
+		Height:     res.Height,
+		Fps:        res.Fps,
+		VideoCodec: res.VideoCodec,
+		AudioCodec: res.AudioCodec,
+	}, nil
+}
+
+func toProbeMismatches(res []queries.FileProbeMismatch) []entities.ProbeMismatch {
+	mismatches := make([]entities.ProbeMismatch, len(res))
+	for i, m := range res {
+		mismatches[i] = entities.ProbeMismatch{
+			FileID:   entities.FileID(m.FileID),
+			Field:    m.Field,
+			Expected: m.Expected,
+			Actual:   m.Actual,
+		}
+	}
+
+	return mismatches
+}
@@ -0,0 +1,206 @@
+package probe_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/dtbead/wc-maps-archive/internal/entities"
+	helper_test "github.com/dtbead/wc-maps-archive/internal/helper/testing"
+	"github.com/dtbead/wc-maps-archive/internal/storage/postgres/file"
+	"github.com/dtbead/wc-maps-archive/internal/storage/postgres/probe"
+	"github.com/google/go-cmp/cmp"
+)
+
+// helperInsertFile stores random bytes in fileRepo and returns their file_id. helperInsertFile will implicitly call
+// t.Fatal if storing fails.
+func helperInsertFile(fileRepo *file.FileRepository, t *testing.T) entities.FileID {
+	t.Helper()
+
+	file_id, err := fileRepo.NewFile(context.Background(), bytes.NewReader([]byte(rand.Text())), "mkv")
+	if err != nil {
+		t.Fatalf("failed to insert file to db, %v", err)
+	}
+
+	return file_id
+}
+
+func TestProbeRepository_NewProbe(t *testing.T) {
+	db := helper_test.NewDatabase(&helper_test.DefaultConnection)
+	defer db.Close()
+
+	probeRepo := probe.NewProbeRepository(db)
+	fileRepo, err := file.NewFileRepository(db, t.TempDir())
+	if err != nil {
+		t.Fatalf("failed to create file repo, %v", err)
+	}
+
+	file_id := helperInsertFile(fileRepo, t)
+
+	// file_video starts out as whatever yt-dlp claimed
+	err = fileRepo.NewFileVideo(context.Background(), file_id, &entities.Video{
+		VideoCodec: "avc1.64001F",
+		AudioCodec: "opus",
+		Duration:   7,
+		Width:      976,
+		Height:     720,
+		Fps:        30,
+	})
+	if err != nil {
+		t.Fatalf("failed to insert file video, %v", err)
+	}
+
+	want := &entities.MediaProbe{
+		Container: "matroska,webm",
+		Duration:  7.012,
+		Bitrate:   823753,
+		Streams: []entities.MediaStream{
+			{Index: 0, CodecType: "video", Codec: "h264", Width: 976, Height: 720, Fps: 30000.0 / 1001.0},
+		},
+	}
+	wantMismatches := []entities.ProbeMismatch{
+		{FileID: file_id, Field: "audio_stream", Expected: "opus", Actual: "none"},
+	}
+
+	if err := probeRepo.NewProbe(context.Background(), file_id, want, wantMismatches); err != nil {
+		t.Fatalf("ProbeRepository.NewProbe() error = %v", err)
+	}
+
+	got, err := probeRepo.GetProbe(context.Background(), file_id)
+	if err != nil {
+		t.Fatalf("ProbeRepository.GetProbe() error = %v", err)
+	}
+
+	if !cmp.Equal(got, want) {
+		t.Errorf("got diff %s", cmp.Diff(got, want))
+	}
+
+	gotMismatches, err := probeRepo.GetProbeMismatches(context.Background(), file_id)
+	if err != nil {
+		t.Fatalf("ProbeRepository.GetProbeMismatches() error = %v", err)
+	}
+
+	if !cmp.Equal(gotMismatches, wantMismatches) {
+		t.Errorf("got diff %s", cmp.Diff(gotMismatches, wantMismatches))
+	}
+
+	// file_video must now describe the actual file rather than the claim
+	gotVideo, err := fileRepo.GetFileVideo(context.Background(), file_id)
+	if err != nil {
+		t.Fatalf("FileRepository.GetFileVideo() error = %v", err)
+	}
+
+	wantVideo := &entities.Video{VideoCodec: "h264", Duration: 7, Width: 976, Height: 720, Fps: 30}
+	if !cmp.Equal(gotVideo, wantVideo) {
+		t.Errorf("got diff %s", cmp.Diff(gotVideo, wantVideo))
+	}
+
+	// probing again replaces the previous probe and its mismatches
+	want.Streams = append(want.Streams, entities.MediaStream{Index: 1, CodecType: "audio", Codec: "opus", SampleRate: 48000, Channels: 2})
+	if err := probeRepo.NewProbe(context.Background(), file_id, want, nil); err != nil {
+		t.Fatalf("ProbeRepository.NewProbe() error = %v", err)
+	}
+
+	got, err = probeRepo.GetProbe(context.Background(), file_id)
+	if err != nil {
+		t.Fatalf("ProbeRepository.GetProbe() error = %v", err)
+	}
+
+	if !cmp.Equal(got, want) {
+		t.Errorf("got diff %s", cmp.Diff(got, want))
+	}
+
+	gotMismatches, err = probeRepo.GetProbeMismatches(context.Background(), file_id)
+	if err != nil {
+		t.Fatalf("ProbeRepository.GetProbeMismatches() error = %v", err)
+	}
+
+	if len(gotMismatches) != 0 {
+		t.Errorf("got %d mismatches after reprobing, want 0", len(gotMismatches))
+	}
+}
+
+func TestProbeRepository_GetUnprobedFiles(t *testing.T) {
+	db := helper_test.NewDatabase(&helper_test.DefaultConnection)
+	defer db.Close()
+
+	probeRepo := probe.NewProbeRepository(db)
+	fileRepo, err := file.NewFileRepository(db, t.TempDir())
+	if err != nil {
+		t.Fatalf("failed to create file repo, %v", err)
+	}
+
+	probed := helperInsertFile(fileRepo, t)
+	unprobed := helperInsertFile(fileRepo, t)
+
+	err = probeRepo.NewProbe(context.Background(), probed, &entities.MediaProbe{Container: "matroska,webm", Duration: 1}, nil)
+	if err != nil {
+		t.Fatalf("ProbeRepository.NewProbe() error = %v", err)
+	}
+
+	got, err := probeRepo.GetUnprobedFiles(context.Background())
+	if err != nil {
+		t.Fatalf("ProbeRepository.GetUnprobedFiles() error = %v", err)
+	}
+
+	if slices.Contains(got, probed) {
+		t.Errorf("GetUnprobedFiles() returned probed file %d", probed)
+	}
+
+	if !slices.Contains(got, unprobed) {
+		t.Errorf("GetUnprobedFiles() is missing unprobed file %d", unprobed)
+	}
+}
+
+func TestProbeRepository_GetClaimedVideo(t *testing.T) {
+	db := helper_test.NewDatabase(&helper_test.DefaultConnection)
+	defer db.Close()
+
+	probeRepo := probe.NewProbeRepository(db)
+	fileRepo, err := file.NewFileRepository(db, t.TempDir())
+	if err != nil {
+		t.Fatalf("failed to create file repo, %v", err)
+	}
+
+	file_id := helperInsertFile(fileRepo, t)
+
+	_, err = probeRepo.GetClaimedVideo(context.Background(), file_id)
+	if !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("ProbeRepository.GetClaimedVideo() of a file without an info json error = %v, want %v", err, sql.ErrNoRows)
+	}
+
+	_, err = db.Exec(`INSERT INTO youtube_video (id, upload_date, duration) VALUES ('y_wo8pyoxyk', NOW(), 7)`)
+	if err != nil {
+		t.Fatalf("failed to insert youtube video, %v", err)
+	}
+
+	info := `{"id": "y_wo8pyoxyk", "duration": 7, "width": 976, "height": 720, "fps": 29.97, "vcodec": "avc1.64001F", "acodec": "opus"}`
+	_, err = db.Exec(`INSERT INTO youtube_video_ytdlp_info (file_id, youtube_id, info) VALUES ($1, 'y_wo8pyoxyk', $2)`, int64(file_id), info)
+	if err != nil {
+		t.Fatalf("failed to insert ytdlp info, %v", err)
+	}
+
+	// probing overwrites file_video, but not what yt-dlp claimed
+	err = probeRepo.NewProbe(context.Background(), file_id, &entities.MediaProbe{
+		Container: "matroska,webm",
+		Duration:  7.012,
+		Streams:   []entities.MediaStream{{Index: 0, CodecType: "video", Codec: "h264", Width: 1280, Height: 720, Fps: 30}},
+	}, nil)
+	if err != nil {
+		t.Fatalf("ProbeRepository.NewProbe() error = %v", err)
+	}
+
+	got, err := probeRepo.GetClaimedVideo(context.Background(), file_id)
+	if err != nil {
+		t.Fatalf("ProbeRepository.GetClaimedVideo() error = %v", err)
+	}
+
+	want := &entities.Video{VideoCodec: "avc1.64001F", AudioCodec: "opus", Duration: 7, Width: 976, Height: 720, Fps: 29}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("ProbeRepository.GetClaimedVideo() mismatch (-want +got):\n%s", diff)
+	}
+}
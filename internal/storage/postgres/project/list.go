@@ -0,0 +1,141 @@
+package project
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/dtbead/wc-maps-archive/internal/entities"
+	"github.com/dtbead/wc-maps-archive/internal/filter"
+	"github.com/dtbead/wc-maps-archive/internal/storage/postgres/where"
+)
+
+// projectVideos joins p, the project, to the youtube videos of its files as yv.
+const projectVideos = `project_file pf
+	JOIN youtube_file yf ON yf.file_id = pf.file_id
+	JOIN youtube_video yv ON yv.id = yf.youtube_id`
+
+// announcedPeriod and completedPeriod are the periods the partial dates a project was announced and completed on
+// cover, NULL if unknown.
+const (
+	announcedPeriod = "partial_date_range(p.date_announced, p.date_announced_precision)"
+	completedPeriod = "partial_date_range(p.date_completed, p.date_completed_precision)"
+)
+
+// periodMiddle sorts the period of a partial date by its middle, so that 2015 sorts after 2015-03 but before 2015-09,
+// and unknown dates first when ascending.
+func periodMiddle(period string) string {
+	return fmt.Sprintf("COALESCE(lower(%[1]s) + (upper(%[1]s) - lower(%[1]s)) / 2, '-infinity')", period)
+}
+
+// projectSchema is every field projects can be listed by, p being the project. Types are matched by what
+// entities.ProjectType calls them, which isn't always what the database does. status is the current status of a
+// project and was any status it ever had, so that status:cancelled has:part-footage finds cancelled MAPs with some of
+// their parts archived.
+var projectSchema = where.Schema{
+	"":            {Kind: where.KindText, Expr: "EXISTS (SELECT 1 FROM project_title t WHERE t.project_id = p.id AND t.title %s)"},
+	"title":       {Kind: where.KindText, Expr: "EXISTS (SELECT 1 FROM project_title t WHERE t.project_id = p.id AND t.title %s)"},
+	"description": {Kind: where.KindText, Expr: "EXISTS (SELECT 1 FROM project_description d WHERE d.project_id = p.id AND d.description %s)"},
+	"type": {Kind: where.KindChoice, Choices: map[string]string{
+		entities.ProjectTypeUnknown.ToString():        "p.type = 'unknown'",
+		entities.ProjectTypeOther.ToString():          "p.type = 'other'",
+		entities.ProjectMultiAnimation.ToString():     "p.type = 'multi-animation'",
+		entities.ProjectMultiAnimationPart.ToString(): "p.type = 'multi-animation part'",
+		entities.ProjectMultiEdit.ToString():          "p.type = 'multi-edit'",
+		entities.ProjectMultiEditPart.ToString():      "p.type = 'multi-edit part'",
+		entities.ProjectAnimatedMusicVideo.ToString(): "p.type = 'animated music video'",
+		entities.ProjectPictureMusicVideo.ToString():  "p.type = 'picture music video'",
+		entities.ProjectAnimationMeme.ToString():      "p.type = 'animation meme'",
+	}},
+	"announced": {Kind: where.KindPeriod, Expr: announcedPeriod + " %s"},
+	"completed": {Kind: where.KindPeriod, Expr: completedPeriod + " %s"},
+	"archived":  {Kind: where.KindDate, Expr: "p.date_archived %s"},
+	"uploaded":  {Kind: where.KindDate, Expr: "EXISTS (SELECT 1 FROM " + projectVideos + " WHERE pf.project_id = p.id AND yv.upload_date %s)"},
+	"duration": {Kind: where.KindInt, Expr: `EXISTS (SELECT 1 FROM project_file pf
+		JOIN file_video fv ON fv.file_id = pf.file_id
+		WHERE pf.project_id = p.id AND fv.duration %s)`},
+	"views": {Kind: where.KindInt, Expr: "EXISTS (SELECT 1 FROM " + projectVideos + " WHERE pf.project_id = p.id AND yv.view_count %s)"},
+	"channel": {Kind: where.KindExact, Expr: `(EXISTS (SELECT 1 FROM ` + projectVideos + `
+		JOIN youtube_channel_youtube_video c ON c.youtube_id = yv.id
+		WHERE pf.project_id = p.id AND c.channel_id %[1]s)
+		OR EXISTS (SELECT 1 FROM project_part pp WHERE pp.project_id = p.id AND pp.participant_channel_id %[1]s))`},
+	"status": {Kind: where.KindChoice, Choices: statusChoices(`(SELECT s.status FROM project_status s WHERE s.project_id = p.id
+		ORDER BY s.date DESC, s.id DESC LIMIT 1) = '%s'`)},
+	"was":         {Kind: where.KindChoice, Choices: statusChoices("EXISTS (SELECT 1 FROM project_status s WHERE s.project_id = p.id AND s.status = '%s')")},
+	"participant": {Kind: where.KindText, Expr: "EXISTS (SELECT 1 FROM project_part pp WHERE pp.project_id = p.id AND pp.participant_name %s)"},
+	"parts":       {Kind: where.KindInt, Expr: "(SELECT count(*) FROM project_part pp WHERE pp.project_id = p.id) %s"},
+	"music": {Kind: where.KindText, Expr: `EXISTS (SELECT 1 FROM project_music pm
+		JOIN music m ON m.id = pm.music_id
+		WHERE pm.project_id = p.id AND (m.artist || ' - ' || m.title) %s)`},
+	"character": {Kind: where.KindText, Expr: `EXISTS (SELECT 1 FROM project_character pc
+		JOIN "character" ch ON ch.id = pc.character_id
+		LEFT JOIN character_alias ca ON ca.character_id = ch.id
+		WHERE pc.project_id = p.id AND (ch.name::text %[1]s OR ca.name::text %[1]s))`},
+	"has": {Kind: where.KindChoice, Choices: map[string]string{
+		"files":       "EXISTS (SELECT 1 FROM project_file pf WHERE pf.project_id = p.id)",
+		"youtube":     "EXISTS (SELECT 1 FROM " + projectVideos + " WHERE pf.project_id = p.id)",
+		"title":       "EXISTS (SELECT 1 FROM project_title t WHERE t.project_id = p.id)",
+		"description": "EXISTS (SELECT 1 FROM project_description d WHERE d.project_id = p.id AND d.description != '')",
+		"parts":       "EXISTS (SELECT 1 FROM project_part pp WHERE pp.project_id = p.id)",
+		"music":       "EXISTS (SELECT 1 FROM project_music pm WHERE pm.project_id = p.id)",
+		"characters":  "EXISTS (SELECT 1 FROM project_character pc WHERE pc.project_id = p.id)",
+		"relations":   "EXISTS (SELECT 1 FROM project_relation r WHERE r.project_id = p.id OR r.related_project_id = p.id)",
+		"status":      "EXISTS (SELECT 1 FROM project_status s WHERE s.project_id = p.id)",
+		"footage": `(EXISTS (SELECT 1 FROM project_file pf WHERE pf.project_id = p.id)
+			OR EXISTS (SELECT 1 FROM project_part pp WHERE pp.project_id = p.id AND (pp.file_id IS NOT NULL OR pp.youtube_id IS NOT NULL)))`,
+		"part-footage": "EXISTS (SELECT 1 FROM project_part pp WHERE pp.project_id = p.id AND (pp.file_id IS NOT NULL OR pp.youtube_id IS NOT NULL))",
+	}},
+}
+
+// statusChoices maps every project status to condition, with its %s replaced by the status.
+func statusChoices(condition string) map[string]string {
+	choices := make(map[string]string)
+	for status := entities.ProjectStatusAnnounced; status <= entities.ProjectStatusRevived; status++ {
+		choices[status.ToString()] = fmt.Sprintf(condition, status.ToString())
+	}
+
+	return choices
+}
+
+// projectListing pages through projects, most recently archived first by default. A project's upload date is the
+// earliest of its youtube videos, its duration that of its longest file, and its views the total of its videos. Projects
+// announced or completed on partial dates sort by the middle of the period they cover.
+var projectListing = where.Listing{
+	From:   "project p",
+	Select: "p.uuid",
+	ID:     "p.id",
+	IDType: "bigint",
+	Orders: map[entities.ListSort]where.Order{
+		entities.ListSortDefault:      {Expr: "p.date_archived", Type: "timestamp"},
+		entities.ListSortArchivedDate: {Expr: "p.date_archived", Type: "timestamp"},
+		entities.ListSortUploadDate: {Expr: "COALESCE((SELECT min(yv.upload_date) FROM " + projectVideos + " WHERE pf.project_id = p.id), '-infinity')",
+			Type: "timestamp"},
+		entities.ListSortDuration: {Expr: `COALESCE((SELECT max(fv.duration) FROM project_file pf
+			JOIN file_video fv ON fv.file_id = pf.file_id
+			WHERE pf.project_id = p.id), -1)::bigint`, Type: "bigint"},
+		entities.ListSortViews: {Expr: "COALESCE((SELECT sum(yv.view_count) FROM " + projectVideos + " WHERE pf.project_id = p.id), -1)::bigint",
+			Type: "bigint"},
+
+		entities.ListSortAnnouncedDate: {Expr: periodMiddle(announcedPeriod), Type: "timestamp"},
+		entities.ListSortCompletedDate: {Expr: periodMiddle(completedPeriod), Type: "timestamp"},
+	},
+}
+
+// ListProjects returns a page of projects matching f, along with the cursor of the next page.
+func (p ProjectRepository) ListProjects(ctx context.Context, f filter.Filter, opts entities.ListOptions) (uuids []entities.ProjectUUID, next_cursor string, err error) {
+	condition, args, err := where.Compile(f, projectSchema)
+	if err != nil {
+		return nil, "", err
+	}
+
+	res, next_cursor, err := where.List(ctx, p.db, projectListing, condition, args, opts)
+	if err != nil {
+		return nil, "", err
+	}
+
+	uuids = make([]entities.ProjectUUID, 0, len(res))
+	for _, v := range res {
+		uuids = append(uuids, entities.ProjectUUID(v))
+	}
+
+	return uuids, next_cursor, nil
+}
@@ -0,0 +1,163 @@
+package project
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/dtbead/wc-maps-archive/internal/entities"
+	"github.com/dtbead/wc-maps-archive/internal/storage/postgres/queries"
+)
+
+// NewProjectPart adds part to the project part.ProjectUUID. part.ID is ignored.
+func (p ProjectRepository) NewProjectPart(ctx context.Context, part *entities.ProjectPart) (part_id int64, err error) {
+	if part == nil {
+		return 0, errors.New("nil part")
+	}
+
+	artist_id, err := p.participantArtist(ctx, part)
+	if err != nil {
+		return 0, err
+	}
+
+	start, end := partTimestamps(part)
+	return p.q.NewProjectPart(ctx, queries.NewProjectPartParams{
+		Uuid:                 string(part.ProjectUUID),
+		PartNumber:           int16(part.Number),
+		StartMs:              start,
+		EndMs:                end,
+		Status:               queries.Partstatus(part.Status.ToString()),
+		ParticipantChannelID: sql.NullString{String: string(part.Participant), Valid: part.Participant != ""},
+		ParticipantName:      sql.NullString{String: part.ParticipantName, Valid: part.ParticipantName != ""},
+		FileID:               sql.NullInt64{Int64: int64(part.FileID), Valid: part.FileID.IsValid()},
+		YoutubeID:            sql.NullString{String: string(part.YoutubeID), Valid: part.YoutubeID != ""},
+		ParticipantArtistID:  artist_id,
+	})
+}
+
+// UpdateProjectPart overwrites the part with part.ID. Moving a part to another project isn't supported, so
+// part.ProjectUUID is ignored.
+func (p ProjectRepository) UpdateProjectPart(ctx context.Context, part *entities.ProjectPart) (err error) {
+	if part == nil {
+		return errors.New("nil part")
+	}
+
+	artist_id, err := p.participantArtist(ctx, part)
+	if err != nil {
+		return err
+	}
+
+	start, end := partTimestamps(part)
+	rows, err := p.q.UpdateProjectPart(ctx, queries.UpdateProjectPartParams{
+		ID:                   part.ID,
+		PartNumber:           int16(part.Number),
+		StartMs:              start,
+		EndMs:                end,
+		Status:               queries.Partstatus(part.Status.ToString()),
+		ParticipantChannelID: sql.NullString{String: string(part.Participant), Valid: part.Participant != ""},
+		ParticipantName:      sql.NullString{String: part.ParticipantName, Valid: part.ParticipantName != ""},
+		FileID:               sql.NullInt64{Int64: int64(part.FileID), Valid: part.FileID.IsValid()},
+		YoutubeID:            sql.NullString{String: string(part.YoutubeID), Valid: part.YoutubeID != ""},
+		ParticipantArtistID:  artist_id,
+	})
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+func (p ProjectRepository) DeleteProjectPart(ctx context.Context, part_id int64) (err error) {
+	rows, err := p.q.DeleteProjectPart(ctx, part_id)
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+func (p ProjectRepository) GetProjectPart(ctx context.Context, part_id int64) (part *entities.ProjectPart, err error) {
+	res, err := p.q.GetProjectPart(ctx, part_id)
+	if err != nil {
+		return nil, err
+	}
+
+	return toProjectPart(queries.GetProjectPartsRow(res))
+}
+
+// GetProjectParts returns every part of uuid, ordered by part number.
+func (p ProjectRepository) GetProjectParts(ctx context.Context, uuid entities.ProjectUUID) (parts []entities.ProjectPart, err error) {
+	res, err := p.q.GetProjectParts(ctx, string(uuid))
+	if err != nil {
+		return nil, err
+	}
+
+	parts = make([]entities.ProjectPart, 0, len(res))
+	for _, v := range res {
+		part, err := toProjectPart(v)
+		if err != nil {
+			return nil, err
+		}
+		parts = append(parts, *part)
+	}
+
+	return parts, nil
+}
+
+// participantArtist returns the id of the artist named by part.ParticipantArtist, which may be any of their aliases.
+func (p ProjectRepository) participantArtist(ctx context.Context, part *entities.ProjectPart) (artist_id sql.NullInt64, err error) {
+	if part.ParticipantArtist == "" {
+		return sql.NullInt64{}, nil
+	}
+
+	id, err := p.q.GetArtistID(ctx, part.ParticipantArtist)
+	if errors.Is(err, sql.ErrNoRows) {
+		return sql.NullInt64{}, fmt.Errorf("artist %q: %w", part.ParticipantArtist, err)
+	}
+	if err != nil {
+		return sql.NullInt64{}, err
+	}
+
+	return sql.NullInt64{Int64: id, Valid: true}, nil
+}
+
+// partTimestamps returns the start and end of part in milliseconds, both being NULL if its End is unknown.
+func partTimestamps(part *entities.ProjectPart) (start, end sql.NullInt32) {
+	if part.End <= 0 {
+		return sql.NullInt32{}, sql.NullInt32{}
+	}
+
+	return sql.NullInt32{Int32: int32(part.Start.Milliseconds()), Valid: true},
+		sql.NullInt32{Int32: int32(part.End.Milliseconds()), Valid: true}
+}
+
+func toProjectPart(res queries.GetProjectPartsRow) (*entities.ProjectPart, error) {
+	status, err := entities.NewPartStatus(string(res.Status))
+	if err != nil {
+		return nil, err
+	}
+
+	return &entities.ProjectPart{
+		ID:                res.ID,
+		ProjectUUID:       entities.ProjectUUID(res.Uuid),
+		Number:            int(res.PartNumber),
+		Start:             time.Duration(res.StartMs.Int32) * time.Millisecond,
+		End:               time.Duration(res.EndMs.Int32) * time.Millisecond,
+		Status:            status,
+		Participant:       entities.YoutubeChannelID(res.ParticipantChannelID.String),
+		ParticipantArtist: res.ParticipantArtist.String,
+		ParticipantName:   res.ParticipantName.String,
+		FileID:            entities.FileID(res.FileID.Int64),
+		YoutubeID:         entities.YoutubeVideoID(res.YoutubeID.String),
+	}, nil
+}
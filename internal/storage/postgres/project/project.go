@@ -4,9 +4,9 @@ import (
 	"context"
 	"database/sql"
 	"errors"
-	"time"
 
 	"github.com/dtbead/wc-maps-archive/internal/entities"
+	"github.com/dtbead/wc-maps-archive/internal/helper"
 	"github.com/dtbead/wc-maps-archive/internal/storage/postgres/queries"
 )
 
@@ -22,8 +22,31 @@ func NewProjectRepository(db *sql.DB) *ProjectRepository {
 	}
 }
 
-func isEmptyTime(t time.Time) bool {
-	return t.IsZero()
+// partialDateColumns splits d into the date, precision and approximate columns it is stored as. An unknown d is stored
+// as NULL.
+func partialDateColumns(d entities.PartialDate) (date sql.NullTime, precision queries.NullDateprecision, approximate bool) {
+	if d.IsZero() {
+		return sql.NullTime{}, queries.NullDateprecision{}, false
+	}
+
+	d = entities.NewPartialDate(d.Time, d.Precision, d.Approximate)
+	return sql.NullTime{Time: d.Time, Valid: true},
+		queries.NullDateprecision{Dateprecision: queries.Dateprecision(d.Precision.ToString()), Valid: true},
+		d.Approximate
+}
+
+// newPartialDate is the inverse of partialDateColumns.
+func newPartialDate(date sql.NullTime, precision queries.NullDateprecision, approximate bool) (d entities.PartialDate, err error) {
+	if !date.Valid || !precision.Valid {
+		return entities.PartialDate{}, nil
+	}
+
+	p, err := entities.NewDatePrecision(string(precision.Dateprecision))
+	if err != nil {
+		return entities.PartialDate{}, err
+	}
+
+	return entities.NewPartialDate(date.Time, p, approximate), nil
 }
 
 // DateArchived is ignored implicitly.
@@ -32,11 +55,18 @@ func (p ProjectRepository) NewProject(ctx context.Context, project *entities.Pro
 		return entities.InvalidProjectUUID, errors.New("nil project")
 	}
 
+	announced, announced_precision, announced_approximate := partialDateColumns(project.DateAnnounced)
+	completed, completed_precision, completed_approximate := partialDateColumns(project.DateCompleted)
+
 	s, err := p.q.NewProject(ctx, queries.NewProjectParams{
-		Uuid:          project.UUID,
-		Type:          queries.Projecttype(project.ProjectType.ToString()),
-		DateAnnounced: sql.NullTime{Time: project.DateAnnounced.UTC().Truncate(time.Second), Valid: !isEmptyTime(project.DateAnnounced.UTC())},
-		DateCompleted: sql.NullTime{Time: project.DateCompleted.UTC().Truncate(time.Second), Valid: !isEmptyTime(project.DateCompleted.UTC())},
+		Uuid:                     project.UUID,
+		Type:                     queries.Projecttype(project.ProjectType.ToString()),
+		DateAnnounced:            announced,
+		DateAnnouncedPrecision:   announced_precision,
+		DateAnnouncedApproximate: announced_approximate,
+		DateCompleted:            completed,
+		DateCompletedPrecision:   completed_precision,
+		DateCompletedApproximate: completed_approximate,
 	})
 	if err != nil {
 		return entities.InvalidProjectUUID, err
@@ -46,6 +76,63 @@ func (p ProjectRepository) NewProject(ctx context.Context, project *entities.Pro
 
 }
 
+// SetProjectDateAnnounced replaces when uuid was announced with date, which may be unknown.
+func (p ProjectRepository) SetProjectDateAnnounced(ctx context.Context, uuid entities.ProjectUUID, date entities.PartialDate) (err error) {
+	announced, precision, approximate := partialDateColumns(date)
+	rows, err := p.q.UpdateProjectDateAnnounced(ctx, queries.UpdateProjectDateAnnouncedParams{
+		Uuid:                     string(uuid),
+		DateAnnounced:            announced,
+		DateAnnouncedPrecision:   precision,
+		DateAnnouncedApproximate: approximate,
+	})
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// SetProjectType replaces the type of uuid with project_type.
+func (p ProjectRepository) SetProjectType(ctx context.Context, uuid entities.ProjectUUID, project_type entities.ProjectType) (err error) {
+	rows, err := p.q.UpdateProjectType(ctx, queries.UpdateProjectTypeParams{
+		Uuid: string(uuid),
+		Type: queries.Projecttype(project_type.ToString()),
+	})
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// SetProjectDateCompleted replaces when uuid was completed with date, which may be unknown.
+func (p ProjectRepository) SetProjectDateCompleted(ctx context.Context, uuid entities.ProjectUUID, date entities.PartialDate) (err error) {
+	completed, precision, approximate := partialDateColumns(date)
+	rows, err := p.q.UpdateProjectDateCompleted(ctx, queries.UpdateProjectDateCompletedParams{
+		Uuid:                     string(uuid),
+		DateCompleted:            completed,
+		DateCompletedPrecision:   precision,
+		DateCompletedApproximate: approximate,
+	})
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
 func (p ProjectRepository) DeleteProject(ctx context.Context, uuid entities.ProjectUUID) (err error) {
 	return p.q.DeleteProjectByUUID(ctx, string(uuid))
 }
@@ -66,21 +153,58 @@ func (p ProjectRepository) GetProject(ctx context.Context, uuid entities.Project
 		return nil, err
 	}
 
+	title, err := p.q.GetCurrentProjectTitle(ctx, string(uuid))
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+
+	description, err := p.q.GetCurrentProjectDescription(ctx, string(uuid))
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+
+	announced, err := newPartialDate(res.DateAnnounced, res.DateAnnouncedPrecision, res.DateAnnouncedApproximate)
+	if err != nil {
+		return nil, err
+	}
+
+	completed, err := newPartialDate(res.DateCompleted, res.DateCompletedPrecision, res.DateCompletedApproximate)
+	if err != nil {
+		return nil, err
+	}
+
 	return &entities.Project{
 		UUID:          res.Uuid,
+		Title:         title.Title,
+		Description:   description.Description,
 		ProjectType:   project_type,
-		DateAnnounced: res.DateAnnounced.Time,
+		DateAnnounced: announced,
 		DateArchived:  res.DateArchived,
-		DateCompleted: res.DateCompleted.Time,
+		DateCompleted: completed,
 		FileIDs:       file_ids,
 	}, nil
 }
 func (p ProjectRepository) AssignProjectFile(ctx context.Context, uuid entities.ProjectUUID, file_id entities.FileID) (err error) {
 	return p.q.AssignProjectFile(ctx, queries.AssignProjectFileParams{Uuid: string(uuid), FileID: int64(file_id)})
 }
+
+// UnassignProjectVideo removes file_id from the files of uuid, returning sql.ErrNoRows if it wasn't one of them.
 func (p ProjectRepository) UnassignProjectVideo(ctx context.Context, uuid entities.ProjectUUID, file_id entities.FileID) (err error) {
-	return p.q.UnassignProjectFile(ctx, int64(file_id))
+	rows, err := p.q.UnassignProjectFile(ctx, queries.UnassignProjectFileParams{
+		Uuid:   string(uuid),
+		FileID: int64(file_id),
+	})
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
 }
+
 func (p ProjectRepository) GetProjectVideos(ctx context.Context, uuid entities.ProjectUUID) (file_ids []entities.FileID, err error) {
 	res, err := p.q.GetProjectFile(ctx, string(uuid))
 	if err != nil {
@@ -96,6 +220,22 @@ func (p ProjectRepository) GetProjectVideos(ctx context.Context, uuid entities.P
 	return file_ids, nil
 }
 
+// GetFileProjects returns every project file_id belongs to, either as one of its files or as the upload of one of its
+// parts.
+func (p ProjectRepository) GetFileProjects(ctx context.Context, file_id entities.FileID) (uuids []entities.ProjectUUID, err error) {
+	res, err := p.q.GetFileProjects(ctx, int64(file_id))
+	if err != nil {
+		return nil, err
+	}
+
+	uuids = make([]entities.ProjectUUID, 0, len(res))
+	for _, uuid := range res {
+		uuids = append(uuids, entities.ProjectUUID(uuid))
+	}
+
+	return uuids, nil
+}
+
 func (p ProjectRepository) AssignYoutube(ctx context.Context, uuid entities.ProjectUUID, youtube_id entities.YoutubeVideoID) (err error) {
 	return p.q.AssignYoutubeVideoToProject(ctx, queries.AssignYoutubeVideoToProjectParams{
 		Uuid:      string(uuid),
@@ -103,9 +243,138 @@ func (p ProjectRepository) AssignYoutube(ctx context.Context, uuid entities.Proj
 	})
 }
 
+// GetProjectYoutube returns every youtube video one of the files of uuid is an upload of.
+func (p ProjectRepository) GetProjectYoutube(ctx context.Context, uuid entities.ProjectUUID) (youtube_ids []entities.YoutubeVideoID, err error) {
+	res, err := p.q.GetProjectYoutubeIDs(ctx, string(uuid))
+	if err != nil {
+		return nil, err
+	}
+
+	youtube_ids = make([]entities.YoutubeVideoID, 0, len(res))
+	for _, v := range res {
+		id, ok := v.(string)
+		if !ok {
+			return nil, errors.New("unexpected youtube_id type")
+		}
+		youtube_ids = append(youtube_ids, entities.YoutubeVideoID(id))
+	}
+
+	return youtube_ids, nil
+}
+
 func (p ProjectRepository) UnassignYoutube(ctx context.Context, uuid entities.ProjectUUID, youtube_id entities.YoutubeVideoID) (err error) {
 	return p.q.UnassignYoutubeVideoFromProject(ctx, queries.UnassignYoutubeVideoFromProjectParams{
 		Uuid:      string(uuid),
 		YoutubeID: youtube_id,
 	})
 }
+
+// NewProjectTitle makes title the current title of uuid. The previous titles are kept as its history. Setting the
+// current title again does nothing.
+func (p ProjectRepository) NewProjectTitle(ctx context.Context, uuid entities.ProjectUUID, title string) (err error) {
+	tx, err := p.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	q := p.q.WithTx(tx)
+
+	_, err = q.GetProjectByUUID(ctx, string(uuid))
+	if err != nil {
+		return err
+	}
+
+	current, err := q.GetCurrentProjectTitle(ctx, string(uuid))
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+
+	if err == nil && current.Title == title {
+		return nil
+	}
+
+	err = q.NewProjectTitle(ctx, queries.NewProjectTitleParams{
+		Uuid:     string(uuid),
+		Title:    title,
+		TitleMd5: helper.GetMD5HashFromString(title),
+	})
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// GetProjectTitles returns every title uuid has had, newest first.
+func (p ProjectRepository) GetProjectTitles(ctx context.Context, uuid entities.ProjectUUID) (titles []entities.ProjectTitle, err error) {
+	res, err := p.q.GetProjectTitles(ctx, string(uuid))
+	if err != nil {
+		return nil, err
+	}
+
+	titles = make([]entities.ProjectTitle, 0, len(res))
+	for _, v := range res {
+		titles = append(titles, entities.ProjectTitle{
+			ProjectUUID: uuid,
+			Title:       v.Title,
+			DateAdded:   v.DateAdded,
+		})
+	}
+
+	return titles, nil
+}
+
+// NewProjectDescription makes description the current description of uuid. The previous descriptions are kept as its
+// history. Setting the current description again does nothing.
+func (p ProjectRepository) NewProjectDescription(ctx context.Context, uuid entities.ProjectUUID, description string) (err error) {
+	tx, err := p.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	q := p.q.WithTx(tx)
+
+	_, err = q.GetProjectByUUID(ctx, string(uuid))
+	if err != nil {
+		return err
+	}
+
+	current, err := q.GetCurrentProjectDescription(ctx, string(uuid))
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+
+	if err == nil && current.Description == description {
+		return nil
+	}
+
+	err = q.NewProjectDescription(ctx, queries.NewProjectDescriptionParams{
+		Uuid:           string(uuid),
+		Description:    description,
+		DescriptionMd5: helper.GetMD5HashFromString(description),
+	})
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// GetProjectDescriptions returns every description uuid has had, newest first.
+func (p ProjectRepository) GetProjectDescriptions(ctx context.Context, uuid entities.ProjectUUID) (descriptions []entities.ProjectDescription, err error) {
+	res, err := p.q.GetProjectDescriptions(ctx, string(uuid))
+	if err != nil {
+		return nil, err
+	}
+
+	descriptions = make([]entities.ProjectDescription, 0, len(res))
+	for _, v := range res {
+		descriptions = append(descriptions, entities.ProjectDescription{
+			ProjectUUID: uuid,
+			Description: v.Description,
+			DateAdded:   v.DateAdded,
+		})
+	}
+
+	return descriptions, nil
+}
@@ -2,6 +2,8 @@ package project_test
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"os"
 	"reflect"
 	"slices"
@@ -9,6 +11,7 @@ import (
 	"time"
 
 	"github.com/dtbead/wc-maps-archive/internal/entities"
+	"github.com/dtbead/wc-maps-archive/internal/filter"
 	"github.com/dtbead/wc-maps-archive/internal/helper"
 	helper_test "github.com/dtbead/wc-maps-archive/internal/helper/testing"
 	"github.com/dtbead/wc-maps-archive/internal/storage/postgres/file"
@@ -38,8 +41,8 @@ func TestProjectRepository_NewProject(t *testing.T) {
 			UUID:          "r4ruYKZQT3XBtpxPjr6s9k",
 			FileIDs:       nil,
 			ProjectType:   entities.ProjectAnimatedMusicVideo,
-			DateAnnounced: time.Now(),
-			DateCompleted: time.Now(),
+			DateAnnounced: entities.NewPartialDate(time.Now(), entities.DatePrecisionDay, false),
+			DateCompleted: entities.NewPartialDate(time.Now(), entities.DatePrecisionDay, false),
 			DateArchived:  time.Time{},
 		}}, "r4ruYKZQT3XBtpxPjr6s9k", false},
 		{"nil project", *projectRepo, args{context.Background(), &entities.Project{}}, entities.InvalidProjectUUID, true},
@@ -68,8 +71,8 @@ func TestProjectRepository_DeleteProject(t *testing.T) {
 	p := entities.Project{
 		UUID:          helper.RandomUUID(),
 		ProjectType:   entities.ProjectAnimatedMusicVideo,
-		DateAnnounced: time.Now(),
-		DateCompleted: time.Now(),
+		DateAnnounced: entities.NewPartialDate(time.Now(), entities.DatePrecisionDay, false),
+		DateCompleted: entities.NewPartialDate(time.Now(), entities.DatePrecisionDay, false),
 	}
 
 	uuid, err := projectRepo.NewProject(context.Background(), &p)
@@ -110,8 +113,8 @@ func TestProjectRepository_GetProject(t *testing.T) {
 		UUID:          helper.RandomUUID(),
 		FileIDs:       []entities.FileID{},
 		ProjectType:   entities.ProjectAnimatedMusicVideo,
-		DateAnnounced: time.Now().UTC().Round(time.Second),
-		DateCompleted: time.Now().UTC().Round(time.Second),
+		DateAnnounced: entities.NewPartialDate(time.Now(), entities.DatePrecisionYear, true),
+		DateCompleted: entities.NewPartialDate(time.Now(), entities.DatePrecisionMonth, false),
 	}
 
 	uuid, err := projectRepo.NewProject(context.Background(), &p)
@@ -178,8 +181,8 @@ func TestProjectRepository_AssignProjectFile(t *testing.T) {
 	p := entities.Project{
 		UUID:          helper.RandomUUID(),
 		ProjectType:   entities.ProjectAnimatedMusicVideo,
-		DateAnnounced: time.Now(),
-		DateCompleted: time.Now(),
+		DateAnnounced: entities.NewPartialDate(time.Now(), entities.DatePrecisionDay, false),
+		DateCompleted: entities.NewPartialDate(time.Now(), entities.DatePrecisionDay, false),
 	}
 
 	uuid, err := projectRepo.NewProject(context.Background(), &p)
@@ -242,8 +245,8 @@ func TestProjectRepository_AssignProjectFileEmptyUUID(t *testing.T) {
 	_, err = projectRepo.NewProject(context.Background(), &entities.Project{
 		UUID:          helper.RandomUUID(),
 		ProjectType:   entities.ProjectAnimatedMusicVideo,
-		DateAnnounced: time.Now(),
-		DateCompleted: time.Now(),
+		DateAnnounced: entities.NewPartialDate(time.Now(), entities.DatePrecisionDay, false),
+		DateCompleted: entities.NewPartialDate(time.Now(), entities.DatePrecisionDay, false),
 	})
 
 	if err != nil {
@@ -282,8 +285,8 @@ func TestProjectRepository_UnassignProjectVideoInvalidFileID(t *testing.T) {
 	uuid, err := projectRepo.NewProject(context.Background(), &entities.Project{
 		UUID:          helper.RandomUUID(),
 		ProjectType:   entities.ProjectAnimatedMusicVideo,
-		DateAnnounced: time.Now(),
-		DateCompleted: time.Now(),
+		DateAnnounced: entities.NewPartialDate(time.Now(), entities.DatePrecisionDay, false),
+		DateCompleted: entities.NewPartialDate(time.Now(), entities.DatePrecisionDay, false),
 	})
 
 	if err != nil {
@@ -296,3 +299,597 @@ func TestProjectRepository_UnassignProjectVideoInvalidFileID(t *testing.T) {
 		return
 	}
 }
+
+func TestProjectRepository_NewProjectTitle(t *testing.T) {
+	db := helper_test.NewDatabase(&helper_test.DefaultConnection)
+	defer db.Close()
+
+	projectRepo := project.NewProjectRepository(db)
+
+	uuid, err := projectRepo.NewProject(context.Background(), &entities.Project{
+		UUID:        helper.RandomUUID(),
+		ProjectType: entities.ProjectMultiAnimation,
+	})
+	if err != nil {
+		t.Fatalf("failed to create mock project, %v", err.Error())
+	}
+
+	// setting the current title again must not add another revision, while reverting to an older one must
+	for _, title := range []string{"Untitled MAP", "Warriors MAP", "Warriors MAP", "Untitled MAP"} {
+		if err := projectRepo.NewProjectTitle(context.Background(), uuid, title); err != nil {
+			t.Fatalf("ProjectRepository.NewProjectTitle() error = %v", err)
+		}
+	}
+
+	if err := projectRepo.NewProjectDescription(context.Background(), uuid, "a MAP about warrior cats"); err != nil {
+		t.Fatalf("ProjectRepository.NewProjectDescription() error = %v", err)
+	}
+
+	titles, err := projectRepo.GetProjectTitles(context.Background(), uuid)
+	if err != nil {
+		t.Fatalf("ProjectRepository.GetProjectTitles() error = %v", err)
+	}
+
+	got := make([]string, len(titles))
+	for i, title := range titles {
+		got[i] = title.Title
+	}
+
+	want := []string{"Untitled MAP", "Warriors MAP", "Untitled MAP"}
+	if !slices.Equal(got, want) {
+		t.Errorf("ProjectRepository.GetProjectTitles() = %v, want %v", got, want)
+	}
+
+	p, err := projectRepo.GetProject(context.Background(), uuid)
+	if err != nil {
+		t.Fatalf("ProjectRepository.GetProject() error = %v", err)
+	}
+
+	if p.Title != "Untitled MAP" || p.Description != "a MAP about warrior cats" {
+		t.Errorf("ProjectRepository.GetProject() title = %q, description = %q", p.Title, p.Description)
+	}
+
+	if err := projectRepo.NewProjectTitle(context.Background(), "", "Untitled MAP"); err == nil {
+		t.Errorf("ProjectRepository.NewProjectTitle() of missing project error = %v, wantErr %v", err, true)
+	}
+}
+
+func TestProjectRepository_ProjectPart(t *testing.T) {
+	db := helper_test.NewDatabase(&helper_test.DefaultConnection)
+	defer db.Close()
+
+	projectRepo := project.NewProjectRepository(db)
+
+	uuid, err := projectRepo.NewProject(context.Background(), &entities.Project{
+		UUID:        helper.RandomUUID(),
+		ProjectType: entities.ProjectMultiAnimation,
+	})
+	if err != nil {
+		t.Fatalf("failed to create mock project, %v", err.Error())
+	}
+
+	artist := "Leafpool " + string(uuid)
+	if _, err := db.Exec(`INSERT INTO artist (name) VALUES ($1)`, artist); err != nil {
+		t.Fatalf("failed to create mock artist, %v", err)
+	}
+
+	want := []entities.ProjectPart{
+		{ProjectUUID: uuid, Number: 2, Start: 15 * time.Second, End: 30500 * time.Millisecond, Status: entities.PartStatusCompleted, ParticipantName: "Stormpaw"},
+		{ProjectUUID: uuid, Number: 1, End: 15 * time.Second, Status: entities.PartStatusClaimed, ParticipantArtist: artist},
+		{ProjectUUID: uuid, Number: 3, Status: entities.PartStatusOpen},
+	}
+
+	for i := range want {
+		want[i].ID, err = projectRepo.NewProjectPart(context.Background(), &want[i])
+		if err != nil {
+			t.Fatalf("ProjectRepository.NewProjectPart() error = %v", err)
+		}
+	}
+
+	want[2].Status = entities.PartStatusDropped
+	if err := projectRepo.UpdateProjectPart(context.Background(), &want[2]); err != nil {
+		t.Fatalf("ProjectRepository.UpdateProjectPart() error = %v", err)
+	}
+
+	got, err := projectRepo.GetProjectParts(context.Background(), uuid)
+	if err != nil {
+		t.Fatalf("ProjectRepository.GetProjectParts() error = %v", err)
+	}
+
+	// parts come back ordered by their number rather than the order they were added in
+	wantOrdered := []entities.ProjectPart{want[1], want[0], want[2]}
+	if !cmp.Equal(got, wantOrdered) {
+		t.Errorf("got diff %s", cmp.Diff(got, wantOrdered))
+	}
+
+	if err := projectRepo.DeleteProjectPart(context.Background(), want[0].ID); err != nil {
+		t.Fatalf("ProjectRepository.DeleteProjectPart() error = %v", err)
+	}
+
+	if _, err := projectRepo.GetProjectPart(context.Background(), want[0].ID); err == nil {
+		t.Errorf("ProjectRepository.GetProjectPart() of a deleted part error = %v, wantErr %v", err, true)
+	}
+
+	if err := projectRepo.DeleteProjectPart(context.Background(), want[0].ID); err == nil {
+		t.Errorf("ProjectRepository.DeleteProjectPart() of a deleted part error = %v, wantErr %v", err, true)
+	}
+
+	unknown := entities.ProjectPart{ProjectUUID: uuid, Number: 4, ParticipantArtist: "nobody " + string(uuid)}
+	if _, err := projectRepo.NewProjectPart(context.Background(), &unknown); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("ProjectRepository.NewProjectPart() by an unknown artist error = %v, want %v", err, sql.ErrNoRows)
+	}
+
+	// a failing statement aborts the test transaction, so this has to come last
+	if _, err := projectRepo.NewProjectPart(context.Background(), &want[1]); err == nil {
+		t.Errorf("ProjectRepository.NewProjectPart() of a duplicate part number error = %v, wantErr %v", err, true)
+	}
+}
+
+func TestProjectRepository_ProjectRelation(t *testing.T) {
+	db := helper_test.NewDatabase(&helper_test.DefaultConnection)
+	defer db.Close()
+
+	projectRepo := project.NewProjectRepository(db)
+
+	newProject := func(project_type entities.ProjectType) entities.ProjectUUID {
+		uuid, err := projectRepo.NewProject(context.Background(), &entities.Project{
+			UUID:        helper.RandomUUID(),
+			ProjectType: project_type,
+		})
+		if err != nil {
+			t.Fatalf("failed to create mock project, %v", err.Error())
+		}
+		return uuid
+	}
+
+	compilation := newProject(entities.ProjectMultiAnimation)
+	m := newProject(entities.ProjectMultiAnimation)
+	part1 := newProject(entities.ProjectTypeOther)
+	part2 := newProject(entities.ProjectTypeOther)
+
+	for _, r := range []entities.ProjectRelation{
+		{Project: m, Related: compilation, Type: entities.ProjectRelationPartOf},
+		{Project: part1, Related: m, Type: entities.ProjectRelationPartOf},
+		{Project: part2, Related: m, Type: entities.ProjectRelationPartOf},
+	} {
+		if err := projectRepo.NewProjectRelation(context.Background(), r); err != nil {
+			t.Fatalf("ProjectRepository.NewProjectRelation() error = %v", err)
+		}
+	}
+
+	ancestors, err := projectRepo.GetProjectAncestors(context.Background(), part1, entities.ProjectRelationPartOf)
+	if err != nil {
+		t.Fatalf("ProjectRepository.GetProjectAncestors() error = %v", err)
+	}
+
+	wantAncestors := []entities.ProjectRelation{
+		{Project: part1, Related: m, Type: entities.ProjectRelationPartOf, Depth: 1},
+		{Project: m, Related: compilation, Type: entities.ProjectRelationPartOf, Depth: 2},
+	}
+	if !cmp.Equal(ancestors, wantAncestors) {
+		t.Errorf("got diff %s", cmp.Diff(ancestors, wantAncestors))
+	}
+
+	descendants, err := projectRepo.GetProjectDescendants(context.Background(), compilation, entities.ProjectRelationPartOf)
+	if err != nil {
+		t.Fatalf("ProjectRepository.GetProjectDescendants() error = %v", err)
+	}
+
+	if len(descendants) != 3 || descendants[0].Project != m || descendants[1].Depth != 2 || descendants[2].Depth != 2 {
+		t.Errorf("ProjectRepository.GetProjectDescendants() = %v", descendants)
+	}
+
+	// other relation types don't take part in the part-of tree
+	sequels, err := projectRepo.GetProjectDescendants(context.Background(), compilation, entities.ProjectRelationSequelOf)
+	if err != nil {
+		t.Fatalf("ProjectRepository.GetProjectDescendants() error = %v", err)
+	}
+
+	if len(sequels) != 0 {
+		t.Errorf("ProjectRepository.GetProjectDescendants() of sequels = %v, want none", sequels)
+	}
+
+	err = projectRepo.NewProjectRelation(context.Background(), entities.ProjectRelation{Project: compilation, Related: part1, Type: entities.ProjectRelationPartOf})
+	if !errors.Is(err, entities.ErrorProjectRelationCycle) {
+		t.Errorf("ProjectRepository.NewProjectRelation() closing a cycle error = %v, want %v", err, entities.ErrorProjectRelationCycle)
+	}
+
+	// the same projects may still be related in a different way
+	err = projectRepo.NewProjectRelation(context.Background(), entities.ProjectRelation{Project: compilation, Related: part1, Type: entities.ProjectRelationReuploadOf})
+	if err != nil {
+		t.Errorf("ProjectRepository.NewProjectRelation() error = %v", err)
+	}
+
+	relations, err := projectRepo.GetProjectRelations(context.Background(), part1)
+	if err != nil {
+		t.Fatalf("ProjectRepository.GetProjectRelations() error = %v", err)
+	}
+
+	if len(relations) != 2 {
+		t.Errorf("ProjectRepository.GetProjectRelations() = %v, want 2 relations", relations)
+	}
+
+	if err := projectRepo.DeleteProjectRelation(context.Background(), relations[0]); err != nil {
+		t.Errorf("ProjectRepository.DeleteProjectRelation() error = %v", err)
+	}
+
+	// a part belongs to a single MAP; a failing statement aborts the test transaction, so this has to come last
+	err = projectRepo.NewProjectRelation(context.Background(), entities.ProjectRelation{Project: part2, Related: compilation, Type: entities.ProjectRelationPartOf})
+	if err == nil {
+		t.Errorf("ProjectRepository.NewProjectRelation() of a second MAP error = %v, wantErr %v", err, true)
+	}
+}
+
+func TestProjectRepository_ListProjects(t *testing.T) {
+	db := helper_test.NewDatabase(&helper_test.DefaultConnection)
+	defer db.Close()
+
+	projectRepo := project.NewProjectRepository(db)
+	ctx := context.Background()
+
+	// every title carries tag, so that projects already in the database aren't listed
+	tag := helper.RandomString(12)
+
+	newProject := func(project_type entities.ProjectType, title string, announced string) entities.ProjectUUID {
+		date, err := entities.ParsePartialDate(announced)
+		if err != nil {
+			t.Fatalf("entities.ParsePartialDate() error = %v", err)
+		}
+
+		uuid, err := projectRepo.NewProject(ctx, &entities.Project{
+			UUID:          helper.RandomUUID(),
+			ProjectType:   project_type,
+			DateAnnounced: date,
+		})
+		if err != nil {
+			t.Fatalf("failed to create mock project, %v", err)
+		}
+
+		if err := projectRepo.NewProjectTitle(ctx, uuid, tag+" "+title); err != nil {
+			t.Fatalf("failed to create mock project title, %v", err)
+		}
+		return uuid
+	}
+
+	firestar := newProject(entities.ProjectMultiAnimation, "Firestar MAP", "2015-03")
+	ashfur := newProject(entities.ProjectMultiAnimation, "Ashfur MAP", "~2017")
+	leafstar := newProject(entities.ProjectMultiEdit, "Leafstar MAP", "2015")
+	meme := newProject(entities.ProjectAnimationMeme, "Firestar meme", "")
+
+	if _, err := projectRepo.NewProjectPart(ctx, &entities.ProjectPart{ProjectUUID: ashfur, Number: 1}); err != nil {
+		t.Fatalf("failed to create mock part, %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		query   string
+		want    []entities.ProjectUUID
+		wantErr bool
+	}{
+		{"everything", "", []entities.ProjectUUID{firestar, ashfur, leafstar, meme}, false},
+		{"type", `type:"multi-animation"`, []entities.ProjectUUID{firestar, ashfur}, false},
+		{"negated type", `firestar -type:"animation meme"`, []entities.ProjectUUID{firestar}, false},
+		{"date range", "announced:2014..2016", []entities.ProjectUUID{firestar, leafstar}, false},
+		{"after a year", "announced:>2015", []entities.ProjectUUID{ashfur}, false},
+		{"within a year", "announced:2015", []entities.ProjectUUID{firestar, leafstar}, false},
+		{"within a month", "announced:2015-03", []entities.ProjectUUID{firestar}, false},
+		{"before a month", "announced:<2015-06", []entities.ProjectUUID{firestar}, false},
+		{"has", "has:parts", []entities.ProjectUUID{ashfur}, false},
+		{"count", "parts:0", []entities.ProjectUUID{firestar, leafstar, meme}, false},
+		{"unknown field", "bogus:1", nil, true},
+		{"invalid number", "parts:many", nil, true},
+		{"unknown type", "type:film", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := filter.Parse(tag + " " + tt.query)
+			if err != nil {
+				t.Fatalf("filter.Parse() error = %v", err)
+			}
+
+			got, _, err := projectRepo.ListProjects(ctx, f, entities.ListOptions{Ascending: true})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ProjectRepository.ListProjects() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !cmp.Equal(got, tt.want) {
+				t.Errorf("got diff %s", cmp.Diff(got, tt.want))
+			}
+		})
+	}
+
+	// projects archived together are listed in the order they were made in, which is what pages are checked against
+	f, err := filter.Parse(tag)
+	if err != nil {
+		t.Fatalf("filter.Parse() error = %v", err)
+	}
+
+	opts := entities.ListOptions{Ascending: true, Limit: 2}
+	got, next_cursor, err := projectRepo.ListProjects(ctx, f, opts)
+	if err != nil {
+		t.Fatalf("ProjectRepository.ListProjects() error = %v", err)
+	}
+
+	if !cmp.Equal(got, []entities.ProjectUUID{firestar, ashfur}) || next_cursor == "" {
+		t.Fatalf("ProjectRepository.ListProjects() first page = %v, %q, want %v and a cursor", got, next_cursor, []entities.ProjectUUID{firestar, ashfur})
+	}
+
+	opts.Cursor = next_cursor
+	got, next_cursor, err = projectRepo.ListProjects(ctx, f, opts)
+	if err != nil {
+		t.Fatalf("ProjectRepository.ListProjects() error = %v", err)
+	}
+
+	if !cmp.Equal(got, []entities.ProjectUUID{leafstar, meme}) || next_cursor != "" {
+		t.Errorf("ProjectRepository.ListProjects() last page = %v, %q, want %v and no cursor", got, next_cursor, []entities.ProjectUUID{leafstar, meme})
+	}
+
+	// partial dates sort by the middle of their period, and unknown ones first when ascending
+	got, _, err = projectRepo.ListProjects(ctx, f, entities.ListOptions{Sort: entities.ListSortAnnouncedDate, Ascending: true})
+	if err != nil {
+		t.Fatalf("ProjectRepository.ListProjects() error = %v", err)
+	}
+
+	if want := []entities.ProjectUUID{meme, firestar, leafstar, ashfur}; !cmp.Equal(got, want) {
+		t.Errorf("ProjectRepository.ListProjects() by announced date got diff %s", cmp.Diff(got, want))
+	}
+
+	// a cursor only continues the order it was given out for
+	opts.Sort = entities.ListSortDuration
+	if _, _, err := projectRepo.ListProjects(ctx, f, opts); !errors.Is(err, entities.ErrorInvalidCursor) {
+		t.Errorf("ProjectRepository.ListProjects() with a cursor of another sort error = %v, want %v", err, entities.ErrorInvalidCursor)
+	}
+}
+
+func TestProjectRepository_ProjectStatus(t *testing.T) {
+	db := helper_test.NewDatabase(&helper_test.DefaultConnection)
+	defer db.Close()
+
+	projectRepo := project.NewProjectRepository(db)
+	ctx := context.Background()
+
+	uuid, err := projectRepo.NewProject(ctx, &entities.Project{
+		UUID:        helper.RandomUUID(),
+		ProjectType: entities.ProjectMultiAnimation,
+	})
+	if err != nil {
+		t.Fatalf("failed to create mock project, %v", err)
+	}
+
+	timeline := []entities.ProjectStatusChange{
+		{Status: entities.ProjectStatusAnnounced, Date: time.Date(2015, 3, 1, 0, 0, 0, 0, time.UTC)},
+		{Status: entities.ProjectStatusSignupsOpen, Date: time.Date(2015, 3, 1, 0, 0, 0, 0, time.UTC), Note: "in the description"},
+		{Status: entities.ProjectStatusCancelled, Date: time.Date(2016, 1, 9, 0, 0, 0, 0, time.UTC)},
+	}
+	for i := range timeline {
+		timeline[i].ID, err = projectRepo.NewProjectStatus(ctx, uuid, timeline[i], nil)
+		if err != nil {
+			t.Fatalf("ProjectRepository.NewProjectStatus() error = %v", err)
+		}
+	}
+
+	got, err := projectRepo.GetProjectStatuses(ctx, uuid)
+	if err != nil {
+		t.Fatalf("ProjectRepository.GetProjectStatuses() error = %v", err)
+	}
+
+	for i := range got {
+		got[i].DateAdded = time.Time{}
+	}
+
+	if !cmp.Equal(got, timeline) {
+		t.Errorf("got diff %s", cmp.Diff(got, timeline))
+	}
+
+	// check sees the timeline as it stands, and refusing the change leaves it be
+	errRefused := errors.New("refused")
+	revived := entities.ProjectStatusChange{Status: entities.ProjectStatusRevived, Date: time.Date(2017, 1, 1, 0, 0, 0, 0, time.UTC)}
+	_, err = projectRepo.NewProjectStatus(ctx, uuid, revived, func(checked []entities.ProjectStatusChange) error {
+		if len(checked) != len(timeline) {
+			t.Errorf("ProjectRepository.NewProjectStatus() checked %d statuses, want %d", len(checked), len(timeline))
+		}
+		return errRefused
+	})
+	if !errors.Is(err, errRefused) {
+		t.Errorf("ProjectRepository.NewProjectStatus() refused by check error = %v, want %v", err, errRefused)
+	}
+
+	if got, err := projectRepo.GetProjectStatuses(ctx, uuid); err != nil || len(got) != len(timeline) {
+		t.Errorf("ProjectRepository.GetProjectStatuses() after a refused change = %v, %v, want %d statuses", got, err, len(timeline))
+	}
+
+	_, err = projectRepo.NewProjectStatus(ctx, entities.ProjectUUID(helper.RandomUUID()), revived, func([]entities.ProjectStatusChange) error { return nil })
+	if !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("ProjectRepository.NewProjectStatus() of an unknown project error = %v, want %v", err, sql.ErrNoRows)
+	}
+
+	p, err := projectRepo.GetProject(ctx, uuid)
+	if err != nil {
+		t.Fatalf("ProjectRepository.GetProject() error = %v", err)
+	}
+
+	if want := entities.NewPartialDate(timeline[0].Date, entities.DatePrecisionDay, false); !cmp.Equal(p.DateAnnounced, want) {
+		t.Errorf("ProjectRepository.GetProject() DateAnnounced = %v, want %v", p.DateAnnounced, want)
+	}
+
+	list := func(query string) []entities.ProjectUUID {
+		t.Helper()
+
+		f, err := filter.Parse(query)
+		if err != nil {
+			t.Fatalf("filter.Parse() error = %v", err)
+		}
+
+		uuids, _, err := projectRepo.ListProjects(ctx, f, entities.ListOptions{Limit: entities.MaxListLimit})
+		if err != nil {
+			t.Fatalf("ProjectRepository.ListProjects() error = %v", err)
+		}
+		return uuids
+	}
+
+	if uuids := list("status:cancelled was:announced -has:part-footage"); !slices.Contains(uuids, uuid) {
+		t.Errorf("ProjectRepository.ListProjects() = %v, want it to contain %v", uuids, uuid)
+	}
+
+	if err := projectRepo.DeleteLatestProjectStatus(ctx, uuid); err != nil {
+		t.Fatalf("ProjectRepository.DeleteLatestProjectStatus() error = %v", err)
+	}
+
+	if uuids := list("status:cancelled"); slices.Contains(uuids, uuid) {
+		t.Errorf("ProjectRepository.ListProjects() = %v, want it to no longer contain %v", uuids, uuid)
+	}
+
+	if uuids := list(`status:"sign-ups open" was:cancelled`); slices.Contains(uuids, uuid) {
+		t.Errorf("ProjectRepository.ListProjects() = %v, want it to no longer contain %v", uuids, uuid)
+	}
+}
+
+func TestProjectRepository_SetProjectDates(t *testing.T) {
+	db := helper_test.NewDatabase(&helper_test.DefaultConnection)
+	defer db.Close()
+
+	projectRepo := project.NewProjectRepository(db)
+	ctx := context.Background()
+
+	uuid, err := projectRepo.NewProject(ctx, &entities.Project{
+		UUID:          helper.RandomUUID(),
+		ProjectType:   entities.ProjectMultiAnimation,
+		DateAnnounced: entities.NewPartialDate(time.Now(), entities.DatePrecisionDay, false),
+	})
+	if err != nil {
+		t.Fatalf("failed to create mock project, %v", err)
+	}
+
+	// summer 2013, as best as anyone remembers
+	announced, err := entities.ParsePartialDate("~2013-07")
+	if err != nil {
+		t.Fatalf("entities.ParsePartialDate() error = %v", err)
+	}
+
+	completed, err := entities.ParsePartialDate("2014")
+	if err != nil {
+		t.Fatalf("entities.ParsePartialDate() error = %v", err)
+	}
+
+	if err := projectRepo.SetProjectDateAnnounced(ctx, uuid, announced); err != nil {
+		t.Fatalf("ProjectRepository.SetProjectDateAnnounced() error = %v", err)
+	}
+
+	if err := projectRepo.SetProjectDateCompleted(ctx, uuid, completed); err != nil {
+		t.Fatalf("ProjectRepository.SetProjectDateCompleted() error = %v", err)
+	}
+
+	p, err := projectRepo.GetProject(ctx, uuid)
+	if err != nil {
+		t.Fatalf("ProjectRepository.GetProject() error = %v", err)
+	}
+
+	if p.DateAnnounced.String() != "~2013-07" || p.DateCompleted.String() != "2014" {
+		t.Errorf("ProjectRepository.GetProject() dates = %q, %q, want %q, %q", p.DateAnnounced.String(), p.DateCompleted.String(), "~2013-07", "2014")
+	}
+
+	// an unknown date clears it
+	if err := projectRepo.SetProjectDateCompleted(ctx, uuid, entities.PartialDate{}); err != nil {
+		t.Fatalf("ProjectRepository.SetProjectDateCompleted() error = %v", err)
+	}
+
+	p, err = projectRepo.GetProject(ctx, uuid)
+	if err != nil {
+		t.Fatalf("ProjectRepository.GetProject() error = %v", err)
+	}
+
+	if !p.DateCompleted.IsZero() {
+		t.Errorf("ProjectRepository.GetProject() DateCompleted = %q, want it unknown", p.DateCompleted.String())
+	}
+
+	if err := projectRepo.SetProjectDateAnnounced(ctx, "", announced); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("ProjectRepository.SetProjectDateAnnounced() of a missing project error = %v, want %v", err, sql.ErrNoRows)
+	}
+}
+
+func TestProjectRepository_SetProjectType(t *testing.T) {
+	db := helper_test.NewDatabase(&helper_test.DefaultConnection)
+	defer db.Close()
+
+	projectRepo := project.NewProjectRepository(db)
+	ctx := context.Background()
+
+	uuid, err := projectRepo.NewProject(ctx, &entities.Project{UUID: helper.RandomUUID(), ProjectType: entities.ProjectMultiAnimation})
+	if err != nil {
+		t.Fatalf("failed to create mock project, %v", err)
+	}
+
+	if err := projectRepo.SetProjectType(ctx, uuid, entities.ProjectAnimatedMusicVideo); err != nil {
+		t.Fatalf("ProjectRepository.SetProjectType() error = %v", err)
+	}
+
+	p, err := projectRepo.GetProject(ctx, uuid)
+	if err != nil {
+		t.Fatalf("ProjectRepository.GetProject() error = %v", err)
+	}
+
+	if p.ProjectType != entities.ProjectAnimatedMusicVideo {
+		t.Errorf("ProjectRepository.GetProject() type = %v, want %v", p.ProjectType, entities.ProjectAnimatedMusicVideo)
+	}
+
+	if err := projectRepo.SetProjectType(ctx, "", entities.ProjectAnimatedMusicVideo); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("ProjectRepository.SetProjectType() of a missing project error = %v, want %v", err, sql.ErrNoRows)
+	}
+}
+
+func TestProjectRepository_UnassignProjectVideo(t *testing.T) {
+	db := helper_test.NewDatabase(&helper_test.DefaultConnection)
+	defer db.Close()
+
+	projectRepo := project.NewProjectRepository(db)
+	ctx := context.Background()
+
+	fileRepo, err := file.NewFileRepository(db, t.TempDir())
+	if err != nil {
+		t.Fatalf("failed to create file repository, %v", err.Error())
+	}
+
+	f, err := os.Open("testdata/y_wo8pyoxyk.mkv")
+	if err != nil {
+		t.Fatalf("failed to open test file, %v", err.Error())
+	}
+	defer f.Close()
+
+	file_id, err := fileRepo.NewFile(ctx, f, "mkv")
+	if err != nil {
+		t.Fatalf("failed to store test file in file repo, %v", err.Error())
+	}
+
+	// the same file belongs to both projects, and unassigning it from one leaves it in the other
+	var uuids []entities.ProjectUUID
+	for range 2 {
+		uuid, err := projectRepo.NewProject(ctx, &entities.Project{UUID: helper.RandomUUID(), ProjectType: entities.ProjectMultiAnimation})
+		if err != nil {
+			t.Fatalf("failed to create mock project, %v", err)
+		}
+
+		if err := projectRepo.AssignProjectFile(ctx, uuid, file_id); err != nil {
+			t.Fatalf("ProjectRepository.AssignProjectFile() error = %v", err)
+		}
+		uuids = append(uuids, uuid)
+	}
+
+	if err := projectRepo.UnassignProjectVideo(ctx, uuids[0], file_id); err != nil {
+		t.Fatalf("ProjectRepository.UnassignProjectVideo() error = %v", err)
+	}
+
+	for i, want := range [][]entities.FileID{{}, {file_id}} {
+		got, err := projectRepo.GetProjectVideos(ctx, uuids[i])
+		if err != nil {
+			t.Fatalf("ProjectRepository.GetProjectVideos() error = %v", err)
+		}
+
+		if !cmp.Equal(got, want) {
+			t.Errorf("ProjectRepository.GetProjectVideos() of project %d got diff %s", i, cmp.Diff(got, want))
+		}
+	}
+
+	if err := projectRepo.UnassignProjectVideo(ctx, uuids[0], file_id); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("ProjectRepository.UnassignProjectVideo() of an unassigned file error = %v, want %v", err, sql.ErrNoRows)
+	}
+}
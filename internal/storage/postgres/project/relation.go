@@ -0,0 +1,144 @@
+package project
+
+import (
+	"context"
+	"database/sql"
+	"slices"
+
+	"github.com/dtbead/wc-maps-archive/internal/entities"
+	"github.com/dtbead/wc-maps-archive/internal/storage/postgres/queries"
+)
+
+// NewProjectRelation stores relation, returning entities.ErrorProjectRelationCycle if relation.Related is already
+// related to relation.Project through relations of the same type. relation.Depth is ignored.
+func (p ProjectRepository) NewProjectRelation(ctx context.Context, relation entities.ProjectRelation) (err error) {
+	relation_type := queries.Projectrelationtype(relation.Type.ToString())
+
+	tx, err := p.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	q := p.q.WithTx(tx)
+
+	// keep two concurrent inserts from each closing half of the same cycle
+	err = q.LockProjectRelations(ctx)
+	if err != nil {
+		return err
+	}
+
+	ancestors, err := q.GetProjectRelationAncestors(ctx, queries.GetProjectRelationAncestorsParams{
+		Uuid: string(relation.Related),
+		Type: relation_type,
+	})
+	if err != nil {
+		return err
+	}
+
+	if relation.Project == relation.Related || slices.ContainsFunc(ancestors, func(a queries.GetProjectRelationAncestorsRow) bool {
+		return a.RelatedUuid == string(relation.Project)
+	}) {
+		return entities.ErrorProjectRelationCycle
+	}
+
+	err = q.NewProjectRelation(ctx, queries.NewProjectRelationParams{
+		Uuid:   string(relation.Project),
+		Uuid_2: string(relation.Related),
+		Type:   relation_type,
+	})
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (p ProjectRepository) DeleteProjectRelation(ctx context.Context, relation entities.ProjectRelation) (err error) {
+	rows, err := p.q.DeleteProjectRelation(ctx, queries.DeleteProjectRelationParams{
+		Uuid:   string(relation.Project),
+		Uuid_2: string(relation.Related),
+		Type:   queries.Projectrelationtype(relation.Type.ToString()),
+	})
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// GetProjectRelations returns every relation uuid is either side of.
+func (p ProjectRepository) GetProjectRelations(ctx context.Context, uuid entities.ProjectUUID) (relations []entities.ProjectRelation, err error) {
+	res, err := p.q.GetProjectRelations(ctx, string(uuid))
+	if err != nil {
+		return nil, err
+	}
+
+	relations = make([]entities.ProjectRelation, 0, len(res))
+	for _, v := range res {
+		relation_type, err := entities.NewProjectRelationType(string(v.Type))
+		if err != nil {
+			return nil, err
+		}
+
+		relations = append(relations, entities.ProjectRelation{
+			Project: entities.ProjectUUID(v.ProjectUuid),
+			Related: entities.ProjectUUID(v.RelatedUuid),
+			Type:    relation_type,
+			Depth:   1,
+		})
+	}
+
+	return relations, nil
+}
+
+// GetProjectAncestors walks relations of relation_type up from uuid, such as from a part to its MAP. Relations closer
+// to uuid come first.
+func (p ProjectRepository) GetProjectAncestors(ctx context.Context, uuid entities.ProjectUUID, relation_type entities.ProjectRelationType) (relations []entities.ProjectRelation, err error) {
+	res, err := p.q.GetProjectRelationAncestors(ctx, queries.GetProjectRelationAncestorsParams{
+		Uuid: string(uuid),
+		Type: queries.Projectrelationtype(relation_type.ToString()),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	relations = make([]entities.ProjectRelation, 0, len(res))
+	for _, v := range res {
+		relations = append(relations, entities.ProjectRelation{
+			Project: entities.ProjectUUID(v.ProjectUuid),
+			Related: entities.ProjectUUID(v.RelatedUuid),
+			Type:    relation_type,
+			Depth:   int(v.Depth),
+		})
+	}
+
+	return relations, nil
+}
+
+// GetProjectDescendants walks relations of relation_type down from uuid, such as from a MAP to its parts. Relations
+// closer to uuid come first.
+func (p ProjectRepository) GetProjectDescendants(ctx context.Context, uuid entities.ProjectUUID, relation_type entities.ProjectRelationType) (relations []entities.ProjectRelation, err error) {
+	res, err := p.q.GetProjectRelationDescendants(ctx, queries.GetProjectRelationDescendantsParams{
+		Uuid: string(uuid),
+		Type: queries.Projectrelationtype(relation_type.ToString()),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	relations = make([]entities.ProjectRelation, 0, len(res))
+	for _, v := range res {
+		relations = append(relations, entities.ProjectRelation{
+			Project: entities.ProjectUUID(v.ProjectUuid),
+			Related: entities.ProjectUUID(v.RelatedUuid),
+			Type:    relation_type,
+			Depth:   int(v.Depth),
+		})
+	}
+
+	return relations, nil
+}
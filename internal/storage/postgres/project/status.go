@@ -0,0 +1,122 @@
+package project
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/dtbead/wc-maps-archive/internal/entities"
+	"github.com/dtbead/wc-maps-archive/internal/storage/postgres/queries"
+)
+
+// NewProjectStatus adds change to the timeline of uuid. A non-nil check is given the current timeline and refuses the
+// change by returning an error, with the project locked until the change is added so that another change can't come
+// in between.
+// Being announced or completed also sets the project's DateAnnounced or DateCompleted to the day of change, unless it
+// already has one.
+// change.ID and change.DateAdded are ignored.
+func (p ProjectRepository) NewProjectStatus(ctx context.Context, uuid entities.ProjectUUID, change entities.ProjectStatusChange, check func(timeline []entities.ProjectStatusChange) error) (status_id int64, err error) {
+	date := change.Date.UTC().Truncate(time.Second)
+
+	tx, err := p.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+	q := p.q.WithTx(tx)
+
+	if check != nil {
+		_, err = q.LockProject(ctx, string(uuid))
+		if err != nil {
+			return 0, err
+		}
+
+		res, err := q.GetProjectStatuses(ctx, string(uuid))
+		if err != nil {
+			return 0, err
+		}
+
+		timeline, err := toTimeline(res)
+		if err != nil {
+			return 0, err
+		}
+
+		if err := check(timeline); err != nil {
+			return 0, err
+		}
+	}
+
+	status_id, err = q.NewProjectStatus(ctx, queries.NewProjectStatusParams{
+		Uuid:   string(uuid),
+		Status: queries.Projectstatus(change.Status.ToString()),
+		Date:   date,
+		Note:   change.Note,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	day := entities.NewPartialDate(date, entities.DatePrecisionDay, false)
+	switch change.Status {
+	case entities.ProjectStatusAnnounced:
+		err = q.SetProjectDateAnnounced(ctx, queries.SetProjectDateAnnouncedParams{
+			Uuid:          string(uuid),
+			DateAnnounced: sql.NullTime{Time: day.Time, Valid: true},
+		})
+	case entities.ProjectStatusCompleted:
+		err = q.SetProjectDateCompleted(ctx, queries.SetProjectDateCompletedParams{
+			Uuid:          string(uuid),
+			DateCompleted: sql.NullTime{Time: day.Time, Valid: true},
+		})
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	return status_id, tx.Commit()
+}
+
+// GetProjectStatuses returns the timeline of uuid, oldest first. The last entry is the current status.
+func (p ProjectRepository) GetProjectStatuses(ctx context.Context, uuid entities.ProjectUUID) (timeline []entities.ProjectStatusChange, err error) {
+	res, err := p.q.GetProjectStatuses(ctx, string(uuid))
+	if err != nil {
+		return nil, err
+	}
+
+	return toTimeline(res)
+}
+
+func toTimeline(res []queries.GetProjectStatusesRow) (timeline []entities.ProjectStatusChange, err error) {
+	timeline = make([]entities.ProjectStatusChange, 0, len(res))
+	for _, v := range res {
+		status, err := entities.NewProjectStatus(string(v.Status))
+		if err != nil {
+			return nil, err
+		}
+
+		timeline = append(timeline, entities.ProjectStatusChange{
+			ID:        v.ID,
+			Status:    status,
+			Date:      v.Date,
+			Note:      v.Note,
+			DateAdded: v.DateAdded,
+		})
+	}
+
+	return timeline, nil
+}
+
+// DeleteLatestProjectStatus removes the current status of uuid from its timeline, going back to the one before it.
+// DateAnnounced and DateCompleted are left as they are.
+func (p ProjectRepository) DeleteLatestProjectStatus(ctx context.Context, uuid entities.ProjectUUID) (err error) {
+	rows, err := p.q.DeleteLatestProjectStatus(ctx, string(uuid))
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
@@ -24,9 +24,15 @@ func New(db DBTX) *Queries {
 func Prepare(ctx context.Context, db DBTX) (*Queries, error) {
 	q := Queries{db: db}
 	var err error
+	if q.assignArtistChannelStmt, err = db.PrepareContext(ctx, assignArtistChannel); err != nil {
+		return nil, fmt.Errorf("error preparing query AssignArtistChannel: %w", err)
+	}
 	if q.assignProjectFileStmt, err = db.PrepareContext(ctx, assignProjectFile); err != nil {
 		return nil, fmt.Errorf("error preparing query AssignProjectFile: %w", err)
 	}
+	if q.assignProjectMusicStmt, err = db.PrepareContext(ctx, assignProjectMusic); err != nil {
+		return nil, fmt.Errorf("error preparing query AssignProjectMusic: %w", err)
+	}
 	if q.assignYoutubeDescriptionStmt, err = db.PrepareContext(ctx, assignYoutubeDescription); err != nil {
 		return nil, fmt.Errorf("error preparing query AssignYoutubeDescription: %w", err)
 	}
@@ -39,33 +45,219 @@ func Prepare(ctx context.Context, db DBTX) (*Queries, error) {
 	if q.assignYoutubeVideoToProjectStmt, err = db.PrepareContext(ctx, assignYoutubeVideoToProject); err != nil {
 		return nil, fmt.Errorf("error preparing query AssignYoutubeVideoToProject: %w", err)
 	}
+	if q.deleteArtistStmt, err = db.PrepareContext(ctx, deleteArtist); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteArtist: %w", err)
+	}
+	if q.deleteArtistAliasStmt, err = db.PrepareContext(ctx, deleteArtistAlias); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteArtistAlias: %w", err)
+	}
+	if q.deleteCharacterStmt, err = db.PrepareContext(ctx, deleteCharacter); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteCharacter: %w", err)
+	}
+	if q.deleteCharacterAliasStmt, err = db.PrepareContext(ctx, deleteCharacterAlias); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteCharacterAlias: %w", err)
+	}
 	if q.deleteFileByIDStmt, err = db.PrepareContext(ctx, deleteFileByID); err != nil {
 		return nil, fmt.Errorf("error preparing query DeleteFileByID: %w", err)
 	}
+	if q.deleteFileIntentStmt, err = db.PrepareContext(ctx, deleteFileIntent); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteFileIntent: %w", err)
+	}
+	if q.deleteFileProbeMismatchesStmt, err = db.PrepareContext(ctx, deleteFileProbeMismatches); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteFileProbeMismatches: %w", err)
+	}
+	if q.deleteFileProbeStreamsStmt, err = db.PrepareContext(ctx, deleteFileProbeStreams); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteFileProbeStreams: %w", err)
+	}
+	if q.deleteLatestProjectStatusStmt, err = db.PrepareContext(ctx, deleteLatestProjectStatus); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteLatestProjectStatus: %w", err)
+	}
+	if q.deleteMusicStmt, err = db.PrepareContext(ctx, deleteMusic); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteMusic: %w", err)
+	}
 	if q.deleteProjectByUUIDStmt, err = db.PrepareContext(ctx, deleteProjectByUUID); err != nil {
 		return nil, fmt.Errorf("error preparing query DeleteProjectByUUID: %w", err)
 	}
+	if q.deleteProjectPartStmt, err = db.PrepareContext(ctx, deleteProjectPart); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteProjectPart: %w", err)
+	}
+	if q.deleteProjectRelationStmt, err = db.PrepareContext(ctx, deleteProjectRelation); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteProjectRelation: %w", err)
+	}
+	if q.exportSnapshotStmt, err = db.PrepareContext(ctx, exportSnapshot); err != nil {
+		return nil, fmt.Errorf("error preparing query ExportSnapshot: %w", err)
+	}
+	if q.findCharactersStmt, err = db.PrepareContext(ctx, findCharacters); err != nil {
+		return nil, fmt.Errorf("error preparing query FindCharacters: %w", err)
+	}
+	if q.findMusicStmt, err = db.PrepareContext(ctx, findMusic); err != nil {
+		return nil, fmt.Errorf("error preparing query FindMusic: %w", err)
+	}
+	if q.getAllFileProbeMismatchesStmt, err = db.PrepareContext(ctx, getAllFileProbeMismatches); err != nil {
+		return nil, fmt.Errorf("error preparing query GetAllFileProbeMismatches: %w", err)
+	}
+	if q.getArtistStmt, err = db.PrepareContext(ctx, getArtist); err != nil {
+		return nil, fmt.Errorf("error preparing query GetArtist: %w", err)
+	}
+	if q.getArtistAliasesStmt, err = db.PrepareContext(ctx, getArtistAliases); err != nil {
+		return nil, fmt.Errorf("error preparing query GetArtistAliases: %w", err)
+	}
+	if q.getArtistChannelsStmt, err = db.PrepareContext(ctx, getArtistChannels); err != nil {
+		return nil, fmt.Errorf("error preparing query GetArtistChannels: %w", err)
+	}
+	if q.getArtistIDStmt, err = db.PrepareContext(ctx, getArtistID); err != nil {
+		return nil, fmt.Errorf("error preparing query GetArtistID: %w", err)
+	}
+	if q.getArtistProjectPartsStmt, err = db.PrepareContext(ctx, getArtistProjectParts); err != nil {
+		return nil, fmt.Errorf("error preparing query GetArtistProjectParts: %w", err)
+	}
+	if q.getArtistYoutubeVideosStmt, err = db.PrepareContext(ctx, getArtistYoutubeVideos); err != nil {
+		return nil, fmt.Errorf("error preparing query GetArtistYoutubeVideos: %w", err)
+	}
+	if q.getAuditEntriesStmt, err = db.PrepareContext(ctx, getAuditEntries); err != nil {
+		return nil, fmt.Errorf("error preparing query GetAuditEntries: %w", err)
+	}
+	if q.getAuditEntryStmt, err = db.PrepareContext(ctx, getAuditEntry); err != nil {
+		return nil, fmt.Errorf("error preparing query GetAuditEntry: %w", err)
+	}
+	if q.getChannelArtistsStmt, err = db.PrepareContext(ctx, getChannelArtists); err != nil {
+		return nil, fmt.Errorf("error preparing query GetChannelArtists: %w", err)
+	}
+	if q.getCharacterStmt, err = db.PrepareContext(ctx, getCharacter); err != nil {
+		return nil, fmt.Errorf("error preparing query GetCharacter: %w", err)
+	}
+	if q.getCharacterAliasesStmt, err = db.PrepareContext(ctx, getCharacterAliases); err != nil {
+		return nil, fmt.Errorf("error preparing query GetCharacterAliases: %w", err)
+	}
+	if q.getCharacterProjectsStmt, err = db.PrepareContext(ctx, getCharacterProjects); err != nil {
+		return nil, fmt.Errorf("error preparing query GetCharacterProjects: %w", err)
+	}
+	if q.getCurrentProjectDescriptionStmt, err = db.PrepareContext(ctx, getCurrentProjectDescription); err != nil {
+		return nil, fmt.Errorf("error preparing query GetCurrentProjectDescription: %w", err)
+	}
+	if q.getCurrentProjectTitleStmt, err = db.PrepareContext(ctx, getCurrentProjectTitle); err != nil {
+		return nil, fmt.Errorf("error preparing query GetCurrentProjectTitle: %w", err)
+	}
 	if q.getFileByIDStmt, err = db.PrepareContext(ctx, getFileByID); err != nil {
 		return nil, fmt.Errorf("error preparing query GetFileByID: %w", err)
 	}
+	if q.getFileExistsByPathStmt, err = db.PrepareContext(ctx, getFileExistsByPath); err != nil {
+		return nil, fmt.Errorf("error preparing query GetFileExistsByPath: %w", err)
+	}
+	if q.getFileFingerprintStmt, err = db.PrepareContext(ctx, getFileFingerprint); err != nil {
+		return nil, fmt.Errorf("error preparing query GetFileFingerprint: %w", err)
+	}
+	if q.getFileFingerprintsByDurationStmt, err = db.PrepareContext(ctx, getFileFingerprintsByDuration); err != nil {
+		return nil, fmt.Errorf("error preparing query GetFileFingerprintsByDuration: %w", err)
+	}
+	if q.getFileIDBySHA256Stmt, err = db.PrepareContext(ctx, getFileIDBySHA256); err != nil {
+		return nil, fmt.Errorf("error preparing query GetFileIDBySHA256: %w", err)
+	}
+	if q.getFileIntentExistsByPathStmt, err = db.PrepareContext(ctx, getFileIntentExistsByPath); err != nil {
+		return nil, fmt.Errorf("error preparing query GetFileIntentExistsByPath: %w", err)
+	}
+	if q.getFileIntentsStmt, err = db.PrepareContext(ctx, getFileIntents); err != nil {
+		return nil, fmt.Errorf("error preparing query GetFileIntents: %w", err)
+	}
+	if q.getFileProbeStmt, err = db.PrepareContext(ctx, getFileProbe); err != nil {
+		return nil, fmt.Errorf("error preparing query GetFileProbe: %w", err)
+	}
+	if q.getFileProbeMismatchesStmt, err = db.PrepareContext(ctx, getFileProbeMismatches); err != nil {
+		return nil, fmt.Errorf("error preparing query GetFileProbeMismatches: %w", err)
+	}
+	if q.getFileProbeStreamsStmt, err = db.PrepareContext(ctx, getFileProbeStreams); err != nil {
+		return nil, fmt.Errorf("error preparing query GetFileProbeStreams: %w", err)
+	}
+	if q.getFileProjectsStmt, err = db.PrepareContext(ctx, getFileProjects); err != nil {
+		return nil, fmt.Errorf("error preparing query GetFileProjects: %w", err)
+	}
 	if q.getFileVideoStmt, err = db.PrepareContext(ctx, getFileVideo); err != nil {
 		return nil, fmt.Errorf("error preparing query GetFileVideo: %w", err)
 	}
+	if q.getFileYoutubeIDStmt, err = db.PrepareContext(ctx, getFileYoutubeID); err != nil {
+		return nil, fmt.Errorf("error preparing query GetFileYoutubeID: %w", err)
+	}
+	if q.getFilesAfterStmt, err = db.PrepareContext(ctx, getFilesAfter); err != nil {
+		return nil, fmt.Errorf("error preparing query GetFilesAfter: %w", err)
+	}
+	if q.getFilesWithoutVideoStmt, err = db.PrepareContext(ctx, getFilesWithoutVideo); err != nil {
+		return nil, fmt.Errorf("error preparing query GetFilesWithoutVideo: %w", err)
+	}
+	if q.getMisattributedDownloadsStmt, err = db.PrepareContext(ctx, getMisattributedDownloads); err != nil {
+		return nil, fmt.Errorf("error preparing query GetMisattributedDownloads: %w", err)
+	}
+	if q.getMusicStmt, err = db.PrepareContext(ctx, getMusic); err != nil {
+		return nil, fmt.Errorf("error preparing query GetMusic: %w", err)
+	}
+	if q.getMusicProjectsStmt, err = db.PrepareContext(ctx, getMusicProjects); err != nil {
+		return nil, fmt.Errorf("error preparing query GetMusicProjects: %w", err)
+	}
 	if q.getOrphanFilesStmt, err = db.PrepareContext(ctx, getOrphanFiles); err != nil {
 		return nil, fmt.Errorf("error preparing query GetOrphanFiles: %w", err)
 	}
+	if q.getPartCharactersStmt, err = db.PrepareContext(ctx, getPartCharacters); err != nil {
+		return nil, fmt.Errorf("error preparing query GetPartCharacters: %w", err)
+	}
 	if q.getProjectByUUIDStmt, err = db.PrepareContext(ctx, getProjectByUUID); err != nil {
 		return nil, fmt.Errorf("error preparing query GetProjectByUUID: %w", err)
 	}
 	if q.getProjectByYoutubeIDStmt, err = db.PrepareContext(ctx, getProjectByYoutubeID); err != nil {
 		return nil, fmt.Errorf("error preparing query GetProjectByYoutubeID: %w", err)
 	}
+	if q.getProjectCharactersStmt, err = db.PrepareContext(ctx, getProjectCharacters); err != nil {
+		return nil, fmt.Errorf("error preparing query GetProjectCharacters: %w", err)
+	}
+	if q.getProjectDescriptionsStmt, err = db.PrepareContext(ctx, getProjectDescriptions); err != nil {
+		return nil, fmt.Errorf("error preparing query GetProjectDescriptions: %w", err)
+	}
 	if q.getProjectFileStmt, err = db.PrepareContext(ctx, getProjectFile); err != nil {
 		return nil, fmt.Errorf("error preparing query GetProjectFile: %w", err)
 	}
+	if q.getProjectMusicStmt, err = db.PrepareContext(ctx, getProjectMusic); err != nil {
+		return nil, fmt.Errorf("error preparing query GetProjectMusic: %w", err)
+	}
+	if q.getProjectPartStmt, err = db.PrepareContext(ctx, getProjectPart); err != nil {
+		return nil, fmt.Errorf("error preparing query GetProjectPart: %w", err)
+	}
+	if q.getProjectPartsStmt, err = db.PrepareContext(ctx, getProjectParts); err != nil {
+		return nil, fmt.Errorf("error preparing query GetProjectParts: %w", err)
+	}
+	if q.getProjectRelationAncestorsStmt, err = db.PrepareContext(ctx, getProjectRelationAncestors); err != nil {
+		return nil, fmt.Errorf("error preparing query GetProjectRelationAncestors: %w", err)
+	}
+	if q.getProjectRelationDescendantsStmt, err = db.PrepareContext(ctx, getProjectRelationDescendants); err != nil {
+		return nil, fmt.Errorf("error preparing query GetProjectRelationDescendants: %w", err)
+	}
+	if q.getProjectRelationsStmt, err = db.PrepareContext(ctx, getProjectRelations); err != nil {
+		return nil, fmt.Errorf("error preparing query GetProjectRelations: %w", err)
+	}
+	if q.getProjectStatusesStmt, err = db.PrepareContext(ctx, getProjectStatuses); err != nil {
+		return nil, fmt.Errorf("error preparing query GetProjectStatuses: %w", err)
+	}
+	if q.getProjectTitlesStmt, err = db.PrepareContext(ctx, getProjectTitles); err != nil {
+		return nil, fmt.Errorf("error preparing query GetProjectTitles: %w", err)
+	}
 	if q.getProjectTypeByYoutubeIDStmt, err = db.PrepareContext(ctx, getProjectTypeByYoutubeID); err != nil {
 		return nil, fmt.Errorf("error preparing query GetProjectTypeByYoutubeID: %w", err)
 	}
+	if q.getProjectYoutubeIDsStmt, err = db.PrepareContext(ctx, getProjectYoutubeIDs); err != nil {
+		return nil, fmt.Errorf("error preparing query GetProjectYoutubeIDs: %w", err)
+	}
+	if q.getProjectsWithoutFilesStmt, err = db.PrepareContext(ctx, getProjectsWithoutFiles); err != nil {
+		return nil, fmt.Errorf("error preparing query GetProjectsWithoutFiles: %w", err)
+	}
+	if q.getSeriesCharactersStmt, err = db.PrepareContext(ctx, getSeriesCharacters); err != nil {
+		return nil, fmt.Errorf("error preparing query GetSeriesCharacters: %w", err)
+	}
+	if q.getUnassignedDownloadsStmt, err = db.PrepareContext(ctx, getUnassignedDownloads); err != nil {
+		return nil, fmt.Errorf("error preparing query GetUnassignedDownloads: %w", err)
+	}
+	if q.getUnfingerprintedFileIDsStmt, err = db.PrepareContext(ctx, getUnfingerprintedFileIDs); err != nil {
+		return nil, fmt.Errorf("error preparing query GetUnfingerprintedFileIDs: %w", err)
+	}
+	if q.getUnprobedFileIDsStmt, err = db.PrepareContext(ctx, getUnprobedFileIDs); err != nil {
+		return nil, fmt.Errorf("error preparing query GetUnprobedFileIDs: %w", err)
+	}
 	if q.getYoutubeChannelByIDStmt, err = db.PrepareContext(ctx, getYoutubeChannelByID); err != nil {
 		return nil, fmt.Errorf("error preparing query GetYoutubeChannelByID: %w", err)
 	}
@@ -78,6 +270,9 @@ func Prepare(ctx context.Context, db DBTX) (*Queries, error) {
 	if q.getYoutubeFileIDStmt, err = db.PrepareContext(ctx, getYoutubeFileID); err != nil {
 		return nil, fmt.Errorf("error preparing query GetYoutubeFileID: %w", err)
 	}
+	if q.getYoutubeThumbnailStmt, err = db.PrepareContext(ctx, getYoutubeThumbnail); err != nil {
+		return nil, fmt.Errorf("error preparing query GetYoutubeThumbnail: %w", err)
+	}
 	if q.getYoutubeTitleStmt, err = db.PrepareContext(ctx, getYoutubeTitle); err != nil {
 		return nil, fmt.Errorf("error preparing query GetYoutubeTitle: %w", err)
 	}
@@ -87,18 +282,81 @@ func Prepare(ctx context.Context, db DBTX) (*Queries, error) {
 	if q.getYoutubeVideoFormatByYoutubeIDStmt, err = db.PrepareContext(ctx, getYoutubeVideoFormatByYoutubeID); err != nil {
 		return nil, fmt.Errorf("error preparing query GetYoutubeVideoFormatByYoutubeID: %w", err)
 	}
+	if q.getYoutubeWithoutFilesStmt, err = db.PrepareContext(ctx, getYoutubeWithoutFiles); err != nil {
+		return nil, fmt.Errorf("error preparing query GetYoutubeWithoutFiles: %w", err)
+	}
+	if q.getYoutubeYtdlpInfoStmt, err = db.PrepareContext(ctx, getYoutubeYtdlpInfo); err != nil {
+		return nil, fmt.Errorf("error preparing query GetYoutubeYtdlpInfo: %w", err)
+	}
 	if q.getYoutubeYtdlpVersionStmt, err = db.PrepareContext(ctx, getYoutubeYtdlpVersion); err != nil {
 		return nil, fmt.Errorf("error preparing query GetYoutubeYtdlpVersion: %w", err)
 	}
+	if q.getYtdlpClaimedVideoStmt, err = db.PrepareContext(ctx, getYtdlpClaimedVideo); err != nil {
+		return nil, fmt.Errorf("error preparing query GetYtdlpClaimedVideo: %w", err)
+	}
+	if q.lockFilesStmt, err = db.PrepareContext(ctx, lockFiles); err != nil {
+		return nil, fmt.Errorf("error preparing query LockFiles: %w", err)
+	}
+	if q.lockProjectStmt, err = db.PrepareContext(ctx, lockProject); err != nil {
+		return nil, fmt.Errorf("error preparing query LockProject: %w", err)
+	}
+	if q.lockProjectRelationsStmt, err = db.PrepareContext(ctx, lockProjectRelations); err != nil {
+		return nil, fmt.Errorf("error preparing query LockProjectRelations: %w", err)
+	}
+	if q.lockStorageSharedStmt, err = db.PrepareContext(ctx, lockStorageShared); err != nil {
+		return nil, fmt.Errorf("error preparing query LockStorageShared: %w", err)
+	}
+	if q.newArtistStmt, err = db.PrepareContext(ctx, newArtist); err != nil {
+		return nil, fmt.Errorf("error preparing query NewArtist: %w", err)
+	}
+	if q.newArtistAliasStmt, err = db.PrepareContext(ctx, newArtistAlias); err != nil {
+		return nil, fmt.Errorf("error preparing query NewArtistAlias: %w", err)
+	}
+	if q.newAuditEntryStmt, err = db.PrepareContext(ctx, newAuditEntry); err != nil {
+		return nil, fmt.Errorf("error preparing query NewAuditEntry: %w", err)
+	}
+	if q.newCharacterStmt, err = db.PrepareContext(ctx, newCharacter); err != nil {
+		return nil, fmt.Errorf("error preparing query NewCharacter: %w", err)
+	}
+	if q.newCharacterAliasStmt, err = db.PrepareContext(ctx, newCharacterAlias); err != nil {
+		return nil, fmt.Errorf("error preparing query NewCharacterAlias: %w", err)
+	}
 	if q.newFileStmt, err = db.PrepareContext(ctx, newFile); err != nil {
 		return nil, fmt.Errorf("error preparing query NewFile: %w", err)
 	}
+	if q.newFileIntentStmt, err = db.PrepareContext(ctx, newFileIntent); err != nil {
+		return nil, fmt.Errorf("error preparing query NewFileIntent: %w", err)
+	}
+	if q.newFileProbeMismatchStmt, err = db.PrepareContext(ctx, newFileProbeMismatch); err != nil {
+		return nil, fmt.Errorf("error preparing query NewFileProbeMismatch: %w", err)
+	}
+	if q.newFileProbeStreamStmt, err = db.PrepareContext(ctx, newFileProbeStream); err != nil {
+		return nil, fmt.Errorf("error preparing query NewFileProbeStream: %w", err)
+	}
 	if q.newFileVideoStmt, err = db.PrepareContext(ctx, newFileVideo); err != nil {
 		return nil, fmt.Errorf("error preparing query NewFileVideo: %w", err)
 	}
+	if q.newMusicStmt, err = db.PrepareContext(ctx, newMusic); err != nil {
+		return nil, fmt.Errorf("error preparing query NewMusic: %w", err)
+	}
 	if q.newProjectStmt, err = db.PrepareContext(ctx, newProject); err != nil {
 		return nil, fmt.Errorf("error preparing query NewProject: %w", err)
 	}
+	if q.newProjectDescriptionStmt, err = db.PrepareContext(ctx, newProjectDescription); err != nil {
+		return nil, fmt.Errorf("error preparing query NewProjectDescription: %w", err)
+	}
+	if q.newProjectPartStmt, err = db.PrepareContext(ctx, newProjectPart); err != nil {
+		return nil, fmt.Errorf("error preparing query NewProjectPart: %w", err)
+	}
+	if q.newProjectRelationStmt, err = db.PrepareContext(ctx, newProjectRelation); err != nil {
+		return nil, fmt.Errorf("error preparing query NewProjectRelation: %w", err)
+	}
+	if q.newProjectStatusStmt, err = db.PrepareContext(ctx, newProjectStatus); err != nil {
+		return nil, fmt.Errorf("error preparing query NewProjectStatus: %w", err)
+	}
+	if q.newProjectTitleStmt, err = db.PrepareContext(ctx, newProjectTitle); err != nil {
+		return nil, fmt.Errorf("error preparing query NewProjectTitle: %w", err)
+	}
 	if q.newYoutubeStmt, err = db.PrepareContext(ctx, newYoutube); err != nil {
 		return nil, fmt.Errorf("error preparing query NewYoutube: %w", err)
 	}
@@ -117,25 +375,107 @@ func Prepare(ctx context.Context, db DBTX) (*Queries, error) {
 	if q.newYoutubeFormatStmt, err = db.PrepareContext(ctx, newYoutubeFormat); err != nil {
 		return nil, fmt.Errorf("error preparing query NewYoutubeFormat: %w", err)
 	}
+	if q.newYoutubeThumbnailStmt, err = db.PrepareContext(ctx, newYoutubeThumbnail); err != nil {
+		return nil, fmt.Errorf("error preparing query NewYoutubeThumbnail: %w", err)
+	}
+	if q.newYoutubeYtdlpInfoStmt, err = db.PrepareContext(ctx, newYoutubeYtdlpInfo); err != nil {
+		return nil, fmt.Errorf("error preparing query NewYoutubeYtdlpInfo: %w", err)
+	}
 	if q.newYoutubeYtdlpVersionStmt, err = db.PrepareContext(ctx, newYoutubeYtdlpVersion); err != nil {
 		return nil, fmt.Errorf("error preparing query NewYoutubeYtdlpVersion: %w", err)
 	}
+	if q.renameArtistStmt, err = db.PrepareContext(ctx, renameArtist); err != nil {
+		return nil, fmt.Errorf("error preparing query RenameArtist: %w", err)
+	}
+	if q.searchStmt, err = db.PrepareContext(ctx, search); err != nil {
+		return nil, fmt.Errorf("error preparing query Search: %w", err)
+	}
+	if q.setProjectDateAnnouncedStmt, err = db.PrepareContext(ctx, setProjectDateAnnounced); err != nil {
+		return nil, fmt.Errorf("error preparing query SetProjectDateAnnounced: %w", err)
+	}
+	if q.setProjectDateCompletedStmt, err = db.PrepareContext(ctx, setProjectDateCompleted); err != nil {
+		return nil, fmt.Errorf("error preparing query SetProjectDateCompleted: %w", err)
+	}
+	if q.tagPartCharacterStmt, err = db.PrepareContext(ctx, tagPartCharacter); err != nil {
+		return nil, fmt.Errorf("error preparing query TagPartCharacter: %w", err)
+	}
+	if q.tagProjectCharacterStmt, err = db.PrepareContext(ctx, tagProjectCharacter); err != nil {
+		return nil, fmt.Errorf("error preparing query TagProjectCharacter: %w", err)
+	}
+	if q.tryLockStorageStmt, err = db.PrepareContext(ctx, tryLockStorage); err != nil {
+		return nil, fmt.Errorf("error preparing query TryLockStorage: %w", err)
+	}
+	if q.unassignArtistChannelStmt, err = db.PrepareContext(ctx, unassignArtistChannel); err != nil {
+		return nil, fmt.Errorf("error preparing query UnassignArtistChannel: %w", err)
+	}
 	if q.unassignProjectFileStmt, err = db.PrepareContext(ctx, unassignProjectFile); err != nil {
 		return nil, fmt.Errorf("error preparing query UnassignProjectFile: %w", err)
 	}
+	if q.unassignProjectMusicStmt, err = db.PrepareContext(ctx, unassignProjectMusic); err != nil {
+		return nil, fmt.Errorf("error preparing query UnassignProjectMusic: %w", err)
+	}
 	if q.unassignYoutubeVideoFromProjectStmt, err = db.PrepareContext(ctx, unassignYoutubeVideoFromProject); err != nil {
 		return nil, fmt.Errorf("error preparing query UnassignYoutubeVideoFromProject: %w", err)
 	}
+	if q.unlockStorageStmt, err = db.PrepareContext(ctx, unlockStorage); err != nil {
+		return nil, fmt.Errorf("error preparing query UnlockStorage: %w", err)
+	}
+	if q.unlockStorageSharedStmt, err = db.PrepareContext(ctx, unlockStorageShared); err != nil {
+		return nil, fmt.Errorf("error preparing query UnlockStorageShared: %w", err)
+	}
+	if q.untagPartCharacterStmt, err = db.PrepareContext(ctx, untagPartCharacter); err != nil {
+		return nil, fmt.Errorf("error preparing query UntagPartCharacter: %w", err)
+	}
+	if q.untagProjectCharacterStmt, err = db.PrepareContext(ctx, untagProjectCharacter); err != nil {
+		return nil, fmt.Errorf("error preparing query UntagProjectCharacter: %w", err)
+	}
+	if q.updateCharacterStmt, err = db.PrepareContext(ctx, updateCharacter); err != nil {
+		return nil, fmt.Errorf("error preparing query UpdateCharacter: %w", err)
+	}
+	if q.updateMusicStmt, err = db.PrepareContext(ctx, updateMusic); err != nil {
+		return nil, fmt.Errorf("error preparing query UpdateMusic: %w", err)
+	}
+	if q.updateProjectDateAnnouncedStmt, err = db.PrepareContext(ctx, updateProjectDateAnnounced); err != nil {
+		return nil, fmt.Errorf("error preparing query UpdateProjectDateAnnounced: %w", err)
+	}
+	if q.updateProjectDateCompletedStmt, err = db.PrepareContext(ctx, updateProjectDateCompleted); err != nil {
+		return nil, fmt.Errorf("error preparing query UpdateProjectDateCompleted: %w", err)
+	}
+	if q.updateProjectPartStmt, err = db.PrepareContext(ctx, updateProjectPart); err != nil {
+		return nil, fmt.Errorf("error preparing query UpdateProjectPart: %w", err)
+	}
+	if q.updateProjectTypeStmt, err = db.PrepareContext(ctx, updateProjectType); err != nil {
+		return nil, fmt.Errorf("error preparing query UpdateProjectType: %w", err)
+	}
+	if q.upsertFileFingerprintStmt, err = db.PrepareContext(ctx, upsertFileFingerprint); err != nil {
+		return nil, fmt.Errorf("error preparing query UpsertFileFingerprint: %w", err)
+	}
+	if q.upsertFileProbeStmt, err = db.PrepareContext(ctx, upsertFileProbe); err != nil {
+		return nil, fmt.Errorf("error preparing query UpsertFileProbe: %w", err)
+	}
+	if q.upsertFileVideoStmt, err = db.PrepareContext(ctx, upsertFileVideo); err != nil {
+		return nil, fmt.Errorf("error preparing query UpsertFileVideo: %w", err)
+	}
 	return &q, nil
 }
 
 func (q *Queries) Close() error {
 	var err error
+	if q.assignArtistChannelStmt != nil {
+		if cerr := q.assignArtistChannelStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing assignArtistChannelStmt: %w", cerr)
+		}
+	}
 	if q.assignProjectFileStmt != nil {
 		if cerr := q.assignProjectFileStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing assignProjectFileStmt: %w", cerr)
 		}
 	}
+	if q.assignProjectMusicStmt != nil {
+		if cerr := q.assignProjectMusicStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing assignProjectMusicStmt: %w", cerr)
+		}
+	}
 	if q.assignYoutubeDescriptionStmt != nil {
 		if cerr := q.assignYoutubeDescriptionStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing assignYoutubeDescriptionStmt: %w", cerr)
@@ -156,31 +496,261 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing assignYoutubeVideoToProjectStmt: %w", cerr)
 		}
 	}
+	if q.deleteArtistStmt != nil {
+		if cerr := q.deleteArtistStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteArtistStmt: %w", cerr)
+		}
+	}
+	if q.deleteArtistAliasStmt != nil {
+		if cerr := q.deleteArtistAliasStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteArtistAliasStmt: %w", cerr)
+		}
+	}
+	if q.deleteCharacterStmt != nil {
+		if cerr := q.deleteCharacterStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteCharacterStmt: %w", cerr)
+		}
+	}
+	if q.deleteCharacterAliasStmt != nil {
+		if cerr := q.deleteCharacterAliasStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteCharacterAliasStmt: %w", cerr)
+		}
+	}
 	if q.deleteFileByIDStmt != nil {
 		if cerr := q.deleteFileByIDStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing deleteFileByIDStmt: %w", cerr)
 		}
 	}
+	if q.deleteFileIntentStmt != nil {
+		if cerr := q.deleteFileIntentStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteFileIntentStmt: %w", cerr)
+		}
+	}
+	if q.deleteFileProbeMismatchesStmt != nil {
+		if cerr := q.deleteFileProbeMismatchesStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteFileProbeMismatchesStmt: %w", cerr)
+		}
+	}
+	if q.deleteFileProbeStreamsStmt != nil {
+		if cerr := q.deleteFileProbeStreamsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteFileProbeStreamsStmt: %w", cerr)
+		}
+	}
+	if q.deleteLatestProjectStatusStmt != nil {
+		if cerr := q.deleteLatestProjectStatusStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteLatestProjectStatusStmt: %w", cerr)
+		}
+	}
+	if q.deleteMusicStmt != nil {
+		if cerr := q.deleteMusicStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteMusicStmt: %w", cerr)
+		}
+	}
 	if q.deleteProjectByUUIDStmt != nil {
 		if cerr := q.deleteProjectByUUIDStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing deleteProjectByUUIDStmt: %w", cerr)
 		}
 	}
+	if q.deleteProjectPartStmt != nil {
+		if cerr := q.deleteProjectPartStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteProjectPartStmt: %w", cerr)
+		}
+	}
+	if q.deleteProjectRelationStmt != nil {
+		if cerr := q.deleteProjectRelationStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteProjectRelationStmt: %w", cerr)
+		}
+	}
+	if q.exportSnapshotStmt != nil {
+		if cerr := q.exportSnapshotStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing exportSnapshotStmt: %w", cerr)
+		}
+	}
+	if q.findCharactersStmt != nil {
+		if cerr := q.findCharactersStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing findCharactersStmt: %w", cerr)
+		}
+	}
+	if q.findMusicStmt != nil {
+		if cerr := q.findMusicStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing findMusicStmt: %w", cerr)
+		}
+	}
+	if q.getAllFileProbeMismatchesStmt != nil {
+		if cerr := q.getAllFileProbeMismatchesStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getAllFileProbeMismatchesStmt: %w", cerr)
+		}
+	}
+	if q.getArtistStmt != nil {
+		if cerr := q.getArtistStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getArtistStmt: %w", cerr)
+		}
+	}
+	if q.getArtistAliasesStmt != nil {
+		if cerr := q.getArtistAliasesStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getArtistAliasesStmt: %w", cerr)
+		}
+	}
+	if q.getArtistChannelsStmt != nil {
+		if cerr := q.getArtistChannelsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getArtistChannelsStmt: %w", cerr)
+		}
+	}
+	if q.getArtistIDStmt != nil {
+		if cerr := q.getArtistIDStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getArtistIDStmt: %w", cerr)
+		}
+	}
+	if q.getArtistProjectPartsStmt != nil {
+		if cerr := q.getArtistProjectPartsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getArtistProjectPartsStmt: %w", cerr)
+		}
+	}
+	if q.getArtistYoutubeVideosStmt != nil {
+		if cerr := q.getArtistYoutubeVideosStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getArtistYoutubeVideosStmt: %w", cerr)
+		}
+	}
+	if q.getAuditEntriesStmt != nil {
+		if cerr := q.getAuditEntriesStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getAuditEntriesStmt: %w", cerr)
+		}
+	}
+	if q.getAuditEntryStmt != nil {
+		if cerr := q.getAuditEntryStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getAuditEntryStmt: %w", cerr)
+		}
+	}
+	if q.getChannelArtistsStmt != nil {
+		if cerr := q.getChannelArtistsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getChannelArtistsStmt: %w", cerr)
+		}
+	}
+	if q.getCharacterStmt != nil {
+		if cerr := q.getCharacterStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getCharacterStmt: %w", cerr)
+		}
+	}
+	if q.getCharacterAliasesStmt != nil {
+		if cerr := q.getCharacterAliasesStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getCharacterAliasesStmt: %w", cerr)
+		}
+	}
+	if q.getCharacterProjectsStmt != nil {
+		if cerr := q.getCharacterProjectsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getCharacterProjectsStmt: %w", cerr)
+		}
+	}
+	if q.getCurrentProjectDescriptionStmt != nil {
+		if cerr := q.getCurrentProjectDescriptionStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getCurrentProjectDescriptionStmt: %w", cerr)
+		}
+	}
+	if q.getCurrentProjectTitleStmt != nil {
+		if cerr := q.getCurrentProjectTitleStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getCurrentProjectTitleStmt: %w", cerr)
+		}
+	}
 	if q.getFileByIDStmt != nil {
 		if cerr := q.getFileByIDStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing getFileByIDStmt: %w", cerr)
 		}
 	}
+	if q.getFileExistsByPathStmt != nil {
+		if cerr := q.getFileExistsByPathStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getFileExistsByPathStmt: %w", cerr)
+		}
+	}
+	if q.getFileFingerprintStmt != nil {
+		if cerr := q.getFileFingerprintStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getFileFingerprintStmt: %w", cerr)
+		}
+	}
+	if q.getFileFingerprintsByDurationStmt != nil {
+		if cerr := q.getFileFingerprintsByDurationStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getFileFingerprintsByDurationStmt: %w", cerr)
+		}
+	}
+	if q.getFileIDBySHA256Stmt != nil {
+		if cerr := q.getFileIDBySHA256Stmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getFileIDBySHA256Stmt: %w", cerr)
+		}
+	}
+	if q.getFileIntentExistsByPathStmt != nil {
+		if cerr := q.getFileIntentExistsByPathStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getFileIntentExistsByPathStmt: %w", cerr)
+		}
+	}
+	if q.getFileIntentsStmt != nil {
+		if cerr := q.getFileIntentsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getFileIntentsStmt: %w", cerr)
+		}
+	}
+	if q.getFileProbeStmt != nil {
+		if cerr := q.getFileProbeStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getFileProbeStmt: %w", cerr)
+		}
+	}
+	if q.getFileProbeMismatchesStmt != nil {
+		if cerr := q.getFileProbeMismatchesStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getFileProbeMismatchesStmt: %w", cerr)
+		}
+	}
+	if q.getFileProbeStreamsStmt != nil {
+		if cerr := q.getFileProbeStreamsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getFileProbeStreamsStmt: %w", cerr)
+		}
+	}
+	if q.getFileProjectsStmt != nil {
+		if cerr := q.getFileProjectsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getFileProjectsStmt: %w", cerr)
+		}
+	}
 	if q.getFileVideoStmt != nil {
 		if cerr := q.getFileVideoStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing getFileVideoStmt: %w", cerr)
 		}
 	}
+	if q.getFileYoutubeIDStmt != nil {
+		if cerr := q.getFileYoutubeIDStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getFileYoutubeIDStmt: %w", cerr)
+		}
+	}
+	if q.getFilesAfterStmt != nil {
+		if cerr := q.getFilesAfterStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getFilesAfterStmt: %w", cerr)
+		}
+	}
+	if q.getFilesWithoutVideoStmt != nil {
+		if cerr := q.getFilesWithoutVideoStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getFilesWithoutVideoStmt: %w", cerr)
+		}
+	}
+	if q.getMisattributedDownloadsStmt != nil {
+		if cerr := q.getMisattributedDownloadsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getMisattributedDownloadsStmt: %w", cerr)
+		}
+	}
+	if q.getMusicStmt != nil {
+		if cerr := q.getMusicStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getMusicStmt: %w", cerr)
+		}
+	}
+	if q.getMusicProjectsStmt != nil {
+		if cerr := q.getMusicProjectsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getMusicProjectsStmt: %w", cerr)
+		}
+	}
 	if q.getOrphanFilesStmt != nil {
 		if cerr := q.getOrphanFilesStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing getOrphanFilesStmt: %w", cerr)
 		}
 	}
+	if q.getPartCharactersStmt != nil {
+		if cerr := q.getPartCharactersStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getPartCharactersStmt: %w", cerr)
+		}
+	}
 	if q.getProjectByUUIDStmt != nil {
 		if cerr := q.getProjectByUUIDStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing getProjectByUUIDStmt: %w", cerr)
@@ -191,16 +761,96 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing getProjectByYoutubeIDStmt: %w", cerr)
 		}
 	}
+	if q.getProjectCharactersStmt != nil {
+		if cerr := q.getProjectCharactersStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getProjectCharactersStmt: %w", cerr)
+		}
+	}
+	if q.getProjectDescriptionsStmt != nil {
+		if cerr := q.getProjectDescriptionsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getProjectDescriptionsStmt: %w", cerr)
+		}
+	}
 	if q.getProjectFileStmt != nil {
 		if cerr := q.getProjectFileStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing getProjectFileStmt: %w", cerr)
 		}
 	}
+	if q.getProjectMusicStmt != nil {
+		if cerr := q.getProjectMusicStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getProjectMusicStmt: %w", cerr)
+		}
+	}
+	if q.getProjectPartStmt != nil {
+		if cerr := q.getProjectPartStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getProjectPartStmt: %w", cerr)
+		}
+	}
+	if q.getProjectPartsStmt != nil {
+		if cerr := q.getProjectPartsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getProjectPartsStmt: %w", cerr)
+		}
+	}
+	if q.getProjectRelationAncestorsStmt != nil {
+		if cerr := q.getProjectRelationAncestorsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getProjectRelationAncestorsStmt: %w", cerr)
+		}
+	}
+	if q.getProjectRelationDescendantsStmt != nil {
+		if cerr := q.getProjectRelationDescendantsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getProjectRelationDescendantsStmt: %w", cerr)
+		}
+	}
+	if q.getProjectRelationsStmt != nil {
+		if cerr := q.getProjectRelationsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getProjectRelationsStmt: %w", cerr)
+		}
+	}
+	if q.getProjectStatusesStmt != nil {
+		if cerr := q.getProjectStatusesStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getProjectStatusesStmt: %w", cerr)
+		}
+	}
+	if q.getProjectTitlesStmt != nil {
+		if cerr := q.getProjectTitlesStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getProjectTitlesStmt: %w", cerr)
+		}
+	}
 	if q.getProjectTypeByYoutubeIDStmt != nil {
 		if cerr := q.getProjectTypeByYoutubeIDStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing getProjectTypeByYoutubeIDStmt: %w", cerr)
 		}
 	}
+	if q.getProjectYoutubeIDsStmt != nil {
+		if cerr := q.getProjectYoutubeIDsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getProjectYoutubeIDsStmt: %w", cerr)
+		}
+	}
+	if q.getProjectsWithoutFilesStmt != nil {
+		if cerr := q.getProjectsWithoutFilesStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getProjectsWithoutFilesStmt: %w", cerr)
+		}
+	}
+	if q.getSeriesCharactersStmt != nil {
+		if cerr := q.getSeriesCharactersStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getSeriesCharactersStmt: %w", cerr)
+		}
+	}
+	if q.getUnassignedDownloadsStmt != nil {
+		if cerr := q.getUnassignedDownloadsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getUnassignedDownloadsStmt: %w", cerr)
+		}
+	}
+	if q.getUnfingerprintedFileIDsStmt != nil {
+		if cerr := q.getUnfingerprintedFileIDsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getUnfingerprintedFileIDsStmt: %w", cerr)
+		}
+	}
+	if q.getUnprobedFileIDsStmt != nil {
+		if cerr := q.getUnprobedFileIDsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getUnprobedFileIDsStmt: %w", cerr)
+		}
+	}
 	if q.getYoutubeChannelByIDStmt != nil {
 		if cerr := q.getYoutubeChannelByIDStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing getYoutubeChannelByIDStmt: %w", cerr)
@@ -221,6 +871,11 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing getYoutubeFileIDStmt: %w", cerr)
 		}
 	}
+	if q.getYoutubeThumbnailStmt != nil {
+		if cerr := q.getYoutubeThumbnailStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getYoutubeThumbnailStmt: %w", cerr)
+		}
+	}
 	if q.getYoutubeTitleStmt != nil {
 		if cerr := q.getYoutubeTitleStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing getYoutubeTitleStmt: %w", cerr)
@@ -236,26 +891,131 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing getYoutubeVideoFormatByYoutubeIDStmt: %w", cerr)
 		}
 	}
+	if q.getYoutubeWithoutFilesStmt != nil {
+		if cerr := q.getYoutubeWithoutFilesStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getYoutubeWithoutFilesStmt: %w", cerr)
+		}
+	}
+	if q.getYoutubeYtdlpInfoStmt != nil {
+		if cerr := q.getYoutubeYtdlpInfoStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getYoutubeYtdlpInfoStmt: %w", cerr)
+		}
+	}
 	if q.getYoutubeYtdlpVersionStmt != nil {
 		if cerr := q.getYoutubeYtdlpVersionStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing getYoutubeYtdlpVersionStmt: %w", cerr)
 		}
 	}
+	if q.getYtdlpClaimedVideoStmt != nil {
+		if cerr := q.getYtdlpClaimedVideoStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getYtdlpClaimedVideoStmt: %w", cerr)
+		}
+	}
+	if q.lockFilesStmt != nil {
+		if cerr := q.lockFilesStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing lockFilesStmt: %w", cerr)
+		}
+	}
+	if q.lockProjectStmt != nil {
+		if cerr := q.lockProjectStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing lockProjectStmt: %w", cerr)
+		}
+	}
+	if q.lockProjectRelationsStmt != nil {
+		if cerr := q.lockProjectRelationsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing lockProjectRelationsStmt: %w", cerr)
+		}
+	}
+	if q.lockStorageSharedStmt != nil {
+		if cerr := q.lockStorageSharedStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing lockStorageSharedStmt: %w", cerr)
+		}
+	}
+	if q.newArtistStmt != nil {
+		if cerr := q.newArtistStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing newArtistStmt: %w", cerr)
+		}
+	}
+	if q.newArtistAliasStmt != nil {
+		if cerr := q.newArtistAliasStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing newArtistAliasStmt: %w", cerr)
+		}
+	}
+	if q.newAuditEntryStmt != nil {
+		if cerr := q.newAuditEntryStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing newAuditEntryStmt: %w", cerr)
+		}
+	}
+	if q.newCharacterStmt != nil {
+		if cerr := q.newCharacterStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing newCharacterStmt: %w", cerr)
+		}
+	}
+	if q.newCharacterAliasStmt != nil {
+		if cerr := q.newCharacterAliasStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing newCharacterAliasStmt: %w", cerr)
+		}
+	}
 	if q.newFileStmt != nil {
 		if cerr := q.newFileStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing newFileStmt: %w", cerr)
 		}
 	}
+	if q.newFileIntentStmt != nil {
+		if cerr := q.newFileIntentStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing newFileIntentStmt: %w", cerr)
+		}
+	}
+	if q.newFileProbeMismatchStmt != nil {
+		if cerr := q.newFileProbeMismatchStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing newFileProbeMismatchStmt: %w", cerr)
+		}
+	}
+	if q.newFileProbeStreamStmt != nil {
+		if cerr := q.newFileProbeStreamStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing newFileProbeStreamStmt: %w", cerr)
+		}
+	}
 	if q.newFileVideoStmt != nil {
 		if cerr := q.newFileVideoStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing newFileVideoStmt: %w", cerr)
 		}
 	}
+	if q.newMusicStmt != nil {
+		if cerr := q.newMusicStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing newMusicStmt: %w", cerr)
+		}
+	}
 	if q.newProjectStmt != nil {
 		if cerr := q.newProjectStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing newProjectStmt: %w", cerr)
 		}
 	}
+	if q.newProjectDescriptionStmt != nil {
+		if cerr := q.newProjectDescriptionStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing newProjectDescriptionStmt: %w", cerr)
+		}
+	}
+	if q.newProjectPartStmt != nil {
+		if cerr := q.newProjectPartStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing newProjectPartStmt: %w", cerr)
+		}
+	}
+	if q.newProjectRelationStmt != nil {
+		if cerr := q.newProjectRelationStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing newProjectRelationStmt: %w", cerr)
+		}
+	}
+	if q.newProjectStatusStmt != nil {
+		if cerr := q.newProjectStatusStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing newProjectStatusStmt: %w", cerr)
+		}
+	}
+	if q.newProjectTitleStmt != nil {
+		if cerr := q.newProjectTitleStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing newProjectTitleStmt: %w", cerr)
+		}
+	}
 	if q.newYoutubeStmt != nil {
 		if cerr := q.newYoutubeStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing newYoutubeStmt: %w", cerr)
@@ -286,21 +1046,141 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing newYoutubeFormatStmt: %w", cerr)
 		}
 	}
+	if q.newYoutubeThumbnailStmt != nil {
+		if cerr := q.newYoutubeThumbnailStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing newYoutubeThumbnailStmt: %w", cerr)
+		}
+	}
+	if q.newYoutubeYtdlpInfoStmt != nil {
+		if cerr := q.newYoutubeYtdlpInfoStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing newYoutubeYtdlpInfoStmt: %w", cerr)
+		}
+	}
 	if q.newYoutubeYtdlpVersionStmt != nil {
 		if cerr := q.newYoutubeYtdlpVersionStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing newYoutubeYtdlpVersionStmt: %w", cerr)
 		}
 	}
+	if q.renameArtistStmt != nil {
+		if cerr := q.renameArtistStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing renameArtistStmt: %w", cerr)
+		}
+	}
+	if q.searchStmt != nil {
+		if cerr := q.searchStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing searchStmt: %w", cerr)
+		}
+	}
+	if q.setProjectDateAnnouncedStmt != nil {
+		if cerr := q.setProjectDateAnnouncedStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing setProjectDateAnnouncedStmt: %w", cerr)
+		}
+	}
+	if q.setProjectDateCompletedStmt != nil {
+		if cerr := q.setProjectDateCompletedStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing setProjectDateCompletedStmt: %w", cerr)
+		}
+	}
+	if q.tagPartCharacterStmt != nil {
+		if cerr := q.tagPartCharacterStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing tagPartCharacterStmt: %w", cerr)
+		}
+	}
+	if q.tagProjectCharacterStmt != nil {
+		if cerr := q.tagProjectCharacterStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing tagProjectCharacterStmt: %w", cerr)
+		}
+	}
+	if q.tryLockStorageStmt != nil {
+		if cerr := q.tryLockStorageStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing tryLockStorageStmt: %w", cerr)
+		}
+	}
+	if q.unassignArtistChannelStmt != nil {
+		if cerr := q.unassignArtistChannelStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing unassignArtistChannelStmt: %w", cerr)
+		}
+	}
 	if q.unassignProjectFileStmt != nil {
 		if cerr := q.unassignProjectFileStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing unassignProjectFileStmt: %w", cerr)
 		}
 	}
+	if q.unassignProjectMusicStmt != nil {
+		if cerr := q.unassignProjectMusicStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing unassignProjectMusicStmt: %w", cerr)
+		}
+	}
 	if q.unassignYoutubeVideoFromProjectStmt != nil {
 		if cerr := q.unassignYoutubeVideoFromProjectStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing unassignYoutubeVideoFromProjectStmt: %w", cerr)
 		}
 	}
+	if q.unlockStorageStmt != nil {
+		if cerr := q.unlockStorageStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing unlockStorageStmt: %w", cerr)
+		}
+	}
+	if q.unlockStorageSharedStmt != nil {
+		if cerr := q.unlockStorageSharedStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing unlockStorageSharedStmt: %w", cerr)
+		}
+	}
+	if q.untagPartCharacterStmt != nil {
+		if cerr := q.untagPartCharacterStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing untagPartCharacterStmt: %w", cerr)
+		}
+	}
+	if q.untagProjectCharacterStmt != nil {
+		if cerr := q.untagProjectCharacterStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing untagProjectCharacterStmt: %w", cerr)
+		}
+	}
+	if q.updateCharacterStmt != nil {
+		if cerr := q.updateCharacterStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing updateCharacterStmt: %w", cerr)
+		}
+	}
+	if q.updateMusicStmt != nil {
+		if cerr := q.updateMusicStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing updateMusicStmt: %w", cerr)
+		}
+	}
+	if q.updateProjectDateAnnouncedStmt != nil {
+		if cerr := q.updateProjectDateAnnouncedStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing updateProjectDateAnnouncedStmt: %w", cerr)
+		}
+	}
+	if q.updateProjectDateCompletedStmt != nil {
+		if cerr := q.updateProjectDateCompletedStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing updateProjectDateCompletedStmt: %w", cerr)
+		}
+	}
+	if q.updateProjectPartStmt != nil {
+		if cerr := q.updateProjectPartStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing updateProjectPartStmt: %w", cerr)
+		}
+	}
+	if q.updateProjectTypeStmt != nil {
+		if cerr := q.updateProjectTypeStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing updateProjectTypeStmt: %w", cerr)
+		}
+	}
+	if q.upsertFileFingerprintStmt != nil {
+		if cerr := q.upsertFileFingerprintStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing upsertFileFingerprintStmt: %w", cerr)
+		}
+	}
+	if q.upsertFileProbeStmt != nil {
+		if cerr := q.upsertFileProbeStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing upsertFileProbeStmt: %w", cerr)
+		}
+	}
+	if q.upsertFileVideoStmt != nil {
+		if cerr := q.upsertFileVideoStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing upsertFileVideoStmt: %w", cerr)
+		}
+	}
 	return err
 }
 
@@ -340,79 +1220,299 @@ func (q *Queries) queryRow(ctx context.Context, stmt *sql.Stmt, query string, ar
 type Queries struct {
 	db                                   DBTX
 	tx                                   *sql.Tx
+	assignArtistChannelStmt              *sql.Stmt
 	assignProjectFileStmt                *sql.Stmt
+	assignProjectMusicStmt               *sql.Stmt
 	assignYoutubeDescriptionStmt         *sql.Stmt
 	assignYoutubeFileIDStmt              *sql.Stmt
 	assignYoutubeTitleStmt               *sql.Stmt
 	assignYoutubeVideoToProjectStmt      *sql.Stmt
+	deleteArtistStmt                     *sql.Stmt
+	deleteArtistAliasStmt                *sql.Stmt
+	deleteCharacterStmt                  *sql.Stmt
+	deleteCharacterAliasStmt             *sql.Stmt
 	deleteFileByIDStmt                   *sql.Stmt
+	deleteFileIntentStmt                 *sql.Stmt
+	deleteFileProbeMismatchesStmt        *sql.Stmt
+	deleteFileProbeStreamsStmt           *sql.Stmt
+	deleteLatestProjectStatusStmt        *sql.Stmt
+	deleteMusicStmt                      *sql.Stmt
 	deleteProjectByUUIDStmt              *sql.Stmt
+	deleteProjectPartStmt                *sql.Stmt
+	deleteProjectRelationStmt            *sql.Stmt
+	exportSnapshotStmt                   *sql.Stmt
+	findCharactersStmt                   *sql.Stmt
+	findMusicStmt                        *sql.Stmt
+	getAllFileProbeMismatchesStmt        *sql.Stmt
+	getArtistStmt                        *sql.Stmt
+	getArtistAliasesStmt                 *sql.Stmt
+	getArtistChannelsStmt                *sql.Stmt
+	getArtistIDStmt                      *sql.Stmt
+	getArtistProjectPartsStmt            *sql.Stmt
+	getArtistYoutubeVideosStmt           *sql.Stmt
+	getAuditEntriesStmt                  *sql.Stmt
+	getAuditEntryStmt                    *sql.Stmt
+	getChannelArtistsStmt                *sql.Stmt
+	getCharacterStmt                     *sql.Stmt
+	getCharacterAliasesStmt              *sql.Stmt
+	getCharacterProjectsStmt             *sql.Stmt
+	getCurrentProjectDescriptionStmt     *sql.Stmt
+	getCurrentProjectTitleStmt           *sql.Stmt
 	getFileByIDStmt                      *sql.Stmt
+	getFileExistsByPathStmt              *sql.Stmt
+	getFileFingerprintStmt               *sql.Stmt
+	getFileFingerprintsByDurationStmt    *sql.Stmt
+	getFileIDBySHA256Stmt                *sql.Stmt
+	getFileIntentExistsByPathStmt        *sql.Stmt
+	getFileIntentsStmt                   *sql.Stmt
+	getFileProbeStmt                     *sql.Stmt
+	getFileProbeMismatchesStmt           *sql.Stmt
+	getFileProbeStreamsStmt              *sql.Stmt
+	getFileProjectsStmt                  *sql.Stmt
 	getFileVideoStmt                     *sql.Stmt
+	getFileYoutubeIDStmt                 *sql.Stmt
+	getFilesAfterStmt                    *sql.Stmt
+	getFilesWithoutVideoStmt             *sql.Stmt
+	getMisattributedDownloadsStmt        *sql.Stmt
+	getMusicStmt                         *sql.Stmt
+	getMusicProjectsStmt                 *sql.Stmt
 	getOrphanFilesStmt                   *sql.Stmt
+	getPartCharactersStmt                *sql.Stmt
 	getProjectByUUIDStmt                 *sql.Stmt
 	getProjectByYoutubeIDStmt            *sql.Stmt
+	getProjectCharactersStmt             *sql.Stmt
+	getProjectDescriptionsStmt           *sql.Stmt
 	getProjectFileStmt                   *sql.Stmt
+	getProjectMusicStmt                  *sql.Stmt
+	getProjectPartStmt                   *sql.Stmt
+	getProjectPartsStmt                  *sql.Stmt
+	getProjectRelationAncestorsStmt      *sql.Stmt
+	getProjectRelationDescendantsStmt    *sql.Stmt
+	getProjectRelationsStmt              *sql.Stmt
+	getProjectStatusesStmt               *sql.Stmt
+	getProjectTitlesStmt                 *sql.Stmt
 	getProjectTypeByYoutubeIDStmt        *sql.Stmt
+	getProjectYoutubeIDsStmt             *sql.Stmt
+	getProjectsWithoutFilesStmt          *sql.Stmt
+	getSeriesCharactersStmt              *sql.Stmt
+	getUnassignedDownloadsStmt           *sql.Stmt
+	getUnfingerprintedFileIDsStmt        *sql.Stmt
+	getUnprobedFileIDsStmt               *sql.Stmt
 	getYoutubeChannelByIDStmt            *sql.Stmt
 	getYoutubeChannelVideosStmt          *sql.Stmt
 	getYoutubeDescriptionStmt            *sql.Stmt
 	getYoutubeFileIDStmt                 *sql.Stmt
+	getYoutubeThumbnailStmt              *sql.Stmt
 	getYoutubeTitleStmt                  *sql.Stmt
 	getYoutubeVideoStmt                  *sql.Stmt
 	getYoutubeVideoFormatByYoutubeIDStmt *sql.Stmt
+	getYoutubeWithoutFilesStmt           *sql.Stmt
+	getYoutubeYtdlpInfoStmt              *sql.Stmt
 	getYoutubeYtdlpVersionStmt           *sql.Stmt
+	getYtdlpClaimedVideoStmt             *sql.Stmt
+	lockFilesStmt                        *sql.Stmt
+	lockProjectStmt                      *sql.Stmt
+	lockProjectRelationsStmt             *sql.Stmt
+	lockStorageSharedStmt                *sql.Stmt
+	newArtistStmt                        *sql.Stmt
+	newArtistAliasStmt                   *sql.Stmt
+	newAuditEntryStmt                    *sql.Stmt
+	newCharacterStmt                     *sql.Stmt
+	newCharacterAliasStmt                *sql.Stmt
 	newFileStmt                          *sql.Stmt
+	newFileIntentStmt                    *sql.Stmt
+	newFileProbeMismatchStmt             *sql.Stmt
+	newFileProbeStreamStmt               *sql.Stmt
 	newFileVideoStmt                     *sql.Stmt
+	newMusicStmt                         *sql.Stmt
 	newProjectStmt                       *sql.Stmt
+	newProjectDescriptionStmt            *sql.Stmt
+	newProjectPartStmt                   *sql.Stmt
+	newProjectRelationStmt               *sql.Stmt
+	newProjectStatusStmt                 *sql.Stmt
+	newProjectTitleStmt                  *sql.Stmt
 	newYoutubeStmt                       *sql.Stmt
 	newYoutubeChannelStmt                *sql.Stmt
 	newYoutubeChannelUploaderIDStmt      *sql.Stmt
 	newYoutubeChannelUploaderNameStmt    *sql.Stmt
 	newYoutubeChannelVideoStmt           *sql.Stmt
 	newYoutubeFormatStmt                 *sql.Stmt
+	newYoutubeThumbnailStmt              *sql.Stmt
+	newYoutubeYtdlpInfoStmt              *sql.Stmt
 	newYoutubeYtdlpVersionStmt           *sql.Stmt
+	renameArtistStmt                     *sql.Stmt
+	searchStmt                           *sql.Stmt
+	setProjectDateAnnouncedStmt          *sql.Stmt
+	setProjectDateCompletedStmt          *sql.Stmt
+	tagPartCharacterStmt                 *sql.Stmt
+	tagProjectCharacterStmt              *sql.Stmt
+	tryLockStorageStmt                   *sql.Stmt
+	unassignArtistChannelStmt            *sql.Stmt
 	unassignProjectFileStmt              *sql.Stmt
+	unassignProjectMusicStmt             *sql.Stmt
 	unassignYoutubeVideoFromProjectStmt  *sql.Stmt
+	unlockStorageStmt                    *sql.Stmt
+	unlockStorageSharedStmt              *sql.Stmt
+	untagPartCharacterStmt               *sql.Stmt
+	untagProjectCharacterStmt            *sql.Stmt
+	updateCharacterStmt                  *sql.Stmt
+	updateMusicStmt                      *sql.Stmt
+	updateProjectDateAnnouncedStmt       *sql.Stmt
+	updateProjectDateCompletedStmt       *sql.Stmt
+	updateProjectPartStmt                *sql.Stmt
+	updateProjectTypeStmt                *sql.Stmt
+	upsertFileFingerprintStmt            *sql.Stmt
+	upsertFileProbeStmt                  *sql.Stmt
+	upsertFileVideoStmt                  *sql.Stmt
 }
 
 func (q *Queries) WithTx(tx *sql.Tx) *Queries {
 	return &Queries{
 		db:                                   tx,
 		tx:                                   tx,
+		assignArtistChannelStmt:              q.assignArtistChannelStmt,
 		assignProjectFileStmt:                q.assignProjectFileStmt,
+		assignProjectMusicStmt:               q.assignProjectMusicStmt,
 		assignYoutubeDescriptionStmt:         q.assignYoutubeDescriptionStmt,
 		assignYoutubeFileIDStmt:              q.assignYoutubeFileIDStmt,
 		assignYoutubeTitleStmt:               q.assignYoutubeTitleStmt,
 		assignYoutubeVideoToProjectStmt:      q.assignYoutubeVideoToProjectStmt,
+		deleteArtistStmt:                     q.deleteArtistStmt,
+		deleteArtistAliasStmt:                q.deleteArtistAliasStmt,
+		deleteCharacterStmt:                  q.deleteCharacterStmt,
+		deleteCharacterAliasStmt:             q.deleteCharacterAliasStmt,
 		deleteFileByIDStmt:                   q.deleteFileByIDStmt,
+		deleteFileIntentStmt:                 q.deleteFileIntentStmt,
+		deleteFileProbeMismatchesStmt:        q.deleteFileProbeMismatchesStmt,
+		deleteFileProbeStreamsStmt:           q.deleteFileProbeStreamsStmt,
+		deleteLatestProjectStatusStmt:        q.deleteLatestProjectStatusStmt,
+		deleteMusicStmt:                      q.deleteMusicStmt,
 		deleteProjectByUUIDStmt:              q.deleteProjectByUUIDStmt,
+		deleteProjectPartStmt:                q.deleteProjectPartStmt,
+		deleteProjectRelationStmt:            q.deleteProjectRelationStmt,
+		exportSnapshotStmt:                   q.exportSnapshotStmt,
+		findCharactersStmt:                   q.findCharactersStmt,
+		findMusicStmt:                        q.findMusicStmt,
+		getAllFileProbeMismatchesStmt:        q.getAllFileProbeMismatchesStmt,
+		getArtistStmt:                        q.getArtistStmt,
+		getArtistAliasesStmt:                 q.getArtistAliasesStmt,
+		getArtistChannelsStmt:                q.getArtistChannelsStmt,
+		getArtistIDStmt:                      q.getArtistIDStmt,
+		getArtistProjectPartsStmt:            q.getArtistProjectPartsStmt,
+		getArtistYoutubeVideosStmt:           q.getArtistYoutubeVideosStmt,
+		getAuditEntriesStmt:                  q.getAuditEntriesStmt,
+		getAuditEntryStmt:                    q.getAuditEntryStmt,
+		getChannelArtistsStmt:                q.getChannelArtistsStmt,
+		getCharacterStmt:                     q.getCharacterStmt,
+		getCharacterAliasesStmt:              q.getCharacterAliasesStmt,
+		getCharacterProjectsStmt:             q.getCharacterProjectsStmt,
+		getCurrentProjectDescriptionStmt:     q.getCurrentProjectDescriptionStmt,
+		getCurrentProjectTitleStmt:           q.getCurrentProjectTitleStmt,
 		getFileByIDStmt:                      q.getFileByIDStmt,
+		getFileExistsByPathStmt:              q.getFileExistsByPathStmt,
+		getFileFingerprintStmt:               q.getFileFingerprintStmt,
+		getFileFingerprintsByDurationStmt:    q.getFileFingerprintsByDurationStmt,
+		getFileIDBySHA256Stmt:                q.getFileIDBySHA256Stmt,
+		getFileIntentExistsByPathStmt:        q.getFileIntentExistsByPathStmt,
+		getFileIntentsStmt:                   q.getFileIntentsStmt,
+		getFileProbeStmt:                     q.getFileProbeStmt,
+		getFileProbeMismatchesStmt:           q.getFileProbeMismatchesStmt,
+		getFileProbeStreamsStmt:              q.getFileProbeStreamsStmt,
+		getFileProjectsStmt:                  q.getFileProjectsStmt,
 		getFileVideoStmt:                     q.getFileVideoStmt,
+		getFileYoutubeIDStmt:                 q.getFileYoutubeIDStmt,
+		getFilesAfterStmt:                    q.getFilesAfterStmt,
+		getFilesWithoutVideoStmt:             q.getFilesWithoutVideoStmt,
+		getMisattributedDownloadsStmt:        q.getMisattributedDownloadsStmt,
+		getMusicStmt:                         q.getMusicStmt,
+		getMusicProjectsStmt:                 q.getMusicProjectsStmt,
 		getOrphanFilesStmt:                   q.getOrphanFilesStmt,
+		getPartCharactersStmt:                q.getPartCharactersStmt,
 		getProjectByUUIDStmt:                 q.getProjectByUUIDStmt,
 		getProjectByYoutubeIDStmt:            q.getProjectByYoutubeIDStmt,
+		getProjectCharactersStmt:             q.getProjectCharactersStmt,
+		getProjectDescriptionsStmt:           q.getProjectDescriptionsStmt,
 		getProjectFileStmt:                   q.getProjectFileStmt,
+		getProjectMusicStmt:                  q.getProjectMusicStmt,
+		getProjectPartStmt:                   q.getProjectPartStmt,
+		getProjectPartsStmt:                  q.getProjectPartsStmt,
+		getProjectRelationAncestorsStmt:      q.getProjectRelationAncestorsStmt,
+		getProjectRelationDescendantsStmt:    q.getProjectRelationDescendantsStmt,
+		getProjectRelationsStmt:              q.getProjectRelationsStmt,
+		getProjectStatusesStmt:               q.getProjectStatusesStmt,
+		getProjectTitlesStmt:                 q.getProjectTitlesStmt,
 		getProjectTypeByYoutubeIDStmt:        q.getProjectTypeByYoutubeIDStmt,
+		getProjectYoutubeIDsStmt:             q.getProjectYoutubeIDsStmt,
+		getProjectsWithoutFilesStmt:          q.getProjectsWithoutFilesStmt,
+		getSeriesCharactersStmt:              q.getSeriesCharactersStmt,
+		getUnassignedDownloadsStmt:           q.getUnassignedDownloadsStmt,
+		getUnfingerprintedFileIDsStmt:        q.getUnfingerprintedFileIDsStmt,
+		getUnprobedFileIDsStmt:               q.getUnprobedFileIDsStmt,
 		getYoutubeChannelByIDStmt:            q.getYoutubeChannelByIDStmt,
 		getYoutubeChannelVideosStmt:          q.getYoutubeChannelVideosStmt,
 		getYoutubeDescriptionStmt:            q.getYoutubeDescriptionStmt,
 		getYoutubeFileIDStmt:                 q.getYoutubeFileIDStmt,
+		getYoutubeThumbnailStmt:              q.getYoutubeThumbnailStmt,
 		getYoutubeTitleStmt:                  q.getYoutubeTitleStmt,
 		getYoutubeVideoStmt:                  q.getYoutubeVideoStmt,
 		getYoutubeVideoFormatByYoutubeIDStmt: q.getYoutubeVideoFormatByYoutubeIDStmt,
+		getYoutubeWithoutFilesStmt:           q.getYoutubeWithoutFilesStmt,
+		getYoutubeYtdlpInfoStmt:              q.getYoutubeYtdlpInfoStmt,
 		getYoutubeYtdlpVersionStmt:           q.getYoutubeYtdlpVersionStmt,
+		getYtdlpClaimedVideoStmt:             q.getYtdlpClaimedVideoStmt,
+		lockFilesStmt:                        q.lockFilesStmt,
+		lockProjectStmt:                      q.lockProjectStmt,
+		lockProjectRelationsStmt:             q.lockProjectRelationsStmt,
+		lockStorageSharedStmt:                q.lockStorageSharedStmt,
+		newArtistStmt:                        q.newArtistStmt,
+		newArtistAliasStmt:                   q.newArtistAliasStmt,
+		newAuditEntryStmt:                    q.newAuditEntryStmt,
+		newCharacterStmt:                     q.newCharacterStmt,
+		newCharacterAliasStmt:                q.newCharacterAliasStmt,
 		newFileStmt:                          q.newFileStmt,
+		newFileIntentStmt:                    q.newFileIntentStmt,
+		newFileProbeMismatchStmt:             q.newFileProbeMismatchStmt,
+		newFileProbeStreamStmt:               q.newFileProbeStreamStmt,
 		newFileVideoStmt:                     q.newFileVideoStmt,
+		newMusicStmt:                         q.newMusicStmt,
 		newProjectStmt:                       q.newProjectStmt,
+		newProjectDescriptionStmt:            q.newProjectDescriptionStmt,
+		newProjectPartStmt:                   q.newProjectPartStmt,
+		newProjectRelationStmt:               q.newProjectRelationStmt,
+		newProjectStatusStmt:                 q.newProjectStatusStmt,
+		newProjectTitleStmt:                  q.newProjectTitleStmt,
 		newYoutubeStmt:                       q.newYoutubeStmt,
 		newYoutubeChannelStmt:                q.newYoutubeChannelStmt,
 		newYoutubeChannelUploaderIDStmt:      q.newYoutubeChannelUploaderIDStmt,
 		newYoutubeChannelUploaderNameStmt:    q.newYoutubeChannelUploaderNameStmt,
 		newYoutubeChannelVideoStmt:           q.newYoutubeChannelVideoStmt,
 		newYoutubeFormatStmt:                 q.newYoutubeFormatStmt,
+		newYoutubeThumbnailStmt:              q.newYoutubeThumbnailStmt,
+		newYoutubeYtdlpInfoStmt:              q.newYoutubeYtdlpInfoStmt,
 		newYoutubeYtdlpVersionStmt:           q.newYoutubeYtdlpVersionStmt,
+		renameArtistStmt:                     q.renameArtistStmt,
+		searchStmt:                           q.searchStmt,
+		setProjectDateAnnouncedStmt:          q.setProjectDateAnnouncedStmt,
+		setProjectDateCompletedStmt:          q.setProjectDateCompletedStmt,
+		tagPartCharacterStmt:                 q.tagPartCharacterStmt,
+		tagProjectCharacterStmt:              q.tagProjectCharacterStmt,
+		tryLockStorageStmt:                   q.tryLockStorageStmt,
+		unassignArtistChannelStmt:            q.unassignArtistChannelStmt,
 		unassignProjectFileStmt:              q.unassignProjectFileStmt,
+		unassignProjectMusicStmt:             q.unassignProjectMusicStmt,
 		unassignYoutubeVideoFromProjectStmt:  q.unassignYoutubeVideoFromProjectStmt,
+		unlockStorageStmt:                    q.unlockStorageStmt,
+		unlockStorageSharedStmt:              q.unlockStorageSharedStmt,
+		untagPartCharacterStmt:               q.untagPartCharacterStmt,
+		untagProjectCharacterStmt:            q.untagProjectCharacterStmt,
+		updateCharacterStmt:                  q.updateCharacterStmt,
+		updateMusicStmt:                      q.updateMusicStmt,
+		updateProjectDateAnnouncedStmt:       q.updateProjectDateAnnouncedStmt,
+		updateProjectDateCompletedStmt:       q.updateProjectDateCompletedStmt,
+		updateProjectPartStmt:                q.updateProjectPartStmt,
+		updateProjectTypeStmt:                q.updateProjectTypeStmt,
+		upsertFileFingerprintStmt:            q.upsertFileFingerprintStmt,
+		upsertFileProbeStmt:                  q.upsertFileProbeStmt,
+		upsertFileVideoStmt:                  q.upsertFileVideoStmt,
 	}
 }
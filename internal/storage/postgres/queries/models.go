@@ -7,12 +7,322 @@ package queries
 import (
 	"database/sql"
 	"database/sql/driver"
+	"encoding/json"
 	"fmt"
 	"time"
 
 	"github.com/dtbead/wc-maps-archive/internal/entities"
 )
 
+type Auditentity string
+
+const (
+	AuditentityProject Auditentity = "project"
+	AuditentityFile    Auditentity = "file"
+	AuditentityYoutube Auditentity = "youtube"
+)
+
+func (e *Auditentity) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = Auditentity(s)
+	case string:
+		*e = Auditentity(s)
+	default:
+		return fmt.Errorf("unsupported scan type for Auditentity: %T", src)
+	}
+	return nil
+}
+
+type NullAuditentity struct {
+	Auditentity Auditentity
+	Valid       bool // Valid is true if Auditentity is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullAuditentity) Scan(value interface{}) error {
+	if value == nil {
+		ns.Auditentity, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.Auditentity.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullAuditentity) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.Auditentity), nil
+}
+
+type Characternamestage string
+
+const (
+	CharacternamestageKit        Characternamestage = "kit"
+	CharacternamestageApprentice Characternamestage = "apprentice"
+	CharacternamestageWarrior    Characternamestage = "warrior"
+	CharacternamestageLeader     Characternamestage = "leader"
+	CharacternamestageOther      Characternamestage = "other"
+)
+
+func (e *Characternamestage) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = Characternamestage(s)
+	case string:
+		*e = Characternamestage(s)
+	default:
+		return fmt.Errorf("unsupported scan type for Characternamestage: %T", src)
+	}
+	return nil
+}
+
+type NullCharacternamestage struct {
+	Characternamestage Characternamestage
+	Valid              bool // Valid is true if Characternamestage is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullCharacternamestage) Scan(value interface{}) error {
+	if value == nil {
+		ns.Characternamestage, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.Characternamestage.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullCharacternamestage) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.Characternamestage), nil
+}
+
+type Dateprecision string
+
+const (
+	DateprecisionYear  Dateprecision = "year"
+	DateprecisionMonth Dateprecision = "month"
+	DateprecisionDay   Dateprecision = "day"
+)
+
+func (e *Dateprecision) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = Dateprecision(s)
+	case string:
+		*e = Dateprecision(s)
+	default:
+		return fmt.Errorf("unsupported scan type for Dateprecision: %T", src)
+	}
+	return nil
+}
+
+type NullDateprecision struct {
+	Dateprecision Dateprecision
+	Valid         bool // Valid is true if Dateprecision is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullDateprecision) Scan(value interface{}) error {
+	if value == nil {
+		ns.Dateprecision, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.Dateprecision.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullDateprecision) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.Dateprecision), nil
+}
+
+type Fileintentaction string
+
+const (
+	FileintentactionAdd    Fileintentaction = "add"
+	FileintentactionDelete Fileintentaction = "delete"
+)
+
+func (e *Fileintentaction) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = Fileintentaction(s)
+	case string:
+		*e = Fileintentaction(s)
+	default:
+		return fmt.Errorf("unsupported scan type for Fileintentaction: %T", src)
+	}
+	return nil
+}
+
+type NullFileintentaction struct {
+	Fileintentaction Fileintentaction
+	Valid            bool // Valid is true if Fileintentaction is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullFileintentaction) Scan(value interface{}) error {
+	if value == nil {
+		ns.Fileintentaction, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.Fileintentaction.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullFileintentaction) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.Fileintentaction), nil
+}
+
+type Partstatus string
+
+const (
+	PartstatusUnknown   Partstatus = "unknown"
+	PartstatusOpen      Partstatus = "open"
+	PartstatusClaimed   Partstatus = "claimed"
+	PartstatusCompleted Partstatus = "completed"
+	PartstatusDropped   Partstatus = "dropped"
+)
+
+func (e *Partstatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = Partstatus(s)
+	case string:
+		*e = Partstatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for Partstatus: %T", src)
+	}
+	return nil
+}
+
+type NullPartstatus struct {
+	Partstatus Partstatus
+	Valid      bool // Valid is true if Partstatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullPartstatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.Partstatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.Partstatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullPartstatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.Partstatus), nil
+}
+
+type Projectrelationtype string
+
+const (
+	ProjectrelationtypePartOf     Projectrelationtype = "part-of"
+	ProjectrelationtypeBackupOf   Projectrelationtype = "backup-of"
+	ProjectrelationtypeSequelOf   Projectrelationtype = "sequel-of"
+	ProjectrelationtypeReuploadOf Projectrelationtype = "reupload-of"
+)
+
+func (e *Projectrelationtype) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = Projectrelationtype(s)
+	case string:
+		*e = Projectrelationtype(s)
+	default:
+		return fmt.Errorf("unsupported scan type for Projectrelationtype: %T", src)
+	}
+	return nil
+}
+
+type NullProjectrelationtype struct {
+	Projectrelationtype Projectrelationtype
+	Valid               bool // Valid is true if Projectrelationtype is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullProjectrelationtype) Scan(value interface{}) error {
+	if value == nil {
+		ns.Projectrelationtype, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.Projectrelationtype.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullProjectrelationtype) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.Projectrelationtype), nil
+}
+
+type Projectstatus string
+
+const (
+	ProjectstatusAnnounced     Projectstatus = "announced"
+	ProjectstatusSignUpsopen   Projectstatus = "sign-ups open"
+	ProjectstatusPartsassigned Projectstatus = "parts assigned"
+	ProjectstatusInprogress    Projectstatus = "in progress"
+	ProjectstatusCompleted     Projectstatus = "completed"
+	ProjectstatusCancelled     Projectstatus = "cancelled"
+	ProjectstatusRevived       Projectstatus = "revived"
+)
+
+func (e *Projectstatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = Projectstatus(s)
+	case string:
+		*e = Projectstatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for Projectstatus: %T", src)
+	}
+	return nil
+}
+
+type NullProjectstatus struct {
+	Projectstatus Projectstatus
+	Valid         bool // Valid is true if Projectstatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullProjectstatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.Projectstatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.Projectstatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullProjectstatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.Projectstatus), nil
+}
+
 type Projecttype string
 
 const (
@@ -62,6 +372,33 @@ func (ns NullProjecttype) Value() (driver.Value, error) {
 	return string(ns.Projecttype), nil
 }
 
+type Artist struct {
+	ID        int64
+	Name      string
+	DateAdded time.Time
+}
+
+type ArtistAlias struct {
+	ArtistID int64
+	Alias    string
+}
+
+type ArtistChannel struct {
+	ArtistID  int64
+	ChannelID interface{}
+}
+
+type AuditLog struct {
+	ID        int64
+	Actor     string
+	Entity    Auditentity
+	EntityID  string
+	Action    string
+	Before    json.RawMessage
+	After     json.RawMessage
+	DateAdded time.Time
+}
+
 type Character struct {
 	ID         int32
 	Name       string
@@ -69,6 +406,12 @@ type Character struct {
 	IsOriginal bool
 }
 
+type CharacterAlias struct {
+	CharacterID int32
+	Name        string
+	Stage       Characternamestage
+}
+
 type File struct {
 	ID        int64
 	Path      string
@@ -79,6 +422,49 @@ type File struct {
 	Filesize  int64
 }
 
+type FileFingerprint struct {
+	FileID        int64
+	FrameInterval float64
+	Hashes        []byte
+	DateAdded     time.Time
+}
+
+type FileIntent struct {
+	ID        int64
+	Action    Fileintentaction
+	Path      string
+	DateAdded time.Time
+}
+
+type FileProbe struct {
+	FileID     int64
+	Container  string
+	Duration   float64
+	Bitrate    sql.NullInt64
+	DateProbed time.Time
+}
+
+type FileProbeMismatch struct {
+	FileID    int64
+	Field     string
+	Expected  string
+	Actual    string
+	DateAdded time.Time
+}
+
+type FileProbeStream struct {
+	FileID      int64
+	StreamIndex int16
+	CodecType   string
+	Codec       sql.NullString
+	Bitrate     sql.NullInt64
+	Width       sql.NullInt16
+	Height      sql.NullInt16
+	Fps         sql.NullFloat64
+	SampleRate  sql.NullInt32
+	Channels    sql.NullInt16
+}
+
 type FileVideo struct {
 	FileID     int64
 	Duration   int32
@@ -96,12 +482,16 @@ type Music struct {
 }
 
 type Project struct {
-	ID            int64
-	Uuid          string
-	Type          Projecttype
-	DateAnnounced sql.NullTime
-	DateCompleted sql.NullTime
-	DateArchived  time.Time
+	ID                       int64
+	Uuid                     string
+	Type                     Projecttype
+	DateAnnounced            sql.NullTime
+	DateAnnouncedPrecision   NullDateprecision
+	DateAnnouncedApproximate bool
+	DateCompleted            sql.NullTime
+	DateCompletedPrecision   NullDateprecision
+	DateCompletedApproximate bool
+	DateArchived             time.Time
 }
 
 type ProjectCharacter struct {
@@ -110,6 +500,7 @@ type ProjectCharacter struct {
 }
 
 type ProjectDescription struct {
+	ID             int64
 	ProjectID      int64
 	Description    string
 	DescriptionMd5 []byte
@@ -126,12 +517,48 @@ type ProjectMusic struct {
 	MusicID   int32
 }
 
+type ProjectPart struct {
+	ID                   int64
+	ProjectID            int64
+	PartNumber           int16
+	StartMs              sql.NullInt32
+	EndMs                sql.NullInt32
+	Status               Partstatus
+	ParticipantChannelID sql.NullString
+	ParticipantName      sql.NullString
+	FileID               sql.NullInt64
+	YoutubeID            sql.NullString
+	ParticipantArtistID  sql.NullInt64
+}
+
+type ProjectPartCharacter struct {
+	PartID      int64
+	CharacterID int32
+}
+
 type ProjectParticipant struct {
 	ProjectID int32
 	FileID    int64
 }
 
+type ProjectRelation struct {
+	ProjectID        int64
+	RelatedProjectID int64
+	Type             Projectrelationtype
+	DateAdded        time.Time
+}
+
+type ProjectStatus struct {
+	ID        int64
+	ProjectID int64
+	Status    Projectstatus
+	Date      time.Time
+	Note      string
+	DateAdded time.Time
+}
+
 type ProjectTitle struct {
+	ID        int64
 	ProjectID int64
 	Title     string
 	TitleMd5  []byte
@@ -194,6 +621,18 @@ type YoutubeVideoFormat struct {
 	Format    string
 }
 
+type YoutubeVideoThumbnail struct {
+	FileID    int64
+	YoutubeID interface{}
+	Thumbnail []byte
+}
+
+type YoutubeVideoYtdlpInfo struct {
+	FileID    int64
+	YoutubeID interface{}
+	Info      json.RawMessage
+}
+
 type YoutubeVideoYtdlpVersion struct {
 	FileID         int64
 	YoutubeID      interface{}
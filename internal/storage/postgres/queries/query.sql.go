@@ -8,11 +8,26 @@ package queries
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"time"
 
 	"github.com/dtbead/wc-maps-archive/internal/entities"
 )
 
+const assignArtistChannel = `-- name: AssignArtistChannel :exec
+INSERT INTO artist_channel (artist_id, channel_id) VALUES ($1, $2) ON CONFLICT DO NOTHING
+`
+
+type AssignArtistChannelParams struct {
+	ArtistID  int64
+	ChannelID interface{}
+}
+
+func (q *Queries) AssignArtistChannel(ctx context.Context, arg AssignArtistChannelParams) error {
+	_, err := q.exec(ctx, q.assignArtistChannelStmt, assignArtistChannel, arg.ArtistID, arg.ChannelID)
+	return err
+}
+
 const assignProjectFile = `-- name: AssignProjectFile :exec
 INSERT INTO project_file (project_id, file_id) VALUES ((SELECT id FROM project WHERE uuid = $1), $2)
 `
@@ -27,6 +42,21 @@ func (q *Queries) AssignProjectFile(ctx context.Context, arg AssignProjectFilePa
 	return err
 }
 
+const assignProjectMusic = `-- name: AssignProjectMusic :exec
+INSERT INTO project_music (project_id, music_id) VALUES ((SELECT id FROM project WHERE uuid = $1), $2)
+ON CONFLICT DO NOTHING
+`
+
+type AssignProjectMusicParams struct {
+	Uuid    string
+	MusicID int32
+}
+
+func (q *Queries) AssignProjectMusic(ctx context.Context, arg AssignProjectMusicParams) error {
+	_, err := q.exec(ctx, q.assignProjectMusicStmt, assignProjectMusic, arg.Uuid, arg.MusicID)
+	return err
+}
+
 const assignYoutubeDescription = `-- name: AssignYoutubeDescription :exec
 INSERT INTO youtube_description (youtube_id, description, description_md5) VALUES ($1, $2, $3) ON CONFLICT DO NOTHING
 `
@@ -86,6 +116,64 @@ func (q *Queries) AssignYoutubeVideoToProject(ctx context.Context, arg AssignYou
 	return err
 }
 
+const deleteArtist = `-- name: DeleteArtist :execrows
+DELETE FROM artist WHERE id = $1
+`
+
+func (q *Queries) DeleteArtist(ctx context.Context, id int64) (int64, error) {
+	result, err := q.exec(ctx, q.deleteArtistStmt, deleteArtist, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deleteArtistAlias = `-- name: DeleteArtistAlias :execrows
+DELETE FROM artist_alias WHERE artist_id = $1 AND alias = $2
+`
+
+type DeleteArtistAliasParams struct {
+	ArtistID int64
+	Alias    string
+}
+
+func (q *Queries) DeleteArtistAlias(ctx context.Context, arg DeleteArtistAliasParams) (int64, error) {
+	result, err := q.exec(ctx, q.deleteArtistAliasStmt, deleteArtistAlias, arg.ArtistID, arg.Alias)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deleteCharacter = `-- name: DeleteCharacter :execrows
+DELETE FROM character WHERE id = $1
+`
+
+func (q *Queries) DeleteCharacter(ctx context.Context, id int32) (int64, error) {
+	result, err := q.exec(ctx, q.deleteCharacterStmt, deleteCharacter, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deleteCharacterAlias = `-- name: DeleteCharacterAlias :execrows
+DELETE FROM character_alias WHERE character_id = $1 AND name = $2
+`
+
+type DeleteCharacterAliasParams struct {
+	CharacterID int32
+	Name        string
+}
+
+func (q *Queries) DeleteCharacterAlias(ctx context.Context, arg DeleteCharacterAliasParams) (int64, error) {
+	result, err := q.exec(ctx, q.deleteCharacterAliasStmt, deleteCharacterAlias, arg.CharacterID, arg.Name)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 const deleteFileByID = `-- name: DeleteFileByID :exec
 DELETE FROM file WHERE id = $1
 `
@@ -95,6 +183,63 @@ func (q *Queries) DeleteFileByID(ctx context.Context, id int64) error {
 	return err
 }
 
+const deleteFileIntent = `-- name: DeleteFileIntent :exec
+DELETE FROM file_intent WHERE id = $1
+`
+
+func (q *Queries) DeleteFileIntent(ctx context.Context, id int64) error {
+	_, err := q.exec(ctx, q.deleteFileIntentStmt, deleteFileIntent, id)
+	return err
+}
+
+const deleteFileProbeMismatches = `-- name: DeleteFileProbeMismatches :exec
+DELETE FROM file_probe_mismatch WHERE file_id = $1
+`
+
+func (q *Queries) DeleteFileProbeMismatches(ctx context.Context, fileID int64) error {
+	_, err := q.exec(ctx, q.deleteFileProbeMismatchesStmt, deleteFileProbeMismatches, fileID)
+	return err
+}
+
+const deleteFileProbeStreams = `-- name: DeleteFileProbeStreams :exec
+DELETE FROM file_probe_stream WHERE file_id = $1
+`
+
+func (q *Queries) DeleteFileProbeStreams(ctx context.Context, fileID int64) error {
+	_, err := q.exec(ctx, q.deleteFileProbeStreamsStmt, deleteFileProbeStreams, fileID)
+	return err
+}
+
+const deleteLatestProjectStatus = `-- name: DeleteLatestProjectStatus :execrows
+DELETE FROM project_status WHERE id = (
+    SELECT project_status.id FROM project_status
+    INNER JOIN project ON project.id = project_status.project_id
+    WHERE project.uuid = $1
+    ORDER BY project_status.date DESC, project_status.id DESC
+    LIMIT 1
+)
+`
+
+func (q *Queries) DeleteLatestProjectStatus(ctx context.Context, uuid string) (int64, error) {
+	result, err := q.exec(ctx, q.deleteLatestProjectStatusStmt, deleteLatestProjectStatus, uuid)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deleteMusic = `-- name: DeleteMusic :execrows
+DELETE FROM music WHERE id = $1
+`
+
+func (q *Queries) DeleteMusic(ctx context.Context, id int32) (int64, error) {
+	result, err := q.exec(ctx, q.deleteMusicStmt, deleteMusic, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 const deleteProjectByUUID = `-- name: DeleteProjectByUUID :exec
 DELETE FROM project WHERE uuid = $1
 `
@@ -104,70 +249,70 @@ func (q *Queries) DeleteProjectByUUID(ctx context.Context, uuid string) error {
 	return err
 }
 
-const getFileByID = `-- name: GetFileByID :one
-SELECT id, path, extension, md5, sha1, sha256, filesize FROM file WHERE id = $1
+const deleteProjectPart = `-- name: DeleteProjectPart :execrows
+DELETE FROM project_part WHERE id = $1
 `
 
-func (q *Queries) GetFileByID(ctx context.Context, id int64) (File, error) {
-	row := q.queryRow(ctx, q.getFileByIDStmt, getFileByID, id)
-	var i File
-	err := row.Scan(
-		&i.ID,
-		&i.Path,
-		&i.Extension,
-		&i.Md5,
-		&i.Sha1,
-		&i.Sha256,
-		&i.Filesize,
-	)
-	return i, err
+func (q *Queries) DeleteProjectPart(ctx context.Context, id int64) (int64, error) {
+	result, err := q.exec(ctx, q.deleteProjectPartStmt, deleteProjectPart, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
 }
 
-const getFileVideo = `-- name: GetFileVideo :one
-SELECT file_id, duration, width, height, fps, video_codec, audio_codec FROM file_video WHERE file_id = $1
+const deleteProjectRelation = `-- name: DeleteProjectRelation :execrows
+DELETE FROM project_relation WHERE
+    project_id = (SELECT id FROM project WHERE uuid = $1)
+AND related_project_id = (SELECT id FROM project WHERE uuid = $2)
+AND type = $3
 `
 
-func (q *Queries) GetFileVideo(ctx context.Context, fileID int64) (FileVideo, error) {
-	row := q.queryRow(ctx, q.getFileVideoStmt, getFileVideo, fileID)
-	var i FileVideo
-	err := row.Scan(
-		&i.FileID,
-		&i.Duration,
-		&i.Width,
-		&i.Height,
-		&i.Fps,
-		&i.VideoCodec,
-		&i.AudioCodec,
-	)
-	return i, err
+type DeleteProjectRelationParams struct {
+	Uuid   string
+	Uuid_2 string
+	Type   Projectrelationtype
 }
 
-const getOrphanFiles = `-- name: GetOrphanFiles :many
-SELECT file.id, file.path, file.extension, file.md5, file.sha1, file.sha256, file.filesize FROM file 
-WHERE file.id NOT IN (
-	SELECT youtube_file.file_id FROM youtube_file
-		UNION 
-	SELECT project_file.file_id FROM project_file
-)
+func (q *Queries) DeleteProjectRelation(ctx context.Context, arg DeleteProjectRelationParams) (int64, error) {
+	result, err := q.exec(ctx, q.deleteProjectRelationStmt, deleteProjectRelation, arg.Uuid, arg.Uuid_2, arg.Type)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const exportSnapshot = `-- name: ExportSnapshot :one
+SELECT pg_export_snapshot()::text AS snapshot_id
 `
 
-func (q *Queries) GetOrphanFiles(ctx context.Context) ([]File, error) {
-	rows, err := q.query(ctx, q.getOrphanFilesStmt, getOrphanFiles)
+func (q *Queries) ExportSnapshot(ctx context.Context) (string, error) {
+	row := q.queryRow(ctx, q.exportSnapshotStmt, exportSnapshot)
+	var snapshot_id string
+	err := row.Scan(&snapshot_id)
+	return snapshot_id, err
+}
+
+const findCharacters = `-- name: FindCharacters :many
+SELECT id, name, series, is_original FROM character
+WHERE name = $1 OR id IN (SELECT character_id FROM character_alias WHERE character_alias.name = $1)
+ORDER BY name, series, id
+`
+
+func (q *Queries) FindCharacters(ctx context.Context, name string) ([]Character, error) {
+	rows, err := q.query(ctx, q.findCharactersStmt, findCharacters, name)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []File
+	var items []Character
 	for rows.Next() {
-		var i File
+		var i Character
 		if err := rows.Scan(
 			&i.ID,
-			&i.Path,
-			&i.Extension,
-			&i.Md5,
-			&i.Sha1,
-			&i.Sha256,
-			&i.Filesize,
+			&i.Name,
+			&i.Series,
+			&i.IsOriginal,
 		); err != nil {
 			return nil, err
 		}
@@ -182,62 +327,55 @@ func (q *Queries) GetOrphanFiles(ctx context.Context) ([]File, error) {
 	return items, nil
 }
 
-const getProjectByUUID = `-- name: GetProjectByUUID :one
-SELECT id, uuid, type, date_announced, date_completed, date_archived FROM project WHERE uuid = $1
+const findMusic = `-- name: FindMusic :many
+SELECT id, artist, title, score FROM (
+    SELECT music.id, music.artist, music.title, (
+        CASE WHEN $1::text = '' THEN 1 ELSE word_similarity($1::text, artist::text) END *
+        CASE WHEN $2::text = '' THEN 1 ELSE word_similarity($2::text, title::text) END
+    )::DOUBLE PRECISION AS score FROM music
+) AS matches
+WHERE score >= $3::DOUBLE PRECISION
+ORDER BY score DESC, artist, title
+LIMIT $4::INTEGER
 `
 
-func (q *Queries) GetProjectByUUID(ctx context.Context, uuid string) (Project, error) {
-	row := q.queryRow(ctx, q.getProjectByUUIDStmt, getProjectByUUID, uuid)
-	var i Project
-	err := row.Scan(
-		&i.ID,
-		&i.Uuid,
-		&i.Type,
-		&i.DateAnnounced,
-		&i.DateCompleted,
-		&i.DateArchived,
-	)
-	return i, err
+type FindMusicParams struct {
+	Artist     string
+	Title      string
+	MinScore   float64
+	MaxResults int32
 }
 
-const getProjectByYoutubeID = `-- name: GetProjectByYoutubeID :one
-SELECT project.id, project.uuid, project.type, project.date_announced, project.date_completed, project.date_archived FROM project 
-INNER JOIN project_file ON project.id = project_file.project_id
-INNER JOIN youtube_file ON project_file.file_id = youtube_file.file_id
-WHERE youtube_file.youtube_id = $1
-`
-
-func (q *Queries) GetProjectByYoutubeID(ctx context.Context, youtubeID interface{}) (Project, error) {
-	row := q.queryRow(ctx, q.getProjectByYoutubeIDStmt, getProjectByYoutubeID, youtubeID)
-	var i Project
-	err := row.Scan(
-		&i.ID,
-		&i.Uuid,
-		&i.Type,
-		&i.DateAnnounced,
-		&i.DateCompleted,
-		&i.DateArchived,
-	)
-	return i, err
+type FindMusicRow struct {
+	ID     int32
+	Artist string
+	Title  string
+	Score  float64
 }
 
-const getProjectFile = `-- name: GetProjectFile :many
-SELECT file_id FROM project_file WHERE project_id = (SELECT id FROM project WHERE uuid = $1)
-`
-
-func (q *Queries) GetProjectFile(ctx context.Context, uuid string) ([]int64, error) {
-	rows, err := q.query(ctx, q.getProjectFileStmt, getProjectFile, uuid)
+func (q *Queries) FindMusic(ctx context.Context, arg FindMusicParams) ([]FindMusicRow, error) {
+	rows, err := q.query(ctx, q.findMusicStmt, findMusic,
+		arg.Artist,
+		arg.Title,
+		arg.MinScore,
+		arg.MaxResults,
+	)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []int64
+	var items []FindMusicRow
 	for rows.Next() {
-		var file_id int64
-		if err := rows.Scan(&file_id); err != nil {
+		var i FindMusicRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Artist,
+			&i.Title,
+			&i.Score,
+		); err != nil {
 			return nil, err
 		}
-		items = append(items, file_id)
+		items = append(items, i)
 	}
 	if err := rows.Close(); err != nil {
 		return nil, err
@@ -248,61 +386,29 @@ func (q *Queries) GetProjectFile(ctx context.Context, uuid string) ([]int64, err
 	return items, nil
 }
 
-const getProjectTypeByYoutubeID = `-- name: GetProjectTypeByYoutubeID :one
-SELECT project.type FROM project 
-INNER JOIN project_file ON project.id = project_file.project_id
-INNER JOIN youtube_file ON project_file.file_id = youtube_file.file_id
-WHERE youtube_file.youtube_id = $1
-`
-
-func (q *Queries) GetProjectTypeByYoutubeID(ctx context.Context, youtubeID interface{}) (Projecttype, error) {
-	row := q.queryRow(ctx, q.getProjectTypeByYoutubeIDStmt, getProjectTypeByYoutubeID, youtubeID)
-	var type_ Projecttype
-	err := row.Scan(&type_)
-	return type_, err
-}
-
-const getYoutubeChannelByID = `-- name: GetYoutubeChannelByID :one
-SELECT 
-    youtube_channel_youtube_video.channel_id AS channel_id, 
-    youtube_channel_uploader_id.uploader_id AS uploader_id,
-    youtube_channel_uploader_name.uploader AS uploader_name 
-FROM youtube_channel_youtube_video
-    INNER JOIN youtube_channel_uploader_id ON youtube_channel_uploader_id.channel_id = youtube_channel_youtube_video.channel_id
-    INNER JOIN youtube_channel_uploader_name ON youtube_channel_uploader_name.channel_id = youtube_channel_youtube_video.channel_id
-WHERE youtube_channel_youtube_video.youtube_id = $1 LIMIT 1
-`
-
-type GetYoutubeChannelByIDRow struct {
-	ChannelID    interface{}
-	UploaderID   string
-	UploaderName string
-}
-
-func (q *Queries) GetYoutubeChannelByID(ctx context.Context, youtubeID entities.YoutubeVideoID) (GetYoutubeChannelByIDRow, error) {
-	row := q.queryRow(ctx, q.getYoutubeChannelByIDStmt, getYoutubeChannelByID, youtubeID)
-	var i GetYoutubeChannelByIDRow
-	err := row.Scan(&i.ChannelID, &i.UploaderID, &i.UploaderName)
-	return i, err
-}
-
-const getYoutubeChannelVideos = `-- name: GetYoutubeChannelVideos :many
-SELECT youtube_id FROM youtube_channel_youtube_video WHERE channel_id = $1
+const getAllFileProbeMismatches = `-- name: GetAllFileProbeMismatches :many
+SELECT file_id, field, expected, actual, date_added FROM file_probe_mismatch ORDER BY file_id, field
 `
 
-func (q *Queries) GetYoutubeChannelVideos(ctx context.Context, channelID interface{}) ([]entities.YoutubeVideoID, error) {
-	rows, err := q.query(ctx, q.getYoutubeChannelVideosStmt, getYoutubeChannelVideos, channelID)
+func (q *Queries) GetAllFileProbeMismatches(ctx context.Context) ([]FileProbeMismatch, error) {
+	rows, err := q.query(ctx, q.getAllFileProbeMismatchesStmt, getAllFileProbeMismatches)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []entities.YoutubeVideoID
+	var items []FileProbeMismatch
 	for rows.Next() {
-		var youtube_id entities.YoutubeVideoID
-		if err := rows.Scan(&youtube_id); err != nil {
+		var i FileProbeMismatch
+		if err := rows.Scan(
+			&i.FileID,
+			&i.Field,
+			&i.Expected,
+			&i.Actual,
+			&i.DateAdded,
+		); err != nil {
 			return nil, err
 		}
-		items = append(items, youtube_id)
+		items = append(items, i)
 	}
 	if err := rows.Close(); err != nil {
 		return nil, err
@@ -313,23 +419,34 @@ func (q *Queries) GetYoutubeChannelVideos(ctx context.Context, channelID interfa
 	return items, nil
 }
 
-const getYoutubeDescription = `-- name: GetYoutubeDescription :many
-SELECT description FROM youtube_description WHERE youtube_id = $1
+const getArtist = `-- name: GetArtist :one
+SELECT id, name, date_added FROM artist WHERE id = $1
 `
 
-func (q *Queries) GetYoutubeDescription(ctx context.Context, youtubeID interface{}) ([]string, error) {
-	rows, err := q.query(ctx, q.getYoutubeDescriptionStmt, getYoutubeDescription, youtubeID)
+func (q *Queries) GetArtist(ctx context.Context, id int64) (Artist, error) {
+	row := q.queryRow(ctx, q.getArtistStmt, getArtist, id)
+	var i Artist
+	err := row.Scan(&i.ID, &i.Name, &i.DateAdded)
+	return i, err
+}
+
+const getArtistAliases = `-- name: GetArtistAliases :many
+SELECT alias FROM artist_alias WHERE artist_id = $1 ORDER BY alias
+`
+
+func (q *Queries) GetArtistAliases(ctx context.Context, artistID int64) ([]string, error) {
+	rows, err := q.query(ctx, q.getArtistAliasesStmt, getArtistAliases, artistID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 	var items []string
 	for rows.Next() {
-		var description string
-		if err := rows.Scan(&description); err != nil {
+		var alias string
+		if err := rows.Scan(&alias); err != nil {
 			return nil, err
 		}
-		items = append(items, description)
+		items = append(items, alias)
 	}
 	if err := rows.Close(); err != nil {
 		return nil, err
@@ -340,23 +457,23 @@ func (q *Queries) GetYoutubeDescription(ctx context.Context, youtubeID interface
 	return items, nil
 }
 
-const getYoutubeFileID = `-- name: GetYoutubeFileID :many
-SELECT file_id FROM youtube_file WHERE youtube_id = $1
+const getArtistChannels = `-- name: GetArtistChannels :many
+SELECT channel_id FROM artist_channel WHERE artist_id = $1 ORDER BY channel_id
 `
 
-func (q *Queries) GetYoutubeFileID(ctx context.Context, youtubeID interface{}) ([]int64, error) {
-	rows, err := q.query(ctx, q.getYoutubeFileIDStmt, getYoutubeFileID, youtubeID)
+func (q *Queries) GetArtistChannels(ctx context.Context, artistID int64) ([]interface{}, error) {
+	rows, err := q.query(ctx, q.getArtistChannelsStmt, getArtistChannels, artistID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []int64
+	var items []interface{}
 	for rows.Next() {
-		var file_id int64
-		if err := rows.Scan(&file_id); err != nil {
+		var channel_id interface{}
+		if err := rows.Scan(&channel_id); err != nil {
 			return nil, err
 		}
-		items = append(items, file_id)
+		items = append(items, channel_id)
 	}
 	if err := rows.Close(); err != nil {
 		return nil, err
@@ -367,18 +484,1671 @@ func (q *Queries) GetYoutubeFileID(ctx context.Context, youtubeID interface{}) (
 	return items, nil
 }
 
-const getYoutubeTitle = `-- name: GetYoutubeTitle :many
-SELECT title FROM youtube_title WHERE youtube_id = $1
+const getArtistID = `-- name: GetArtistID :one
+SELECT id FROM artist
+WHERE name = $1 OR id IN (SELECT artist_id FROM artist_alias WHERE alias = $1)
+ORDER BY name = $1 DESC
+LIMIT 1
 `
 
-func (q *Queries) GetYoutubeTitle(ctx context.Context, youtubeID interface{}) ([]string, error) {
-	rows, err := q.query(ctx, q.getYoutubeTitleStmt, getYoutubeTitle, youtubeID)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	var items []string
-	for rows.Next() {
+func (q *Queries) GetArtistID(ctx context.Context, name string) (int64, error) {
+	row := q.queryRow(ctx, q.getArtistIDStmt, getArtistID, name)
+	var id int64
+	err := row.Scan(&id)
+	return id, err
+}
+
+const getArtistProjectParts = `-- name: GetArtistProjectParts :many
+SELECT project.uuid, project_part.id, project_part.project_id, project_part.part_number, project_part.start_ms, project_part.end_ms, project_part.status, project_part.participant_channel_id, project_part.participant_name, project_part.file_id, project_part.youtube_id, project_part.participant_artist_id, artist.name::text AS participant_artist FROM project_part
+INNER JOIN project ON project.id = project_part.project_id
+LEFT JOIN artist ON artist.id = project_part.participant_artist_id
+WHERE project_part.participant_artist_id = $1
+   OR project_part.participant_channel_id IN (SELECT channel_id FROM artist_channel WHERE artist_channel.artist_id = $1)
+   OR project_part.participant_name IN (
+        SELECT artist.name::text FROM artist WHERE artist.id = $1
+        UNION SELECT artist_alias.alias::text FROM artist_alias WHERE artist_alias.artist_id = $1
+   )
+ORDER BY project.uuid, project_part.part_number
+`
+
+type GetArtistProjectPartsRow struct {
+	Uuid                 string
+	ID                   int64
+	ProjectID            int64
+	PartNumber           int16
+	StartMs              sql.NullInt32
+	EndMs                sql.NullInt32
+	Status               Partstatus
+	ParticipantChannelID sql.NullString
+	ParticipantName      sql.NullString
+	FileID               sql.NullInt64
+	YoutubeID            sql.NullString
+	ParticipantArtistID  sql.NullInt64
+	ParticipantArtist    sql.NullString
+}
+
+func (q *Queries) GetArtistProjectParts(ctx context.Context, artistID int64) ([]GetArtistProjectPartsRow, error) {
+	rows, err := q.query(ctx, q.getArtistProjectPartsStmt, getArtistProjectParts, artistID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetArtistProjectPartsRow
+	for rows.Next() {
+		var i GetArtistProjectPartsRow
+		if err := rows.Scan(
+			&i.Uuid,
+			&i.ID,
+			&i.ProjectID,
+			&i.PartNumber,
+			&i.StartMs,
+			&i.EndMs,
+			&i.Status,
+			&i.ParticipantChannelID,
+			&i.ParticipantName,
+			&i.FileID,
+			&i.YoutubeID,
+			&i.ParticipantArtistID,
+			&i.ParticipantArtist,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getArtistYoutubeVideos = `-- name: GetArtistYoutubeVideos :many
+SELECT youtube_video.id FROM youtube_video
+INNER JOIN youtube_channel_youtube_video ON youtube_channel_youtube_video.youtube_id = youtube_video.id
+INNER JOIN artist_channel ON artist_channel.channel_id = youtube_channel_youtube_video.channel_id
+WHERE artist_channel.artist_id = $1
+ORDER BY youtube_video.upload_date, youtube_video.id
+`
+
+func (q *Queries) GetArtistYoutubeVideos(ctx context.Context, artistID int64) ([]entities.YoutubeVideoID, error) {
+	rows, err := q.query(ctx, q.getArtistYoutubeVideosStmt, getArtistYoutubeVideos, artistID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []entities.YoutubeVideoID
+	for rows.Next() {
+		var id entities.YoutubeVideoID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getAuditEntries = `-- name: GetAuditEntries :many
+SELECT id, actor, entity, entity_id, action, before, after, date_added FROM audit_log WHERE entity = $1 AND entity_id = $2 ORDER BY id
+`
+
+type GetAuditEntriesParams struct {
+	Entity   Auditentity
+	EntityID string
+}
+
+func (q *Queries) GetAuditEntries(ctx context.Context, arg GetAuditEntriesParams) ([]AuditLog, error) {
+	rows, err := q.query(ctx, q.getAuditEntriesStmt, getAuditEntries, arg.Entity, arg.EntityID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []AuditLog
+	for rows.Next() {
+		var i AuditLog
+		if err := rows.Scan(
+			&i.ID,
+			&i.Actor,
+			&i.Entity,
+			&i.EntityID,
+			&i.Action,
+			&i.Before,
+			&i.After,
+			&i.DateAdded,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getAuditEntry = `-- name: GetAuditEntry :one
+SELECT id, actor, entity, entity_id, action, before, after, date_added FROM audit_log WHERE id = $1
+`
+
+func (q *Queries) GetAuditEntry(ctx context.Context, id int64) (AuditLog, error) {
+	row := q.queryRow(ctx, q.getAuditEntryStmt, getAuditEntry, id)
+	var i AuditLog
+	err := row.Scan(
+		&i.ID,
+		&i.Actor,
+		&i.Entity,
+		&i.EntityID,
+		&i.Action,
+		&i.Before,
+		&i.After,
+		&i.DateAdded,
+	)
+	return i, err
+}
+
+const getChannelArtists = `-- name: GetChannelArtists :many
+SELECT artist.name FROM artist
+INNER JOIN artist_channel ON artist_channel.artist_id = artist.id
+WHERE artist_channel.channel_id = $1
+ORDER BY artist.name
+`
+
+func (q *Queries) GetChannelArtists(ctx context.Context, channelID interface{}) ([]string, error) {
+	rows, err := q.query(ctx, q.getChannelArtistsStmt, getChannelArtists, channelID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		items = append(items, name)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getCharacter = `-- name: GetCharacter :one
+SELECT id, name, series, is_original FROM character WHERE id = $1
+`
+
+func (q *Queries) GetCharacter(ctx context.Context, id int32) (Character, error) {
+	row := q.queryRow(ctx, q.getCharacterStmt, getCharacter, id)
+	var i Character
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Series,
+		&i.IsOriginal,
+	)
+	return i, err
+}
+
+const getCharacterAliases = `-- name: GetCharacterAliases :many
+SELECT name, stage FROM character_alias WHERE character_id = $1 ORDER BY stage, name
+`
+
+type GetCharacterAliasesRow struct {
+	Name  string
+	Stage Characternamestage
+}
+
+func (q *Queries) GetCharacterAliases(ctx context.Context, characterID int32) ([]GetCharacterAliasesRow, error) {
+	rows, err := q.query(ctx, q.getCharacterAliasesStmt, getCharacterAliases, characterID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetCharacterAliasesRow
+	for rows.Next() {
+		var i GetCharacterAliasesRow
+		if err := rows.Scan(&i.Name, &i.Stage); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getCharacterProjects = `-- name: GetCharacterProjects :many
+SELECT project.uuid FROM project
+WHERE project.id IN (SELECT project_id FROM project_character WHERE project_character.character_id = $1)
+   OR project.id IN (
+        SELECT project_part.project_id FROM project_part
+        INNER JOIN project_part_character ON project_part_character.part_id = project_part.id
+        WHERE project_part_character.character_id = $1
+   )
+ORDER BY project.date_archived, project.id
+`
+
+func (q *Queries) GetCharacterProjects(ctx context.Context, characterID int32) ([]string, error) {
+	rows, err := q.query(ctx, q.getCharacterProjectsStmt, getCharacterProjects, characterID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var uuid string
+		if err := rows.Scan(&uuid); err != nil {
+			return nil, err
+		}
+		items = append(items, uuid)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getCurrentProjectDescription = `-- name: GetCurrentProjectDescription :one
+SELECT project_description.id, project_description.project_id, project_description.description, project_description.description_md5, project_description.date_added FROM project_description
+INNER JOIN project ON project.id = project_description.project_id
+WHERE project.uuid = $1
+ORDER BY project_description.date_added DESC, project_description.id DESC
+LIMIT 1
+`
+
+func (q *Queries) GetCurrentProjectDescription(ctx context.Context, uuid string) (ProjectDescription, error) {
+	row := q.queryRow(ctx, q.getCurrentProjectDescriptionStmt, getCurrentProjectDescription, uuid)
+	var i ProjectDescription
+	err := row.Scan(
+		&i.ID,
+		&i.ProjectID,
+		&i.Description,
+		&i.DescriptionMd5,
+		&i.DateAdded,
+	)
+	return i, err
+}
+
+const getCurrentProjectTitle = `-- name: GetCurrentProjectTitle :one
+SELECT project_title.id, project_title.project_id, project_title.title, project_title.title_md5, project_title.date_added FROM project_title
+INNER JOIN project ON project.id = project_title.project_id
+WHERE project.uuid = $1
+ORDER BY project_title.date_added DESC, project_title.id DESC
+LIMIT 1
+`
+
+func (q *Queries) GetCurrentProjectTitle(ctx context.Context, uuid string) (ProjectTitle, error) {
+	row := q.queryRow(ctx, q.getCurrentProjectTitleStmt, getCurrentProjectTitle, uuid)
+	var i ProjectTitle
+	err := row.Scan(
+		&i.ID,
+		&i.ProjectID,
+		&i.Title,
+		&i.TitleMd5,
+		&i.DateAdded,
+	)
+	return i, err
+}
+
+const getFileByID = `-- name: GetFileByID :one
+SELECT id, path, extension, md5, sha1, sha256, filesize FROM file WHERE id = $1
+`
+
+func (q *Queries) GetFileByID(ctx context.Context, id int64) (File, error) {
+	row := q.queryRow(ctx, q.getFileByIDStmt, getFileByID, id)
+	var i File
+	err := row.Scan(
+		&i.ID,
+		&i.Path,
+		&i.Extension,
+		&i.Md5,
+		&i.Sha1,
+		&i.Sha256,
+		&i.Filesize,
+	)
+	return i, err
+}
+
+const getFileExistsByPath = `-- name: GetFileExistsByPath :one
+SELECT EXISTS(SELECT 1 FROM file WHERE path = $1)
+`
+
+func (q *Queries) GetFileExistsByPath(ctx context.Context, path string) (bool, error) {
+	row := q.queryRow(ctx, q.getFileExistsByPathStmt, getFileExistsByPath, path)
+	var exists bool
+	err := row.Scan(&exists)
+	return exists, err
+}
+
+const getFileFingerprint = `-- name: GetFileFingerprint :one
+SELECT file_id, frame_interval, hashes, date_added FROM file_fingerprint WHERE file_id = $1
+`
+
+func (q *Queries) GetFileFingerprint(ctx context.Context, fileID int64) (FileFingerprint, error) {
+	row := q.queryRow(ctx, q.getFileFingerprintStmt, getFileFingerprint, fileID)
+	var i FileFingerprint
+	err := row.Scan(
+		&i.FileID,
+		&i.FrameInterval,
+		&i.Hashes,
+		&i.DateAdded,
+	)
+	return i, err
+}
+
+const getFileFingerprintsByDuration = `-- name: GetFileFingerprintsByDuration :many
+SELECT file_id, frame_interval, hashes, date_added FROM file_fingerprint
+WHERE length(hashes) / 8 * frame_interval BETWEEN $1::DOUBLE PRECISION AND $2::DOUBLE PRECISION
+ORDER BY file_id
+`
+
+type GetFileFingerprintsByDurationParams struct {
+	MinDuration float64
+	MaxDuration float64
+}
+
+func (q *Queries) GetFileFingerprintsByDuration(ctx context.Context, arg GetFileFingerprintsByDurationParams) ([]FileFingerprint, error) {
+	rows, err := q.query(ctx, q.getFileFingerprintsByDurationStmt, getFileFingerprintsByDuration, arg.MinDuration, arg.MaxDuration)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []FileFingerprint
+	for rows.Next() {
+		var i FileFingerprint
+		if err := rows.Scan(
+			&i.FileID,
+			&i.FrameInterval,
+			&i.Hashes,
+			&i.DateAdded,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getFileIDBySHA256 = `-- name: GetFileIDBySHA256 :one
+SELECT id FROM file WHERE sha256 = $1
+`
+
+func (q *Queries) GetFileIDBySHA256(ctx context.Context, sha256 []byte) (int64, error) {
+	row := q.queryRow(ctx, q.getFileIDBySHA256Stmt, getFileIDBySHA256, sha256)
+	var id int64
+	err := row.Scan(&id)
+	return id, err
+}
+
+const getFileIntentExistsByPath = `-- name: GetFileIntentExistsByPath :one
+SELECT EXISTS(SELECT 1 FROM file_intent WHERE path = $1)
+`
+
+func (q *Queries) GetFileIntentExistsByPath(ctx context.Context, path string) (bool, error) {
+	row := q.queryRow(ctx, q.getFileIntentExistsByPathStmt, getFileIntentExistsByPath, path)
+	var exists bool
+	err := row.Scan(&exists)
+	return exists, err
+}
+
+const getFileIntents = `-- name: GetFileIntents :many
+SELECT id, action, path, date_added FROM file_intent ORDER BY id
+`
+
+func (q *Queries) GetFileIntents(ctx context.Context) ([]FileIntent, error) {
+	rows, err := q.query(ctx, q.getFileIntentsStmt, getFileIntents)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []FileIntent
+	for rows.Next() {
+		var i FileIntent
+		if err := rows.Scan(
+			&i.ID,
+			&i.Action,
+			&i.Path,
+			&i.DateAdded,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getFileProbe = `-- name: GetFileProbe :one
+SELECT file_id, container, duration, bitrate, date_probed FROM file_probe WHERE file_id = $1
+`
+
+func (q *Queries) GetFileProbe(ctx context.Context, fileID int64) (FileProbe, error) {
+	row := q.queryRow(ctx, q.getFileProbeStmt, getFileProbe, fileID)
+	var i FileProbe
+	err := row.Scan(
+		&i.FileID,
+		&i.Container,
+		&i.Duration,
+		&i.Bitrate,
+		&i.DateProbed,
+	)
+	return i, err
+}
+
+const getFileProbeMismatches = `-- name: GetFileProbeMismatches :many
+SELECT file_id, field, expected, actual, date_added FROM file_probe_mismatch WHERE file_id = $1 ORDER BY field
+`
+
+func (q *Queries) GetFileProbeMismatches(ctx context.Context, fileID int64) ([]FileProbeMismatch, error) {
+	rows, err := q.query(ctx, q.getFileProbeMismatchesStmt, getFileProbeMismatches, fileID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []FileProbeMismatch
+	for rows.Next() {
+		var i FileProbeMismatch
+		if err := rows.Scan(
+			&i.FileID,
+			&i.Field,
+			&i.Expected,
+			&i.Actual,
+			&i.DateAdded,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getFileProbeStreams = `-- name: GetFileProbeStreams :many
+SELECT file_id, stream_index, codec_type, codec, bitrate, width, height, fps, sample_rate, channels FROM file_probe_stream WHERE file_id = $1 ORDER BY stream_index
+`
+
+func (q *Queries) GetFileProbeStreams(ctx context.Context, fileID int64) ([]FileProbeStream, error) {
+	rows, err := q.query(ctx, q.getFileProbeStreamsStmt, getFileProbeStreams, fileID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []FileProbeStream
+	for rows.Next() {
+		var i FileProbeStream
+		if err := rows.Scan(
+			&i.FileID,
+			&i.StreamIndex,
+			&i.CodecType,
+			&i.Codec,
+			&i.Bitrate,
+			&i.Width,
+			&i.Height,
+			&i.Fps,
+			&i.SampleRate,
+			&i.Channels,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getFileProjects = `-- name: GetFileProjects :many
+SELECT project.uuid FROM project
+WHERE project.id IN (
+	SELECT project_file.project_id FROM project_file WHERE project_file.file_id = $1
+		UNION
+	SELECT project_part.project_id FROM project_part WHERE project_part.file_id = $1
+)
+ORDER BY project.uuid
+`
+
+func (q *Queries) GetFileProjects(ctx context.Context, fileID int64) ([]string, error) {
+	rows, err := q.query(ctx, q.getFileProjectsStmt, getFileProjects, fileID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var uuid string
+		if err := rows.Scan(&uuid); err != nil {
+			return nil, err
+		}
+		items = append(items, uuid)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getFileVideo = `-- name: GetFileVideo :one
+SELECT file_id, duration, width, height, fps, video_codec, audio_codec FROM file_video WHERE file_id = $1
+`
+
+func (q *Queries) GetFileVideo(ctx context.Context, fileID int64) (FileVideo, error) {
+	row := q.queryRow(ctx, q.getFileVideoStmt, getFileVideo, fileID)
+	var i FileVideo
+	err := row.Scan(
+		&i.FileID,
+		&i.Duration,
+		&i.Width,
+		&i.Height,
+		&i.Fps,
+		&i.VideoCodec,
+		&i.AudioCodec,
+	)
+	return i, err
+}
+
+const getFileYoutubeID = `-- name: GetFileYoutubeID :one
+SELECT youtube_id FROM youtube_file WHERE file_id = $1
+`
+
+func (q *Queries) GetFileYoutubeID(ctx context.Context, fileID int64) (interface{}, error) {
+	row := q.queryRow(ctx, q.getFileYoutubeIDStmt, getFileYoutubeID, fileID)
+	var youtube_id interface{}
+	err := row.Scan(&youtube_id)
+	return youtube_id, err
+}
+
+const getFilesAfter = `-- name: GetFilesAfter :many
+SELECT id, path, extension, md5, sha1, sha256, filesize FROM file WHERE id > $1 ORDER BY id
+`
+
+func (q *Queries) GetFilesAfter(ctx context.Context, id int64) ([]File, error) {
+	rows, err := q.query(ctx, q.getFilesAfterStmt, getFilesAfter, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []File
+	for rows.Next() {
+		var i File
+		if err := rows.Scan(
+			&i.ID,
+			&i.Path,
+			&i.Extension,
+			&i.Md5,
+			&i.Sha1,
+			&i.Sha256,
+			&i.Filesize,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getFilesWithoutVideo = `-- name: GetFilesWithoutVideo :many
+SELECT id FROM file
+WHERE id NOT IN (SELECT file_id FROM file_video)
+ORDER BY id
+`
+
+func (q *Queries) GetFilesWithoutVideo(ctx context.Context) ([]int64, error) {
+	rows, err := q.query(ctx, q.getFilesWithoutVideoStmt, getFilesWithoutVideo)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getMisattributedDownloads = `-- name: GetMisattributedDownloads :many
+SELECT download.source, download.file_id, download.youtube_id::text AS youtube_id, youtube_file.youtube_id::text AS assigned_youtube_id FROM (
+	SELECT 'youtube_video_format'::text AS source, file_id, youtube_id FROM youtube_video_format
+	UNION ALL SELECT 'youtube_video_ytdlp_info'::text, file_id, youtube_id FROM youtube_video_ytdlp_info
+	UNION ALL SELECT 'youtube_video_ytdlp_version'::text, file_id, youtube_id FROM youtube_video_ytdlp_version
+	UNION ALL SELECT 'youtube_video_thumbnail'::text, file_id, youtube_id FROM youtube_video_thumbnail
+) AS download
+INNER JOIN youtube_file ON youtube_file.file_id = download.file_id
+WHERE youtube_file.youtube_id != download.youtube_id
+ORDER BY download.file_id, download.source
+`
+
+type GetMisattributedDownloadsRow struct {
+	Source            string
+	FileID            int64
+	YoutubeID         string
+	AssignedYoutubeID string
+}
+
+func (q *Queries) GetMisattributedDownloads(ctx context.Context) ([]GetMisattributedDownloadsRow, error) {
+	rows, err := q.query(ctx, q.getMisattributedDownloadsStmt, getMisattributedDownloads)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetMisattributedDownloadsRow
+	for rows.Next() {
+		var i GetMisattributedDownloadsRow
+		if err := rows.Scan(
+			&i.Source,
+			&i.FileID,
+			&i.YoutubeID,
+			&i.AssignedYoutubeID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getMusic = `-- name: GetMusic :one
+SELECT id, artist, title FROM music WHERE id = $1
+`
+
+func (q *Queries) GetMusic(ctx context.Context, id int32) (Music, error) {
+	row := q.queryRow(ctx, q.getMusicStmt, getMusic, id)
+	var i Music
+	err := row.Scan(&i.ID, &i.Artist, &i.Title)
+	return i, err
+}
+
+const getMusicProjects = `-- name: GetMusicProjects :many
+SELECT project.uuid FROM project
+INNER JOIN project_music ON project_music.project_id = project.id
+WHERE project_music.music_id = $1
+ORDER BY project.date_archived, project.id
+`
+
+func (q *Queries) GetMusicProjects(ctx context.Context, musicID int32) ([]string, error) {
+	rows, err := q.query(ctx, q.getMusicProjectsStmt, getMusicProjects, musicID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var uuid string
+		if err := rows.Scan(&uuid); err != nil {
+			return nil, err
+		}
+		items = append(items, uuid)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getOrphanFiles = `-- name: GetOrphanFiles :many
+SELECT file.id, file.path, file.extension, file.md5, file.sha1, file.sha256, file.filesize FROM file 
+WHERE file.id NOT IN (
+	SELECT youtube_file.file_id FROM youtube_file
+		UNION 
+	SELECT project_file.file_id FROM project_file
+)
+`
+
+func (q *Queries) GetOrphanFiles(ctx context.Context) ([]File, error) {
+	rows, err := q.query(ctx, q.getOrphanFilesStmt, getOrphanFiles)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []File
+	for rows.Next() {
+		var i File
+		if err := rows.Scan(
+			&i.ID,
+			&i.Path,
+			&i.Extension,
+			&i.Md5,
+			&i.Sha1,
+			&i.Sha256,
+			&i.Filesize,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getPartCharacters = `-- name: GetPartCharacters :many
+SELECT character.id, character.name, character.series, character.is_original FROM character
+INNER JOIN project_part_character ON project_part_character.character_id = character.id
+WHERE project_part_character.part_id = $1
+ORDER BY character.name, character.id
+`
+
+func (q *Queries) GetPartCharacters(ctx context.Context, partID int64) ([]Character, error) {
+	rows, err := q.query(ctx, q.getPartCharactersStmt, getPartCharacters, partID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Character
+	for rows.Next() {
+		var i Character
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Series,
+			&i.IsOriginal,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getProjectByUUID = `-- name: GetProjectByUUID :one
+SELECT id, uuid, type, date_announced, date_announced_precision, date_announced_approximate, date_completed, date_completed_precision, date_completed_approximate, date_archived FROM project WHERE uuid = $1
+`
+
+func (q *Queries) GetProjectByUUID(ctx context.Context, uuid string) (Project, error) {
+	row := q.queryRow(ctx, q.getProjectByUUIDStmt, getProjectByUUID, uuid)
+	var i Project
+	err := row.Scan(
+		&i.ID,
+		&i.Uuid,
+		&i.Type,
+		&i.DateAnnounced,
+		&i.DateAnnouncedPrecision,
+		&i.DateAnnouncedApproximate,
+		&i.DateCompleted,
+		&i.DateCompletedPrecision,
+		&i.DateCompletedApproximate,
+		&i.DateArchived,
+	)
+	return i, err
+}
+
+const getProjectByYoutubeID = `-- name: GetProjectByYoutubeID :one
+SELECT project.id, project.uuid, project.type, project.date_announced, project.date_announced_precision, project.date_announced_approximate, project.date_completed, project.date_completed_precision, project.date_completed_approximate, project.date_archived FROM project 
+INNER JOIN project_file ON project.id = project_file.project_id
+INNER JOIN youtube_file ON project_file.file_id = youtube_file.file_id
+WHERE youtube_file.youtube_id = $1
+`
+
+func (q *Queries) GetProjectByYoutubeID(ctx context.Context, youtubeID interface{}) (Project, error) {
+	row := q.queryRow(ctx, q.getProjectByYoutubeIDStmt, getProjectByYoutubeID, youtubeID)
+	var i Project
+	err := row.Scan(
+		&i.ID,
+		&i.Uuid,
+		&i.Type,
+		&i.DateAnnounced,
+		&i.DateAnnouncedPrecision,
+		&i.DateAnnouncedApproximate,
+		&i.DateCompleted,
+		&i.DateCompletedPrecision,
+		&i.DateCompletedApproximate,
+		&i.DateArchived,
+	)
+	return i, err
+}
+
+const getProjectCharacters = `-- name: GetProjectCharacters :many
+SELECT character.id, character.name, character.series, character.is_original FROM character
+INNER JOIN project_character ON project_character.character_id = character.id
+WHERE project_character.project_id = (SELECT id FROM project WHERE uuid = $1)
+ORDER BY character.name, character.id
+`
+
+func (q *Queries) GetProjectCharacters(ctx context.Context, uuid string) ([]Character, error) {
+	rows, err := q.query(ctx, q.getProjectCharactersStmt, getProjectCharacters, uuid)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Character
+	for rows.Next() {
+		var i Character
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Series,
+			&i.IsOriginal,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getProjectDescriptions = `-- name: GetProjectDescriptions :many
+SELECT project_description.id, project_description.project_id, project_description.description, project_description.description_md5, project_description.date_added FROM project_description
+INNER JOIN project ON project.id = project_description.project_id
+WHERE project.uuid = $1
+ORDER BY project_description.date_added DESC, project_description.id DESC
+`
+
+func (q *Queries) GetProjectDescriptions(ctx context.Context, uuid string) ([]ProjectDescription, error) {
+	rows, err := q.query(ctx, q.getProjectDescriptionsStmt, getProjectDescriptions, uuid)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ProjectDescription
+	for rows.Next() {
+		var i ProjectDescription
+		if err := rows.Scan(
+			&i.ID,
+			&i.ProjectID,
+			&i.Description,
+			&i.DescriptionMd5,
+			&i.DateAdded,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getProjectFile = `-- name: GetProjectFile :many
+SELECT file_id FROM project_file WHERE project_id = (SELECT id FROM project WHERE uuid = $1)
+`
+
+func (q *Queries) GetProjectFile(ctx context.Context, uuid string) ([]int64, error) {
+	rows, err := q.query(ctx, q.getProjectFileStmt, getProjectFile, uuid)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []int64
+	for rows.Next() {
+		var file_id int64
+		if err := rows.Scan(&file_id); err != nil {
+			return nil, err
+		}
+		items = append(items, file_id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getProjectMusic = `-- name: GetProjectMusic :many
+SELECT music.id, music.artist, music.title FROM music
+INNER JOIN project_music ON project_music.music_id = music.id
+WHERE project_music.project_id = (SELECT id FROM project WHERE uuid = $1)
+ORDER BY music.artist, music.title
+`
+
+func (q *Queries) GetProjectMusic(ctx context.Context, uuid string) ([]Music, error) {
+	rows, err := q.query(ctx, q.getProjectMusicStmt, getProjectMusic, uuid)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Music
+	for rows.Next() {
+		var i Music
+		if err := rows.Scan(&i.ID, &i.Artist, &i.Title); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getProjectPart = `-- name: GetProjectPart :one
+SELECT project.uuid, project_part.id, project_part.project_id, project_part.part_number, project_part.start_ms, project_part.end_ms, project_part.status, project_part.participant_channel_id, project_part.participant_name, project_part.file_id, project_part.youtube_id, project_part.participant_artist_id, artist.name::text AS participant_artist FROM project_part
+INNER JOIN project ON project.id = project_part.project_id
+LEFT JOIN artist ON artist.id = project_part.participant_artist_id
+WHERE project_part.id = $1
+`
+
+type GetProjectPartRow struct {
+	Uuid                 string
+	ID                   int64
+	ProjectID            int64
+	PartNumber           int16
+	StartMs              sql.NullInt32
+	EndMs                sql.NullInt32
+	Status               Partstatus
+	ParticipantChannelID sql.NullString
+	ParticipantName      sql.NullString
+	FileID               sql.NullInt64
+	YoutubeID            sql.NullString
+	ParticipantArtistID  sql.NullInt64
+	ParticipantArtist    sql.NullString
+}
+
+func (q *Queries) GetProjectPart(ctx context.Context, id int64) (GetProjectPartRow, error) {
+	row := q.queryRow(ctx, q.getProjectPartStmt, getProjectPart, id)
+	var i GetProjectPartRow
+	err := row.Scan(
+		&i.Uuid,
+		&i.ID,
+		&i.ProjectID,
+		&i.PartNumber,
+		&i.StartMs,
+		&i.EndMs,
+		&i.Status,
+		&i.ParticipantChannelID,
+		&i.ParticipantName,
+		&i.FileID,
+		&i.YoutubeID,
+		&i.ParticipantArtistID,
+		&i.ParticipantArtist,
+	)
+	return i, err
+}
+
+const getProjectParts = `-- name: GetProjectParts :many
+SELECT project.uuid, project_part.id, project_part.project_id, project_part.part_number, project_part.start_ms, project_part.end_ms, project_part.status, project_part.participant_channel_id, project_part.participant_name, project_part.file_id, project_part.youtube_id, project_part.participant_artist_id, artist.name::text AS participant_artist FROM project_part
+INNER JOIN project ON project.id = project_part.project_id
+LEFT JOIN artist ON artist.id = project_part.participant_artist_id
+WHERE project.uuid = $1
+ORDER BY project_part.part_number
+`
+
+type GetProjectPartsRow struct {
+	Uuid                 string
+	ID                   int64
+	ProjectID            int64
+	PartNumber           int16
+	StartMs              sql.NullInt32
+	EndMs                sql.NullInt32
+	Status               Partstatus
+	ParticipantChannelID sql.NullString
+	ParticipantName      sql.NullString
+	FileID               sql.NullInt64
+	YoutubeID            sql.NullString
+	ParticipantArtistID  sql.NullInt64
+	ParticipantArtist    sql.NullString
+}
+
+func (q *Queries) GetProjectParts(ctx context.Context, uuid string) ([]GetProjectPartsRow, error) {
+	rows, err := q.query(ctx, q.getProjectPartsStmt, getProjectParts, uuid)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetProjectPartsRow
+	for rows.Next() {
+		var i GetProjectPartsRow
+		if err := rows.Scan(
+			&i.Uuid,
+			&i.ID,
+			&i.ProjectID,
+			&i.PartNumber,
+			&i.StartMs,
+			&i.EndMs,
+			&i.Status,
+			&i.ParticipantChannelID,
+			&i.ParticipantName,
+			&i.FileID,
+			&i.YoutubeID,
+			&i.ParticipantArtistID,
+			&i.ParticipantArtist,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getProjectRelationAncestors = `-- name: GetProjectRelationAncestors :many
+WITH RECURSIVE up (project_id, related_project_id, depth) AS (
+    SELECT project_relation.project_id, project_relation.related_project_id, 1 FROM project_relation
+    WHERE project_relation.project_id = (SELECT id FROM project WHERE uuid = $1) AND project_relation.type = $2
+  UNION ALL
+    SELECT project_relation.project_id, project_relation.related_project_id, up.depth + 1 FROM project_relation
+    INNER JOIN up ON project_relation.project_id = up.related_project_id
+    WHERE project_relation.type = $2 AND up.depth < 64
+)
+SELECT project.uuid AS project_uuid, related.uuid AS related_uuid, up.depth FROM up
+INNER JOIN project ON project.id = up.project_id
+INNER JOIN project AS related ON related.id = up.related_project_id
+ORDER BY up.depth, related.uuid
+`
+
+type GetProjectRelationAncestorsParams struct {
+	Uuid string
+	Type Projectrelationtype
+}
+
+type GetProjectRelationAncestorsRow struct {
+	ProjectUuid string
+	RelatedUuid string
+	Depth       int32
+}
+
+func (q *Queries) GetProjectRelationAncestors(ctx context.Context, arg GetProjectRelationAncestorsParams) ([]GetProjectRelationAncestorsRow, error) {
+	rows, err := q.query(ctx, q.getProjectRelationAncestorsStmt, getProjectRelationAncestors, arg.Uuid, arg.Type)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetProjectRelationAncestorsRow
+	for rows.Next() {
+		var i GetProjectRelationAncestorsRow
+		if err := rows.Scan(&i.ProjectUuid, &i.RelatedUuid, &i.Depth); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getProjectRelationDescendants = `-- name: GetProjectRelationDescendants :many
+WITH RECURSIVE down (project_id, related_project_id, depth) AS (
+    SELECT project_relation.project_id, project_relation.related_project_id, 1 FROM project_relation
+    WHERE project_relation.related_project_id = (SELECT id FROM project WHERE uuid = $1) AND project_relation.type = $2
+  UNION ALL
+    SELECT project_relation.project_id, project_relation.related_project_id, down.depth + 1 FROM project_relation
+    INNER JOIN down ON project_relation.related_project_id = down.project_id
+    WHERE project_relation.type = $2 AND down.depth < 64
+)
+SELECT project.uuid AS project_uuid, related.uuid AS related_uuid, down.depth FROM down
+INNER JOIN project ON project.id = down.project_id
+INNER JOIN project AS related ON related.id = down.related_project_id
+ORDER BY down.depth, project.uuid
+`
+
+type GetProjectRelationDescendantsParams struct {
+	Uuid string
+	Type Projectrelationtype
+}
+
+type GetProjectRelationDescendantsRow struct {
+	ProjectUuid string
+	RelatedUuid string
+	Depth       int32
+}
+
+func (q *Queries) GetProjectRelationDescendants(ctx context.Context, arg GetProjectRelationDescendantsParams) ([]GetProjectRelationDescendantsRow, error) {
+	rows, err := q.query(ctx, q.getProjectRelationDescendantsStmt, getProjectRelationDescendants, arg.Uuid, arg.Type)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetProjectRelationDescendantsRow
+	for rows.Next() {
+		var i GetProjectRelationDescendantsRow
+		if err := rows.Scan(&i.ProjectUuid, &i.RelatedUuid, &i.Depth); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getProjectRelations = `-- name: GetProjectRelations :many
+SELECT project.uuid AS project_uuid, related.uuid AS related_uuid, project_relation.type FROM project_relation
+INNER JOIN project ON project.id = project_relation.project_id
+INNER JOIN project AS related ON related.id = project_relation.related_project_id
+WHERE project.uuid = $1 OR related.uuid = $1
+ORDER BY project_relation.type, project_relation.date_added
+`
+
+type GetProjectRelationsRow struct {
+	ProjectUuid string
+	RelatedUuid string
+	Type        Projectrelationtype
+}
+
+func (q *Queries) GetProjectRelations(ctx context.Context, uuid string) ([]GetProjectRelationsRow, error) {
+	rows, err := q.query(ctx, q.getProjectRelationsStmt, getProjectRelations, uuid)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetProjectRelationsRow
+	for rows.Next() {
+		var i GetProjectRelationsRow
+		if err := rows.Scan(&i.ProjectUuid, &i.RelatedUuid, &i.Type); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getProjectStatuses = `-- name: GetProjectStatuses :many
+SELECT project_status.id, project_status.status, project_status.date, project_status.note, project_status.date_added FROM project_status
+INNER JOIN project ON project.id = project_status.project_id
+WHERE project.uuid = $1
+ORDER BY project_status.date, project_status.id
+`
+
+type GetProjectStatusesRow struct {
+	ID        int64
+	Status    Projectstatus
+	Date      time.Time
+	Note      string
+	DateAdded time.Time
+}
+
+func (q *Queries) GetProjectStatuses(ctx context.Context, uuid string) ([]GetProjectStatusesRow, error) {
+	rows, err := q.query(ctx, q.getProjectStatusesStmt, getProjectStatuses, uuid)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetProjectStatusesRow
+	for rows.Next() {
+		var i GetProjectStatusesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Status,
+			&i.Date,
+			&i.Note,
+			&i.DateAdded,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getProjectTitles = `-- name: GetProjectTitles :many
+SELECT project_title.id, project_title.project_id, project_title.title, project_title.title_md5, project_title.date_added FROM project_title
+INNER JOIN project ON project.id = project_title.project_id
+WHERE project.uuid = $1
+ORDER BY project_title.date_added DESC, project_title.id DESC
+`
+
+func (q *Queries) GetProjectTitles(ctx context.Context, uuid string) ([]ProjectTitle, error) {
+	rows, err := q.query(ctx, q.getProjectTitlesStmt, getProjectTitles, uuid)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ProjectTitle
+	for rows.Next() {
+		var i ProjectTitle
+		if err := rows.Scan(
+			&i.ID,
+			&i.ProjectID,
+			&i.Title,
+			&i.TitleMd5,
+			&i.DateAdded,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getProjectTypeByYoutubeID = `-- name: GetProjectTypeByYoutubeID :one
+SELECT project.type FROM project 
+INNER JOIN project_file ON project.id = project_file.project_id
+INNER JOIN youtube_file ON project_file.file_id = youtube_file.file_id
+WHERE youtube_file.youtube_id = $1
+`
+
+func (q *Queries) GetProjectTypeByYoutubeID(ctx context.Context, youtubeID interface{}) (Projecttype, error) {
+	row := q.queryRow(ctx, q.getProjectTypeByYoutubeIDStmt, getProjectTypeByYoutubeID, youtubeID)
+	var type_ Projecttype
+	err := row.Scan(&type_)
+	return type_, err
+}
+
+const getProjectYoutubeIDs = `-- name: GetProjectYoutubeIDs :many
+SELECT DISTINCT youtube_file.youtube_id FROM project_file
+INNER JOIN youtube_file ON youtube_file.file_id = project_file.file_id
+WHERE project_file.project_id = (SELECT id FROM project WHERE uuid = $1)
+ORDER BY youtube_file.youtube_id
+`
+
+func (q *Queries) GetProjectYoutubeIDs(ctx context.Context, uuid string) ([]interface{}, error) {
+	rows, err := q.query(ctx, q.getProjectYoutubeIDsStmt, getProjectYoutubeIDs, uuid)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []interface{}
+	for rows.Next() {
+		var youtube_id interface{}
+		if err := rows.Scan(&youtube_id); err != nil {
+			return nil, err
+		}
+		items = append(items, youtube_id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getProjectsWithoutFiles = `-- name: GetProjectsWithoutFiles :many
+SELECT uuid FROM project
+WHERE id NOT IN (SELECT project_id FROM project_file)
+AND id NOT IN (SELECT project_id FROM project_part WHERE file_id IS NOT NULL)
+ORDER BY uuid
+`
+
+func (q *Queries) GetProjectsWithoutFiles(ctx context.Context) ([]string, error) {
+	rows, err := q.query(ctx, q.getProjectsWithoutFilesStmt, getProjectsWithoutFiles)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var uuid string
+		if err := rows.Scan(&uuid); err != nil {
+			return nil, err
+		}
+		items = append(items, uuid)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getSeriesCharacters = `-- name: GetSeriesCharacters :many
+SELECT id, name, series, is_original FROM character WHERE series = $1 ORDER BY name, id
+`
+
+func (q *Queries) GetSeriesCharacters(ctx context.Context, series string) ([]Character, error) {
+	rows, err := q.query(ctx, q.getSeriesCharactersStmt, getSeriesCharacters, series)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Character
+	for rows.Next() {
+		var i Character
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Series,
+			&i.IsOriginal,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getUnassignedDownloads = `-- name: GetUnassignedDownloads :many
+SELECT download.file_id, download.youtube_id::text AS youtube_id FROM (
+	SELECT file_id, youtube_id FROM youtube_video_format
+	UNION SELECT file_id, youtube_id FROM youtube_video_ytdlp_info
+	UNION SELECT file_id, youtube_id FROM youtube_video_ytdlp_version
+	UNION SELECT file_id, youtube_id FROM youtube_video_thumbnail
+) AS download
+WHERE download.file_id NOT IN (SELECT file_id FROM youtube_file)
+ORDER BY download.file_id, download.youtube_id
+`
+
+type GetUnassignedDownloadsRow struct {
+	FileID    int64
+	YoutubeID string
+}
+
+func (q *Queries) GetUnassignedDownloads(ctx context.Context) ([]GetUnassignedDownloadsRow, error) {
+	rows, err := q.query(ctx, q.getUnassignedDownloadsStmt, getUnassignedDownloads)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetUnassignedDownloadsRow
+	for rows.Next() {
+		var i GetUnassignedDownloadsRow
+		if err := rows.Scan(&i.FileID, &i.YoutubeID); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getUnfingerprintedFileIDs = `-- name: GetUnfingerprintedFileIDs :many
+SELECT file_id FROM file_video WHERE file_id NOT IN (SELECT file_id FROM file_fingerprint) ORDER BY file_id
+`
+
+func (q *Queries) GetUnfingerprintedFileIDs(ctx context.Context) ([]int64, error) {
+	rows, err := q.query(ctx, q.getUnfingerprintedFileIDsStmt, getUnfingerprintedFileIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []int64
+	for rows.Next() {
+		var file_id int64
+		if err := rows.Scan(&file_id); err != nil {
+			return nil, err
+		}
+		items = append(items, file_id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getUnprobedFileIDs = `-- name: GetUnprobedFileIDs :many
+SELECT id FROM file WHERE id NOT IN (SELECT file_id FROM file_probe) ORDER BY id
+`
+
+func (q *Queries) GetUnprobedFileIDs(ctx context.Context) ([]int64, error) {
+	rows, err := q.query(ctx, q.getUnprobedFileIDsStmt, getUnprobedFileIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getYoutubeChannelByID = `-- name: GetYoutubeChannelByID :one
+SELECT 
+    youtube_channel_youtube_video.channel_id AS channel_id, 
+    youtube_channel_uploader_id.uploader_id AS uploader_id,
+    youtube_channel_uploader_name.uploader AS uploader_name 
+FROM youtube_channel_youtube_video
+    INNER JOIN youtube_channel_uploader_id ON youtube_channel_uploader_id.channel_id = youtube_channel_youtube_video.channel_id
+    INNER JOIN youtube_channel_uploader_name ON youtube_channel_uploader_name.channel_id = youtube_channel_youtube_video.channel_id
+WHERE youtube_channel_youtube_video.youtube_id = $1 LIMIT 1
+`
+
+type GetYoutubeChannelByIDRow struct {
+	ChannelID    interface{}
+	UploaderID   string
+	UploaderName string
+}
+
+func (q *Queries) GetYoutubeChannelByID(ctx context.Context, youtubeID entities.YoutubeVideoID) (GetYoutubeChannelByIDRow, error) {
+	row := q.queryRow(ctx, q.getYoutubeChannelByIDStmt, getYoutubeChannelByID, youtubeID)
+	var i GetYoutubeChannelByIDRow
+	err := row.Scan(&i.ChannelID, &i.UploaderID, &i.UploaderName)
+	return i, err
+}
+
+const getYoutubeChannelVideos = `-- name: GetYoutubeChannelVideos :many
+SELECT youtube_id FROM youtube_channel_youtube_video WHERE channel_id = $1
+`
+
+func (q *Queries) GetYoutubeChannelVideos(ctx context.Context, channelID interface{}) ([]entities.YoutubeVideoID, error) {
+	rows, err := q.query(ctx, q.getYoutubeChannelVideosStmt, getYoutubeChannelVideos, channelID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []entities.YoutubeVideoID
+	for rows.Next() {
+		var youtube_id entities.YoutubeVideoID
+		if err := rows.Scan(&youtube_id); err != nil {
+			return nil, err
+		}
+		items = append(items, youtube_id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getYoutubeDescription = `-- name: GetYoutubeDescription :many
+SELECT description FROM youtube_description WHERE youtube_id = $1
+`
+
+func (q *Queries) GetYoutubeDescription(ctx context.Context, youtubeID interface{}) ([]string, error) {
+	rows, err := q.query(ctx, q.getYoutubeDescriptionStmt, getYoutubeDescription, youtubeID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var description string
+		if err := rows.Scan(&description); err != nil {
+			return nil, err
+		}
+		items = append(items, description)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getYoutubeFileID = `-- name: GetYoutubeFileID :many
+SELECT file_id FROM youtube_file WHERE youtube_id = $1
+`
+
+func (q *Queries) GetYoutubeFileID(ctx context.Context, youtubeID interface{}) ([]int64, error) {
+	rows, err := q.query(ctx, q.getYoutubeFileIDStmt, getYoutubeFileID, youtubeID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []int64
+	for rows.Next() {
+		var file_id int64
+		if err := rows.Scan(&file_id); err != nil {
+			return nil, err
+		}
+		items = append(items, file_id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getYoutubeThumbnail = `-- name: GetYoutubeThumbnail :one
+SELECT thumbnail FROM youtube_video_thumbnail WHERE file_id = $1
+`
+
+func (q *Queries) GetYoutubeThumbnail(ctx context.Context, fileID int64) ([]byte, error) {
+	row := q.queryRow(ctx, q.getYoutubeThumbnailStmt, getYoutubeThumbnail, fileID)
+	var thumbnail []byte
+	err := row.Scan(&thumbnail)
+	return thumbnail, err
+}
+
+const getYoutubeTitle = `-- name: GetYoutubeTitle :many
+SELECT title FROM youtube_title WHERE youtube_id = $1
+`
+
+func (q *Queries) GetYoutubeTitle(ctx context.Context, youtubeID interface{}) ([]string, error) {
+	rows, err := q.query(ctx, q.getYoutubeTitleStmt, getYoutubeTitle, youtubeID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
 		var title string
 		if err := rows.Scan(&title); err != nil {
 			return nil, err
@@ -446,6 +2216,46 @@ func (q *Queries) GetYoutubeVideoFormatByYoutubeID(ctx context.Context, youtubeI
 	return items, nil
 }
 
+const getYoutubeWithoutFiles = `-- name: GetYoutubeWithoutFiles :many
+SELECT id::text AS youtube_id FROM youtube_video
+WHERE id NOT IN (SELECT youtube_id FROM youtube_file)
+ORDER BY id
+`
+
+func (q *Queries) GetYoutubeWithoutFiles(ctx context.Context) ([]string, error) {
+	rows, err := q.query(ctx, q.getYoutubeWithoutFilesStmt, getYoutubeWithoutFiles)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var youtube_id string
+		if err := rows.Scan(&youtube_id); err != nil {
+			return nil, err
+		}
+		items = append(items, youtube_id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getYoutubeYtdlpInfo = `-- name: GetYoutubeYtdlpInfo :one
+SELECT info FROM youtube_video_ytdlp_info WHERE file_id = $1
+`
+
+func (q *Queries) GetYoutubeYtdlpInfo(ctx context.Context, fileID int64) (json.RawMessage, error) {
+	row := q.queryRow(ctx, q.getYoutubeYtdlpInfoStmt, getYoutubeYtdlpInfo, fileID)
+	var info json.RawMessage
+	err := row.Scan(&info)
+	return info, err
+}
+
 const getYoutubeYtdlpVersion = `-- name: GetYoutubeYtdlpVersion :one
 SELECT file_id, youtube_id, repository, release_git_head, version FROM youtube_video_ytdlp_version WHERE youtube_id = $1 AND file_id = $2
 `
@@ -468,6 +2278,163 @@ func (q *Queries) GetYoutubeYtdlpVersion(ctx context.Context, arg GetYoutubeYtdl
 	return i, err
 }
 
+const getYtdlpClaimedVideo = `-- name: GetYtdlpClaimedVideo :one
+SELECT
+    COALESCE(ROUND((info->>'duration')::numeric), 0)::integer AS duration,
+    COALESCE(ROUND((info->>'width')::numeric), 0)::smallint AS width,
+    COALESCE(ROUND((info->>'height')::numeric), 0)::smallint AS height,
+    COALESCE(TRUNC((info->>'fps')::numeric), 0)::smallint AS fps,
+    COALESCE(info->>'vcodec', '')::text AS video_codec,
+    COALESCE(info->>'acodec', '')::text AS audio_codec
+FROM youtube_video_ytdlp_info WHERE file_id = $1
+`
+
+type GetYtdlpClaimedVideoRow struct {
+	Duration   int32
+	Width      int16
+	Height     int16
+	Fps        int16
+	VideoCodec string
+	AudioCodec string
+}
+
+func (q *Queries) GetYtdlpClaimedVideo(ctx context.Context, fileID int64) (GetYtdlpClaimedVideoRow, error) {
+	row := q.queryRow(ctx, q.getYtdlpClaimedVideoStmt, getYtdlpClaimedVideo, fileID)
+	var i GetYtdlpClaimedVideoRow
+	err := row.Scan(
+		&i.Duration,
+		&i.Width,
+		&i.Height,
+		&i.Fps,
+		&i.VideoCodec,
+		&i.AudioCodec,
+	)
+	return i, err
+}
+
+const lockFiles = `-- name: LockFiles :exec
+LOCK TABLE file IN SHARE MODE
+`
+
+func (q *Queries) LockFiles(ctx context.Context) error {
+	_, err := q.exec(ctx, q.lockFilesStmt, lockFiles)
+	return err
+}
+
+const lockProject = `-- name: LockProject :one
+SELECT id FROM project WHERE uuid = $1 FOR UPDATE
+`
+
+func (q *Queries) LockProject(ctx context.Context, uuid string) (int64, error) {
+	row := q.queryRow(ctx, q.lockProjectStmt, lockProject, uuid)
+	var id int64
+	err := row.Scan(&id)
+	return id, err
+}
+
+const lockProjectRelations = `-- name: LockProjectRelations :exec
+LOCK TABLE project_relation IN SHARE ROW EXCLUSIVE MODE
+`
+
+func (q *Queries) LockProjectRelations(ctx context.Context) error {
+	_, err := q.exec(ctx, q.lockProjectRelationsStmt, lockProjectRelations)
+	return err
+}
+
+const lockStorageShared = `-- name: LockStorageShared :exec
+SELECT pg_advisory_lock_shared(hashtext('storage_root'))
+`
+
+func (q *Queries) LockStorageShared(ctx context.Context) error {
+	_, err := q.exec(ctx, q.lockStorageSharedStmt, lockStorageShared)
+	return err
+}
+
+const newArtist = `-- name: NewArtist :one
+INSERT INTO artist (name) VALUES ($1) RETURNING id
+`
+
+func (q *Queries) NewArtist(ctx context.Context, name string) (int64, error) {
+	row := q.queryRow(ctx, q.newArtistStmt, newArtist, name)
+	var id int64
+	err := row.Scan(&id)
+	return id, err
+}
+
+const newArtistAlias = `-- name: NewArtistAlias :exec
+INSERT INTO artist_alias (artist_id, alias) VALUES ($1, $2) ON CONFLICT (artist_id, alias) DO NOTHING
+`
+
+type NewArtistAliasParams struct {
+	ArtistID int64
+	Alias    string
+}
+
+func (q *Queries) NewArtistAlias(ctx context.Context, arg NewArtistAliasParams) error {
+	_, err := q.exec(ctx, q.newArtistAliasStmt, newArtistAlias, arg.ArtistID, arg.Alias)
+	return err
+}
+
+const newAuditEntry = `-- name: NewAuditEntry :one
+INSERT INTO audit_log (actor, entity, entity_id, action, before, after) VALUES ($1, $2, $3, $4, $5, $6) RETURNING id
+`
+
+type NewAuditEntryParams struct {
+	Actor    string
+	Entity   Auditentity
+	EntityID string
+	Action   string
+	Before   json.RawMessage
+	After    json.RawMessage
+}
+
+func (q *Queries) NewAuditEntry(ctx context.Context, arg NewAuditEntryParams) (int64, error) {
+	row := q.queryRow(ctx, q.newAuditEntryStmt, newAuditEntry,
+		arg.Actor,
+		arg.Entity,
+		arg.EntityID,
+		arg.Action,
+		arg.Before,
+		arg.After,
+	)
+	var id int64
+	err := row.Scan(&id)
+	return id, err
+}
+
+const newCharacter = `-- name: NewCharacter :one
+INSERT INTO character (name, series, is_original) VALUES ($1, $2, $3) RETURNING id
+`
+
+type NewCharacterParams struct {
+	Name       string
+	Series     string
+	IsOriginal bool
+}
+
+func (q *Queries) NewCharacter(ctx context.Context, arg NewCharacterParams) (int32, error) {
+	row := q.queryRow(ctx, q.newCharacterStmt, newCharacter, arg.Name, arg.Series, arg.IsOriginal)
+	var id int32
+	err := row.Scan(&id)
+	return id, err
+}
+
+const newCharacterAlias = `-- name: NewCharacterAlias :exec
+INSERT INTO character_alias (character_id, name, stage) VALUES ($1, $2, $3)
+ON CONFLICT (character_id, name) DO UPDATE SET stage = EXCLUDED.stage
+`
+
+type NewCharacterAliasParams struct {
+	CharacterID int32
+	Name        string
+	Stage       Characternamestage
+}
+
+func (q *Queries) NewCharacterAlias(ctx context.Context, arg NewCharacterAliasParams) error {
+	_, err := q.exec(ctx, q.newCharacterAliasStmt, newCharacterAlias, arg.CharacterID, arg.Name, arg.Stage)
+	return err
+}
+
 const newFile = `-- name: NewFile :one
 INSERT INTO file (path, extension, md5, sha1, sha256, filesize) VALUES ($1, $2, $3, $4, $5, $6) RETURNING id
 `
@@ -495,6 +2462,77 @@ func (q *Queries) NewFile(ctx context.Context, arg NewFileParams) (int64, error)
 	return id, err
 }
 
+const newFileIntent = `-- name: NewFileIntent :one
+INSERT INTO file_intent (action, path) VALUES ($1, $2) RETURNING id
+`
+
+type NewFileIntentParams struct {
+	Action Fileintentaction
+	Path   string
+}
+
+func (q *Queries) NewFileIntent(ctx context.Context, arg NewFileIntentParams) (int64, error) {
+	row := q.queryRow(ctx, q.newFileIntentStmt, newFileIntent, arg.Action, arg.Path)
+	var id int64
+	err := row.Scan(&id)
+	return id, err
+}
+
+const newFileProbeMismatch = `-- name: NewFileProbeMismatch :exec
+INSERT INTO file_probe_mismatch (file_id, field, expected, actual) VALUES ($1, $2, $3, $4)
+`
+
+type NewFileProbeMismatchParams struct {
+	FileID   int64
+	Field    string
+	Expected string
+	Actual   string
+}
+
+func (q *Queries) NewFileProbeMismatch(ctx context.Context, arg NewFileProbeMismatchParams) error {
+	_, err := q.exec(ctx, q.newFileProbeMismatchStmt, newFileProbeMismatch,
+		arg.FileID,
+		arg.Field,
+		arg.Expected,
+		arg.Actual,
+	)
+	return err
+}
+
+const newFileProbeStream = `-- name: NewFileProbeStream :exec
+INSERT INTO file_probe_stream (file_id, stream_index, codec_type, codec, bitrate, width, height, fps, sample_rate, channels)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+`
+
+type NewFileProbeStreamParams struct {
+	FileID      int64
+	StreamIndex int16
+	CodecType   string
+	Codec       sql.NullString
+	Bitrate     sql.NullInt64
+	Width       sql.NullInt16
+	Height      sql.NullInt16
+	Fps         sql.NullFloat64
+	SampleRate  sql.NullInt32
+	Channels    sql.NullInt16
+}
+
+func (q *Queries) NewFileProbeStream(ctx context.Context, arg NewFileProbeStreamParams) error {
+	_, err := q.exec(ctx, q.newFileProbeStreamStmt, newFileProbeStream,
+		arg.FileID,
+		arg.StreamIndex,
+		arg.CodecType,
+		arg.Codec,
+		arg.Bitrate,
+		arg.Width,
+		arg.Height,
+		arg.Fps,
+		arg.SampleRate,
+		arg.Channels,
+	)
+	return err
+}
+
 const newFileVideo = `-- name: NewFileVideo :exec
 INSERT INTO file_video (file_id, duration, width, height, fps, video_codec, audio_codec)
 VALUES ($1, $2, $3, $4, $5, $6, $7)
@@ -523,27 +2561,160 @@ func (q *Queries) NewFileVideo(ctx context.Context, arg NewFileVideoParams) erro
 	return err
 }
 
-const newProject = `-- name: NewProject :one
-INSERT INTO project (uuid, type, date_announced, date_completed) VALUES ($1, $2, $3, $4) RETURNING uuid
+const newMusic = `-- name: NewMusic :one
+INSERT INTO music (artist, title) VALUES ($1, $2) RETURNING id
+`
+
+type NewMusicParams struct {
+	Artist string
+	Title  string
+}
+
+func (q *Queries) NewMusic(ctx context.Context, arg NewMusicParams) (int32, error) {
+	row := q.queryRow(ctx, q.newMusicStmt, newMusic, arg.Artist, arg.Title)
+	var id int32
+	err := row.Scan(&id)
+	return id, err
+}
+
+const newProject = `-- name: NewProject :one
+INSERT INTO project (uuid, type, date_announced, date_announced_precision, date_announced_approximate,
+date_completed, date_completed_precision, date_completed_approximate) VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING uuid
+`
+
+type NewProjectParams struct {
+	Uuid                     string
+	Type                     Projecttype
+	DateAnnounced            sql.NullTime
+	DateAnnouncedPrecision   NullDateprecision
+	DateAnnouncedApproximate bool
+	DateCompleted            sql.NullTime
+	DateCompletedPrecision   NullDateprecision
+	DateCompletedApproximate bool
+}
+
+func (q *Queries) NewProject(ctx context.Context, arg NewProjectParams) (string, error) {
+	row := q.queryRow(ctx, q.newProjectStmt, newProject,
+		arg.Uuid,
+		arg.Type,
+		arg.DateAnnounced,
+		arg.DateAnnouncedPrecision,
+		arg.DateAnnouncedApproximate,
+		arg.DateCompleted,
+		arg.DateCompletedPrecision,
+		arg.DateCompletedApproximate,
+	)
+	var uuid string
+	err := row.Scan(&uuid)
+	return uuid, err
+}
+
+const newProjectDescription = `-- name: NewProjectDescription :exec
+INSERT INTO project_description (project_id, description, description_md5) VALUES ((SELECT id FROM project WHERE uuid = $1), $2, $3)
+`
+
+type NewProjectDescriptionParams struct {
+	Uuid           string
+	Description    string
+	DescriptionMd5 []byte
+}
+
+func (q *Queries) NewProjectDescription(ctx context.Context, arg NewProjectDescriptionParams) error {
+	_, err := q.exec(ctx, q.newProjectDescriptionStmt, newProjectDescription, arg.Uuid, arg.Description, arg.DescriptionMd5)
+	return err
+}
+
+const newProjectPart = `-- name: NewProjectPart :one
+INSERT INTO project_part (project_id, part_number, start_ms, end_ms, status, participant_channel_id, participant_name, file_id, youtube_id, participant_artist_id)
+VALUES ((SELECT id FROM project WHERE uuid = $1), $2, $3, $4, $5, $6, $7, $8, $9, $10)
+RETURNING id
+`
+
+type NewProjectPartParams struct {
+	Uuid                 string
+	PartNumber           int16
+	StartMs              sql.NullInt32
+	EndMs                sql.NullInt32
+	Status               Partstatus
+	ParticipantChannelID sql.NullString
+	ParticipantName      sql.NullString
+	FileID               sql.NullInt64
+	YoutubeID            sql.NullString
+	ParticipantArtistID  sql.NullInt64
+}
+
+func (q *Queries) NewProjectPart(ctx context.Context, arg NewProjectPartParams) (int64, error) {
+	row := q.queryRow(ctx, q.newProjectPartStmt, newProjectPart,
+		arg.Uuid,
+		arg.PartNumber,
+		arg.StartMs,
+		arg.EndMs,
+		arg.Status,
+		arg.ParticipantChannelID,
+		arg.ParticipantName,
+		arg.FileID,
+		arg.YoutubeID,
+		arg.ParticipantArtistID,
+	)
+	var id int64
+	err := row.Scan(&id)
+	return id, err
+}
+
+const newProjectRelation = `-- name: NewProjectRelation :exec
+INSERT INTO project_relation (project_id, related_project_id, type) VALUES
+((SELECT id FROM project WHERE uuid = $1), (SELECT id FROM project WHERE uuid = $2), $3)
+`
+
+type NewProjectRelationParams struct {
+	Uuid   string
+	Uuid_2 string
+	Type   Projectrelationtype
+}
+
+func (q *Queries) NewProjectRelation(ctx context.Context, arg NewProjectRelationParams) error {
+	_, err := q.exec(ctx, q.newProjectRelationStmt, newProjectRelation, arg.Uuid, arg.Uuid_2, arg.Type)
+	return err
+}
+
+const newProjectStatus = `-- name: NewProjectStatus :one
+INSERT INTO project_status (project_id, status, date, note) VALUES
+((SELECT id FROM project WHERE uuid = $1), $2, $3, $4)
+RETURNING id
+`
+
+type NewProjectStatusParams struct {
+	Uuid   string
+	Status Projectstatus
+	Date   time.Time
+	Note   string
+}
+
+func (q *Queries) NewProjectStatus(ctx context.Context, arg NewProjectStatusParams) (int64, error) {
+	row := q.queryRow(ctx, q.newProjectStatusStmt, newProjectStatus,
+		arg.Uuid,
+		arg.Status,
+		arg.Date,
+		arg.Note,
+	)
+	var id int64
+	err := row.Scan(&id)
+	return id, err
+}
+
+const newProjectTitle = `-- name: NewProjectTitle :exec
+INSERT INTO project_title (project_id, title, title_md5) VALUES ((SELECT id FROM project WHERE uuid = $1), $2, $3)
 `
 
-type NewProjectParams struct {
-	Uuid          string
-	Type          Projecttype
-	DateAnnounced sql.NullTime
-	DateCompleted sql.NullTime
+type NewProjectTitleParams struct {
+	Uuid     string
+	Title    string
+	TitleMd5 []byte
 }
 
-func (q *Queries) NewProject(ctx context.Context, arg NewProjectParams) (string, error) {
-	row := q.queryRow(ctx, q.newProjectStmt, newProject,
-		arg.Uuid,
-		arg.Type,
-		arg.DateAnnounced,
-		arg.DateCompleted,
-	)
-	var uuid string
-	err := row.Scan(&uuid)
-	return uuid, err
+func (q *Queries) NewProjectTitle(ctx context.Context, arg NewProjectTitleParams) error {
+	_, err := q.exec(ctx, q.newProjectTitleStmt, newProjectTitle, arg.Uuid, arg.Title, arg.TitleMd5)
+	return err
 }
 
 const newYoutube = `-- name: NewYoutube :exec
@@ -662,6 +2833,36 @@ func (q *Queries) NewYoutubeFormat(ctx context.Context, arg NewYoutubeFormatPara
 	return err
 }
 
+const newYoutubeThumbnail = `-- name: NewYoutubeThumbnail :exec
+INSERT INTO youtube_video_thumbnail (file_id, youtube_id, thumbnail) VALUES ($1, $2, $3)
+`
+
+type NewYoutubeThumbnailParams struct {
+	FileID    int64
+	YoutubeID interface{}
+	Thumbnail []byte
+}
+
+func (q *Queries) NewYoutubeThumbnail(ctx context.Context, arg NewYoutubeThumbnailParams) error {
+	_, err := q.exec(ctx, q.newYoutubeThumbnailStmt, newYoutubeThumbnail, arg.FileID, arg.YoutubeID, arg.Thumbnail)
+	return err
+}
+
+const newYoutubeYtdlpInfo = `-- name: NewYoutubeYtdlpInfo :exec
+INSERT INTO youtube_video_ytdlp_info (file_id, youtube_id, info) VALUES ($1, $2, $3)
+`
+
+type NewYoutubeYtdlpInfoParams struct {
+	FileID    int64
+	YoutubeID interface{}
+	Info      json.RawMessage
+}
+
+func (q *Queries) NewYoutubeYtdlpInfo(ctx context.Context, arg NewYoutubeYtdlpInfoParams) error {
+	_, err := q.exec(ctx, q.newYoutubeYtdlpInfoStmt, newYoutubeYtdlpInfo, arg.FileID, arg.YoutubeID, arg.Info)
+	return err
+}
+
 const newYoutubeYtdlpVersion = `-- name: NewYoutubeYtdlpVersion :exec
 INSERT INTO youtube_video_ytdlp_version ("file_id", "youtube_id", "repository", "release_git_head", "version") VALUES ($1, $2, $3, $4, $5)
 `
@@ -685,15 +2886,246 @@ func (q *Queries) NewYoutubeYtdlpVersion(ctx context.Context, arg NewYoutubeYtdl
 	return err
 }
 
-const unassignProjectFile = `-- name: UnassignProjectFile :exec
-DELETE FROM project_file WHERE file_id = $1
+const renameArtist = `-- name: RenameArtist :exec
+UPDATE artist SET name = $2 WHERE id = $1
+`
+
+type RenameArtistParams struct {
+	ID   int64
+	Name string
+}
+
+func (q *Queries) RenameArtist(ctx context.Context, arg RenameArtistParams) error {
+	_, err := q.exec(ctx, q.renameArtistStmt, renameArtist, arg.ID, arg.Name)
+	return err
+}
+
+const search = `-- name: Search :many
+WITH query AS (
+    SELECT websearch_to_tsquery('english', $1::text) AS q
+), matches AS (
+    SELECT DISTINCT ON (kind, id) kind, id, text, rank FROM (
+        SELECT 'youtube' AS kind, youtube_title.youtube_id::text AS id, youtube_title.title AS text,
+            ts_rank(to_tsvector('english', youtube_title.title), query.q) AS rank
+        FROM youtube_title, query WHERE to_tsvector('english', youtube_title.title) @@ query.q
+        UNION ALL
+        SELECT 'youtube', youtube_description.youtube_id::text, youtube_description.description,
+            ts_rank(to_tsvector('english', youtube_description.description), query.q) / 2
+        FROM youtube_description, query WHERE to_tsvector('english', youtube_description.description) @@ query.q
+        UNION ALL
+        SELECT 'channel', youtube_channel_uploader_name.channel_id::text, youtube_channel_uploader_name.uploader,
+            ts_rank(to_tsvector('english', youtube_channel_uploader_name.uploader), query.q)
+        FROM youtube_channel_uploader_name, query WHERE to_tsvector('english', youtube_channel_uploader_name.uploader) @@ query.q
+        UNION ALL
+        SELECT 'project', project.uuid, project_title.title,
+            ts_rank(to_tsvector('english', project_title.title), query.q)
+        FROM project_title INNER JOIN project ON project.id = project_title.project_id, query
+        WHERE to_tsvector('english', project_title.title) @@ query.q
+        UNION ALL
+        SELECT 'artist', artist.id::text, artist.name::text,
+            ts_rank(to_tsvector('english', artist.name::text), query.q)
+        FROM artist, query WHERE to_tsvector('english', artist.name::text) @@ query.q
+        UNION ALL
+        SELECT 'artist', artist_alias.artist_id::text, artist_alias.alias::text,
+            ts_rank(to_tsvector('english', artist_alias.alias::text), query.q)
+        FROM artist_alias, query WHERE to_tsvector('english', artist_alias.alias::text) @@ query.q
+        UNION ALL
+        SELECT 'music', music.id::text, music.artist::text || ' - ' || music.title::text,
+            ts_rank(to_tsvector('english', music.artist::text || ' ' || music.title::text), query.q)
+        FROM music, query WHERE to_tsvector('english', music.artist::text || ' ' || music.title::text) @@ query.q
+        UNION ALL
+        SELECT 'character', character.id::text, character.name::text || ' (' || character.series::text || ')',
+            ts_rank(to_tsvector('english', character.name::text || ' ' || character.series::text), query.q)
+        FROM character, query WHERE to_tsvector('english', character.name::text || ' ' || character.series::text) @@ query.q
+        UNION ALL
+        SELECT 'character', character_alias.character_id::text, character_alias.name::text,
+            ts_rank(to_tsvector('english', character_alias.name::text), query.q)
+        FROM character_alias, query WHERE to_tsvector('english', character_alias.name::text) @@ query.q
+    ) AS m
+    WHERE $2::text = '' OR m.kind = $2::text
+    ORDER BY kind, id, rank DESC
+)
+SELECT
+    matches.kind::text AS kind,
+    matches.id::text AS id,
+    COALESCE(CASE matches.kind
+        WHEN 'youtube' THEN (SELECT title FROM youtube_title WHERE youtube_title.youtube_id = matches.id ORDER BY date_added DESC LIMIT 1)
+        WHEN 'project' THEN (SELECT title FROM project_title WHERE project_title.project_id = (SELECT id FROM project WHERE uuid = matches.id) ORDER BY date_added DESC, project_title.id DESC LIMIT 1)
+        WHEN 'artist' THEN (SELECT name::text FROM artist WHERE artist.id = matches.id::BIGINT)
+        WHEN 'character' THEN (SELECT name::text FROM character WHERE character.id = matches.id::INTEGER)
+    END, matches.text)::text AS title,
+    ts_headline('english', translate(matches.text, chr(2) || chr(3), ''), query.q,
+        'StartSel=' || chr(2) || ', StopSel=' || chr(3) || ', MaxFragments=2, MaxWords=24, MinWords=8')::text AS headline,
+    matches.rank::DOUBLE PRECISION AS rank
+FROM matches, query
+ORDER BY matches.rank DESC, matches.kind, matches.id
+LIMIT $3::INTEGER
+`
+
+type SearchParams struct {
+	Query      string
+	Kind       string
+	MaxResults int32
+}
+
+type SearchRow struct {
+	Kind     string
+	ID       string
+	Title    string
+	Headline string
+	Rank     float64
+}
+
+func (q *Queries) Search(ctx context.Context, arg SearchParams) ([]SearchRow, error) {
+	rows, err := q.query(ctx, q.searchStmt, search, arg.Query, arg.Kind, arg.MaxResults)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SearchRow
+	for rows.Next() {
+		var i SearchRow
+		if err := rows.Scan(
+			&i.Kind,
+			&i.ID,
+			&i.Title,
+			&i.Headline,
+			&i.Rank,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const setProjectDateAnnounced = `-- name: SetProjectDateAnnounced :exec
+UPDATE project SET date_announced = $2, date_announced_precision = 'day', date_announced_approximate = FALSE
+WHERE uuid = $1 AND date_announced IS NULL
+`
+
+type SetProjectDateAnnouncedParams struct {
+	Uuid          string
+	DateAnnounced sql.NullTime
+}
+
+func (q *Queries) SetProjectDateAnnounced(ctx context.Context, arg SetProjectDateAnnouncedParams) error {
+	_, err := q.exec(ctx, q.setProjectDateAnnouncedStmt, setProjectDateAnnounced, arg.Uuid, arg.DateAnnounced)
+	return err
+}
+
+const setProjectDateCompleted = `-- name: SetProjectDateCompleted :exec
+UPDATE project SET date_completed = $2, date_completed_precision = 'day', date_completed_approximate = FALSE
+WHERE uuid = $1 AND date_completed IS NULL
+`
+
+type SetProjectDateCompletedParams struct {
+	Uuid          string
+	DateCompleted sql.NullTime
+}
+
+func (q *Queries) SetProjectDateCompleted(ctx context.Context, arg SetProjectDateCompletedParams) error {
+	_, err := q.exec(ctx, q.setProjectDateCompletedStmt, setProjectDateCompleted, arg.Uuid, arg.DateCompleted)
+	return err
+}
+
+const tagPartCharacter = `-- name: TagPartCharacter :exec
+INSERT INTO project_part_character (part_id, character_id) VALUES ($1, $2) ON CONFLICT DO NOTHING
 `
 
-func (q *Queries) UnassignProjectFile(ctx context.Context, fileID int64) error {
-	_, err := q.exec(ctx, q.unassignProjectFileStmt, unassignProjectFile, fileID)
+type TagPartCharacterParams struct {
+	PartID      int64
+	CharacterID int32
+}
+
+func (q *Queries) TagPartCharacter(ctx context.Context, arg TagPartCharacterParams) error {
+	_, err := q.exec(ctx, q.tagPartCharacterStmt, tagPartCharacter, arg.PartID, arg.CharacterID)
+	return err
+}
+
+const tagProjectCharacter = `-- name: TagProjectCharacter :exec
+INSERT INTO project_character (project_id, character_id) VALUES ((SELECT id FROM project WHERE uuid = $1), $2)
+ON CONFLICT DO NOTHING
+`
+
+type TagProjectCharacterParams struct {
+	Uuid        string
+	CharacterID int32
+}
+
+func (q *Queries) TagProjectCharacter(ctx context.Context, arg TagProjectCharacterParams) error {
+	_, err := q.exec(ctx, q.tagProjectCharacterStmt, tagProjectCharacter, arg.Uuid, arg.CharacterID)
 	return err
 }
 
+const tryLockStorage = `-- name: TryLockStorage :one
+SELECT pg_try_advisory_lock(hashtext('storage_root'))
+`
+
+func (q *Queries) TryLockStorage(ctx context.Context) (bool, error) {
+	row := q.queryRow(ctx, q.tryLockStorageStmt, tryLockStorage)
+	var pg_try_advisory_lock bool
+	err := row.Scan(&pg_try_advisory_lock)
+	return pg_try_advisory_lock, err
+}
+
+const unassignArtistChannel = `-- name: UnassignArtistChannel :execrows
+DELETE FROM artist_channel WHERE artist_id = $1 AND channel_id = $2
+`
+
+type UnassignArtistChannelParams struct {
+	ArtistID  int64
+	ChannelID interface{}
+}
+
+func (q *Queries) UnassignArtistChannel(ctx context.Context, arg UnassignArtistChannelParams) (int64, error) {
+	result, err := q.exec(ctx, q.unassignArtistChannelStmt, unassignArtistChannel, arg.ArtistID, arg.ChannelID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const unassignProjectFile = `-- name: UnassignProjectFile :execrows
+DELETE FROM project_file WHERE project_id = (SELECT id FROM project WHERE uuid = $1) AND file_id = $2
+`
+
+type UnassignProjectFileParams struct {
+	Uuid   string
+	FileID int64
+}
+
+func (q *Queries) UnassignProjectFile(ctx context.Context, arg UnassignProjectFileParams) (int64, error) {
+	result, err := q.exec(ctx, q.unassignProjectFileStmt, unassignProjectFile, arg.Uuid, arg.FileID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const unassignProjectMusic = `-- name: UnassignProjectMusic :execrows
+DELETE FROM project_music WHERE project_id = (SELECT id FROM project WHERE uuid = $1) AND music_id = $2
+`
+
+type UnassignProjectMusicParams struct {
+	Uuid    string
+	MusicID int32
+}
+
+func (q *Queries) UnassignProjectMusic(ctx context.Context, arg UnassignProjectMusicParams) (int64, error) {
+	result, err := q.exec(ctx, q.unassignProjectMusicStmt, unassignProjectMusic, arg.Uuid, arg.MusicID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 const unassignYoutubeVideoFromProject = `-- name: UnassignYoutubeVideoFromProject :exec
 DELETE FROM project_file WHERE 
     project_id = (SELECT id FROM project WHERE uuid = $1)
@@ -710,3 +3142,288 @@ func (q *Queries) UnassignYoutubeVideoFromProject(ctx context.Context, arg Unass
 	_, err := q.exec(ctx, q.unassignYoutubeVideoFromProjectStmt, unassignYoutubeVideoFromProject, arg.Uuid, arg.YoutubeID)
 	return err
 }
+
+const unlockStorage = `-- name: UnlockStorage :exec
+SELECT pg_advisory_unlock(hashtext('storage_root'))
+`
+
+func (q *Queries) UnlockStorage(ctx context.Context) error {
+	_, err := q.exec(ctx, q.unlockStorageStmt, unlockStorage)
+	return err
+}
+
+const unlockStorageShared = `-- name: UnlockStorageShared :exec
+SELECT pg_advisory_unlock_shared(hashtext('storage_root'))
+`
+
+func (q *Queries) UnlockStorageShared(ctx context.Context) error {
+	_, err := q.exec(ctx, q.unlockStorageSharedStmt, unlockStorageShared)
+	return err
+}
+
+const untagPartCharacter = `-- name: UntagPartCharacter :execrows
+DELETE FROM project_part_character WHERE part_id = $1 AND character_id = $2
+`
+
+type UntagPartCharacterParams struct {
+	PartID      int64
+	CharacterID int32
+}
+
+func (q *Queries) UntagPartCharacter(ctx context.Context, arg UntagPartCharacterParams) (int64, error) {
+	result, err := q.exec(ctx, q.untagPartCharacterStmt, untagPartCharacter, arg.PartID, arg.CharacterID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const untagProjectCharacter = `-- name: UntagProjectCharacter :execrows
+DELETE FROM project_character WHERE project_id = (SELECT id FROM project WHERE uuid = $1) AND character_id = $2
+`
+
+type UntagProjectCharacterParams struct {
+	Uuid        string
+	CharacterID int32
+}
+
+func (q *Queries) UntagProjectCharacter(ctx context.Context, arg UntagProjectCharacterParams) (int64, error) {
+	result, err := q.exec(ctx, q.untagProjectCharacterStmt, untagProjectCharacter, arg.Uuid, arg.CharacterID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const updateCharacter = `-- name: UpdateCharacter :execrows
+UPDATE character SET name = $2, series = $3, is_original = $4 WHERE id = $1
+`
+
+type UpdateCharacterParams struct {
+	ID         int32
+	Name       string
+	Series     string
+	IsOriginal bool
+}
+
+func (q *Queries) UpdateCharacter(ctx context.Context, arg UpdateCharacterParams) (int64, error) {
+	result, err := q.exec(ctx, q.updateCharacterStmt, updateCharacter,
+		arg.ID,
+		arg.Name,
+		arg.Series,
+		arg.IsOriginal,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const updateMusic = `-- name: UpdateMusic :execrows
+UPDATE music SET artist = $2, title = $3 WHERE id = $1
+`
+
+type UpdateMusicParams struct {
+	ID     int32
+	Artist string
+	Title  string
+}
+
+func (q *Queries) UpdateMusic(ctx context.Context, arg UpdateMusicParams) (int64, error) {
+	result, err := q.exec(ctx, q.updateMusicStmt, updateMusic, arg.ID, arg.Artist, arg.Title)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const updateProjectDateAnnounced = `-- name: UpdateProjectDateAnnounced :execrows
+UPDATE project SET date_announced = $2, date_announced_precision = $3, date_announced_approximate = $4 WHERE uuid = $1
+`
+
+type UpdateProjectDateAnnouncedParams struct {
+	Uuid                     string
+	DateAnnounced            sql.NullTime
+	DateAnnouncedPrecision   NullDateprecision
+	DateAnnouncedApproximate bool
+}
+
+func (q *Queries) UpdateProjectDateAnnounced(ctx context.Context, arg UpdateProjectDateAnnouncedParams) (int64, error) {
+	result, err := q.exec(ctx, q.updateProjectDateAnnouncedStmt, updateProjectDateAnnounced,
+		arg.Uuid,
+		arg.DateAnnounced,
+		arg.DateAnnouncedPrecision,
+		arg.DateAnnouncedApproximate,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const updateProjectDateCompleted = `-- name: UpdateProjectDateCompleted :execrows
+UPDATE project SET date_completed = $2, date_completed_precision = $3, date_completed_approximate = $4 WHERE uuid = $1
+`
+
+type UpdateProjectDateCompletedParams struct {
+	Uuid                     string
+	DateCompleted            sql.NullTime
+	DateCompletedPrecision   NullDateprecision
+	DateCompletedApproximate bool
+}
+
+func (q *Queries) UpdateProjectDateCompleted(ctx context.Context, arg UpdateProjectDateCompletedParams) (int64, error) {
+	result, err := q.exec(ctx, q.updateProjectDateCompletedStmt, updateProjectDateCompleted,
+		arg.Uuid,
+		arg.DateCompleted,
+		arg.DateCompletedPrecision,
+		arg.DateCompletedApproximate,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const updateProjectPart = `-- name: UpdateProjectPart :execrows
+UPDATE project_part SET
+    part_number = $2,
+    start_ms = $3,
+    end_ms = $4,
+    status = $5,
+    participant_channel_id = $6,
+    participant_name = $7,
+    file_id = $8,
+    youtube_id = $9,
+    participant_artist_id = $10
+WHERE id = $1
+`
+
+type UpdateProjectPartParams struct {
+	ID                   int64
+	PartNumber           int16
+	StartMs              sql.NullInt32
+	EndMs                sql.NullInt32
+	Status               Partstatus
+	ParticipantChannelID sql.NullString
+	ParticipantName      sql.NullString
+	FileID               sql.NullInt64
+	YoutubeID            sql.NullString
+	ParticipantArtistID  sql.NullInt64
+}
+
+func (q *Queries) UpdateProjectPart(ctx context.Context, arg UpdateProjectPartParams) (int64, error) {
+	result, err := q.exec(ctx, q.updateProjectPartStmt, updateProjectPart,
+		arg.ID,
+		arg.PartNumber,
+		arg.StartMs,
+		arg.EndMs,
+		arg.Status,
+		arg.ParticipantChannelID,
+		arg.ParticipantName,
+		arg.FileID,
+		arg.YoutubeID,
+		arg.ParticipantArtistID,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const updateProjectType = `-- name: UpdateProjectType :execrows
+UPDATE project SET type = $2 WHERE uuid = $1
+`
+
+type UpdateProjectTypeParams struct {
+	Uuid string
+	Type Projecttype
+}
+
+func (q *Queries) UpdateProjectType(ctx context.Context, arg UpdateProjectTypeParams) (int64, error) {
+	result, err := q.exec(ctx, q.updateProjectTypeStmt, updateProjectType, arg.Uuid, arg.Type)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const upsertFileFingerprint = `-- name: UpsertFileFingerprint :exec
+INSERT INTO file_fingerprint (file_id, frame_interval, hashes) VALUES ($1, $2, $3)
+ON CONFLICT (file_id) DO UPDATE SET
+    frame_interval = EXCLUDED.frame_interval,
+    hashes = EXCLUDED.hashes,
+    date_added = (NOW() AT TIME ZONE 'utc')
+`
+
+type UpsertFileFingerprintParams struct {
+	FileID        int64
+	FrameInterval float64
+	Hashes        []byte
+}
+
+func (q *Queries) UpsertFileFingerprint(ctx context.Context, arg UpsertFileFingerprintParams) error {
+	_, err := q.exec(ctx, q.upsertFileFingerprintStmt, upsertFileFingerprint, arg.FileID, arg.FrameInterval, arg.Hashes)
+	return err
+}
+
+const upsertFileProbe = `-- name: UpsertFileProbe :exec
+INSERT INTO file_probe (file_id, container, duration, bitrate) VALUES ($1, $2, $3, $4)
+ON CONFLICT (file_id) DO UPDATE SET
+    container = EXCLUDED.container,
+    duration = EXCLUDED.duration,
+    bitrate = EXCLUDED.bitrate,
+    date_probed = (NOW() AT TIME ZONE 'utc')
+`
+
+type UpsertFileProbeParams struct {
+	FileID    int64
+	Container string
+	Duration  float64
+	Bitrate   sql.NullInt64
+}
+
+func (q *Queries) UpsertFileProbe(ctx context.Context, arg UpsertFileProbeParams) error {
+	_, err := q.exec(ctx, q.upsertFileProbeStmt, upsertFileProbe,
+		arg.FileID,
+		arg.Container,
+		arg.Duration,
+		arg.Bitrate,
+	)
+	return err
+}
+
+const upsertFileVideo = `-- name: UpsertFileVideo :exec
+INSERT INTO file_video (file_id, duration, width, height, fps, video_codec, audio_codec)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+ON CONFLICT (file_id) DO UPDATE SET
+    duration = EXCLUDED.duration,
+    width = EXCLUDED.width,
+    height = EXCLUDED.height,
+    fps = EXCLUDED.fps,
+    video_codec = EXCLUDED.video_codec,
+    audio_codec = EXCLUDED.audio_codec
+`
+
+type UpsertFileVideoParams struct {
+	FileID     int64
+	Duration   int32
+	Width      int16
+	Height     int16
+	Fps        sql.NullInt16
+	VideoCodec sql.NullString
+	AudioCodec sql.NullString
+}
+
+func (q *Queries) UpsertFileVideo(ctx context.Context, arg UpsertFileVideoParams) error {
+	_, err := q.exec(ctx, q.upsertFileVideoStmt, upsertFileVideo,
+		arg.FileID,
+		arg.Duration,
+		arg.Width,
+		arg.Height,
+		arg.Fps,
+		arg.VideoCodec,
+		arg.AudioCodec,
+	)
+	return err
+}
@@ -7,8 +7,48 @@ DELETE FROM file WHERE id = $1;
 -- name: GetFileByID :one
 SELECT * FROM file WHERE id = $1;
 
+-- name: GetFileExistsByPath :one
+SELECT EXISTS(SELECT 1 FROM file WHERE path = $1);
+
+-- name: GetFileIDBySHA256 :one
+SELECT id FROM file WHERE sha256 = $1;
+
+-- name: NewFileIntent :one
+INSERT INTO file_intent (action, path) VALUES ($1, $2) RETURNING id;
+
+-- name: DeleteFileIntent :exec
+DELETE FROM file_intent WHERE id = $1;
+
+-- name: GetFileIntents :many
+SELECT * FROM file_intent ORDER BY id;
+
+-- name: GetFileIntentExistsByPath :one
+SELECT EXISTS(SELECT 1 FROM file_intent WHERE path = $1);
+
+-- name: LockStorageShared :exec
+SELECT pg_advisory_lock_shared(hashtext('storage_root'));
+
+-- name: UnlockStorageShared :exec
+SELECT pg_advisory_unlock_shared(hashtext('storage_root'));
+
+-- name: TryLockStorage :one
+SELECT pg_try_advisory_lock(hashtext('storage_root'));
+
+-- name: UnlockStorage :exec
+SELECT pg_advisory_unlock(hashtext('storage_root'));
+
+-- name: GetFilesAfter :many
+SELECT * FROM file WHERE id > $1 ORDER BY id;
+
+-- name: LockFiles :exec
+LOCK TABLE file IN SHARE MODE;
+
+-- name: ExportSnapshot :one
+SELECT pg_export_snapshot()::text AS snapshot_id;
+
 -- name: NewProject :one
-INSERT INTO project (uuid, type, date_announced, date_completed) VALUES ($1, $2, $3, $4) RETURNING uuid;
+INSERT INTO project (uuid, type, date_announced, date_announced_precision, date_announced_approximate,
+date_completed, date_completed_precision, date_completed_approximate) VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING uuid;
 
 -- name: DeleteProjectByUUID :exec
 DELETE FROM project WHERE uuid = $1;
@@ -19,12 +59,175 @@ SELECT * FROM project WHERE uuid = $1;
 -- name: AssignProjectFile :exec
 INSERT INTO project_file (project_id, file_id) VALUES ((SELECT id FROM project WHERE uuid = $1), $2);
 
--- name: UnassignProjectFile :exec
-DELETE FROM project_file WHERE file_id = $1;
+-- name: UnassignProjectFile :execrows
+DELETE FROM project_file WHERE project_id = (SELECT id FROM project WHERE uuid = $1) AND file_id = $2;
 
 -- name: GetProjectFile :many
 SELECT file_id FROM project_file WHERE project_id = (SELECT id FROM project WHERE uuid = $1);
 
+-- name: GetFileProjects :many
+SELECT project.uuid FROM project
+WHERE project.id IN (
+	SELECT project_file.project_id FROM project_file WHERE project_file.file_id = $1
+		UNION
+	SELECT project_part.project_id FROM project_part WHERE project_part.file_id = $1
+)
+ORDER BY project.uuid;
+
+-- name: NewProjectTitle :exec
+INSERT INTO project_title (project_id, title, title_md5) VALUES ((SELECT id FROM project WHERE uuid = $1), $2, $3);
+
+-- name: GetProjectTitles :many
+SELECT project_title.* FROM project_title
+INNER JOIN project ON project.id = project_title.project_id
+WHERE project.uuid = $1
+ORDER BY project_title.date_added DESC, project_title.id DESC;
+
+-- name: GetCurrentProjectTitle :one
+SELECT project_title.* FROM project_title
+INNER JOIN project ON project.id = project_title.project_id
+WHERE project.uuid = $1
+ORDER BY project_title.date_added DESC, project_title.id DESC
+LIMIT 1;
+
+-- name: NewProjectDescription :exec
+INSERT INTO project_description (project_id, description, description_md5) VALUES ((SELECT id FROM project WHERE uuid = $1), $2, $3);
+
+-- name: GetProjectDescriptions :many
+SELECT project_description.* FROM project_description
+INNER JOIN project ON project.id = project_description.project_id
+WHERE project.uuid = $1
+ORDER BY project_description.date_added DESC, project_description.id DESC;
+
+-- name: GetCurrentProjectDescription :one
+SELECT project_description.* FROM project_description
+INNER JOIN project ON project.id = project_description.project_id
+WHERE project.uuid = $1
+ORDER BY project_description.date_added DESC, project_description.id DESC
+LIMIT 1;
+
+-- name: LockProjectRelations :exec
+LOCK TABLE project_relation IN SHARE ROW EXCLUSIVE MODE;
+
+-- name: NewProjectRelation :exec
+INSERT INTO project_relation (project_id, related_project_id, type) VALUES
+((SELECT id FROM project WHERE uuid = $1), (SELECT id FROM project WHERE uuid = $2), $3);
+
+-- name: DeleteProjectRelation :execrows
+DELETE FROM project_relation WHERE
+    project_id = (SELECT id FROM project WHERE uuid = $1)
+AND related_project_id = (SELECT id FROM project WHERE uuid = $2)
+AND type = $3;
+
+-- name: GetProjectRelations :many
+SELECT project.uuid AS project_uuid, related.uuid AS related_uuid, project_relation.type FROM project_relation
+INNER JOIN project ON project.id = project_relation.project_id
+INNER JOIN project AS related ON related.id = project_relation.related_project_id
+WHERE project.uuid = $1 OR related.uuid = $1
+ORDER BY project_relation.type, project_relation.date_added;
+
+-- name: GetProjectRelationAncestors :many
+WITH RECURSIVE up (project_id, related_project_id, depth) AS (
+    SELECT project_relation.project_id, project_relation.related_project_id, 1 FROM project_relation
+    WHERE project_relation.project_id = (SELECT id FROM project WHERE uuid = $1) AND project_relation.type = $2
+  UNION ALL
+    SELECT project_relation.project_id, project_relation.related_project_id, up.depth + 1 FROM project_relation
+    INNER JOIN up ON project_relation.project_id = up.related_project_id
+    WHERE project_relation.type = $2 AND up.depth < 64
+)
+SELECT project.uuid AS project_uuid, related.uuid AS related_uuid, up.depth FROM up
+INNER JOIN project ON project.id = up.project_id
+INNER JOIN project AS related ON related.id = up.related_project_id
+ORDER BY up.depth, related.uuid;
+
+-- name: GetProjectRelationDescendants :many
+WITH RECURSIVE down (project_id, related_project_id, depth) AS (
+    SELECT project_relation.project_id, project_relation.related_project_id, 1 FROM project_relation
+    WHERE project_relation.related_project_id = (SELECT id FROM project WHERE uuid = $1) AND project_relation.type = $2
+  UNION ALL
+    SELECT project_relation.project_id, project_relation.related_project_id, down.depth + 1 FROM project_relation
+    INNER JOIN down ON project_relation.related_project_id = down.project_id
+    WHERE project_relation.type = $2 AND down.depth < 64
+)
+SELECT project.uuid AS project_uuid, related.uuid AS related_uuid, down.depth FROM down
+INNER JOIN project ON project.id = down.project_id
+INNER JOIN project AS related ON related.id = down.related_project_id
+ORDER BY down.depth, project.uuid;
+
+-- name: NewProjectStatus :one
+INSERT INTO project_status (project_id, status, date, note) VALUES
+((SELECT id FROM project WHERE uuid = $1), $2, $3, $4)
+RETURNING id;
+
+-- name: LockProject :one
+SELECT id FROM project WHERE uuid = $1 FOR UPDATE;
+
+-- name: GetProjectStatuses :many
+SELECT project_status.id, project_status.status, project_status.date, project_status.note, project_status.date_added FROM project_status
+INNER JOIN project ON project.id = project_status.project_id
+WHERE project.uuid = $1
+ORDER BY project_status.date, project_status.id;
+
+-- name: DeleteLatestProjectStatus :execrows
+DELETE FROM project_status WHERE id = (
+    SELECT project_status.id FROM project_status
+    INNER JOIN project ON project.id = project_status.project_id
+    WHERE project.uuid = $1
+    ORDER BY project_status.date DESC, project_status.id DESC
+    LIMIT 1
+);
+
+-- name: SetProjectDateAnnounced :exec
+UPDATE project SET date_announced = $2, date_announced_precision = 'day', date_announced_approximate = FALSE
+WHERE uuid = $1 AND date_announced IS NULL;
+
+-- name: SetProjectDateCompleted :exec
+UPDATE project SET date_completed = $2, date_completed_precision = 'day', date_completed_approximate = FALSE
+WHERE uuid = $1 AND date_completed IS NULL;
+
+-- name: UpdateProjectDateAnnounced :execrows
+UPDATE project SET date_announced = $2, date_announced_precision = $3, date_announced_approximate = $4 WHERE uuid = $1;
+
+-- name: UpdateProjectDateCompleted :execrows
+UPDATE project SET date_completed = $2, date_completed_precision = $3, date_completed_approximate = $4 WHERE uuid = $1;
+
+-- name: UpdateProjectType :execrows
+UPDATE project SET type = $2 WHERE uuid = $1;
+
+-- name: NewProjectPart :one
+INSERT INTO project_part (project_id, part_number, start_ms, end_ms, status, participant_channel_id, participant_name, file_id, youtube_id, participant_artist_id)
+VALUES ((SELECT id FROM project WHERE uuid = $1), $2, $3, $4, $5, $6, $7, $8, $9, $10)
+RETURNING id;
+
+-- name: UpdateProjectPart :execrows
+UPDATE project_part SET
+    part_number = $2,
+    start_ms = $3,
+    end_ms = $4,
+    status = $5,
+    participant_channel_id = $6,
+    participant_name = $7,
+    file_id = $8,
+    youtube_id = $9,
+    participant_artist_id = $10
+WHERE id = $1;
+
+-- name: DeleteProjectPart :execrows
+DELETE FROM project_part WHERE id = $1;
+
+-- name: GetProjectPart :one
+SELECT project.uuid, project_part.*, artist.name::text AS participant_artist FROM project_part
+INNER JOIN project ON project.id = project_part.project_id
+LEFT JOIN artist ON artist.id = project_part.participant_artist_id
+WHERE project_part.id = $1;
+
+-- name: GetProjectParts :many
+SELECT project.uuid, project_part.*, artist.name::text AS participant_artist FROM project_part
+INNER JOIN project ON project.id = project_part.project_id
+LEFT JOIN artist ON artist.id = project_part.participant_artist_id
+WHERE project.uuid = $1
+ORDER BY project_part.part_number;
+
 -- name: NewYoutube :exec
 INSERT INTO youtube_video (
     id, 
@@ -82,6 +285,9 @@ INSERT INTO youtube_file (youtube_id, file_id) VALUES ($1, $2);
 -- name: GetYoutubeFileID :many
 SELECT file_id FROM youtube_file WHERE youtube_id = $1;
 
+-- name: GetFileYoutubeID :one
+SELECT youtube_id FROM youtube_file WHERE file_id = $1;
+
 -- name: GetYoutubeChannelByID :one
 SELECT 
     youtube_channel_youtube_video.channel_id AS channel_id, 
@@ -98,6 +304,18 @@ SELECT * FROM youtube_video_ytdlp_version WHERE youtube_id = $1 AND file_id = $2
 -- name: NewYoutubeYtdlpVersion :exec
 INSERT INTO youtube_video_ytdlp_version ("file_id", "youtube_id", "repository", "release_git_head", "version") VALUES ($1, $2, $3, $4, $5);
 
+-- name: NewYoutubeYtdlpInfo :exec
+INSERT INTO youtube_video_ytdlp_info (file_id, youtube_id, info) VALUES ($1, $2, $3);
+
+-- name: GetYoutubeYtdlpInfo :one
+SELECT info FROM youtube_video_ytdlp_info WHERE file_id = $1;
+
+-- name: NewYoutubeThumbnail :exec
+INSERT INTO youtube_video_thumbnail (file_id, youtube_id, thumbnail) VALUES ($1, $2, $3);
+
+-- name: GetYoutubeThumbnail :one
+SELECT thumbnail FROM youtube_video_thumbnail WHERE file_id = $1;
+
 -- name: GetProjectByYoutubeID :one
 SELECT project.* FROM project 
 INNER JOIN project_file ON project.id = project_file.project_id
@@ -125,6 +343,81 @@ VALUES ($1, $2, $3, $4, $5, $6, $7);
 -- name: GetFileVideo :one
 SELECT * FROM file_video WHERE file_id = $1;
 
+-- name: UpsertFileVideo :exec
+INSERT INTO file_video (file_id, duration, width, height, fps, video_codec, audio_codec)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+ON CONFLICT (file_id) DO UPDATE SET
+    duration = EXCLUDED.duration,
+    width = EXCLUDED.width,
+    height = EXCLUDED.height,
+    fps = EXCLUDED.fps,
+    video_codec = EXCLUDED.video_codec,
+    audio_codec = EXCLUDED.audio_codec;
+
+-- name: UpsertFileProbe :exec
+INSERT INTO file_probe (file_id, container, duration, bitrate) VALUES ($1, $2, $3, $4)
+ON CONFLICT (file_id) DO UPDATE SET
+    container = EXCLUDED.container,
+    duration = EXCLUDED.duration,
+    bitrate = EXCLUDED.bitrate,
+    date_probed = (NOW() AT TIME ZONE 'utc');
+
+-- name: GetFileProbe :one
+SELECT * FROM file_probe WHERE file_id = $1;
+
+-- name: DeleteFileProbeStreams :exec
+DELETE FROM file_probe_stream WHERE file_id = $1;
+
+-- name: NewFileProbeStream :exec
+INSERT INTO file_probe_stream (file_id, stream_index, codec_type, codec, bitrate, width, height, fps, sample_rate, channels)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10);
+
+-- name: GetFileProbeStreams :many
+SELECT * FROM file_probe_stream WHERE file_id = $1 ORDER BY stream_index;
+
+-- name: DeleteFileProbeMismatches :exec
+DELETE FROM file_probe_mismatch WHERE file_id = $1;
+
+-- name: NewFileProbeMismatch :exec
+INSERT INTO file_probe_mismatch (file_id, field, expected, actual) VALUES ($1, $2, $3, $4);
+
+-- name: GetFileProbeMismatches :many
+SELECT * FROM file_probe_mismatch WHERE file_id = $1 ORDER BY field;
+
+-- name: GetAllFileProbeMismatches :many
+SELECT * FROM file_probe_mismatch ORDER BY file_id, field;
+
+-- name: GetUnprobedFileIDs :many
+SELECT id FROM file WHERE id NOT IN (SELECT file_id FROM file_probe) ORDER BY id;
+
+-- name: GetYtdlpClaimedVideo :one
+SELECT
+    COALESCE(ROUND((info->>'duration')::numeric), 0)::integer AS duration,
+    COALESCE(ROUND((info->>'width')::numeric), 0)::smallint AS width,
+    COALESCE(ROUND((info->>'height')::numeric), 0)::smallint AS height,
+    COALESCE(TRUNC((info->>'fps')::numeric), 0)::smallint AS fps,
+    COALESCE(info->>'vcodec', '')::text AS video_codec,
+    COALESCE(info->>'acodec', '')::text AS audio_codec
+FROM youtube_video_ytdlp_info WHERE file_id = $1;
+
+-- name: UpsertFileFingerprint :exec
+INSERT INTO file_fingerprint (file_id, frame_interval, hashes) VALUES ($1, $2, $3)
+ON CONFLICT (file_id) DO UPDATE SET
+    frame_interval = EXCLUDED.frame_interval,
+    hashes = EXCLUDED.hashes,
+    date_added = (NOW() AT TIME ZONE 'utc');
+
+-- name: GetFileFingerprint :one
+SELECT * FROM file_fingerprint WHERE file_id = $1;
+
+-- name: GetFileFingerprintsByDuration :many
+SELECT * FROM file_fingerprint
+WHERE length(hashes) / 8 * frame_interval BETWEEN sqlc.arg(min_duration)::DOUBLE PRECISION AND sqlc.arg(max_duration)::DOUBLE PRECISION
+ORDER BY file_id;
+
+-- name: GetUnfingerprintedFileIDs :many
+SELECT file_id FROM file_video WHERE file_id NOT IN (SELECT file_id FROM file_fingerprint) ORDER BY file_id;
+
 -- name: GetYoutubeChannelVideos :many
 SELECT youtube_id FROM youtube_channel_youtube_video WHERE channel_id = $1;
 
@@ -132,9 +425,290 @@ SELECT youtube_id FROM youtube_channel_youtube_video WHERE channel_id = $1;
 INSERT INTO project_file (project_id, file_id) VALUES
 ((SELECT id FROM project WHERE uuid = $1), (SELECT file_id FROM youtube_file WHERE youtube_id = $2));
 
+-- name: GetProjectYoutubeIDs :many
+SELECT DISTINCT youtube_file.youtube_id FROM project_file
+INNER JOIN youtube_file ON youtube_file.file_id = project_file.file_id
+WHERE project_file.project_id = (SELECT id FROM project WHERE uuid = $1)
+ORDER BY youtube_file.youtube_id;
+
 -- name: UnassignYoutubeVideoFromProject :exec
 DELETE FROM project_file 
 WHERE 
  project_id = (SELECT id FROM project WHERE uuid = $1)
 AND
- file_id = (SELECT file_id FROM youtube_file WHERE youtube_id = $2);
\ No newline at end of file
+ file_id = (SELECT file_id FROM youtube_file WHERE youtube_id = $2);
+
+-- name: NewArtist :one
+INSERT INTO artist (name) VALUES ($1) RETURNING id;
+
+-- name: DeleteArtist :execrows
+DELETE FROM artist WHERE id = $1;
+
+-- name: GetArtistID :one
+SELECT id FROM artist
+WHERE name = $1 OR id IN (SELECT artist_id FROM artist_alias WHERE alias = $1)
+ORDER BY name = $1 DESC
+LIMIT 1;
+
+-- name: GetArtist :one
+SELECT * FROM artist WHERE id = $1;
+
+-- name: RenameArtist :exec
+UPDATE artist SET name = $2 WHERE id = $1;
+
+-- name: NewArtistAlias :exec
+INSERT INTO artist_alias (artist_id, alias) VALUES ($1, $2) ON CONFLICT (artist_id, alias) DO NOTHING;
+
+-- name: DeleteArtistAlias :execrows
+DELETE FROM artist_alias WHERE artist_id = $1 AND alias = $2;
+
+-- name: GetArtistAliases :many
+SELECT alias FROM artist_alias WHERE artist_id = $1 ORDER BY alias;
+
+-- name: AssignArtistChannel :exec
+INSERT INTO artist_channel (artist_id, channel_id) VALUES ($1, $2) ON CONFLICT DO NOTHING;
+
+-- name: UnassignArtistChannel :execrows
+DELETE FROM artist_channel WHERE artist_id = $1 AND channel_id = $2;
+
+-- name: GetArtistChannels :many
+SELECT channel_id FROM artist_channel WHERE artist_id = $1 ORDER BY channel_id;
+
+-- name: GetChannelArtists :many
+SELECT artist.name FROM artist
+INNER JOIN artist_channel ON artist_channel.artist_id = artist.id
+WHERE artist_channel.channel_id = $1
+ORDER BY artist.name;
+
+-- name: GetArtistYoutubeVideos :many
+SELECT youtube_video.id FROM youtube_video
+INNER JOIN youtube_channel_youtube_video ON youtube_channel_youtube_video.youtube_id = youtube_video.id
+INNER JOIN artist_channel ON artist_channel.channel_id = youtube_channel_youtube_video.channel_id
+WHERE artist_channel.artist_id = $1
+ORDER BY youtube_video.upload_date, youtube_video.id;
+
+-- name: GetArtistProjectParts :many
+SELECT project.uuid, project_part.*, artist.name::text AS participant_artist FROM project_part
+INNER JOIN project ON project.id = project_part.project_id
+LEFT JOIN artist ON artist.id = project_part.participant_artist_id
+WHERE project_part.participant_artist_id = $1
+   OR project_part.participant_channel_id IN (SELECT channel_id FROM artist_channel WHERE artist_channel.artist_id = $1)
+   OR project_part.participant_name IN (
+        SELECT artist.name::text FROM artist WHERE artist.id = $1
+        UNION SELECT artist_alias.alias::text FROM artist_alias WHERE artist_alias.artist_id = $1
+   )
+ORDER BY project.uuid, project_part.part_number;
+
+-- name: NewMusic :one
+INSERT INTO music (artist, title) VALUES ($1, $2) RETURNING id;
+
+-- name: UpdateMusic :execrows
+UPDATE music SET artist = $2, title = $3 WHERE id = $1;
+
+-- name: DeleteMusic :execrows
+DELETE FROM music WHERE id = $1;
+
+-- name: GetMusic :one
+SELECT * FROM music WHERE id = $1;
+
+-- name: FindMusic :many
+SELECT id, artist, title, score FROM (
+    SELECT music.*, (
+        CASE WHEN sqlc.arg(artist)::text = '' THEN 1 ELSE word_similarity(sqlc.arg(artist)::text, artist::text) END *
+        CASE WHEN sqlc.arg(title)::text = '' THEN 1 ELSE word_similarity(sqlc.arg(title)::text, title::text) END
+    )::DOUBLE PRECISION AS score FROM music
+) AS matches
+WHERE score >= sqlc.arg(min_score)::DOUBLE PRECISION
+ORDER BY score DESC, artist, title
+LIMIT sqlc.arg(max_results)::INTEGER;
+
+-- name: AssignProjectMusic :exec
+INSERT INTO project_music (project_id, music_id) VALUES ((SELECT id FROM project WHERE uuid = $1), $2)
+ON CONFLICT DO NOTHING;
+
+-- name: UnassignProjectMusic :execrows
+DELETE FROM project_music WHERE project_id = (SELECT id FROM project WHERE uuid = $1) AND music_id = $2;
+
+-- name: GetProjectMusic :many
+SELECT music.* FROM music
+INNER JOIN project_music ON project_music.music_id = music.id
+WHERE project_music.project_id = (SELECT id FROM project WHERE uuid = $1)
+ORDER BY music.artist, music.title;
+
+-- name: GetMusicProjects :many
+SELECT project.uuid FROM project
+INNER JOIN project_music ON project_music.project_id = project.id
+WHERE project_music.music_id = $1
+ORDER BY project.date_archived, project.id;
+
+-- name: NewCharacter :one
+INSERT INTO character (name, series, is_original) VALUES ($1, $2, $3) RETURNING id;
+
+-- name: UpdateCharacter :execrows
+UPDATE character SET name = $2, series = $3, is_original = $4 WHERE id = $1;
+
+-- name: DeleteCharacter :execrows
+DELETE FROM character WHERE id = $1;
+
+-- name: GetCharacter :one
+SELECT * FROM character WHERE id = $1;
+
+-- name: NewCharacterAlias :exec
+INSERT INTO character_alias (character_id, name, stage) VALUES ($1, $2, $3)
+ON CONFLICT (character_id, name) DO UPDATE SET stage = EXCLUDED.stage;
+
+-- name: DeleteCharacterAlias :execrows
+DELETE FROM character_alias WHERE character_id = $1 AND name = $2;
+
+-- name: GetCharacterAliases :many
+SELECT name, stage FROM character_alias WHERE character_id = $1 ORDER BY stage, name;
+
+-- name: FindCharacters :many
+SELECT * FROM character
+WHERE name = $1 OR id IN (SELECT character_id FROM character_alias WHERE character_alias.name = $1)
+ORDER BY name, series, id;
+
+-- name: GetSeriesCharacters :many
+SELECT * FROM character WHERE series = $1 ORDER BY name, id;
+
+-- name: TagProjectCharacter :exec
+INSERT INTO project_character (project_id, character_id) VALUES ((SELECT id FROM project WHERE uuid = $1), $2)
+ON CONFLICT DO NOTHING;
+
+-- name: UntagProjectCharacter :execrows
+DELETE FROM project_character WHERE project_id = (SELECT id FROM project WHERE uuid = $1) AND character_id = $2;
+
+-- name: GetProjectCharacters :many
+SELECT character.* FROM character
+INNER JOIN project_character ON project_character.character_id = character.id
+WHERE project_character.project_id = (SELECT id FROM project WHERE uuid = $1)
+ORDER BY character.name, character.id;
+
+-- name: TagPartCharacter :exec
+INSERT INTO project_part_character (part_id, character_id) VALUES ($1, $2) ON CONFLICT DO NOTHING;
+
+-- name: UntagPartCharacter :execrows
+DELETE FROM project_part_character WHERE part_id = $1 AND character_id = $2;
+
+-- name: GetPartCharacters :many
+SELECT character.* FROM character
+INNER JOIN project_part_character ON project_part_character.character_id = character.id
+WHERE project_part_character.part_id = $1
+ORDER BY character.name, character.id;
+
+-- name: GetCharacterProjects :many
+SELECT project.uuid FROM project
+WHERE project.id IN (SELECT project_id FROM project_character WHERE project_character.character_id = $1)
+   OR project.id IN (
+        SELECT project_part.project_id FROM project_part
+        INNER JOIN project_part_character ON project_part_character.part_id = project_part.id
+        WHERE project_part_character.character_id = $1
+   )
+ORDER BY project.date_archived, project.id;
+
+-- name: Search :many
+WITH query AS (
+    SELECT websearch_to_tsquery('english', sqlc.arg(query)::text) AS q
+), matches AS (
+    SELECT DISTINCT ON (kind, id) kind, id, text, rank FROM (
+        SELECT 'youtube' AS kind, youtube_title.youtube_id::text AS id, youtube_title.title AS text,
+            ts_rank(to_tsvector('english', youtube_title.title), query.q) AS rank
+        FROM youtube_title, query WHERE to_tsvector('english', youtube_title.title) @@ query.q
+        UNION ALL
+        SELECT 'youtube', youtube_description.youtube_id::text, youtube_description.description,
+            ts_rank(to_tsvector('english', youtube_description.description), query.q) / 2
+        FROM youtube_description, query WHERE to_tsvector('english', youtube_description.description) @@ query.q
+        UNION ALL
+        SELECT 'channel', youtube_channel_uploader_name.channel_id::text, youtube_channel_uploader_name.uploader,
+            ts_rank(to_tsvector('english', youtube_channel_uploader_name.uploader), query.q)
+        FROM youtube_channel_uploader_name, query WHERE to_tsvector('english', youtube_channel_uploader_name.uploader) @@ query.q
+        UNION ALL
+        SELECT 'project', project.uuid, project_title.title,
+            ts_rank(to_tsvector('english', project_title.title), query.q)
+        FROM project_title INNER JOIN project ON project.id = project_title.project_id, query
+        WHERE to_tsvector('english', project_title.title) @@ query.q
+        UNION ALL
+        SELECT 'artist', artist.id::text, artist.name::text,
+            ts_rank(to_tsvector('english', artist.name::text), query.q)
+        FROM artist, query WHERE to_tsvector('english', artist.name::text) @@ query.q
+        UNION ALL
+        SELECT 'artist', artist_alias.artist_id::text, artist_alias.alias::text,
+            ts_rank(to_tsvector('english', artist_alias.alias::text), query.q)
+        FROM artist_alias, query WHERE to_tsvector('english', artist_alias.alias::text) @@ query.q
+        UNION ALL
+        SELECT 'music', music.id::text, music.artist::text || ' - ' || music.title::text,
+            ts_rank(to_tsvector('english', music.artist::text || ' ' || music.title::text), query.q)
+        FROM music, query WHERE to_tsvector('english', music.artist::text || ' ' || music.title::text) @@ query.q
+        UNION ALL
+        SELECT 'character', character.id::text, character.name::text || ' (' || character.series::text || ')',
+            ts_rank(to_tsvector('english', character.name::text || ' ' || character.series::text), query.q)
+        FROM character, query WHERE to_tsvector('english', character.name::text || ' ' || character.series::text) @@ query.q
+        UNION ALL
+        SELECT 'character', character_alias.character_id::text, character_alias.name::text,
+            ts_rank(to_tsvector('english', character_alias.name::text), query.q)
+        FROM character_alias, query WHERE to_tsvector('english', character_alias.name::text) @@ query.q
+    ) AS m
+    WHERE sqlc.arg(kind)::text = '' OR m.kind = sqlc.arg(kind)::text
+    ORDER BY kind, id, rank DESC
+)
+SELECT
+    matches.kind::text AS kind,
+    matches.id::text AS id,
+    COALESCE(CASE matches.kind
+        WHEN 'youtube' THEN (SELECT title FROM youtube_title WHERE youtube_title.youtube_id = matches.id ORDER BY date_added DESC LIMIT 1)
+        WHEN 'project' THEN (SELECT title FROM project_title WHERE project_title.project_id = (SELECT id FROM project WHERE uuid = matches.id) ORDER BY date_added DESC, project_title.id DESC LIMIT 1)
+        WHEN 'artist' THEN (SELECT name::text FROM artist WHERE artist.id = matches.id::BIGINT)
+        WHEN 'character' THEN (SELECT name::text FROM character WHERE character.id = matches.id::INTEGER)
+    END, matches.text)::text AS title,
+    ts_headline('english', translate(matches.text, chr(2) || chr(3), ''), query.q,
+        'StartSel=' || chr(2) || ', StopSel=' || chr(3) || ', MaxFragments=2, MaxWords=24, MinWords=8')::text AS headline,
+    matches.rank::DOUBLE PRECISION AS rank
+FROM matches, query
+ORDER BY matches.rank DESC, matches.kind, matches.id
+LIMIT sqlc.arg(max_results)::INTEGER;
+
+-- name: NewAuditEntry :one
+INSERT INTO audit_log (actor, entity, entity_id, action, before, after) VALUES ($1, $2, $3, $4, $5, $6) RETURNING id;
+
+-- name: GetAuditEntry :one
+SELECT * FROM audit_log WHERE id = $1;
+
+-- name: GetAuditEntries :many
+SELECT * FROM audit_log WHERE entity = $1 AND entity_id = $2 ORDER BY id;
+
+-- name: GetYoutubeWithoutFiles :many
+SELECT id::text AS youtube_id FROM youtube_video
+WHERE id NOT IN (SELECT youtube_id FROM youtube_file)
+ORDER BY id;
+
+-- name: GetFilesWithoutVideo :many
+SELECT id FROM file
+WHERE id NOT IN (SELECT file_id FROM file_video)
+ORDER BY id;
+
+-- name: GetUnassignedDownloads :many
+SELECT download.file_id, download.youtube_id::text AS youtube_id FROM (
+	SELECT file_id, youtube_id FROM youtube_video_format
+	UNION SELECT file_id, youtube_id FROM youtube_video_ytdlp_info
+	UNION SELECT file_id, youtube_id FROM youtube_video_ytdlp_version
+	UNION SELECT file_id, youtube_id FROM youtube_video_thumbnail
+) AS download
+WHERE download.file_id NOT IN (SELECT file_id FROM youtube_file)
+ORDER BY download.file_id, download.youtube_id;
+
+-- name: GetMisattributedDownloads :many
+SELECT download.source, download.file_id, download.youtube_id::text AS youtube_id, youtube_file.youtube_id::text AS assigned_youtube_id FROM (
+	SELECT 'youtube_video_format'::text AS source, file_id, youtube_id FROM youtube_video_format
+	UNION ALL SELECT 'youtube_video_ytdlp_info'::text, file_id, youtube_id FROM youtube_video_ytdlp_info
+	UNION ALL SELECT 'youtube_video_ytdlp_version'::text, file_id, youtube_id FROM youtube_video_ytdlp_version
+	UNION ALL SELECT 'youtube_video_thumbnail'::text, file_id, youtube_id FROM youtube_video_thumbnail
+) AS download
+INNER JOIN youtube_file ON youtube_file.file_id = download.file_id
+WHERE youtube_file.youtube_id != download.youtube_id
+ORDER BY download.file_id, download.source;
+
+-- name: GetProjectsWithoutFiles :many
+SELECT uuid FROM project
+WHERE id NOT IN (SELECT project_id FROM project_file)
+AND id NOT IN (SELECT project_id FROM project_part WHERE file_id IS NOT NULL)
+ORDER BY uuid;
@@ -25,6 +25,74 @@ CREATE TABLE "file" (
 	PRIMARY KEY("id")
 );
 
+CREATE TYPE PartStatus AS ENUM (
+	'unknown',
+	'open',
+	'claimed',
+	'completed',
+	'dropped'
+);
+
+CREATE TYPE ProjectRelationType AS ENUM (
+	'part-of',
+	'backup-of',
+	'sequel-of',
+	'reupload-of'
+);
+
+-- ProjectStatus is where a project is in its life. A cancelled project may be revived, after which it goes through
+-- the same statuses again.
+CREATE TYPE ProjectStatus AS ENUM (
+	'announced',
+	'sign-ups open',
+	'parts assigned',
+	'in progress',
+	'completed',
+	'cancelled',
+	'revived'
+);
+
+-- DatePrecision is how much of a partial date is known. A partial date is stored as the start of the year, month or day
+-- it names, along with its precision.
+CREATE TYPE DatePrecision AS ENUM (
+	'year',
+	'month',
+	'day'
+);
+
+-- partial_date_range is the period a partial date covers, from its start up to the start of the next year, month or
+-- day. It is NULL for an unknown date.
+CREATE FUNCTION partial_date_range(TIMESTAMP, DatePrecision) RETURNS TSRANGE
+LANGUAGE SQL IMMUTABLE STRICT AS $$
+	SELECT tsrange($1, $1 + CASE $2 WHEN 'year' THEN INTERVAL '1 year' WHEN 'month' THEN INTERVAL '1 month' ELSE INTERVAL '1 day' END)
+$$;
+
+-- CharacterNameStage is the stage of life a character went by a name in, such as Firepaw being Firestar's apprentice
+-- name. Names that don't follow the naming ceremonies, like a kittypet name, are 'other'.
+CREATE TYPE CharacterNameStage AS ENUM (
+	'kit',
+	'apprentice',
+	'warrior',
+	'leader',
+	'other'
+);
+
+CREATE TYPE FileIntentAction AS ENUM (
+	'add',
+	'delete'
+);
+
+-- file_intent journals changes to the storage root that haven't been matched by the "file" table yet. A row is
+-- committed before a file gets moved into place, and alongside a "file" row being deleted, so that a crash between the
+-- database and the filesystem can be resolved at startup.
+CREATE TABLE "file_intent" (
+	"id" BIGINT NOT NULL UNIQUE GENERATED ALWAYS AS IDENTITY,
+	"action" FileIntentAction NOT NULL,
+	"path" TEXT NOT NULL CHECK (path != ''),
+	"date_added" TIMESTAMP NOT NULL DEFAULT (NOW() AT TIME ZONE 'utc'),
+	PRIMARY KEY("id")
+);
+
 CREATE TABLE "file_video" (
 	"file_id" BIGINT NOT NULL UNIQUE,
 	"duration" INTEGER NOT NULL CHECK (duration >= 0),
@@ -37,6 +105,58 @@ CREATE TABLE "file_video" (
 	ON UPDATE CASCADE ON DELETE CASCADE
 );
 
+-- file_probe holds what ffprobe reports about the actual file on disk, as opposed to what yt-dlp claimed it downloaded.
+CREATE TABLE "file_probe" (
+	"file_id" BIGINT NOT NULL UNIQUE,
+	"container" TEXT NOT NULL,
+	"duration" DOUBLE PRECISION NOT NULL CHECK (duration >= 0),
+	"bitrate" BIGINT CHECK (bitrate >= 0),
+	"date_probed" TIMESTAMP NOT NULL DEFAULT (NOW() AT TIME ZONE 'utc'),
+	PRIMARY KEY("file_id"),
+	FOREIGN KEY ("file_id") REFERENCES file("id")
+	ON UPDATE CASCADE ON DELETE CASCADE
+);
+
+CREATE TABLE "file_probe_stream" (
+	"file_id" BIGINT NOT NULL,
+	"stream_index" SMALLINT NOT NULL CHECK (stream_index >= 0),
+	"codec_type" TEXT NOT NULL,
+	"codec" TEXT,
+	"bitrate" BIGINT CHECK (bitrate >= 0),
+	"width" SMALLINT CHECK (width >= 0),
+	"height" SMALLINT CHECK (height >= 0),
+	"fps" DOUBLE PRECISION CHECK (fps >= 0),
+	"sample_rate" INTEGER CHECK (sample_rate >= 0),
+	"channels" SMALLINT CHECK (channels >= 0),
+	UNIQUE("file_id", "stream_index"),
+	PRIMARY KEY("file_id", "stream_index"),
+	FOREIGN KEY ("file_id") REFERENCES file("id")
+	ON UPDATE CASCADE ON DELETE CASCADE
+);
+
+CREATE TABLE "file_probe_mismatch" (
+	"file_id" BIGINT NOT NULL,
+	"field" TEXT NOT NULL,
+	"expected" TEXT NOT NULL,
+	"actual" TEXT NOT NULL,
+	"date_added" TIMESTAMP NOT NULL DEFAULT (NOW() AT TIME ZONE 'utc'),
+	UNIQUE("file_id", "field"),
+	PRIMARY KEY("file_id", "field"),
+	FOREIGN KEY ("file_id") REFERENCES file("id")
+	ON UPDATE CASCADE ON DELETE CASCADE
+);
+
+-- hashes holds one big-endian 64-bit perceptual hash per sampled frame, frame_interval seconds apart.
+CREATE TABLE "file_fingerprint" (
+	"file_id" BIGINT NOT NULL UNIQUE,
+	"frame_interval" DOUBLE PRECISION NOT NULL CHECK (frame_interval > 0),
+	"hashes" BYTEA NOT NULL CHECK (length(hashes) % 8 = 0),
+	"date_added" TIMESTAMP NOT NULL DEFAULT (NOW() AT TIME ZONE 'utc'),
+	PRIMARY KEY("file_id"),
+	FOREIGN KEY ("file_id") REFERENCES file("id")
+	ON UPDATE CASCADE ON DELETE CASCADE
+);
+
 CREATE TABLE "youtube_channel" (
 	"id" YoutubeChannelID NOT NULL UNIQUE DEFAULT ('UC000000000000000000000A'),
 	PRIMARY KEY("id")
@@ -163,35 +283,67 @@ CREATE TABLE "youtube_video_ytdlp_version" (
 	ON UPDATE CASCADE ON DELETE CASCADE
 );
 
+-- youtube_video_ytdlp_info holds the info json yt-dlp printed when downloading file_id, kept exactly as printed.
+CREATE TABLE "youtube_video_ytdlp_info" (
+	"file_id" BIGINT NOT NULL UNIQUE,
+	"youtube_id" YoutubeVideoID NOT NULL,
+	"info" JSON NOT NULL,
+	PRIMARY KEY("file_id", "youtube_id"),
+	FOREIGN KEY("file_id") REFERENCES "file"("id")
+	ON UPDATE CASCADE ON DELETE CASCADE,
+	FOREIGN KEY ("youtube_id") REFERENCES "youtube_video"("id")
+	ON UPDATE CASCADE ON DELETE CASCADE
+);
+
+-- youtube_video_thumbnail holds the thumbnail yt-dlp wrote when downloading file_id, converted to a jpeg.
+CREATE TABLE "youtube_video_thumbnail" (
+	"file_id" BIGINT NOT NULL UNIQUE,
+	"youtube_id" YoutubeVideoID NOT NULL,
+	"thumbnail" BYTEA NOT NULL CHECK (length(thumbnail) > 0),
+	PRIMARY KEY("file_id", "youtube_id"),
+	FOREIGN KEY("file_id") REFERENCES "file"("id")
+	ON UPDATE CASCADE ON DELETE CASCADE,
+	FOREIGN KEY ("youtube_id") REFERENCES "youtube_video"("id")
+	ON UPDATE CASCADE ON DELETE CASCADE
+);
+
 CREATE TABLE "project" (
 	"id" BIGINT NOT NULL UNIQUE GENERATED ALWAYS AS IDENTITY,
 	"uuid" TEXT NOT NULL UNIQUE CHECK (uuid != ''),
 	"type" ProjectType NOT NULL DEFAULT 'unknown',
 	"date_announced" TIMESTAMP,
+	"date_announced_precision" DatePrecision,
+	"date_announced_approximate" BOOLEAN NOT NULL DEFAULT FALSE,
 	"date_completed" TIMESTAMP,
+	"date_completed_precision" DatePrecision,
+	"date_completed_approximate" BOOLEAN NOT NULL DEFAULT FALSE,
 	"date_archived" TIMESTAMP NOT NULL DEFAULT (NOW() AT TIME ZONE 'utc'),
+	CHECK ((date_announced IS NULL) = (date_announced_precision IS NULL)),
+	CHECK ((date_completed IS NULL) = (date_completed_precision IS NULL)),
 	PRIMARY KEY("id")
 );
 
+-- project_title and project_description keep every revision, the newest being the current one. Reverting to an
+-- earlier revision adds it again, so the same title may appear more than once per project.
 CREATE TABLE "project_title" (
+	"id" BIGINT NOT NULL UNIQUE GENERATED ALWAYS AS IDENTITY,
 	"project_id" BIGINT NOT NULL,
-	"title" TEXT NOT NULL,
-	"title_md5" BYTEA NOT NULL UNIQUE CHECK (length(title_md5) = 16),
+	"title" TEXT NOT NULL CHECK (title != ''),
+	"title_md5" BYTEA NOT NULL CHECK (length(title_md5) = 16),
 	"date_added" TIMESTAMP NOT NULL DEFAULT (NOW() AT TIME ZONE 'utc'), 
-	UNIQUE("project_id", "title_md5"),
-	PRIMARY KEY ("project_id", "title_md5"),
+	PRIMARY KEY ("id"),
 	FOREIGN KEY ("project_id") REFERENCES "project"("id")
 	ON UPDATE CASCADE ON DELETE CASCADE
 );
 
 
 CREATE TABLE "project_description" (
-	"project_id" BIGINT NOT NULL UNIQUE GENERATED ALWAYS AS IDENTITY,
+	"id" BIGINT NOT NULL UNIQUE GENERATED ALWAYS AS IDENTITY,
+	"project_id" BIGINT NOT NULL,
 	"description" TEXT NOT NULL,
-	"description_md5" BYTEA NOT NULL UNIQUE CHECK (length(description_md5) = 16),
+	"description_md5" BYTEA NOT NULL CHECK (length(description_md5) = 16),
 	"date_added" TIMESTAMP NOT NULL DEFAULT (NOW() AT TIME ZONE 'utc'), 
-	UNIQUE("project_id", "description_md5"),
-	PRIMARY KEY ("project_id", "description_md5"),
+	PRIMARY KEY ("id"),
 	FOREIGN KEY ("project_id") REFERENCES "project"("id")
 	ON UPDATE CASCADE ON DELETE CASCADE
 );
@@ -219,12 +371,104 @@ CREATE TABLE "project_participant" (
 	ON UPDATE CASCADE ON DELETE CASCADE
 );
 
+-- project_relation reads as "project_id is a <type> related_project_id", such as a part project being part-of its MAP.
+-- Relations of the same type never form a cycle, which is checked on insert.
+CREATE TABLE "project_relation" (
+	"project_id" BIGINT NOT NULL,
+	"related_project_id" BIGINT NOT NULL CHECK (related_project_id != project_id),
+	"type" ProjectRelationType NOT NULL,
+	"date_added" TIMESTAMP NOT NULL DEFAULT (NOW() AT TIME ZONE 'utc'),
+	UNIQUE("project_id", "related_project_id", "type"),
+	PRIMARY KEY("project_id", "related_project_id", "type"),
+	FOREIGN KEY ("project_id") REFERENCES "project"("id")
+	ON UPDATE CASCADE ON DELETE CASCADE,
+	FOREIGN KEY ("related_project_id") REFERENCES "project"("id")
+	ON UPDATE CASCADE ON DELETE CASCADE
+);
+
+-- a part belongs to a single MAP
+CREATE UNIQUE INDEX "project_relation_part_of" ON "project_relation" ("project_id") WHERE type = 'part-of';
+
+-- project_status is the timeline of a project, its latest entry being its current status. date is when the project
+-- changed status, as opposed to date_added being when that was recorded.
+CREATE TABLE "project_status" (
+	"id" BIGINT NOT NULL UNIQUE GENERATED ALWAYS AS IDENTITY,
+	"project_id" BIGINT NOT NULL,
+	"status" ProjectStatus NOT NULL,
+	"date" TIMESTAMP NOT NULL,
+	"note" TEXT NOT NULL DEFAULT '',
+	"date_added" TIMESTAMP NOT NULL DEFAULT (NOW() AT TIME ZONE 'utc'),
+	PRIMARY KEY("id"),
+	FOREIGN KEY ("project_id") REFERENCES "project"("id")
+	ON UPDATE CASCADE ON DELETE CASCADE
+);
+
+CREATE INDEX "project_status_project_id" ON "project_status" ("project_id", "date");
+
 CREATE EXTENSION IF NOT EXISTS citext;
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- artist is a person behind one or more youtube channels. Animators move between channels and rename themselves, so
+-- every other name an artist has gone by is kept as an alias, and a channel may be shared by several artists.
+CREATE TABLE "artist" (
+	"id" BIGINT NOT NULL UNIQUE GENERATED ALWAYS AS IDENTITY,
+	"name" citext NOT NULL UNIQUE CHECK (name != ''),
+	"date_added" TIMESTAMP NOT NULL DEFAULT (NOW() AT TIME ZONE 'utc'),
+	PRIMARY KEY("id")
+);
+
+CREATE TABLE "artist_alias" (
+	"artist_id" BIGINT NOT NULL,
+	"alias" citext NOT NULL UNIQUE CHECK (alias != ''),
+	PRIMARY KEY("artist_id", "alias"),
+	FOREIGN KEY ("artist_id") REFERENCES "artist"("id")
+	ON UPDATE CASCADE ON DELETE CASCADE
+);
+
+CREATE TABLE "artist_channel" (
+	"artist_id" BIGINT NOT NULL,
+	"channel_id" YoutubeChannelID NOT NULL,
+	UNIQUE("artist_id", "channel_id"),
+	PRIMARY KEY("artist_id", "channel_id"),
+	FOREIGN KEY ("artist_id") REFERENCES "artist"("id")
+	ON UPDATE CASCADE ON DELETE CASCADE,
+	FOREIGN KEY ("channel_id") REFERENCES "youtube_channel"("id")
+	ON UPDATE CASCADE ON DELETE CASCADE
+);
+
+-- project_part is a single numbered part of a MAP, occupying start_ms to end_ms of the finished video. Its participant
+-- is any of a youtube channel, an artist and a name. file_id and youtube_id point to the part's standalone upload, if
+-- any.
+CREATE TABLE "project_part" (
+	"id" BIGINT NOT NULL UNIQUE GENERATED ALWAYS AS IDENTITY,
+	"project_id" BIGINT NOT NULL,
+	"part_number" SMALLINT NOT NULL CHECK (part_number >= 0),
+	"start_ms" INTEGER CHECK (start_ms >= 0),
+	"end_ms" INTEGER CHECK (end_ms > start_ms),
+	"status" PartStatus NOT NULL DEFAULT 'unknown',
+	"participant_channel_id" TEXT,
+	"participant_name" TEXT CHECK (participant_name != ''),
+	"file_id" BIGINT,
+	"youtube_id" TEXT,
+	"participant_artist_id" BIGINT,
+	UNIQUE("project_id", "part_number"),
+	PRIMARY KEY("id"),
+	FOREIGN KEY ("project_id") REFERENCES "project"("id")
+	ON UPDATE CASCADE ON DELETE CASCADE,
+	FOREIGN KEY ("participant_channel_id") REFERENCES "youtube_channel"("id")
+	ON UPDATE CASCADE ON DELETE SET NULL,
+	FOREIGN KEY ("file_id") REFERENCES "file"("id")
+	ON UPDATE CASCADE ON DELETE SET NULL,
+	FOREIGN KEY ("youtube_id") REFERENCES "youtube_video"("id")
+	ON UPDATE CASCADE ON DELETE SET NULL,
+	FOREIGN KEY ("participant_artist_id") REFERENCES "artist"("id")
+	ON UPDATE CASCADE ON DELETE SET NULL
+);
 
 CREATE TABLE "music" (
 	"id" INTEGER NOT NULL UNIQUE GENERATED ALWAYS AS IDENTITY,
-	"artist" citext NOT NULL,
-	"title" citext NOT NULL,
+	"artist" citext NOT NULL CHECK (artist != ''),
+	"title" citext NOT NULL CHECK (title != ''),
 	UNIQUE("artist", "title"),
 	PRIMARY KEY("id")
 );
@@ -241,15 +485,29 @@ CREATE TABLE "project_music" (
 );
 
 
+-- character is known by name, and any other name it went by is kept in character_alias. series is the universe the
+-- character comes from, and is_original marks fan-made characters.
 CREATE TABLE "character" (
 	"id" INTEGER NOT NULL UNIQUE GENERATED ALWAYS AS IDENTITY,
-	"name" TEXT NOT NULL,
-	"series" TEXT NOT NULL,
+	"name" citext NOT NULL CHECK (name != ''),
+	"series" citext NOT NULL CHECK (series != ''),
 	"is_original" BOOLEAN NOT NULL DEFAULT (false),
 	UNIQUE("name", "series", "is_original"),
 	PRIMARY KEY("id")
 );
 
+CREATE TABLE "character_alias" (
+	"character_id" INTEGER NOT NULL,
+	"name" citext NOT NULL CHECK (name != ''),
+	"stage" CharacterNameStage NOT NULL DEFAULT 'other',
+	UNIQUE("character_id", "name"),
+	PRIMARY KEY("character_id", "name"),
+	FOREIGN KEY ("character_id") REFERENCES "character"("id")
+	ON UPDATE CASCADE ON DELETE CASCADE
+);
+
+CREATE INDEX "character_alias_name" ON "character_alias" ("name");
+
 
 CREATE TABLE "project_character" (
 	"project_id" INTEGER NOT NULL,
@@ -260,4 +518,48 @@ CREATE TABLE "project_character" (
 	ON UPDATE CASCADE ON DELETE CASCADE,
 	FOREIGN KEY ("character_id") REFERENCES "character"("id")
 	ON UPDATE CASCADE ON DELETE CASCADE
-);
\ No newline at end of file
+);
+
+CREATE TABLE "project_part_character" (
+	"part_id" BIGINT NOT NULL,
+	"character_id" INTEGER NOT NULL,
+	UNIQUE("part_id", "character_id"),
+	PRIMARY KEY("part_id", "character_id"),
+	FOREIGN KEY ("part_id") REFERENCES "project_part"("id")
+	ON UPDATE CASCADE ON DELETE CASCADE,
+	FOREIGN KEY ("character_id") REFERENCES "character"("id")
+	ON UPDATE CASCADE ON DELETE CASCADE
+);
+
+-- full-text search indexes. Search has to use the exact same to_tsvector expressions for these to be used.
+CREATE INDEX "youtube_title_search" ON "youtube_title" USING GIN (to_tsvector('english', title));
+CREATE INDEX "youtube_description_search" ON "youtube_description" USING GIN (to_tsvector('english', description));
+CREATE INDEX "youtube_channel_uploader_name_search" ON "youtube_channel_uploader_name" USING GIN (to_tsvector('english', uploader));
+CREATE INDEX "project_title_search" ON "project_title" USING GIN (to_tsvector('english', title));
+CREATE INDEX "artist_search" ON "artist" USING GIN (to_tsvector('english', name::text));
+CREATE INDEX "artist_alias_search" ON "artist_alias" USING GIN (to_tsvector('english', alias::text));
+CREATE INDEX "music_search" ON "music" USING GIN (to_tsvector('english', artist::text || ' ' || title::text));
+CREATE INDEX "character_search" ON "character" USING GIN (to_tsvector('english', name::text || ' ' || series::text));
+CREATE INDEX "character_alias_search" ON "character_alias" USING GIN (to_tsvector('english', name::text));
+
+CREATE TYPE AuditEntity AS ENUM (
+	'project',
+	'file',
+	'youtube'
+);
+
+-- audit_log records every change made through the service layer, before and after being JSON of whatever the change
+-- touched. It deliberately has no foreign keys, so that the history of something outlives it being deleted.
+CREATE TABLE "audit_log" (
+	"id" BIGINT NOT NULL UNIQUE GENERATED ALWAYS AS IDENTITY,
+	"actor" TEXT NOT NULL CHECK (actor != ''),
+	"entity" AuditEntity NOT NULL,
+	"entity_id" TEXT NOT NULL CHECK (entity_id != ''),
+	"action" TEXT NOT NULL CHECK (action != ''),
+	"before" JSONB NOT NULL DEFAULT 'null',
+	"after" JSONB NOT NULL DEFAULT 'null',
+	"date_added" TIMESTAMP NOT NULL DEFAULT (NOW() AT TIME ZONE 'utc'),
+	PRIMARY KEY("id")
+);
+
+CREATE INDEX "audit_log_entity" ON "audit_log" ("entity", "entity_id", "id");
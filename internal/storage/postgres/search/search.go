@@ -0,0 +1,59 @@
+package search
+
+import (
+	"context"
+	"database/sql"
+	"html"
+	"strings"
+
+	"github.com/dtbead/wc-maps-archive/internal/entities"
+	"github.com/dtbead/wc-maps-archive/internal/storage/postgres/queries"
+)
+
+type SearchRepository struct {
+	db *sql.DB
+	q  *queries.Queries
+}
+
+func NewSearchRepository(db *sql.DB) *SearchRepository {
+	return &SearchRepository{
+		db: db,
+		q:  queries.New(db),
+	}
+}
+
+// headlineMarks turns the marks the Search query wraps matched words in into markup. The query marks them with the control
+// characters STX and ETX, which it strips from the text beforehand, so that the text can be escaped first.
+var headlineMarks = strings.NewReplacer("\x02", "<b>", "\x03", "</b>")
+
+// Search runs query, written like a web search (quoted phrases, "or" and -excluded words), against every youtube
+// title and description a video has had, uploader names, project titles, artists, music and characters. kind limits
+// the results to a single kind unless it's entities.SearchKindAny. At most max_results are returned, best first.
+func (s SearchRepository) Search(ctx context.Context, query string, kind entities.SearchKind, max_results int) (results []entities.SearchResult, err error) {
+	res, err := s.q.Search(ctx, queries.SearchParams{
+		Query:      query,
+		Kind:       kind.ToString(),
+		MaxResults: int32(max_results),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	results = make([]entities.SearchResult, 0, len(res))
+	for _, v := range res {
+		kind, err := entities.NewSearchKind(v.Kind)
+		if err != nil {
+			return nil, err
+		}
+
+		results = append(results, entities.SearchResult{
+			Kind:     kind,
+			ID:       v.ID,
+			Title:    v.Title,
+			Headline: headlineMarks.Replace(html.EscapeString(v.Headline)),
+			Rank:     v.Rank,
+		})
+	}
+
+	return results, nil
+}
@@ -0,0 +1,136 @@
+package search_test
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/dtbead/wc-maps-archive/internal/entities"
+	"github.com/dtbead/wc-maps-archive/internal/helper"
+	helper_test "github.com/dtbead/wc-maps-archive/internal/helper/testing"
+	"github.com/dtbead/wc-maps-archive/internal/storage/postgres/character"
+	"github.com/dtbead/wc-maps-archive/internal/storage/postgres/music"
+	"github.com/dtbead/wc-maps-archive/internal/storage/postgres/project"
+	"github.com/dtbead/wc-maps-archive/internal/storage/postgres/search"
+)
+
+func TestSearchRepository_Search(t *testing.T) {
+	db := helper_test.NewDatabase(&helper_test.DefaultConnection)
+	defer db.Close()
+
+	searchRepo := search.NewSearchRepository(db)
+	projectRepo := project.NewProjectRepository(db)
+	musicRepo := music.NewMusicRepository(db)
+	characterRepo := character.NewCharacterRepository(db)
+	ctx := context.Background()
+
+	uuid, err := projectRepo.NewProject(ctx, &entities.Project{
+		UUID:        helper.RandomUUID(),
+		ProjectType: entities.ProjectMultiAnimation,
+	})
+	if err != nil {
+		t.Fatalf("failed to create mock project, %v", err)
+	}
+
+	if err := projectRepo.NewProjectTitle(ctx, uuid, "Ashfur's Hatred MAP"); err != nil {
+		t.Fatalf("failed to create mock project title, %v", err)
+	}
+
+	music_id, err := musicRepo.NewMusic(ctx, &entities.Music{Artist: "Ruelle", Title: "Hatred Burns"})
+	if err != nil {
+		t.Fatalf("failed to create mock music, %v", err)
+	}
+
+	character_id, err := characterRepo.NewCharacter(ctx, &entities.Character{
+		Name:    "Ashfur",
+		Series:  "Warriors",
+		Aliases: []entities.CharacterAlias{{Name: "Ashpaw", Stage: entities.NameStageApprentice}},
+	})
+	if err != nil {
+		t.Fatalf("failed to create mock character, %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		query string
+		kind  entities.SearchKind
+		want  []entities.SearchKind
+	}{
+		{"any kind", "hatred", entities.SearchKindAny, []entities.SearchKind{entities.SearchKindProject, entities.SearchKindMusic}},
+		{"single kind", "hatred", entities.SearchKindMusic, []entities.SearchKind{entities.SearchKindMusic}},
+		{"stemmed", "burning", entities.SearchKindAny, []entities.SearchKind{entities.SearchKindMusic}},
+		{"excluded word", "hatred -ruelle", entities.SearchKindAny, []entities.SearchKind{entities.SearchKindProject}},
+		{"alias", "ashpaw", entities.SearchKindAny, []entities.SearchKind{entities.SearchKindCharacter}},
+		{"no match", "bramblestar", entities.SearchKindAny, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := searchRepo.Search(ctx, tt.query, tt.kind, 10)
+			if err != nil {
+				t.Fatalf("SearchRepository.Search() error = %v", err)
+			}
+
+			if len(got) != len(tt.want) {
+				t.Fatalf("SearchRepository.Search() = %v, want %d results", got, len(tt.want))
+			}
+
+			for _, kind := range tt.want {
+				found := false
+				for _, r := range got {
+					found = found || r.Kind == kind
+				}
+
+				if !found {
+					t.Errorf("SearchRepository.Search() = %v, want a %s result", got, kind.ToString())
+				}
+			}
+		})
+	}
+
+	got, err := searchRepo.Search(ctx, "ashpaw", entities.SearchKindCharacter, 10)
+	if err != nil {
+		t.Fatalf("SearchRepository.Search() error = %v", err)
+	}
+
+	// a match on an alias is still listed under the character's current name
+	if len(got) != 1 || got[0].ID != strconv.Itoa(character_id) || got[0].Title != "Ashfur" {
+		t.Errorf("SearchRepository.Search() = %v, want character %d titled Ashfur", got, character_id)
+	}
+
+	got, err = searchRepo.Search(ctx, "hatred", entities.SearchKindMusic, 10)
+	if err != nil {
+		t.Fatalf("SearchRepository.Search() error = %v", err)
+	}
+
+	if len(got) != 1 || got[0].ID != strconv.Itoa(int(music_id)) || !strings.Contains(got[0].Headline, "<b>Hatred</b>") {
+		t.Errorf("SearchRepository.Search() = %v, want music %d with a highlighted headline", got, music_id)
+	}
+
+	// whatever's matched is only ever highlighted, never interpreted as markup
+	uuid, err = projectRepo.NewProject(ctx, &entities.Project{
+		UUID:        helper.RandomUUID(),
+		ProjectType: entities.ProjectMultiAnimation,
+	})
+	if err != nil {
+		t.Fatalf("failed to create mock project, %v", err)
+	}
+
+	if err := projectRepo.NewProjectTitle(ctx, uuid, "<svg/onload=alert(1)> <script>alert(1)</script> Tigerstar \x02MAP\x03"); err != nil {
+		t.Fatalf("failed to create mock project title, %v", err)
+	}
+
+	got, err = searchRepo.Search(ctx, "tigerstar", entities.SearchKindProject, 10)
+	if err != nil {
+		t.Fatalf("SearchRepository.Search() error = %v", err)
+	}
+
+	if len(got) != 1 {
+		t.Fatalf("SearchRepository.Search() = %v, want a single result", got)
+	}
+
+	headline := got[0].Headline
+	if !strings.Contains(headline, "<b>Tigerstar</b>") || strings.ContainsAny(strings.NewReplacer("<b>", "", "</b>", "").Replace(headline), "<>\x02\x03") {
+		t.Errorf("SearchRepository.Search() headline = %q, want Tigerstar highlighted and no other markup", headline)
+	}
+}
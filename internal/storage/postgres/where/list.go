@@ -0,0 +1,161 @@
+package where
+
+import (
+	"context"
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/dtbead/wc-maps-archive/internal/entities"
+)
+
+// Order is a way of sorting a Listing. Expr may never be NULL, and Type is its SQL type, such as timestamp or bigint.
+type Order struct {
+	Expr, Type string
+}
+
+// Listing describes how to page through a table. Select is what gets listed, ID uniquely identifies every row, which
+// breaks ties between rows sorting the same, and Orders holds every entities.ListSort the listing supports, which has
+// to include entities.ListSortDefault.
+type Listing struct {
+	From   string
+	Select string
+	ID     string
+	IDType string
+	Orders map[entities.ListSort]Order
+}
+
+// cursor is where a page of a listing left off, the sort key and id of its last row. Sort and Ascending are kept so
+// that a cursor can't be used with a different order than it was given out for.
+type cursor struct {
+	Sort      string `json:"sort"`
+	Ascending bool   `json:"ascending,omitempty"`
+	Key       string `json:"key"`
+	ID        string `json:"id"`
+}
+
+func (c cursor) encode() string {
+	b, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func decodeCursor(s string) (c cursor, err error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return cursor{}, entities.ErrorInvalidCursor
+	}
+
+	if err := json.Unmarshal(b, &c); err != nil {
+		return cursor{}, entities.ErrorInvalidCursor
+	}
+
+	return c, nil
+}
+
+// checkKey makes sure key, as taken from a cursor, casts to the SQL type typ, so that a tampered or corrupt cursor is
+// told apart from the database failing. Timestamps are in the format Postgres prints them as text.
+func checkKey(key, typ string) error {
+	var err error
+	switch typ {
+	case "bigint":
+		_, err = strconv.ParseInt(key, 10, 64)
+	case "timestamp":
+		if key != "infinity" && key != "-infinity" {
+			_, err = time.Parse("2006-01-02 15:04:05.999999999", key)
+		}
+	case "text":
+		if !utf8.ValidString(key) || strings.ContainsRune(key, 0) {
+			err = fmt.Errorf("invalid text %q", key)
+		}
+	default:
+		return fmt.Errorf("unsupported cursor key type %s", typ)
+	}
+
+	if err != nil {
+		return fmt.Errorf("%w, %w", entities.ErrorInvalidCursor, err)
+	}
+
+	return nil
+}
+
+// List returns a page of listing matching condition, whose placeholders take args, along with the cursor of the page
+// following it. The cursor is empty on the last page. Rows are compared against the cursor rather than skipped with an
+// offset, so pages stay consistent while rows get added and removed.
+func List(ctx context.Context, db *sql.DB, listing Listing, condition string, args []any, opts entities.ListOptions) (values []string, next_cursor string, err error) {
+	if err := opts.Normalize(); err != nil {
+		return nil, "", err
+	}
+
+	order, ok := listing.Orders[opts.Sort]
+	if !ok {
+		return nil, "", fmt.Errorf("%w, can't sort this listing by %s", entities.ErrorUnsupportedSort, opts.Sort.ToString())
+	}
+
+	direction, comparison := "DESC", "<"
+	if opts.Ascending {
+		direction, comparison = "ASC", ">"
+	}
+
+	args = append([]any{}, args...)
+
+	after := "TRUE"
+	if opts.Cursor != "" {
+		c, err := decodeCursor(opts.Cursor)
+		if err != nil {
+			return nil, "", err
+		}
+
+		if c.Sort != opts.Sort.ToString() || c.Ascending != opts.Ascending {
+			return nil, "", entities.ErrorInvalidCursor
+		}
+
+		if err := checkKey(c.Key, order.Type); err != nil {
+			return nil, "", err
+		}
+
+		if err := checkKey(c.ID, listing.IDType); err != nil {
+			return nil, "", err
+		}
+
+		args = append(args, c.Key, c.ID)
+		after = fmt.Sprintf("(key, id) %s ($%d::%s, $%d::%s)", comparison, len(args)-1, order.Type, len(args), listing.IDType)
+	}
+
+	// one more row than asked for is fetched to tell whether there's a page after this one
+	args = append(args, opts.Limit+1)
+	query := fmt.Sprintf(`SELECT value::text, id::text, key::text FROM (
+	SELECT %s AS value, %s AS id, %s AS key FROM %s WHERE %s
+) listing
+WHERE %s
+ORDER BY key %s, id %s
+LIMIT $%d`, listing.Select, listing.ID, order.Expr, listing.From, condition, after, direction, direction, len(args))
+
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, "", err
+	}
+	defer rows.Close()
+
+	values = []string{}
+	last := cursor{Sort: opts.Sort.ToString(), Ascending: opts.Ascending}
+	for rows.Next() {
+		if len(values) == opts.Limit {
+			next_cursor = last.encode()
+			break
+		}
+
+		var value string
+		if err := rows.Scan(&value, &last.ID, &last.Key); err != nil {
+			return nil, "", err
+		}
+
+		values = append(values, value)
+	}
+
+	return values, next_cursor, rows.Err()
+}
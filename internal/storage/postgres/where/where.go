@@ -0,0 +1,273 @@
+// Package where compiles a filter.Filter into a parameterized SQL condition, according to a Schema describing the fields
+// a listing can be filtered by.
+package where
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dtbead/wc-maps-archive/internal/entities"
+	"github.com/dtbead/wc-maps-archive/internal/filter"
+)
+
+type Kind int
+
+const (
+	// KindText matches values containing the term, case insensitively.
+	KindText Kind = iota
+	// KindExact matches values equal to the term.
+	KindExact
+	// KindInt compares whole numbers.
+	KindInt
+	// KindDate compares timestamps against a year, month or day, such as 2014, 2014-06 or 2014-06-21. A date stands for
+	// the whole period it names, so uploaded:2014 matches all of 2014, and uploaded:>2014 anything from 2015 onward.
+	KindDate
+	// KindPeriod is KindDate for a tsrange, the period some partial date covers. A period only matches a date when it
+	// falls entirely within it, so that announced:2015-06 doesn't match a project only known to be announced in 2015,
+	// while announced:2015 does match one announced in June 2015. Likewise, announced:<2015 only matches periods over
+	// before 2015 begins.
+	KindPeriod
+	// KindChoice matches one of a fixed set of values, each with its own condition.
+	KindChoice
+)
+
+// Field is something a listing can be filtered by. Expr is an SQL expression containing a %s, or several %[1]s, which is
+// replaced with the comparison a term makes, such as "v.duration %s" becoming "v.duration > $1". Fields of KindChoice
+// use Choices instead, mapping every value the field accepts to a complete condition.
+type Field struct {
+	Kind    Kind
+	Expr    string
+	Choices map[string]string
+}
+
+// Schema maps field names to their Field. Bare words in a query are compiled against the field named "".
+type Schema map[string]Field
+
+// Compile turns f into a condition ANDing every term, along with the arguments to its placeholders. Placeholders are
+// numbered from $1, so further arguments can be appended after args. An empty f gives the condition TRUE. A negated term
+// also matches whatever its field is unknown for, such as -views:>100 matching videos without a view count. Errors
+// wrap entities.ErrorInvalidFilter.
+func Compile(f filter.Filter, schema Schema) (condition string, args []any, err error) {
+	if len(f) == 0 {
+		return "TRUE", nil, nil
+	}
+
+	conditions := make([]string, 0, len(f))
+	for _, t := range f {
+		field, ok := schema[t.Field]
+		if !ok {
+			if t.Field == "" {
+				return "", nil, fmt.Errorf("%w, bare words are not supported here", entities.ErrorInvalidFilter)
+			}
+
+			return "", nil, fmt.Errorf("%w, unknown field %q", entities.ErrorInvalidFilter, t.Field)
+		}
+
+		c, err := compileTerm(t, field, &args)
+		if err != nil {
+			return "", nil, fmt.Errorf("%w, %w", entities.ErrorInvalidFilter, err)
+		}
+
+		if t.Negate {
+			c = "(" + c + ") IS NOT TRUE"
+		}
+
+		conditions = append(conditions, c)
+	}
+
+	return strings.Join(conditions, " AND "), args, nil
+}
+
+func compileTerm(t filter.Term, field Field, args *[]any) (condition string, err error) {
+	// placeholder adds v as an argument, returning the placeholder referring to it
+	placeholder := func(v any) string {
+		*args = append(*args, v)
+		return "$" + strconv.Itoa(len(*args))
+	}
+
+	switch field.Kind {
+	case KindChoice:
+		if t.Op != filter.OpEqual {
+			return "", fmt.Errorf("%q can only be compared for equality", t.Field)
+		}
+
+		c, ok := field.Choices[strings.ToLower(t.Value)]
+		if !ok {
+			return "", fmt.Errorf("unknown %s %q", t.Field, t.Value)
+		}
+
+		return c, nil
+
+	case KindText, KindExact:
+		if t.Op != filter.OpEqual {
+			return "", fmt.Errorf("%q can only be compared for equality", t.Field)
+		}
+
+		if field.Kind == KindText {
+			return fmt.Sprintf(field.Expr, "ILIKE "+placeholder("%"+escapeLike(t.Value)+"%")), nil
+		}
+
+		return fmt.Sprintf(field.Expr, "= "+placeholder(t.Value)), nil
+
+	case KindInt:
+		parse := func(s string) (any, error) {
+			if s == "" {
+				return nil, nil
+			}
+
+			n, err := strconv.ParseInt(s, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("%q expects a whole number, got %q", t.Field, s)
+			}
+
+			return n, nil
+		}
+
+		return compileComparison(t, field, parse, placeholder)
+
+	case KindDate:
+		return compileDate(t, field, placeholder)
+
+	case KindPeriod:
+		return compilePeriod(t, field, placeholder)
+	}
+
+	return "", fmt.Errorf("field %q has an unknown kind", t.Field)
+}
+
+// compileComparison compares field against t's values as parsed by parse, which returns nil for an empty value.
+func compileComparison(t filter.Term, field Field, parse func(string) (any, error), placeholder func(any) string) (condition string, err error) {
+	value, err := parse(t.Value)
+	if err != nil {
+		return "", err
+	}
+
+	if t.Op != filter.OpRange {
+		return fmt.Sprintf(field.Expr, t.Op.ToString()+" "+placeholder(value)), nil
+	}
+
+	max, err := parse(t.Max)
+	if err != nil {
+		return "", err
+	}
+
+	switch {
+	case value == nil:
+		return fmt.Sprintf(field.Expr, "<= "+placeholder(max)), nil
+	case max == nil:
+		return fmt.Sprintf(field.Expr, ">= "+placeholder(value)), nil
+	default:
+		return fmt.Sprintf(field.Expr, "BETWEEN "+placeholder(value)+" AND "+placeholder(max)), nil
+	}
+}
+
+// compileDate compares field against the periods named by t. Every comparison is made against the start or end of a
+// period, with the end being exclusive.
+func compileDate(t filter.Term, field Field, placeholder func(any) string) (condition string, err error) {
+	start, end, err := parsePeriod(t.Value)
+	if err != nil {
+		return "", fmt.Errorf("%q expects a date, %w", t.Field, err)
+	}
+
+	switch t.Op {
+	case filter.OpLess:
+		return fmt.Sprintf(field.Expr, "< "+placeholder(start)), nil
+	case filter.OpLessEqual:
+		return fmt.Sprintf(field.Expr, "< "+placeholder(end)), nil
+	case filter.OpGreater:
+		return fmt.Sprintf(field.Expr, ">= "+placeholder(end)), nil
+	case filter.OpGreaterEqual:
+		return fmt.Sprintf(field.Expr, ">= "+placeholder(start)), nil
+	case filter.OpRange:
+		if t.Max != "" {
+			_, end, err = parsePeriod(t.Max)
+			if err != nil {
+				return "", fmt.Errorf("%q expects a date, %w", t.Field, err)
+			}
+		}
+
+		if t.Value == "" {
+			return fmt.Sprintf(field.Expr, "< "+placeholder(end)), nil
+		}
+
+		if t.Max == "" {
+			return fmt.Sprintf(field.Expr, ">= "+placeholder(start)), nil
+		}
+	}
+
+	return fmt.Sprintf(field.Expr, "<@ tsrange("+placeholder(start)+"::timestamp, "+placeholder(end)+"::timestamp)"), nil
+}
+
+// compilePeriod compares the periods of field against those named by t, using the range operators of tsrange. A
+// period is before a date when it ends before the date starts, and after it when it starts after the date ends.
+func compilePeriod(t filter.Term, field Field, placeholder func(any) string) (condition string, err error) {
+	start, end, err := parsePeriod(t.Value)
+	if err != nil {
+		return "", fmt.Errorf("%q expects a date, %w", t.Field, err)
+	}
+
+	// from and until are the unbounded ranges starting at or ending before a time
+	from := func(t time.Time) string { return "tsrange(" + placeholder(t) + "::timestamp, NULL)" }
+	until := func(t time.Time) string { return "tsrange(NULL, " + placeholder(t) + "::timestamp)" }
+
+	switch t.Op {
+	case filter.OpLess:
+		return fmt.Sprintf(field.Expr, "<< "+from(start)), nil
+	case filter.OpLessEqual:
+		return fmt.Sprintf(field.Expr, "&< "+until(end)), nil
+	case filter.OpGreater:
+		return fmt.Sprintf(field.Expr, ">> "+until(end)), nil
+	case filter.OpGreaterEqual:
+		return fmt.Sprintf(field.Expr, "&> "+from(start)), nil
+	case filter.OpRange:
+		if t.Max != "" {
+			_, end, err = parsePeriod(t.Max)
+			if err != nil {
+				return "", fmt.Errorf("%q expects a date, %w", t.Field, err)
+			}
+		}
+
+		if t.Value == "" {
+			return fmt.Sprintf(field.Expr, "&< "+until(end)), nil
+		}
+
+		if t.Max == "" {
+			return fmt.Sprintf(field.Expr, "&> "+from(start)), nil
+		}
+	}
+
+	return fmt.Sprintf(field.Expr, "<@ tsrange("+placeholder(start)+"::timestamp, "+placeholder(end)+"::timestamp)"), nil
+}
+
+// parsePeriod parses a year, month or day, returning when it starts and when the next one does. An empty s parses as
+// the zero period.
+func parsePeriod(s string) (start, end time.Time, err error) {
+	if s == "" {
+		return time.Time{}, time.Time{}, nil
+	}
+
+	for _, p := range []struct {
+		layout string
+		years  int
+		months int
+		days   int
+	}{
+		{"2006", 1, 0, 0},
+		{"2006-01", 0, 1, 0},
+		{"2006-01-02", 0, 0, 1},
+	} {
+		start, err = time.Parse(p.layout, s)
+		if err == nil {
+			return start, start.AddDate(p.years, p.months, p.days), nil
+		}
+	}
+
+	return time.Time{}, time.Time{}, fmt.Errorf("got %q", s)
+}
+
+// escapeLike escapes the wildcards of a LIKE pattern in s.
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
@@ -0,0 +1,128 @@
+package youtube
+
+import (
+	"context"
+
+	"github.com/dtbead/wc-maps-archive/internal/entities"
+	"github.com/dtbead/wc-maps-archive/internal/filter"
+	"github.com/dtbead/wc-maps-archive/internal/storage/postgres/where"
+)
+
+// youtubeSchema is every field youtube videos can be listed by, v being the youtube_video.
+var youtubeSchema = where.Schema{
+	"":            {Kind: where.KindText, Expr: "EXISTS (SELECT 1 FROM youtube_title t WHERE t.youtube_id = v.id AND t.title %s)"},
+	"title":       {Kind: where.KindText, Expr: "EXISTS (SELECT 1 FROM youtube_title t WHERE t.youtube_id = v.id AND t.title %s)"},
+	"description": {Kind: where.KindText, Expr: "EXISTS (SELECT 1 FROM youtube_description d WHERE d.youtube_id = v.id AND d.description %s)"},
+	"channel":     {Kind: where.KindExact, Expr: "EXISTS (SELECT 1 FROM youtube_channel_youtube_video c WHERE c.youtube_id = v.id AND c.channel_id %s)"},
+	"uploader": {Kind: where.KindText, Expr: `EXISTS (SELECT 1 FROM youtube_channel_youtube_video c
+		JOIN youtube_channel_uploader_name u ON u.channel_id = c.channel_id
+		WHERE c.youtube_id = v.id AND u.uploader %s)`},
+	"uploaded": {Kind: where.KindDate, Expr: "v.upload_date %s"},
+	"duration": {Kind: where.KindInt, Expr: "v.duration %s"},
+	"views":    {Kind: where.KindInt, Expr: "v.view_count %s"},
+	"likes":    {Kind: where.KindInt, Expr: "v.like_count %s"},
+	"width": {Kind: where.KindInt, Expr: `EXISTS (SELECT 1 FROM youtube_file yf
+		JOIN file_video fv ON fv.file_id = yf.file_id
+		WHERE yf.youtube_id = v.id AND fv.width %s)`},
+	"height": {Kind: where.KindInt, Expr: `EXISTS (SELECT 1 FROM youtube_file yf
+		JOIN file_video fv ON fv.file_id = yf.file_id
+		WHERE yf.youtube_id = v.id AND fv.height %s)`},
+	"project": {Kind: where.KindExact, Expr: `EXISTS (SELECT 1 FROM youtube_file yf
+		JOIN project_file pf ON pf.file_id = yf.file_id
+		JOIN project p ON p.id = pf.project_id
+		WHERE yf.youtube_id = v.id AND p.uuid %s)`},
+	"is": {Kind: where.KindChoice, Choices: map[string]string{
+		"live":       "v.is_live",
+		"restricted": "v.is_restricted",
+	}},
+	"has": {Kind: where.KindChoice, Choices: map[string]string{
+		"file":        "EXISTS (SELECT 1 FROM youtube_file yf WHERE yf.youtube_id = v.id)",
+		"description": "EXISTS (SELECT 1 FROM youtube_description d WHERE d.youtube_id = v.id AND d.description != '')",
+		"project": `EXISTS (SELECT 1 FROM youtube_file yf
+			JOIN project_file pf ON pf.file_id = yf.file_id
+			WHERE yf.youtube_id = v.id)`,
+		"music": `EXISTS (SELECT 1 FROM youtube_file yf
+			JOIN project_file pf ON pf.file_id = yf.file_id
+			JOIN project_music pm ON pm.project_id = pf.project_id
+			WHERE yf.youtube_id = v.id)`,
+		"characters": `EXISTS (SELECT 1 FROM youtube_file yf
+			JOIN project_file pf ON pf.file_id = yf.file_id
+			JOIN project_character pc ON pc.project_id = pf.project_id
+			WHERE yf.youtube_id = v.id)`,
+		"artist": `EXISTS (SELECT 1 FROM youtube_channel_youtube_video c
+			JOIN artist_channel ac ON ac.channel_id = c.channel_id
+			WHERE c.youtube_id = v.id)`,
+	}},
+}
+
+// youtubeListing pages through youtube videos, newest upload first by default. A video counts as archived when its
+// first title was.
+var youtubeListing = where.Listing{
+	From:   "youtube_video v",
+	Select: "v.id",
+	ID:     "v.id",
+	IDType: "text",
+	Orders: map[entities.ListSort]where.Order{
+		entities.ListSortDefault:      {Expr: "v.upload_date", Type: "timestamp"},
+		entities.ListSortUploadDate:   {Expr: "v.upload_date", Type: "timestamp"},
+		entities.ListSortArchivedDate: {Expr: "COALESCE((SELECT min(t.date_added) FROM youtube_title t WHERE t.youtube_id = v.id), '-infinity')", Type: "timestamp"},
+		entities.ListSortDuration:     {Expr: "v.duration::bigint", Type: "bigint"},
+		entities.ListSortViews:        {Expr: "COALESCE(v.view_count, -1)::bigint", Type: "bigint"},
+	},
+}
+
+// ListYoutube returns a page of youtube videos matching f, along with the cursor of the next page.
+func (y YoutubeRepository) ListYoutube(ctx context.Context, f filter.Filter, opts entities.ListOptions) (youtube_ids []entities.YoutubeVideoID, next_cursor string, err error) {
+	condition, args, err := where.Compile(f, youtubeSchema)
+	if err != nil {
+		return nil, "", err
+	}
+
+	res, next_cursor, err := where.List(ctx, y.db, youtubeListing, condition, args, opts)
+	if err != nil {
+		return nil, "", err
+	}
+
+	youtube_ids = make([]entities.YoutubeVideoID, 0, len(res))
+	for _, v := range res {
+		youtube_ids = append(youtube_ids, entities.YoutubeVideoID(v))
+	}
+
+	return youtube_ids, next_cursor, nil
+}
+
+// channelVideos joins c, the youtube channel, to its videos as v.
+const channelVideos = "youtube_channel_youtube_video cv JOIN youtube_video v ON v.id = cv.youtube_id WHERE cv.channel_id = c.id"
+
+// channelListing pages through youtube channels, the one with the latest upload first by default. Duration and views
+// are the totals of every video of a channel.
+var channelListing = where.Listing{
+	From:   "youtube_channel c",
+	Select: "c.id",
+	ID:     "c.id",
+	IDType: "text",
+	Orders: map[entities.ListSort]where.Order{
+		entities.ListSortDefault:    {Expr: "COALESCE((SELECT max(v.upload_date) FROM " + channelVideos + "), '-infinity')", Type: "timestamp"},
+		entities.ListSortUploadDate: {Expr: "COALESCE((SELECT max(v.upload_date) FROM " + channelVideos + "), '-infinity')", Type: "timestamp"},
+		entities.ListSortArchivedDate: {Expr: `COALESCE((SELECT min(t.date_added) FROM youtube_channel_youtube_video cv
+			JOIN youtube_title t ON t.youtube_id = cv.youtube_id
+			WHERE cv.channel_id = c.id), '-infinity')`, Type: "timestamp"},
+		entities.ListSortDuration: {Expr: "COALESCE((SELECT sum(v.duration) FROM " + channelVideos + "), 0)::bigint", Type: "bigint"},
+		entities.ListSortViews:    {Expr: "COALESCE((SELECT sum(v.view_count) FROM " + channelVideos + "), 0)::bigint", Type: "bigint"},
+	},
+}
+
+// ListChannels returns a page of every youtube channel known to the archive, along with the cursor of the next page.
+func (y YoutubeRepository) ListChannels(ctx context.Context, opts entities.ListOptions) (channel_ids []entities.YoutubeChannelID, next_cursor string, err error) {
+	res, next_cursor, err := where.List(ctx, y.db, channelListing, "TRUE", nil, opts)
+	if err != nil {
+		return nil, "", err
+	}
+
+	channel_ids = make([]entities.YoutubeChannelID, 0, len(res))
+	for _, v := range res {
+		channel_ids = append(channel_ids, entities.YoutubeChannelID(v))
+	}
+
+	return channel_ids, next_cursor, nil
+}
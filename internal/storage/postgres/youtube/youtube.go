@@ -3,6 +3,7 @@ package youtube
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
 
 	"github.com/dtbead/wc-maps-archive/internal/entities"
@@ -210,6 +211,28 @@ func (y YoutubeRepository) NewYoutube(ctx context.Context, file_id entities.File
 		}
 	}
 
+	if len(youtube.DlpInfo) > 0 {
+		err = y.q.NewYoutubeYtdlpInfo(ctx, queries.NewYoutubeYtdlpInfoParams{
+			FileID:    int64(file_id),
+			YoutubeID: youtube.YouTube.YoutubeID,
+			Info:      youtube.DlpInfo,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(youtube.Thumbnail) > 0 {
+		err = y.q.NewYoutubeThumbnail(ctx, queries.NewYoutubeThumbnailParams{
+			FileID:    int64(file_id),
+			YoutubeID: youtube.YouTube.YoutubeID,
+			Thumbnail: youtube.Thumbnail,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
 	err = tx.Commit()
 	if err != nil {
 		return err
@@ -269,9 +292,53 @@ func (y YoutubeRepository) GetYoutube(ctx context.Context, youtube_id entities.Y
 		yt.Description = description[0]
 	}
 
+	channel, err := y.GetChannelByVideoID(ctx, youtube_id)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+	if err == nil {
+		yt.Channel = &channel
+	}
+
+	formats, err := y.q.GetYoutubeVideoFormatByYoutubeID(ctx, youtube_id)
+	if err != nil {
+		return nil, err
+	}
+	if len(formats) > 0 {
+		yt.Format = &entities.VideoYoutubeFormat{
+			YoutubeID: youtube_id,
+			FileID:    entities.FileID(formats[0].FileID),
+			Format:    formats[0].Format,
+			FormatID:  formats[0].FormatID,
+		}
+	}
+
+	version, err := y.GetYtdlpVersion(ctx, youtube_id, entities.FileID(file_id[0]))
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+	if err == nil {
+		yt.DlpVersion = version
+	}
+
 	return yt, nil
 }
 
+// GetFileYoutubeID returns the youtube video file_id is a download of, or sql.ErrNoRows if it isn't one.
+func (y YoutubeRepository) GetFileYoutubeID(ctx context.Context, file_id entities.FileID) (youtube_id entities.YoutubeVideoID, err error) {
+	res, err := y.q.GetFileYoutubeID(ctx, int64(file_id))
+	if err != nil {
+		return "", err
+	}
+
+	id, ok := res.(string)
+	if !ok {
+		return "", errors.New("unexpected youtube_id type")
+	}
+
+	return entities.YoutubeVideoID(id), nil
+}
+
 func (y YoutubeRepository) GetYoutubeFileIDs(ctx context.Context, youtube_id entities.YoutubeVideoID) (file_ids []entities.FileID, err error) {
 	if !youtube_id.IsValid() {
 		return nil, entities.ErrorInvalidYoutubeID
@@ -290,6 +357,18 @@ func (y YoutubeRepository) GetYoutubeFileIDs(ctx context.Context, youtube_id ent
 	return file_ids, nil
 }
 
+// AssignYoutubeFile links an already stored file to an existing youtube video, such as a local copy imported from disk.
+func (y YoutubeRepository) AssignYoutubeFile(ctx context.Context, youtube_id entities.YoutubeVideoID, file_id entities.FileID) (err error) {
+	if !youtube_id.IsValid() {
+		return entities.ErrorInvalidYoutubeID
+	}
+
+	return y.q.AssignYoutubeFileID(ctx, queries.AssignYoutubeFileIDParams{
+		YoutubeID: youtube_id,
+		FileID:    int64(file_id),
+	})
+}
+
 func (y YoutubeRepository) GetYtdlpVersion(ctx context.Context, youtube_id entities.YoutubeVideoID, file_id entities.FileID) (version *entities.VideoYoutubeDlpVersion, err error) {
 	res, err := y.q.GetYoutubeYtdlpVersion(ctx, queries.GetYoutubeYtdlpVersionParams{
 		YoutubeID: youtube_id,
@@ -308,6 +387,16 @@ func (y YoutubeRepository) GetYtdlpVersion(ctx context.Context, youtube_id entit
 	}, nil
 }
 
+// GetYtdlpInfo returns the info json yt-dlp printed when downloading file_id, or sql.ErrNoRows if none was kept.
+func (y YoutubeRepository) GetYtdlpInfo(ctx context.Context, file_id entities.FileID) (info json.RawMessage, err error) {
+	return y.q.GetYoutubeYtdlpInfo(ctx, int64(file_id))
+}
+
+// GetThumbnail returns the jpeg thumbnail yt-dlp wrote when downloading file_id, or sql.ErrNoRows if none was kept.
+func (y YoutubeRepository) GetThumbnail(ctx context.Context, file_id entities.FileID) (thumbnail []byte, err error) {
+	return y.q.GetYoutubeThumbnail(ctx, int64(file_id))
+}
+
 func (y YoutubeRepository) GetFormat(ctx context.Context, youtube_id entities.YoutubeVideoID) (format *entities.VideoYoutubeFormat, err error) {
 	if !youtube_id.IsValid() {
 		return nil, entities.ErrorInvalidYoutubeID
@@ -375,8 +464,12 @@ func (y YoutubeRepository) GetDescription(ctx context.Context, youtube_id entiti
 
 func (y YoutubeRepository) GetChannelVideos(ctx context.Context, channel_id entities.YoutubeChannelID) (videos []entities.YoutubeVideoID, err error) {
 	res, err := y.q.GetYoutubeChannelVideos(ctx, channel_id)
+	if err != nil {
+		return nil, err
+	}
+
 	if len(res) < 1 {
-		return nil, errors.New("no videos found")
+		return nil, entities.ErrorNoChannelVideos
 	}
 	return res, nil
 }
@@ -2,6 +2,9 @@ package youtube_test
 
 import (
 	"context"
+	"database/sql"
+	"encoding/base64"
+	"errors"
 	"os"
 	"reflect"
 	"slices"
@@ -9,6 +12,8 @@ import (
 	"time"
 
 	"github.com/dtbead/wc-maps-archive/internal/entities"
+	"github.com/dtbead/wc-maps-archive/internal/filter"
+	"github.com/dtbead/wc-maps-archive/internal/helper"
 	helper_test "github.com/dtbead/wc-maps-archive/internal/helper/testing"
 	mock "github.com/dtbead/wc-maps-archive/internal/helper/testing/mock/youtube"
 	"github.com/dtbead/wc-maps-archive/internal/storage/postgres/file"
@@ -286,6 +291,45 @@ func TestYoutubeRepository_GetYoutubeFileIDs(t *testing.T) {
 	}
 }
 
+func TestYoutubeRepository_GetFileYoutubeID(t *testing.T) {
+	db := helper_test.NewDatabase(&helper_test.DefaultConnection)
+	defer db.Close()
+
+	youtubeRepo := youtube.NewYoutubeRepository(db)
+	fileRepo, err := file.NewFileRepository(db, t.TempDir())
+	if err != nil {
+		t.Fatalf("failed to create file repo, %v", err)
+	}
+
+	file_id := helperInsertFile(*fileRepo, t)
+	mockYt := mock.NewYoutube()
+	if err := youtubeRepo.NewYoutube(context.Background(), file_id, &mockYt); err != nil {
+		t.Fatalf("failed to insert mock youtube, %v", err)
+	}
+
+	tests := []struct {
+		name           string
+		file_id        entities.FileID
+		wantYoutube_id entities.YoutubeVideoID
+		wantErr        bool
+	}{
+		{"youtube download", file_id, mockYt.YouTube.YoutubeID, false},
+		{"missing file_id", file_id + 1, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotYoutube_id, err := youtubeRepo.GetFileYoutubeID(context.Background(), tt.file_id)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("YoutubeRepository.GetFileYoutubeID() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if gotYoutube_id != tt.wantYoutube_id {
+				t.Errorf("YoutubeRepository.GetFileYoutubeID() = %v, want %v", gotYoutube_id, tt.wantYoutube_id)
+			}
+		})
+	}
+}
+
 func TestYoutubeRepository_GetYtdlpVersion(t *testing.T) {
 	db := helper_test.NewDatabase(&helper_test.DefaultConnection)
 	defer db.Close()
@@ -330,6 +374,47 @@ func TestYoutubeRepository_GetYtdlpVersion(t *testing.T) {
 	}
 }
 
+func TestYoutubeRepository_GetThumbnail(t *testing.T) {
+	db := helper_test.NewDatabase(&helper_test.DefaultConnection)
+	defer db.Close()
+
+	youtubeRepo := youtube.NewYoutubeRepository(db)
+	fileRepo, err := file.NewFileRepository(db, t.TempDir())
+	if err != nil {
+		t.Fatalf("failed to create file repo, %v", err)
+	}
+	file_id := helperInsertFile(*fileRepo, t)
+	mockYt := mock.NewYoutube()
+	mockYt.Thumbnail = []byte{0xff, 0xd8, 0xff, 0xe0, 0x00, 0x10, 'J', 'F', 'I', 'F', 0x00}
+
+	err = youtubeRepo.NewYoutube(context.Background(), file_id, &mockYt)
+	if err != nil {
+		t.Fatalf("failed to insert youtube, %v", err)
+	}
+
+	tests := []struct {
+		name          string
+		file_id       entities.FileID
+		wantThumbnail []byte
+		wantErr       error
+	}{
+		{"with thumbnail", file_id, mockYt.Thumbnail, nil},
+		{"without thumbnail", file_id + 999, nil, sql.ErrNoRows},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotThumbnail, err := youtubeRepo.GetThumbnail(context.Background(), tt.file_id)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("YoutubeRepository.GetThumbnail() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !cmp.Equal(gotThumbnail, tt.wantThumbnail) {
+				t.Errorf("YoutubeRepository.GetThumbnail() got diff %s", cmp.Diff(gotThumbnail, tt.wantThumbnail))
+			}
+		})
+	}
+}
+
 func TestYoutubeRepository_GetFormat(t *testing.T) {
 	db := helper_test.NewDatabase(&helper_test.DefaultConnection)
 	defer db.Close()
@@ -526,15 +611,15 @@ func TestYoutubeRepository_GetChannelVideos(t *testing.T) {
 		y          youtube.YoutubeRepository
 		args       args
 		wantVideos []entities.YoutubeVideoID
-		wantErr    bool
+		wantErr    error
 	}{
-		{"no videos", *youtubeRepo, args{ctx: context.Background(), channel_id: entities.UnknownYoutubeChannelID}, nil, true},
-		{"one videos", *youtubeRepo, args{ctx: context.Background(), channel_id: mockYt.Channel.ChannelID}, []entities.YoutubeVideoID{mockYt.YouTube.YoutubeID}, false},
+		{"no videos", *youtubeRepo, args{ctx: context.Background(), channel_id: entities.UnknownYoutubeChannelID}, nil, entities.ErrorNoChannelVideos},
+		{"one videos", *youtubeRepo, args{ctx: context.Background(), channel_id: mockYt.Channel.ChannelID}, []entities.YoutubeVideoID{mockYt.YouTube.YoutubeID}, nil},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			gotVideos, err := tt.y.GetChannelVideos(tt.args.ctx, tt.args.channel_id)
-			if (err != nil) != tt.wantErr {
+			if !errors.Is(err, tt.wantErr) {
 				t.Errorf("YoutubeRepository.GetChannelVideos() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
@@ -544,3 +629,182 @@ func TestYoutubeRepository_GetChannelVideos(t *testing.T) {
 		})
 	}
 }
+
+// helperInsertVideo inserts a youtube video uploaded at upload_date onto channel_id, titled title, without any file.
+func helperInsertVideo(t *testing.T, db *sql.DB, channel_id entities.YoutubeChannelID, title, upload_date string, duration int, views sql.NullInt32) entities.YoutubeVideoID {
+	t.Helper()
+
+	youtube_id := helper.RandomYoutubeID()
+	_, err := db.Exec(`INSERT INTO youtube_video (id, upload_date, duration, view_count) VALUES ($1, $2, $3, $4)`,
+		string(youtube_id), upload_date, duration, views)
+	if err != nil {
+		t.Fatalf("failed to insert youtube video, %v", err)
+	}
+
+	_, err = db.Exec(`INSERT INTO youtube_title (youtube_id, title, title_md5) VALUES ($1, $2, decode(md5($2), 'hex'))`, string(youtube_id), title)
+	if err != nil {
+		t.Fatalf("failed to insert youtube title, %v", err)
+	}
+
+	_, err = db.Exec(`INSERT INTO youtube_channel_youtube_video (channel_id, youtube_id) VALUES ($1, $2)`, string(channel_id), string(youtube_id))
+	if err != nil {
+		t.Fatalf("failed to insert youtube channel video, %v", err)
+	}
+
+	return youtube_id
+}
+
+func TestYoutubeRepository_ListYoutube(t *testing.T) {
+	db := helper_test.NewDatabase(&helper_test.DefaultConnection)
+	defer db.Close()
+
+	youtubeRepo := youtube.NewYoutubeRepository(db)
+	ctx := context.Background()
+
+	channel_id := helper.RandomYoutubeChannelID()
+	if _, err := db.Exec(`INSERT INTO youtube_channel (id) VALUES ($1)`, string(channel_id)); err != nil {
+		t.Fatalf("failed to insert youtube channel, %v", err)
+	}
+
+	// every title carries tag, so that videos already in the database aren't listed
+	tag := helper.RandomString(12)
+	oldest := helperInsertVideo(t, db, channel_id, tag+" oldest", "2015-01-01", 30, sql.NullInt32{Int32: 100, Valid: true})
+	middle := helperInsertVideo(t, db, channel_id, tag+" middle", "2016-01-01", 10, sql.NullInt32{})
+	newest := helperInsertVideo(t, db, channel_id, tag+" newest", "2017-01-01", 20, sql.NullInt32{Int32: 50, Valid: true})
+
+	f, err := filter.Parse(tag)
+	if err != nil {
+		t.Fatalf("filter.Parse() error = %v", err)
+	}
+
+	tests := []struct {
+		name string
+		opts entities.ListOptions
+		want []entities.YoutubeVideoID
+	}{
+		{"newest first", entities.ListOptions{}, []entities.YoutubeVideoID{newest, middle, oldest}},
+		{"oldest first", entities.ListOptions{Sort: entities.ListSortUploadDate, Ascending: true}, []entities.YoutubeVideoID{oldest, middle, newest}},
+		{"shortest first", entities.ListOptions{Sort: entities.ListSortDuration, Ascending: true}, []entities.YoutubeVideoID{middle, newest, oldest}},
+		{"most viewed first", entities.ListOptions{Sort: entities.ListSortViews}, []entities.YoutubeVideoID{oldest, newest, middle}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, _, err := youtubeRepo.ListYoutube(ctx, f, tt.opts)
+			if err != nil {
+				t.Fatalf("YoutubeRepository.ListYoutube() error = %v", err)
+			}
+
+			if !cmp.Equal(got, tt.want) {
+				t.Errorf("got diff %s", cmp.Diff(got, tt.want))
+			}
+		})
+	}
+
+	opts := entities.ListOptions{Limit: 2}
+	got, next_cursor, err := youtubeRepo.ListYoutube(ctx, f, opts)
+	if err != nil {
+		t.Fatalf("YoutubeRepository.ListYoutube() error = %v", err)
+	}
+
+	if want := []entities.YoutubeVideoID{newest, middle}; !cmp.Equal(got, want) || next_cursor == "" {
+		t.Fatalf("YoutubeRepository.ListYoutube() first page = %v, %q, want %v and a cursor", got, next_cursor, want)
+	}
+
+	opts.Cursor = next_cursor
+	got, next_cursor, err = youtubeRepo.ListYoutube(ctx, f, opts)
+	if err != nil {
+		t.Fatalf("YoutubeRepository.ListYoutube() error = %v", err)
+	}
+
+	if want := []entities.YoutubeVideoID{oldest}; !cmp.Equal(got, want) || next_cursor != "" {
+		t.Errorf("YoutubeRepository.ListYoutube() last page = %v, %q, want %v and no cursor", got, next_cursor, want)
+	}
+
+	// a cursor whose key isn't a date never reaches the database
+	opts.Cursor = base64.RawURLEncoding.EncodeToString([]byte(`{"sort":"","key":"yesterday","id":"` + string(oldest) + `"}`))
+	if _, _, err := youtubeRepo.ListYoutube(ctx, f, opts); !errors.Is(err, entities.ErrorInvalidCursor) {
+		t.Errorf("YoutubeRepository.ListYoutube() with a cursor keyed by a non-date error = %v, want %v", err, entities.ErrorInvalidCursor)
+	}
+
+	if _, _, err := youtubeRepo.ListYoutube(ctx, f, entities.ListOptions{Sort: entities.ListSortAnnouncedDate}); !errors.Is(err, entities.ErrorUnsupportedSort) {
+		t.Errorf("YoutubeRepository.ListYoutube() by announced date error = %v, want %v", err, entities.ErrorUnsupportedSort)
+	}
+}
+
+func TestYoutubeRepository_ListChannels(t *testing.T) {
+	db := helper_test.NewDatabase(&helper_test.DefaultConnection)
+	defer db.Close()
+
+	youtubeRepo := youtube.NewYoutubeRepository(db)
+	ctx := context.Background()
+
+	newChannel := func() entities.YoutubeChannelID {
+		channel_id := helper.RandomYoutubeChannelID()
+		if _, err := db.Exec(`INSERT INTO youtube_channel (id) VALUES ($1)`, string(channel_id)); err != nil {
+			t.Fatalf("failed to insert youtube channel, %v", err)
+		}
+		return channel_id
+	}
+
+	// older has the most footage, newer the latest upload, and empty no videos at all
+	older, newer, empty := newChannel(), newChannel(), newChannel()
+	helperInsertVideo(t, db, older, helper.RandomString(12), "2015-01-01", 30, sql.NullInt32{})
+	helperInsertVideo(t, db, newer, helper.RandomString(12), "2016-01-01", 5, sql.NullInt32{})
+	helperInsertVideo(t, db, newer, helper.RandomString(12), "2017-01-01", 10, sql.NullInt32{})
+	mine := []entities.YoutubeChannelID{older, newer, empty}
+
+	// channels can't be filtered, so every page is walked and only the test's channels are checked
+	list := func(opts entities.ListOptions) (got []entities.YoutubeChannelID) {
+		t.Helper()
+
+		opts.Limit = 1
+		for {
+			channel_ids, next_cursor, err := youtubeRepo.ListChannels(ctx, opts)
+			if err != nil {
+				t.Fatalf("YoutubeRepository.ListChannels() error = %v", err)
+			}
+
+			if len(channel_ids) > opts.Limit {
+				t.Fatalf("YoutubeRepository.ListChannels() page = %v, want at most %d channels", channel_ids, opts.Limit)
+			}
+
+			for _, channel_id := range channel_ids {
+				if slices.Contains(mine, channel_id) {
+					got = append(got, channel_id)
+				}
+			}
+
+			if next_cursor == "" {
+				return got
+			}
+			opts.Cursor = next_cursor
+		}
+	}
+
+	tests := []struct {
+		name string
+		opts entities.ListOptions
+		want []entities.YoutubeChannelID
+	}{
+		{"latest upload first", entities.ListOptions{}, []entities.YoutubeChannelID{newer, older, empty}},
+		{"earliest upload first", entities.ListOptions{Sort: entities.ListSortUploadDate, Ascending: true}, []entities.YoutubeChannelID{empty, older, newer}},
+		{"most footage first", entities.ListOptions{Sort: entities.ListSortDuration}, []entities.YoutubeChannelID{older, newer, empty}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := list(tt.opts); !cmp.Equal(got, tt.want) {
+				t.Errorf("got diff %s", cmp.Diff(got, tt.want))
+			}
+		})
+	}
+
+	// a cursor only continues the order it was given out for
+	_, next_cursor, err := youtubeRepo.ListChannels(ctx, entities.ListOptions{Limit: 1})
+	if err != nil {
+		t.Fatalf("YoutubeRepository.ListChannels() error = %v", err)
+	}
+
+	if _, _, err := youtubeRepo.ListChannels(ctx, entities.ListOptions{Limit: 1, Sort: entities.ListSortViews, Cursor: next_cursor}); !errors.Is(err, entities.ErrorInvalidCursor) {
+		t.Errorf("YoutubeRepository.ListChannels() with a cursor of another sort error = %v, want %v", err, entities.ErrorInvalidCursor)
+	}
+}
@@ -2,27 +2,61 @@ package storage
 
 import (
 	"context"
+	"encoding/json"
 	"io"
 
 	"github.com/dtbead/wc-maps-archive/internal/entities"
+	"github.com/dtbead/wc-maps-archive/internal/filter"
 )
 
 type FileRepository interface {
 	NewFile(ctx context.Context, file io.Reader, extension string) (file_id entities.FileID, err error)
 	DeleteFile(ctx context.Context, file_id entities.FileID) (err error)
 	GetFile(ctx context.Context, file_id entities.FileID) (file_metadata *entities.File, err error)
+	GetFileIDBySHA256(ctx context.Context, sha256 []byte) (file_id entities.FileID, err error)
+	AdoptFile(ctx context.Context, path_relative string) (file_id entities.FileID, err error)
+	GetStoredPaths(ctx context.Context) (paths []string, err error)
+	WriteSidecar(ctx context.Context, file_id entities.FileID, sidecar []byte) (err error)
+	ReadSidecar(ctx context.Context, path_relative string) (sidecar []byte, err error)
 	NewTempFile(ctx context.Context) (file io.ReadWriteCloser, err error)
+	Recover(ctx context.Context) (err error)
+	NewFileVideo(ctx context.Context, file_id entities.FileID, video *entities.Video) (err error)
+	GetFileVideo(ctx context.Context, file_id entities.FileID) (video *entities.Video, err error)
+	ListFiles(ctx context.Context, opts entities.ListOptions) (file_ids []entities.FileID, next_cursor string, err error)
 }
 
 type ProjectRepository interface {
 	NewProject(ctx context.Context, project *entities.Project) (uuid entities.ProjectUUID, err error)
 	DeleteProject(ctx context.Context, uuid entities.ProjectUUID) (err error)
 	GetProject(ctx context.Context, uuid entities.ProjectUUID) (project *entities.Project, err error)
+	SetProjectDateAnnounced(ctx context.Context, uuid entities.ProjectUUID, date entities.PartialDate) (err error)
+	SetProjectDateCompleted(ctx context.Context, uuid entities.ProjectUUID, date entities.PartialDate) (err error)
+	SetProjectType(ctx context.Context, uuid entities.ProjectUUID, project_type entities.ProjectType) (err error)
 	AssignProjectFile(ctx context.Context, uuid entities.ProjectUUID, file_id entities.FileID) (err error)
 	UnassignProjectVideo(ctx context.Context, uuid entities.ProjectUUID, file_id entities.FileID) (err error)
 	GetProjectVideos(ctx context.Context, uuid entities.ProjectUUID) (file_ids []entities.FileID, err error)
+	GetFileProjects(ctx context.Context, file_id entities.FileID) (uuids []entities.ProjectUUID, err error)
 	AssignYoutube(ctx context.Context, project_uuid entities.ProjectUUID, youtube_id entities.YoutubeVideoID) (err error)
 	UnassignYoutube(ctx context.Context, project_uuid entities.ProjectUUID, youtube_id entities.YoutubeVideoID) (err error)
+	GetProjectYoutube(ctx context.Context, uuid entities.ProjectUUID) (youtube_ids []entities.YoutubeVideoID, err error)
+	NewProjectTitle(ctx context.Context, uuid entities.ProjectUUID, title string) (err error)
+	GetProjectTitles(ctx context.Context, uuid entities.ProjectUUID) (titles []entities.ProjectTitle, err error)
+	NewProjectDescription(ctx context.Context, uuid entities.ProjectUUID, description string) (err error)
+	GetProjectDescriptions(ctx context.Context, uuid entities.ProjectUUID) (descriptions []entities.ProjectDescription, err error)
+	NewProjectPart(ctx context.Context, part *entities.ProjectPart) (part_id int64, err error)
+	UpdateProjectPart(ctx context.Context, part *entities.ProjectPart) (err error)
+	DeleteProjectPart(ctx context.Context, part_id int64) (err error)
+	GetProjectPart(ctx context.Context, part_id int64) (part *entities.ProjectPart, err error)
+	GetProjectParts(ctx context.Context, uuid entities.ProjectUUID) (parts []entities.ProjectPart, err error)
+	NewProjectRelation(ctx context.Context, relation entities.ProjectRelation) (err error)
+	DeleteProjectRelation(ctx context.Context, relation entities.ProjectRelation) (err error)
+	GetProjectRelations(ctx context.Context, uuid entities.ProjectUUID) (relations []entities.ProjectRelation, err error)
+	GetProjectAncestors(ctx context.Context, uuid entities.ProjectUUID, relation_type entities.ProjectRelationType) (relations []entities.ProjectRelation, err error)
+	GetProjectDescendants(ctx context.Context, uuid entities.ProjectUUID, relation_type entities.ProjectRelationType) (relations []entities.ProjectRelation, err error)
+	NewProjectStatus(ctx context.Context, uuid entities.ProjectUUID, change entities.ProjectStatusChange, check func(timeline []entities.ProjectStatusChange) error) (status_id int64, err error)
+	GetProjectStatuses(ctx context.Context, uuid entities.ProjectUUID) (timeline []entities.ProjectStatusChange, err error)
+	DeleteLatestProjectStatus(ctx context.Context, uuid entities.ProjectUUID) (err error)
+	ListProjects(ctx context.Context, f filter.Filter, opts entities.ListOptions) (uuids []entities.ProjectUUID, next_cursor string, err error)
 }
 
 type YoutubeRepository interface {
@@ -34,6 +68,90 @@ type YoutubeRepository interface {
 	GetDescription(ctx context.Context, youtube_id entities.YoutubeVideoID) (description string, err error)
 	GetChannelVideos(ctx context.Context, channel_id entities.YoutubeChannelID) (videos []entities.YoutubeVideoID, err error)
 	GetYoutubeFileIDs(ctx context.Context, youtube_id entities.YoutubeVideoID) (file_ids []entities.FileID, err error)
+	GetFileYoutubeID(ctx context.Context, file_id entities.FileID) (youtube_id entities.YoutubeVideoID, err error)
+	GetYtdlpInfo(ctx context.Context, file_id entities.FileID) (info json.RawMessage, err error)
+	GetThumbnail(ctx context.Context, file_id entities.FileID) (thumbnail []byte, err error)
+	AssignYoutubeFile(ctx context.Context, youtube_id entities.YoutubeVideoID, file_id entities.FileID) (err error)
+	ListYoutube(ctx context.Context, f filter.Filter, opts entities.ListOptions) (youtube_ids []entities.YoutubeVideoID, next_cursor string, err error)
+	ListChannels(ctx context.Context, opts entities.ListOptions) (channel_ids []entities.YoutubeChannelID, next_cursor string, err error)
+}
+
+type ProbeRepository interface {
+	NewProbe(ctx context.Context, file_id entities.FileID, probe *entities.MediaProbe, mismatches []entities.ProbeMismatch) (err error)
+	GetProbe(ctx context.Context, file_id entities.FileID) (probe *entities.MediaProbe, err error)
+	GetProbeMismatches(ctx context.Context, file_id entities.FileID) (mismatches []entities.ProbeMismatch, err error)
+	GetAllProbeMismatches(ctx context.Context) (mismatches []entities.ProbeMismatch, err error)
+	GetUnprobedFiles(ctx context.Context) (file_ids []entities.FileID, err error)
+	GetClaimedVideo(ctx context.Context, file_id entities.FileID) (video *entities.Video, err error)
+}
+
+type FingerprintRepository interface {
+	NewFingerprint(ctx context.Context, file_id entities.FileID, fingerprint *entities.Fingerprint) (err error)
+	GetFingerprint(ctx context.Context, file_id entities.FileID) (fingerprint *entities.Fingerprint, err error)
+	GetFingerprintsByDuration(ctx context.Context, min_duration, max_duration float64) (fingerprints map[entities.FileID]entities.Fingerprint, err error)
+	GetUnfingerprintedFiles(ctx context.Context) (file_ids []entities.FileID, err error)
+}
+
+type ArtistRepository interface {
+	NewArtist(ctx context.Context, artist_name string) (err error)
+	DeleteArtist(ctx context.Context, artist_name string) (err error)
+	RenameArtist(ctx context.Context, artist_name, new_name string) (err error)
+	NewArtistAlias(ctx context.Context, artist_name, alias string) (err error)
+	DeleteArtistAlias(ctx context.Context, artist_name, alias string) (err error)
+	AssignArtistChannel(ctx context.Context, artist_name string, channel_id entities.YoutubeChannelID) (err error)
+	UnassignArtistChannel(ctx context.Context, artist_name string, channel_id entities.YoutubeChannelID) (err error)
+	GetArtist(ctx context.Context, artist_name string) (artist *entities.Artist, err error)
+	GetChannelArtists(ctx context.Context, channel_id entities.YoutubeChannelID) (artists []string, err error)
+	GetArtistVideos(ctx context.Context, artist_name string) (videos []entities.YoutubeVideoID, err error)
+	GetArtistParts(ctx context.Context, artist_name string) (parts []entities.ProjectPart, err error)
+}
+
+type MusicRepository interface {
+	NewMusic(ctx context.Context, music *entities.Music) (music_id int32, err error)
+	UpdateMusic(ctx context.Context, music *entities.Music) (err error)
+	DeleteMusic(ctx context.Context, music_id int32) (err error)
+	GetMusic(ctx context.Context, music_id int32) (music *entities.Music, err error)
+	FindMusic(ctx context.Context, artist, title string, min_score float64, max_results int) (matches []entities.MusicMatch, err error)
+	AssignProjectMusic(ctx context.Context, uuid entities.ProjectUUID, music_id int32) (err error)
+	UnassignProjectMusic(ctx context.Context, uuid entities.ProjectUUID, music_id int32) (err error)
+	GetProjectMusic(ctx context.Context, uuid entities.ProjectUUID) (music []entities.Music, err error)
+	GetMusicProjects(ctx context.Context, music_id int32) (uuids []entities.ProjectUUID, err error)
+}
+
+type CharacterRepository interface {
+	NewCharacter(ctx context.Context, character *entities.Character) (character_id int, err error)
+	UpdateCharacter(ctx context.Context, character *entities.Character) (err error)
+	DeleteCharacter(ctx context.Context, character_id int) (err error)
+	GetCharacter(ctx context.Context, character_id int) (character *entities.Character, err error)
+	NewCharacterAlias(ctx context.Context, character_id int, alias entities.CharacterAlias) (err error)
+	DeleteCharacterAlias(ctx context.Context, character_id int, name string) (err error)
+	FindCharacters(ctx context.Context, name string) (characters []entities.Character, err error)
+	GetSeriesCharacters(ctx context.Context, series string) (characters []entities.Character, err error)
+	TagProjectCharacter(ctx context.Context, uuid entities.ProjectUUID, character_id int) (err error)
+	UntagProjectCharacter(ctx context.Context, uuid entities.ProjectUUID, character_id int) (err error)
+	GetProjectCharacters(ctx context.Context, uuid entities.ProjectUUID) (characters []entities.Character, err error)
+	TagPartCharacter(ctx context.Context, part_id int64, character_id int) (err error)
+	UntagPartCharacter(ctx context.Context, part_id int64, character_id int) (err error)
+	GetPartCharacters(ctx context.Context, part_id int64) (characters []entities.Character, err error)
+	GetCharacterProjects(ctx context.Context, character_id int) (uuids []entities.ProjectUUID, err error)
+}
+
+type SearchRepository interface {
+	Search(ctx context.Context, query string, kind entities.SearchKind, max_results int) (results []entities.SearchResult, err error)
+}
+
+type AuditRepository interface {
+	NewAuditEntry(ctx context.Context, entry *entities.AuditEntry) (entry_id int64, err error)
+	GetAuditEntry(ctx context.Context, entry_id int64) (entry *entities.AuditEntry, err error)
+	GetAuditEntries(ctx context.Context, entity entities.AuditEntity, entity_id string) (entries []entities.AuditEntry, err error)
+}
+
+type FsckRepository interface {
+	GetYoutubeWithoutFiles(ctx context.Context) (youtube_ids []entities.YoutubeVideoID, err error)
+	GetFilesWithoutVideo(ctx context.Context) (file_ids []entities.FileID, err error)
+	GetUnassignedDownloads(ctx context.Context) (downloads []entities.YoutubeDownload, err error)
+	GetMisattributedDownloads(ctx context.Context) (downloads []entities.YoutubeDownload, err error)
+	GetProjectsWithoutFiles(ctx context.Context) (uuids []entities.ProjectUUID, err error)
 }
 
 type VideoRepository interface {
@@ -41,7 +159,15 @@ type VideoRepository interface {
 }
 
 type Repository struct {
-	Project ProjectRepository
-	Youtube YoutubeRepository
-	File    FileRepository
+	Project     ProjectRepository
+	Youtube     YoutubeRepository
+	File        FileRepository
+	Probe       ProbeRepository
+	Fingerprint FingerprintRepository
+	Artist      ArtistRepository
+	Music       MusicRepository
+	Character   CharacterRepository
+	Search      SearchRepository
+	Audit       AuditRepository
+	Fsck        FsckRepository
 }
@@ -0,0 +1,10 @@
+//go:build !linux
+
+package tree
+
+import "errors"
+
+// reflink isn't supported outside of linux, leaving it to the next method.
+func reflink(source, destination string) error {
+	return errors.ErrUnsupported
+}
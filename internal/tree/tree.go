@@ -0,0 +1,374 @@
+// Package tree materializes a directory tree of human-readable names linking to stored files, so that the archive can
+// be browsed, or pointed a media player at, without knowing how its storage root is laid out. Entries are reflinked,
+// hardlinked or copied out of the storage root, and a tree is kept up to date by syncing it again, which only touches
+// the entries that changed.
+package tree
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Version is the version of the state file written by Sync.
+const Version = 1
+
+// stateName is the file within a tree recording which entries Sync put there, and what they hold.
+const stateName = ".wc-maps-tree.json"
+
+// tmpSuffix is appended to the name of an entry while it's being linked, before being renamed into place.
+const tmpSuffix = ".wc-tmp"
+
+// maxNameLength is the most runes Name leaves in a name, keeping a path of several names within what filesystems allow.
+const maxNameLength = 100
+
+// Method is how an entry gets its contents out of the storage root. A reflink shares the contents of the stored file
+// until either is changed, and is only supported by some filesystems, such as btrfs and xfs. A hardlink is the stored
+// file itself, so changing it changes the archive. A copy takes up the space of the stored file again.
+type Method int
+
+const (
+	MethodReflink Method = iota
+	MethodHardlink
+	MethodCopy
+)
+
+// DefaultMethods tries the cheapest method first, falling back to copying.
+var DefaultMethods = []Method{MethodReflink, MethodHardlink, MethodCopy}
+
+func (m Method) ToString() string {
+	switch m {
+	case MethodReflink:
+		return "reflink"
+	case MethodHardlink:
+		return "hardlink"
+	case MethodCopy:
+		return "copy"
+	default:
+		return "unknown"
+	}
+}
+
+func NewMethod(s string) (Method, error) {
+	switch s {
+	case "reflink":
+		return MethodReflink, nil
+	case "hardlink":
+		return MethodHardlink, nil
+	case "copy":
+		return MethodCopy, nil
+	default:
+		return 0, fmt.Errorf("unknown link method %q", s)
+	}
+}
+
+// Entry is a single file of a tree. Path is where it goes within the tree, separated by slashes, and Source is the
+// stored file it links to. An entry with Contents, such as metadata made up for the tree, is written out instead of
+// linked to anything. SHA256 identifies the contents of Source, or Contents, telling whether an entry already in the
+// tree is up to date.
+type Entry struct {
+	Path     string
+	Source   string
+	Contents []byte
+	SHA256   string
+}
+
+// SyncResult is what syncing a tree changed. Linked is how many entries were added or replaced by each Method, Written
+// is how many entries with Contents were, and Conflicts are the paths of entries which weren't added, as something
+// Sync didn't put there is in their way.
+type SyncResult struct {
+	Added, Replaced, Removed, Unchanged int
+	Linked                              map[Method]int
+	Written                             int
+	Conflicts                           []string
+}
+
+type state struct {
+	Version int `json:"version"`
+	// Entries maps the path of every entry put in the tree to the SHA256 of its contents.
+	Entries map[string]string `json:"entries"`
+}
+
+// Name makes s usable as a single file or directory name on any common filesystem, replacing path separators and
+// characters Windows refuses with an underscore, and shortening it to a reasonable length. An empty name becomes
+// "untitled".
+func Name(s string) string {
+	s = strings.Map(func(r rune) rune {
+		switch {
+		case unicode.IsSpace(r):
+			return ' '
+		case strings.ContainsRune(`<>:"/\|?*`, r), unicode.IsControl(r):
+			return '_'
+		default:
+			return r
+		}
+	}, s)
+	s = strings.Join(strings.Fields(s), " ")
+
+	if utf8.RuneCountInString(s) > maxNameLength {
+		s = strings.TrimSpace(string([]rune(s)[:maxNameLength]))
+	}
+
+	// Windows drops trailing dots and spaces, which would make two names the same
+	s = strings.TrimRight(s, ". ")
+	if s == "" {
+		return "untitled"
+	}
+
+	return s
+}
+
+// Sync makes the tree at directory hold exactly entries, trying methods in order to link each of them. Entries Sync put
+// there before which are gone from entries are removed, along with any directory left empty, while anything else in
+// directory is left alone. The progress made is kept even if Sync fails partway, so syncing again carries on where it
+// stopped.
+func Sync(ctx context.Context, directory string, entries []Entry, methods []Method) (result SyncResult, err error) {
+	if len(methods) == 0 {
+		return result, errors.New("no link methods")
+	}
+
+	err = checkEntries(entries)
+	if err != nil {
+		return result, err
+	}
+
+	err = os.MkdirAll(directory, 0775)
+	if err != nil {
+		return result, err
+	}
+
+	st, err := readState(directory)
+	if err != nil {
+		return result, err
+	}
+
+	defer func() {
+		err = errors.Join(err, writeState(directory, st))
+	}()
+
+	result.Linked = make(map[Method]int)
+	wanted := make(map[string]bool, len(entries))
+	for _, e := range entries {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+		wanted[e.Path] = true
+
+		destination := filepath.Join(directory, filepath.FromSlash(e.Path))
+		sha256, tracked := st.Entries[e.Path]
+
+		_, err := os.Lstat(destination)
+		switch {
+		case err == nil && tracked && sha256 == e.SHA256:
+			result.Unchanged++
+			continue
+		case err == nil && !tracked:
+			result.Conflicts = append(result.Conflicts, e.Path)
+			continue
+		case err != nil && !errors.Is(err, fs.ErrNotExist):
+			return result, err
+		}
+
+		if e.Contents != nil {
+			err := writeEntry(e.Contents, destination)
+			if err != nil {
+				return result, fmt.Errorf("%s: %w", e.Path, err)
+			}
+			result.Written++
+		} else {
+			method, err := linkEntry(e.Source, destination, methods)
+			if err != nil {
+				return result, fmt.Errorf("%s: %w", e.Path, err)
+			}
+			result.Linked[method]++
+		}
+
+		if tracked {
+			result.Replaced++
+		} else {
+			result.Added++
+		}
+		st.Entries[e.Path] = e.SHA256
+	}
+
+	stale := make([]string, 0)
+	for p := range st.Entries {
+		if !wanted[p] {
+			stale = append(stale, p)
+		}
+	}
+	slices.Sort(stale)
+
+	for _, p := range stale {
+		err := os.Remove(filepath.Join(directory, filepath.FromSlash(p)))
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return result, err
+		}
+
+		delete(st.Entries, p)
+		result.Removed++
+		removeEmptyParents(directory, p)
+	}
+
+	return result, nil
+}
+
+// checkEntries makes sure every entry stays within the tree, and that no two of them would end up as the same file on
+// a case-insensitive filesystem.
+func checkEntries(entries []Entry) error {
+	seen := make(map[string]string, len(entries))
+	for _, e := range entries {
+		if e.Path == "" || path.IsAbs(e.Path) || path.Clean(e.Path) != e.Path || e.Path == ".." || strings.HasPrefix(e.Path, "../") {
+			return fmt.Errorf("invalid entry path %q", e.Path)
+		}
+
+		if e.Path == stateName || strings.HasSuffix(e.Path, tmpSuffix) {
+			return fmt.Errorf("entry path %q is reserved", e.Path)
+		}
+
+		folded := strings.ToLower(e.Path)
+		if other, ok := seen[folded]; ok {
+			return fmt.Errorf("entry paths %q and %q are the same", other, e.Path)
+		}
+		seen[folded] = e.Path
+	}
+
+	return nil
+}
+
+// linkEntry links source to destination with the first of methods that works, replacing whatever is at destination
+// once it has.
+func linkEntry(source, destination string, methods []Method) (Method, error) {
+	err := os.MkdirAll(filepath.Dir(destination), 0775)
+	if err != nil {
+		return 0, err
+	}
+
+	tmp := destination + tmpSuffix
+	err = os.Remove(tmp)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return 0, err
+	}
+
+	var errs []error
+	for _, m := range methods {
+		err := link(source, tmp, m)
+		if err == nil {
+			return m, os.Rename(tmp, destination)
+		}
+
+		os.Remove(tmp)
+		errs = append(errs, fmt.Errorf("%s, %w", m.ToString(), err))
+	}
+
+	return 0, errors.Join(errs...)
+}
+
+// writeEntry writes contents to destination, replacing whatever is there once it has.
+func writeEntry(contents []byte, destination string) error {
+	err := os.MkdirAll(filepath.Dir(destination), 0775)
+	if err != nil {
+		return err
+	}
+
+	tmp := destination + tmpSuffix
+	err = os.WriteFile(tmp, contents, 0664)
+	if err != nil {
+		os.Remove(tmp)
+		return err
+	}
+
+	return os.Rename(tmp, destination)
+}
+
+func link(source, destination string, method Method) error {
+	switch method {
+	case MethodReflink:
+		return reflink(source, destination)
+	case MethodHardlink:
+		return os.Link(source, destination)
+	case MethodCopy:
+		return copyFile(source, destination)
+	default:
+		return fmt.Errorf("unknown link method %d", method)
+	}
+}
+
+func copyFile(source, destination string) (err error) {
+	src, err := os.Open(source)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := os.OpenFile(destination, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0664)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		err = errors.Join(err, dst.Close())
+	}()
+
+	_, err = io.Copy(dst, src)
+	return err
+}
+
+// removeEmptyParents removes every directory p was in which is now empty, up to the root of the tree.
+func removeEmptyParents(directory, p string) {
+	for dir := path.Dir(p); dir != "."; dir = path.Dir(dir) {
+		if os.Remove(filepath.Join(directory, filepath.FromSlash(dir))) != nil {
+			return
+		}
+	}
+}
+
+func readState(directory string) (st state, err error) {
+	contents, err := os.ReadFile(filepath.Join(directory, stateName))
+	if errors.Is(err, fs.ErrNotExist) {
+		return state{Version: Version, Entries: make(map[string]string)}, nil
+	}
+	if err != nil {
+		return state{}, err
+	}
+
+	err = json.Unmarshal(contents, &st)
+	if err != nil {
+		return state{}, fmt.Errorf("%s: %w", stateName, err)
+	}
+
+	if st.Version < 1 || st.Version > Version {
+		return state{}, fmt.Errorf("%s: unsupported version %d", stateName, st.Version)
+	}
+
+	if st.Entries == nil {
+		st.Entries = make(map[string]string)
+	}
+
+	return st, nil
+}
+
+// writeState replaces the state file of the tree at once, so that it's never left half written.
+func writeState(directory string, st state) error {
+	contents, err := json.MarshalIndent(st, "", "\t")
+	if err != nil {
+		return err
+	}
+
+	tmp := filepath.Join(directory, stateName+tmpSuffix)
+	err = os.WriteFile(tmp, contents, 0664)
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, filepath.Join(directory, stateName))
+}
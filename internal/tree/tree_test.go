@@ -0,0 +1,178 @@
+package tree_test
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dtbead/wc-maps-archive/internal/tree"
+)
+
+func TestName(t *testing.T) {
+	tests := []struct {
+		name string
+		s    string
+		want string
+	}{
+		{"plain", "firestar MAP", "firestar MAP"},
+		{"separators", "AMV/PMV: hollyleaf\\ashfur", "AMV_PMV_ hollyleaf_ashfur"},
+		{"windows characters", `what? "ever" <3 | *`, `what_ _ever_ _3 _ _`},
+		{"whitespace", "  two\tparts \n done ", "two parts done"},
+		{"trailing dots", "to be continued...", "to be continued"},
+		{"empty", " . ", "untitled"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tree.Name(tt.s); got != tt.want {
+				t.Errorf("Name() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	long := tree.Name(strings.Repeat("ß", 150))
+	if n := len([]rune(long)); n != 100 {
+		t.Errorf("Name() of a long name is %d runes, want 100", n)
+	}
+}
+
+// writeSources writes a stored file for every contents, returning their paths.
+func writeSources(t *testing.T, contents ...string) []string {
+	t.Helper()
+
+	directory := t.TempDir()
+	paths := make([]string, 0, len(contents))
+	for i, c := range contents {
+		p := filepath.Join(directory, string(rune('a'+i)))
+		if err := os.WriteFile(p, []byte(c), 0664); err != nil {
+			t.Fatal(err)
+		}
+		paths = append(paths, p)
+	}
+
+	return paths
+}
+
+func readEntry(t *testing.T, directory, p string) string {
+	t.Helper()
+
+	contents, err := os.ReadFile(filepath.Join(directory, filepath.FromSlash(p)))
+	if err != nil {
+		t.Fatalf("failed to read entry %s, %v", p, err)
+	}
+
+	return string(contents)
+}
+
+func TestSync(t *testing.T) {
+	sources := writeSources(t, "first", "second", "third")
+	directory := t.TempDir()
+	methods := []tree.Method{tree.MethodHardlink, tree.MethodCopy}
+
+	entries := []tree.Entry{
+		{Path: "Projects/map/firestar MAP [abc]/firestar MAP [y_wo8pyoxyk].mkv", Source: sources[0], SHA256: "1"},
+		{Path: "Projects/map/firestar MAP [abc]/01 - leafstar [dQw4w9WgXcQ].mp4", Source: sources[1], SHA256: "2"},
+	}
+
+	got, err := tree.Sync(context.Background(), directory, entries, methods)
+	if err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+	if got.Added != 2 || got.Linked[tree.MethodHardlink]+got.Linked[tree.MethodCopy] != 2 {
+		t.Errorf("Sync() = %+v, want 2 added", got)
+	}
+	if c := readEntry(t, directory, entries[1].Path); c != "second" {
+		t.Errorf("entry %s = %q, want %q", entries[1].Path, c, "second")
+	}
+
+	// something put in the tree by hand is left alone
+	untracked := filepath.Join(directory, "Projects", "notes.txt")
+	if err := os.WriteFile(untracked, []byte("notes"), 0664); err != nil {
+		t.Fatal(err)
+	}
+
+	entries[1].Source, entries[1].SHA256 = sources[2], "3"
+	entries = append(entries[:1:1], entries[1], tree.Entry{Path: "Projects/notes.txt", Source: sources[0], SHA256: "1"})
+	got, err = tree.Sync(context.Background(), directory, entries, methods)
+	if err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+	if got.Unchanged != 1 || got.Replaced != 1 || len(got.Conflicts) != 1 || got.Conflicts[0] != "Projects/notes.txt" {
+		t.Errorf("Sync() = %+v, want 1 unchanged, 1 replaced and Projects/notes.txt conflicting", got)
+	}
+	if c := readEntry(t, directory, entries[1].Path); c != "third" {
+		t.Errorf("entry %s = %q, want %q", entries[1].Path, c, "third")
+	}
+	if c := readEntry(t, directory, "Projects/notes.txt"); c != "notes" {
+		t.Errorf("untracked file = %q, want %q", c, "notes")
+	}
+
+	got, err = tree.Sync(context.Background(), directory, nil, methods)
+	if err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+	if got.Removed != 2 {
+		t.Errorf("Sync() = %+v, want 2 removed", got)
+	}
+
+	_, err = os.Stat(filepath.Join(directory, "Projects", "map"))
+	if !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("empty directory left behind, error = %v", err)
+	}
+	if _, err := os.Stat(untracked); err != nil {
+		t.Errorf("untracked file removed, %v", err)
+	}
+}
+
+func TestSync_Contents(t *testing.T) {
+	directory := t.TempDir()
+	entries := []tree.Entry{{Path: "Youtube/leafstar/video.nfo", Contents: []byte("<movie/>"), SHA256: "1"}}
+
+	got, err := tree.Sync(context.Background(), directory, entries, tree.DefaultMethods)
+	if err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+	if got.Added != 1 || got.Written != 1 {
+		t.Errorf("Sync() = %+v, want 1 added and written", got)
+	}
+	if c := readEntry(t, directory, entries[0].Path); c != "<movie/>" {
+		t.Errorf("entry %s = %q, want %q", entries[0].Path, c, "<movie/>")
+	}
+
+	entries[0].Contents, entries[0].SHA256 = []byte("<movie></movie>"), "2"
+	got, err = tree.Sync(context.Background(), directory, entries, tree.DefaultMethods)
+	if err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+	if got.Replaced != 1 || got.Written != 1 {
+		t.Errorf("Sync() = %+v, want 1 replaced and written", got)
+	}
+	if c := readEntry(t, directory, entries[0].Path); c != "<movie></movie>" {
+		t.Errorf("entry %s = %q, want %q", entries[0].Path, c, "<movie></movie>")
+	}
+}
+
+func TestSync_InvalidEntries(t *testing.T) {
+	sources := writeSources(t, "first")
+
+	tests := []struct {
+		name    string
+		entries []tree.Entry
+	}{
+		{"escapes the tree", []tree.Entry{{Path: "../outside.mp4", Source: sources[0]}}},
+		{"absolute", []tree.Entry{{Path: "/outside.mp4", Source: sources[0]}}},
+		{"unclean", []tree.Entry{{Path: "Projects//a.mp4", Source: sources[0]}}},
+		{"same path", []tree.Entry{{Path: "Projects/A.mp4", Source: sources[0]}, {Path: "Projects/a.mp4", Source: sources[0]}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tree.Sync(context.Background(), t.TempDir(), tt.entries, tree.DefaultMethods)
+			if err == nil {
+				t.Errorf("Sync() error = nil, want an error")
+			}
+		})
+	}
+}